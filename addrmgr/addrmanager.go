@@ -6,16 +6,18 @@
 package addrmgr
 
 import (
+	"container/list"
+	"context"
 	crand "crypto/rand" // for seeding
 	"encoding/base32"
 	"encoding/binary"
-	"encoding/json"
 	"fmt"
 	"io"
 	"math/rand"
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -29,51 +31,153 @@ import (
 // PeersFilename is the default filename to store serialized peers.
 const PeersFilename = "peers.json"
 
+// peersBackupSuffix is the suffix appended to the peers filename to form the
+// filename of the backup of the previously saved peers.
+const peersBackupSuffix = ".bak"
+
 // AddrManager provides a concurrency safe address manager for caching potential
 // peers on the Decred network.
 type AddrManager struct {
-	mtx            sync.Mutex                               // main mutex used to sync methods
-	peersFile      string                                   // path of file to store peers in
-	lookupFunc     func(string) ([]net.IP, error)           // for DNS lookups
-	rand           *rand.Rand                               // internal PRNG
-	key            [32]byte                                 // cryptographically secure random bytes
-	addrIndex      map[string]*KnownAddress                 // address key to ka for all addresses
-	addrNew        [newBucketCount]map[string]*KnownAddress // storage for new addresses
-	addrTried      [triedBucketCount][]*KnownAddress        // storage for tried addresses
-	addrChanged    bool                                     // true if address state needs saving
-	started        int32                                    // is 1 if started
-	shutdown       int32                                    // is 1 if shutdown is done or in progress
-	wg             sync.WaitGroup                           // wait group used by main handler
-	quit           chan struct{}                            // channel to notify main handler of shutdown
-	nTried         int                                      // number of tried addresses
-	nNew           int                                      // number of new addresses (i.e., not tried)
-	lamtx          sync.Mutex                               // local address mutex
-	localAddresses map[string]*localAddress                 // address key to la for all local addresses
+	mtx            sync.Mutex                     // main mutex used to sync methods
+	cfg            Config                         // tunable parameters
+	peersFile      string                         // path of file to store peers in
+	lookupFunc     func(string) ([]net.IP, error) // for DNS lookups
+	rand           *rand.Rand                     // internal PRNG
+	key            [32]byte                       // cryptographically secure random bytes
+	addrIndex      map[string]*KnownAddress       // address key to ka for all addresses
+	addrNew        []map[string]*KnownAddress     // storage for new addresses
+	addrTried      [][]*KnownAddress              // storage for tried addresses
+	addrChanged    bool                           // true if address state needs saving
+	started        int32                          // is 1 if started
+	shutdown       int32                          // is 1 if shutdown is done or in progress
+	wg             sync.WaitGroup                 // wait group used by main handler
+	quit           chan struct{}                  // channel to notify main handler of shutdown
+	nTried         int                            // number of tried addresses
+	nNew           int                            // number of new addresses (i.e., not tried)
+	lamtx          sync.Mutex                     // local address mutex
+	localAddresses map[string]*localAddress       // address key to la for all local addresses
+	dialPolicy     NetworkReachabilityPolicy      // how remote networks are reached, protected by lamtx
+	asmap          *ASMap                         // optional map of IP addresses to AS numbers
+	anchorsFile    string                         // path of file to store anchors in
+	anchors        []*wire.NetAddress             // anchors loaded from the anchors file
+	newAnchors     []*wire.NetAddress             // anchors to save to the anchors file
+	peersFormat    PeersFileFormat                // format to save peers file in
+	oldPeersFile   string                         // path of peers file in another format to remove once saved
+	subscribers    []EventCallback                // callbacks to notify of address lifecycle events
+	events         []*Event                       // address lifecycle events yet to be sent
+	metrics        addrMetrics                    // counters included in metrics
+	removeBackup   bool                           // true if the peers file backup has discarded addresses
+	sourceLimiter  *sourceLimiter                 // limits the rate addresses are accepted per source
+	relay          *relayTracker                  // addresses known to each peer
+	seedQuarantine map[string]*seedAddress        // seeded addresses yet to be confirmed
+	outboundGroups map[string]int                 // group key to number of outbound connections
+	bansFile       string                         // path of file to store bans in
+	banned         map[string]time.Time           // banned host to time the ban expires
+	bansChanged    bool                           // true if bans need saving
+	lru            *list.List                     // new addresses by use when limited
+	blocklists     []*Blocklist                   // addresses that are never stored
+
+	// triedCollisions houses the keys of the new addresses that could not
+	// be moved to their full tried bucket when testing before evicting,
+	// along with the time each collision was recorded.
+	triedCollisions map[string]time.Time
 }
 
 type serializedKnownAddress struct {
-	Addr        string
-	Src         string
-	Attempts    int
-	TimeStamp   int64
-	LastAttempt int64
-	LastSuccess int64
+	Addr            string
+	Src             string
+	Attempts        int
+	TimeStamp       int64
+	LastAttempt     int64
+	LastSuccess     int64
+	PoorService     int
+	UserAgent       string
+	ProtocolVersion uint32
+	TotalAttempts   int
+	TotalSuccesses  int
+	Latency         int64 // microseconds
+	Uptime          int64 // seconds
+	LastBlock       int64
+	BlocksBehind    int64
 	// no refcount or tried, that is available from context.
 }
 
 type serializedAddrManager struct {
 	Version      int
 	Key          [32]byte
+	ASMap        string // checksum of the asmap used to bucket addresses
 	Addresses    []*serializedKnownAddress
-	NewBuckets   [newBucketCount][]string // string is NetAddressKey
-	TriedBuckets [triedBucketCount][]string
+	NewBuckets   [][]string // string is NetAddressKey
+	TriedBuckets [][]string
 }
 
 type localAddress struct {
 	na    *wire.NetAddress
 	score AddressPriority
+
+	// The following fields are only set for addresses with a lease, such
+	// as those mapped by NAT traversal.  The address is no longer used
+	// once the lease expires.
+	ttl     time.Duration
+	expires time.Time
+	refresh LocalAddressRefreshFunc
+}
+
+// expired returns whether or not the lease of the local address has expired as
+// of the provided time.  Addresses without a lease never expire.
+func (la *localAddress) expired(now time.Time) bool {
+	return !la.expires.IsZero() && !now.Before(la.expires)
+}
+
+// NetworkReachabilityPolicy describes how the local node reaches the networks
+// of remote addresses, which determines the local addresses that are safe to
+// advertise to peers.
+type NetworkReachabilityPolicy struct {
+	// ClearnetProxied indicates connections to IPv4 and IPv6 addresses are
+	// made through a proxy, such as Tor.  Since advertising the clearnet
+	// addresses of the local node would reveal its network location, the
+	// only clearnet local addresses that are advertised are those that
+	// were explicitly provided with ManualPrio, and no clearnet addresses
+	// are learned from peers or discovered via STUN.
+	ClearnetProxied bool
+}
+
+// isClearnet returns whether or not the given address is an IPv4 or IPv6
+// address on the public internet as opposed to an overlay network, such as Tor
+// or cjdns.
+func isClearnet(na *wire.NetAddress) bool {
+	return !isOnionCatTor(na) && !isCJDNS(na)
+}
+
+// SetDialPolicy sets the policy that describes how the local node reaches the
+// networks of remote addresses, such as through a proxy.  It is typically
+// configured from the proxy settings before the local addresses are added.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) SetDialPolicy(policy NetworkReachabilityPolicy) {
+	a.lamtx.Lock()
+	a.dialPolicy = policy
+	a.lamtx.Unlock()
+}
+
+// advertisable returns whether or not the given local address may be
+// advertised to peers according to the dial policy.
+//
+// This function MUST be called with the local address lock held (for reads).
+func (a *AddrManager) advertisable(la *localAddress) bool {
+	if a.dialPolicy.ClearnetProxied && isClearnet(la.na) {
+		return la.score >= ManualPrio
+	}
+	return true
 }
 
+// LocalAddressRefreshFunc is the signature of a callback that renews the lease
+// of a local address registered with AddLocalAddressWithExpiry, such as by
+// renewing the port mapping of a NAT gateway.  It returns the time to live of
+// the renewed lease.  The address expires when an error is returned and the
+// current lease lapses.
+type LocalAddressRefreshFunc func(na *wire.NetAddress) (time.Duration, error)
+
 // LocalAddr represents network address information for a local address.
 type LocalAddr struct {
 	Address string
@@ -98,6 +202,9 @@ const (
 	// HTTPPrio signifies the address was obtained from an external HTTP service.
 	HTTPPrio
 
+	// StunPrio signifies the address was obtained from a STUN server.
+	StunPrio
+
 	// ManualPrio signifies the address was provided by --externalip.
 	ManualPrio
 )
@@ -107,24 +214,28 @@ const (
 	// address manager will claim to need more addresses.
 	needAddressThreshold = 1000
 
-	// dumpAddressInterval is the interval used to dump the address
-	// cache to disk for future use.
+	// dumpAddressInterval is the default interval used to dump the
+	// address cache to disk for future use.
 	dumpAddressInterval = time.Minute * 10
 
-	// triedBucketSize is the maximum number of addresses in each
+	// localAddressCheckInterval is the interval at which the leases of
+	// local addresses are refreshed or expired.
+	localAddressCheckInterval = time.Minute
+
+	// triedBucketSize is the default maximum number of addresses in each
 	// tried address bucket.
 	triedBucketSize = 256
 
-	// triedBucketCount is the number of buckets we split tried
+	// triedBucketCount is the default number of buckets we split tried
 	// addresses over.
 	triedBucketCount = 64
 
-	// newBucketSize is the maximum number of addresses in each new address
-	// bucket.
+	// newBucketSize is the default maximum number of addresses in each new
+	// address bucket.
 	newBucketSize = 64
 
-	// newBucketCount is the number of buckets that we spread new addresses
-	// over.
+	// newBucketCount is the default number of buckets that we spread new
+	// addresses over.
 	newBucketCount = 1024
 
 	// triedBucketsPerGroup is the number of tried buckets over which an
@@ -139,20 +250,26 @@ const (
 	// address may end up in.
 	newBucketsPerAddress = 8
 
-	// numMissingDays is the number of days before which we assume an
-	// address has vanished if we have not seen it announced in that long.
+	// numMissingDays is the default number of days before which we assume
+	// an address has vanished if we have not seen it announced in that
+	// long.
 	numMissingDays = 30
 
-	// numRetries is the number of tried without a single success before
-	// we assume an address is bad.
+	// numRetries is the default number of tried without a single success
+	// before we assume an address is bad.
 	numRetries = 3
 
-	// maxFailures is the maximum number of failures we will accept without
-	// a success before considering an address bad.
+	// maxPoorService is the maximum number of times poor service is
+	// tracked for an address.  It limits how much an address is
+	// deprioritised for poor service.
+	maxPoorService = 8
+
+	// maxFailures is the default maximum number of failures we will accept
+	// without a success before considering an address bad.
 	maxFailures = 5
 
-	// minBadDays is the number of days since the last success before we
-	// will consider evicting an address.
+	// minBadDays is the default number of days since the last success
+	// before we will consider evicting an address.
 	minBadDays = 7
 
 	// getAddrMax is the most addresses that we will send in response
@@ -164,8 +281,36 @@ const (
 	// will share with a call to AddressCache.
 	getAddrPercent = 23
 
+	// getAddressesPicksPerAddr is the number of addresses GetAddresses will
+	// pick for each requested address while looking for addresses from
+	// distinct groups.
+	getAddressesPicksPerAddr = 100
+
+	// feelerRetryInterval is the minimum interval between feeler
+	// connection attempts to the same address.
+	feelerRetryInterval = 10 * time.Minute
+
+	// uptimeBonusPeriod is the cumulative uptime of the peer at an address
+	// at which it receives the maximum preference when selecting addresses.
+	uptimeBonusPeriod = 24 * time.Hour
+
+	// latencyAverageWeight is the weight of the previous average connection
+	// latency of an address when a new latency is recorded.
+	latencyAverageWeight = 7
+
+	// farBehindBlocks is the number of blocks a peer must have been behind
+	// the local best chain when it was last connected to in order to be
+	// deprioritised when selecting addresses.
+	farBehindBlocks = 288
+
 	// serialisationVersion is the current version of the on-disk format.
-	serialisationVersion = 1
+	// Version 2 adds the connection statistics of addresses and version 3
+	// adds their last advertised block height.
+	serialisationVersion = 3
+
+	// minSerialisationVersion is the oldest version of the on-disk format
+	// that can still be loaded.
+	minSerialisationVersion = 1
 )
 
 // updateAddress is a helper function to either update an address already known
@@ -173,12 +318,31 @@ const (
 func (a *AddrManager) updateAddress(netAddr, srcAddr *wire.NetAddress) {
 	// Filter out non-routable addresses. Note that non-routable
 	// also includes invalid and local addresses.
-	if !IsRoutable(netAddr) {
+	if !a.IsRoutable(netAddr) {
+		return
+	}
+
+	// Filter out addresses on other networks when only onion addresses
+	// are accepted.
+	if a.refuseNetwork(netAddr) {
+		return
+	}
+
+	// Filter out addresses of banned hosts and blocklisted addresses.
+	if a.isBanned(netAddr) || a.isBlocked(netAddr) {
+		return
+	}
+
+	// Limit the rate addresses are accepted from the same source group so a
+	// single source cannot dominate the new buckets.
+	srcGroup := a.GroupKey(srcAddr)
+	if !a.sourceLimiter.allow(srcGroup, a.cfg.now()) {
+		a.metrics.rateLimited++
 		return
 	}
 
 	addr := NetAddressKey(netAddr)
-	ka := a.find(netAddr)
+	ka := a.addrIndex[addr]
 	if ka != nil {
 		// TODO(oga) only update addresses periodically.
 		// Update the last seen time and services.
@@ -196,12 +360,14 @@ func (a *AddrManager) updateAddress(netAddr, srcAddr *wire.NetAddress) {
 			ka.mtx.Lock()
 			ka.na = &naCopy
 			ka.mtx.Unlock()
+			a.notify(EventUpdated, &naCopy)
 		}
 
 		// If already in tried, we have nothing to do here.
 		if ka.tried {
 			return
 		}
+		a.lruTouch(ka)
 
 		// Already at our max?
 		if ka.refs == newBucketsPerAddress {
@@ -219,14 +385,18 @@ func (a *AddrManager) updateAddress(netAddr, srcAddr *wire.NetAddress) {
 		// updated elsewhere in the addrmanager code and would otherwise
 		// change the actual netaddress on the peer.
 		netAddrCopy := *netAddr
-		ka = &KnownAddress{na: &netAddrCopy, srcAddr: srcAddr}
+		ka = &KnownAddress{na: &netAddrCopy, srcAddr: srcAddr,
+			cachedKey: addr, cachedSrcGroup: srcGroup}
+		delete(a.seedQuarantine, addr)
 		a.addrIndex[addr] = ka
 		a.nNew++
 		a.addrChanged = true
+		a.notify(EventAdded, ka.na)
+		a.lruTouch(ka)
 		// XXX time penalty?
 	}
 
-	bucket := a.getNewBucket(netAddr, srcAddr)
+	bucket := a.newBucket(a.groupOf(ka), srcGroup)
 
 	// Already exists?
 	if _, ok := a.addrNew[bucket][addr]; ok {
@@ -234,7 +404,7 @@ func (a *AddrManager) updateAddress(netAddr, srcAddr *wire.NetAddress) {
 	}
 
 	// Enforce max addresses.
-	if len(a.addrNew[bucket]) > newBucketSize {
+	if len(a.addrNew[bucket]) > a.cfg.NewBucketSize {
 		log.Tracef("new bucket is full, expiring old")
 		a.expireNew(bucket)
 	}
@@ -244,6 +414,9 @@ func (a *AddrManager) updateAddress(netAddr, srcAddr *wire.NetAddress) {
 	a.addrNew[bucket][addr] = ka
 	a.addrChanged = true
 
+	// Enforce the maximum total number of addresses.
+	a.evictLRU()
+
 	log.Tracef("Added new address %s for a total of %d addresses", addr,
 		a.nTried+a.nNew)
 }
@@ -258,7 +431,7 @@ func (a *AddrManager) expireNew(bucket int) {
 	// use that information instead.
 	var oldest *KnownAddress
 	for k, v := range a.addrNew[bucket] {
-		if v.isBad() {
+		if v.isBad(&a.cfg) {
 			log.Tracef("expiring bad address %v", k)
 			delete(a.addrNew[bucket], k)
 			a.addrChanged = true
@@ -266,6 +439,8 @@ func (a *AddrManager) expireNew(bucket int) {
 			if v.refs == 0 {
 				a.nNew--
 				delete(a.addrIndex, k)
+				a.lruRemove(v)
+				a.notify(EventExpired, v.na)
 			}
 			continue
 		}
@@ -277,7 +452,7 @@ func (a *AddrManager) expireNew(bucket int) {
 	}
 
 	if oldest != nil {
-		key := NetAddressKey(oldest.na)
+		key := oldest.key()
 		log.Tracef("expiring oldest address %v", key)
 
 		delete(a.addrNew[bucket], key)
@@ -286,6 +461,8 @@ func (a *AddrManager) expireNew(bucket int) {
 		if oldest.refs == 0 {
 			a.nNew--
 			delete(a.addrIndex, key)
+			a.lruRemove(oldest)
+			a.notify(EventEvicted, oldest.na)
 		}
 	}
 }
@@ -307,14 +484,20 @@ func (a *AddrManager) pickTried(bucket int) int {
 }
 
 func (a *AddrManager) getNewBucket(netAddr, srcAddr *wire.NetAddress) int {
+	return a.newBucket(a.GroupKey(netAddr), a.GroupKey(srcAddr))
+}
+
+// newBucket returns the new bucket for an address in the provided group that
+// was learned from a source in the provided group.
+func (a *AddrManager) newBucket(group, srcGroup string) int {
 	// bitcoind:
 	// doublesha256(key + sourcegroup + int64(doublesha256(key + group
 	// + sourcegroup))%bucket_per_source_group) % num_new_buckets
 
 	data1 := []byte{}
 	data1 = append(data1, a.key[:]...)
-	data1 = append(data1, []byte(GroupKey(netAddr))...)
-	data1 = append(data1, []byte(GroupKey(srcAddr))...)
+	data1 = append(data1, []byte(group)...)
+	data1 = append(data1, []byte(srcGroup)...)
 	hash1 := chainhash.HashB(data1)
 	hash64 := binary.LittleEndian.Uint64(hash1)
 	hash64 %= newBucketsPerGroup
@@ -322,20 +505,26 @@ func (a *AddrManager) getNewBucket(netAddr, srcAddr *wire.NetAddress) int {
 	binary.LittleEndian.PutUint64(hashbuf[:], hash64)
 	data2 := []byte{}
 	data2 = append(data2, a.key[:]...)
-	data2 = append(data2, GroupKey(srcAddr)...)
+	data2 = append(data2, srcGroup...)
 	data2 = append(data2, hashbuf[:]...)
 
 	hash2 := chainhash.HashB(data2)
-	return int(binary.LittleEndian.Uint64(hash2) % newBucketCount)
+	return int(binary.LittleEndian.Uint64(hash2) % uint64(len(a.addrNew)))
 }
 
 func (a *AddrManager) getTriedBucket(netAddr *wire.NetAddress) int {
+	return a.triedBucket(NetAddressKey(netAddr), a.GroupKey(netAddr))
+}
+
+// triedBucket returns the tried bucket for the address with the provided key
+// in the provided group.
+func (a *AddrManager) triedBucket(key, group string) int {
 	// bitcoind hashes this as:
 	// doublesha256(key + group + truncate_to_64bits(doublesha256(key))
 	// % buckets_per_group) % num_buckets
 	data1 := []byte{}
 	data1 = append(data1, a.key[:]...)
-	data1 = append(data1, []byte(NetAddressKey(netAddr))...)
+	data1 = append(data1, []byte(key)...)
 	hash1 := chainhash.HashB(data1)
 	hash64 := binary.LittleEndian.Uint64(hash1)
 	hash64 %= triedBucketsPerGroup
@@ -343,29 +532,64 @@ func (a *AddrManager) getTriedBucket(netAddr *wire.NetAddress) int {
 	binary.LittleEndian.PutUint64(hashbuf[:], hash64)
 	data2 := []byte{}
 	data2 = append(data2, a.key[:]...)
-	data2 = append(data2, GroupKey(netAddr)...)
+	data2 = append(data2, group...)
 	data2 = append(data2, hashbuf[:]...)
 
 	hash2 := chainhash.HashB(data2)
-	return int(binary.LittleEndian.Uint64(hash2) % triedBucketCount)
+	return int(binary.LittleEndian.Uint64(hash2) % uint64(len(a.addrTried)))
+}
+
+// knownNewBucket returns the new bucket for the provided known address using
+// its cached groups.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) knownNewBucket(ka *KnownAddress) int {
+	return a.newBucket(a.groupOf(ka), a.srcGroupOf(ka))
+}
+
+// knownTriedBucket returns the tried bucket for the provided known address
+// using its cached key and group.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) knownTriedBucket(ka *KnownAddress) int {
+	return a.triedBucket(ka.key(), a.groupOf(ka))
 }
 
 // addressHandler is the main handler for the address manager.  It must be run
-// as a goroutine.
-func (a *AddrManager) addressHandler() {
-	dumpAddressTicker := time.NewTicker(dumpAddressInterval)
+// as a goroutine.  It saves the known addresses and anchors and exits when
+// either the provided context is canceled or the address manager is stopped.
+func (a *AddrManager) addressHandler(ctx context.Context) {
+	dumpAddressTicker := time.NewTicker(a.cfg.DumpAddressInterval)
 	defer dumpAddressTicker.Stop()
+	localAddressTicker := time.NewTicker(localAddressCheckInterval)
+	defer localAddressTicker.Stop()
 out:
 	for {
 		select {
+		case <-localAddressTicker.C:
+			a.refreshLocalAddresses()
+
 		case <-dumpAddressTicker.C:
+			if a.cfg.MemoryOnly {
+				continue
+			}
 			a.savePeers()
+			a.saveBans()
+
+		case <-ctx.Done():
+			break out
 
 		case <-a.quit:
 			break out
 		}
 	}
-	a.savePeers()
+	if !a.cfg.MemoryOnly {
+		a.savePeers()
+		a.saveBans()
+		a.saveAnchors()
+	}
 	a.wg.Done()
 	log.Trace("Address handler done")
 }
@@ -380,11 +604,15 @@ func (a *AddrManager) savePeers() {
 		// Nothing changed since last savePeers call.
 		return
 	}
+	start := time.Now()
 
-	// First we make a serialisable data structure so we can encode it to JSON.
+	// First we make a serialisable data structure so we can encode it.
 	sam := new(serializedAddrManager)
 	sam.Version = serialisationVersion
 	copy(sam.Key[:], a.key[:])
+	sam.NewBuckets = make([][]string, len(a.addrNew))
+	sam.TriedBuckets = make([][]string, len(a.addrTried))
+	sam.ASMap = a.asmapChecksum()
 
 	sam.Addresses = make([]*serializedKnownAddress, len(a.addrIndex))
 	i := 0
@@ -396,6 +624,15 @@ func (a *AddrManager) savePeers() {
 		ska.Attempts = v.attempts
 		ska.LastAttempt = v.lastattempt.Unix()
 		ska.LastSuccess = v.lastsuccess.Unix()
+		ska.PoorService = v.poorService
+		ska.UserAgent = v.userAgent
+		ska.ProtocolVersion = v.protocolVersion
+		ska.TotalAttempts = v.totalAttempts
+		ska.TotalSuccesses = v.totalSuccesses
+		ska.Latency = int64(v.latency / time.Microsecond)
+		ska.Uptime = int64(v.uptime / time.Second)
+		ska.LastBlock = v.lastBlock
+		ska.BlocksBehind = v.blocksBehind
 		// Tried and refs are implicit in the rest of the structure
 		// and will be worked out from context on unserialisation.
 		sam.Addresses[i] = ska
@@ -413,42 +650,91 @@ func (a *AddrManager) savePeers() {
 		sam.TriedBuckets[i] = make([]string, len(a.addrTried[i]))
 		j := 0
 		for _, ka := range a.addrTried[i] {
-			sam.TriedBuckets[i][j] = NetAddressKey(ka.na)
+			sam.TriedBuckets[i][j] = ka.key()
 			j++
 		}
 	}
 
-	// Write temporary peers file and then move it into place.
+	// Write temporary peers file and then move it into place so the peers
+	// file is never left partially written.
 	tmpfile := a.peersFile + ".new"
 	w, err := os.Create(tmpfile)
 	if err != nil {
 		log.Errorf("Error opening file %s: %v", tmpfile, err)
 		return
 	}
-	enc := json.NewEncoder(w)
-	if err := enc.Encode(&sam); err != nil {
+	if err := encodePeers(w, sam, a.peersFormat); err != nil {
+		w.Close()
 		log.Errorf("Failed to encode file %s: %v", tmpfile, err)
 		return
 	}
+	if err := w.Sync(); err != nil {
+		w.Close()
+		log.Errorf("Error syncing file %s: %v", tmpfile, err)
+		return
+	}
 	if err := w.Close(); err != nil {
 		log.Errorf("Error closing file %s: %v", tmpfile, err)
 		return
 	}
+
+	// Rotate the previously saved peers file to the backup so the known
+	// addresses are not lost should the new peers file fail to load.
+	backupFile := a.peersFile + peersBackupSuffix
+	err = os.Rename(a.peersFile, backupFile)
+	if err != nil && !os.IsNotExist(err) {
+		log.Warnf("Error backing up file %s: %v", a.peersFile, err)
+	}
 	if err := os.Rename(tmpfile, a.peersFile); err != nil {
 		log.Errorf("Error writing file %s: %v", a.peersFile, err)
 		return
 	}
 	a.addrChanged = false
+	a.metrics.recordSave(time.Since(start))
+
+	// Remove the peers file the addresses were loaded from when it was
+	// saved in another format now that they are saved in the configured
+	// one.
+	if a.oldPeersFile != "" {
+		for _, path := range []string{a.oldPeersFile,
+			a.oldPeersFile + peersBackupSuffix} {
+
+			err := os.Remove(path)
+			if err != nil && !os.IsNotExist(err) {
+				log.Warnf("Failed to remove file %s: %v", path, err)
+			}
+		}
+		a.oldPeersFile = ""
+	}
+
+	// Remove the backup when it is the peers file the discarded addresses
+	// were loaded from so they are no longer persisted.
+	if a.removeBackup {
+		err := os.Remove(backupFile)
+		if err != nil && !os.IsNotExist(err) {
+			log.Warnf("Failed to remove file %s: %v", backupFile, err)
+		}
+		a.removeBackup = false
+	}
 }
 
-// loadPeers loads the known address from the saved file.  If empty, missing, or
-// malformed file, just don't load anything and start fresh
+// loadPeers loads the known address from the saved file.  When the file is
+// malformed, or missing due to an interrupted save, the addresses are loaded
+// from the backup of the previously saved file instead.  If both are empty,
+// missing, or malformed, just don't load anything and start fresh.
 func (a *AddrManager) loadPeers() {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
-	err := a.deserializePeers(a.peersFile)
-	if err != nil {
+	backupFile := a.peersFile + peersBackupSuffix
+	_, err := os.Stat(a.peersFile)
+	if !os.IsNotExist(err) {
+		err = a.deserializePeers(a.peersFile)
+		if err == nil {
+			log.Infof("Loaded %d addresses from file '%s'",
+				a.numAddresses(), a.peersFile)
+			return
+		}
 		log.Errorf("Failed to parse file %s: %v", a.peersFile, err)
 		// if it is invalid we nuke the old one unconditionally.
 		err = os.Remove(a.peersFile)
@@ -457,9 +743,53 @@ func (a *AddrManager) loadPeers() {
 				a.peersFile, err)
 		}
 		a.reset()
+	}
+
+	// Fall back to the backup of the previously saved peers file.
+	if _, err := os.Stat(backupFile); os.IsNotExist(err) {
+		a.loadOtherFormatPeers()
+		return
+	}
+	err = a.deserializePeers(backupFile)
+	if err != nil {
+		log.Errorf("Failed to parse file %s: %v", backupFile, err)
+		a.reset()
+		return
+	}
+	log.Infof("Loaded %d addresses from backup file '%s'", a.numAddresses(),
+		backupFile)
+
+	// Ensure the recovered addresses are saved to a new peers file.
+	a.addrChanged = true
+}
+
+// loadOtherFormatPeers loads the known addresses from a peers file saved in a
+// format other than the configured one, such as when the format was changed
+// since the addresses were last saved.  The file is removed once the addresses
+// are saved in the configured format.
+//
+// This function MUST be called with the address manager lock held.
+func (a *AddrManager) loadOtherFormatPeers() {
+	dataDir := filepath.Dir(a.peersFile)
+	for _, format := range []PeersFileFormat{PeersFileJSON, PeersFileBinary} {
+		if format == a.peersFormat {
+			continue
+		}
+		path := filepath.Join(dataDir, format.filename())
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		if err := a.deserializePeers(path); err != nil {
+			log.Errorf("Failed to parse file %s: %v", path, err)
+			a.reset()
+			continue
+		}
+		log.Infof("Loaded %d addresses from file '%s'", a.numAddresses(),
+			path)
+		a.oldPeersFile = path
+		a.addrChanged = true
 		return
 	}
-	log.Infof("Loaded %d addresses from file '%s'", a.numAddresses(), a.peersFile)
 }
 
 func (a *AddrManager) deserializePeers(filePath string) error {
@@ -473,14 +803,14 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 	}
 	defer r.Close()
 
-	var sam serializedAddrManager
-	dec := json.NewDecoder(r)
-	err = dec.Decode(&sam)
+	sam, err := decodePeers(r)
 	if err != nil {
 		return fmt.Errorf("error reading %s: %v", filePath, err)
 	}
 
-	if sam.Version != serialisationVersion {
+	if sam.Version < minSerialisationVersion ||
+		sam.Version > serialisationVersion {
+
 		return fmt.Errorf("unknown version %v in serialized "+
 			"addrmanager", sam.Version)
 	}
@@ -501,9 +831,22 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 		ka.attempts = v.Attempts
 		ka.lastattempt = time.Unix(v.LastAttempt, 0)
 		ka.lastsuccess = time.Unix(v.LastSuccess, 0)
-		a.addrIndex[NetAddressKey(ka.na)] = ka
-	}
-
+		ka.poorService = v.PoorService
+		ka.userAgent = v.UserAgent
+		ka.protocolVersion = v.ProtocolVersion
+		ka.totalAttempts = v.TotalAttempts
+		ka.totalSuccesses = v.TotalSuccesses
+		ka.latency = time.Duration(v.Latency) * time.Microsecond
+		ka.uptime = time.Duration(v.Uptime) * time.Second
+		ka.lastBlock = v.LastBlock
+		ka.blocksBehind = v.BlocksBehind
+		a.addrIndex[ka.key()] = ka
+	}
+
+	// The addresses are only placed in the buckets they were saved in when
+	// the bucket layout has not changed since they were saved.  Otherwise,
+	// they are redistributed below.
+	sameLayout := a.bucketsFit(sam.NewBuckets, sam.TriedBuckets)
 	for i := range sam.NewBuckets {
 		for _, val := range sam.NewBuckets[i] {
 			ka, ok := a.addrIndex[val]
@@ -516,7 +859,9 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 				a.nNew++
 			}
 			ka.refs++
-			a.addrNew[i][val] = ka
+			if sameLayout {
+				a.addrNew[i][val] = ka
+			}
 		}
 	}
 	for i := range sam.TriedBuckets {
@@ -529,7 +874,9 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 
 			ka.tried = true
 			a.nTried++
-			a.addrTried[i] = append(a.addrTried[i], ka)
+			if sameLayout {
+				a.addrTried[i] = append(a.addrTried[i], ka)
+			}
 		}
 	}
 
@@ -546,9 +893,99 @@ func (a *AddrManager) deserializePeers(filePath string) error {
 		}
 	}
 
+	// Discard addresses on other networks when only onion addresses are
+	// accepted.  The remaining addresses are redistributed below to remove
+	// the discarded ones from the buckets.
+	var discarded int
+	for k, v := range a.addrIndex {
+		if a.refuseNetwork(v.na) {
+			delete(a.addrIndex, k)
+			discarded++
+		}
+	}
+
+	// The buckets addresses belong in depend on the asmap, so redistribute
+	// them when it has changed since they were saved.
+	switch {
+	case discarded > 0:
+		log.Infof("Discarded %d non-onion addresses", discarded)
+		a.removeBackup = true
+		a.rebucket()
+	case !sameLayout:
+		log.Infof("Redistributing addresses due to a change of bucket " +
+			"layout")
+		a.rebucket()
+	case sam.ASMap != a.asmapChecksum():
+		log.Infof("Redistributing addresses due to a change of asmap")
+		a.rebucket()
+	}
+
 	return nil
 }
 
+// rebucket redistributes all known addresses among the new and tried buckets
+// according to the current group keys.  Tried addresses that no longer fit in
+// their tried bucket are moved to the new buckets, and new addresses that no
+// longer fit in their new bucket are forgotten.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) rebucket() {
+	for i := range a.addrNew {
+		a.addrNew[i] = make(map[string]*KnownAddress)
+	}
+	for i := range a.addrTried {
+		a.addrTried[i] = nil
+	}
+	a.nNew = 0
+	a.nTried = 0
+
+	for key, ka := range a.addrIndex {
+		ka.refs = 0
+		if ka.tried {
+			bucket := a.knownTriedBucket(ka)
+			if len(a.addrTried[bucket]) < a.cfg.TriedBucketSize {
+				a.addrTried[bucket] = append(a.addrTried[bucket], ka)
+				a.nTried++
+				continue
+			}
+			ka.tried = false
+		}
+
+		bucket := a.knownNewBucket(ka)
+		if len(a.addrNew[bucket]) >= a.cfg.NewBucketSize {
+			delete(a.addrIndex, key)
+			continue
+		}
+		a.addrNew[bucket][key] = ka
+		ka.refs = 1
+		a.nNew++
+	}
+	a.addrChanged = true
+}
+
+// bucketsFit returns whether or not the provided serialized new and tried
+// buckets fit the bucket layout of the address manager such that the
+// addresses can be placed in the same buckets they were saved in.
+func (a *AddrManager) bucketsFit(newBuckets, triedBuckets [][]string) bool {
+	if len(newBuckets) != len(a.addrNew) ||
+		len(triedBuckets) != len(a.addrTried) {
+
+		return false
+	}
+	for _, bucket := range newBuckets {
+		if len(bucket) > a.cfg.NewBucketSize {
+			return false
+		}
+	}
+	for _, bucket := range triedBuckets {
+		if len(bucket) > a.cfg.TriedBucketSize {
+			return false
+		}
+	}
+	return true
+}
+
 // DeserializeNetAddress converts a given address string to a *wire.NetAddress
 func (a *AddrManager) DeserializeNetAddress(addr string) (*wire.NetAddress, error) {
 	host, portStr, err := net.SplitHostPort(addr)
@@ -564,8 +1001,17 @@ func (a *AddrManager) DeserializeNetAddress(addr string) (*wire.NetAddress, erro
 }
 
 // Start begins the core address handler which manages a pool of known
-// addresses, timeouts, and interval based writes.
+// addresses, timeouts, and interval based writes.  It runs until Stop is
+// called.
 func (a *AddrManager) Start() {
+	a.StartContext(context.Background())
+}
+
+// StartContext begins the core address handler which manages a pool of known
+// addresses, timeouts, and interval based writes.  It runs until either the
+// provided context is canceled or Stop is called.  Either way, the known
+// addresses and anchors are saved when it exits.
+func (a *AddrManager) StartContext(ctx context.Context) {
 	// Already started?
 	if atomic.AddInt32(&a.started, 1) != 1 {
 		return
@@ -573,12 +1019,52 @@ func (a *AddrManager) Start() {
 
 	log.Trace("Starting address manager")
 
-	// Load peers we already know about from file.
-	a.loadPeers()
+	if !a.cfg.MemoryOnly {
+		// Load peers we already know about from file.
+		a.loadPeers()
+
+		// Load the bans and forget any known addresses of banned hosts.
+		a.loadBans()
+	}
+
+	// Forget any known addresses that match a registered blocklist.
+	a.mtx.Lock()
+	a.removeBlocked()
+
+	// Enforce the maximum number of addresses on the loaded addresses.
+	a.lruReset()
+	a.mtx.Unlock()
+
+	// Load the anchors saved when the address manager was last stopped.
+	if !a.cfg.MemoryOnly {
+		a.loadAnchors()
+	}
 
 	// Start the address ticker to save addresses periodically.
 	a.wg.Add(1)
-	go a.addressHandler()
+	go a.addressHandler(ctx)
+}
+
+// Flush immediately saves the known addresses and bans to disk instead of
+// waiting for them to be saved periodically, as configured by
+// DumpAddressInterval, or when the address manager is stopped.  The known
+// addresses are saved even when only the information tracked about them has
+// changed.  This allows embedders that rarely shut down cleanly to persist the
+// addresses learned so far at convenient times.  Nothing is saved when the
+// address manager is configured to be memory only.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) Flush() {
+	if a.cfg.MemoryOnly {
+		return
+	}
+
+	a.mtx.Lock()
+	a.addrChanged = true
+	a.mtx.Unlock()
+
+	a.savePeers()
+	a.saveBans()
 }
 
 // Stop gracefully shuts down the address manager by stopping the main handler.
@@ -599,6 +1085,7 @@ func (a *AddrManager) Stop() error {
 // number of addresses and silently ignores duplicate addresses.  It is
 // safe for concurrent access.
 func (a *AddrManager) AddAddresses(addrs []*wire.NetAddress, srcAddr *wire.NetAddress) {
+	defer a.sendEvents()
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -611,6 +1098,7 @@ func (a *AddrManager) AddAddresses(addrs []*wire.NetAddress, srcAddr *wire.NetAd
 // number of addresses and silently ignores duplicate addresses.  It is
 // safe for concurrent access.
 func (a *AddrManager) AddAddress(addr, srcAddr *wire.NetAddress) {
+	defer a.sendEvents()
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -656,6 +1144,57 @@ func (a *AddrManager) NeedMoreAddresses() bool {
 // AddressCache returns the current address cache.  It must be treated as
 // read-only (but since it is a copy now, this is not as dangerous).
 func (a *AddrManager) AddressCache() []*wire.NetAddress {
+	return a.AddressCacheWithServices(0)
+}
+
+// AddressCacheWithServices returns the current address cache limited to the
+// addresses that advertise all of the provided services.  It must be treated
+// as read-only.
+func (a *AddrManager) AddressCacheWithServices(services wire.ServiceFlag) []*wire.NetAddress {
+	return a.addressCache(hasServices(services))
+}
+
+// AddressCacheOnNetworks returns the current address cache limited to the
+// addresses that belong to any of the provided types of networks, such as only
+// onion addresses.  It must be treated as read-only.
+func (a *AddrManager) AddressCacheOnNetworks(networks ...NetworkAddress) []*wire.NetAddress {
+	return a.addressCache(onNetworks(networks))
+}
+
+// refuseNetwork returns whether or not the provided address must not be stored
+// or relayed because only onion addresses are accepted.
+func (a *AddrManager) refuseNetwork(na *wire.NetAddress) bool {
+	return a.cfg.OnionOnly && !isOnionCatTor(na)
+}
+
+// addressFilter returns whether or not a known address should be considered.
+type addressFilter func(ka *KnownAddress) bool
+
+// hasServices returns a filter that only considers addresses that advertise
+// all of the provided services.
+func hasServices(services wire.ServiceFlag) addressFilter {
+	return func(ka *KnownAddress) bool {
+		return ka.na.Services&services == services
+	}
+}
+
+// onNetworks returns a filter that only considers addresses that belong to any
+// of the provided types of networks.
+func onNetworks(networks []NetworkAddress) addressFilter {
+	return func(ka *KnownAddress) bool {
+		network := getNetwork(ka.na)
+		for _, n := range networks {
+			if network == n {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// addressCache returns the current address cache limited to the addresses
+// accepted by the provided filter.
+func (a *AddrManager) addressCache(filter addressFilter) []*wire.NetAddress {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -669,18 +1208,27 @@ func (a *AddrManager) AddressCache() []*wire.NetAddress {
 	// Iteration order is undefined here, but we randomise it anyway.
 	for _, v := range a.addrIndex {
 		// Skip low quality addresses.
-		if v.isBad() {
+		if v.isBad(&a.cfg) {
 			continue
 		}
 		// Skip addresses that never succeeded.
 		if v.lastsuccess.IsZero() {
 			continue
 		}
+		// Skip addresses that are filtered out.
+		if !filter(v) {
+			continue
+		}
 		allAddr = append(allAddr, v.na)
 	}
 
 	// Adjust length, we only deal with high quality addresses now.
 	addrLen = len(allAddr)
+	if a.cfg.Rand != nil {
+		sort.Slice(allAddr, func(i, j int) bool {
+			return NetAddressKey(allAddr[i]) < NetAddressKey(allAddr[j])
+		})
+	}
 
 	numAddresses := addrLen * getAddrPercent / 100
 	if numAddresses > getAddrMax {
@@ -696,7 +1244,32 @@ func (a *AddrManager) AddressCache() []*wire.NetAddress {
 	}
 
 	// Slice off the limit we are willing to share.
-	return allAddr[0:numAddresses]
+	allAddr = allAddr[0:numAddresses]
+
+	// Hide the precise times the addresses were last seen when they are
+	// relayed while keeping them internally.
+	if a.cfg.RelayTimestampGranularity > 0 {
+		for i, na := range allAddr {
+			relayed := *na
+			relayed.Timestamp = a.relayTimestamp(na.Timestamp)
+			allAddr[i] = &relayed
+		}
+	}
+	return allAddr
+}
+
+// relayTimestamp returns the provided last seen time of an address coarsened
+// for relay according to the configured granularity.  The time is truncated to
+// a multiple of the granularity and then moved back by a random offset of less
+// than the granularity, so it is never later than the precise time and does not
+// reveal it.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) relayTimestamp(ts time.Time) time.Time {
+	granularity := a.cfg.RelayTimestampGranularity
+	offset := time.Duration(a.rand.Int63n(int64(granularity)))
+	return ts.Truncate(granularity).Add(-offset).Truncate(time.Second)
 }
 
 // reset resets the address manager by reinitialising the random source
@@ -704,24 +1277,49 @@ func (a *AddrManager) AddressCache() []*wire.NetAddress {
 func (a *AddrManager) reset() {
 	a.addrIndex = make(map[string]*KnownAddress)
 
-	// fill key with bytes from a good random source.
-	io.ReadFull(crand.Reader, a.key[:])
+	// fill key with bytes from a good random source unless a deterministic
+	// source was provided.
+	keySource := crand.Reader
+	if a.cfg.Rand != nil {
+		keySource = a.rand
+	}
+	io.ReadFull(keySource, a.key[:])
+	a.addrNew = make([]map[string]*KnownAddress, a.cfg.NewBucketCount)
 	for i := range a.addrNew {
 		a.addrNew[i] = make(map[string]*KnownAddress)
 	}
-	for i := range a.addrTried {
-		a.addrTried[i] = nil
-	}
+	a.addrTried = make([][]*KnownAddress, a.cfg.TriedBucketCount)
+	a.triedCollisions = make(map[string]time.Time)
+	a.seedQuarantine = make(map[string]*seedAddress)
 	a.addrChanged = true
+	a.lru = nil
+	if a.cfg.MaxAddresses > 0 {
+		a.lru = list.New()
+	}
 }
 
 // HostToNetAddress returns a netaddress given a host address. If the address is
 // a Tor .onion address this will be taken care of. Else if the host is not an
-// IP address it will be resolved (via Tor if required).
+// IP address it will be resolved (via Tor if required) with the lookup function
+// of the address manager.
 func (a *AddrManager) HostToNetAddress(host string, port uint16, services wire.ServiceFlag) (*wire.NetAddress, error) {
-	// Tor address is 16 char base32 + ".onion"
+	return HostToNetAddress(host, port, services, a.lookupFunc)
+}
+
+// HostToNetAddress returns a netaddress given a host that is either a bare IP
+// address, a Tor .onion address, or a name that is resolved with the provided
+// lookup function, such as one that performs DNS lookups via Tor when required.
+// The first address returned by the lookup function is used.
+//
+// Version 3 .onion addresses are not supported since they are longer than can
+// be encoded in a netaddress.
+func HostToNetAddress(host string, port uint16, services wire.ServiceFlag,
+	lookupFunc func(string) ([]net.IP, error)) (*wire.NetAddress, error) {
+
 	var ip net.IP
-	if len(host) == 22 && host[16:] == ".onion" {
+	switch {
+	// Tor address is 16 char base32 + ".onion"
+	case len(host) == 22 && strings.EqualFold(host[16:], ".onion"):
 		// go base32 encoding uses capitals (as does the rfc
 		// but Tor and bitcoind tend to user lowercase, so we switch
 		// case here.
@@ -732,8 +1330,21 @@ func (a *AddrManager) HostToNetAddress(host string, port uint16, services wire.S
 		}
 		prefix := []byte{0xfd, 0x87, 0xd8, 0x7e, 0xeb, 0x43}
 		ip = net.IP(append(prefix, data...))
-	} else if ip = net.ParseIP(host); ip == nil {
-		ips, err := a.lookupFunc(host)
+
+	// Version 3 Tor address is 56 char base32 + ".onion"
+	case len(host) == 62 && strings.EqualFold(host[56:], ".onion"):
+		return nil, fmt.Errorf("version 3 onion address %s is not "+
+			"supported", host)
+
+	default:
+		if ip = net.ParseIP(host); ip != nil {
+			break
+		}
+		if lookupFunc == nil {
+			return nil, fmt.Errorf("unable to resolve %s: no lookup "+
+				"function", host)
+		}
+		ips, err := lookupFunc(host)
 		if err != nil {
 			return nil, err
 		}
@@ -775,13 +1386,113 @@ func (a *AddrManager) GetAddress() *KnownAddress {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
-	if a.numAddresses() == 0 {
+	return a.getAddress()
+}
+
+// GetAddresses returns up to n distinct addresses that should be routable and
+// each belong to a different address group.  The addresses are picked the same
+// way as GetAddress, however, the lock is only acquired once for the entire
+// batch which makes it more efficient to fill multiple outbound connection
+// slots.  Fewer than n addresses are returned when not enough addresses from
+// distinct groups are found within a bounded number of picks.
+func (a *AddrManager) GetAddresses(n int) []*KnownAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if n <= 0 || a.numAddresses() == 0 {
+		return nil
+	}
+
+	addrs := make([]*KnownAddress, 0, n)
+	groups := make(map[string]struct{}, n)
+	maxPicks := n * getAddressesPicksPerAddr
+	for picks := 0; picks < maxPicks && len(addrs) < n; picks++ {
+		ka := a.getAddress()
+		group := a.groupOf(ka)
+		if _, ok := groups[group]; ok {
+			continue
+		}
+		groups[group] = struct{}{}
+		addrs = append(addrs, ka)
+	}
+	return addrs
+}
+
+// GetAddressWithServices returns a single address that should be routable and
+// advertises all of the provided services, such as full nodes that serve
+// committed filters.  It is picked the same way as GetAddress, however, only
+// addresses with the provided services are considered so callers do not have
+// to repeatedly draw addresses that lack them.  It returns nil when there are
+// no known addresses with the provided services.
+func (a *AddrManager) GetAddressWithServices(services wire.ServiceFlag) *KnownAddress {
+	return a.getAddressMatching(hasServices(services))
+}
+
+// GetAddressOnNetworks returns a single address that should be routable and
+// belongs to any of the provided types of networks.  For example, operators
+// that only connect over Tor must never be handed clearnet addresses.  It is
+// picked the same way as GetAddress and returns nil when there are no known
+// addresses on the provided networks.
+func (a *AddrManager) GetAddressOnNetworks(networks ...NetworkAddress) *KnownAddress {
+	return a.getAddressMatching(onNetworks(networks))
+}
+
+// getAddressMatching returns a single address accepted by the provided filter
+// that is picked the same way as GetAddress, or nil when there are none.
+func (a *AddrManager) getAddressMatching(filter addressFilter) *KnownAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	var tried, untried []*KnownAddress
+	for _, ka := range a.addrIndex {
+		if !filter(ka) {
+			continue
+		}
+		if ka.tried {
+			tried = append(tried, ka)
+		} else {
+			untried = append(untried, ka)
+		}
+	}
+	if len(tried) == 0 && len(untried) == 0 {
 		return nil
 	}
+	a.orderForSelection(tried)
+	a.orderForSelection(untried)
+
+	// Use a 50% chance for choosing between tried and new entries.
+	candidates := untried
+	if len(tried) > 0 && (len(untried) == 0 || a.rand.Intn(2) == 0) {
+		candidates = tried
+	}
+	large := 1 << 30
+	factor := 1.0
+	now := a.cfg.now()
+	for {
+		ka := candidates[a.rand.Intn(len(candidates))]
+		randval := a.rand.Intn(large)
+		if float64(randval) < (factor * ka.chance(now) * float64(large)) {
+			log.Tracef("Selected filtered address %v",
+				ka.key())
+			return ka
+		}
+		factor *= 1.2
+	}
+}
+
+// getAddress returns a single address that should be routable as described by
+// GetAddress.
+//
+// This function MUST be called with the address manager lock held.
+func (a *AddrManager) getAddress() *KnownAddress {
+	if a.numAddresses() == 0 {
+		return a.getQuarantinedAddress()
+	}
 
 	// Use a 50% chance for choosing between tried and new table entries.
 	large := 1 << 30
 	factor := 1.0
+	now := a.cfg.now()
 	if a.nTried > 0 && (a.nNew == 0 || a.rand.Intn(2) == 0) {
 		// Tried entry.
 		for {
@@ -796,9 +1507,9 @@ func (a *AddrManager) GetAddress() *KnownAddress {
 			ka := a.addrTried[bucket][randEntry]
 
 			randval := a.rand.Intn(large)
-			if float64(randval) < (factor * ka.chance() * float64(large)) {
+			if float64(randval) < (factor * ka.chance(now) * float64(large)) {
 				log.Tracef("Selected %v from tried bucket",
-					NetAddressKey(ka.na))
+					ka.key())
 				return ka
 			}
 			factor *= 1.2
@@ -813,18 +1524,12 @@ func (a *AddrManager) GetAddress() *KnownAddress {
 			}
 
 			// Then, a random entry in it.
-			var ka *KnownAddress
 			nth := a.rand.Intn(len(a.addrNew[bucket]))
-			for _, value := range a.addrNew[bucket] {
-				if nth == 0 {
-					ka = value
-				}
-				nth--
-			}
+			ka := a.nthNewAddress(bucket, nth)
 			randval := a.rand.Intn(large)
-			if float64(randval) < (factor * ka.chance() * float64(large)) {
+			if float64(randval) < (factor * ka.chance(now) * float64(large)) {
 				log.Tracef("Selected %v from new bucket",
-					NetAddressKey(ka.na))
+					ka.key())
 				return ka
 			}
 			factor *= 1.2
@@ -832,10 +1537,153 @@ func (a *AddrManager) GetAddress() *KnownAddress {
 	}
 }
 
+// nthNewAddress returns the nth address in the provided new bucket.  The
+// addresses are ordered by key when a deterministic source of randomness is in
+// use since the iteration order of the bucket is otherwise random.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) nthNewAddress(bucket, nth int) *KnownAddress {
+	if a.cfg.Rand != nil {
+		addrs := make([]*KnownAddress, 0, len(a.addrNew[bucket]))
+		for _, ka := range a.addrNew[bucket] {
+			addrs = append(addrs, ka)
+		}
+		a.orderForSelection(addrs)
+		return addrs[nth]
+	}
+	for _, ka := range a.addrNew[bucket] {
+		if nth == 0 {
+			return ka
+		}
+		nth--
+	}
+	return nil
+}
+
+// orderForSelection sorts the provided addresses by key when a deterministic
+// source of randomness is in use so that randomly selecting from them does not
+// depend on the iteration order of the maps they were collected from.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) orderForSelection(addrs []*KnownAddress) {
+	if a.cfg.Rand == nil {
+		return
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].key() < addrs[j].key()
+	})
+}
+
 func (a *AddrManager) find(addr *wire.NetAddress) *KnownAddress {
 	return a.addrIndex[NetAddressKey(addr)]
 }
 
+// GetFeelerAddress returns an address from the new buckets to make a feeler
+// connection to.  Feeler connections are short-lived connections used to test
+// whether the addresses in the new buckets are reachable so they are promoted
+// to the tried buckets, or eventually evicted otherwise.  Addresses that have
+// never been attempted are preferred.  Addresses that had a feeler attempt
+// recorded via FeelerAttempt within the last feelerRetryInterval are not
+// returned.  Addresses learned from seeders that are still quarantined are
+// candidates as well so they are promoted once they are reachable.  It returns
+// nil when there are no candidates.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) GetFeelerAddress() *KnownAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	var untried, retry []*KnownAddress
+	now := a.cfg.now()
+	all := make([]*KnownAddress, 0, len(a.addrIndex)+len(a.seedQuarantine))
+	for _, ka := range a.addrIndex {
+		all = append(all, ka)
+	}
+	for _, entry := range a.seedQuarantine {
+		all = append(all, entry.ka)
+	}
+	for _, ka := range all {
+		if ka.tried {
+			continue
+		}
+		ka.mtx.Lock()
+		attempted := ka.attempts > 0 || !ka.lastfeeler.IsZero()
+		recent := now.Sub(ka.lastfeeler) < feelerRetryInterval
+		ka.mtx.Unlock()
+		switch {
+		case !attempted:
+			untried = append(untried, ka)
+		case !recent:
+			retry = append(retry, ka)
+		}
+	}
+
+	candidates := untried
+	if len(candidates) == 0 {
+		candidates = retry
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	a.orderForSelection(candidates)
+	ka := candidates[a.rand.Intn(len(candidates))]
+	log.Tracef("Selected %v for feeler connection", ka.key())
+	return ka
+}
+
+// FeelerAttempt records a feeler connection attempt to the given address.
+// Unlike Attempt, it does not increase the attempt counter used to determine
+// whether the address is bad or how likely it is to be selected by GetAddress.
+// A successful feeler connection should be followed by a call to Good to
+// promote the address to the tried buckets.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) FeelerAttempt(addr *wire.NetAddress) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		ka = a.findQuarantined(addr)
+	}
+	if ka == nil {
+		return
+	}
+
+	ka.mtx.Lock()
+	ka.lastfeeler = a.cfg.now()
+	ka.mtx.Unlock()
+	a.metrics.feelerAttempts++
+}
+
+// HasAddress returns whether or not the given address is known to the address
+// manager.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) HasAddress(addr *wire.NetAddress) bool {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return a.find(addr) != nil
+}
+
+// Lookup returns a snapshot of the information tracked about the given address
+// and whether or not it is known to the address manager.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) Lookup(addr *wire.NetAddress) (*KnownAddressSnapshot, bool) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		return nil, false
+	}
+	return ka.snapshot(), true
+}
+
 // Attempt increases the given address' attempt counter and updates
 // the last attempt time.
 func (a *AddrManager) Attempt(addr *wire.NetAddress) {
@@ -845,6 +1693,9 @@ func (a *AddrManager) Attempt(addr *wire.NetAddress) {
 	// find address.
 	// Surely address will be in tried by now?
 	ka := a.find(addr)
+	if ka == nil {
+		ka = a.findQuarantined(addr)
+	}
 	if ka == nil {
 		return
 	}
@@ -852,14 +1703,20 @@ func (a *AddrManager) Attempt(addr *wire.NetAddress) {
 	// set last tried time to now
 	ka.mtx.Lock()
 	ka.attempts++
-	ka.lastattempt = time.Now()
+	ka.totalAttempts++
+	ka.lastattempt = a.cfg.now()
 	ka.mtx.Unlock()
+	a.metrics.attempts++
+	if !ka.tried {
+		a.lruTouch(ka)
+	}
 }
 
 // Connected Marks the given address as currently connected and working at the
 // current time.  The address must already be known to AddrManager else it will
 // be ignored.
 func (a *AddrManager) Connected(addr *wire.NetAddress) {
+	defer a.sendEvents()
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -870,46 +1727,126 @@ func (a *AddrManager) Connected(addr *wire.NetAddress) {
 
 	// Update the time as long as it has been 20 minutes since last we did
 	// so.
-	now := time.Now()
+	now := a.cfg.now()
 	if now.After(ka.na.Timestamp.Add(time.Minute * 20)) {
 		// ka.na is immutable, so replace it.
 		ka.mtx.Lock()
 		naCopy := *ka.na
-		naCopy.Timestamp = time.Now()
+		naCopy.Timestamp = now
 		ka.na = &naCopy
 		ka.mtx.Unlock()
+		a.notify(EventUpdated, &naCopy)
 	}
 }
 
 // Good marks the given address as good.  To be called after a successful
-// connection and version exchange.  If the address is unknown to the address
-// manager it will be ignored.
+// connection and version exchange.  Addresses learned from seeders that are
+// still quarantined are promoted to the new buckets first.  If the address is
+// unknown to the address manager it will be ignored.
 func (a *AddrManager) Good(addr *wire.NetAddress) {
+	defer a.sendEvents()
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
 	ka := a.find(addr)
+	if ka == nil {
+		ka = a.promoteQuarantined(addr)
+	}
 	if ka == nil {
 		return
 	}
 
 	// ka.Timestamp is not updated here to avoid leaking information
 	// about currently connected peers.
-	now := time.Now()
+	now := a.cfg.now()
+	ka.mtx.Lock()
 	ka.lastsuccess = now
 	ka.lastattempt = now
 	ka.attempts = 0
+	ka.totalSuccesses++
+	ka.mtx.Unlock()
 
 	// move to tried set, optionally evicting other addresses if needed.
 	if ka.tried {
 		return
 	}
 
-	// ok, need to move it to tried.
+	// When testing before evicting, leave the address in the new buckets
+	// and record the collision instead of evicting an address from a full
+	// tried bucket.  The collision is resolved by ResolveCollisions once
+	// the address that would be evicted has been tested.
+	bucket := a.knownTriedBucket(ka)
+	if a.cfg.TestBeforeEvict &&
+		len(a.addrTried[bucket]) >= a.cfg.TriedBucketSize {
+
+		a.addTriedCollision(ka)
+		return
+	}
+
+	a.moveToTried(ka)
+}
+
+// DeleteAddress forgets the given address by removing it from the new or tried
+// buckets it is in.  It returns whether or not the address was known.  Unlike
+// addresses of banned hosts, the address is stored again should it be added
+// later.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) DeleteAddress(addr *wire.NetAddress) bool {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		return false
+	}
+	a.removeAddress(ka)
+	log.Debugf("Deleted address %s", NetAddressKey(addr))
+	return true
+}
 
+// removeAddress forgets the provided known address by removing it from the new
+// or tried buckets it is in.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) removeAddress(ka *KnownAddress) {
+	key := ka.key()
+	if ka.tried {
+		for i, bucket := range a.addrTried {
+			for j, v := range bucket {
+				if v == ka {
+					a.addrTried[i] = append(bucket[:j],
+						bucket[j+1:]...)
+					break
+				}
+			}
+		}
+		ka.tried = false
+		a.nTried--
+	} else {
+		for i := range a.addrNew {
+			delete(a.addrNew[i], key)
+		}
+		ka.refs = 0
+		a.nNew--
+	}
+	delete(a.addrIndex, key)
+	delete(a.triedCollisions, key)
+	a.lruRemove(ka)
+	a.addrChanged = true
+}
+
+// moveToTried moves the provided address from the new buckets to the tried
+// buckets, evicting another address from its tried bucket back to the new
+// buckets if needed.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) moveToTried(ka *KnownAddress) {
 	// remove from all new buckets.
 	// record one of the buckets in question and call it the `first'
-	addrKey := NetAddressKey(addr)
+	addrKey := ka.key()
 	oldBucket := -1
 	for i := range a.addrNew {
 		// we check for existence so we can record the first one
@@ -929,14 +1866,16 @@ func (a *AddrManager) Good(addr *wire.NetAddress) {
 		return
 	}
 
-	bucket := a.getTriedBucket(ka.na)
+	bucket := a.knownTriedBucket(ka)
 
 	// Room in this tried bucket?
-	if len(a.addrTried[bucket]) < triedBucketSize {
+	if len(a.addrTried[bucket]) < a.cfg.TriedBucketSize {
 		ka.tried = true
 		a.addrTried[bucket] = append(a.addrTried[bucket], ka)
 		a.addrChanged = true
 		a.nTried++
+		a.lruRemove(ka)
+		a.notify(EventPromoted, ka.na)
 		return
 	}
 
@@ -945,17 +1884,19 @@ func (a *AddrManager) Good(addr *wire.NetAddress) {
 	rmka := a.addrTried[bucket][triedIdx]
 
 	// First bucket it would have been put in.
-	newBucket := a.getNewBucket(rmka.na, rmka.srcAddr)
+	newBucket := a.knownNewBucket(rmka)
 
 	// If no room in the original bucket, we put it in a bucket we just
 	// freed up a space in.
-	if len(a.addrNew[newBucket]) >= newBucketSize {
+	if len(a.addrNew[newBucket]) >= a.cfg.NewBucketSize {
 		newBucket = oldBucket
 	}
 
 	// replace with ka in list.
 	ka.tried = true
 	a.addrTried[bucket][triedIdx] = ka
+	a.lruRemove(ka)
+	a.notify(EventPromoted, ka.na)
 
 	rmka.tried = false
 	rmka.refs++
@@ -965,15 +1906,44 @@ func (a *AddrManager) Good(addr *wire.NetAddress) {
 	// something back.
 	a.nNew++
 
-	rmkey := NetAddressKey(rmka.na)
+	rmkey := rmka.key()
 	log.Tracef("Replacing %s with %s in tried", rmkey, addrKey)
 
 	// We made sure there is space here just above.
 	a.addrNew[newBucket][rmkey] = rmka
+	a.lruTouch(rmka)
+	a.notify(EventEvicted, rmka.na)
 }
 
-// SetServices sets the services for the given address to the provided value.
-func (a *AddrManager) SetServices(addr *wire.NetAddress, services wire.ServiceFlag) {
+// ServedPoorly marks the given address as having served poorly, such as by
+// stalling or sending invalid data while the chain is being synced from it.
+// Addresses that have served poorly are less likely to be selected for new
+// connections.  If the address is unknown to the address manager it will be
+// ignored.
+func (a *AddrManager) ServedPoorly(addr *wire.NetAddress) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		return
+	}
+
+	ka.mtx.Lock()
+	if ka.poorService < maxPoorService {
+		ka.poorService++
+	}
+	ka.mtx.Unlock()
+}
+
+// RecordConnectionStats records the statistics of a connection to the given
+// address once it is closed.  The latency, such as the round trip time of the
+// most recent ping, updates a rolling average of the latency of the address
+// unless it is zero, and the uptime is added to the cumulative time connected
+// to the address.  Addresses with low latency that have historically stayed
+// connected are more likely to be selected for new connections.  If the
+// address is unknown to the address manager it will be ignored.
+func (a *AddrManager) RecordConnectionStats(addr *wire.NetAddress, latency, uptime time.Duration) {
 	a.mtx.Lock()
 	defer a.mtx.Unlock()
 
@@ -982,6 +1952,85 @@ func (a *AddrManager) SetServices(addr *wire.NetAddress, services wire.ServiceFl
 		return
 	}
 
+	ka.mtx.Lock()
+	if latency > 0 {
+		if ka.latency == 0 {
+			ka.latency = latency
+		} else {
+			ka.latency = (ka.latency*latencyAverageWeight + latency) /
+				(latencyAverageWeight + 1)
+		}
+	}
+	if uptime > 0 {
+		ka.uptime += uptime
+	}
+	ka.mtx.Unlock()
+}
+
+// SetLastBlock sets the height of the last block advertised by the peer at the
+// given address, such as in its version message, along with the height of the
+// local best chain at the time.  Addresses of peers that were far behind the
+// local best chain are less likely to be selected for new connections.  If the
+// address is unknown to the address manager it will be ignored.
+func (a *AddrManager) SetLastBlock(addr *wire.NetAddress, height, chainHeight int64) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		return
+	}
+
+	behind := chainHeight - height
+	if behind < 0 {
+		behind = 0
+	}
+	ka.mtx.Lock()
+	ka.lastBlock = height
+	ka.blocksBehind = behind
+	ka.mtx.Unlock()
+}
+
+// SetUserAgent sets the user agent and protocol version advertised by the peer
+// at the given address to the provided values so the software it runs may be
+// considered when selecting addresses for new connections.  If the address is
+// unknown to the address manager it will be ignored.
+func (a *AddrManager) SetUserAgent(addr *wire.NetAddress, userAgent string, protocolVersion uint32) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	ka := a.find(addr)
+	if ka == nil {
+		return
+	}
+
+	ka.mtx.Lock()
+	ka.userAgent = userAgent
+	ka.protocolVersion = protocolVersion
+	ka.mtx.Unlock()
+}
+
+// ServicesUpdate describes the services of an address for SetServicesBatch.
+type ServicesUpdate struct {
+	Addr     *wire.NetAddress
+	Services wire.ServiceFlag
+}
+
+// setServices sets the services of the given address to the provided value, or
+// adds them to its existing services when merge is true.  Unknown addresses are
+// ignored.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) setServices(addr *wire.NetAddress, services wire.ServiceFlag, merge bool) {
+	ka := a.find(addr)
+	if ka == nil {
+		return
+	}
+	if merge {
+		services |= ka.na.Services
+	}
+
 	// Update the services if needed.
 	if ka.na.Services != services {
 		// ka.na is immutable, so replace it.
@@ -990,39 +2039,173 @@ func (a *AddrManager) SetServices(addr *wire.NetAddress, services wire.ServiceFl
 		naCopy.Services = services
 		ka.na = &naCopy
 		ka.mtx.Unlock()
+		a.notify(EventUpdated, &naCopy)
+	}
+}
+
+// SetServices sets the services for the given address to the provided value.
+func (a *AddrManager) SetServices(addr *wire.NetAddress, services wire.ServiceFlag) {
+	defer a.sendEvents()
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.setServices(addr, services, false)
+}
+
+// AddServices adds the provided services to those already known for the given
+// address rather than replacing them, so services learned from multiple sources
+// do not clobber each other.
+func (a *AddrManager) AddServices(addr *wire.NetAddress, services wire.ServiceFlag) {
+	defer a.sendEvents()
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.setServices(addr, services, true)
+}
+
+// SetServicesBatch updates the services of multiple addresses at once while
+// only acquiring the lock once.  The services of each address are set to the
+// provided value, or added to those already known when merge is true.  Unknown
+// addresses are ignored.
+func (a *AddrManager) SetServicesBatch(updates []ServicesUpdate, merge bool) {
+	defer a.sendEvents()
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	for _, update := range updates {
+		a.setServices(update.Addr, update.Services, merge)
 	}
 }
 
 // AddLocalAddress adds na to the list of known local addresses to advertise
 // with the given priority.
 func (a *AddrManager) AddLocalAddress(na *wire.NetAddress, priority AddressPriority) error {
-	if !IsRoutable(na) {
+	if !a.IsRoutable(na) {
 		return fmt.Errorf("address %s is not routable", na.IP)
 	}
 
 	a.lamtx.Lock()
-	defer a.lamtx.Unlock()
+	a.addLocalAddress(na, priority)
+	a.lamtx.Unlock()
+	return nil
+}
 
+// addLocalAddress adds na to the list of known local addresses with the given
+// priority, or raises the priority of the address if it is already known, and
+// returns the local address.
+//
+// This function MUST be called with the local address lock held (for writes).
+func (a *AddrManager) addLocalAddress(na *wire.NetAddress, priority AddressPriority) *localAddress {
 	key := NetAddressKey(na)
 	la, ok := a.localAddresses[key]
 	if !ok || la.score < priority {
 		if ok {
 			la.score = priority + 1
 		} else {
-			a.localAddresses[key] = &localAddress{
+			la = &localAddress{
 				na:    na,
 				score: priority,
 			}
+			a.localAddresses[key] = la
 		}
 	}
+	return la
+}
+
+// AddLocalAddressWithExpiry adds na to the list of known local addresses to
+// advertise with the given priority for as long as the provided time to live,
+// such as the lease of a port mapping obtained via UPnP or NAT-PMP.  The address
+// is no longer advertised once the lease expires unless it is added again
+// beforehand.  When a refresh callback is provided, it is invoked to renew the
+// lease once half of it has elapsed.  Adding an address that is already known
+// without a lease keeps it from expiring.
+//
+// The leases are only refreshed and expired addresses removed while the
+// address manager is running, however, expired addresses are never returned by
+// GetBestLocalAddress.
+func (a *AddrManager) AddLocalAddressWithExpiry(na *wire.NetAddress, priority AddressPriority, ttl time.Duration, refresh LocalAddressRefreshFunc) error {
+	if !a.IsRoutable(na) {
+		return fmt.Errorf("address %s is not routable", na.IP)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("invalid lease time to live %v", ttl)
+	}
+
+	a.lamtx.Lock()
+	defer a.lamtx.Unlock()
+
+	existing, ok := a.localAddresses[NetAddressKey(na)]
+	la := a.addLocalAddress(na, priority)
+	if ok && existing.expires.IsZero() {
+		// The address is already known without a lease.
+		return nil
+	}
+	la.ttl = ttl
+	la.expires = a.cfg.now().Add(ttl)
+	la.refresh = refresh
 	return nil
 }
 
+// refreshLocalAddresses renews the leases of the local addresses that are past
+// half of their lease and have a refresh callback, and removes the local
+// addresses with expired leases.
+//
+// This function MUST NOT be called with the local address lock held since
+// the refresh callbacks are invoked without it.
+func (a *AddrManager) refreshLocalAddresses() {
+	type pendingRefresh struct {
+		key string
+		la  *localAddress
+	}
+	var pending []pendingRefresh
+	now := a.cfg.now()
+	a.lamtx.Lock()
+	for key, la := range a.localAddresses {
+		if la.expires.IsZero() {
+			continue
+		}
+		if la.refresh != nil && !now.Before(la.expires.Add(-la.ttl/2)) {
+			pending = append(pending, pendingRefresh{key, la})
+			continue
+		}
+		if la.expired(now) {
+			log.Debugf("Lease of local address %s expired", key)
+			delete(a.localAddresses, key)
+		}
+	}
+	a.lamtx.Unlock()
+
+	for _, p := range pending {
+		ttl, err := p.la.refresh(p.la.na)
+		now := a.cfg.now()
+		a.lamtx.Lock()
+		switch {
+		case a.localAddresses[p.key] != p.la:
+			// The address was removed or replaced in the mean time.
+
+		case err == nil && ttl > 0:
+			p.la.ttl = ttl
+			p.la.expires = now.Add(ttl)
+
+		case p.la.expired(now):
+			log.Debugf("Lease of local address %s expired after "+
+				"failing to refresh it: %v", p.key, err)
+			delete(a.localAddresses, p.key)
+
+		default:
+			log.Debugf("Unable to refresh lease of local address %s: %v",
+				p.key, err)
+		}
+		a.lamtx.Unlock()
+	}
+}
+
 // HasLocalAddress asserts if the manager has the provided local address.
 func (a *AddrManager) HasLocalAddress(na *wire.NetAddress) bool {
 	key := NetAddressKey(na)
 	a.lamtx.Lock()
-	_, ok := a.localAddresses[key]
+	la, ok := a.localAddresses[key]
+	ok = ok && !la.expired(a.cfg.now())
 	a.lamtx.Unlock()
 	return ok
 }
@@ -1034,7 +2217,11 @@ func (a *AddrManager) FetchLocalAddresses() []LocalAddr {
 	defer a.lamtx.Unlock()
 
 	addrs := make([]LocalAddr, 0, len(a.localAddresses))
+	now := a.cfg.now()
 	for _, addr := range a.localAddresses {
+		if addr.expired(now) {
+			continue
+		}
 		la := LocalAddr{
 			Address: addr.na.IP.String(),
 			Port:    addr.na.Port,
@@ -1068,14 +2255,28 @@ const (
 	// Ipv6Strong represents a connection state between two IPV6 addresses.
 	Ipv6Strong
 
-	// Private represents a connection state connect between two Tor addresses.
+	// Private represents a connection state connect between two Tor addresses
+	// or two cjdns addresses.
 	Private
 )
 
 // getReachabilityFrom returns the relative reachability of the provided local
 // address to the provided remote address.
-func getReachabilityFrom(localAddr, remoteAddr *wire.NetAddress) int {
-	if !IsRoutable(remoteAddr) {
+func (a *AddrManager) getReachabilityFrom(localAddr, remoteAddr *wire.NetAddress) int {
+	if !a.IsRoutable(remoteAddr) {
+		return Unreachable
+	}
+
+	// cjdns addresses are only reachable from other cjdns addresses since
+	// the network is not connected to the internet.
+	localCJDNS := isCJDNS(localAddr) && a.IsRoutable(localAddr)
+	if isCJDNS(remoteAddr) {
+		if localCJDNS {
+			return Private
+		}
+		return Unreachable
+	}
+	if localCJDNS {
 		return Unreachable
 	}
 
@@ -1084,7 +2285,7 @@ func getReachabilityFrom(localAddr, remoteAddr *wire.NetAddress) int {
 			return Private
 		}
 
-		if IsRoutable(localAddr) && isIPv4(localAddr) {
+		if a.IsRoutable(localAddr) && isIPv4(localAddr) {
 			return Ipv4
 		}
 
@@ -1092,7 +2293,7 @@ func getReachabilityFrom(localAddr, remoteAddr *wire.NetAddress) int {
 	}
 
 	if isRFC4380(remoteAddr) {
-		if !IsRoutable(localAddr) {
+		if !a.IsRoutable(localAddr) {
 			return Default
 		}
 
@@ -1108,7 +2309,7 @@ func getReachabilityFrom(localAddr, remoteAddr *wire.NetAddress) int {
 	}
 
 	if isIPv4(remoteAddr) {
-		if IsRoutable(localAddr) && isIPv4(localAddr) {
+		if a.IsRoutable(localAddr) && isIPv4(localAddr) {
 			return Ipv4
 		}
 		return Unreachable
@@ -1121,7 +2322,7 @@ func getReachabilityFrom(localAddr, remoteAddr *wire.NetAddress) int {
 		tunnelled = true
 	}
 
-	if !IsRoutable(localAddr) {
+	if !a.IsRoutable(localAddr) {
 		return Default
 	}
 
@@ -1150,8 +2351,12 @@ func (a *AddrManager) GetBestLocalAddress(remoteAddr *wire.NetAddress) *wire.Net
 	bestreach := 0
 	var bestscore AddressPriority
 	var bestAddress *wire.NetAddress
+	now := a.cfg.now()
 	for _, la := range a.localAddresses {
-		reach := getReachabilityFrom(la.na, remoteAddr)
+		if la.expired(now) || !a.advertisable(la) {
+			continue
+		}
+		reach := a.getReachabilityFrom(la.na, remoteAddr)
 		if reach > bestreach ||
 			(reach == bestreach && la.score > bestscore) {
 			bestreach = reach
@@ -1179,12 +2384,35 @@ func (a *AddrManager) GetBestLocalAddress(remoteAddr *wire.NetAddress) *wire.Net
 	return bestAddress
 }
 
+// IsExternalAddrCandidate returns whether or not the provided local address, as
+// suggested by the remote peer with the provided address, is a candidate for
+// the external address of the local node.  In addition to being valid per
+// ValidatePeerNa, the peer must be on a network where it observes the actual
+// address of the local node, so addresses suggested by peers on Tor are never
+// candidates, and clearnet addresses are not candidates when clearnet
+// connections are made through a proxy according to the dial policy.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) IsExternalAddrCandidate(localAddr, remoteAddr *wire.NetAddress) bool {
+	if isOnionCatTor(remoteAddr) {
+		return false
+	}
+	a.lamtx.Lock()
+	proxied := a.dialPolicy.ClearnetProxied
+	a.lamtx.Unlock()
+	if proxied && isClearnet(localAddr) {
+		return false
+	}
+	valid, _ := a.ValidatePeerNa(localAddr, remoteAddr)
+	return valid
+}
+
 // ValidatePeerNa returns the validity and reachability of the
 // provided local address based on its routablility and reachability
 // from the peer that suggested it.
 func (a *AddrManager) ValidatePeerNa(localAddr, remoteAddr *wire.NetAddress) (bool, int) {
 	net := getNetwork(localAddr)
-	reach := getReachabilityFrom(localAddr, remoteAddr)
+	reach := a.getReachabilityFrom(localAddr, remoteAddr)
 	valid := (net == IPv4Address && reach == Ipv4) || (net == IPv6Address &&
 		(reach == Ipv6Weak || reach == Ipv6Strong || reach == Teredo))
 	return valid, reach
@@ -1200,17 +2428,307 @@ func (a *AddrManager) IsPeerNaValid(localAddr, remoteAddr *wire.NetAddress) bool
 	return valid
 }
 
-// New returns a new Decred address manager.
+// SetASMap sets the map of IP addresses to the autonomous systems (AS) they are
+// announced by which is used to group addresses.  When set, addresses are
+// grouped by AS number instead of by network prefix so an adversary that
+// controls many addresses spread across prefixes in a single AS is not able to
+// dominate the buckets as easily.  Addresses that are already known are
+// redistributed accordingly when they are loaded.
+//
+// This MUST be called before Start.
+func (a *AddrManager) SetASMap(asmap *ASMap) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.asmap = asmap
+
+	// Clear the cached groups since they depend on the asmap.
+	for _, ka := range a.addrIndex {
+		ka.cachedGroup = ""
+		ka.cachedSrcGroup = ""
+	}
+}
+
+// SetPeersFileFormat sets the format used to save known addresses.  Peers saved
+// in the binary format are stored in a separate file from the default JSON
+// format.  Addresses saved in the other format are loaded when there is no
+// file for the configured one.
+//
+// This MUST be called before Start.
+func (a *AddrManager) SetPeersFileFormat(format PeersFileFormat) {
+	a.peersFormat = format
+	a.peersFile = filepath.Join(filepath.Dir(a.peersFile), format.filename())
+}
+
+// asmapChecksum returns the checksum of the asmap in use or an empty string
+// when there is none.
+func (a *AddrManager) asmapChecksum() string {
+	if a.asmap == nil {
+		return ""
+	}
+	return a.asmap.Checksum()
+}
+
+// GroupKey returns a string representing the network group an address is part
+// of.  It is the same as the package level GroupKey function except cjdns
+// addresses are grouped separately when CJDNSReachable is set in the config.
+// Additionally, when an asmap has been set, IPv4 and IPv6 addresses with a
+// known AS number, including IPv4 addresses embedded in IPv6 addresses, are
+// grouped by the string "as:num" where num is the AS number instead.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) GroupKey(na *wire.NetAddress) string {
+	if a.asmap == nil || isLocal(na) || !a.IsRoutable(na) ||
+		isOnionCatTor(na) || isCJDNS(na) {
+
+		return groupKey(na, a.cfg.CJDNSReachable)
+	}
+
+	ip := na.IP
+	switch {
+	case isIPv4(na):
+		ip = na.IP.To4()
+	case isRFC6145(na) || isRFC6052(na):
+		ip = na.IP[12:16]
+	case isRFC3964(na):
+		ip = na.IP[2:6]
+	case isRFC4380(na):
+		ip = make(net.IP, 4)
+		for i, b := range na.IP[12:16] {
+			ip[i] = b ^ 0xff
+		}
+	}
+	if asn := a.asmap.Lookup(ip); asn != 0 {
+		return fmt.Sprintf("as:%d", asn)
+	}
+	return groupKey(na, a.cfg.CJDNSReachable)
+}
+
+// IsRoutable returns whether or not the passed address is routable.  It is the
+// same as the package level IsRoutable function except addresses in the cjdns
+// address block are also routable when CJDNSReachable is set in the config.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) IsRoutable(na *wire.NetAddress) bool {
+	return isRoutable(na, a.cfg.CJDNSReachable)
+}
+
+// groupOf returns the group of the provided known address as returned by
+// GroupKey.  It is computed once and cached until the asmap changes.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) groupOf(ka *KnownAddress) string {
+	if ka.cachedGroup == "" {
+		ka.cachedGroup = a.GroupKey(ka.na)
+	}
+	return ka.cachedGroup
+}
+
+// srcGroupOf returns the group of the source of the provided known address as
+// returned by GroupKey.  It is computed once and cached until the asmap
+// changes.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) srcGroupOf(ka *KnownAddress) string {
+	if ka.cachedSrcGroup == "" {
+		ka.cachedSrcGroup = a.GroupKey(ka.srcAddr)
+	}
+	return ka.cachedSrcGroup
+}
+
+// Clock provides the current time to an address manager.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+}
+
+// Config houses the tunable parameters of an address manager.  Any fields that
+// are left at their zero value use the package defaults.
+type Config struct {
+	// DataDir is the directory the known addresses are saved in.
+	DataDir string
+
+	// LookupFunc is used for the DNS lookups of hosts that are not IP
+	// addresses.
+	LookupFunc func(string) ([]net.IP, error)
+
+	// TriedBucketSize is the maximum number of addresses in each tried
+	// address bucket.
+	TriedBucketSize int
+
+	// TriedBucketCount is the number of buckets tried addresses are spread
+	// over.
+	TriedBucketCount int
+
+	// NewBucketSize is the maximum number of addresses in each new address
+	// bucket.
+	NewBucketSize int
+
+	// NewBucketCount is the number of buckets new addresses are spread
+	// over.
+	NewBucketCount int
+
+	// NumMissingDays is the number of days after which an address is
+	// assumed to have vanished if it has not been announced in that long.
+	NumMissingDays int
+
+	// NumRetries is the number of attempts without a single success after
+	// which an address is assumed to be bad.
+	NumRetries int
+
+	// MaxFailures is the maximum number of failures accepted without a
+	// success in the last MinBadDays before an address is considered bad.
+	MaxFailures int
+
+	// MinBadDays is the number of days since the last success before an
+	// address is considered for eviction.
+	MinBadDays int
+
+	// DumpAddressInterval is the interval the known addresses are saved to
+	// disk at.
+	DumpAddressInterval time.Duration
+
+	// SourceRateLimit is the number of addresses per second that are
+	// accepted from each source address group, such as the group of a peer
+	// that relays them.  A negative rate disables the limit.
+	SourceRateLimit float64
+
+	// SourceRateBurst is the maximum number of addresses that are accepted
+	// at once from each source address group.
+	SourceRateBurst int
+
+	// TestBeforeEvict prevents Good from evicting an address from a full
+	// tried bucket.  Instead, the collision is recorded and the address is
+	// only moved to the tried bucket by ResolveCollisions once the address
+	// that would be evicted fails a connectivity test.
+	TestBeforeEvict bool
+
+	// OnionOnly refuses to store or relay any addresses that are not Tor
+	// onion addresses.  Previously saved addresses on other networks are
+	// discarded when the known addresses are loaded, so they are no longer
+	// persisted to disk.
+	OnionOnly bool
+
+	// CJDNSReachable treats addresses in the IPv6 address block used by
+	// cjdns (fc00::/8) as routable.  They are otherwise treated as
+	// unroutable RFC4193 unique local addresses.  It should only be set
+	// when the local node is connected to a cjdns network.
+	CJDNSReachable bool
+
+	// Clock provides the current time used to track when addresses were
+	// seen, attempted, and banned and to determine when they expire.  It
+	// defaults to the system clock.  Providing another clock allows the
+	// passage of time to be simulated, which is only intended for tests
+	// and simulations.
+	Clock Clock
+
+	// Rand is the source of randomness used to select addresses and to
+	// generate the secret key that determines which buckets addresses are
+	// placed in.  By default, addresses are selected with a source seeded
+	// from the current time and the key is generated by a cryptographically
+	// secure source.  Providing a deterministically seeded source makes the
+	// selection and placement of addresses reproducible, which is only
+	// intended for tests and simulations.  It MUST NOT be used by anything
+	// else since it is not safe for concurrent access.
+	Rand *rand.Rand
+
+	// MemoryOnly keeps the known addresses, bans, and anchors in memory
+	// only.  Nothing is loaded from or saved to the data directory, which
+	// is useful for ephemeral nodes, such as those on simnet, and for
+	// embedded use where disk writes are undesirable.
+	MemoryOnly bool
+
+	// MaxAddresses is a hard limit on the total number of known addresses.
+	// When it is exceeded, the least recently added, announced, or
+	// attempted addresses in the new buckets are forgotten.  Addresses in
+	// the tried buckets are never evicted to make room.  A value of zero
+	// or less means there is no limit beyond the bucket capacities.
+	MaxAddresses int
+
+	// RelayTimestampGranularity coarsens the times addresses were last
+	// seen when they are handed out for relay by the address cache so the
+	// precise times, which could be used to fingerprint connections, are
+	// not leaked.  The relayed times are truncated to a multiple of the
+	// granularity and randomly moved back by less than the granularity.
+	// The precise times are still kept internally.  A value of zero or
+	// less disables the rounding.
+	RelayTimestampGranularity time.Duration
+
+	// MaxKnownAddrsPerPeer is the approximate maximum number of addresses
+	// remembered as known to each peer in order to avoid relaying them to
+	// the peer again.
+	MaxKnownAddrsPerPeer int
+}
+
+// now returns the current time according to the configured clock or the system
+// clock when there is none.
+func (cfg *Config) now() time.Time {
+	if cfg.Clock == nil {
+		return time.Now()
+	}
+	return cfg.Clock.Now()
+}
+
+// setDefaults replaces any unset tunable parameters with the package defaults.
+func (cfg *Config) setDefaults() {
+	setDefault := func(val *int, def int) {
+		if *val <= 0 {
+			*val = def
+		}
+	}
+	setDefault(&cfg.TriedBucketSize, triedBucketSize)
+	setDefault(&cfg.TriedBucketCount, triedBucketCount)
+	setDefault(&cfg.NewBucketSize, newBucketSize)
+	setDefault(&cfg.NewBucketCount, newBucketCount)
+	setDefault(&cfg.NumMissingDays, numMissingDays)
+	setDefault(&cfg.NumRetries, numRetries)
+	setDefault(&cfg.MaxFailures, maxFailures)
+	setDefault(&cfg.MinBadDays, minBadDays)
+	if cfg.DumpAddressInterval <= 0 {
+		cfg.DumpAddressInterval = dumpAddressInterval
+	}
+	if cfg.SourceRateLimit == 0 {
+		cfg.SourceRateLimit = sourceRateLimit
+	}
+	setDefault(&cfg.SourceRateBurst, sourceRateBurst)
+	setDefault(&cfg.MaxKnownAddrsPerPeer, maxKnownAddrsPerPeer)
+}
+
+// New returns a new Decred address manager with the default parameters.
 // Use Start to begin processing asynchronous address updates.
 // The address manager uses lookupFunc for necessary DNS lookups.
 func New(dataDir string, lookupFunc func(string) ([]net.IP, error)) *AddrManager {
+	return NewWithConfig(&Config{DataDir: dataDir, LookupFunc: lookupFunc})
+}
+
+// NewWithConfig returns a new Decred address manager with the parameters in
+// the provided config.  Use Start to begin processing asynchronous address
+// updates.
+//
+// Changing the bucket counts or sizes causes the known addresses to be
+// redistributed among the buckets when they are loaded.
+func NewWithConfig(cfg *Config) *AddrManager {
 	am := AddrManager{
-		peersFile:      filepath.Join(dataDir, PeersFilename),
-		lookupFunc:     lookupFunc,
-		rand:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		cfg:            *cfg,
+		peersFile:      filepath.Join(cfg.DataDir, PeersFilename),
+		anchorsFile:    filepath.Join(cfg.DataDir, AnchorsFilename),
+		lookupFunc:     cfg.LookupFunc,
+		rand:           cfg.Rand,
 		quit:           make(chan struct{}),
 		localAddresses: make(map[string]*localAddress),
+		outboundGroups: make(map[string]int),
+		bansFile:       filepath.Join(cfg.DataDir, BansFilename),
+		banned:         make(map[string]time.Time),
+	}
+	am.cfg.setDefaults()
+	if am.rand == nil {
+		am.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
 	}
+	am.sourceLimiter = newSourceLimiter(am.cfg.SourceRateLimit,
+		am.cfg.SourceRateBurst)
+	am.relay = newRelayTracker(am.cfg.MaxKnownAddrsPerPeer)
 	am.reset()
 	return &am
 }
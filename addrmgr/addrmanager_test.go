@@ -6,9 +6,11 @@
 package addrmgr
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/rand"
 	"net"
 	"os"
 	"path/filepath"
@@ -274,6 +276,166 @@ func TestAddLocalAddress(t *testing.T) {
 	}
 }
 
+// TestAddLocalAddressWithExpiry ensures local addresses with a lease are only
+// used until the lease expires and are refreshed via the provided callback.
+func TestAddLocalAddressWithExpiry(t *testing.T) {
+	clock := &testClock{now: time.Unix(1600000000, 0)}
+	n := NewWithConfig(&Config{DataDir: "testaddlocaladdresswithexpiry",
+		Clock: clock})
+	remote := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	leased := wire.NewNetAddressIPPort(net.ParseIP("204.124.1.1"), 9108, 0)
+	refreshed := wire.NewNetAddressIPPort(net.ParseIP("204.124.1.2"), 9108,
+		0)
+	permanent := wire.NewNetAddressIPPort(net.ParseIP("204.124.1.3"), 9108,
+		0)
+
+	// Ensure invalid leases and unroutable addresses are rejected.
+	err := n.AddLocalAddressWithExpiry(leased, UpnpPrio, 0, nil)
+	if err == nil {
+		t.Fatal("lease without a time to live was accepted")
+	}
+	unroutable := wire.NewNetAddressIPPort(net.ParseIP("192.168.0.1"), 9108,
+		0)
+	err = n.AddLocalAddressWithExpiry(unroutable, UpnpPrio, time.Hour, nil)
+	if err == nil {
+		t.Fatal("unroutable address was accepted")
+	}
+
+	// Ensure a leased address is used until its lease expires.
+	err = n.AddLocalAddressWithExpiry(leased, UpnpPrio, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("unable to add leased address: %v", err)
+	}
+	if best := n.GetBestLocalAddress(remote); best != leased {
+		t.Fatalf("unexpected best local address %v", best)
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if n.HasLocalAddress(leased) {
+		t.Fatal("expired local address is still known")
+	}
+	if best := n.GetBestLocalAddress(remote); IsRoutable(best) {
+		t.Fatalf("expired local address %v was returned", best)
+	}
+	n.refreshLocalAddresses()
+	if len(n.localAddresses) != 0 {
+		t.Fatal("expired local address was not removed")
+	}
+
+	// Ensure leases are refreshed once half of the lease has elapsed and
+	// the address expires once refreshing fails and the lease lapses.
+	var refreshes int
+	var refreshErr error
+	refresh := func(na *wire.NetAddress) (time.Duration, error) {
+		refreshes++
+		return time.Hour, refreshErr
+	}
+	err = n.AddLocalAddressWithExpiry(refreshed, UpnpPrio, time.Hour,
+		refresh)
+	if err != nil {
+		t.Fatalf("unable to add refreshed address: %v", err)
+	}
+	clock.now = clock.now.Add(20 * time.Minute)
+	n.refreshLocalAddresses()
+	if refreshes != 0 {
+		t.Fatal("lease refreshed before half of it elapsed")
+	}
+	clock.now = clock.now.Add(20 * time.Minute)
+	n.refreshLocalAddresses()
+	if refreshes != 1 {
+		t.Fatal("lease not refreshed after half of it elapsed")
+	}
+	clock.now = clock.now.Add(50 * time.Minute)
+	if !n.HasLocalAddress(refreshed) {
+		t.Fatal("refreshed local address expired")
+	}
+	refreshErr = errors.New("mapping failed")
+	n.refreshLocalAddresses()
+	if !n.HasLocalAddress(refreshed) {
+		t.Fatal("local address removed before its lease lapsed")
+	}
+	clock.now = clock.now.Add(10 * time.Minute)
+	n.refreshLocalAddresses()
+	if n.HasLocalAddress(refreshed) || len(n.localAddresses) != 0 {
+		t.Fatal("local address not removed after failing to refresh it")
+	}
+
+	// Ensure adding a lease for an address known without one does not make
+	// it expire.
+	if err := n.AddLocalAddress(permanent, ManualPrio); err != nil {
+		t.Fatalf("unable to add permanent address: %v", err)
+	}
+	err = n.AddLocalAddressWithExpiry(permanent, UpnpPrio, time.Hour, nil)
+	if err != nil {
+		t.Fatalf("unable to add lease for permanent address: %v", err)
+	}
+	clock.now = clock.now.Add(2 * time.Hour)
+	n.refreshLocalAddresses()
+	if !n.HasLocalAddress(permanent) {
+		t.Fatal("permanent local address expired")
+	}
+}
+
+// TestDialPolicy ensures clearnet local addresses are neither advertised nor
+// considered external address candidates when clearnet connections are made
+// through a proxy.
+func TestDialPolicy(t *testing.T) {
+	n := New("testdialpolicy", nil)
+	remote := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	onionRemote := wire.NewNetAddressIPPort(net.ParseIP("fd87:d87e:eb43::1"),
+		9108, 0)
+	bound := wire.NewNetAddressIPPort(net.ParseIP("204.124.1.1"), 9108, 0)
+	manual := wire.NewNetAddressIPPort(net.ParseIP("204.124.1.2"), 9108, 0)
+	suggested := wire.NewNetAddressIPPort(net.ParseIP("204.124.1.3"), 9108,
+		0)
+	onion := wire.NewNetAddressIPPort(net.ParseIP("fd87:d87e:eb43::2"), 9108,
+		0)
+
+	// Ensure interface addresses are advertised and suggested addresses
+	// are candidates without a proxy, except when suggested by onion peers.
+	if err := n.AddLocalAddress(bound, BoundPrio); err != nil {
+		t.Fatalf("unable to add local address: %v", err)
+	}
+	if best := n.GetBestLocalAddress(remote); best != bound {
+		t.Fatalf("unexpected best local address %v", best)
+	}
+	if !n.IsExternalAddrCandidate(suggested, remote) {
+		t.Fatal("suggested address is not a candidate")
+	}
+	if n.IsExternalAddrCandidate(suggested, onionRemote) {
+		t.Fatal("address suggested by onion peer is a candidate")
+	}
+
+	// Ensure interface addresses are no longer advertised and suggested
+	// clearnet addresses are not candidates with a clearnet proxy.
+	n.SetDialPolicy(NetworkReachabilityPolicy{ClearnetProxied: true})
+	if best := n.GetBestLocalAddress(remote); IsRoutable(best) {
+		t.Fatalf("interface address %v advertised through proxy", best)
+	}
+	if n.IsExternalAddrCandidate(suggested, remote) {
+		t.Fatal("suggested address is a candidate through proxy")
+	}
+	added := n.DiscoverStunAddresses(context.Background(),
+		[]string{"127.0.0.1:1"}, 9108, 0)
+	if len(added) != 0 {
+		t.Fatalf("STUN servers queried through proxy: %v", added)
+	}
+
+	// Ensure explicitly provided clearnet addresses and onion addresses are
+	// still advertised.
+	if err := n.AddLocalAddress(manual, ManualPrio); err != nil {
+		t.Fatalf("unable to add local address: %v", err)
+	}
+	if best := n.GetBestLocalAddress(remote); best != manual {
+		t.Fatalf("unexpected best local address %v", best)
+	}
+	if err := n.AddLocalAddress(onion, ManualPrio); err != nil {
+		t.Fatalf("unable to add local address: %v", err)
+	}
+	if best := n.GetBestLocalAddress(onionRemote); best != onion {
+		t.Fatalf("unexpected best local address %v", best)
+	}
+}
+
 func TestAttempt(t *testing.T) {
 	n := New("testattempt", lookupFunc)
 
@@ -296,6 +458,63 @@ func TestAttempt(t *testing.T) {
 	}
 }
 
+func TestServedPoorly(t *testing.T) {
+	n := New("testservedpoorly", lookupFunc)
+
+	// Add a new address and get it
+	err := n.addAddressByIP(someIP + ":8333")
+	if err != nil {
+		t.Fatalf("Adding address failed: %v", err)
+	}
+	ka := n.GetAddress()
+	chance := ka.chance(time.Now())
+
+	// Ensure poor service reduces the chance of selecting the address and is
+	// limited to the maximum.
+	na := ka.NetAddress()
+	for i := 0; i < maxPoorService+2; i++ {
+		n.ServedPoorly(na)
+	}
+	if ka.poorService != maxPoorService {
+		t.Errorf("Unexpected poor service count: got %d, want %d",
+			ka.poorService, maxPoorService)
+	}
+	if ka.chance(time.Now()) >= chance {
+		t.Errorf("Poor service did not reduce chance: got %v, was %v",
+			ka.chance(time.Now()), chance)
+	}
+
+	// Ensure unknown addresses are ignored.
+	unknown := wire.NewNetAddressIPPort(net.ParseIP("173.194.115.67"), 8333, 0)
+	n.ServedPoorly(unknown)
+}
+
+func TestSetUserAgent(t *testing.T) {
+	n := New("testsetuseragent", lookupFunc)
+
+	// Add a new address and get it
+	err := n.addAddressByIP(someIP + ":8333")
+	if err != nil {
+		t.Fatalf("Adding address failed: %v", err)
+	}
+	ka := n.GetAddress()
+	if ka.UserAgent() != "" || ka.ProtocolVersion() != 0 {
+		t.Fatalf("Address should not have a user agent, but does")
+	}
+
+	// Ensure the user agent and protocol version are updated.
+	const userAgent = "/dcrwire:0.4.0/dcrd:1.6.0/"
+	n.SetUserAgent(ka.NetAddress(), userAgent, 8)
+	if ka.UserAgent() != userAgent || ka.ProtocolVersion() != 8 {
+		t.Errorf("Unexpected user agent: got %q (%d), want %q (%d)",
+			ka.UserAgent(), ka.ProtocolVersion(), userAgent, 8)
+	}
+
+	// Ensure unknown addresses are ignored.
+	unknown := wire.NewNetAddressIPPort(net.ParseIP("173.194.115.67"), 8333, 0)
+	n.SetUserAgent(unknown, userAgent, 8)
+}
+
 func TestConnected(t *testing.T) {
 	n := New("testconnected", lookupFunc)
 
@@ -558,3 +777,1057 @@ func TestCorruptPeersFile(t *testing.T) {
 		t.Fatalf("Corrupt peers file has not been removed: %s", peersFile)
 	}
 }
+
+// TestPeersFileBackup ensures the previously saved peers file is rotated to a
+// backup and the addresses are loaded from the backup when the peers file is
+// malformed or missing.
+func TestPeersFileBackup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testpeersfilebackup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	peersFile := filepath.Join(dir, PeersFilename)
+	backupFile := peersFile + peersBackupSuffix
+
+	// Save the peers file twice so the first one is rotated to the backup.
+	n := New(dir, nil)
+	for _, ip := range []string{"12.1.2.3", "13.1.2.3"} {
+		if err := n.addAddressByIP(ip + ":9108"); err != nil {
+			t.Fatalf("Adding address failed: %v", err)
+		}
+	}
+	n.savePeers()
+	if _, err := os.Stat(backupFile); !os.IsNotExist(err) {
+		t.Fatalf("unexpected backup file after first save: %v", err)
+	}
+	if err := n.addAddressByIP("14.1.2.3:9108"); err != nil {
+		t.Fatalf("Adding address failed: %v", err)
+	}
+	n.savePeers()
+	if _, err := os.Stat(backupFile); err != nil {
+		t.Fatalf("backup file was not created: %v", err)
+	}
+
+	// Ensure the addresses are loaded from the peers file when it is valid.
+	n = New(dir, nil)
+	n.loadPeers()
+	if n.numAddresses() != 3 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 3",
+			n.numAddresses())
+	}
+
+	// Ensure the addresses are loaded from the backup when the peers file is
+	// corrupt.
+	if err := ioutil.WriteFile(peersFile, []byte("{"), 0600); err != nil {
+		t.Fatalf("unable to write peers file: %v", err)
+	}
+	n = New(dir, nil)
+	n.loadPeers()
+	if n.numAddresses() != 2 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 2",
+			n.numAddresses())
+	}
+	if _, err := os.Stat(peersFile); !os.IsNotExist(err) {
+		t.Fatalf("corrupt peers file was not removed: %v", err)
+	}
+
+	// Ensure the addresses are loaded from the backup when the peers file is
+	// missing and the recovered addresses are saved.
+	n = New(dir, nil)
+	n.loadPeers()
+	if n.numAddresses() != 2 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 2",
+			n.numAddresses())
+	}
+	n.savePeers()
+	if _, err := os.Stat(peersFile); err != nil {
+		t.Fatalf("recovered peers file was not saved: %v", err)
+	}
+}
+
+// TestNewWithConfig ensures the tunable parameters of the address manager are
+// applied and known addresses are redistributed when they are loaded with a
+// different bucket layout than they were saved with.
+func TestNewWithConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testnewwithconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := NewWithConfig(&Config{
+		DataDir:          dir,
+		TriedBucketCount: 4,
+		NewBucketCount:   16,
+		NewBucketSize:    8,
+		NumRetries:       1,
+	})
+	if len(n.addrNew) != 16 || len(n.addrTried) != 4 {
+		t.Fatalf("unexpected bucket counts -- got %d new %d tried, want "+
+			"16 new 4 tried", len(n.addrNew), len(n.addrTried))
+	}
+	if n.cfg.TriedBucketSize != triedBucketSize ||
+		n.cfg.DumpAddressInterval != dumpAddressInterval {
+
+		t.Fatalf("unset parameters do not use the defaults: %+v", n.cfg)
+	}
+
+	// Ensure the configured retry limit is used to determine bad addresses.
+	na := &wire.NetAddress{Timestamp: time.Now()}
+	ka := newKnownAddress(na, 1, time.Now().Add(-time.Hour), time.Time{},
+		false, 0)
+	if !ka.isBad(&n.cfg) {
+		t.Fatal("address with too many retries is not bad")
+	}
+
+	// Ensure the new buckets never exceed the configured size.
+	addTestAddresses(n, 500)
+	for i, bucket := range n.addrNew {
+		if len(bucket) > n.cfg.NewBucketSize+1 {
+			t.Fatalf("new bucket %d has %d addresses", i, len(bucket))
+		}
+	}
+	n.savePeers()
+
+	// Ensure the addresses are redistributed over the buckets of the
+	// default layout when loaded.
+	loaded := New(dir, nil)
+	loaded.loadPeers()
+	if loaded.numAddresses() != n.numAddresses() ||
+		loaded.nTried != n.nTried {
+
+		t.Fatalf("unexpected addresses -- got %d (%d tried), want %d (%d "+
+			"tried)", loaded.numAddresses(), loaded.nTried,
+			n.numAddresses(), n.nTried)
+	}
+	for key, ka := range loaded.addrIndex {
+		if ka.tried {
+			continue
+		}
+		bucket := loaded.getNewBucket(ka.na, ka.srcAddr)
+		if _, ok := loaded.addrNew[bucket][key]; !ok {
+			t.Fatalf("address %s is not in its new bucket %d", key,
+				bucket)
+		}
+	}
+}
+
+// TestStartContext ensures the address handler exits and saves the known
+// addresses when the context the address manager was started with is canceled.
+func TestStartContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teststartcontext")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := New(dir, nil)
+	n.StartContext(ctx)
+	if err := n.addAddressByIP("12.1.2.3:9108"); err != nil {
+		t.Fatalf("Adding address failed: %v", err)
+	}
+
+	// Ensure the address handler exits once the context is canceled.
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		n.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("address handler did not exit after context cancel")
+	}
+
+	// Ensure the known addresses were saved and stopping afterwards still
+	// works as expected.
+	if err := n.Stop(); err != nil {
+		t.Fatalf("Stop: unexpected error: %v", err)
+	}
+	loaded := New(dir, nil)
+	loaded.loadPeers()
+	if loaded.numAddresses() != 1 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 1",
+			loaded.numAddresses())
+	}
+}
+
+// TestGetAddresses ensures batches of addresses are selected from distinct
+// address groups.
+func TestGetAddresses(t *testing.T) {
+	n := New("testgetaddresses", lookupFunc)
+
+	// Ensure nothing is returned when there are no addresses.
+	if addrs := n.GetAddresses(8); len(addrs) != 0 {
+		t.Fatalf("unexpected addresses %v", addrs)
+	}
+
+	// Add several addresses in each of a few groups.
+	for _, group := range []string{"12.1", "13.1", "14.1"} {
+		for i := 0; i < 5; i++ {
+			addr := fmt.Sprintf("%s.%d.1:9108", group, i)
+			if err := n.addAddressByIP(addr); err != nil {
+				t.Fatalf("Adding address failed: %v", err)
+			}
+		}
+	}
+
+	// Ensure only one address per group is returned when more addresses
+	// than there are groups are requested.
+	addrs := n.GetAddresses(8)
+	if len(addrs) != 3 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 3",
+			len(addrs))
+	}
+	groups := make(map[string]struct{})
+	for _, ka := range addrs {
+		group := n.GroupKey(ka.NetAddress())
+		if _, ok := groups[group]; ok {
+			t.Fatalf("multiple addresses returned from group %s", group)
+		}
+		groups[group] = struct{}{}
+	}
+
+	// Ensure no more than the requested number of addresses are returned.
+	if addrs := n.GetAddresses(2); len(addrs) != 2 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 2",
+			len(addrs))
+	}
+	if addrs := n.GetAddresses(0); len(addrs) != 0 {
+		t.Fatalf("unexpected addresses %v", addrs)
+	}
+}
+
+// TestServicesFiltering ensures address selection and the address cache can be
+// limited to addresses that advertise the required services.
+func TestServicesFiltering(t *testing.T) {
+	n := New("testservicesfiltering", lookupFunc)
+
+	// Ensure nothing is returned when there are no addresses.
+	if ka := n.GetAddressWithServices(wire.SFNodeCF); ka != nil {
+		t.Fatalf("unexpected address %v", ka.NetAddress())
+	}
+
+	// Add addresses with and without the committed filters service and
+	// mark them good so they are included in the address cache.
+	full := wire.SFNodeNetwork
+	cf := wire.SFNodeNetwork | wire.SFNodeCF
+	addrs := []*wire.NetAddress{
+		wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, full),
+		wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, full),
+		wire.NewNetAddressIPPort(net.ParseIP("14.1.2.3"), 9108, cf),
+	}
+	n.AddAddresses(addrs, addrs[0])
+	for _, na := range addrs {
+		n.Good(na)
+	}
+
+	for i := 0; i < 20; i++ {
+		ka := n.GetAddressWithServices(wire.SFNodeCF)
+		if ka == nil {
+			t.Fatal("no address with the required services returned")
+		}
+		if ka.NetAddress().Services&wire.SFNodeCF == 0 {
+			t.Fatalf("address %v lacks the required services",
+				ka.NetAddress())
+		}
+	}
+	if ka := n.GetAddressWithServices(wire.SFNodeBloom); ka != nil {
+		t.Fatalf("unexpected address %v", ka.NetAddress())
+	}
+
+	// Ensure the address cache only contains addresses with the required
+	// services.  The cache is a percentage of the known addresses, so add
+	// enough for it to contain some.
+	for i := 0; i < 100; i++ {
+		na := wire.NewNetAddressIPPort(net.IPv4(20, byte(i), 1, 1), 9108,
+			cf)
+		n.AddAddress(na, na)
+		n.Good(na)
+	}
+	cache := n.AddressCacheWithServices(wire.SFNodeCF)
+	if len(cache) == 0 {
+		t.Fatal("empty address cache")
+	}
+	for _, na := range cache {
+		if na.Services&wire.SFNodeCF == 0 {
+			t.Fatalf("cached address %v lacks the required services", na)
+		}
+	}
+}
+
+// TestNetworkFiltering ensures address selection and the address cache can be
+// limited to addresses on the requested types of networks.
+func TestNetworkFiltering(t *testing.T) {
+	n := New("testnetworkfiltering", lookupFunc)
+
+	// Add addresses on each network and mark them good so they are included
+	// in the address cache.  The cache is a percentage of the known
+	// addresses, so add several of each.
+	var addrs []*wire.NetAddress
+	for i := 0; i < 20; i++ {
+		addrs = append(addrs,
+			wire.NewNetAddressIPPort(net.IPv4(20, byte(i), 1, 1), 9108,
+				0),
+			wire.NewNetAddressIPPort(net.ParseIP(fmt.Sprintf(
+				"2602:%x::1", 0x100+i)), 9108, 0),
+			wire.NewNetAddressIPPort(net.ParseIP(fmt.Sprintf(
+				"fd87:d87e:eb43:%x::1", i)), 9108, 0))
+	}
+	for _, na := range addrs {
+		n.AddAddress(na, na)
+		n.Good(na)
+	}
+
+	tests := []struct {
+		networks []NetworkAddress
+	}{
+		{[]NetworkAddress{OnionAddress}},
+		{[]NetworkAddress{IPv4Address}},
+		{[]NetworkAddress{IPv6Address}},
+		{[]NetworkAddress{IPv4Address, IPv6Address}},
+	}
+	for _, test := range tests {
+		onNetworks := func(na *wire.NetAddress) bool {
+			for _, network := range test.networks {
+				if getNetwork(na) == network {
+					return true
+				}
+			}
+			return false
+		}
+		for i := 0; i < 20; i++ {
+			ka := n.GetAddressOnNetworks(test.networks...)
+			if ka == nil {
+				t.Fatalf("%v: no address returned", test.networks)
+			}
+			if !onNetworks(ka.NetAddress()) {
+				t.Fatalf("%v: address %v is on another network",
+					test.networks, ka.NetAddress())
+			}
+		}
+		cache := n.AddressCacheOnNetworks(test.networks...)
+		if len(cache) == 0 {
+			t.Fatalf("%v: empty address cache", test.networks)
+		}
+		for _, na := range cache {
+			if !onNetworks(na) {
+				t.Fatalf("%v: cached address %v is on another network",
+					test.networks, na)
+			}
+		}
+	}
+
+	// Ensure nothing is returned for networks without known addresses.
+	if ka := n.GetAddressOnNetworks(CJDNSAddress); ka != nil {
+		t.Fatalf("unexpected address %v", ka.NetAddress())
+	}
+}
+
+// TestOnionOnly ensures an address manager in onion-only mode neither stores
+// nor relays addresses on other networks and discards any that were previously
+// saved.
+func TestOnionOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testoniononly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Save addresses on every network with a regular address manager twice
+	// so they are also in the backup.
+	addrs := []string{"12.1.2.3:9108", "[2602:100::1]:9108",
+		"[fd87:d87e:eb43::1]:9108"}
+	n := New(dir, nil)
+	for _, addr := range addrs {
+		if err := n.addAddressByIP(addr); err != nil {
+			t.Fatalf("Adding address failed: %v", err)
+		}
+	}
+	n.savePeers()
+	n.addrChanged = true
+	n.savePeers()
+
+	// Ensure only the onion address is loaded in onion-only mode.
+	n = NewWithConfig(&Config{DataDir: dir, OnionOnly: true})
+	n.loadPeers()
+	if n.numAddresses() != 1 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 1",
+			n.numAddresses())
+	}
+
+	// Ensure addresses on other networks are not stored.
+	for _, addr := range []string{"13.1.2.3:9108", "[2602:101::1]:9108"} {
+		if err := n.addAddressByIP(addr); err != nil {
+			t.Fatalf("Adding address failed: %v", err)
+		}
+	}
+	if err := n.addAddressByIP("[fd87:d87e:eb43::2]:9108"); err != nil {
+		t.Fatalf("Adding address failed: %v", err)
+	}
+	if n.numAddresses() != 2 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 2",
+			n.numAddresses())
+	}
+
+	// Ensure anchors on other networks are not saved.
+	n.SetAnchors([]*wire.NetAddress{
+		wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0),
+	})
+	if len(n.newAnchors) != 0 {
+		t.Fatalf("unexpected anchors %v", n.newAnchors)
+	}
+
+	// Ensure the discarded addresses are no longer persisted in either the
+	// peers file or its backup once saved.
+	n.savePeers()
+	backupFile := n.peersFile + peersBackupSuffix
+	if _, err := os.Stat(backupFile); !os.IsNotExist(err) {
+		t.Fatalf("backup file with discarded addresses was not removed: "+
+			"%v", err)
+	}
+	n = New(dir, nil)
+	n.loadPeers()
+	if n.numAddresses() != 2 {
+		t.Fatalf("unexpected number of saved addresses -- got %d, want 2",
+			n.numAddresses())
+	}
+	for _, ka := range n.addrIndex {
+		if !isOnionCatTor(ka.na) {
+			t.Fatalf("non-onion address %v was saved", ka.na)
+		}
+	}
+}
+
+// TestGetFeelerAddress ensures feeler addresses are selected from the new
+// buckets, prefer never attempted addresses, and that feeler attempts are
+// tracked separately from normal attempts.
+func TestGetFeelerAddress(t *testing.T) {
+	n := New("testgetfeeleraddress", lookupFunc)
+	if ka := n.GetFeelerAddress(); ka != nil {
+		t.Fatalf("unexpected feeler address %v", ka.NetAddress())
+	}
+
+	// Add a tried address, an attempted new address, and a never attempted
+	// new address.
+	tried := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	attempted := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	untried := wire.NewNetAddressIPPort(net.ParseIP("14.1.2.3"), 9108, 0)
+	n.AddAddresses([]*wire.NetAddress{tried, attempted, untried}, tried)
+	n.Good(tried)
+	n.Attempt(attempted)
+
+	// Ensure the never attempted address is preferred.
+	for i := 0; i < 20; i++ {
+		ka := n.GetFeelerAddress()
+		if ka == nil || NetAddressKey(ka.NetAddress()) != "14.1.2.3:9108" {
+			t.Fatalf("unexpected feeler address %v", ka)
+		}
+	}
+
+	// Ensure the feeler attempt is recorded separately from normal attempts
+	// and the attempted addresses are no longer returned until the retry
+	// interval elapses.
+	n.FeelerAttempt(untried)
+	ka := n.find(untried)
+	if ka.LastFeelerAttempt().IsZero() || ka.attempts != 0 ||
+		!ka.LastAttempt().IsZero() {
+
+		t.Fatalf("unexpected attempts after feeler attempt -- got %d "+
+			"attempts, last feeler %v, last attempt %v", ka.attempts,
+			ka.LastFeelerAttempt(), ka.LastAttempt())
+	}
+	n.FeelerAttempt(attempted)
+	if ka := n.GetFeelerAddress(); ka != nil {
+		t.Fatalf("unexpected feeler address %v", ka.NetAddress())
+	}
+	ka.lastfeeler = time.Now().Add(-feelerRetryInterval)
+	if ka := n.GetFeelerAddress(); ka == nil ||
+		NetAddressKey(ka.NetAddress()) != "14.1.2.3:9108" {
+
+		t.Fatalf("unexpected feeler address %v", ka)
+	}
+	if m := n.Metrics(); m.FeelerAttempts != 2 || m.Attempts != 1 {
+		t.Fatalf("unexpected attempt counters -- got %d feeler attempts "+
+			"and %d attempts, want 2 and 1", m.FeelerAttempts, m.Attempts)
+	}
+}
+
+// TestDeleteAddress ensures deleted addresses are removed from the address
+// index along with the new and tried buckets.
+func TestDeleteAddress(t *testing.T) {
+	n := New("testdeleteaddress", lookupFunc)
+	newAddr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	triedAddr := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	n.AddAddresses([]*wire.NetAddress{newAddr, triedAddr}, newAddr)
+	n.Good(triedAddr)
+
+	// Ensure deleting an unknown address does nothing.
+	unknown := wire.NewNetAddressIPPort(net.ParseIP("14.1.2.3"), 9108, 0)
+	if n.DeleteAddress(unknown) {
+		t.Fatal("unknown address was deleted")
+	}
+
+	for _, addr := range []*wire.NetAddress{newAddr, triedAddr} {
+		if !n.DeleteAddress(addr) {
+			t.Fatalf("address %v was not deleted", addr)
+		}
+		if n.find(addr) != nil {
+			t.Fatalf("address %v is still known", addr)
+		}
+	}
+	if n.numAddresses() != 0 || n.nNew != 0 || n.nTried != 0 {
+		t.Fatalf("unexpected number of addresses -- got %d new, %d tried",
+			n.nNew, n.nTried)
+	}
+	for i, bucket := range n.addrNew {
+		if len(bucket) != 0 {
+			t.Fatalf("new bucket %d is not empty", i)
+		}
+	}
+	for i, bucket := range n.addrTried {
+		if len(bucket) != 0 {
+			t.Fatalf("tried bucket %d is not empty", i)
+		}
+	}
+
+	// Ensure a deleted address may be added again.
+	n.AddAddress(newAddr, newAddr)
+	if n.find(newAddr) == nil {
+		t.Fatal("deleted address was not added again")
+	}
+}
+
+// TestLookup ensures known addresses are reported and their snapshots reflect
+// the tracked information without aliasing it.
+func TestLookup(t *testing.T) {
+	n := New("testlookup", lookupFunc)
+	addr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108,
+		wire.SFNodeNetwork)
+	src := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	unknown := wire.NewNetAddressIPPort(net.ParseIP("14.1.2.3"), 9108, 0)
+
+	if n.HasAddress(unknown) {
+		t.Fatal("unknown address is reported as known")
+	}
+	if snap, ok := n.Lookup(unknown); ok || snap != nil {
+		t.Fatalf("unexpected snapshot for unknown address %v", snap)
+	}
+
+	n.AddAddress(addr, src)
+	n.Attempt(addr)
+	n.Attempt(addr)
+	if !n.HasAddress(addr) {
+		t.Fatal("known address is not reported as known")
+	}
+	snap, ok := n.Lookup(addr)
+	if !ok {
+		t.Fatal("known address was not found")
+	}
+	if NetAddressKey(&snap.Addr) != "12.1.2.3:9108" ||
+		NetAddressKey(&snap.Source) != "13.1.2.3:9108" ||
+		snap.Addr.Services != wire.SFNodeNetwork {
+
+		t.Fatalf("unexpected addresses in snapshot %v and %v", snap.Addr,
+			snap.Source)
+	}
+	if snap.Attempts != 2 || snap.LastAttempt.IsZero() ||
+		!snap.LastSuccess.IsZero() || snap.Tried || snap.NewBucketRefs != 1 {
+
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+
+	// Ensure the snapshot is not affected by later changes.
+	n.Good(addr)
+	if snap.Tried || !snap.LastSuccess.IsZero() {
+		t.Fatalf("snapshot changed after address was marked good: %+v",
+			snap)
+	}
+	if snap, _ := n.Lookup(addr); !snap.Tried || snap.Attempts != 0 ||
+		snap.LastSuccess.IsZero() {
+
+		t.Fatalf("unexpected snapshot after address was marked good: %+v",
+			snap)
+	}
+}
+
+// TestCachedKeys ensures the cached key and groups of known addresses match
+// those computed from the addresses and that the groups are recomputed when
+// the asmap changes.
+func TestCachedKeys(t *testing.T) {
+	n := New("testcachedkeys", nil)
+	addr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	src := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	n.AddAddress(addr, src)
+
+	ka := n.find(addr)
+	if ka == nil {
+		t.Fatal("address not found")
+	}
+	if got, want := ka.key(), NetAddressKey(addr); got != want {
+		t.Fatalf("unexpected key -- got %q, want %q", got, want)
+	}
+	if got, want := n.groupOf(ka), GroupKey(addr); got != want {
+		t.Fatalf("unexpected group -- got %q, want %q", got, want)
+	}
+	if got, want := n.srcGroupOf(ka), GroupKey(src); got != want {
+		t.Fatalf("unexpected source group -- got %q, want %q", got, want)
+	}
+	if got, want := n.knownNewBucket(ka), n.getNewBucket(addr, src); got != want {
+		t.Fatalf("unexpected new bucket -- got %d, want %d", got, want)
+	}
+	if got, want := n.knownTriedBucket(ka), n.getTriedBucket(addr); got != want {
+		t.Fatalf("unexpected tried bucket -- got %d, want %d", got, want)
+	}
+
+	// Ensure the groups are recomputed when the asmap changes.
+	asmap, err := DecodeASMap(testASMap())
+	if err != nil {
+		t.Fatalf("DecodeASMap: unexpected error: %v", err)
+	}
+	n.SetASMap(asmap)
+	if got, want := n.groupOf(ka), "as:100"; got != want {
+		t.Fatalf("unexpected group after asmap change -- got %q, want %q",
+			got, want)
+	}
+	if got, want := n.srcGroupOf(ka), "as:100"; got != want {
+		t.Fatalf("unexpected source group after asmap change -- got %q, "+
+			"want %q", got, want)
+	}
+}
+
+// TestDeterministicRand ensures address managers with identically seeded
+// sources of randomness place and select addresses identically.
+func TestDeterministicRand(t *testing.T) {
+	addrs := make([]*wire.NetAddress, 0, 100)
+	for i := 0; i < cap(addrs); i++ {
+		ip := net.IPv4(byte(i)+12, byte(i), 1, 1)
+		addrs = append(addrs, wire.NewNetAddressIPPort(ip, 9108, 0))
+	}
+	src := wire.NewNetAddressIPPort(net.ParseIP("173.144.173.111"), 9108, 0)
+
+	newManager := func(seed int64) *AddrManager {
+		n := NewWithConfig(&Config{
+			DataDir: "testdeterministicrand",
+			Rand:    rand.New(rand.NewSource(seed)),
+		})
+		n.AddAddresses(addrs, src)
+		for _, addr := range addrs[:20] {
+			n.Good(addr)
+		}
+		return n
+	}
+	n1, n2 := newManager(1), newManager(1)
+	if n1.key != n2.key {
+		t.Fatal("bucket keys differ for identically seeded managers")
+	}
+	if !reflect.DeepEqual(n1.Snapshot(false), n2.Snapshot(false)) {
+		t.Fatal("bucket occupancy differs for identically seeded managers")
+	}
+	for i := 0; i < 50; i++ {
+		ka1, ka2 := n1.GetAddress(), n2.GetAddress()
+		if NetAddressKey(ka1.na) != NetAddressKey(ka2.na) {
+			t.Fatalf("selection %d differs -- got %s and %s", i,
+				NetAddressKey(ka1.na), NetAddressKey(ka2.na))
+		}
+	}
+
+	// Ensure differently seeded managers use different bucket keys.
+	if n3 := newManager(2); n3.key == n1.key {
+		t.Fatal("bucket keys match for differently seeded managers")
+	}
+}
+
+// TestRecordConnectionStats ensures connection statistics are tracked and that
+// they affect the chance of selecting an address as expected.
+func TestRecordConnectionStats(t *testing.T) {
+	n := New("testrecordconnectionstats", nil)
+	addr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	n.AddAddress(addr, addr)
+	ka := n.find(addr)
+	now := time.Now().Add(time.Hour)
+	chance := ka.chance(now)
+
+	// Ensure successful connections are tracked and a fully reliable
+	// address is favoured.
+	n.Attempt(addr)
+	n.Good(addr)
+	if ka.totalAttempts != 1 || ka.totalSuccesses != 1 {
+		t.Fatalf("unexpected connection counts -- got %d attempts %d "+
+			"successes, want 1 attempt 1 success", ka.totalAttempts,
+			ka.totalSuccesses)
+	}
+	reliableChance := ka.chance(now)
+	if reliableChance <= chance {
+		t.Fatalf("reliability did not increase chance: got %v, was %v",
+			reliableChance, chance)
+	}
+
+	// Ensure unsuccessful attempts reduce the chance.
+	n.Attempt(addr)
+	n.Attempt(addr)
+	if got := ka.chance(now); got >= reliableChance {
+		t.Fatalf("failed attempts did not reduce chance: got %v, was %v",
+			got, reliableChance)
+	}
+	n.Good(addr)
+
+	// Ensure the latency is a rolling average and reduces the chance.
+	chance = ka.chance(now)
+	n.RecordConnectionStats(addr, 800*time.Millisecond, 0)
+	if ka.latency != 800*time.Millisecond {
+		t.Fatalf("unexpected latency %v", ka.latency)
+	}
+	n.RecordConnectionStats(addr, 0, 0)
+	n.RecordConnectionStats(addr, 1600*time.Millisecond, 0)
+	if ka.latency != 900*time.Millisecond {
+		t.Fatalf("unexpected average latency -- got %v, want %v",
+			ka.latency, 900*time.Millisecond)
+	}
+	latencyChance := ka.chance(now)
+	if latencyChance >= chance {
+		t.Fatalf("latency did not reduce chance: got %v, was %v",
+			latencyChance, chance)
+	}
+
+	// Ensure uptime accumulates and increases the chance.
+	n.RecordConnectionStats(addr, 0, time.Hour)
+	n.RecordConnectionStats(addr, 0, 2*time.Hour)
+	if ka.uptime != 3*time.Hour {
+		t.Fatalf("unexpected uptime -- got %v, want %v", ka.uptime,
+			3*time.Hour)
+	}
+	if got := ka.chance(now); got <= latencyChance {
+		t.Fatalf("uptime did not increase chance: got %v, was %v", got,
+			latencyChance)
+	}
+
+	// Ensure unknown addresses are ignored.
+	unknown := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	n.RecordConnectionStats(unknown, time.Second, time.Hour)
+}
+
+// TestSetLastBlock ensures the last advertised block height of addresses is
+// tracked and that peers that were far behind are less likely to be selected.
+func TestSetLastBlock(t *testing.T) {
+	n := New("testsetlastblock", nil)
+	addr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	n.AddAddress(addr, addr)
+	ka := n.find(addr)
+	now := time.Now().Add(time.Hour)
+	chance := ka.chance(now)
+
+	// Ensure a peer that is slightly behind or ahead is not deprioritised.
+	n.SetLastBlock(addr, 1000, 1000+farBehindBlocks)
+	if ka.lastBlock != 1000 || ka.blocksBehind != farBehindBlocks {
+		t.Fatalf("unexpected last block %d and blocks behind %d",
+			ka.lastBlock, ka.blocksBehind)
+	}
+	if got := ka.chance(now); got != chance {
+		t.Fatalf("unexpected chance -- got %v, want %v", got, chance)
+	}
+	n.SetLastBlock(addr, 1010, 1000)
+	if ka.blocksBehind != 0 {
+		t.Fatalf("unexpected blocks behind %d", ka.blocksBehind)
+	}
+
+	// Ensure a peer that is far behind is deprioritised.
+	n.SetLastBlock(addr, 1000, 1001+farBehindBlocks)
+	if got := ka.chance(now); got >= chance {
+		t.Fatalf("being far behind did not reduce chance: got %v, was %v",
+			got, chance)
+	}
+	if s, _ := n.Lookup(addr); s.LastBlock != 1000 {
+		t.Fatalf("unexpected last block in snapshot %d", s.LastBlock)
+	}
+
+	// Ensure unknown addresses are ignored.
+	unknown := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	n.SetLastBlock(unknown, 1000, 2000)
+}
+
+// TestSetServices ensures services are replaced or merged as requested, both
+// individually and in batches.
+func TestSetServices(t *testing.T) {
+	n := New("testsetservices", nil)
+	addr1 := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108,
+		wire.SFNodeNetwork)
+	addr2 := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108,
+		wire.SFNodeNetwork)
+	n.AddAddresses([]*wire.NetAddress{addr1, addr2}, addr1)
+	services := func(addr *wire.NetAddress) wire.ServiceFlag {
+		return n.find(addr).NetAddress().Services
+	}
+
+	n.AddServices(addr1, wire.SFNodeBloom)
+	if got, want := services(addr1), wire.SFNodeNetwork|wire.SFNodeBloom; got != want {
+		t.Fatalf("unexpected merged services -- got %v, want %v", got, want)
+	}
+	n.SetServices(addr1, wire.SFNodeCF)
+	if got, want := services(addr1), wire.SFNodeCF; got != want {
+		t.Fatalf("unexpected services -- got %v, want %v", got, want)
+	}
+
+	unknown := wire.NewNetAddressIPPort(net.ParseIP("14.1.2.3"), 9108, 0)
+	updates := []ServicesUpdate{
+		{Addr: addr1, Services: wire.SFNodeBloom},
+		{Addr: addr2, Services: wire.SFNodeCF},
+		{Addr: unknown, Services: wire.SFNodeCF},
+	}
+	n.SetServicesBatch(updates, true)
+	if got, want := services(addr1), wire.SFNodeCF|wire.SFNodeBloom; got != want {
+		t.Fatalf("unexpected batch merged services -- got %v, want %v",
+			got, want)
+	}
+	if got, want := services(addr2), wire.SFNodeNetwork|wire.SFNodeCF; got != want {
+		t.Fatalf("unexpected batch merged services -- got %v, want %v",
+			got, want)
+	}
+	n.SetServicesBatch(updates, false)
+	if got, want := services(addr1), wire.SFNodeBloom; got != want {
+		t.Fatalf("unexpected batch services -- got %v, want %v", got, want)
+	}
+	if got, want := services(addr2), wire.SFNodeCF; got != want {
+		t.Fatalf("unexpected batch services -- got %v, want %v", got, want)
+	}
+	if n.HasAddress(unknown) {
+		t.Fatal("unknown address was added")
+	}
+}
+
+// TestHostToNetAddress ensures bare IP addresses, onion addresses, and names
+// are converted to network addresses as expected.
+func TestHostToNetAddress(t *testing.T) {
+	lookup := func(host string) ([]net.IP, error) {
+		switch host {
+		case "seed.example.com":
+			return []net.IP{net.ParseIP("12.1.2.3"), net.ParseIP("13.1.2.3")}, nil
+		case "empty.example.com":
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unknown host %s", host)
+	}
+
+	tests := []struct {
+		name   string
+		host   string
+		lookup func(string) ([]net.IP, error)
+		want   string
+		err    bool
+	}{
+		{"ipv4", "12.1.2.3", nil, "12.1.2.3", false},
+		{"ipv6", "2602:100::1", nil, "2602:100::1", false},
+		{"onion", "aaaaaaaaaaaaaaab.onion", nil, "fd87:d87e:eb43::1", false},
+		{"onion upper", "AAAAAAAAAAAAAAAB.ONION", nil, "fd87:d87e:eb43::1", false},
+		{"onion invalid", "aaaaaaaaaaaaaaa1.onion", nil, "", true},
+		{"onion v3", "vww6ybal4bd7szmgncyruucpgfkqahzddi37ktceo3ah7ngmcopnpyyd.onion",
+			nil, "", true},
+		{"name", "seed.example.com", lookup, "12.1.2.3", false},
+		{"name no addresses", "empty.example.com", lookup, "", true},
+		{"name unknown", "unknown.example.com", lookup, "", true},
+		{"name no lookup", "seed.example.com", nil, "", true},
+	}
+	for _, test := range tests {
+		na, err := HostToNetAddress(test.host, 9108, wire.SFNodeNetwork,
+			test.lookup)
+		if test.err {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !na.IP.Equal(net.ParseIP(test.want)) || na.Port != 9108 ||
+			na.Services != wire.SFNodeNetwork {
+
+			t.Errorf("%s: unexpected address -- got %v, want %s",
+				test.name, na, test.want)
+		}
+	}
+}
+
+// testClock is a clock whose current time is only changed explicitly.
+type testClock struct {
+	now time.Time
+}
+
+// Now returns the current time of the clock.
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+// TestClock ensures the configured clock is used to track when addresses are
+// attempted and banned and to determine when they expire.
+func TestClock(t *testing.T) {
+	clock := &testClock{now: time.Unix(1600000000, 0)}
+	n := NewWithConfig(&Config{DataDir: "testclock", Clock: clock})
+	addr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	addr.Timestamp = clock.now
+	src := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	n.AddAddress(addr, src)
+
+	n.Attempt(addr)
+	ka, ok := n.Lookup(addr)
+	if !ok {
+		t.Fatal("address not found")
+	}
+	if !ka.LastAttempt.Equal(clock.now) {
+		t.Fatalf("unexpected last attempt -- got %v, want %v",
+			ka.LastAttempt, clock.now)
+	}
+
+	// Ensure the address is considered bad once it has not been seen in
+	// too long according to the clock.
+	if n.find(addr).isBad(&n.cfg) {
+		t.Fatal("address is unexpectedly bad")
+	}
+	clock.now = clock.now.Add(time.Duration(numMissingDays+1) * 24 *
+		time.Hour)
+	if !n.find(addr).isBad(&n.cfg) {
+		t.Fatal("address is unexpectedly not bad")
+	}
+
+	// Ensure bans expire according to the clock.
+	n.BanAddress(src, time.Hour)
+	if !n.IsBanned(src) {
+		t.Fatal("address is unexpectedly not banned")
+	}
+	clock.now = clock.now.Add(time.Hour)
+	if n.IsBanned(src) {
+		t.Fatal("address is unexpectedly still banned")
+	}
+}
+
+// TestFlush ensures flushing saves the known addresses and bans immediately.
+func TestFlush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testflush")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := New(dir, nil)
+	addr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	banned := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	n.AddAddress(addr, addr)
+	n.BanAddress(banned, time.Hour)
+	n.Flush()
+
+	loaded := New(dir, nil)
+	loaded.loadPeers()
+	loaded.loadBans()
+	if !loaded.HasAddress(addr) {
+		t.Fatal("flushed address was not loaded")
+	}
+	if !loaded.IsBanned(banned) {
+		t.Fatal("flushed ban was not loaded")
+	}
+
+	// Ensure changes to the information tracked about known addresses are
+	// saved as well.
+	n.Attempt(addr)
+	n.Flush()
+	loaded = New(dir, nil)
+	loaded.loadPeers()
+	if ka, ok := loaded.Lookup(addr); !ok || ka.Attempts != 1 {
+		t.Fatalf("flushed attempt was not loaded: %+v", ka)
+	}
+}
+
+// TestRelayTimestampGranularity ensures the times addresses were last seen are
+// coarsened when handed out by the address cache while the precise times are
+// kept internally.
+func TestRelayTimestampGranularity(t *testing.T) {
+	const granularity = 3 * time.Hour
+	n := NewWithConfig(&Config{
+		DataDir:                   "testrelaytimestampgranularity",
+		SourceRateLimit:           -1,
+		RelayTimestampGranularity: granularity,
+	})
+	now := time.Unix(time.Now().Unix(), 0)
+	for i := 0; i < 100; i++ {
+		na := wire.NewNetAddressIPPort(net.IPv4(20, byte(i), 1, 1), 9108, 0)
+		na.Timestamp = now.Add(-time.Duration(i) * time.Minute)
+		n.AddAddress(na, na)
+		n.Good(na)
+	}
+
+	cache := n.AddressCache()
+	if len(cache) == 0 {
+		t.Fatal("empty address cache")
+	}
+	for _, na := range cache {
+		ka := n.addrIndex[NetAddressKey(na)]
+		if ka.na == na {
+			t.Fatalf("cached address %v is not a copy", na)
+		}
+		precise := ka.na.Timestamp
+		if precise.Before(now.Add(-time.Hour * 2)) {
+			t.Fatalf("precise timestamp of %v was modified", na)
+		}
+		if na.Timestamp.After(precise) {
+			t.Fatalf("relayed timestamp %v of %v is later than %v",
+				na.Timestamp, na, precise)
+		}
+		if precise.Sub(na.Timestamp) >= 2*granularity {
+			t.Fatalf("relayed timestamp %v of %v is too far from %v",
+				na.Timestamp, na, precise)
+		}
+		if na.Timestamp.Nanosecond() != 0 {
+			t.Fatalf("relayed timestamp %v of %v is not in whole "+
+				"seconds", na.Timestamp, na)
+		}
+	}
+
+	// Ensure the timestamps are left as is without a granularity.
+	n.cfg.RelayTimestampGranularity = 0
+	for _, na := range n.AddressCache() {
+		if n.addrIndex[NetAddressKey(na)].na != na {
+			t.Fatalf("cached address %v is a copy", na)
+		}
+	}
+}
+
+// BenchmarkAddAddresses benchmarks adding addresses that are already known,
+// which is the common case when peers relay addresses.
+func BenchmarkAddAddresses(b *testing.B) {
+	n := NewWithConfig(&Config{DataDir: "benchaddaddresses",
+		SourceRateLimit: -1})
+	addrs := make([]*wire.NetAddress, 0, 1000)
+	for i := 0; i < cap(addrs); i++ {
+		ip := net.IPv4(byte(i>>8)+12, byte(i), 1, 1)
+		addrs = append(addrs, wire.NewNetAddressIPPort(ip, 9108, 0))
+	}
+	src := wire.NewNetAddressIPPort(net.ParseIP("173.144.173.111"), 9108, 0)
+	n.AddAddresses(addrs, src)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.AddAddresses(addrs, src)
+	}
+}
+
+// BenchmarkGetAddresses benchmarks selecting addresses from distinct groups.
+func BenchmarkGetAddresses(b *testing.B) {
+	n := New("benchgetaddresses", nil)
+	addrs := make([]*wire.NetAddress, 0, 1000)
+	for i := 0; i < cap(addrs); i++ {
+		ip := net.IPv4(byte(i>>8)+12, byte(i), 1, 1)
+		addrs = append(addrs, wire.NewNetAddressIPPort(ip, 9108, 0))
+	}
+	src := wire.NewNetAddressIPPort(net.ParseIP("173.144.173.111"), 9108, 0)
+	n.AddAddresses(addrs, src)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n.GetAddresses(8)
+	}
+}
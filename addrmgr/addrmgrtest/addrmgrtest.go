@@ -0,0 +1,190 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package addrmgrtest provides utilities for testing code that uses the address
+// manager, such as generators of unique routable addresses and address managers
+// that are populated with them without touching the disk.
+package addrmgrtest
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/addrmgr"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// DefaultPort is the port of the generated addresses.
+	DefaultPort = 9108
+
+	// DefaultServices are the services of the generated addresses.
+	DefaultServices = wire.SFNodeNetwork
+)
+
+// AddrGen generates unique routable addresses of each type of network.
+// Consecutively generated addresses of the same type belong to different
+// address groups where possible so they are spread over the buckets of an
+// address manager.  The zero value is ready to use and generators that are
+// used identically generate identical addresses.
+type AddrGen struct {
+	// Clock, when set, provides the timestamp of the generated addresses.
+	// Otherwise, they are timestamped with the current system time.
+	Clock addrmgr.Clock
+
+	numIPv4  uint32
+	numIPv6  uint32
+	numOnion uint32
+}
+
+// IPv4 returns a unique routable IPv4 address.  It panics when more than
+// 5767168 addresses have been generated.
+func (g *AddrGen) IPv4() *wire.NetAddress {
+	n := g.numIPv4
+	g.numIPv4++
+	if n >= 88<<16 {
+		panic("addrmgrtest: too many IPv4 addresses generated")
+	}
+
+	// The first octet is in the range 12-99 which is entirely routable.
+	ip := net.IPv4(byte(12+(n>>16)), byte(n), byte(n>>8), 1)
+	return g.netAddress(ip)
+}
+
+// IPv6 returns a unique routable IPv6 address.
+func (g *AddrGen) IPv6() *wire.NetAddress {
+	n := g.numIPv6
+	g.numIPv6++
+
+	// The addresses are in the 2602::/16 block which is entirely
+	// routable.
+	ip := make(net.IP, net.IPv6len)
+	ip[0], ip[1] = 0x26, 0x02
+	ip[2], ip[3] = byte(n>>8), byte(n)
+	ip[4], ip[5] = byte(n>>24), byte(n>>16)
+	ip[15] = 1
+	return g.netAddress(ip)
+}
+
+// Onion returns a unique Tor onion address encoded as an OnionCat IPv6
+// address.
+func (g *AddrGen) Onion() *wire.NetAddress {
+	n := g.numOnion
+	g.numOnion++
+
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, []byte{0xfd, 0x87, 0xd8, 0x7e, 0xeb, 0x43})
+	ip[6], ip[7], ip[8], ip[9] = byte(n), byte(n>>8), byte(n>>16),
+		byte(n>>24)
+	return g.netAddress(ip)
+}
+
+// netAddress returns a network address for the provided IP that is timestamped
+// according to the clock of the generator.
+func (g *AddrGen) netAddress(ip net.IP) *wire.NetAddress {
+	now := time.Now()
+	if g.Clock != nil {
+		now = g.Clock.Now()
+	}
+	return wire.NewNetAddressTimestamp(now, DefaultServices, ip, DefaultPort)
+}
+
+// Addresses returns count unique addresses generated by the provided function,
+// such as the IPv4 method of a generator.
+func Addresses(count int, gen func() *wire.NetAddress) []*wire.NetAddress {
+	addrs := make([]*wire.NetAddress, 0, count)
+	for i := 0; i < count; i++ {
+		addrs = append(addrs, gen())
+	}
+	return addrs
+}
+
+// Config describes an address manager created by New.
+type Config struct {
+	// NumNew and NumTried are the number of generated IPv4 addresses that
+	// are added to the new and tried buckets, respectively.  Addresses in
+	// the tried buckets may evict each other when their buckets are full,
+	// so fewer addresses than requested may be known when it is large.
+	NumNew   int
+	NumTried int
+
+	// Seed seeds the source of randomness of the address manager so the
+	// placement and selection of addresses are reproducible.
+	Seed int64
+
+	// Clock is the clock of the address manager.  It defaults to the system
+	// clock.  A ManualClock allows the passage of time to be simulated.
+	Clock addrmgr.Clock
+}
+
+// ManualClock is a clock whose current time only changes when it is set or
+// advanced explicitly.  It is safe for concurrent access.
+type ManualClock struct {
+	mtx sync.Mutex
+	now time.Time
+}
+
+// NewManualClock returns a clock that is set to the provided time.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *ManualClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.now
+}
+
+// Set sets the current time of the clock.
+func (c *ManualClock) Set(now time.Time) {
+	c.mtx.Lock()
+	c.now = now
+	c.mtx.Unlock()
+}
+
+// Advance moves the current time of the clock forward by the provided
+// duration.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mtx.Lock()
+	c.now = c.now.Add(d)
+	c.mtx.Unlock()
+}
+
+// Ensure ManualClock implements the addrmgr.Clock interface.
+var _ addrmgr.Clock = (*ManualClock)(nil)
+
+// New returns an address manager that is populated according to the provided
+// configuration along with the generator used to populate it, which may be
+// used to generate further unique addresses.
+//
+// The address manager is never started and does not have a data directory, so
+// it neither loads nor saves addresses.  It MUST NOT be started.  The rate at
+// which addresses are accepted is not limited so it may be populated quickly.
+func New(cfg *Config) (*addrmgr.AddrManager, *AddrGen) {
+	amgr := addrmgr.NewWithConfig(&addrmgr.Config{
+		SourceRateLimit: -1,
+		Rand:            rand.New(rand.NewSource(cfg.Seed)),
+		Clock:           cfg.Clock,
+	})
+	gen := &AddrGen{Clock: cfg.Clock}
+	Populate(amgr, gen, cfg.NumNew, cfg.NumTried)
+	return amgr, gen
+}
+
+// Populate adds numNew and numTried IPv4 addresses from the provided generator
+// to the new and tried buckets of the provided address manager, respectively.
+// Each address is learned from a different source so they are spread over the
+// buckets.
+func Populate(amgr *addrmgr.AddrManager, gen *AddrGen, numNew, numTried int) {
+	for i := 0; i < numNew+numTried; i++ {
+		addr := gen.IPv4()
+		amgr.AddAddress(addr, gen.IPv6())
+		if i >= numNew {
+			amgr.Good(addr)
+		}
+	}
+}
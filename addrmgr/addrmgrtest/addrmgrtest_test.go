@@ -0,0 +1,114 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgrtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/addrmgr"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestAddrGen ensures generated addresses are unique, routable, on the
+// expected network, and spread over address groups.
+func TestAddrGen(t *testing.T) {
+	tests := []struct {
+		name    string
+		network addrmgr.NetworkAddress
+		gen     func(g *AddrGen) func() *wire.NetAddress
+	}{
+		{"ipv4", addrmgr.IPv4Address, func(g *AddrGen) func() *wire.NetAddress {
+			return g.IPv4
+		}},
+		{"ipv6", addrmgr.IPv6Address, func(g *AddrGen) func() *wire.NetAddress {
+			return g.IPv6
+		}},
+		{"onion", addrmgr.OnionAddress, func(g *AddrGen) func() *wire.NetAddress {
+			return g.Onion
+		}},
+	}
+	for _, test := range tests {
+		const count = 1000
+		addrs := Addresses(count, test.gen(new(AddrGen)))
+		keys := make(map[string]struct{})
+		groups := make(map[string]struct{})
+		for _, addr := range addrs {
+			if !addrmgr.IsRoutable(addr) {
+				t.Fatalf("%s: address %v is not routable", test.name,
+					addr.IP)
+			}
+			if got := addrmgr.Network(addr); got != test.network {
+				t.Fatalf("%s: unexpected network for %v -- got %v, "+
+					"want %v", test.name, addr.IP, got, test.network)
+			}
+			keys[addrmgr.NetAddressKey(addr)] = struct{}{}
+			groups[addrmgr.GroupKey(addr)] = struct{}{}
+		}
+		if len(keys) != count {
+			t.Fatalf("%s: unexpected number of unique addresses -- got "+
+				"%d, want %d", test.name, len(keys), count)
+		}
+		if len(groups) < 2 {
+			t.Fatalf("%s: addresses are all in group %v", test.name,
+				groups)
+		}
+
+		// Ensure generators generate identical addresses.
+		again := Addresses(count, test.gen(new(AddrGen)))
+		for i := range addrs {
+			if !addrs[i].IP.Equal(again[i].IP) {
+				t.Fatalf("%s: address %d differs -- got %v, want %v",
+					test.name, i, again[i].IP, addrs[i].IP)
+			}
+		}
+	}
+}
+
+// TestNew ensures address managers are populated as requested.
+func TestNew(t *testing.T) {
+	amgr, gen := New(&Config{NumNew: 100, NumTried: 20, Seed: 1})
+	s := amgr.Snapshot(false)
+	if s.NewAddresses != 100 || s.TriedAddresses != 20 {
+		t.Fatalf("unexpected address counts -- got %d new %d tried, want "+
+			"100 new 20 tried", s.NewAddresses, s.TriedAddresses)
+	}
+	if amgr.GetAddress() == nil {
+		t.Fatal("no address selected")
+	}
+
+	// Ensure the generator continues to generate unique addresses.
+	if addr := gen.IPv4(); amgr.HasAddress(addr) {
+		t.Fatalf("generated address %v is already known", addr.IP)
+	}
+}
+
+// TestManualClock ensures address managers use the provided clock.
+func TestManualClock(t *testing.T) {
+	clock := NewManualClock(time.Unix(1600000000, 0))
+	amgr, gen := New(&Config{NumNew: 1, Clock: clock})
+	addr := gen.IPv4()
+	if !addr.Timestamp.Equal(clock.Now()) {
+		t.Fatalf("unexpected timestamp -- got %v, want %v", addr.Timestamp,
+			clock.Now())
+	}
+	amgr.AddAddress(addr, gen.IPv6())
+
+	clock.Advance(time.Hour)
+	amgr.Attempt(addr)
+	ka, ok := amgr.Lookup(addr)
+	if !ok {
+		t.Fatal("address not found")
+	}
+	if want := time.Unix(1600003600, 0); !ka.LastAttempt.Equal(want) {
+		t.Fatalf("unexpected last attempt -- got %v, want %v",
+			ka.LastAttempt, want)
+	}
+
+	clock.Set(time.Unix(1700000000, 0))
+	if got, want := clock.Now(), time.Unix(1700000000, 0); !got.Equal(want) {
+		t.Fatalf("unexpected time -- got %v, want %v", got, want)
+	}
+}
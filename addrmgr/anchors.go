@@ -0,0 +1,148 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// AnchorsFilename is the default filename to store serialized anchors.
+const AnchorsFilename = "anchors.json"
+
+// MaxAnchors is the maximum number of anchor addresses that are saved.
+const MaxAnchors = 2
+
+// serializedAnchor is the serialized form of an anchor address.
+type serializedAnchor struct {
+	Addr     string
+	Services wire.ServiceFlag
+}
+
+// SetAnchors sets the addresses of the peers that are saved as anchors when the
+// address manager is stopped or its context is canceled.  Anchors are peers
+// that were successfully used to relay blocks, and reconnecting to them on the
+// next start makes it harder for an adversary to take advantage of a restart
+// to eclipse the node.  Only the first MaxAnchors addresses are saved, so they
+// should be ordered from the most to the least preferred.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) SetAnchors(addrs []*wire.NetAddress) {
+	a.mtx.Lock()
+	a.newAnchors = nil
+	for _, na := range addrs {
+		if len(a.newAnchors) == MaxAnchors {
+			break
+		}
+		if a.refuseNetwork(na) {
+			continue
+		}
+		a.newAnchors = append(a.newAnchors, na)
+	}
+	a.mtx.Unlock()
+}
+
+// GetAnchors returns the anchor addresses that were saved when the address
+// manager was last stopped.  They are loaded when the address manager is
+// started.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) GetAnchors() []*wire.NetAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return append([]*wire.NetAddress(nil), a.anchors...)
+}
+
+// saveAnchors saves the anchors set via SetAnchors to a file so they can be
+// read back in at next run.
+func (a *AddrManager) saveAnchors() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if len(a.newAnchors) == 0 {
+		return
+	}
+
+	anchors := make([]serializedAnchor, 0, len(a.newAnchors))
+	for _, na := range a.newAnchors {
+		anchors = append(anchors, serializedAnchor{
+			Addr:     NetAddressKey(na),
+			Services: na.Services,
+		})
+	}
+
+	// Write temporary anchors file and then move it into place.
+	tmpfile := a.anchorsFile + ".new"
+	w, err := os.Create(tmpfile)
+	if err != nil {
+		log.Errorf("Error opening file %s: %v", tmpfile, err)
+		return
+	}
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(&anchors); err != nil {
+		w.Close()
+		log.Errorf("Failed to encode file %s: %v", tmpfile, err)
+		return
+	}
+	if err := w.Close(); err != nil {
+		log.Errorf("Error closing file %s: %v", tmpfile, err)
+		return
+	}
+	if err := os.Rename(tmpfile, a.anchorsFile); err != nil {
+		log.Errorf("Error writing file %s: %v", a.anchorsFile, err)
+		return
+	}
+}
+
+// loadAnchors loads the anchors from the saved file and then removes it so the
+// same anchors are not reused should the node stop before new ones are saved.
+// A missing or malformed file results in no anchors.
+func (a *AddrManager) loadAnchors() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	r, err := os.Open(a.anchorsFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("Error opening file %s: %v", a.anchorsFile, err)
+		}
+		return
+	}
+	var anchors []serializedAnchor
+	err = json.NewDecoder(r).Decode(&anchors)
+	r.Close()
+	if err := os.Remove(a.anchorsFile); err != nil {
+		log.Warnf("Failed to remove anchors file %s: %v", a.anchorsFile,
+			err)
+	}
+	if err != nil {
+		log.Errorf("Failed to parse file %s: %v", a.anchorsFile, err)
+		return
+	}
+
+	for _, anchor := range anchors {
+		if len(a.anchors) == MaxAnchors {
+			break
+		}
+		na, err := a.DeserializeNetAddress(anchor.Addr)
+		if err != nil {
+			log.Warnf("Failed to deserialize anchor %s: %v", anchor.Addr,
+				err)
+			continue
+		}
+		if !a.IsRoutable(na) || a.refuseNetwork(na) || a.isBanned(na) ||
+			a.isBlocked(na) {
+
+			continue
+		}
+		na.Services = anchor.Services
+		a.anchors = append(a.anchors, na)
+	}
+	log.Infof("Loaded %d anchors from file '%s'", len(a.anchors),
+		a.anchorsFile)
+}
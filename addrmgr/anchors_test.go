@@ -0,0 +1,76 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestAnchors ensures anchors are saved when the address manager is stopped,
+// loaded when it is next started, and only used once.
+func TestAnchors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anchorstest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// Ensure there are no anchors without a saved anchors file.
+	n := New(dir, nil)
+	n.Start()
+	if anchors := n.GetAnchors(); len(anchors) != 0 {
+		t.Fatalf("unexpected anchors %v", anchors)
+	}
+
+	// Ensure only the maximum number of anchors are saved.
+	addrs := []*wire.NetAddress{
+		wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108,
+			wire.SFNodeNetwork),
+		wire.NewNetAddressIPPort(net.ParseIP("2602:100::1"), 19108,
+			wire.SFNodeNetwork),
+		wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108,
+			wire.SFNodeNetwork),
+	}
+	n.SetAnchors(addrs)
+	n.Stop()
+
+	n = New(dir, nil)
+	n.Start()
+	anchors := n.GetAnchors()
+	if len(anchors) != MaxAnchors {
+		t.Fatalf("unexpected number of anchors -- got %d, want %d",
+			len(anchors), MaxAnchors)
+	}
+	for i, anchor := range anchors {
+		if NetAddressKey(anchor) != NetAddressKey(addrs[i]) ||
+			anchor.Services != addrs[i].Services {
+
+			t.Errorf("anchor %d: unexpected address -- got %s (%v), want "+
+				"%s (%v)", i, NetAddressKey(anchor), anchor.Services,
+				NetAddressKey(addrs[i]), addrs[i].Services)
+		}
+	}
+
+	// Ensure the anchors file is removed once loaded.
+	_, err = os.Stat(filepath.Join(dir, AnchorsFilename))
+	if !os.IsNotExist(err) {
+		t.Fatalf("anchors file was not removed: %v", err)
+	}
+	n.Stop()
+
+	// Ensure there are no anchors when none were set before stopping.
+	n = New(dir, nil)
+	n.Start()
+	defer n.Stop()
+	if anchors := n.GetAnchors(); len(anchors) != 0 {
+		t.Fatalf("unexpected anchors %v", anchors)
+	}
+}
@@ -0,0 +1,357 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"math/bits"
+	"net"
+)
+
+// asmapInstruction defines the instructions of the program encoded by an
+// asmap.
+type asmapInstruction uint32
+
+const (
+	// asmapReturn returns the AS number that follows it.
+	asmapReturn asmapInstruction = 0
+
+	// asmapJump consumes a bit of the IP address and skips ahead by the
+	// offset that follows it when the bit is set.
+	asmapJump asmapInstruction = 1
+
+	// asmapMatch compares the bits of the IP address against the pattern
+	// that follows it and returns the default AS number when they differ.
+	asmapMatch asmapInstruction = 2
+
+	// asmapDefault sets the default AS number to the one that follows it.
+	asmapDefault asmapInstruction = 3
+)
+
+const (
+	// asmapInvalid is returned when decoding a value from an asmap fails.
+	asmapInvalid = 0xffffffff
+
+	// asmapIPBits is the number of bits in the IP addresses that are
+	// looked up in an asmap.  IPv4 addresses are looked up via their IPv4
+	// mapped IPv6 form.
+	asmapIPBits = 128
+
+	// maxASMapSize is the maximum size of an asmap file that will be
+	// loaded.
+	maxASMapSize = 8 * 1024 * 1024
+)
+
+var (
+	// The following define the sizes of the variable length encodings used
+	// for the types of instructions and the values that follow them.
+	asmapTypeBitSizes  = []uint8{0, 0, 1}
+	asmapASNBitSizes   = []uint8{15, 16, 17, 18, 19, 20, 21, 22, 23, 24}
+	asmapMatchBitSizes = []uint8{1, 2, 3, 4, 5, 6, 7, 8}
+	asmapJumpBitSizes  = []uint8{5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16,
+		17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30}
+)
+
+// ASMap maps IP addresses to the number of the autonomous system (AS) they are
+// announced by.  It uses the compact binary format produced by the asmap tools
+// used by other cryptocurrency node software, which encodes a small program
+// that is executed against the bits of an IP address to find its AS number.
+//
+// Grouping addresses by AS number instead of by network prefix makes it harder
+// for an adversary that controls many addresses spread across prefixes in a
+// single AS to eclipse a node.
+type ASMap struct {
+	data     []byte
+	numBits  int
+	checksum string
+}
+
+// bit returns the bit of the asmap at the provided position.
+func (m *ASMap) bit(pos int) bool {
+	return (m.data[pos/8]>>(uint(pos)%8))&1 == 1
+}
+
+// decodeBits decodes a variable length encoded value with the provided minimum
+// value and encoding sizes from the asmap at the provided position.  It returns
+// the decoded value and the position that follows it, or asmapInvalid when the
+// encoding extends past the end of the asmap.
+func (m *ASMap) decodeBits(pos int, minVal uint32, bitSizes []uint8) (uint32, int) {
+	val := minVal
+	for i, bitSize := range bitSizes {
+		var bit bool
+		if i+1 != len(bitSizes) {
+			if pos == m.numBits {
+				break
+			}
+			bit = m.bit(pos)
+			pos++
+		}
+		if bit {
+			val += 1 << bitSize
+			continue
+		}
+		for b := uint8(0); b < bitSize; b++ {
+			if pos == m.numBits {
+				return asmapInvalid, pos
+			}
+			if m.bit(pos) {
+				val += 1 << (bitSize - 1 - b)
+			}
+			pos++
+		}
+		return val, pos
+	}
+	return asmapInvalid, pos
+}
+
+// decodeType decodes an instruction type from the asmap at the provided
+// position.
+func (m *ASMap) decodeType(pos int) (asmapInstruction, int) {
+	val, pos := m.decodeBits(pos, 0, asmapTypeBitSizes)
+	return asmapInstruction(val), pos
+}
+
+// decodeASN decodes an AS number from the asmap at the provided position.
+func (m *ASMap) decodeASN(pos int) (uint32, int) {
+	return m.decodeBits(pos, 1, asmapASNBitSizes)
+}
+
+// decodeMatch decodes a match pattern from the asmap at the provided position.
+func (m *ASMap) decodeMatch(pos int) (uint32, int) {
+	return m.decodeBits(pos, 2, asmapMatchBitSizes)
+}
+
+// decodeJump decodes a jump offset from the asmap at the provided position.
+func (m *ASMap) decodeJump(pos int) (uint32, int) {
+	return m.decodeBits(pos, 17, asmapJumpBitSizes)
+}
+
+// asmapIPBit returns the bit of the provided 16-byte IP address at the provided
+// position, starting from the most significant bit.
+func asmapIPBit(ip net.IP, pos int) bool {
+	return (ip[pos/8]>>(7-uint(pos)%8))&1 == 1
+}
+
+// sanityCheck returns whether or not the program encoded by the asmap is well
+// formed such that executing it always terminates without reading past the end
+// of the asmap or the IP address.
+func (m *ASMap) sanityCheck() bool {
+	type jumpTarget struct {
+		offset int
+		bits   int
+	}
+	var jumps []jumpTarget
+	bitsLeft := asmapIPBits
+	prevOpcode := asmapJump
+	hadIncompleteMatch := false
+	pos := 0
+	for pos != m.numBits {
+		if len(jumps) > 0 && pos >= jumps[len(jumps)-1].offset {
+			// Jump into the middle of the previous instruction.
+			return false
+		}
+
+		opcode, next := m.decodeType(pos)
+		pos = next
+		switch opcode {
+		case asmapReturn:
+			if prevOpcode == asmapDefault {
+				// A default followed by a return could be combined
+				// into just the return.
+				return false
+			}
+			asn, next := m.decodeASN(pos)
+			if asn == asmapInvalid {
+				return false
+			}
+			pos = next
+			if len(jumps) == 0 {
+				// Nothing is left to execute, so only up to 7 bits of
+				// zero padding may remain.
+				if m.numBits-pos > 7 {
+					return false
+				}
+				for ; pos != m.numBits; pos++ {
+					if m.bit(pos) {
+						return false
+					}
+				}
+				return true
+			}
+
+			// Continue as if the last jump was taken.
+			target := jumps[len(jumps)-1]
+			if pos != target.offset {
+				// Unreachable code.
+				return false
+			}
+			bitsLeft = target.bits
+			jumps = jumps[:len(jumps)-1]
+			prevOpcode = asmapJump
+
+		case asmapJump:
+			jump, next := m.decodeJump(pos)
+			if jump == asmapInvalid {
+				return false
+			}
+			pos = next
+			if int64(jump) > int64(m.numBits-pos) || bitsLeft == 0 {
+				return false
+			}
+			bitsLeft--
+			offset := pos + int(jump)
+			if len(jumps) > 0 && offset >= jumps[len(jumps)-1].offset {
+				// Intersecting jumps.
+				return false
+			}
+			jumps = append(jumps, jumpTarget{offset, bitsLeft})
+			prevOpcode = asmapJump
+
+		case asmapMatch:
+			match, next := m.decodeMatch(pos)
+			if match == asmapInvalid {
+				return false
+			}
+			pos = next
+			matchLen := bits.Len32(match) - 1
+			if prevOpcode != asmapMatch {
+				hadIncompleteMatch = false
+			}
+			if matchLen < 8 && hadIncompleteMatch {
+				// Only one match in a sequence may be incomplete.
+				return false
+			}
+			hadIncompleteMatch = matchLen < 8
+			if bitsLeft < matchLen {
+				return false
+			}
+			bitsLeft -= matchLen
+			prevOpcode = asmapMatch
+
+		case asmapDefault:
+			if prevOpcode == asmapDefault {
+				// Successive defaults could be combined into one.
+				return false
+			}
+			asn, next := m.decodeASN(pos)
+			if asn == asmapInvalid {
+				return false
+			}
+			pos = next
+			prevOpcode = asmapDefault
+
+		default:
+			// Instruction extends past the end of the asmap.
+			return false
+		}
+	}
+
+	// The end of the asmap was reached without a return.
+	return false
+}
+
+// Lookup returns the number of the autonomous system the provided IP address
+// is announced by, or zero when it is unknown.
+func (m *ASMap) Lookup(ip net.IP) uint32 {
+	ip = ip.To16()
+	if ip == nil {
+		return 0
+	}
+
+	bitsLeft := asmapIPBits
+	var defaultASN uint32
+	pos := 0
+	for pos != m.numBits {
+		opcode, next := m.decodeType(pos)
+		pos = next
+		switch opcode {
+		case asmapReturn:
+			asn, _ := m.decodeASN(pos)
+			if asn == asmapInvalid {
+				return 0
+			}
+			return asn
+
+		case asmapJump:
+			jump, next := m.decodeJump(pos)
+			if jump == asmapInvalid || bitsLeft == 0 ||
+				int64(jump) >= int64(m.numBits-next) {
+
+				return 0
+			}
+			pos = next
+			if asmapIPBit(ip, asmapIPBits-bitsLeft) {
+				pos += int(jump)
+			}
+			bitsLeft--
+
+		case asmapMatch:
+			match, next := m.decodeMatch(pos)
+			if match == asmapInvalid {
+				return 0
+			}
+			pos = next
+			matchLen := bits.Len32(match) - 1
+			if bitsLeft < matchLen {
+				return 0
+			}
+			for bit := 0; bit < matchLen; bit++ {
+				want := (match>>uint(matchLen-1-bit))&1 == 1
+				if asmapIPBit(ip, asmapIPBits-bitsLeft) != want {
+					return defaultASN
+				}
+				bitsLeft--
+			}
+
+		case asmapDefault:
+			asn, next := m.decodeASN(pos)
+			if asn == asmapInvalid {
+				return 0
+			}
+			pos = next
+			defaultASN = asn
+
+		default:
+			return 0
+		}
+	}
+	return 0
+}
+
+// Checksum returns a hex-encoded hash of the asmap which uniquely identifies
+// it.
+func (m *ASMap) Checksum() string {
+	return m.checksum
+}
+
+// DecodeASMap decodes the provided serialized asmap.  An error is returned when
+// the program it encodes is not well formed.
+func DecodeASMap(data []byte) (*ASMap, error) {
+	hash := sha256.Sum256(data)
+	m := &ASMap{
+		data:     data,
+		numBits:  len(data) * 8,
+		checksum: hex.EncodeToString(hash[:]),
+	}
+	if !m.sanityCheck() {
+		return nil, errors.New("malformed asmap")
+	}
+	return m, nil
+}
+
+// LoadASMap reads and decodes the asmap in the file at the provided path.
+func LoadASMap(path string) (*ASMap, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxASMapSize {
+		return nil, errors.New("asmap file is too large")
+	}
+	return DecodeASMap(data)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// asmapBuilder builds serialized asmaps for testing.
+type asmapBuilder struct {
+	bits []bool
+}
+
+// putBits appends the provided number of low order bits of the provided value,
+// most significant bit first.
+func (b *asmapBuilder) putBits(val uint32, numBits uint8) {
+	for i := int(numBits) - 1; i >= 0; i-- {
+		b.bits = append(b.bits, (val>>uint(i))&1 == 1)
+	}
+}
+
+// putValue appends the provided value using the variable length encoding with
+// the provided minimum value and encoding sizes.
+func (b *asmapBuilder) putValue(val, minVal uint32, bitSizes []uint8) {
+	val -= minVal
+	for i, bitSize := range bitSizes {
+		last := i+1 == len(bitSizes)
+		if !last && val >= 1<<bitSize {
+			b.bits = append(b.bits, true)
+			val -= 1 << bitSize
+			continue
+		}
+		if !last {
+			b.bits = append(b.bits, false)
+		}
+		b.putBits(val, bitSize)
+		return
+	}
+}
+
+func (b *asmapBuilder) putType(t asmapInstruction) {
+	b.putValue(uint32(t), 0, asmapTypeBitSizes)
+}
+
+func (b *asmapBuilder) putReturn(asn uint32) {
+	b.putType(asmapReturn)
+	b.putValue(asn, 1, asmapASNBitSizes)
+}
+
+func (b *asmapBuilder) putJump(offset uint32) {
+	b.putType(asmapJump)
+	b.putValue(offset, 17, asmapJumpBitSizes)
+}
+
+func (b *asmapBuilder) putMatch(pattern byte) {
+	b.putType(asmapMatch)
+	b.putValue(1<<8|uint32(pattern), 2, asmapMatchBitSizes)
+}
+
+// bytes returns the serialized asmap padded with zero bits to a whole number of
+// bytes.
+func (b *asmapBuilder) bytes() []byte {
+	data := make([]byte, (len(b.bits)+7)/8)
+	for i, bit := range b.bits {
+		if bit {
+			data[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return data
+}
+
+// testASMap returns a serialized asmap which maps IPv4 addresses with a first
+// octet below 128 to AS 100, all other IPv4 addresses to AS 200, and all IPv6
+// addresses to the unknown AS 0.
+func testASMap() []byte {
+	var b asmapBuilder
+	for _, pattern := range net.IPv4(0, 0, 0, 0)[:12] {
+		b.putMatch(pattern)
+	}
+	var ret asmapBuilder
+	ret.putReturn(100)
+	b.putJump(uint32(len(ret.bits)))
+	b.putReturn(100)
+	b.putReturn(200)
+	return b.bytes()
+}
+
+// TestASMap ensures asmaps are decoded, validated, and used to look up the AS
+// number of IP addresses as expected.
+func TestASMap(t *testing.T) {
+	asmap, err := DecodeASMap(testASMap())
+	if err != nil {
+		t.Fatalf("DecodeASMap: unexpected error: %v", err)
+	}
+
+	lookupTests := []struct {
+		ip   string
+		want uint32
+	}{
+		{"12.1.2.3", 100},
+		{"127.255.255.255", 100},
+		{"128.0.0.0", 200},
+		{"196.1.2.3", 200},
+		{"2602:100::1", 0},
+	}
+	for _, test := range lookupTests {
+		if asn := asmap.Lookup(net.ParseIP(test.ip)); asn != test.want {
+			t.Errorf("Lookup %s: unexpected AS -- got %d, want %d",
+				test.ip, asn, test.want)
+		}
+	}
+	if asn := asmap.Lookup(net.ParseIP("12.1.2.3").To4()); asn != 100 {
+		t.Errorf("Lookup: unexpected AS for 4-byte IPv4 -- got %d, want %d",
+			asn, 100)
+	}
+
+	// Ensure malformed asmaps are rejected.
+	valid := testASMap()
+	malformed := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"truncated", valid[:len(valid)-2]},
+		{"extra byte", append(append([]byte(nil), valid...), 0)},
+		{"nonzero padding", func() []byte {
+			data := append([]byte(nil), valid...)
+			data[len(data)-1] |= 0x80
+			return data
+		}()},
+	}
+	for _, test := range malformed {
+		if _, err := DecodeASMap(test.data); err == nil {
+			t.Errorf("DecodeASMap %s: did not receive expected error",
+				test.name)
+		}
+	}
+}
+
+// TestASMapGroupKey ensures the address manager groups addresses by AS number
+// when an asmap is set and the addresses are redistributed when the asmap of a
+// saved peers file differs.
+func TestASMapGroupKey(t *testing.T) {
+	dir, err := ioutil.TempDir("", "asmaptest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := New(dir, nil)
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"12.1.2.3", "12.1.0.0"},
+		{"2602:100::1", "2602:100::"},
+		{"fd87:d87e:eb43:1234::5678", "tor:2"},
+	}
+	for _, test := range tests {
+		na := wire.NewNetAddressIPPort(net.ParseIP(test.ip), 9108, 0)
+		if key := n.GroupKey(na); key != test.want {
+			t.Errorf("GroupKey %s: unexpected group key -- got %q, want "+
+				"%q", test.ip, key, test.want)
+		}
+	}
+
+	// Save some addresses without an asmap.
+	for _, ip := range []string{"12.1.2.3", "13.1.2.3", "196.1.2.3"} {
+		if err := n.addAddressByIP(ip + ":9108"); err != nil {
+			t.Fatalf("Adding address failed: %v", err)
+		}
+	}
+	n.savePeers()
+
+	asmapPath := filepath.Join(dir, "asmap.dat")
+	if err := ioutil.WriteFile(asmapPath, testASMap(), 0600); err != nil {
+		t.Fatalf("unable to write asmap: %v", err)
+	}
+	asmap, err := LoadASMap(asmapPath)
+	if err != nil {
+		t.Fatalf("LoadASMap: unexpected error: %v", err)
+	}
+
+	// Ensure addresses are grouped by AS number when it is known and the
+	// saved addresses are loaded into the buckets for the new grouping.
+	n = New(dir, nil)
+	n.SetASMap(asmap)
+	tests = []struct {
+		ip   string
+		want string
+	}{
+		{"12.1.2.3", "as:100"},
+		{"13.1.2.3", "as:100"},
+		{"196.1.2.3", "as:200"},
+		{"2002:c401:0203::", "as:200"},
+		{"2602:100::1", "2602:100::"},
+		{"fd87:d87e:eb43:1234::5678", "tor:2"},
+		{"10.1.2.3", "unroutable"},
+	}
+	for _, test := range tests {
+		na := wire.NewNetAddressIPPort(net.ParseIP(test.ip), 9108, 0)
+		if key := n.GroupKey(na); key != test.want {
+			t.Errorf("GroupKey %s: unexpected group key -- got %q, want "+
+				"%q", test.ip, key, test.want)
+		}
+	}
+	n.loadPeers()
+	if n.numAddresses() != 3 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 3",
+			n.numAddresses())
+	}
+	for key, ka := range n.addrIndex {
+		bucket := n.getNewBucket(ka.na, ka.srcAddr)
+		if _, ok := n.addrNew[bucket][key]; !ok {
+			t.Errorf("address %s is not in its new bucket %d", key, bucket)
+		}
+	}
+}
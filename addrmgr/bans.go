@@ -0,0 +1,167 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// BansFilename is the default filename to store serialized bans.
+const BansFilename = "bans.json"
+
+// serializedBan is the serialized form of a banned host.
+type serializedBan struct {
+	Host  string
+	Until int64
+}
+
+// banKey returns the key used to track bans of the host of the provided
+// address.  Bans apply to every port of a host.
+func banKey(na *wire.NetAddress) string {
+	return ipString(na)
+}
+
+// BanAddress bans the host of the provided address for the provided duration.
+// Known addresses of the host are forgotten and addresses of the host are not
+// stored, and therefore not selected or relayed, until the ban expires.  The
+// bans are saved alongside the known addresses so they persist across
+// restarts.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) BanAddress(na *wire.NetAddress, duration time.Duration) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	host := banKey(na)
+	a.banned[host] = a.cfg.now().Add(duration)
+	a.bansChanged = true
+	a.removeBanned()
+	log.Debugf("Banned host %s for %v", host, duration)
+}
+
+// IsBanned returns whether or not the host of the provided address is
+// currently banned.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) IsBanned(na *wire.NetAddress) bool {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return a.isBanned(na)
+}
+
+// isBanned returns whether or not the host of the provided address is
+// currently banned.  Expired bans are removed.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) isBanned(na *wire.NetAddress) bool {
+	host := banKey(na)
+	until, ok := a.banned[host]
+	if !ok {
+		return false
+	}
+	if a.cfg.now().Before(until) {
+		return true
+	}
+	log.Debugf("Host %s is no longer banned", host)
+	delete(a.banned, host)
+	a.bansChanged = true
+	return false
+}
+
+// removeBanned forgets all known addresses of banned hosts.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) removeBanned() {
+	for _, ka := range a.addrIndex {
+		if a.isBanned(ka.na) {
+			a.removeAddress(ka)
+		}
+	}
+}
+
+// saveBans saves the unexpired bans to a file so they can be read back in at
+// next run.
+func (a *AddrManager) saveBans() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if !a.bansChanged {
+		return
+	}
+
+	now := a.cfg.now()
+	bans := make([]serializedBan, 0, len(a.banned))
+	for host, until := range a.banned {
+		if !now.Before(until) {
+			delete(a.banned, host)
+			continue
+		}
+		bans = append(bans, serializedBan{Host: host, Until: until.Unix()})
+	}
+
+	// Write temporary bans file and then move it into place.
+	tmpfile := a.bansFile + ".new"
+	w, err := os.Create(tmpfile)
+	if err != nil {
+		log.Errorf("Error opening file %s: %v", tmpfile, err)
+		return
+	}
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(&bans); err != nil {
+		w.Close()
+		log.Errorf("Failed to encode file %s: %v", tmpfile, err)
+		return
+	}
+	if err := w.Close(); err != nil {
+		log.Errorf("Error closing file %s: %v", tmpfile, err)
+		return
+	}
+	if err := os.Rename(tmpfile, a.bansFile); err != nil {
+		log.Errorf("Error writing file %s: %v", a.bansFile, err)
+		return
+	}
+	a.bansChanged = false
+}
+
+// loadBans loads the unexpired bans from the saved file and forgets any known
+// addresses of the banned hosts.  A missing or malformed file results in no
+// bans.
+func (a *AddrManager) loadBans() {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	r, err := os.Open(a.bansFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Errorf("Error opening file %s: %v", a.bansFile, err)
+		}
+		return
+	}
+	defer r.Close()
+
+	var bans []serializedBan
+	if err := json.NewDecoder(r).Decode(&bans); err != nil {
+		log.Errorf("Failed to parse file %s: %v", a.bansFile, err)
+		return
+	}
+	now := a.cfg.now()
+	for _, ban := range bans {
+		until := time.Unix(ban.Until, 0)
+		if !now.Before(until) {
+			a.bansChanged = true
+			continue
+		}
+		a.banned[ban.Host] = until
+	}
+	a.removeBanned()
+	log.Infof("Loaded %d bans from file '%s'", len(a.banned), a.bansFile)
+}
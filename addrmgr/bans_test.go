@@ -0,0 +1,79 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestBans ensures banned hosts are forgotten, refused, and that the bans are
+// persisted across restarts until they expire.
+func TestBans(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testbans")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	newNA := func(ip string, port uint16) *wire.NetAddress {
+		return wire.NewNetAddressIPPort(net.ParseIP(ip), port, 0)
+	}
+	banned := newNA("12.1.2.3", 9108)
+	bannedOtherPort := newNA("12.1.2.3", 9109)
+	tried := newNA("13.1.2.3", 9108)
+	expired := newNA("14.1.2.3", 9108)
+	good := newNA("15.1.2.3", 9108)
+
+	n := New(dir, nil)
+	n.Start()
+	n.AddAddresses([]*wire.NetAddress{banned, bannedOtherPort, tried,
+		expired, good}, good)
+	n.Good(tried)
+
+	// Ensure known addresses of banned hosts are forgotten, including
+	// addresses on other ports and tried addresses.
+	n.BanAddress(banned, time.Hour)
+	n.BanAddress(tried, time.Hour)
+	n.BanAddress(expired, -time.Second)
+	if !n.IsBanned(bannedOtherPort) || !n.IsBanned(tried) {
+		t.Fatal("host was not banned")
+	}
+	if n.IsBanned(expired) || n.IsBanned(good) {
+		t.Fatal("unexpected banned host")
+	}
+	if n.numAddresses() != 2 || n.nTried != 0 || n.nNew != 2 {
+		t.Fatalf("unexpected number of addresses -- got %d (%d new, %d "+
+			"tried), want 2 new", n.numAddresses(), n.nNew, n.nTried)
+	}
+
+	// Ensure addresses of banned hosts are not stored.
+	n.AddAddress(banned, good)
+	if n.find(banned) != nil {
+		t.Fatal("address of banned host was stored")
+	}
+	if err := n.Stop(); err != nil {
+		t.Fatalf("failed to stop address manager: %v", err)
+	}
+
+	// Ensure the bans persist across restarts and known addresses of hosts
+	// that are banned after loading are forgotten.
+	n = New(dir, nil)
+	if err := n.addAddressByIP("13.1.2.3:9108"); err != nil {
+		t.Fatalf("Adding address failed: %v", err)
+	}
+	n.loadBans()
+	if !n.IsBanned(banned) || !n.IsBanned(tried) || n.IsBanned(expired) {
+		t.Fatal("unexpected bans after loading")
+	}
+	if n.find(tried) != nil {
+		t.Fatal("address of banned host was not forgotten after loading")
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// Blocklist is a set of IP network ranges and onion address patterns whose
+// addresses the address manager never stores, such as those published by
+// external threat intelligence blocklists.
+type Blocklist struct {
+	nets   []*net.IPNet
+	onions []string
+}
+
+// ParseBlocklist returns a blocklist of the provided rules.  Each rule is
+// either an IP network in CIDR notation (eg. 192.168.1.0/24), a single IP
+// address, or an onion address pattern (eg. abc*.onion).  Onion address
+// patterns are matched against the entire onion address and may contain *
+// wildcards.
+func ParseBlocklist(rules []string) (*Blocklist, error) {
+	var b Blocklist
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		switch {
+		case strings.HasSuffix(rule, ".onion"):
+			if _, err := path.Match(rule, ""); err != nil {
+				return nil, fmt.Errorf("invalid onion address pattern "+
+					"%q: %v", rule, err)
+			}
+			b.onions = append(b.onions, rule)
+
+		case strings.Contains(rule, "/"):
+			_, ipNet, err := net.ParseCIDR(rule)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP network %q: %v",
+					rule, err)
+			}
+			b.nets = append(b.nets, ipNet)
+
+		default:
+			ip := net.ParseIP(rule)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", rule)
+			}
+			bits := net.IPv6len * 8
+			if ip4 := ip.To4(); ip4 != nil {
+				ip = ip4
+				bits = net.IPv4len * 8
+			}
+			b.nets = append(b.nets, &net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(bits, bits),
+			})
+		}
+	}
+	return &b, nil
+}
+
+// LoadBlocklist returns a blocklist of the rules in the file at the provided
+// path.  The file contains one rule per line in the format accepted by
+// ParseBlocklist.  Empty lines and anything following a # are ignored.
+func LoadBlocklist(path string) (*Blocklist, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var rules []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i != -1 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			rules = append(rules, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ParseBlocklist(rules)
+}
+
+// Contains returns whether or not the provided address matches any of the
+// rules of the blocklist.
+func (b *Blocklist) Contains(na *wire.NetAddress) bool {
+	for _, ipNet := range b.nets {
+		if ipNet.Contains(na.IP) {
+			return true
+		}
+	}
+	if isOnionCatTor(na) {
+		host := ipString(na)
+		for _, pattern := range b.onions {
+			if ok, _ := path.Match(pattern, host); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddBlocklist registers the provided blocklist with the address manager.
+// Addresses that match the blocklist are silently dropped when they are added
+// and known addresses that match it are forgotten.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) AddBlocklist(b *Blocklist) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	a.blocklists = append(a.blocklists, b)
+	a.removeBlocked()
+}
+
+// isBlocked returns whether or not the provided address matches any of the
+// registered blocklists.
+//
+// This function MUST be called with the address manager lock held.
+func (a *AddrManager) isBlocked(na *wire.NetAddress) bool {
+	for _, b := range a.blocklists {
+		if b.Contains(na) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeBlocked forgets all known addresses that match any of the registered
+// blocklists.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) removeBlocked() {
+	if len(a.blocklists) == 0 {
+		return
+	}
+	var removed int
+	for _, ka := range a.addrIndex {
+		if a.isBlocked(ka.na) {
+			a.removeAddress(ka)
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.Infof("Removed %d blocklisted addresses", removed)
+	}
+}
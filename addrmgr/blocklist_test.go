@@ -0,0 +1,79 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestBlocklist ensures blocklists are parsed and loaded correctly, and that
+// blocklisted addresses are dropped and forgotten by the address manager.
+func TestBlocklist(t *testing.T) {
+	// Ensure invalid rules are rejected.
+	invalid := []string{"12.1.2.0/33", "12.1.2", "[.onion", "host.com"}
+	for _, rule := range invalid {
+		if _, err := ParseBlocklist([]string{rule}); err == nil {
+			t.Errorf("invalid rule %q was accepted", rule)
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "testblocklist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "blocklist.txt")
+	contents := "# Threat intel\n12.1.0.0/16\n\n2602:100::1 # host\n" +
+		"76qxxcoe*.onion\n"
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("unable to write blocklist: %v", err)
+	}
+	b, err := LoadBlocklist(path)
+	if err != nil {
+		t.Fatalf("unable to load blocklist: %v", err)
+	}
+
+	newNA := func(ip string) *wire.NetAddress {
+		return wire.NewNetAddressIPPort(net.ParseIP(ip), 9108, 0)
+	}
+	tests := []struct {
+		ip      string
+		blocked bool
+	}{
+		{"12.1.2.3", true},
+		{"12.2.2.3", false},
+		{"2602:100::1", true},
+		{"2602:100::2", false},
+		{"fd87:d87e:eb43:ffa1:7b89:c4d1:5a48:d5a4", true},
+		{"fd87:d87e:eb43:10a1:7b89:c4d1:5a48:d5a4", false},
+	}
+	for _, test := range tests {
+		if got := b.Contains(newNA(test.ip)); got != test.blocked {
+			t.Errorf("%s (%s): unexpected blocked state -- got %v, "+
+				"want %v", test.ip, ipString(newNA(test.ip)), got,
+				test.blocked)
+		}
+	}
+
+	// Ensure known addresses that match the blocklist are forgotten once it
+	// is added and that matching addresses are no longer stored.
+	n := New(dir, nil)
+	src := newNA("13.1.2.3")
+	n.AddAddresses([]*wire.NetAddress{newNA("12.1.2.3"),
+		newNA("12.2.2.3")}, src)
+	n.AddBlocklist(b)
+	n.AddAddresses([]*wire.NetAddress{newNA("12.1.3.3"),
+		newNA("2602:100::1")}, src)
+	if n.numAddresses() != 1 || n.find(newNA("12.2.2.3")) == nil {
+		t.Fatalf("unexpected number of addresses -- got %d, want 1",
+			n.numAddresses())
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"time"
+)
+
+const (
+	// maxTriedCollisions is the maximum number of tried bucket collisions
+	// that are tracked while testing before evicting.  Further collisions
+	// are ignored until some are resolved.
+	maxTriedCollisions = 10
+
+	// collisionKeepWindow is how recently the address that would be
+	// evicted by a tried bucket collision must have succeeded, or been
+	// attempted, to determine the outcome of its connectivity test.
+	collisionKeepWindow = 4 * time.Hour
+
+	// collisionTestGrace is how long after an attempt to connect to the
+	// address that would be evicted a collision is resolved, which allows
+	// time for a successful connection to be reported via Good.
+	collisionTestGrace = time.Minute
+
+	// collisionTimeout is how long a tried bucket collision is kept before
+	// the address that would be evicted is evicted without having been
+	// tested.
+	collisionTimeout = 40 * time.Minute
+)
+
+// addTriedCollision records a collision between the provided new address and
+// the address it would evict from its full tried bucket.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) addTriedCollision(ka *KnownAddress) {
+	key := ka.key()
+	if _, ok := a.triedCollisions[key]; ok {
+		return
+	}
+	if len(a.triedCollisions) >= maxTriedCollisions {
+		return
+	}
+	a.triedCollisions[key] = a.cfg.now()
+	log.Tracef("Recorded tried bucket collision for %s", key)
+}
+
+// triedCollision returns the new address with the provided key along with the
+// address it would evict from its tried bucket.  The evicted address is nil
+// when there is room in the tried bucket.  Both are nil, and the collision is
+// no longer tracked, when the new address is no longer known or already tried.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) triedCollision(key string) (*KnownAddress, *KnownAddress) {
+	ka := a.addrIndex[key]
+	if ka == nil || ka.tried {
+		delete(a.triedCollisions, key)
+		return nil, nil
+	}
+	bucket := a.knownTriedBucket(ka)
+	if len(a.addrTried[bucket]) < a.cfg.TriedBucketSize {
+		return ka, nil
+	}
+	return ka, a.addrTried[bucket][a.pickTried(bucket)]
+}
+
+// SelectTriedCollision returns an address that would be evicted from the tried
+// buckets by a pending collision so a feeler connection to it can test whether
+// it is still reachable.  The outcome of the test should be reported via Good
+// or FeelerAttempt before calling ResolveCollisions.  It returns nil when there
+// are no pending collisions.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) SelectTriedCollision() *KnownAddress {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	for key := range a.triedCollisions {
+		if _, incumbent := a.triedCollision(key); incumbent != nil {
+			return incumbent
+		}
+	}
+	return nil
+}
+
+// ResolveCollisions resolves the pending tried bucket collisions that were
+// recorded by Good when testing before evicting.  The new address is moved to
+// the tried buckets, evicting the address it collides with, when that address
+// was recently tested and failed to connect or when it has not been tested in
+// time.  The collision is discarded without evicting anything when the address
+// it collides with has recently connected successfully.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) ResolveCollisions() {
+	defer a.sendEvents()
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	now := a.cfg.now()
+	for key, recorded := range a.triedCollisions {
+		ka, incumbent := a.triedCollision(key)
+		if ka == nil {
+			continue
+		}
+		if incumbent == nil {
+			// There is room in the tried bucket now.
+			a.moveToTried(ka)
+			delete(a.triedCollisions, key)
+			continue
+		}
+
+		incumbent.mtx.Lock()
+		lastSuccess := incumbent.lastsuccess
+		lastTry := incumbent.lastattempt
+		if incumbent.lastfeeler.After(lastTry) {
+			lastTry = incumbent.lastfeeler
+		}
+		incumbent.mtx.Unlock()
+
+		switch {
+		case now.Sub(lastSuccess) < collisionKeepWindow:
+			// Keep the incumbent since it recently connected.
+			log.Tracef("Keeping %s in tried over %s",
+				incumbent.key(), key)
+			delete(a.triedCollisions, key)
+
+		case now.Sub(lastTry) < collisionKeepWindow:
+			// Evict the incumbent once it has had time to connect
+			// since it was recently attempted without success.
+			if now.Sub(lastTry) > collisionTestGrace {
+				a.moveToTried(ka)
+				delete(a.triedCollisions, key)
+			}
+
+		case now.Sub(recorded) > collisionTimeout:
+			// Evict the incumbent since it was not tested in time.
+			a.moveToTried(ka)
+			delete(a.triedCollisions, key)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestTestBeforeEvict ensures addresses are only evicted from full tried
+// buckets once the collision is resolved according to the outcome of testing
+// the address that would be evicted.
+func TestTestBeforeEvict(t *testing.T) {
+	// Use a single tried bucket with room for one address so every
+	// promotion after the first collides.
+	n := NewWithConfig(&Config{
+		TriedBucketCount: 1,
+		TriedBucketSize:  1,
+		TestBeforeEvict:  true,
+	})
+	newNA := func(ip string) *wire.NetAddress {
+		return wire.NewNetAddressIPPort(net.ParseIP(ip), 9108, 0)
+	}
+	incumbent := newNA("12.1.2.3")
+	challenger := newNA("13.1.2.3")
+	n.AddAddresses([]*wire.NetAddress{incumbent, challenger}, incumbent)
+	n.Good(incumbent)
+
+	// Ensure the colliding address stays in the new buckets and the
+	// incumbent is selected for testing.
+	n.Good(challenger)
+	if n.find(challenger).tried || !n.find(incumbent).tried {
+		t.Fatal("tried address was evicted before being tested")
+	}
+	ka := n.SelectTriedCollision()
+	if ka == nil || NetAddressKey(ka.NetAddress()) != "12.1.2.3:9108" {
+		t.Fatalf("unexpected collision address %v", ka)
+	}
+
+	// Ensure the incumbent is kept when it recently connected.
+	n.ResolveCollisions()
+	if n.find(challenger).tried || len(n.triedCollisions) != 0 {
+		t.Fatal("recently connected tried address was evicted")
+	}
+
+	// Ensure the incumbent is evicted when it recently failed a test.
+	n.Good(challenger)
+	old := n.find(incumbent)
+	old.lastsuccess = time.Now().Add(-2 * collisionKeepWindow)
+	old.lastattempt = old.lastsuccess
+	n.FeelerAttempt(incumbent)
+	n.ResolveCollisions()
+	if n.find(challenger).tried {
+		t.Fatal("collision was resolved before the test grace period")
+	}
+	old.lastfeeler = time.Now().Add(-2 * collisionTestGrace)
+	n.ResolveCollisions()
+	if !n.find(challenger).tried || n.find(incumbent).tried {
+		t.Fatal("tried address that failed its test was not evicted")
+	}
+
+	// Ensure an untested incumbent is evicted once the collision times out.
+	third := newNA("14.1.2.3")
+	n.AddAddress(third, third)
+	n.Good(third)
+	n.find(challenger).lastsuccess = time.Time{}
+	n.find(challenger).lastattempt = time.Time{}
+	n.ResolveCollisions()
+	if n.find(third).tried {
+		t.Fatal("collision was resolved before timing out")
+	}
+	n.triedCollisions["14.1.2.3:9108"] = time.Now().Add(-2 * collisionTimeout)
+	n.ResolveCollisions()
+	if !n.find(third).tried || n.find(challenger).tried {
+		t.Fatal("untested tried address was not evicted after timeout")
+	}
+	if ka := n.SelectTriedCollision(); ka != nil {
+		t.Fatalf("unexpected collision address %v", ka.NetAddress())
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"github.com/decred/dcrd/wire"
+)
+
+// AddOutbound records an outbound connection to the given address so the
+// group it belongs to is considered represented by SuggestDiverseAddress.
+// Every call must be paired with a call to RemoveOutbound once the connection
+// is closed.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) AddOutbound(na *wire.NetAddress) {
+	key := a.GroupKey(na)
+
+	a.mtx.Lock()
+	a.outboundGroups[key]++
+	a.mtx.Unlock()
+}
+
+// RemoveOutbound removes an outbound connection to the given address that was
+// previously recorded with AddOutbound.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) RemoveOutbound(na *wire.NetAddress) {
+	key := a.GroupKey(na)
+
+	a.mtx.Lock()
+	if a.outboundGroups[key] <= 1 {
+		delete(a.outboundGroups, key)
+	} else {
+		a.outboundGroups[key]--
+	}
+	a.mtx.Unlock()
+}
+
+// OutboundGroupCount returns the number of outbound connections recorded with
+// AddOutbound to addresses in the group with the given key.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) OutboundGroupCount(key string) int {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	return a.outboundGroups[key]
+}
+
+// SuggestDiverseAddress returns a single address that should be routable for a
+// new outbound connection.  It is picked the same way as GetAddress, however,
+// addresses in groups that no outbound connection is recorded for are selected
+// when there are any.  This makes it harder for an adversary that controls many
+// addresses in the same network segment to occupy all of the outbound
+// connections.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) SuggestDiverseAddress() *KnownAddress {
+	unrepresented := func(ka *KnownAddress) bool {
+		return a.outboundGroups[a.groupOf(ka)] == 0
+	}
+	if ka := a.getAddressMatching(unrepresented); ka != nil {
+		return ka
+	}
+	return a.GetAddress()
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestSuggestDiverseAddress ensures outbound groups are tracked and addresses
+// in groups without outbound connections are suggested.
+func TestSuggestDiverseAddress(t *testing.T) {
+	n := New("testsuggestdiverseaddress", nil)
+	if ka := n.SuggestDiverseAddress(); ka != nil {
+		t.Fatalf("unexpected address %v", ka.NetAddress())
+	}
+
+	// Add many addresses in one group and a single address in another.
+	var addrs []*wire.NetAddress
+	for i := 0; i < 50; i++ {
+		ip := net.ParseIP(fmt.Sprintf("12.1.%d.1", i))
+		addrs = append(addrs, wire.NewNetAddressIPPort(ip, 9108, 0))
+	}
+	diverse := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	addrs = append(addrs, diverse)
+	n.AddAddresses(addrs, addrs[0])
+
+	// Ensure outbound connections are counted per group.
+	n.AddOutbound(addrs[0])
+	n.AddOutbound(addrs[1])
+	key := n.GroupKey(addrs[0])
+	if got := n.OutboundGroupCount(key); got != 2 {
+		t.Fatalf("unexpected outbound group count -- got %d, want 2", got)
+	}
+
+	// Ensure only the address in the unrepresented group is suggested.
+	for i := 0; i < 20; i++ {
+		ka := n.SuggestDiverseAddress()
+		if ka == nil || NetAddressKey(ka.NetAddress()) != "13.1.2.3:9108" {
+			t.Fatalf("unexpected suggested address %v", ka)
+		}
+	}
+
+	// Ensure an address is still suggested once every group is represented.
+	n.AddOutbound(diverse)
+	if ka := n.SuggestDiverseAddress(); ka == nil {
+		t.Fatal("no address suggested with all groups represented")
+	}
+
+	// Ensure removing outbound connections updates the counts.
+	n.RemoveOutbound(addrs[0])
+	n.RemoveOutbound(addrs[1])
+	if got := n.OutboundGroupCount(key); got != 0 {
+		t.Fatalf("unexpected outbound group count -- got %d, want 0", got)
+	}
+}
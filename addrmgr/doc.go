@@ -35,5 +35,10 @@ provided by the caller about connected, known good, and attempted addresses to
 periodically purge peers which no longer appear to be good peers as well as
 bias the selection toward known good peers.  The general idea is to make a best
 effort at only providing usable addresses.
+
+Finally, the address manager can save a small number of anchor addresses when
+it is stopped and provide them back when it is next started.  Callers use this
+to reconnect to peers that were recently known to relay blocks, which makes it
+harder for an attacker to take advantage of a restart to eclipse the node.
 */
 package addrmgr
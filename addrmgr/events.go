@@ -0,0 +1,109 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// EventType identifies a type of address lifecycle event.
+type EventType int
+
+// Constants for the type of an address lifecycle event.
+const (
+	// EventAdded indicates a previously unknown address was added to the
+	// new buckets.
+	EventAdded EventType = iota
+
+	// EventUpdated indicates the last seen time or services of a known
+	// address were updated.
+	EventUpdated
+
+	// EventPromoted indicates an address was moved from the new buckets to
+	// the tried buckets after a successful connection.
+	EventPromoted
+
+	// EventEvicted indicates an address was evicted to make room for
+	// another address.  Addresses evicted from the tried buckets are moved
+	// back to the new buckets while addresses evicted from the new buckets
+	// are forgotten.
+	EventEvicted
+
+	// EventExpired indicates an address was forgotten because it is no
+	// longer considered usable.
+	EventExpired
+)
+
+// eventTypeStrings is a map of address lifecycle event types back to their
+// constant names for pretty printing.
+var eventTypeStrings = map[EventType]string{
+	EventAdded:    "EventAdded",
+	EventUpdated:  "EventUpdated",
+	EventPromoted: "EventPromoted",
+	EventEvicted:  "EventEvicted",
+	EventExpired:  "EventExpired",
+}
+
+// String returns the EventType in human-readable form.
+func (t EventType) String() string {
+	if s, ok := eventTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown Event Type (%d)", int(t))
+}
+
+// Event describes a change to an address known to the address manager.
+type Event struct {
+	Type EventType
+	Addr *wire.NetAddress
+}
+
+// EventCallback is used by callers to receive address lifecycle events.
+type EventCallback func(*Event)
+
+// Subscribe registers the provided callback to receive address lifecycle
+// events.  The callbacks are invoked synchronously, in the order the events
+// occurred, after the address manager has released its lock, so they may call
+// back into the address manager.  However, they should return quickly since
+// they delay the caller that triggered the events.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) Subscribe(callback EventCallback) {
+	a.mtx.Lock()
+	a.subscribers = append(a.subscribers, callback)
+	a.mtx.Unlock()
+}
+
+// notify records an event of the provided type for the provided address in the
+// metrics and queues it to be sent to the subscribers by sendEvents.  Nothing
+// is queued when there are no subscribers.
+//
+// This function MUST be called with the address manager lock held.
+func (a *AddrManager) notify(typ EventType, na *wire.NetAddress) {
+	a.metrics.recordEvent(typ)
+	if len(a.subscribers) == 0 {
+		return
+	}
+	a.events = append(a.events, &Event{Type: typ, Addr: na})
+}
+
+// sendEvents sends any queued events to the subscribers.
+//
+// This function MUST NOT be called with the address manager lock held.
+func (a *AddrManager) sendEvents() {
+	a.mtx.Lock()
+	events := a.events
+	a.events = nil
+	subscribers := a.subscribers
+	a.mtx.Unlock()
+
+	for _, event := range events {
+		for _, callback := range subscribers {
+			callback(event)
+		}
+	}
+}
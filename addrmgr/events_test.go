@@ -0,0 +1,81 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestEvents ensures subscribers are notified of the expected address lifecycle
+// events.
+func TestEvents(t *testing.T) {
+	// Use a single small bucket of each kind so addresses are evicted and
+	// expired deterministically.
+	n := NewWithConfig(&Config{
+		NewBucketCount:   1,
+		NewBucketSize:    1,
+		TriedBucketCount: 1,
+		TriedBucketSize:  1,
+	})
+
+	type event struct {
+		typ  EventType
+		addr string
+	}
+	var events []event
+	n.Subscribe(func(e *Event) {
+		// Ensure the address manager may be called from the callback.
+		n.NeedMoreAddresses()
+		events = append(events, event{e.Type, NetAddressKey(e.Addr)})
+	})
+
+	now := time.Now()
+	newNA := func(ip string, timestamp time.Time) *wire.NetAddress {
+		na := wire.NewNetAddressIPPort(net.ParseIP(ip), 9108,
+			wire.SFNodeNetwork)
+		na.Timestamp = timestamp
+		return na
+	}
+	a := newNA("12.1.2.3", now.Add(-time.Hour))
+	b := newNA("13.1.2.3", now.Add(-60*24*time.Hour))
+	c := newNA("14.1.2.3", now.Add(-2*time.Hour))
+	d := newNA("15.1.2.3", now)
+
+	n.AddAddress(a, a)
+	n.AddAddress(newNA("12.1.2.3", now), a)
+	n.Good(a)
+	n.AddAddress(b, b)
+	n.AddAddress(c, c)
+	n.AddAddress(d, d)
+	n.Good(d)
+
+	want := []event{
+		{EventAdded, "12.1.2.3:9108"},
+		{EventUpdated, "12.1.2.3:9108"},
+		{EventPromoted, "12.1.2.3:9108"},
+		{EventAdded, "13.1.2.3:9108"},
+		{EventAdded, "14.1.2.3:9108"},
+		{EventAdded, "15.1.2.3:9108"},
+		{EventExpired, "13.1.2.3:9108"},
+		{EventEvicted, "14.1.2.3:9108"},
+		{EventPromoted, "15.1.2.3:9108"},
+		{EventEvicted, "12.1.2.3:9108"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("unexpected number of events -- got %d, want %d: %v",
+			len(events), len(want), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event #%d: got %v %s, want %v %s", i,
+				events[i].typ, events[i].addr, want[i].typ,
+				want[i].addr)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// AddrManagerer defines the methods of an address manager that are used to
+// learn, select, and track the outcome of connections to peer addresses.  It
+// allows consumers to substitute their own implementation, such as a mock in
+// their tests.  AddrManager is the implementation for use in practice.
+type AddrManagerer interface {
+	// AddAddresses adds new addresses to the address manager.
+	AddAddresses(addrs []*wire.NetAddress, srcAddr *wire.NetAddress)
+
+	// AddSeedAddresses adds addresses returned by a seeder to a quarantine
+	// tier until they are confirmed by an independent source.
+	AddSeedAddresses(srcAddr *wire.NetAddress, addrs []*wire.NetAddress)
+
+	// AddAddress adds a new address to the address manager.
+	AddAddress(addr, srcAddr *wire.NetAddress)
+
+	// NeedMoreAddresses returns whether or not the address manager needs
+	// more addresses.
+	NeedMoreAddresses() bool
+
+	// AddressCache returns a randomized subset of the known addresses that
+	// are suitable to share with peers.
+	AddressCache() []*wire.NetAddress
+
+	// GetAddress returns a single address that should be routable or nil
+	// when there are no known addresses.
+	GetAddress() *KnownAddress
+
+	// SuggestDiverseAddress returns a single address that should be
+	// routable and preferably belongs to a group without an outbound
+	// connection or nil when there are no known addresses.
+	SuggestDiverseAddress() *KnownAddress
+
+	// Attempt records a connection attempt to the given address.
+	Attempt(addr *wire.NetAddress)
+
+	// Connected marks the given address as currently connected.
+	Connected(addr *wire.NetAddress)
+
+	// Good marks the given address as good after a successful connection
+	// and version exchange.
+	Good(addr *wire.NetAddress)
+
+	// ServedPoorly records that the peer at the given address served
+	// poorly.
+	ServedPoorly(addr *wire.NetAddress)
+
+	// RecordConnectionStats records the latency and uptime of a closed
+	// connection to the given address.
+	RecordConnectionStats(addr *wire.NetAddress, latency, uptime time.Duration)
+
+	// SetLastBlock sets the height of the last block advertised by the
+	// peer at the given address along with the height of the local best
+	// chain.
+	SetLastBlock(addr *wire.NetAddress, height, chainHeight int64)
+
+	// SetServices sets the services advertised by the peer at the given
+	// address.
+	SetServices(addr *wire.NetAddress, services wire.ServiceFlag)
+
+	// SetUserAgent sets the user agent and protocol version advertised by
+	// the peer at the given address.
+	SetUserAgent(addr *wire.NetAddress, userAgent string, protocolVersion uint32)
+
+	// AddKnownAddresses marks the given addresses as known to the peer
+	// with the given id.
+	AddKnownAddresses(peerID int32, addrs []*wire.NetAddress)
+
+	// UnknownAddresses returns the given addresses that are not yet known
+	// to the peer with the given id.
+	UnknownAddresses(peerID int32, addrs []*wire.NetAddress) []*wire.NetAddress
+
+	// ForgetKnownAddresses forgets the addresses known to the peer with
+	// the given id.
+	ForgetKnownAddresses(peerID int32)
+
+	// AddOutbound records an outbound connection to the given address.
+	AddOutbound(na *wire.NetAddress)
+
+	// RemoveOutbound removes an outbound connection to the given address
+	// that was previously recorded with AddOutbound.
+	RemoveOutbound(na *wire.NetAddress)
+
+	// OutboundGroupCount returns the number of outbound connections to
+	// addresses in the group with the given key.
+	OutboundGroupCount(key string) int
+
+	// GroupKey returns the key of the group the given address belongs to.
+	GroupKey(na *wire.NetAddress) string
+
+	// IsRoutable returns whether or not the given address is routable.
+	IsRoutable(na *wire.NetAddress) bool
+
+	// BanAddress bans the host of the given address for the given
+	// duration.
+	BanAddress(na *wire.NetAddress, duration time.Duration)
+
+	// IsBanned returns whether or not the host of the given address is
+	// banned.
+	IsBanned(na *wire.NetAddress) bool
+
+	// AddLocalAddress adds an address that the local node is reachable at
+	// with the given priority.
+	AddLocalAddress(na *wire.NetAddress, priority AddressPriority) error
+
+	// AddLocalAddressWithExpiry adds an address that the local node is
+	// reachable at with the given priority until the given lease expires.
+	AddLocalAddressWithExpiry(na *wire.NetAddress, priority AddressPriority, ttl time.Duration, refresh LocalAddressRefreshFunc) error
+
+	// GetBestLocalAddress returns the most appropriate local address to
+	// advertise to the given remote address.
+	GetBestLocalAddress(remoteAddr *wire.NetAddress) *wire.NetAddress
+
+	// IsExternalAddrCandidate returns whether or not the given local
+	// address suggested by the given remote peer is a candidate for the
+	// external address of the local node.
+	IsExternalAddrCandidate(localAddr, remoteAddr *wire.NetAddress) bool
+
+	// HostToNetAddress returns a network address for the given host, which
+	// may be an IP address, a Tor onion address, or a host name to look up.
+	HostToNetAddress(host string, port uint16, services wire.ServiceFlag) (*wire.NetAddress, error)
+}
+
+// Ensure AddrManager implements the AddrManagerer interface.
+var _ AddrManagerer = (*AddrManager)(nil)
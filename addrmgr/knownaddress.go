@@ -6,6 +6,8 @@
 package addrmgr
 
 import (
+	"container/list"
+	"math"
 	"sync"
 	"time"
 
@@ -21,8 +23,137 @@ type KnownAddress struct {
 	attempts    int
 	lastattempt time.Time
 	lastsuccess time.Time
+	lastfeeler  time.Time // last feeler attempt, which is not persisted
 	tried       bool
 	refs        int // reference count of new buckets
+	poorService int // number of times the peer served poorly
+
+	// userAgent and protocolVersion are those most recently advertised by
+	// the peer at the address.
+	userAgent       string
+	protocolVersion uint32
+
+	// totalAttempts and totalSuccesses are the cumulative number of
+	// connection attempts and successful connections, latency is a rolling
+	// average of the connection latency, and uptime is the cumulative time
+	// connected to the peer at the address.
+	totalAttempts  int
+	totalSuccesses int
+	latency        time.Duration
+	uptime         time.Duration
+
+	// lastBlock is the height of the last block advertised by the peer at
+	// the address and blocksBehind is how far it was behind the local best
+	// chain at the time.
+	lastBlock    int64
+	blocksBehind int64
+
+	// lruElem is the element of the address in the list of addresses in
+	// the new buckets ordered by use when the number of addresses is
+	// limited.
+	lruElem *list.Element
+
+	// cachedKey, cachedGroup, and cachedSrcGroup are the key of the
+	// address and the groups of the address and its source.  They are
+	// cached since they are needed frequently and are costly to compute.
+	// The groups depend on the asmap in use, so they are cleared when it
+	// changes.
+	cachedKey      string
+	cachedGroup    string
+	cachedSrcGroup string
+}
+
+// key returns the key of the address as returned by NetAddressKey.  It is
+// computed once and cached since it does not change when the address is
+// updated.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (ka *KnownAddress) key() string {
+	if ka.cachedKey == "" {
+		ka.cachedKey = NetAddressKey(ka.na)
+	}
+	return ka.cachedKey
+}
+
+// KnownAddressSnapshot is a copy of the information tracked about a known
+// network address at the time it was looked up.  It is safe to retain and
+// inspect without affecting the address manager.
+type KnownAddressSnapshot struct {
+	// Addr is the network address and Source is the address of the peer
+	// it was learned from.
+	Addr   wire.NetAddress
+	Source wire.NetAddress
+
+	// Attempts is the number of connection attempts since the last success.
+	Attempts int
+
+	// LastAttempt, LastSuccess, and LastFeelerAttempt are the times of the
+	// last connection attempt, successful connection, and feeler connection
+	// attempt, respectively.  They are the zero time when there are none.
+	LastAttempt       time.Time
+	LastSuccess       time.Time
+	LastFeelerAttempt time.Time
+
+	// Tried is whether the address is in the tried buckets.  Otherwise,
+	// NewBucketRefs is the number of new buckets the address is in.
+	Tried         bool
+	NewBucketRefs int
+
+	// PoorService is the number of times the peer at the address served
+	// poorly.
+	PoorService int
+
+	// UserAgent and ProtocolVersion are those most recently advertised by
+	// the peer at the address.
+	UserAgent       string
+	ProtocolVersion uint32
+
+	// TotalAttempts and TotalSuccesses are the cumulative number of
+	// connection attempts and successful connections.
+	TotalAttempts  int
+	TotalSuccesses int
+
+	// Latency is the rolling average connection latency and Uptime is the
+	// cumulative time connected to the peer at the address.
+	Latency time.Duration
+	Uptime  time.Duration
+
+	// LastBlock is the height of the last block advertised by the peer at
+	// the address.  It is zero when unknown.
+	LastBlock int64
+}
+
+// snapshot returns a copy of the information tracked about the known address.
+func (ka *KnownAddress) snapshot() *KnownAddressSnapshot {
+	ka.mtx.Lock()
+	defer ka.mtx.Unlock()
+	return &KnownAddressSnapshot{
+		Addr:              *ka.na,
+		Source:            *ka.srcAddr,
+		Attempts:          ka.attempts,
+		LastAttempt:       ka.lastattempt,
+		LastSuccess:       ka.lastsuccess,
+		LastFeelerAttempt: ka.lastfeeler,
+		Tried:             ka.tried,
+		NewBucketRefs:     ka.refs,
+		PoorService:       ka.poorService,
+		UserAgent:         ka.userAgent,
+		ProtocolVersion:   ka.protocolVersion,
+		TotalAttempts:     ka.totalAttempts,
+		TotalSuccesses:    ka.totalSuccesses,
+		Latency:           ka.latency,
+		Uptime:            ka.uptime,
+		LastBlock:         ka.lastBlock,
+	}
+}
+
+// NewKnownAddress returns a known address for the given network address that
+// was learned from the given source address.  It is primarily useful for
+// implementations of AddrManagerer that are not backed by an address manager,
+// such as mocks.
+func NewKnownAddress(na, srcAddr *wire.NetAddress) *KnownAddress {
+	return &KnownAddress{na: na, srcAddr: srcAddr}
 }
 
 // NetAddress returns the underlying wire.NetAddress associated with the
@@ -40,13 +171,39 @@ func (ka *KnownAddress) LastAttempt() time.Time {
 	return ka.lastattempt
 }
 
+// LastFeelerAttempt returns the last time a feeler connection to the known
+// address was attempted since the address manager was started.
+func (ka *KnownAddress) LastFeelerAttempt() time.Time {
+	ka.mtx.Lock()
+	defer ka.mtx.Unlock()
+	return ka.lastfeeler
+}
+
+// UserAgent returns the user agent most recently advertised by the peer at the
+// known address or an empty string if it has never been connected to.
+func (ka *KnownAddress) UserAgent() string {
+	ka.mtx.Lock()
+	defer ka.mtx.Unlock()
+	return ka.userAgent
+}
+
+// ProtocolVersion returns the protocol version most recently advertised by the
+// peer at the known address or zero if it has never been connected to.
+func (ka *KnownAddress) ProtocolVersion() uint32 {
+	ka.mtx.Lock()
+	defer ka.mtx.Unlock()
+	return ka.protocolVersion
+}
+
 // chance returns the selection probability for a known address.  The priority
 // depends upon how recently the address has been seen, how recently it was last
-// attempted and how often attempts to connect to it have failed.
-func (ka *KnownAddress) chance() float64 {
+// attempted relative to the provided current time, how often attempts to
+// connect to it have failed, how often the peer has served poorly, the
+// historical reliability, uptime, and latency of connections to it, and whether
+// the peer was far behind the local best chain.
+func (ka *KnownAddress) chance(now time.Time) float64 {
 	ka.mtx.Lock()
 	defer ka.mtx.Unlock()
-	now := time.Now()
 	lastAttempt := now.Sub(ka.lastattempt)
 
 	if lastAttempt < 0 {
@@ -65,21 +222,48 @@ func (ka *KnownAddress) chance() float64 {
 		c /= 1.5
 	}
 
+	// Poor service deprioritises.
+	for i := ka.poorService; i > 0; i-- {
+		c /= 2
+	}
+
+	// Historically reliable peers are favoured by scaling the chance by
+	// between 0.5 and 1.5 according to the ratio of successful connections.
+	if ka.totalAttempts > 0 {
+		ratio := float64(ka.totalSuccesses) / float64(ka.totalAttempts)
+		c *= 0.5 + math.Min(ratio, 1)
+	}
+
+	// Long cumulative uptime prioritises up to 1.5 times.
+	if ka.uptime > 0 {
+		c *= 1 + math.Min(float64(ka.uptime)/float64(uptimeBonusPeriod), 1)/2
+	}
+
+	// High latency deprioritises, halving the chance at one second.
+	if ka.latency > 0 {
+		c /= 1 + ka.latency.Seconds()
+	}
+
+	// Peers that were far behind the best chain deprioritise.
+	if ka.blocksBehind > farBehindBlocks {
+		c /= 4
+	}
+
 	return c
 }
 
 // isBad returns true if the address in question has not been tried in the last
 // minute and meets one of the following criteria:
 // 1) It claims to be from the future
-// 2) It hasn't been seen in over a month
-// 3) It has failed at least three times and never succeeded
-// 4) It has failed a total of maxFailures in the last week
+// 2) It hasn't been seen in over NumMissingDays
+// 3) It has failed at least NumRetries times and never succeeded
+// 4) It has failed a total of MaxFailures in the last MinBadDays
 // All addresses that meet these criteria are assumed to be worthless and not
 // worth keeping hold of.
-func (ka *KnownAddress) isBad() bool {
+func (ka *KnownAddress) isBad(cfg *Config) bool {
 	ka.mtx.Lock()
 	defer ka.mtx.Unlock()
-	now := time.Now()
+	now := cfg.now()
 	if ka.lastattempt.After(now.Add(-1 * time.Minute)) {
 		return false
 	}
@@ -89,19 +273,21 @@ func (ka *KnownAddress) isBad() bool {
 		return true
 	}
 
-	// Over a month old?
-	if ka.na.Timestamp.Before(now.Add(-1 * numMissingDays * time.Hour * 24)) {
+	// Not seen in too long?
+	missingDays := time.Duration(cfg.NumMissingDays)
+	if ka.na.Timestamp.Before(now.Add(-1 * missingDays * time.Hour * 24)) {
 		return true
 	}
 
 	// Never succeeded?
-	if ka.lastsuccess.IsZero() && ka.attempts >= numRetries {
+	if ka.lastsuccess.IsZero() && ka.attempts >= cfg.NumRetries {
 		return true
 	}
 
 	// Hasn't succeeded in too long?
-	if !ka.lastsuccess.After(now.Add(-1*minBadDays*time.Hour*24)) &&
-		ka.attempts >= maxFailures {
+	badDays := time.Duration(cfg.MinBadDays)
+	if !ka.lastsuccess.After(now.Add(-1*badDays*time.Hour*24)) &&
+		ka.attempts >= cfg.MaxFailures {
 		return true
 	}
 
@@ -7,6 +7,7 @@ package addrmgr
 
 import (
 	"math"
+	"net"
 	"testing"
 	"time"
 
@@ -49,12 +50,18 @@ func TestChance(t *testing.T) {
 			newKnownAddress(&wire.NetAddress{Timestamp: now.Add(-35 * time.Second)},
 				2, now.Add(-30*time.Minute), now, false, 0),
 			1 / 1.5 / 1.5,
+		}, {
+			// Test case with poor service.
+			&KnownAddress{na: &wire.NetAddress{Timestamp: now.Add(-35 * time.Second)},
+				lastattempt: now.Add(-30 * time.Minute), lastsuccess: now,
+				poorService: 2},
+			1.0 / 2 / 2,
 		},
 	}
 
 	err := .0001
 	for i, test := range tests {
-		chance := test.addr.chance()
+		chance := test.addr.chance(time.Now())
 		if math.Abs(test.expected-chance) >= err {
 			t.Errorf("case %d: got %f, expected %f", i, chance, test.expected)
 		}
@@ -69,6 +76,8 @@ func TestIsBad(t *testing.T) {
 	minutesOld := now.Add(-27 * time.Minute)
 	hoursOld := now.Add(-5 * time.Hour)
 	zeroTime := time.Time{}
+	var cfg Config
+	cfg.setDefaults()
 
 	futureNa := &wire.NetAddress{Timestamp: future}
 	minutesOldNa := &wire.NetAddress{Timestamp: minutesOld}
@@ -76,44 +85,58 @@ func TestIsBad(t *testing.T) {
 	currentNa := &wire.NetAddress{Timestamp: secondsOld}
 
 	// Test addresses that have been tried in the last minute.
-	if newKnownAddress(futureNa, 3, secondsOld, zeroTime, false, 0).isBad() {
+	if newKnownAddress(futureNa, 3, secondsOld, zeroTime, false, 0).isBad(&cfg) {
 		t.Errorf("test case 1: addresses that have been tried in the last minute are not bad.")
 	}
-	if newKnownAddress(monthOldNa, 3, secondsOld, zeroTime, false, 0).isBad() {
+	if newKnownAddress(monthOldNa, 3, secondsOld, zeroTime, false, 0).isBad(&cfg) {
 		t.Errorf("test case 2: addresses that have been tried in the last minute are not bad.")
 	}
-	if newKnownAddress(currentNa, 3, secondsOld, zeroTime, false, 0).isBad() {
+	if newKnownAddress(currentNa, 3, secondsOld, zeroTime, false, 0).isBad(&cfg) {
 		t.Errorf("test case 3: addresses that have been tried in the last minute are not bad.")
 	}
-	if newKnownAddress(currentNa, 3, secondsOld, monthOld, true, 0).isBad() {
+	if newKnownAddress(currentNa, 3, secondsOld, monthOld, true, 0).isBad(&cfg) {
 		t.Errorf("test case 4: addresses that have been tried in the last minute are not bad.")
 	}
-	if newKnownAddress(currentNa, 2, secondsOld, secondsOld, true, 0).isBad() {
+	if newKnownAddress(currentNa, 2, secondsOld, secondsOld, true, 0).isBad(&cfg) {
 		t.Errorf("test case 5: addresses that have been tried in the last minute are not bad.")
 	}
 
 	// Test address that claims to be from the future.
-	if !newKnownAddress(futureNa, 0, minutesOld, hoursOld, true, 0).isBad() {
+	if !newKnownAddress(futureNa, 0, minutesOld, hoursOld, true, 0).isBad(&cfg) {
 		t.Errorf("test case 6: addresses that claim to be from the future are bad.")
 	}
 
 	// Test address that has not been seen in over a month.
-	if !newKnownAddress(monthOldNa, 0, minutesOld, hoursOld, true, 0).isBad() {
+	if !newKnownAddress(monthOldNa, 0, minutesOld, hoursOld, true, 0).isBad(&cfg) {
 		t.Errorf("test case 7: addresses more than a month old are bad.")
 	}
 
 	// It has failed at least three times and never succeeded.
-	if !newKnownAddress(minutesOldNa, 3, minutesOld, zeroTime, true, 0).isBad() {
+	if !newKnownAddress(minutesOldNa, 3, minutesOld, zeroTime, true, 0).isBad(&cfg) {
 		t.Errorf("test case 8: addresses that have never succeeded are bad.")
 	}
 
 	// It has failed ten times in the last week
-	if !newKnownAddress(minutesOldNa, 10, minutesOld, monthOld, true, 0).isBad() {
+	if !newKnownAddress(minutesOldNa, 10, minutesOld, monthOld, true, 0).isBad(&cfg) {
 		t.Errorf("test case 9: addresses that have not succeeded in too long are bad.")
 	}
 
 	// Test an address that should work.
-	if newKnownAddress(minutesOldNa, 2, minutesOld, hoursOld, true, 0).isBad() {
+	if newKnownAddress(minutesOldNa, 2, minutesOld, hoursOld, true, 0).isBad(&cfg) {
 		t.Errorf("test case 10: This should be a valid address.")
 	}
 }
+
+// TestNewKnownAddress ensures known addresses created outside of an address
+// manager report the provided address and have never been attempted.
+func TestNewKnownAddress(t *testing.T) {
+	na := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	src := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	ka := NewKnownAddress(na, src)
+	if ka.NetAddress() != na {
+		t.Fatalf("unexpected address -- got %v, want %v", ka.NetAddress(), na)
+	}
+	if !ka.LastAttempt().IsZero() {
+		t.Fatalf("unexpected last attempt %v", ka.LastAttempt())
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"container/list"
+	"sort"
+)
+
+// lruTouch marks the provided address in the new buckets as the most recently
+// used one.  Addresses are used when they are added, announced again, or
+// attempted.  It does nothing unless the total number of addresses is limited.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) lruTouch(ka *KnownAddress) {
+	if a.lru == nil {
+		return
+	}
+	if ka.lruElem != nil {
+		a.lru.MoveToFront(ka.lruElem)
+		return
+	}
+	ka.lruElem = a.lru.PushFront(ka)
+}
+
+// lruRemove stops tracking the use of the provided address, such as when it is
+// moved to the tried buckets or forgotten.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) lruRemove(ka *KnownAddress) {
+	if a.lru == nil || ka.lruElem == nil {
+		return
+	}
+	a.lru.Remove(ka.lruElem)
+	ka.lruElem = nil
+}
+
+// lruReset starts tracking the use of the addresses in the new buckets from
+// scratch, ordering them by when they were last seen since the order they
+// were used in is not persisted, and evicts the least recently used ones that
+// exceed the maximum number of addresses.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) lruReset() {
+	if a.cfg.MaxAddresses <= 0 {
+		return
+	}
+	a.lru = list.New()
+	addrs := make([]*KnownAddress, 0, len(a.addrIndex))
+	for _, ka := range a.addrIndex {
+		ka.lruElem = nil
+		if !ka.tried {
+			addrs = append(addrs, ka)
+		}
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		return addrs[i].na.Timestamp.Before(addrs[j].na.Timestamp)
+	})
+	for _, ka := range addrs {
+		ka.lruElem = a.lru.PushFront(ka)
+	}
+	a.evictLRU()
+}
+
+// evictLRU forgets the least recently used addresses in the new buckets until
+// the total number of addresses no longer exceeds the maximum.  Addresses in
+// the tried buckets are never evicted.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) evictLRU() {
+	if a.lru == nil {
+		return
+	}
+	for a.numAddresses() > a.cfg.MaxAddresses {
+		elem := a.lru.Back()
+		if elem == nil {
+			return
+		}
+		ka := elem.Value.(*KnownAddress)
+		log.Tracef("Evicting least recently used address %s", ka.key())
+		a.removeAddress(ka)
+		a.notify(EventEvicted, ka.na)
+	}
+}
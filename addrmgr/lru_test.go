@@ -0,0 +1,97 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestMaxAddresses ensures the least recently used addresses in the new
+// buckets are evicted once the maximum number of addresses is exceeded and that
+// addresses in the tried buckets are kept.
+func TestMaxAddresses(t *testing.T) {
+	n := NewWithConfig(&Config{
+		DataDir:         "testmaxaddresses",
+		SourceRateLimit: -1,
+		MaxAddresses:    3,
+	})
+	addrs := make([]*wire.NetAddress, 6)
+	for i := range addrs {
+		ip := net.IPv4(12, byte(i+1), 2, 3)
+		addrs[i] = wire.NewNetAddressIPPort(ip, 9108, 0)
+	}
+
+	// Attempting the first address makes the second one the least recently
+	// used, so it is evicted when the fourth one is added.
+	n.AddAddresses(addrs[:3], addrs[0])
+	n.Attempt(addrs[0])
+	n.AddAddress(addrs[3], addrs[0])
+	if n.numAddresses() != 3 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 3",
+			n.numAddresses())
+	}
+	if n.HasAddress(addrs[1]) {
+		t.Fatal("least recently used address was not evicted")
+	}
+	for _, i := range []int{0, 2, 3} {
+		if !n.HasAddress(addrs[i]) {
+			t.Fatalf("address %d was evicted", i)
+		}
+	}
+
+	// Addresses in the tried buckets are never evicted.
+	n.Good(addrs[0])
+	n.AddAddresses(addrs[4:], addrs[0])
+	if n.numAddresses() != 3 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 3",
+			n.numAddresses())
+	}
+	for _, i := range []int{0, 4, 5} {
+		if !n.HasAddress(addrs[i]) {
+			t.Fatalf("address %d was evicted", i)
+		}
+	}
+	if n.lru.Len() != 2 {
+		t.Fatalf("unexpected number of tracked addresses -- got %d, "+
+			"want 2", n.lru.Len())
+	}
+}
+
+// TestMemoryOnly ensures an address manager configured to be memory only does
+// not write anything to its data directory.
+func TestMemoryOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testmemoryonly")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := NewWithConfig(&Config{DataDir: dir, MemoryOnly: true})
+	n.Start()
+	addr := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	banned := wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0)
+	n.AddAddress(addr, addr)
+	n.BanAddress(banned, time.Hour)
+	n.SetAnchors([]*wire.NetAddress{addr})
+	n.Flush()
+	if err := n.Stop(); err != nil {
+		t.Fatalf("unable to stop address manager: %v", err)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unable to read data dir: %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("memory only address manager wrote %d files",
+			len(files))
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"time"
+)
+
+// Metrics is a snapshot of statistics about the health of an address manager.
+type Metrics struct {
+	// NewAddresses and TriedAddresses are the number of addresses in the
+	// new and tried buckets, respectively.
+	NewAddresses   int
+	TriedAddresses int
+
+	// NewBucketCounts and TriedBucketCounts are the number of addresses in
+	// each of the new and tried buckets, respectively.
+	NewBucketCounts   []int
+	TriedBucketCounts []int
+
+	// NewBucketSize and TriedBucketSize are the maximum number of addresses
+	// in each of the new and tried buckets, respectively.
+	NewBucketSize   int
+	TriedBucketSize int
+
+	// AddressesByNetwork is the number of known addresses by the type of
+	// network they belong to.
+	AddressesByNetwork map[NetworkAddress]int
+
+	// Attempts, Promotions, Evictions, and Expirations are the total number
+	// of connection attempts, promotions to the tried buckets, evictions,
+	// and expirations, respectively, since the address manager was created.
+	Attempts    uint64
+	Promotions  uint64
+	Evictions   uint64
+	Expirations uint64
+
+	// FeelerAttempts is the total number of feeler connection attempts
+	// since the address manager was created.  They are not included in
+	// Attempts.
+	FeelerAttempts uint64
+
+	// RateLimited is the total number of addresses that were dropped
+	// because their source exceeded its rate limit.
+	RateLimited uint64
+
+	// Saves is the total number of times the known addresses were saved to
+	// disk and SaveDuration is the total time taken to do so.
+	// LastSaveDuration is the time taken by the most recent save.
+	Saves            uint64
+	SaveDuration     time.Duration
+	LastSaveDuration time.Duration
+}
+
+// addrMetrics houses the counters of the address manager that are included in
+// its metrics.  They are protected by the address manager lock.
+type addrMetrics struct {
+	attempts         uint64
+	feelerAttempts   uint64
+	rateLimited      uint64
+	promotions       uint64
+	evictions        uint64
+	expirations      uint64
+	saves            uint64
+	saveDuration     time.Duration
+	lastSaveDuration time.Duration
+}
+
+// recordEvent updates the counters for the provided address lifecycle event
+// type.
+func (m *addrMetrics) recordEvent(typ EventType) {
+	switch typ {
+	case EventPromoted:
+		m.promotions++
+	case EventEvicted:
+		m.evictions++
+	case EventExpired:
+		m.expirations++
+	}
+}
+
+// recordSave updates the counters for a save of the known addresses that took
+// the provided amount of time.
+func (m *addrMetrics) recordSave(d time.Duration) {
+	m.saves++
+	m.saveDuration += d
+	m.lastSaveDuration = d
+}
+
+// Metrics returns a snapshot of statistics about the health of the address
+// manager.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) Metrics() *Metrics {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	m := &Metrics{
+		NewAddresses:       a.nNew,
+		TriedAddresses:     a.nTried,
+		NewBucketCounts:    make([]int, len(a.addrNew)),
+		TriedBucketCounts:  make([]int, len(a.addrTried)),
+		NewBucketSize:      a.cfg.NewBucketSize,
+		TriedBucketSize:    a.cfg.TriedBucketSize,
+		AddressesByNetwork: make(map[NetworkAddress]int),
+		Attempts:           a.metrics.attempts,
+		FeelerAttempts:     a.metrics.feelerAttempts,
+		RateLimited:        a.metrics.rateLimited,
+		Promotions:         a.metrics.promotions,
+		Evictions:          a.metrics.evictions,
+		Expirations:        a.metrics.expirations,
+		Saves:              a.metrics.saves,
+		SaveDuration:       a.metrics.saveDuration,
+		LastSaveDuration:   a.metrics.lastSaveDuration,
+	}
+	for i, bucket := range a.addrNew {
+		m.NewBucketCounts[i] = len(bucket)
+	}
+	for i, bucket := range a.addrTried {
+		m.TriedBucketCounts[i] = len(bucket)
+	}
+	for _, ka := range a.addrIndex {
+		m.AddressesByNetwork[getNetwork(ka.na)]++
+	}
+	return m
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestMetrics ensures the metrics snapshot reflects the state and activity of
+// the address manager.
+func TestMetrics(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testmetrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := New(dir, nil)
+	ipv4 := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	ipv6 := wire.NewNetAddressIPPort(net.ParseIP("2602:100::1"), 9108, 0)
+	onion := wire.NewNetAddressIPPort(net.ParseIP("fd87:d87e:eb43::1"),
+		9108, 0)
+	n.AddAddresses([]*wire.NetAddress{ipv4, ipv6, onion}, ipv4)
+	n.Attempt(ipv4)
+	n.Attempt(ipv6)
+	n.Good(ipv4)
+	n.savePeers()
+
+	m := n.Metrics()
+	if m.NewAddresses != 2 || m.TriedAddresses != 1 {
+		t.Fatalf("unexpected address counts -- got %d new %d tried, want "+
+			"2 new 1 tried", m.NewAddresses, m.TriedAddresses)
+	}
+	var newCount, triedCount int
+	for _, count := range m.NewBucketCounts {
+		newCount += count
+	}
+	for _, count := range m.TriedBucketCounts {
+		triedCount += count
+	}
+	if len(m.NewBucketCounts) != newBucketCount ||
+		len(m.TriedBucketCounts) != triedBucketCount ||
+		newCount != 2 || triedCount != 1 {
+
+		t.Fatalf("unexpected bucket counts -- got %d new buckets with %d "+
+			"addresses and %d tried buckets with %d addresses",
+			len(m.NewBucketCounts), newCount, len(m.TriedBucketCounts),
+			triedCount)
+	}
+	wantNetworks := map[NetworkAddress]int{
+		IPv4Address:  1,
+		IPv6Address:  1,
+		OnionAddress: 1,
+	}
+	for network, want := range wantNetworks {
+		if got := m.AddressesByNetwork[network]; got != want {
+			t.Errorf("unexpected %v address count -- got %d, want %d",
+				network, got, want)
+		}
+	}
+	if m.Attempts != 2 || m.Promotions != 1 || m.Saves != 1 {
+		t.Fatalf("unexpected counters -- got %d attempts, %d promotions, "+
+			"%d saves, want 2, 1, 1", m.Attempts, m.Promotions, m.Saves)
+	}
+	if m.LastSaveDuration <= 0 || m.SaveDuration != m.LastSaveDuration {
+		t.Fatalf("unexpected save durations %v and %v", m.SaveDuration,
+			m.LastSaveDuration)
+	}
+}
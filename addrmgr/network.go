@@ -85,6 +85,12 @@ var (
 	// { magic 6 bytes, 10 bytes base32 decode of key hash }
 	onionCatNet = ipNet("fd87:d87e:eb43::", 48, 128)
 
+	// cjdnsNet defines the IPv6 address block used by the cjdns encrypted
+	// mesh network (FC00::/8).  It is part of the RFC4193 unique local IPv6
+	// range, so addresses in it are only considered routable by address
+	// managers that are configured with CJDNSReachable.
+	cjdnsNet = ipNet("FC00::", 8, 128)
+
 	// zero4Net defines the IPv4 address block for address staring with 0
 	// (0.0.0.0/8).
 	zero4Net = ipNet("0.0.0.0", 8, 32)
@@ -118,6 +124,13 @@ func isOnionCatTor(na *wire.NetAddress) bool {
 	return onionCatNet.Contains(na.IP)
 }
 
+// isCJDNS returns whether or not the passed address is in the IPv6 range used
+// by cjdns (fc00::/8).  Note that this range is part of the RFC4193 unique local
+// IPv6 range.
+func isCJDNS(na *wire.NetAddress) bool {
+	return cjdnsNet.Contains(na.IP)
+}
+
 // NetworkAddress type is used to classify a network address.
 type NetworkAddress int
 
@@ -126,8 +139,27 @@ const (
 	IPv4Address
 	IPv6Address
 	OnionAddress
+	CJDNSAddress
 )
 
+// networkAddressStrings is a map of network address types back to their names
+// for pretty printing.
+var networkAddressStrings = map[NetworkAddress]string{
+	LocalAddress: "local",
+	IPv4Address:  "ipv4",
+	IPv6Address:  "ipv6",
+	OnionAddress: "onion",
+	CJDNSAddress: "cjdns",
+}
+
+// String returns the NetworkAddress in human-readable form.
+func (n NetworkAddress) String() string {
+	if s, ok := networkAddressStrings[n]; ok {
+		return s
+	}
+	return fmt.Sprintf("unknown network address type (%d)", int(n))
+}
+
 // getNetwork returns the network address type of the provided network address.
 func getNetwork(na *wire.NetAddress) NetworkAddress {
 	switch {
@@ -140,11 +172,19 @@ func getNetwork(na *wire.NetAddress) NetworkAddress {
 	case isOnionCatTor(na):
 		return OnionAddress
 
+	case isCJDNS(na):
+		return CJDNSAddress
+
 	default:
 		return IPv6Address
 	}
 }
 
+// Network returns the network address type of the provided network address.
+func Network(na *wire.NetAddress) NetworkAddress {
+	return getNetwork(na)
+}
+
 // isRFC1918 returns whether or not the passed address is part of the IPv4
 // private network address space as defined by RFC1918 (10.0.0.0/8,
 // 172.16.0.0/12, or 192.168.0.0/16).
@@ -247,26 +287,38 @@ func isValid(na *wire.NetAddress) bool {
 		na.IP.Equal(net.IPv4bcast))
 }
 
-// IsRoutable returns whether or not the passed address is routable over
-// the public internet.  This is true as long as the address is valid and is not
-// in any reserved ranges.
-func IsRoutable(na *wire.NetAddress) bool {
+// isRoutable returns whether or not the passed address is routable over the
+// public internet, or over the cjdns network when the cjdns reachable flag is
+// set.  This is true as long as the address is valid and is not in any
+// reserved ranges.
+func isRoutable(na *wire.NetAddress, cjdnsReachable bool) bool {
 	return isValid(na) && !(isRFC1918(na) || isRFC2544(na) ||
 		isRFC3927(na) || isRFC4862(na) || isRFC3849(na) ||
 		isRFC4843(na) || isRFC5737(na) || isRFC6598(na) ||
-		isLocal(na) || (isRFC4193(na) && !isOnionCatTor(na)))
+		isLocal(na) || (isRFC4193(na) && !isOnionCatTor(na) &&
+		!(isCJDNS(na) && cjdnsReachable)))
 }
 
-// GroupKey returns a string representing the network group an address is part
+// IsRoutable returns whether or not the passed address is routable over
+// the public internet.  This is true as long as the address is valid and is not
+// in any reserved ranges.  Addresses in the cjdns address block are never
+// routable.  See AddrManager.IsRoutable to take cjdns reachability into
+// account.
+func IsRoutable(na *wire.NetAddress) bool {
+	return isRoutable(na, false)
+}
+
+// groupKey returns a string representing the network group an address is part
 // of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
 // "local" for a local address, the string "tor:key" where key is the /4 of the
-// onion address for Tor address, and the string "unroutable" for an unroutable
-// address.
-func GroupKey(na *wire.NetAddress) string {
+// onion address for Tor address, the string "cjdns:key" where key is the /4
+// following the cjdns prefix for a cjdns address when the cjdns reachable flag
+// is set, and the string "unroutable" for an unroutable address.
+func groupKey(na *wire.NetAddress, cjdnsReachable bool) string {
 	if isLocal(na) {
 		return "local"
 	}
-	if !IsRoutable(na) {
+	if !isRoutable(na, cjdnsReachable) {
 		return "unroutable"
 	}
 	if isIPv4(na) {
@@ -295,6 +347,11 @@ func GroupKey(na *wire.NetAddress) string {
 		// group is keyed off the first 4 bits of the actual onion key.
 		return fmt.Sprintf("tor:%d", na.IP[6]&((1<<4)-1))
 	}
+	if isCJDNS(na) {
+		// cjdns addresses are derived from hashes of public keys, so
+		// the group is keyed off the first 4 bits after the prefix.
+		return fmt.Sprintf("cjdns:%d", na.IP[1]>>4)
+	}
 
 	// OK, so now we know ourselves to be a IPv6 address.
 	// bitcoind uses /32 for everything, except for Hurricane Electric's
@@ -306,3 +363,13 @@ func GroupKey(na *wire.NetAddress) string {
 
 	return na.IP.Mask(net.CIDRMask(bits, 128)).String()
 }
+
+// GroupKey returns a string representing the network group an address is part
+// of.  This is the /16 for IPv4, the /32 (/36 for he.net) for IPv6, the string
+// "local" for a local address, the string "tor:key" where key is the /4 of the
+// onion address for Tor address, and the string "unroutable" for an unroutable
+// address, which includes addresses in the cjdns address block.  See
+// AddrManager.GroupKey to take cjdns reachability into account.
+func GroupKey(na *wire.NetAddress) string {
+	return groupKey(na, false)
+}
@@ -200,3 +200,88 @@ func TestGroupKey(t *testing.T) {
 		}
 	}
 }
+
+// TestCJDNS ensures addresses in the cjdns address block are classified,
+// grouped, and considered reachable as intended depending on whether or not
+// the address manager is configured with cjdns reachability.
+func TestCJDNS(t *testing.T) {
+	newNa := func(ip string) *wire.NetAddress {
+		return wire.NewNetAddressIPPort(net.ParseIP(ip), 8333,
+			wire.SFNodeNetwork)
+	}
+	cjdnsNa := newNa("fc32:17ea:e415:c3bf:9808:149d:b5a2:c9aa")
+	cjdnsNa2 := newNa("fcf9:1a3b::1")
+	ulaNa := newNa("fd00::1234")
+	ipv4Na := newNa("12.1.2.3")
+	ipv6Na := newNa("2602:100::1")
+
+	if !isCJDNS(cjdnsNa) || isCJDNS(ulaNa) || isCJDNS(ipv6Na) {
+		t.Fatal("isCJDNS: unexpected result")
+	}
+	if net := getNetwork(cjdnsNa); net != CJDNSAddress {
+		t.Fatalf("getNetwork: unexpected network -- got %v, want %v", net,
+			CJDNSAddress)
+	}
+
+	// Ensure cjdns addresses are treated as unroutable RFC4193 addresses by
+	// the package level functions and address managers that are not
+	// configured with cjdns reachability.
+	n := NewWithConfig(&Config{MemoryOnly: true})
+	if IsRoutable(cjdnsNa) || n.IsRoutable(cjdnsNa) {
+		t.Fatal("IsRoutable: cjdns address is routable by default")
+	}
+	for _, key := range []string{GroupKey(cjdnsNa), n.GroupKey(cjdnsNa)} {
+		if key != "unroutable" {
+			t.Fatalf("GroupKey: unexpected group key -- got %q, want %q",
+				key, "unroutable")
+		}
+	}
+	if reach := n.getReachabilityFrom(cjdnsNa, cjdnsNa2); reach != Unreachable {
+		t.Fatalf("getReachabilityFrom: unexpected reach -- got %d, want %d",
+			reach, Unreachable)
+	}
+
+	// Ensure cjdns addresses are routable, grouped separately, and only
+	// reachable from other cjdns addresses by an address manager that is
+	// configured with cjdns reachability.  Other RFC4193 addresses must
+	// remain unroutable and other address managers must be unaffected.
+	n = NewWithConfig(&Config{MemoryOnly: true, CJDNSReachable: true})
+	if !n.IsRoutable(cjdnsNa) || n.IsRoutable(ulaNa) {
+		t.Fatal("IsRoutable: unexpected result with cjdns enabled")
+	}
+	if IsRoutable(cjdnsNa) {
+		t.Fatal("IsRoutable: package level cjdns address is routable")
+	}
+	groupKeyTests := []struct {
+		na   *wire.NetAddress
+		want string
+	}{
+		{cjdnsNa, "cjdns:3"},
+		{cjdnsNa2, "cjdns:15"},
+		{ulaNa, "unroutable"},
+	}
+	for _, test := range groupKeyTests {
+		if key := n.GroupKey(test.na); key != test.want {
+			t.Errorf("GroupKey %s: unexpected group key -- got %q, want "+
+				"%q", test.na.IP, key, test.want)
+		}
+	}
+	reachTests := []struct {
+		local, remote *wire.NetAddress
+		want          int
+	}{
+		{cjdnsNa, cjdnsNa2, Private},
+		{ipv4Na, cjdnsNa, Unreachable},
+		{ipv6Na, cjdnsNa, Unreachable},
+		{cjdnsNa, ipv4Na, Unreachable},
+		{cjdnsNa, ipv6Na, Unreachable},
+	}
+	for _, test := range reachTests {
+		reach := n.getReachabilityFrom(test.local, test.remote)
+		if reach != test.want {
+			t.Errorf("getReachabilityFrom %s -> %s: unexpected reach -- "+
+				"got %d, want %d", test.local.IP, test.remote.IP, reach,
+				test.want)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// PeersFileFormat identifies the encoding used to save known addresses.
+type PeersFileFormat uint8
+
+const (
+	// PeersFileJSON saves known addresses as JSON.  It is the default.
+	PeersFileJSON PeersFileFormat = iota
+
+	// PeersFileBinary saves known addresses using a compact binary
+	// encoding which is much faster to save and load than JSON when a large
+	// number of addresses are known.
+	PeersFileBinary
+)
+
+// peersDatFilename is the filename to store peers serialized with the binary
+// encoding.
+const peersDatFilename = "peers.dat"
+
+// maxPeersStringLen is the maximum length of a string in a peers file saved
+// with the binary encoding.
+const maxPeersStringLen = 1024
+
+// binaryPeersMagic identifies peers files that use the binary encoding.
+var binaryPeersMagic = [4]byte{'d', 'c', 'r', 'p'}
+
+// String returns the name of the peers file format.
+func (f PeersFileFormat) String() string {
+	switch f {
+	case PeersFileJSON:
+		return "json"
+	case PeersFileBinary:
+		return "binary"
+	}
+	return fmt.Sprintf("unknown peers file format (%d)", uint8(f))
+}
+
+// filename returns the filename used to store peers saved in the format.
+func (f PeersFileFormat) filename() string {
+	if f == PeersFileBinary {
+		return peersDatFilename
+	}
+	return PeersFilename
+}
+
+// encodeJSONPeers writes the provided serialized address manager to w as JSON.
+func encodeJSONPeers(w io.Writer, sam *serializedAddrManager) error {
+	return json.NewEncoder(w).Encode(sam)
+}
+
+// decodeJSONPeers reads a serialized address manager encoded as JSON from r.
+func decodeJSONPeers(r io.Reader) (*serializedAddrManager, error) {
+	var sam serializedAddrManager
+	if err := json.NewDecoder(r).Decode(&sam); err != nil {
+		return nil, err
+	}
+	return &sam, nil
+}
+
+// writeUvarint writes the provided value to w as a variable length integer.
+func writeUvarint(w *bufio.Writer, val uint64) error {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], val)
+	_, err := w.Write(buf[:n])
+	return err
+}
+
+// readUvarint reads a variable length integer from r.
+func readUvarint(r *bytes.Reader) (uint64, error) {
+	return binary.ReadUvarint(r)
+}
+
+// writeString writes the provided string to w prefixed by its length.
+func writeString(w *bufio.Writer, str string) error {
+	if err := writeUvarint(w, uint64(len(str))); err != nil {
+		return err
+	}
+	_, err := w.WriteString(str)
+	return err
+}
+
+// readString reads a string written by writeString from r.
+func readString(r *bytes.Reader) (string, error) {
+	strLen, err := readUvarint(r)
+	if err != nil {
+		return "", err
+	}
+	if strLen > maxPeersStringLen || strLen > uint64(r.Len()) {
+		return "", fmt.Errorf("invalid string length %d", strLen)
+	}
+	buf := make([]byte, strLen)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+// writeUint64 writes the provided value to w in little endian.
+func writeUint64(w *bufio.Writer, val uint64) error {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], val)
+	_, err := w.Write(buf[:])
+	return err
+}
+
+// readUint64 reads a little endian value from r.
+func readUint64(r *bytes.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(buf[:]), nil
+}
+
+// writeBuckets writes the number of provided buckets followed by the buckets to
+// w with each address represented by its index in the serialized address list.
+func writeBuckets(w *bufio.Writer, buckets [][]string, indices map[string]uint64) error {
+	if err := writeUvarint(w, uint64(len(buckets))); err != nil {
+		return err
+	}
+	for _, bucket := range buckets {
+		if err := writeUvarint(w, uint64(len(bucket))); err != nil {
+			return err
+		}
+		for _, key := range bucket {
+			idx, ok := indices[key]
+			if !ok {
+				return fmt.Errorf("bucket contains %s but none in "+
+					"address list", key)
+			}
+			if err := writeUvarint(w, idx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readBuckets reads buckets written by writeBuckets from r using the serialized
+// address list to resolve the address indices.
+func readBuckets(r *bytes.Reader, addrs []*serializedKnownAddress) ([][]string, error) {
+	numBuckets, err := readUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if numBuckets > uint64(r.Len()) {
+		return nil, fmt.Errorf("invalid number of buckets %d", numBuckets)
+	}
+	buckets := make([][]string, numBuckets)
+	for i := range buckets {
+		count, err := readUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		if count > uint64(len(addrs)) {
+			return nil, fmt.Errorf("bucket size %d exceeds the number of "+
+				"addresses %d", count, len(addrs))
+		}
+		buckets[i] = make([]string, 0, count)
+		for j := uint64(0); j < count; j++ {
+			idx, err := readUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+			if idx >= uint64(len(addrs)) {
+				return nil, fmt.Errorf("address index %d out of range",
+					idx)
+			}
+			buckets[i] = append(buckets[i], addrs[idx].Addr)
+		}
+	}
+	return buckets, nil
+}
+
+// encodeBinaryPeers writes the provided serialized address manager to w using
+// the compact binary encoding.  Addresses in the buckets are encoded as indices
+// into the address list rather than repeating their string form.
+func encodeBinaryPeers(w io.Writer, sam *serializedAddrManager) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(binaryPeersMagic[:]); err != nil {
+		return err
+	}
+	if err := writeUvarint(bw, uint64(sam.Version)); err != nil {
+		return err
+	}
+	if _, err := bw.Write(sam.Key[:]); err != nil {
+		return err
+	}
+	if err := writeString(bw, sam.ASMap); err != nil {
+		return err
+	}
+
+	indices := make(map[string]uint64, len(sam.Addresses))
+	err := writeUvarint(bw, uint64(len(sam.Addresses)))
+	if err != nil {
+		return err
+	}
+	for i, ska := range sam.Addresses {
+		indices[ska.Addr] = uint64(i)
+		if err := writeString(bw, ska.Addr); err != nil {
+			return err
+		}
+		if err := writeString(bw, ska.Src); err != nil {
+			return err
+		}
+		if err := writeUvarint(bw, uint64(ska.Attempts)); err != nil {
+			return err
+		}
+		for _, ts := range []int64{ska.TimeStamp, ska.LastAttempt, ska.LastSuccess} {
+			if err := writeUint64(bw, uint64(ts)); err != nil {
+				return err
+			}
+		}
+		err := writeUvarint(bw, uint64(ska.PoorService))
+		if err != nil {
+			return err
+		}
+		if err := writeString(bw, ska.UserAgent); err != nil {
+			return err
+		}
+		err = writeUvarint(bw, uint64(ska.ProtocolVersion))
+		if err != nil {
+			return err
+		}
+		if sam.Version < 2 {
+			continue
+		}
+		for _, val := range []int64{int64(ska.TotalAttempts),
+			int64(ska.TotalSuccesses), ska.Latency, ska.Uptime} {
+
+			if err := writeUvarint(bw, uint64(val)); err != nil {
+				return err
+			}
+		}
+		if sam.Version < 3 {
+			continue
+		}
+		for _, val := range []int64{ska.LastBlock, ska.BlocksBehind} {
+			if err := writeUvarint(bw, uint64(val)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writeBuckets(bw, sam.NewBuckets, indices); err != nil {
+		return err
+	}
+	if err := writeBuckets(bw, sam.TriedBuckets, indices); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// decodeBinaryPeers reads a serialized address manager encoded with the compact
+// binary encoding from r.
+func decodeBinaryPeers(r *bytes.Reader) (*serializedAddrManager, error) {
+	var magic [len(binaryPeersMagic)]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if magic != binaryPeersMagic {
+		return nil, errors.New("invalid binary peers magic")
+	}
+
+	var sam serializedAddrManager
+	version, err := readUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if version < minSerialisationVersion || version > serialisationVersion {
+		return nil, fmt.Errorf("unknown version %v in serialized "+
+			"addrmanager", version)
+	}
+	sam.Version = int(version)
+	if _, err := io.ReadFull(r, sam.Key[:]); err != nil {
+		return nil, err
+	}
+	if sam.ASMap, err = readString(r); err != nil {
+		return nil, err
+	}
+
+	count, err := readUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if count > uint64(r.Len()) {
+		return nil, fmt.Errorf("too many addresses %d", count)
+	}
+	sam.Addresses = make([]*serializedKnownAddress, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var ska serializedKnownAddress
+		if ska.Addr, err = readString(r); err != nil {
+			return nil, err
+		}
+		if ska.Src, err = readString(r); err != nil {
+			return nil, err
+		}
+		attempts, err := readUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		ska.Attempts = int(attempts)
+		for _, ts := range []*int64{&ska.TimeStamp, &ska.LastAttempt,
+			&ska.LastSuccess} {
+
+			val, err := readUint64(r)
+			if err != nil {
+				return nil, err
+			}
+			*ts = int64(val)
+		}
+		poorService, err := readUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		ska.PoorService = int(poorService)
+		if ska.UserAgent, err = readString(r); err != nil {
+			return nil, err
+		}
+		pver, err := readUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		ska.ProtocolVersion = uint32(pver)
+		if version >= 2 {
+			var totalAttempts, totalSuccesses uint64
+			for _, val := range []*uint64{&totalAttempts,
+				&totalSuccesses} {
+
+				if *val, err = readUvarint(r); err != nil {
+					return nil, err
+				}
+			}
+			ska.TotalAttempts = int(totalAttempts)
+			ska.TotalSuccesses = int(totalSuccesses)
+			for _, val := range []*int64{&ska.Latency, &ska.Uptime} {
+				v, err := readUvarint(r)
+				if err != nil {
+					return nil, err
+				}
+				*val = int64(v)
+			}
+		}
+		if version >= 3 {
+			for _, val := range []*int64{&ska.LastBlock,
+				&ska.BlocksBehind} {
+
+				v, err := readUvarint(r)
+				if err != nil {
+					return nil, err
+				}
+				*val = int64(v)
+			}
+		}
+		sam.Addresses = append(sam.Addresses, &ska)
+	}
+
+	if sam.NewBuckets, err = readBuckets(r, sam.Addresses); err != nil {
+		return nil, err
+	}
+	if sam.TriedBuckets, err = readBuckets(r, sam.Addresses); err != nil {
+		return nil, err
+	}
+	return &sam, nil
+}
+
+// encodePeers writes the provided serialized address manager to w in the
+// provided format.
+func encodePeers(w io.Writer, sam *serializedAddrManager, format PeersFileFormat) error {
+	if format == PeersFileBinary {
+		return encodeBinaryPeers(w, sam)
+	}
+	return encodeJSONPeers(w, sam)
+}
+
+// decodePeers reads a serialized address manager from r.  The format is
+// detected from the data so files saved in any of the formats can be loaded.
+func decodePeers(r io.Reader) (*serializedAddrManager, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(data, binaryPeersMagic[:]) {
+		return decodeBinaryPeers(bytes.NewReader(data))
+	}
+	return decodeJSONPeers(bytes.NewReader(data))
+}
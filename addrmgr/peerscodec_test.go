@@ -0,0 +1,185 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// addTestAddresses adds the provided number of distinct routable addresses to
+// the address manager and marks every tenth one as good so the tried buckets
+// are populated as well.
+func addTestAddresses(a *AddrManager, count int) {
+	for i := 0; i < count; i++ {
+		ip := net.IPv4(byte(20+i%60), byte(i>>8), byte(i), 1)
+		na := wire.NewNetAddressIPPort(ip, 9108, wire.SFNodeNetwork)
+		a.AddAddress(na, na)
+		if i%10 == 0 {
+			a.Attempt(na)
+			a.Good(na)
+			a.RecordConnectionStats(na, time.Duration(i)*time.Millisecond,
+				time.Duration(i)*time.Minute)
+			a.SetLastBlock(na, int64(i), int64(2*i))
+		}
+	}
+}
+
+// TestPeersFileFormats ensures known addresses are saved and loaded in all of
+// the supported peers file formats and that addresses saved in another format
+// are loaded and migrated when the format changes.
+func TestPeersFileFormats(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testpeersfileformats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	formats := []PeersFileFormat{PeersFileJSON, PeersFileBinary}
+	for _, format := range formats {
+		formatDir := filepath.Join(dir, format.String())
+		if err := os.Mkdir(formatDir, 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		n := New(formatDir, nil)
+		n.SetPeersFileFormat(format)
+		addTestAddresses(n, 500)
+		n.savePeers()
+		wantNew, wantTried := n.nNew, n.nTried
+
+		loaded := New(formatDir, nil)
+		loaded.SetPeersFileFormat(format)
+		loaded.loadPeers()
+		if loaded.nNew != wantNew || loaded.nTried != wantTried {
+			t.Fatalf("%s: unexpected addresses -- got %d new %d tried, "+
+				"want %d new %d tried", format, loaded.nNew,
+				loaded.nTried, wantNew, wantTried)
+		}
+		if loaded.key != n.key {
+			t.Fatalf("%s: unexpected key", format)
+		}
+		for key, ka := range n.addrIndex {
+			got, ok := loaded.addrIndex[key]
+			if !ok {
+				t.Fatalf("%s: address %s was not loaded", format, key)
+			}
+			if got.tried != ka.tried || got.attempts != ka.attempts ||
+				got.lastsuccess.Unix() != ka.lastsuccess.Unix() ||
+				NetAddressKey(got.srcAddr) != NetAddressKey(ka.srcAddr) ||
+				got.totalAttempts != ka.totalAttempts ||
+				got.totalSuccesses != ka.totalSuccesses ||
+				got.latency != ka.latency || got.uptime != ka.uptime ||
+				got.lastBlock != ka.lastBlock ||
+				got.blocksBehind != ka.blocksBehind {
+
+				t.Fatalf("%s: address %s does not match", format, key)
+			}
+		}
+
+		// Ensure the addresses are loaded from the file of the other
+		// format after changing formats and that file is removed once
+		// the addresses are saved in the new format.
+		other := formats[(int(format)+1)%len(formats)]
+		migrated := New(formatDir, nil)
+		migrated.SetPeersFileFormat(other)
+		migrated.loadPeers()
+		if migrated.numAddresses() != n.numAddresses() {
+			t.Fatalf("%s: unexpected number of migrated addresses -- "+
+				"got %d, want %d", other, migrated.numAddresses(),
+				n.numAddresses())
+		}
+		migrated.savePeers()
+		_, err := os.Stat(filepath.Join(formatDir, format.filename()))
+		if !os.IsNotExist(err) {
+			t.Fatalf("%s: old peers file was not removed: %v", other,
+				err)
+		}
+		_, err = os.Stat(filepath.Join(formatDir, other.filename()))
+		if err != nil {
+			t.Fatalf("%s: peers file was not saved: %v", other, err)
+		}
+	}
+}
+
+// TestBinaryPeersVersion1 ensures peers saved with the binary encoding before
+// connection statistics were added are still loaded.
+func TestBinaryPeersVersion1(t *testing.T) {
+	sam := &serializedAddrManager{
+		Version: 1,
+		Addresses: []*serializedKnownAddress{{
+			Addr:           "12.1.2.3:9108",
+			Src:            "13.1.2.3:9108",
+			Attempts:       2,
+			UserAgent:      "/dcrd:1.6.0/",
+			TotalAttempts:  5,
+			TotalSuccesses: 3,
+		}},
+		NewBuckets:   [][]string{{"12.1.2.3:9108"}},
+		TriedBuckets: [][]string{{}},
+	}
+	var buf bytes.Buffer
+	if err := encodeBinaryPeers(&buf, sam); err != nil {
+		t.Fatalf("unexpected encode error: %v", err)
+	}
+	got, err := decodeBinaryPeers(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	if got.Version != 1 || len(got.Addresses) != 1 {
+		t.Fatalf("unexpected decoded peers %+v", got)
+	}
+	ska := got.Addresses[0]
+	if ska.Addr != "12.1.2.3:9108" || ska.Attempts != 2 ||
+		ska.UserAgent != "/dcrd:1.6.0/" || ska.TotalAttempts != 0 ||
+		ska.TotalSuccesses != 0 {
+
+		t.Fatalf("unexpected decoded address %+v", ska)
+	}
+	if len(got.NewBuckets) != 1 || len(got.NewBuckets[0]) != 1 {
+		t.Fatalf("unexpected decoded new buckets %v", got.NewBuckets)
+	}
+}
+
+// benchmarkLoadPeers benchmarks loading a large number of known addresses saved
+// in the provided format.
+func benchmarkLoadPeers(b *testing.B, format PeersFileFormat) {
+	dir, err := ioutil.TempDir("", "benchloadpeers")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	n := New(dir, nil)
+	n.SetPeersFileFormat(format)
+	addTestAddresses(n, 50000)
+	n.savePeers()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		n := New(dir, nil)
+		n.SetPeersFileFormat(format)
+		n.loadPeers()
+	}
+}
+
+// BenchmarkLoadPeersJSON benchmarks loading known addresses saved as JSON.
+func BenchmarkLoadPeersJSON(b *testing.B) {
+	benchmarkLoadPeers(b, PeersFileJSON)
+}
+
+// BenchmarkLoadPeersBinary benchmarks loading known addresses saved with the
+// binary encoding.
+func BenchmarkLoadPeersBinary(b *testing.B) {
+	benchmarkLoadPeers(b, PeersFileBinary)
+}
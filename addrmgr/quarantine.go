@@ -0,0 +1,147 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// maxSeedQuarantine is the maximum number of addresses learned from seeders
+// that are held in quarantine.  The addresses that were quarantined the
+// longest are forgotten to make room for new ones.
+const maxSeedQuarantine = 4096
+
+// seedAddress houses an address learned from seeders that is held in
+// quarantine until it is confirmed by an independent source.
+type seedAddress struct {
+	ka        *KnownAddress
+	srcGroups map[string]struct{}
+	added     time.Time
+}
+
+// AddSeedAddresses adds addresses returned by the seeder with the provided
+// source address to a quarantine tier instead of the new buckets.  Quarantined
+// addresses are not shared with peers and are only selected for outbound
+// connections when no other addresses are known, such as when bootstrapping.
+// They are promoted to the new buckets once they are returned by a seeder in a
+// different group, relayed by a peer via AddAddresses or AddAddress, or marked
+// good after a successful connection, such as a feeler connection.  This
+// limits the impact of a compromised seeder since the addresses it returns
+// alone never make it into the buckets without being confirmed.  Addresses that are
+// already known are updated as if added by AddAddresses.  The quarantine is
+// not persisted.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) AddSeedAddresses(srcAddr *wire.NetAddress, addrs []*wire.NetAddress) {
+	defer a.sendEvents()
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	srcGroup := a.GroupKey(srcAddr)
+	for _, na := range addrs {
+		key := NetAddressKey(na)
+		if a.addrIndex[key] != nil {
+			a.updateAddress(na, srcAddr)
+			continue
+		}
+
+		// Promote quarantined addresses returned by a seeder in another
+		// group.
+		if entry, ok := a.seedQuarantine[key]; ok {
+			if _, ok := entry.srcGroups[srcGroup]; ok {
+				continue
+			}
+			delete(a.seedQuarantine, key)
+			a.updateAddress(na, srcAddr)
+			continue
+		}
+
+		if !a.IsRoutable(na) || a.refuseNetwork(na) || a.isBanned(na) ||
+			a.isBlocked(na) {
+
+			continue
+		}
+		if len(a.seedQuarantine) >= maxSeedQuarantine {
+			a.evictSeedQuarantine()
+		}
+		naCopy := *na
+		a.seedQuarantine[key] = &seedAddress{
+			ka: &KnownAddress{na: &naCopy, srcAddr: srcAddr,
+				cachedKey: key, cachedSrcGroup: srcGroup},
+			srcGroups: map[string]struct{}{srcGroup: {}},
+			added:     a.cfg.now(),
+		}
+	}
+}
+
+// evictSeedQuarantine forgets the address that was held in quarantine the
+// longest.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) evictSeedQuarantine() {
+	var oldestKey string
+	var oldest *seedAddress
+	for key, entry := range a.seedQuarantine {
+		if oldest == nil || entry.added.Before(oldest.added) {
+			oldestKey, oldest = key, entry
+		}
+	}
+	delete(a.seedQuarantine, oldestKey)
+}
+
+// findQuarantined returns the quarantined address that matches the provided
+// address or nil if it is not quarantined.
+//
+// This function MUST be called with the address manager lock held (for
+// reads).
+func (a *AddrManager) findQuarantined(addr *wire.NetAddress) *KnownAddress {
+	entry, ok := a.seedQuarantine[NetAddressKey(addr)]
+	if !ok {
+		return nil
+	}
+	return entry.ka
+}
+
+// getQuarantinedAddress returns a random quarantined address or nil when there
+// are none.  It allows bootstrapping from the addresses learned from seeders
+// when no other addresses are known, in which case the address is promoted
+// once the connection to it succeeds.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) getQuarantinedAddress() *KnownAddress {
+	if len(a.seedQuarantine) == 0 {
+		return nil
+	}
+	addrs := make([]*KnownAddress, 0, len(a.seedQuarantine))
+	for _, entry := range a.seedQuarantine {
+		addrs = append(addrs, entry.ka)
+	}
+	a.orderForSelection(addrs)
+	ka := addrs[a.rand.Intn(len(addrs))]
+	log.Tracef("Selected %v from seed quarantine", ka.key())
+	return ka
+}
+
+// promoteQuarantined moves the provided address from the quarantine to the new
+// buckets, such as after a successful feeler connection, and returns the known
+// address it is tracked by.  It returns nil when the address is not
+// quarantined or is not accepted into the new buckets.
+//
+// This function MUST be called with the address manager lock held (for
+// writes).
+func (a *AddrManager) promoteQuarantined(addr *wire.NetAddress) *KnownAddress {
+	key := NetAddressKey(addr)
+	entry, ok := a.seedQuarantine[key]
+	if !ok {
+		return nil
+	}
+	delete(a.seedQuarantine, key)
+	a.updateAddress(entry.ka.na, entry.ka.srcAddr)
+	return a.addrIndex[key]
+}
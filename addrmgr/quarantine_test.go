@@ -0,0 +1,94 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"net"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestSeedQuarantine ensures addresses learned from seeders are quarantined
+// until they are confirmed by an independent source or a successful
+// connection.
+func TestSeedQuarantine(t *testing.T) {
+	n := NewWithConfig(&Config{DataDir: "testseedquarantine",
+		SourceRateLimit: -1})
+	seeder := wire.NewNetAddressIPPort(net.ParseIP("173.144.1.1"), 443, 0)
+	sameGroupSeeder := wire.NewNetAddressIPPort(net.ParseIP("173.144.2.2"),
+		443, 0)
+	otherSeeder := wire.NewNetAddressIPPort(net.ParseIP("174.144.1.1"), 443,
+		0)
+	peer := wire.NewNetAddressIPPort(net.ParseIP("175.144.1.1"), 9108, 0)
+	addrs := make([]*wire.NetAddress, 4)
+	for i := range addrs {
+		ip := net.IPv4(12, byte(i+1), 2, 3)
+		addrs[i] = wire.NewNetAddressIPPort(ip, 9108, 0)
+	}
+
+	// Ensure seeded addresses are quarantined, but are still selected to
+	// bootstrap when no other addresses are known.
+	n.AddSeedAddresses(seeder, addrs)
+	for _, na := range addrs {
+		if n.HasAddress(na) {
+			t.Fatalf("seeded address %v was not quarantined", na)
+		}
+	}
+	if ka := n.GetAddress(); ka == nil {
+		t.Fatal("no quarantined address selected to bootstrap")
+	}
+	if cache := n.AddressCache(); len(cache) != 0 {
+		t.Fatalf("quarantined addresses %v are shared", cache)
+	}
+
+	// Ensure a seeder in the same group does not promote the addresses
+	// while a seeder in another group does.
+	n.AddSeedAddresses(sameGroupSeeder, addrs[:1])
+	if n.HasAddress(addrs[0]) {
+		t.Fatal("seeder in the same group promoted address")
+	}
+	n.AddSeedAddresses(otherSeeder, addrs[:1])
+	if !n.HasAddress(addrs[0]) {
+		t.Fatal("seeder in another group did not promote address")
+	}
+
+	// Ensure an address relayed by a peer is promoted.
+	n.AddAddress(addrs[1], peer)
+	if !n.HasAddress(addrs[1]) {
+		t.Fatal("address relayed by a peer was not promoted")
+	}
+	if _, ok := n.seedQuarantine[NetAddressKey(addrs[1])]; ok {
+		t.Fatal("relayed address is still quarantined")
+	}
+
+	// Ensure quarantined addresses are not selected once other addresses
+	// are known.
+	for i := 0; i < 20; i++ {
+		ka := n.GetAddress()
+		if ka == nil {
+			t.Fatal("no address selected")
+		}
+		if key := ka.key(); n.seedQuarantine[key] != nil {
+			t.Fatalf("quarantined address %v selected", key)
+		}
+	}
+
+	// Ensure a quarantined address is promoted to the tried buckets after
+	// a successful connection.
+	n.FeelerAttempt(addrs[2])
+	n.Good(addrs[2])
+	if ka, ok := n.Lookup(addrs[2]); !ok || !ka.Tried {
+		t.Fatalf("address was not promoted after a successful "+
+			"connection: %+v", ka)
+	}
+
+	// Ensure only the remaining address is still quarantined.
+	if len(n.seedQuarantine) != 1 ||
+		n.seedQuarantine[NetAddressKey(addrs[3])] == nil {
+
+		t.Fatalf("unexpected quarantined addresses %v", n.seedQuarantine)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"time"
+)
+
+const (
+	// sourceRateLimit is the default number of addresses per second that
+	// are accepted from each source address group.
+	sourceRateLimit = 0.1
+
+	// sourceRateBurst is the default maximum number of addresses that are
+	// accepted at once from each source address group.  It matches the
+	// maximum number of addresses in an addr message.
+	sourceRateBurst = 1000
+
+	// maxSourceLimiters is the number of source address groups tracked by
+	// the rate limiter after which the groups that no longer have any
+	// effect on the rate are forgotten.
+	maxSourceLimiters = 4096
+)
+
+// tokenBucket tracks the number of addresses that may currently be accepted
+// from a source address group.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// sourceLimiter limits the rate at which addresses are accepted from each
+// source address group with a token bucket per group.  This prevents a single
+// peer, or group of peers, that floods addr messages from dominating the new
+// buckets.
+type sourceLimiter struct {
+	rate    float64
+	burst   float64
+	buckets map[string]*tokenBucket
+}
+
+// newSourceLimiter returns a rate limiter that accepts the provided number of
+// addresses per second from each source address group with the provided burst
+// size.  A negative rate disables the limiter.
+func newSourceLimiter(rate float64, burst int) *sourceLimiter {
+	return &sourceLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// refill adds the tokens accrued since the last refill of the provided bucket
+// without exceeding the burst size.
+func (l *sourceLimiter) refill(b *tokenBucket, now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens += elapsed * l.rate
+		if b.tokens > l.burst {
+			b.tokens = l.burst
+		}
+	}
+	b.last = now
+}
+
+// allow returns whether or not an address from the source address group with
+// the provided key may be accepted at the provided time and consumes a token
+// when it is.
+func (l *sourceLimiter) allow(group string, now time.Time) bool {
+	if l.rate < 0 {
+		return true
+	}
+
+	b, ok := l.buckets[group]
+	if !ok {
+		if len(l.buckets) >= maxSourceLimiters {
+			l.prune(now)
+		}
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[group] = b
+	}
+	l.refill(b, now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune forgets the source address groups whose token buckets have refilled
+// completely since they behave the same as groups that were never seen.
+func (l *sourceLimiter) prune(now time.Time) {
+	for group, b := range l.buckets {
+		l.refill(b, now)
+		if b.tokens >= l.burst {
+			delete(l.buckets, group)
+		}
+	}
+}
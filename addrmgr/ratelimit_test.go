@@ -0,0 +1,65 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestSourceRateLimit ensures the number of addresses accepted from each source
+// address group is limited.
+func TestSourceRateLimit(t *testing.T) {
+	n := NewWithConfig(&Config{SourceRateBurst: 10})
+	newAddrs := func(first, count int) []*wire.NetAddress {
+		addrs := make([]*wire.NetAddress, 0, count)
+		for i := first; i < first+count; i++ {
+			ip := net.ParseIP(fmt.Sprintf("%d.%d.1.1", 20+i/256, i%256))
+			addrs = append(addrs, wire.NewNetAddressIPPort(ip, 9108, 0))
+		}
+		return addrs
+	}
+
+	// Ensure only the burst size is accepted from a single source group,
+	// including from other addresses in the same group.
+	src := wire.NewNetAddressIPPort(net.ParseIP("173.144.1.1"), 9108, 0)
+	sameGroupSrc := wire.NewNetAddressIPPort(net.ParseIP("173.144.2.2"),
+		9108, 0)
+	n.AddAddresses(newAddrs(0, 15), src)
+	n.AddAddresses(newAddrs(15, 5), sameGroupSrc)
+	if got := n.numAddresses(); got != 10 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 10", got)
+	}
+	if m := n.Metrics(); m.RateLimited != 10 {
+		t.Fatalf("unexpected rate limited count -- got %d, want 10",
+			m.RateLimited)
+	}
+
+	// Ensure another source group is not affected.
+	otherSrc := wire.NewNetAddressIPPort(net.ParseIP("174.144.1.1"), 9108, 0)
+	n.AddAddresses(newAddrs(20, 5), otherSrc)
+	if got := n.numAddresses(); got != 15 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 15", got)
+	}
+
+	// Ensure tokens are refilled at the configured rate.
+	b := n.sourceLimiter.buckets[n.GroupKey(src)]
+	b.last = b.last.Add(-30 * time.Second)
+	n.AddAddresses(newAddrs(25, 5), src)
+	if got := n.numAddresses(); got != 18 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 18", got)
+	}
+
+	// Ensure a negative rate disables the limit.
+	n = NewWithConfig(&Config{SourceRateLimit: -1, SourceRateBurst: 10})
+	n.AddAddresses(newAddrs(0, 20), src)
+	if got := n.numAddresses(); got != 20 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 20", got)
+	}
+}
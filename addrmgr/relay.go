@@ -0,0 +1,123 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"sync"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// maxKnownAddrsPerPeer is the default maximum number of addresses that are
+// remembered as known to each peer.
+const maxKnownAddrsPerPeer = 10000
+
+// rollingSet is a set of address keys that holds approximately up to a limited
+// number of the most recently added keys.  Keys are added to the current
+// generation until it holds half of the limit, at which point the previous
+// generation is forgotten and replaced by the current one.  This bounds the
+// memory used without tracking the order of every individual key.
+type rollingSet struct {
+	limit    int
+	current  map[string]struct{}
+	previous map[string]struct{}
+}
+
+// newRollingSet returns a new rolling set that holds approximately up to the
+// provided number of keys.
+func newRollingSet(limit int) *rollingSet {
+	return &rollingSet{
+		limit:   limit,
+		current: make(map[string]struct{}),
+	}
+}
+
+// add adds the provided key to the set.
+func (s *rollingSet) add(key string) {
+	if _, ok := s.current[key]; ok {
+		return
+	}
+	if len(s.current) >= (s.limit+1)/2 {
+		s.previous = s.current
+		s.current = make(map[string]struct{})
+	}
+	s.current[key] = struct{}{}
+}
+
+// contains returns whether or not the provided key is in the set.
+func (s *rollingSet) contains(key string) bool {
+	if _, ok := s.current[key]; ok {
+		return true
+	}
+	_, ok := s.previous[key]
+	return ok
+}
+
+// relayTracker tracks the addresses known to each peer, either because the
+// peer relayed them or because they were relayed to it, so addresses are not
+// needlessly relayed to peers multiple times.
+type relayTracker struct {
+	mtx   sync.Mutex
+	limit int
+	peers map[int32]*rollingSet
+}
+
+// newRelayTracker returns a new relay tracker that remembers approximately up
+// to the provided number of addresses per peer.
+func newRelayTracker(limit int) *relayTracker {
+	return &relayTracker{
+		limit: limit,
+		peers: make(map[int32]*rollingSet),
+	}
+}
+
+// AddKnownAddresses marks the provided addresses as known to the peer with the
+// provided id so they are no longer returned by UnknownAddresses for it.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) AddKnownAddresses(peerID int32, addrs []*wire.NetAddress) {
+	r := a.relay
+	r.mtx.Lock()
+	known, ok := r.peers[peerID]
+	if !ok {
+		known = newRollingSet(r.limit)
+		r.peers[peerID] = known
+	}
+	for _, na := range addrs {
+		known.add(NetAddressKey(na))
+	}
+	r.mtx.Unlock()
+}
+
+// UnknownAddresses returns the subset of the provided addresses that are not
+// yet known to the peer with the provided id, preserving their order.  It is
+// intended to filter addresses before they are relayed to the peer.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) UnknownAddresses(peerID int32, addrs []*wire.NetAddress) []*wire.NetAddress {
+	r := a.relay
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	known := r.peers[peerID]
+	unknown := make([]*wire.NetAddress, 0, len(addrs))
+	for _, na := range addrs {
+		if known == nil || !known.contains(NetAddressKey(na)) {
+			unknown = append(unknown, na)
+		}
+	}
+	return unknown
+}
+
+// ForgetKnownAddresses forgets which addresses are known to the peer with the
+// provided id.  It must be called once the peer disconnects.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) ForgetKnownAddresses(peerID int32) {
+	r := a.relay
+	r.mtx.Lock()
+	delete(r.peers, peerID)
+	r.mtx.Unlock()
+}
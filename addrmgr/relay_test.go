@@ -0,0 +1,59 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"net"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestRelayTracking ensures the addresses known to each peer are tracked
+// independently, are bounded, and are forgotten on request.
+func TestRelayTracking(t *testing.T) {
+	n := NewWithConfig(&Config{DataDir: "testrelaytracking",
+		MaxKnownAddrsPerPeer: 4})
+	addrs := make([]*wire.NetAddress, 8)
+	for i := range addrs {
+		ip := net.IPv4(12, byte(i+1), 2, 3)
+		addrs[i] = wire.NewNetAddressIPPort(ip, 9108, 0)
+	}
+
+	// Ensure all addresses are unknown to a peer that is not tracked.
+	if got := n.UnknownAddresses(1, addrs); len(got) != len(addrs) {
+		t.Fatalf("unexpected number of unknown addresses -- got %d, "+
+			"want %d", len(got), len(addrs))
+	}
+
+	// Ensure known addresses are filtered for the peer they are known to
+	// only and the order of the remaining addresses is preserved.
+	n.AddKnownAddresses(1, []*wire.NetAddress{addrs[0], addrs[2]})
+	got := n.UnknownAddresses(1, addrs[:4])
+	if len(got) != 2 || got[0] != addrs[1] || got[1] != addrs[3] {
+		t.Fatalf("unexpected unknown addresses %v", got)
+	}
+	if got := n.UnknownAddresses(2, addrs[:4]); len(got) != 4 {
+		t.Fatalf("unexpected number of unknown addresses for other peer "+
+			"-- got %d, want 4", len(got))
+	}
+
+	// Ensure the oldest known addresses are forgotten once the limit is
+	// exceeded while the most recent ones are remembered.
+	n.AddKnownAddresses(1, addrs[3:])
+	if got := n.UnknownAddresses(1, addrs[:1]); len(got) != 1 {
+		t.Fatal("oldest known address was not forgotten")
+	}
+	if got := n.UnknownAddresses(1, addrs[5:]); len(got) != 0 {
+		t.Fatalf("recent known addresses %v were forgotten", got)
+	}
+
+	// Ensure all known addresses are forgotten for a peer on request.
+	n.ForgetKnownAddresses(1)
+	if got := n.UnknownAddresses(1, addrs); len(got) != len(addrs) {
+		t.Fatalf("unexpected number of unknown addresses after "+
+			"forgetting -- got %d, want %d", len(got), len(addrs))
+	}
+}
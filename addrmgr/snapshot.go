@@ -0,0 +1,123 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+// NetworkCounts is the number of known addresses on a type of network in the
+// new and tried buckets.
+type NetworkCounts struct {
+	New   int
+	Tried int
+}
+
+// Snapshot is a copy of the state of an address manager at the time it was
+// taken.  It is safe to retain and inspect without affecting the address
+// manager.
+type Snapshot struct {
+	// NewAddresses and TriedAddresses are the number of addresses in the
+	// new and tried buckets, respectively.
+	NewAddresses   int
+	TriedAddresses int
+
+	// Networks is the number of known addresses by the type of network they
+	// belong to.
+	Networks map[NetworkAddress]NetworkCounts
+
+	// NewBucketCounts and TriedBucketCounts are the number of addresses in
+	// each of the new and tried buckets, respectively.
+	NewBucketCounts   []int
+	TriedBucketCounts []int
+
+	// NewBucketSize and TriedBucketSize are the maximum number of addresses
+	// in each of the new and tried buckets, respectively.
+	NewBucketSize   int
+	TriedBucketSize int
+
+	// UserAgents and ProtocolVersions are the number of known addresses by
+	// the user agent and protocol version most recently advertised by the
+	// peer at them.  Addresses that were never connected to are not
+	// counted.
+	UserAgents       map[string]int
+	ProtocolVersions map[uint32]int
+
+	// Addresses are snapshots of every known address.  It is only populated
+	// when requested.
+	Addresses []*KnownAddressSnapshot
+}
+
+// Snapshot returns a copy of the state of the address manager that includes
+// snapshots of every known address when includeAddresses is true.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) Snapshot(includeAddresses bool) *Snapshot {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	s := &Snapshot{
+		NewAddresses:      a.nNew,
+		TriedAddresses:    a.nTried,
+		Networks:          make(map[NetworkAddress]NetworkCounts),
+		UserAgents:        make(map[string]int),
+		ProtocolVersions:  make(map[uint32]int),
+		NewBucketCounts:   make([]int, len(a.addrNew)),
+		TriedBucketCounts: make([]int, len(a.addrTried)),
+		NewBucketSize:     a.cfg.NewBucketSize,
+		TriedBucketSize:   a.cfg.TriedBucketSize,
+	}
+	for i, bucket := range a.addrNew {
+		s.NewBucketCounts[i] = len(bucket)
+	}
+	for i, bucket := range a.addrTried {
+		s.TriedBucketCounts[i] = len(bucket)
+	}
+	if includeAddresses {
+		s.Addresses = make([]*KnownAddressSnapshot, 0, len(a.addrIndex))
+	}
+	for _, ka := range a.addrIndex {
+		network := getNetwork(ka.na)
+		counts := s.Networks[network]
+		if ka.tried {
+			counts.Tried++
+		} else {
+			counts.New++
+		}
+		s.Networks[network] = counts
+
+		ka.mtx.Lock()
+		if ka.userAgent != "" {
+			s.UserAgents[ka.userAgent]++
+		}
+		if ka.protocolVersion != 0 {
+			s.ProtocolVersions[ka.protocolVersion]++
+		}
+		ka.mtx.Unlock()
+		if includeAddresses {
+			s.Addresses = append(s.Addresses, ka.snapshot())
+		}
+	}
+	return s
+}
+
+// ForEachAddress calls the provided function with a snapshot of every known
+// address, in no particular order, until it returns false.  The snapshots are
+// copied while holding the address manager lock, but the function is called
+// without it held, so it may safely call back into the address manager and
+// does not block it for the duration of the walk.  Addresses added or removed
+// during the walk are not reflected.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) ForEachAddress(fn func(ka *KnownAddressSnapshot) bool) {
+	a.mtx.Lock()
+	addrs := make([]*KnownAddressSnapshot, 0, len(a.addrIndex))
+	for _, ka := range a.addrIndex {
+		addrs = append(addrs, ka.snapshot())
+	}
+	a.mtx.Unlock()
+
+	for _, ka := range addrs {
+		if !fn(ka) {
+			return
+		}
+	}
+}
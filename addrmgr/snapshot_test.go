@@ -0,0 +1,138 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestSnapshot ensures snapshots reflect the state of the address manager.
+func TestSnapshot(t *testing.T) {
+	n := New("testsnapshot", nil)
+	ipv4 := wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0)
+	ipv6 := wire.NewNetAddressIPPort(net.ParseIP("2602:100::1"), 9108, 0)
+	onion := wire.NewNetAddressIPPort(net.ParseIP("fd87:d87e:eb43::1"),
+		9108, 0)
+	n.AddAddresses([]*wire.NetAddress{ipv4, ipv6, onion}, ipv4)
+	n.Good(ipv4)
+
+	s := n.Snapshot(false)
+	if s.NewAddresses != 2 || s.TriedAddresses != 1 {
+		t.Fatalf("unexpected address counts -- got %d new %d tried, want "+
+			"2 new 1 tried", s.NewAddresses, s.TriedAddresses)
+	}
+	wantNetworks := map[NetworkAddress]NetworkCounts{
+		IPv4Address:  {Tried: 1},
+		IPv6Address:  {New: 1},
+		OnionAddress: {New: 1},
+	}
+	if len(s.Networks) != len(wantNetworks) {
+		t.Fatalf("unexpected networks %v", s.Networks)
+	}
+	for network, want := range wantNetworks {
+		if got := s.Networks[network]; got != want {
+			t.Errorf("unexpected %v counts -- got %+v, want %+v",
+				network, got, want)
+		}
+	}
+	var newCount, triedCount int
+	for _, count := range s.NewBucketCounts {
+		newCount += count
+	}
+	for _, count := range s.TriedBucketCounts {
+		triedCount += count
+	}
+	if len(s.NewBucketCounts) != newBucketCount ||
+		len(s.TriedBucketCounts) != triedBucketCount ||
+		newCount != 2 || triedCount != 1 ||
+		s.NewBucketSize != newBucketSize ||
+		s.TriedBucketSize != triedBucketSize {
+
+		t.Fatalf("unexpected bucket occupancy %+v", s)
+	}
+	if s.Addresses != nil {
+		t.Fatalf("unexpected addresses %v", s.Addresses)
+	}
+	if len(s.UserAgents) != 0 || len(s.ProtocolVersions) != 0 {
+		t.Fatalf("unexpected versions %v %v", s.UserAgents,
+			s.ProtocolVersions)
+	}
+
+	// Ensure the advertised user agents and protocol versions are counted.
+	n.SetUserAgent(ipv4, "/dcrd:1.6.0/", 8)
+	n.SetUserAgent(ipv6, "/dcrd:1.6.0/", 9)
+	s = n.Snapshot(false)
+	wantUserAgents := map[string]int{"/dcrd:1.6.0/": 2}
+	if !reflect.DeepEqual(s.UserAgents, wantUserAgents) {
+		t.Fatalf("unexpected user agents -- got %v, want %v",
+			s.UserAgents, wantUserAgents)
+	}
+	wantVersions := map[uint32]int{8: 1, 9: 1}
+	if !reflect.DeepEqual(s.ProtocolVersions, wantVersions) {
+		t.Fatalf("unexpected protocol versions -- got %v, want %v",
+			s.ProtocolVersions, wantVersions)
+	}
+
+	// Ensure addresses are only included when requested.
+	s = n.Snapshot(true)
+	if len(s.Addresses) != 3 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 3",
+			len(s.Addresses))
+	}
+	for _, ka := range s.Addresses {
+		if ka.Tried != (NetAddressKey(&ka.Addr) == "12.1.2.3:9108") {
+			t.Fatalf("unexpected tried state for %v", ka.Addr)
+		}
+	}
+}
+
+// TestForEachAddress ensures every known address is visited, that the walk
+// stops when requested, and that the address manager may be used during it.
+func TestForEachAddress(t *testing.T) {
+	n := New("testforeachaddress", nil)
+	addrs := []*wire.NetAddress{
+		wire.NewNetAddressIPPort(net.ParseIP("12.1.2.3"), 9108, 0),
+		wire.NewNetAddressIPPort(net.ParseIP("13.1.2.3"), 9108, 0),
+		wire.NewNetAddressIPPort(net.ParseIP("14.1.2.3"), 9108, 0),
+	}
+	n.AddAddresses(addrs, addrs[0])
+
+	seen := make(map[string]struct{})
+	n.ForEachAddress(func(ka *KnownAddressSnapshot) bool {
+		seen[NetAddressKey(&ka.Addr)] = struct{}{}
+
+		// Ensure the lock is not held while calling the function.
+		n.DeleteAddress(&ka.Addr)
+		return true
+	})
+	if len(seen) != len(addrs) {
+		t.Fatalf("unexpected number of visited addresses -- got %d, want %d",
+			len(seen), len(addrs))
+	}
+	for _, addr := range addrs {
+		if _, ok := seen[NetAddressKey(addr)]; !ok {
+			t.Errorf("address %s was not visited", NetAddressKey(addr))
+		}
+	}
+	if n.numAddresses() != 0 {
+		t.Fatalf("unexpected number of addresses -- got %d, want 0",
+			n.numAddresses())
+	}
+
+	n.AddAddresses(addrs, addrs[0])
+	var visited int
+	n.ForEachAddress(func(ka *KnownAddressSnapshot) bool {
+		visited++
+		return false
+	})
+	if visited != 1 {
+		t.Fatalf("unexpected number of visited addresses -- got %d, want 1",
+			visited)
+	}
+}
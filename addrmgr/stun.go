@@ -0,0 +1,227 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"bytes"
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// stunBindingRequest and stunBindingSuccess are the STUN message types
+	// of a binding request and its success response per RFC 5389.
+	stunBindingRequest = 0x0001
+	stunBindingSuccess = 0x0101
+
+	// stunMappedAddress and stunXorMappedAddress are the types of the STUN
+	// attributes that hold the address the server observed the request
+	// from.
+	stunMappedAddress    = 0x0001
+	stunXorMappedAddress = 0x0020
+
+	// stunMagicCookie is the fixed value of the magic cookie field of all
+	// STUN messages.
+	stunMagicCookie = 0x2112a442
+
+	// stunHeaderLen is the length of the header of a STUN message.
+	stunHeaderLen = 20
+
+	// stunTimeout is the default time allowed for a STUN server to respond
+	// when the context does not specify a deadline.
+	stunTimeout = 5 * time.Second
+)
+
+// ErrStunNoAddress is returned when a STUN response does not contain the
+// address the server observed the request from.
+var ErrStunNoAddress = errors.New("STUN response does not contain an address")
+
+// stunRequest returns a STUN binding request with the provided transaction id.
+func stunRequest(txID [12]byte) []byte {
+	msg := make([]byte, stunHeaderLen)
+	binary.BigEndian.PutUint16(msg[0:2], stunBindingRequest)
+	binary.BigEndian.PutUint16(msg[2:4], 0)
+	binary.BigEndian.PutUint32(msg[4:8], stunMagicCookie)
+	copy(msg[8:20], txID[:])
+	return msg
+}
+
+// parseStunResponse returns the address the STUN server observed the request
+// with the provided transaction id from per the provided binding response.
+// The XOR-MAPPED-ADDRESS attribute is preferred over the MAPPED-ADDRESS
+// attribute used by older servers.
+func parseStunResponse(msg []byte, txID [12]byte) (net.IP, error) {
+	if len(msg) < stunHeaderLen {
+		return nil, fmt.Errorf("STUN response of %d bytes is too short",
+			len(msg))
+	}
+	msgType := binary.BigEndian.Uint16(msg[0:2])
+	msgLen := int(binary.BigEndian.Uint16(msg[2:4]))
+	if msgType != stunBindingSuccess {
+		return nil, fmt.Errorf("unexpected STUN message type %#04x",
+			msgType)
+	}
+	if binary.BigEndian.Uint32(msg[4:8]) != stunMagicCookie ||
+		!bytes.Equal(msg[8:20], txID[:]) {
+
+		return nil, errors.New("STUN response does not match request")
+	}
+	if stunHeaderLen+msgLen > len(msg) {
+		return nil, errors.New("STUN response is truncated")
+	}
+
+	var mapped net.IP
+	attrs := msg[stunHeaderLen : stunHeaderLen+msgLen]
+	for len(attrs) >= 4 {
+		attrType := binary.BigEndian.Uint16(attrs[0:2])
+		attrLen := int(binary.BigEndian.Uint16(attrs[2:4]))
+		if 4+attrLen > len(attrs) {
+			return nil, errors.New("STUN attribute is truncated")
+		}
+		value := attrs[4 : 4+attrLen]
+
+		switch attrType {
+		case stunXorMappedAddress:
+			ip, err := parseStunAddress(value)
+			if err != nil {
+				return nil, err
+			}
+			var mask [16]byte
+			binary.BigEndian.PutUint32(mask[0:4], stunMagicCookie)
+			copy(mask[4:], txID[:])
+			for i := range ip {
+				ip[i] ^= mask[i]
+			}
+			return ip, nil
+
+		case stunMappedAddress:
+			ip, err := parseStunAddress(value)
+			if err != nil {
+				return nil, err
+			}
+			mapped = ip
+		}
+
+		// Attributes are padded to a multiple of 4 bytes.
+		padded := 4 + (attrLen+3)&^3
+		if padded > len(attrs) {
+			break
+		}
+		attrs = attrs[padded:]
+	}
+	if mapped == nil {
+		return nil, ErrStunNoAddress
+	}
+	return mapped, nil
+}
+
+// parseStunAddress returns the IP address encoded in the value of a STUN
+// address attribute.
+func parseStunAddress(value []byte) (net.IP, error) {
+	if len(value) < 4 {
+		return nil, errors.New("STUN address attribute is too short")
+	}
+	var ipLen int
+	switch family := value[1]; family {
+	case 0x01:
+		ipLen = net.IPv4len
+	case 0x02:
+		ipLen = net.IPv6len
+	default:
+		return nil, fmt.Errorf("unknown STUN address family %#02x", family)
+	}
+	if len(value) < 4+ipLen {
+		return nil, errors.New("STUN address attribute is too short")
+	}
+	ip := make(net.IP, ipLen)
+	copy(ip, value[4:4+ipLen])
+	return ip, nil
+}
+
+// QueryStunServer queries the STUN server at the provided address, in the form
+// host:port, over the provided UDP network, either "udp4" or "udp6", and
+// returns the public IP address the server observed the query from.
+func QueryStunServer(ctx context.Context, server, network string) (net.IP, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, stunTimeout)
+		defer cancel()
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	var txID [12]byte
+	if _, err := io.ReadFull(crand.Reader, txID[:]); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(stunRequest(txID)); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 1500)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return parseStunResponse(buf[:n], txID)
+}
+
+// DiscoverStunAddresses queries the provided STUN servers, in the form
+// host:port, to discover the public IPv4 and IPv6 addresses of the local node
+// and adds them as local addresses with the provided port and services at
+// StunPrio.  The servers are queried in order for each address family until
+// one of them responds with a routable address.  It returns the addresses that
+// were added.  No servers are queried when clearnet connections are made
+// through a proxy according to the dial policy since the queries would reveal
+// the network location of the node.
+//
+// This function is safe for concurrent access.
+func (a *AddrManager) DiscoverStunAddresses(ctx context.Context, servers []string, port uint16, services wire.ServiceFlag) []*wire.NetAddress {
+	a.lamtx.Lock()
+	proxied := a.dialPolicy.ClearnetProxied
+	a.lamtx.Unlock()
+	if proxied {
+		return nil
+	}
+
+	var added []*wire.NetAddress
+	for _, network := range []string{"udp4", "udp6"} {
+		for _, server := range servers {
+			ip, err := QueryStunServer(ctx, server, network)
+			if err != nil {
+				log.Debugf("Unable to query STUN server %s over %s: %v",
+					server, network, err)
+				continue
+			}
+			na := wire.NewNetAddressIPPort(ip, port, services)
+			if err := a.AddLocalAddress(na, StunPrio); err != nil {
+				log.Debugf("Skipping address %s discovered via STUN "+
+					"server %s: %v", ip, server, err)
+				continue
+			}
+			log.Infof("Discovered external address %s via STUN server %s",
+				NetAddressKey(na), server)
+			added = append(added, na)
+			break
+		}
+	}
+	return added
+}
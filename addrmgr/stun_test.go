@@ -0,0 +1,147 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package addrmgr
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// stunResponse returns a STUN binding success response for the provided
+// transaction id with an address attribute of the provided type that holds the
+// provided IP address.
+func stunResponse(txID [12]byte, attrType uint16, ip net.IP) []byte {
+	family := byte(0x02)
+	if ip4 := ip.To4(); ip4 != nil {
+		family, ip = 0x01, ip4
+	}
+	value := make([]byte, 4+len(ip))
+	value[1] = family
+	copy(value[4:], ip)
+	if attrType == stunXorMappedAddress {
+		var mask [16]byte
+		binary.BigEndian.PutUint32(mask[0:4], stunMagicCookie)
+		copy(mask[4:], txID[:])
+		for i := range value[4:] {
+			value[4+i] ^= mask[i]
+		}
+	}
+
+	msg := stunRequest(txID)
+	binary.BigEndian.PutUint16(msg[0:2], stunBindingSuccess)
+	binary.BigEndian.PutUint16(msg[2:4], uint16(4+len(value)))
+	var attr [4]byte
+	binary.BigEndian.PutUint16(attr[0:2], attrType)
+	binary.BigEndian.PutUint16(attr[2:4], uint16(len(value)))
+	msg = append(msg, attr[:]...)
+	return append(msg, value...)
+}
+
+// TestParseStunResponse ensures STUN binding responses are parsed as expected.
+func TestParseStunResponse(t *testing.T) {
+	txID := [12]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	otherTxID := [12]byte{12}
+	ipv4 := net.ParseIP("204.124.1.1")
+	ipv6 := net.ParseIP("2620:100::1")
+	tests := []struct {
+		name string
+		msg  []byte
+		want net.IP
+	}{{
+		name: "xor mapped ipv4",
+		msg:  stunResponse(txID, stunXorMappedAddress, ipv4),
+		want: ipv4,
+	}, {
+		name: "xor mapped ipv6",
+		msg:  stunResponse(txID, stunXorMappedAddress, ipv6),
+		want: ipv6,
+	}, {
+		name: "mapped ipv4",
+		msg:  stunResponse(txID, stunMappedAddress, ipv4),
+		want: ipv4,
+	}, {
+		name: "no address",
+		msg:  stunResponse(txID, 0x8022, ipv4),
+	}, {
+		name: "other transaction",
+		msg:  stunResponse(otherTxID, stunXorMappedAddress, ipv4),
+	}, {
+		name: "request",
+		msg:  stunRequest(txID),
+	}, {
+		name: "truncated",
+		msg:  stunResponse(txID, stunXorMappedAddress, ipv4)[:24],
+	}}
+
+	for _, test := range tests {
+		ip, err := parseStunResponse(test.msg, txID)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("%s: unexpected address %v", test.name, ip)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !ip.Equal(test.want) {
+			t.Errorf("%s: unexpected address -- got %v, want %v",
+				test.name, ip, test.want)
+		}
+	}
+}
+
+// TestDiscoverStunAddresses ensures addresses discovered via STUN servers are
+// added as local addresses.
+func TestDiscoverStunAddresses(t *testing.T) {
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer conn.Close()
+
+	// Serve binding requests with a fixed public address.
+	public := net.ParseIP("204.124.1.1")
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < stunHeaderLen {
+				continue
+			}
+			var txID [12]byte
+			copy(txID[:], buf[8:20])
+			resp := stunResponse(txID, stunXorMappedAddress, public)
+			conn.WriteTo(resp, addr)
+		}
+	}()
+
+	n := New("testdiscoverstunaddresses", nil)
+	servers := []string{"127.0.0.1:1", conn.LocalAddr().String()}
+	added := n.DiscoverStunAddresses(context.Background(), servers, 9108,
+		wire.SFNodeNetwork)
+	if len(added) != 1 || !added[0].IP.Equal(public) ||
+		added[0].Port != 9108 {
+
+		t.Fatalf("unexpected discovered addresses %v", added)
+	}
+	if !n.HasLocalAddress(added[0]) {
+		t.Fatal("discovered address was not added")
+	}
+	n.lamtx.Lock()
+	score := n.localAddresses[NetAddressKey(added[0])].score
+	n.lamtx.Unlock()
+	if score != StunPrio {
+		t.Fatalf("unexpected priority -- got %d, want %d", score, StunPrio)
+	}
+}
@@ -213,6 +213,12 @@ type BlockChain struct {
 	// blocks in each of the actively defined deployments.
 	deploymentCaches map[uint32][]thresholdStateCache
 
+	// forcedDeployments houses the threshold states of the deployments that
+	// have been forced to a specific outcome regardless of the votes cast.
+	// It is only used on the simulation and regression test networks and
+	// is protected by the chain lock.
+	forcedDeployments map[string]ThresholdStateTuple
+
 	// pruner is the automatic pruner for block nodes and stake nodes,
 	// so that the memory may be restored by the garbage collector if
 	// it is unlikely to be referenced in the future.
@@ -689,6 +695,10 @@ func (b *BlockChain) connectBlock(node *blockNode, block, parent *dcrutil.Block,
 				TicketsMissed:   []chainhash.Hash{},
 				TicketsNew:      node.stakeNode.NewTickets(),
 			})
+		// Notify of all ticket state transitions
+		b.sendNotification(NTTicketLifecycle,
+			ticketLifecycleNtfnsData(node.hash, node.height,
+				node.stakeNode.Winners(), node.stakeNode.UndoData()))
 	}
 
 	// Optimization: Before checkpoints, immediately dump the parent's stake
@@ -1601,6 +1611,35 @@ func (b *BlockChain) MainChainHasBlock(hash *chainhash.Hash) bool {
 	return node != nil && b.bestChain.Contains(node)
 }
 
+// FetchPrevScripts returns a source of the previous transaction output scripts
+// and their associated script versions spent by the provided block by using the
+// spend journal.  An error is returned when the block is not in the main chain
+// since the spend journal only retains the spent outputs for those blocks.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) FetchPrevScripts(block *dcrutil.Block) (indexers.PrevScripter, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+
+	if !b.MainChainHasBlock(block.Hash()) {
+		str := fmt.Sprintf("block %s is not in the main chain", block.Hash())
+		return nil, errNotInMainChain(str)
+	}
+
+	var prevScripts indexers.PrevScripter
+	err := b.db.View(func(dbTx database.Tx) error {
+		stxos, err := dbFetchSpendJournalEntry(dbTx, block)
+		if err != nil {
+			return err
+		}
+
+		prevScripts = stxosToScriptSource(block, stxos,
+			currentCompressionVersion)
+		return nil
+	})
+	return prevScripts, err
+}
+
 // BlockHeightByHash returns the height of the block with the given hash in the
 // main chain.
 //
@@ -2031,6 +2070,7 @@ func New(ctx context.Context, config *Config) (*BlockChain, error) {
 		bestChain:                     newChainView(nil),
 		mainChainBlockCache:           make(map[chainhash.Hash]*dcrutil.Block),
 		deploymentCaches:              newThresholdCaches(params),
+		forcedDeployments:             make(map[string]ThresholdStateTuple),
 		isVoterMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
 		isStakeMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
 		calcPriorStakeVersionCache:    make(map[[chainhash.HashSize]byte]uint32),
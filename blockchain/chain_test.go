@@ -17,6 +17,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/decred/dcrd/blockchain/stake/v3"
 	"github.com/decred/dcrd/blockchain/v3/chaingen"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
@@ -789,3 +790,105 @@ func TestLocateInventory(t *testing.T) {
 		}
 	}
 }
+
+// TestFetchPrevScripts ensures the previous output scripts spent by a block are
+// loaded from the spend journal for main chain blocks and that requesting them
+// for a side chain block results in an error.
+func TestFetchPrevScripts(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "fetchprevscriptstest")
+	defer teardownFunc()
+
+	// ---------------------------------------------------------------------
+	// Generate and accept enough blocks to reach stake validation height.
+	// ---------------------------------------------------------------------
+
+	g.AdvanceToStakeValidationHeight()
+
+	// ---------------------------------------------------------------------
+	// Create a block that spends a regular output, purchases tickets, and
+	// includes votes along with a side chain block at the same height.
+	//
+	//   ... -> bsv# -> b1
+	//               \-> b1alt
+	// ---------------------------------------------------------------------
+
+	svhTipName := g.TipName()
+	outs := g.OldestCoinbaseOuts()
+	g.NextBlock("b1", &outs[0], outs[1:])
+	g.AcceptTipBlock()
+
+	g.SetTip(svhTipName)
+	g.NextBlock("b1alt", &outs[0], outs[1:])
+	g.AcceptedToSideChainWithExpectedTip("b1")
+
+	// Collect all transactions in the main chain so the spent outputs can be
+	// looked up.
+	txns := make(map[chainhash.Hash]*wire.MsgTx)
+	tipHeight := g.chain.BestSnapshot().Height
+	for height := int64(0); height <= tipHeight; height++ {
+		block, err := g.chain.BlockByHeight(height)
+		if err != nil {
+			t.Fatalf("unable to fetch block at height %d: %v", height, err)
+		}
+		for _, tx := range block.MsgBlock().Transactions {
+			txns[tx.TxHash()] = tx
+		}
+		for _, tx := range block.MsgBlock().STransactions {
+			txns[tx.TxHash()] = tx
+		}
+	}
+
+	// Ensure the scripts of all outputs spent by the main chain block are
+	// available and match the scripts of the referenced outputs.
+	block := dcrutil.NewBlock(g.BlockByName("b1"))
+	prevScripts, err := g.chain.FetchPrevScripts(block)
+	if err != nil {
+		t.Fatalf("unexpected error fetching prev scripts: %v", err)
+	}
+	var numInputs int
+	checkInputs := func(tx *wire.MsgTx, skipFirst bool) {
+		for txInIdx, txIn := range tx.TxIn {
+			if skipFirst && txInIdx == 0 {
+				continue
+			}
+			numInputs++
+
+			prevOut := &txIn.PreviousOutPoint
+			version, script, ok := prevScripts.PrevScript(prevOut)
+			if !ok {
+				t.Fatalf("missing prev script for %v", prevOut)
+			}
+			originTx, ok := txns[prevOut.Hash]
+			if !ok {
+				t.Fatalf("unable to find origin tx for %v", prevOut)
+			}
+			txOut := originTx.TxOut[prevOut.Index]
+			if version != txOut.Version ||
+				!bytes.Equal(script, txOut.PkScript) {
+
+				t.Fatalf("mismatched prev script for %v -- got (%d, %x), "+
+					"want (%d, %x)", prevOut, version, script,
+					txOut.Version, txOut.PkScript)
+			}
+		}
+	}
+	for _, tx := range block.MsgBlock().STransactions {
+		checkInputs(tx, stake.IsSSGen(tx))
+	}
+	for _, tx := range block.MsgBlock().Transactions[1:] {
+		checkInputs(tx, false)
+	}
+	if numInputs == 0 {
+		t.Fatal("test block does not spend any outputs")
+	}
+
+	// Ensure requesting the scripts for a side chain block fails.
+	sideBlock := dcrutil.NewBlock(g.BlockByName("b1alt"))
+	_, err = g.chain.FetchPrevScripts(sideBlock)
+	if !isNotInMainChainErr(err) {
+		t.Fatalf("unexpected error for side chain block -- got %v, want "+
+			"not in main chain error", err)
+	}
+}
@@ -141,6 +141,7 @@ func newFakeChain(params *chaincfg.Params) *BlockChain {
 		deploymentVers:                deploymentVers,
 		chainParams:                   params,
 		deploymentCaches:              newThresholdCaches(params),
+		forcedDeployments:             make(map[string]ThresholdStateTuple),
 		index:                         index,
 		bestChain:                     newChainView(node),
 		isVoterMajorityVersionCache:   make(map[[stakeMajorityCacheKeySize]byte]bool),
@@ -51,6 +51,16 @@ func (e DuplicateDeploymentError) Error() string {
 		string(e))
 }
 
+// ChoiceError identifies an error that indicates a vote choice ID was specified
+// that does not exist for a deployment.
+type ChoiceError string
+
+// Error returns the error as a human-readable string and satisfies the error
+// interface.
+func (e ChoiceError) Error() string {
+	return fmt.Sprintf("choice ID %v does not exist", string(e))
+}
+
 // NoFilterError identifies an error that indicates a filter for a given block
 // hash does not exist.
 type NoFilterError string
@@ -7,6 +7,7 @@ package blockchain
 import (
 	"fmt"
 
+	"github.com/decred/dcrd/blockchain/standalone"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/database/v2"
 	"github.com/decred/dcrd/dcrutil/v3"
@@ -139,3 +140,155 @@ func (b *BlockChain) FilterByBlockHash(hash *chainhash.Hash) (*gcs.FilterV2, err
 	}
 	return filter, err
 }
+
+// FilterCommitment houses the hash of the version 2 GCS filter for a block
+// along with the header of the block and the inclusion proof that links the
+// filter hash to the commitment root in the header.
+type FilterCommitment struct {
+	// Header is the header of the block the filter is for.
+	Header wire.BlockHeader
+
+	// FilterHash is the hash of the version 2 GCS filter for the block.
+	FilterHash chainhash.Hash
+
+	// ProofIndex and ProofHashes are the index of the filter hash in the
+	// header commitment merkle tree and the inclusion proof for it.
+	ProofIndex  uint32
+	ProofHashes []chainhash.Hash
+
+	// Committed indicates whether or not the header commits to the filter via
+	// the commitment root.  Headers only commit to filters once the header
+	// commitments agenda defined in DCP0005 is active.
+	Committed bool
+}
+
+// VerifyFilterCommitment returns whether or not the provided inclusion proof
+// proves the provided filter hash is committed to by the commitment root in the
+// provided block header.  This allows clients to verify the filters they
+// receive for a block are the ones committed to by its header.
+//
+// Note that headers only commit to filters once the header commitments agenda
+// defined in DCP0005 is active, so this will return false for the headers of
+// blocks prior to that point.
+//
+// This function is safe for concurrent access.
+func VerifyFilterCommitment(header *wire.BlockHeader, filterHash *chainhash.Hash, proofIndex uint32, proofHashes []chainhash.Hash) bool {
+	return standalone.VerifyInclusionProof(&header.StakeRoot, filterHash,
+		proofIndex, proofHashes)
+}
+
+// forEachMainChainFilter invokes the provided function with the block node and
+// version 2 GCS filter for up to the provided maximum number of consecutive
+// blocks in the main chain starting at the provided height.  Fewer blocks than
+// the maximum are visited when the range extends beyond the current tip of the
+// main chain.
+//
+// This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) forEachMainChainFilter(startHeight int64, maxEntries int, fn func(node *blockNode, filter *gcs.FilterV2) error) error {
+	startNode := b.bestChain.NodeByHeight(startHeight)
+	if startNode == nil {
+		str := fmt.Sprintf("no block at height %d exists", startHeight)
+		return errNotInMainChain(str)
+	}
+
+	tipHeight := b.bestChain.Tip().height
+	endHeight := tipHeight + 1
+	if endHeight-startHeight > int64(maxEntries) {
+		endHeight = startHeight + int64(maxEntries)
+	}
+
+	return b.db.View(func(dbTx database.Tx) error {
+		for height := startHeight; height < endHeight; height++ {
+			node := b.bestChain.NodeByHeight(height)
+			filter, err := dbFetchGCSFilter(dbTx, &node.hash)
+			if err != nil {
+				return err
+			}
+			if filter == nil {
+				return NoFilterError(node.hash.String())
+			}
+			if err := fn(node, filter); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// MainChainFilters returns the version 2 GCS filters for up to the provided
+// maximum number of consecutive blocks in the main chain starting at the
+// provided height along with the hashes of the blocks.  Fewer filters than the
+// maximum are returned when the range extends beyond the current tip of the
+// main chain.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) MainChainFilters(startHeight int64, maxEntries int) ([]chainhash.Hash, []*gcs.FilterV2, error) {
+	// Hold the chain lock for the duration so the main chain can't change
+	// while the filters are being loaded.
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	var hashes []chainhash.Hash
+	var filters []*gcs.FilterV2
+	err := b.forEachMainChainFilter(startHeight, maxEntries,
+		func(node *blockNode, filter *gcs.FilterV2) error {
+			hashes = append(hashes, node.hash)
+			filters = append(filters, filter)
+			return nil
+		})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hashes, filters, nil
+}
+
+// MainChainFilterCommitments returns the filter commitments for up to the
+// provided maximum number of consecutive blocks in the main chain starting at
+// the provided height.  Fewer commitments than the maximum are returned when
+// the range extends beyond the current tip of the main chain.
+//
+// All of the returned commitments are from the same point of view of the main
+// chain, so the headers always form a chain that links to one another.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) MainChainFilterCommitments(startHeight int64, maxEntries int) ([]FilterCommitment, error) {
+	// Hold the chain lock for the duration so the main chain can't change
+	// while the commitments are being loaded.
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+
+	var commitments []FilterCommitment
+	err := b.forEachMainChainFilter(startHeight, maxEntries,
+		func(node *blockNode, filter *gcs.FilterV2) error {
+			// The genesis block has no parent and therefore never commits
+			// to its filter.
+			var committed bool
+			if node.parent != nil {
+				var err error
+				committed, err = b.isHeaderCommitmentsAgendaActive(node.parent)
+				if err != nil {
+					return err
+				}
+			}
+
+			// NOTE: When more header commitments are added, this will need
+			// to load the inclusion proof for the filter from the database.
+			// However, since there is only currently a single commitment,
+			// there is only a single leaf in the commitment merkle tree, and
+			// hence the proof hashes will always be empty given there are no
+			// siblings.
+			commitments = append(commitments, FilterCommitment{
+				Header:     node.Header(),
+				FilterHash: filter.Hash(),
+				ProofIndex: HeaderCmtFilterIndex,
+				Committed:  committed,
+			})
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return commitments, nil
+}
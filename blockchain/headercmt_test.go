@@ -5,9 +5,12 @@
 package blockchain
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
 )
 
 // TestCalcCommitmentRootV1 ensures the expected version 1 commitment root is
@@ -54,3 +57,138 @@ func TestCalcCommitmentRootV1(t *testing.T) {
 		}
 	}
 }
+
+// TestVerifyFilterCommitment ensures verifying filter commitments against block
+// headers produces the expected results.
+func TestVerifyFilterCommitment(t *testing.T) {
+	filterHash := chainhash.HashH([]byte("filter"))
+	otherHash := chainhash.HashH([]byte("other"))
+	header := wire.BlockHeader{StakeRoot: CalcCommitmentRootV1(filterHash)}
+
+	if !VerifyFilterCommitment(&header, &filterHash, HeaderCmtFilterIndex, nil) {
+		t.Fatal("failed to verify committed filter hash")
+	}
+	if VerifyFilterCommitment(&header, &otherHash, HeaderCmtFilterIndex, nil) {
+		t.Fatal("verified filter hash that is not committed")
+	}
+	if VerifyFilterCommitment(&header, &filterHash, HeaderCmtFilterIndex,
+		[]chainhash.Hash{otherHash}) {
+
+		t.Fatal("verified filter hash with invalid proof")
+	}
+}
+
+// TestMainChainFilterCommitments ensures the filter commitments and filters for
+// ranges of main chain blocks are returned as expected.
+func TestMainChainFilterCommitments(t *testing.T) {
+	// Create a test harness initialized with the genesis block as the tip and
+	// extend the main chain by a few blocks.
+	params := chaincfg.RegNetParams()
+	g, teardownFunc := newChaingenHarness(t, params, "filtercommitmentstest")
+	defer teardownFunc()
+
+	g.CreateBlockOne("bfb", 0)
+	g.AcceptTipBlock()
+	for i := 0; i < 3; i++ {
+		g.NextBlock(fmt.Sprintf("bm%d", i), nil, nil)
+		g.AcceptTipBlock()
+	}
+
+	// Ensure the commitments for the entire chain link to one another and
+	// are for the expected filters.  Note that the header commitments agenda
+	// is not active on the test chain, so none of the headers commit to the
+	// filters.
+	const tipHeight = 4
+	commitments, err := g.chain.MainChainFilterCommitments(0, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commitments) != tipHeight+1 {
+		t.Fatalf("unexpected number of commitments -- got %d, want %d",
+			len(commitments), tipHeight+1)
+	}
+	for i := range commitments {
+		commitment := &commitments[i]
+		header := &commitment.Header
+		if header.Height != uint32(i) {
+			t.Fatalf("unexpected height for commitment %d -- got %d", i,
+				header.Height)
+		}
+		if i > 0 && header.PrevBlock != commitments[i-1].Header.BlockHash() {
+			t.Fatalf("commitment %d header does not link to previous", i)
+		}
+
+		blockHash := header.BlockHash()
+		filter, err := g.chain.FilterByBlockHash(&blockHash)
+		if err != nil {
+			t.Fatalf("unexpected error loading filter: %v", err)
+		}
+		if commitment.FilterHash != filter.Hash() {
+			t.Fatalf("mismatched filter hash for commitment %d -- got %v, "+
+				"want %v", i, commitment.FilterHash, filter.Hash())
+		}
+		if commitment.ProofIndex != HeaderCmtFilterIndex ||
+			len(commitment.ProofHashes) != 0 {
+
+			t.Fatalf("unexpected proof for commitment %d", i)
+		}
+		if commitment.Committed {
+			t.Fatalf("unexpected committed status for commitment %d", i)
+		}
+	}
+
+	// Ensure the number of commitments is limited by the requested maximum
+	// and the tip of the main chain.
+	commitments, err = g.chain.MainChainFilterCommitments(1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commitments) != 2 || commitments[0].Header.Height != 1 {
+		t.Fatalf("unexpected commitments for limited range: %v", commitments)
+	}
+	commitments, err = g.chain.MainChainFilterCommitments(tipHeight, 100)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commitments) != 1 || commitments[0].Header.Height != tipHeight {
+		t.Fatalf("unexpected commitments at tip: %v", commitments)
+	}
+
+	// Ensure requesting commitments beyond the tip of the main chain fails.
+	_, err = g.chain.MainChainFilterCommitments(tipHeight+1, 100)
+	if err == nil {
+		t.Fatal("did not receive error for start height beyond the tip")
+	}
+
+	// Ensure the filters for a range are the same ones associated with the
+	// block hashes and are limited by the requested maximum.
+	hashes, filters, err := g.chain.MainChainFilters(1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hashes) != 2 || len(filters) != 2 {
+		t.Fatalf("unexpected number of filters -- got %d hashes and %d "+
+			"filters, want 2", len(hashes), len(filters))
+	}
+	for i := range hashes {
+		wantHash, err := g.chain.BlockHashByHeight(int64(i + 1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if hashes[i] != *wantHash {
+			t.Fatalf("mismatched hash for filter %d -- got %v, want %v", i,
+				hashes[i], wantHash)
+		}
+		wantFilter, err := g.chain.FilterByBlockHash(wantHash)
+		if err != nil {
+			t.Fatalf("unexpected error loading filter: %v", err)
+		}
+		if filters[i].Hash() != wantFilter.Hash() {
+			t.Fatalf("mismatched filter %d", i)
+		}
+	}
+	_, _, err = g.chain.MainChainFilters(tipHeight+1, 100)
+	if err == nil {
+		t.Fatal("did not receive error for start height beyond the tip")
+	}
+}
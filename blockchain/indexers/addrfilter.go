@@ -0,0 +1,94 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+const (
+	// addrFilterBitsPerEntry is the number of filter bits allocated per
+	// expected entry in an address filter.  Combined with the number of hash
+	// functions below, this results in a false positive rate of roughly 1%
+	// when the filter is at capacity.
+	addrFilterBitsPerEntry = 10
+
+	// addrFilterNumHashes is the number of hash functions used to set and
+	// test bits in an address filter.
+	addrFilterNumHashes = 7
+
+	// addrFilterMinEntries is the minimum number of entries an address
+	// filter is sized for.
+	addrFilterMinEntries = 1 << 20
+)
+
+// addrFilter is a bloom filter over address keys that is used to quickly
+// determine when an address is definitely not in an index without needing to
+// consult the database.  Since address keys consist of a type byte followed by
+// a hash, the key bytes themselves are used to derive the bit positions via
+// double hashing as opposed to hashing them again.
+//
+// The filter never reports false negatives, but it does report false positives
+// at a rate that increases as the number of entries exceeds the capacity it
+// was sized for.
+type addrFilter struct {
+	mtx  sync.RWMutex
+	bits []uint64
+}
+
+// newAddrFilter returns a new address filter sized for the provided number of
+// expected entries.
+func newAddrFilter(expectedEntries uint64) *addrFilter {
+	if expectedEntries < addrFilterMinEntries {
+		expectedEntries = addrFilterMinEntries
+	}
+	numWords := (expectedEntries*addrFilterBitsPerEntry + 63) / 64
+	if numWords > math.MaxInt32 {
+		numWords = math.MaxInt32
+	}
+	return &addrFilter{bits: make([]uint64, numWords)}
+}
+
+// positions returns the base and step used to derive the bit positions for the
+// provided address key.
+func (f *addrFilter) positions(k *[addrKeySize]byte) (uint64, uint64) {
+	h1 := binary.LittleEndian.Uint64(k[1:9]) ^ uint64(k[0])
+	h2 := binary.LittleEndian.Uint64(k[9:17]) | 1
+	return h1, h2
+}
+
+// add adds the provided address key to the filter.
+//
+// This function is safe for concurrent access.
+func (f *addrFilter) add(k *[addrKeySize]byte) {
+	numBits := uint64(len(f.bits)) * 64
+	h1, h2 := f.positions(k)
+	f.mtx.Lock()
+	for i := uint64(0); i < addrFilterNumHashes; i++ {
+		bit := (h1 + i*h2) % numBits
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+	f.mtx.Unlock()
+}
+
+// mayContain returns whether or not the provided address key might be in the
+// filter.  A false result means the key is definitely not in the filter.
+//
+// This function is safe for concurrent access.
+func (f *addrFilter) mayContain(k *[addrKeySize]byte) bool {
+	numBits := uint64(len(f.bits)) * 64
+	h1, h2 := f.positions(k)
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	for i := uint64(0); i < addrFilterNumHashes; i++ {
+		bit := (h1 + i*h2) % numBits
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// TestAddrFilter ensures the address filter never reports false negatives and
+// that the false positive rate is reasonable when at capacity.
+func TestAddrFilter(t *testing.T) {
+	t.Parallel()
+
+	const numEntries = addrFilterMinEntries
+	prng := rand.New(rand.NewSource(1))
+	randKey := func() [addrKeySize]byte {
+		var k [addrKeySize]byte
+		prng.Read(k[:])
+		return k
+	}
+
+	// Ensure all added keys are reported as possibly existing.
+	filter := newAddrFilter(numEntries)
+	keys := make([][addrKeySize]byte, 0, numEntries)
+	for i := 0; i < numEntries; i++ {
+		k := randKey()
+		filter.add(&k)
+		keys = append(keys, k)
+	}
+	for i := range keys {
+		if !filter.mayContain(&keys[i]) {
+			t.Fatalf("filter does not contain added key %x", keys[i])
+		}
+	}
+
+	// Ensure the false positive rate for keys that were never added is within
+	// the expected range.
+	const numTests = 100000
+	var falsePositives int
+	for i := 0; i < numTests; i++ {
+		k := randKey()
+		if filter.mayContain(&k) {
+			falsePositives++
+		}
+	}
+	if rate := float64(falsePositives) / numTests; rate > 0.02 {
+		t.Fatalf("unexpected false positive rate: got %v, want <= 0.02", rate)
+	}
+}
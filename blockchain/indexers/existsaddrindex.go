@@ -5,7 +5,9 @@
 package indexers
 
 import (
+	"bytes"
 	"context"
+	"sort"
 	"sync"
 
 	"github.com/decred/dcrd/blockchain/stake/v3"
@@ -48,6 +50,11 @@ type ExistsAddrIndex struct {
 	db          database.DB
 	chainParams *chaincfg.Params
 
+	// filter is a bloom filter of all addresses in the index which is used to
+	// avoid database lookups for addresses that have definitely never been
+	// seen.  It is created when the index is initialized.
+	filter *addrFilter
+
 	// The following fields are used to quickly link transactions and
 	// addresses that have not been included into a block yet when an
 	// address index is being maintained.  The are protected by the
@@ -82,13 +89,46 @@ func NewExistsAddrIndex(db database.DB, chainParams *chaincfg.Params) *ExistsAdd
 // Ensure the ExistsAddrIndex type implements the Indexer interface.
 var _ Indexer = (*ExistsAddrIndex)(nil)
 
-// Init is only provided to satisfy the Indexer interface as there is nothing to
-// initialize for this index.
+// Init initializes the exists address index.  In particular, it loads all of
+// the addresses in the index into a bloom filter which allows queries for
+// addresses that have never been seen to be answered without needing to
+// consult the database.
 //
 // This is part of the Indexer interface.
 func (idx *ExistsAddrIndex) Init() error {
-	// Nothing to do.
-	return nil
+	return idx.db.View(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(existsAddrIndexKey)
+
+		// Size the filter to allow the index to double in size before the
+		// false positive rate starts to degrade.
+		var numAddrs uint64
+		err := bucket.ForEach(func(k, _ []byte) error {
+			numAddrs++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		filter := newAddrFilter(numAddrs * 2)
+
+		err = bucket.ForEach(func(k, _ []byte) error {
+			if len(k) != addrKeySize {
+				return nil
+			}
+			var addrKey [addrKeySize]byte
+			copy(addrKey[:], k)
+			filter.add(&addrKey)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Debugf("Loaded %d addresses into the %s filter", numAddrs,
+			existsAddressIndexName)
+		idx.filter = filter
+		return nil
+	})
 }
 
 // Key returns the database key to use for the index as a byte slice.
@@ -128,10 +168,17 @@ func dbPutExistsAddr(bucket internalBucket, addrKey [addrKeySize]byte) error {
 	return bucket.Put(addrKey[:], nil)
 }
 
+// mayExistInDB returns whether or not the provided address key might exist in
+// the database.  A false result means it definitely does not exist and hence
+// the database does not need to be consulted.
+func (idx *ExistsAddrIndex) mayExistInDB(k *[addrKeySize]byte) bool {
+	return idx.filter == nil || idx.filter.mayContain(k)
+}
+
 // existsAddress takes a bucket and key for an address and responds with
 // whether or not the key exists in the database.
 func (idx *ExistsAddrIndex) existsAddress(bucket internalBucket, k [addrKeySize]byte) bool {
-	if bucket.Get(k[:]) != nil {
+	if idx.mayExistInDB(&k) && bucket.Get(k[:]) != nil {
 		return true
 	}
 
@@ -151,15 +198,17 @@ func (idx *ExistsAddrIndex) ExistsAddress(addr dcrutil.Address) (bool, error) {
 	}
 
 	var exists bool
-	err = idx.db.View(func(dbTx database.Tx) error {
-		meta := dbTx.Metadata()
-		existsAddrIndex := meta.Bucket(existsAddrIndexKey)
-		exists = existsAddrIndex.Get(k[:]) != nil
+	if idx.mayExistInDB(&k) {
+		err = idx.db.View(func(dbTx database.Tx) error {
+			meta := dbTx.Metadata()
+			existsAddrIndex := meta.Bucket(existsAddrIndexKey)
+			exists = existsAddrIndex.Get(k[:]) != nil
 
-		return nil
-	})
-	if err != nil {
-		return false, err
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
 	}
 
 	// Only check the in memory map if needed.
@@ -174,6 +223,11 @@ func (idx *ExistsAddrIndex) ExistsAddress(addr dcrutil.Address) (bool, error) {
 
 // ExistsAddresses is the concurrency safe, exported function that returns
 // whether or not each address in a slice of addresses has been seen before.
+//
+// Large batches are handled efficiently since addresses that have definitely
+// never been seen are filtered out without consulting the database and the
+// remaining addresses are looked up in key order in a single database
+// transaction.
 func (idx *ExistsAddrIndex) ExistsAddresses(addrs []dcrutil.Address) ([]bool, error) {
 	exists := make([]bool, len(addrs))
 	addrKeys := make([][addrKeySize]byte, len(addrs))
@@ -185,18 +239,32 @@ func (idx *ExistsAddrIndex) ExistsAddresses(addrs []dcrutil.Address) ([]bool, er
 		}
 	}
 
-	err := idx.db.View(func(dbTx database.Tx) error {
-		for i := range addrKeys {
+	// Determine which addresses need to be looked up in the database and
+	// sort them by key so the lookups are performed in key order.
+	lookups := make([]int, 0, len(addrKeys))
+	for i := range addrKeys {
+		if idx.mayExistInDB(&addrKeys[i]) {
+			lookups = append(lookups, i)
+		}
+	}
+	sort.Slice(lookups, func(i, j int) bool {
+		a, b := &addrKeys[lookups[i]], &addrKeys[lookups[j]]
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+
+	if len(lookups) > 0 {
+		err := idx.db.View(func(dbTx database.Tx) error {
 			meta := dbTx.Metadata()
 			existsAddrIndex := meta.Bucket(existsAddrIndexKey)
+			for _, i := range lookups {
+				exists[i] = existsAddrIndex.Get(addrKeys[i][:]) != nil
+			}
 
-			exists[i] = existsAddrIndex.Get(addrKeys[i][:]) != nil
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
-
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
 
 	idx.unconfirmedLock.RLock()
@@ -322,6 +390,9 @@ func (idx *ExistsAddrIndex) ConnectBlock(dbTx database.Tx, block, parent *dcruti
 		if err != nil {
 			return err
 		}
+		if idx.filter != nil {
+			idx.filter.add(&addrKey)
+		}
 	}
 
 	return nil
@@ -8,6 +8,9 @@ package indexers
 import (
 	"context"
 	"fmt"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/decred/dcrd/blockchain/v3/internal/progresslog"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -16,6 +19,21 @@ import (
 	"github.com/decred/dcrd/dcrutil/v3"
 )
 
+const (
+	// catchupLogInterval is the minimum amount of time between log messages
+	// that report the overall progress of catching up the indexes.
+	catchupLogInterval = time.Minute
+
+	// catchupBatchSize is the maximum number of blocks that are connected to
+	// the indexes in a single database transaction while catching up.
+	catchupBatchSize = 100
+
+	// catchupLookaheadFactor is the number of blocks per worker that are
+	// loaded ahead of the blocks being connected to the indexes while
+	// catching up.
+	catchupLookaheadFactor = 4
+)
+
 var (
 	// indexTipsBucketName is the name of the db bucket used to house the
 	// current tip of each index.
@@ -181,6 +199,94 @@ type Manager struct {
 	params         *chaincfg.Params
 	db             database.DB
 	enabledIndexes []Indexer
+
+	// These fields track the progress of catching up the indexes to the
+	// main chain.  The target height is zero when the indexes are not
+	// being caught up.
+	progressMtx   sync.Mutex
+	catchupStart  time.Time
+	catchupFrom   int32
+	catchupHeight int32
+	catchupTarget int32
+}
+
+// IndexProgress describes the current state of an index managed by the index
+// manager.
+type IndexProgress struct {
+	// Name is the human-readable name of the index.
+	Name string
+
+	// Height is the height of the current tip of the index.
+	Height int64
+
+	// TargetHeight is the height the index is being caught up to.  It is zero
+	// when the index is not being caught up.
+	TargetHeight int64
+
+	// ETA is the estimated amount of time remaining until the index is caught
+	// up.  It is zero when the index is not being caught up or there is not
+	// enough information to provide an estimate yet.
+	ETA time.Duration
+}
+
+// estimateRemaining returns the estimated amount of time required to process
+// the remaining number of items given the amount of time it took to process the
+// completed number of items.  Zero is returned when no items have been
+// completed yet.
+func estimateRemaining(elapsed time.Duration, completed, remaining int64) time.Duration {
+	if completed <= 0 || remaining <= 0 {
+		return 0
+	}
+	perItem := float64(elapsed) / float64(completed)
+	return time.Duration(perItem * float64(remaining)).Round(time.Second)
+}
+
+// catchupETA returns the estimated amount of time remaining until the indexes
+// are caught up based on the rate blocks have been indexed so far.
+//
+// This function MUST be called with the progress mutex held.
+func (m *Manager) catchupETA() time.Duration {
+	return estimateRemaining(time.Since(m.catchupStart),
+		int64(m.catchupHeight-m.catchupFrom),
+		int64(m.catchupTarget-m.catchupHeight))
+}
+
+// Progress returns the current state of each of the enabled indexes, including
+// the target height and estimated time remaining for any indexes that are being
+// caught up to the main chain.
+//
+// This function is safe for concurrent access.
+func (m *Manager) Progress() ([]IndexProgress, error) {
+	progress := make([]IndexProgress, 0, len(m.enabledIndexes))
+	err := m.db.View(func(dbTx database.Tx) error {
+		for _, indexer := range m.enabledIndexes {
+			_, height, err := dbFetchIndexerTip(dbTx, indexer.Key())
+			if err != nil {
+				return err
+			}
+			progress = append(progress, IndexProgress{
+				Name:   indexer.Name(),
+				Height: int64(height),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.progressMtx.Lock()
+	if m.catchupTarget != 0 {
+		eta := m.catchupETA()
+		for i := range progress {
+			if progress[i].Height < int64(m.catchupTarget) {
+				progress[i].TargetHeight = int64(m.catchupTarget)
+				progress[i].ETA = eta
+			}
+		}
+	}
+	m.progressMtx.Unlock()
+	return progress, nil
 }
 
 // Ensure the Manager type implements the IndexManager interface.
@@ -549,74 +655,118 @@ func (m *Manager) Init(ctx context.Context, chain ChainQueryer) error {
 	// At this point, one or more indexes are behind the current best chain
 	// tip and need to be caught up, so log the details and loop through
 	// each block that needs to be indexed.
-	log.Infof("Catching up indexes from height %d to %d", lowestHeight,
-		bestHeight)
+	//
+	// Note that the tip of each index is updated in the same database
+	// transaction as the entries for each block, so the catchup process
+	// resumes from the last indexed block when it is interrupted.
+	if lowestHeight > 0 {
+		log.Infof("Resuming index catchup from height %d to %d",
+			lowestHeight, bestHeight)
+	} else {
+		log.Infof("Catching up indexes from height %d to %d",
+			lowestHeight, bestHeight)
+	}
 
-	var cachedParent *dcrutil.Block
-	for height := lowestHeight + 1; height <= bestHeight; height++ {
+	// Track the catchup progress so it can be queried and logged.
+	m.progressMtx.Lock()
+	m.catchupStart = time.Now()
+	m.catchupFrom = lowestHeight
+	m.catchupHeight = lowestHeight
+	m.catchupTarget = bestHeight
+	m.progressMtx.Unlock()
+	defer func() {
+		m.progressMtx.Lock()
+		m.catchupTarget = 0
+		m.progressMtx.Unlock()
+	}()
+	lastProgressLog := time.Now()
+
+	// Determine the lowest height at which the blocks need to be loaded along
+	// with the previous scripts they reference since only some indexes
+	// require them.
+	inputsHeight := bestHeight
+	for i, indexer := range m.enabledIndexes {
+		if indexNeedsInputs(indexer) && indexerHeights[i] < inputsHeight {
+			inputsHeight = indexerHeights[i]
+		}
+	}
+
+	// Load the blocks that need to be indexed concurrently ahead of the
+	// indexing process below.  The derived context ensures the workers are
+	// stopped when the catchup process returns early.
+	fetchCtx, cancelFetch := context.WithCancel(ctx)
+	defer cancelFetch()
+	pending := fetchCatchupBlocks(fetchCtx, m.db, chain, lowestHeight+1,
+		bestHeight, inputsHeight)
+
+	// Load the parent of the first block to index.
+	var parent *dcrutil.Block
+	err = m.db.View(func(dbTx database.Tx) error {
+		parentHash, err := chain.BlockHashByHeight(int64(lowestHeight))
+		if err != nil {
+			return err
+		}
+		parent, err = dbFetchBlockByHash(dbTx, parentHash)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Connect the blocks to the indexes that need them in batches in order
+	// to reduce the overhead of committing a database transaction for every
+	// block.
+	height := lowestHeight
+	for height < bestHeight {
 		if interruptRequested(ctx) {
 			return errInterruptRequested
 		}
 
-		var block, parent *dcrutil.Block
+		var interrupted bool
 		err = m.db.Update(func(dbTx database.Tx) error {
-			// Get the parent of the block, unless it's already cached.
-			if cachedParent == nil && height > 0 {
-				parentHash, err := chain.BlockHashByHeight(int64(height - 1))
-				if err != nil {
-					return err
-				}
-				parent, err = dbFetchBlockByHash(dbTx, parentHash)
-				if err != nil {
-					return err
+			for i := 0; i < catchupBatchSize && height < bestHeight; i++ {
+				// NOTE: This does not return as it does elsewhere
+				// since it would cause the database transaction to
+				// rollback and undo all work that has been done in
+				// the batch.
+				if interruptRequested(ctx) {
+					interrupted = true
+					return nil
 				}
-			} else {
-				parent = cachedParent
-			}
-
-			// Load the block for the height since it is required to index
-			// it.
-			hash, err := chain.BlockHashByHeight(int64(height))
-			if err != nil {
-				return err
-			}
-			block, err = dbFetchBlockByHash(dbTx, hash)
-			if err != nil {
-				return err
-			}
-			cachedParent = block
 
-			if interruptRequested(ctx) {
-				return errInterruptRequested
-			}
-
-			// Connect the block for all indexes that need it.
-			var prevScripts PrevScripter
-			for i, indexer := range m.enabledIndexes {
-				// Skip indexes that don't need to be updated with this
-				// block.
-				if indexerHeights[i] >= height {
-					continue
+				// Wait for the next block to be loaded.
+				result, ok := <-pending
+				if !ok {
+					interrupted = true
+					return nil
 				}
+				fetched := <-result
+				if fetched.err != nil {
+					return fetched.err
+				}
+				block := fetched.block
+
+				// Connect the block for all indexes that need it.
+				nextHeight := height + 1
+				for i, indexer := range m.enabledIndexes {
+					// Skip indexes that don't need to be updated with
+					// this block.
+					if indexerHeights[i] >= nextHeight {
+						continue
+					}
 
-				// When the index requires all of the referenced
-				// txouts and they haven't been loaded yet, they
-				// need to be retrieved from the transaction
-				// index.
-				if prevScripts == nil && indexNeedsInputs(indexer) {
-					var errMakeView error
-					prevScripts, errMakeView = chain.PrevScripts(dbTx, block)
-					if errMakeView != nil {
-						return errMakeView
+					err := dbIndexConnectBlock(dbTx, indexer, block,
+						parent, fetched.prevScripts)
+					if err != nil {
+						return err
 					}
-				}
-				err = dbIndexConnectBlock(dbTx, indexer, block,
-					parent, prevScripts)
-				if err != nil {
-					return err
-				}
 
-				indexerHeights[i] = height
+					indexerHeights[i] = nextHeight
+				}
+				progressLogger.LogBlockHeight(block.MsgBlock(),
+					parent.MsgBlock())
+				parent = block
+				height = nextHeight
 			}
 
 			return nil
@@ -624,13 +774,106 @@ func (m *Manager) Init(ctx context.Context, chain ChainQueryer) error {
 		if err != nil {
 			return err
 		}
-		progressLogger.LogBlockHeight(block.MsgBlock(), parent.MsgBlock())
+		if interrupted {
+			return errInterruptRequested
+		}
+
+		// Update the catchup progress and periodically log the overall
+		// progress along with the estimated time remaining.
+		m.progressMtx.Lock()
+		m.catchupHeight = height
+		eta := m.catchupETA()
+		m.progressMtx.Unlock()
+		if now := time.Now(); now.Sub(lastProgressLog) >= catchupLogInterval {
+			percent := float64(height) / float64(bestHeight) * 100
+			log.Infof("Index catchup %.2f%% complete (height %d of %d, "+
+				"about %v remaining)", percent, height, bestHeight, eta)
+			lastProgressLog = now
+		}
 	}
 
 	log.Infof("Indexes caught up to height %d", bestHeight)
 	return nil
 }
 
+// catchupBlock houses a block loaded for the purposes of catching up the
+// indexes along with the previous scripts it references when they are needed.
+type catchupBlock struct {
+	block       *dcrutil.Block
+	prevScripts PrevScripter
+	err         error
+}
+
+// fetchCatchupBlock loads the main chain block at the provided height.  The
+// previous scripts referenced by the block are also loaded when the height is
+// greater than the provided inputs height.
+func fetchCatchupBlock(db database.DB, chain ChainQueryer, height, inputsHeight int32) *catchupBlock {
+	var result catchupBlock
+	result.err = db.View(func(dbTx database.Tx) error {
+		hash, err := chain.BlockHashByHeight(int64(height))
+		if err != nil {
+			return err
+		}
+		result.block, err = dbFetchBlockByHash(dbTx, hash)
+		if err != nil {
+			return err
+		}
+
+		if height > inputsHeight {
+			result.prevScripts, err = chain.PrevScripts(dbTx, result.block)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return &result
+}
+
+// fetchCatchupBlocks concurrently loads the main chain blocks in the provided
+// range of heights, along with the previous scripts they reference for heights
+// greater than the provided inputs height, by using a worker per available
+// processor.
+//
+// The returned channel delivers a channel per height in ascending order which
+// in turn delivers the loaded block for that height once it is available.  This
+// allows the blocks to be loaded out of order while still being consumed in
+// order.  The returned channel is closed once all of the blocks have been
+// dispatched or the context is canceled.
+//
+// NOTE: Reading from the database is safe while the blocks are being indexed
+// since database read transactions operate on a snapshot and are independent
+// of the single write transaction.
+func fetchCatchupBlocks(ctx context.Context, db database.DB, chain ChainQueryer, startHeight, endHeight, inputsHeight int32) <-chan chan *catchupBlock {
+	numWorkers := runtime.NumCPU()
+	pending := make(chan chan *catchupBlock, numWorkers*catchupLookaheadFactor)
+	workerSem := make(chan struct{}, numWorkers)
+	go func() {
+		defer close(pending)
+		for height := startHeight; height <= endHeight; height++ {
+			// Deliver the result channel for the height in order and wait
+			// for a worker to become available.
+			result := make(chan *catchupBlock, 1)
+			select {
+			case pending <- result:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case workerSem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+
+			go func(height int32) {
+				result <- fetchCatchupBlock(db, chain, height, inputsHeight)
+				<-workerSem
+			}(height)
+		}
+	}()
+	return pending
+}
+
 // indexNeedsInputs returns whether or not the index needs access to the txouts
 // referenced by the transaction inputs being indexed.
 func indexNeedsInputs(index Indexer) bool {
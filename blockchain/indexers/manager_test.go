@@ -0,0 +1,186 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database/v2"
+	_ "github.com/decred/dcrd/database/v2/ffldb"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// testChainQueryer provides a mock chain queryer backed by a list of block
+// hashes by height by implementing the ChainQueryer interface.
+type testChainQueryer struct {
+	hashes []chainhash.Hash
+}
+
+// MainChainHasBlock returns whether or not the block with the given hash is in
+// the mock main chain.
+//
+// This is part of the ChainQueryer interface.
+func (q *testChainQueryer) MainChainHasBlock(hash *chainhash.Hash) bool {
+	for i := range q.hashes {
+		if q.hashes[i] == *hash {
+			return true
+		}
+	}
+	return false
+}
+
+// BestHeight returns the height of the final block in the mock main chain.
+//
+// This is part of the ChainQueryer interface.
+func (q *testChainQueryer) BestHeight() int64 {
+	return int64(len(q.hashes) - 1)
+}
+
+// BlockHashByHeight returns the hash of the block at the given height in the
+// mock main chain.
+//
+// This is part of the ChainQueryer interface.
+func (q *testChainQueryer) BlockHashByHeight(height int64) (*chainhash.Hash, error) {
+	if height < 0 || height >= int64(len(q.hashes)) {
+		return nil, errors.New("no block at height")
+	}
+	return &q.hashes[height], nil
+}
+
+// PrevScripts is not supported by the mock chain queryer and always returns
+// nil.
+//
+// This is part of the ChainQueryer interface.
+func (q *testChainQueryer) PrevScripts(database.Tx, *dcrutil.Block) (PrevScripter, error) {
+	return nil, nil
+}
+
+// TestEstimateRemaining ensures the estimated time remaining to catch up the
+// indexes is calculated as expected.
+func TestEstimateRemaining(t *testing.T) {
+	tests := []struct {
+		name      string
+		elapsed   time.Duration
+		completed int64
+		remaining int64
+		want      time.Duration
+	}{{
+		name:      "nothing completed yet",
+		elapsed:   time.Minute,
+		completed: 0,
+		remaining: 1000,
+		want:      0,
+	}, {
+		name:      "nothing remaining",
+		elapsed:   time.Minute,
+		completed: 1000,
+		remaining: 0,
+		want:      0,
+	}, {
+		name:      "same rate",
+		elapsed:   time.Minute,
+		completed: 1000,
+		remaining: 3000,
+		want:      3 * time.Minute,
+	}, {
+		name:      "rounded to seconds",
+		elapsed:   1500 * time.Millisecond,
+		completed: 2,
+		remaining: 3,
+		want:      2 * time.Second,
+	}}
+
+	for _, test := range tests {
+		got := estimateRemaining(test.elapsed, test.completed, test.remaining)
+		if got != test.want {
+			t.Errorf("%q: unexpected estimate -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestFetchCatchupBlocks ensures the blocks loaded concurrently while catching
+// up the indexes are delivered in order.
+func TestFetchCatchupBlocks(t *testing.T) {
+	dbPath, err := ioutil.TempDir("", "fetchcatchupblocks")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dbPath)
+
+	db, err := database.Create("ffldb", filepath.Join(dbPath, "db"),
+		wire.SimNet)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	defer db.Close()
+
+	// Store a chain of blocks in the database.
+	const numBlocks = 50
+	chain := &testChainQueryer{}
+	err = db.Update(func(dbTx database.Tx) error {
+		var prevHash chainhash.Hash
+		for height := uint32(0); height < numBlocks; height++ {
+			block := dcrutil.NewBlock(&wire.MsgBlock{
+				Header: wire.BlockHeader{
+					PrevBlock: prevHash,
+					Height:    height,
+				},
+			})
+			if err := dbTx.StoreBlock(block); err != nil {
+				return err
+			}
+			prevHash = *block.Hash()
+			chain.hashes = append(chain.hashes, prevHash)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to store blocks: %v", err)
+	}
+
+	pending := fetchCatchupBlocks(context.Background(), db, chain, 1,
+		numBlocks-1, numBlocks)
+	wantHeight := int64(1)
+	for result := range pending {
+		fetched := <-result
+		if fetched.err != nil {
+			t.Fatalf("unexpected error loading height %d: %v", wantHeight,
+				fetched.err)
+		}
+		if fetched.block.Height() != wantHeight {
+			t.Fatalf("unexpected block height -- got %d, want %d",
+				fetched.block.Height(), wantHeight)
+		}
+		if *fetched.block.Hash() != chain.hashes[wantHeight] {
+			t.Fatalf("unexpected block hash at height %d", wantHeight)
+		}
+		wantHeight++
+	}
+	if wantHeight != numBlocks {
+		t.Fatalf("unexpected number of blocks -- got %d, want %d",
+			wantHeight-1, numBlocks-1)
+	}
+
+	// Ensure canceling the context stops the blocks from being loaded.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pending = fetchCatchupBlocks(ctx, db, chain, 1, numBlocks-1, numBlocks)
+	var numDelivered int
+	for range pending {
+		numDelivered++
+	}
+	if numDelivered == numBlocks-1 {
+		t.Fatal("canceled fetch unexpectedly delivered all blocks")
+	}
+}
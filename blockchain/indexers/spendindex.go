@@ -0,0 +1,323 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// spendIndexName is the human-readable name for the index.
+	spendIndexName = "spend index"
+
+	// spendIndexVersion is the current version of the spend index.
+	spendIndexVersion = 1
+
+	// spendKeySize is the size of a spend index key.  It consists of 32
+	// bytes spent transaction hash + 4 bytes spent output index.
+	spendKeySize = chainhash.HashSize + 4
+
+	// spendEntrySize is the size of a spend index entry.  It consists of 32
+	// bytes spending transaction hash + 4 bytes input index + 32 bytes block
+	// hash + 4 bytes block height.
+	spendEntrySize = chainhash.HashSize + 4 + chainhash.HashSize + 4
+)
+
+var (
+	// spendIndexKey is the key of the spend index and the db bucket used to
+	// house it.
+	spendIndexKey = []byte("spendidx")
+)
+
+// -----------------------------------------------------------------------------
+// The spend index consists of an entry for every transaction output that has
+// been spent by a transaction in the main chain.  Each entry maps the outpoint
+// to the transaction input that spent it along with the block that contains
+// the spending transaction.
+//
+// The serialized format for the keys and values in the spend index bucket is:
+//
+//   <outpoint hash><outpoint index> = <spender hash><input index><block hash><block height>
+//
+//   Field           Type              Size
+//   outpoint hash   chainhash.Hash    32 bytes
+//   outpoint index  uint32            4 bytes
+//   spender hash    chainhash.Hash    32 bytes
+//   input index     uint32            4 bytes
+//   block hash      chainhash.Hash    32 bytes
+//   block height    uint32            4 bytes
+//   -----
+//   Total: 108 bytes
+// -----------------------------------------------------------------------------
+
+// SpendIndexEntry houses information about an entry in the spend index.
+type SpendIndexEntry struct {
+	// SpenderHash is the hash of the transaction that spent the output.
+	SpenderHash chainhash.Hash
+
+	// InputIndex is the index of the input within the spending transaction
+	// that spent the output.
+	InputIndex uint32
+
+	// BlockHash is the hash of the block that contains the spending
+	// transaction.
+	BlockHash chainhash.Hash
+
+	// BlockHeight is the height of the block that contains the spending
+	// transaction.
+	BlockHeight uint32
+}
+
+// spendIndexKeyFor returns the serialized spend index key for the provided
+// outpoint.
+func spendIndexKeyFor(outpoint *wire.OutPoint) []byte {
+	key := make([]byte, spendKeySize)
+	copy(key, outpoint.Hash[:])
+	byteOrder.PutUint32(key[chainhash.HashSize:], outpoint.Index)
+	return key
+}
+
+// putSpendIndexEntry serializes the provided values according to the format
+// described above for a spend index entry.  The target byte slice must be at
+// least large enough to handle the number of bytes defined by the
+// spendEntrySize constant or it will panic.
+func putSpendIndexEntry(target []byte, entry *SpendIndexEntry) {
+	offset := copy(target, entry.SpenderHash[:])
+	byteOrder.PutUint32(target[offset:], entry.InputIndex)
+	offset += 4
+	offset += copy(target[offset:], entry.BlockHash[:])
+	byteOrder.PutUint32(target[offset:], entry.BlockHeight)
+}
+
+// decodeSpendIndexEntry decodes the passed serialized spend index entry into
+// the returned struct.  An error is returned when the serialized data does not
+// have enough bytes.
+func decodeSpendIndexEntry(serialized []byte) (*SpendIndexEntry, error) {
+	if len(serialized) < spendEntrySize {
+		return nil, errDeserialize("unexpected end of data")
+	}
+
+	var entry SpendIndexEntry
+	offset := copy(entry.SpenderHash[:], serialized)
+	entry.InputIndex = byteOrder.Uint32(serialized[offset:])
+	offset += 4
+	offset += copy(entry.BlockHash[:], serialized[offset:])
+	entry.BlockHeight = byteOrder.Uint32(serialized[offset:])
+	return &entry, nil
+}
+
+// dbFetchSpendIndexEntry uses an existing database transaction to fetch the
+// spend details for the provided outpoint from the spend index.  When there is
+// no entry for the provided outpoint, nil will be returned for the both the
+// entry and the error.
+func dbFetchSpendIndexEntry(dbTx database.Tx, outpoint *wire.OutPoint) (*SpendIndexEntry, error) {
+	// Load the record from the database and return now if it doesn't exist.
+	spendIndex := dbTx.Metadata().Bucket(spendIndexKey)
+	serializedData := spendIndex.Get(spendIndexKeyFor(outpoint))
+	if len(serializedData) == 0 {
+		return nil, nil
+	}
+
+	entry, err := decodeSpendIndexEntry(serializedData)
+	if err != nil {
+		return nil, database.Error{
+			ErrorCode: database.ErrCorruption,
+			Description: fmt.Sprintf("corrupt spend index entry for "+
+				"%v: %v", outpoint, err),
+		}
+	}
+	return entry, nil
+}
+
+// spentOutPoints invokes the provided function with every outpoint spent by
+// the transactions in the passed block along with the hash of the spending
+// transaction and the index of the input that spends it.  The coinbase and the
+// stakebase inputs of votes do not spend any outputs and are therefore skipped.
+func spentOutPoints(block *dcrutil.Block, fn func(outpoint *wire.OutPoint, spender *chainhash.Hash, inputIndex uint32) error) error {
+	for i, tx := range block.Transactions() {
+		// The coinbase does not spend any outputs.
+		if i == 0 {
+			continue
+		}
+
+		for txInIdx, txIn := range tx.MsgTx().TxIn {
+			err := fn(&txIn.PreviousOutPoint, tx.Hash(), uint32(txInIdx))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, stx := range block.STransactions() {
+		isSSGen := stake.IsSSGen(stx.MsgTx())
+		for txInIdx, txIn := range stx.MsgTx().TxIn {
+			// Skip the stakebase since it does not spend an output.
+			if isSSGen && txInIdx == 0 {
+				continue
+			}
+
+			err := fn(&txIn.PreviousOutPoint, stx.Hash(), uint32(txInIdx))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// dbAddSpendIndexEntries uses an existing database transaction to add a spend
+// index entry for every output spent by the transactions in the passed block.
+func dbAddSpendIndexEntries(dbTx database.Tx, block *dcrutil.Block) error {
+	spendIndex := dbTx.Metadata().Bucket(spendIndexKey)
+	entry := SpendIndexEntry{
+		BlockHash:   *block.Hash(),
+		BlockHeight: uint32(block.Height()),
+	}
+	return spentOutPoints(block, func(outpoint *wire.OutPoint, spender *chainhash.Hash, inputIndex uint32) error {
+		entry.SpenderHash = *spender
+		entry.InputIndex = inputIndex
+		serialized := make([]byte, spendEntrySize)
+		putSpendIndexEntry(serialized, &entry)
+		return spendIndex.Put(spendIndexKeyFor(outpoint), serialized)
+	})
+}
+
+// dbRemoveSpendIndexEntries uses an existing database transaction to remove
+// the spend index entry for every output spent by the transactions in the
+// passed block.
+func dbRemoveSpendIndexEntries(dbTx database.Tx, block *dcrutil.Block) error {
+	spendIndex := dbTx.Metadata().Bucket(spendIndexKey)
+	return spentOutPoints(block, func(outpoint *wire.OutPoint, _ *chainhash.Hash, _ uint32) error {
+		return spendIndex.Delete(spendIndexKeyFor(outpoint))
+	})
+}
+
+// SpendIndex implements a spent output index.  That is to say, it supports
+// querying the transaction input that spent a given outpoint.
+type SpendIndex struct {
+	db database.DB
+}
+
+// Ensure the SpendIndex type implements the Indexer interface.
+var _ Indexer = (*SpendIndex)(nil)
+
+// Ensure the SpendIndex type implements the IndexDropper interface.
+var _ IndexDropper = (*SpendIndex)(nil)
+
+// Init is only provided to satisfy the Indexer interface as there is nothing
+// to initialize for this index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Init() error {
+	return nil
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Key() []byte {
+	return spendIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Name() string {
+	return spendIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Version() uint32 {
+	return spendIndexVersion
+}
+
+// Create is invoked when the indexer manager determines the index needs
+// to be created for the first time.  It creates the bucket for the spend
+// index.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(spendIndexKey)
+	return err
+}
+
+// ConnectBlock is invoked by the index manager when a new block has been
+// connected to the main chain.  This indexer adds an outpoint-to-spender
+// mapping for every output spent by the transactions in the passed block.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) ConnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, _ PrevScripter) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored for this index because even though the
+	// disapproved transactions no longer apply spend semantics, they still
+	// exist within the block and thus have to be processed before the next
+	// block disapproves them.
+	//
+	// Also, the index only stores a single spender per outpoint.  This means
+	// that if an output spent by a disapproved transaction is later spent by
+	// another transaction, only that most recent spender can be queried.
+	return dbAddSpendIndexEntries(dbTx, block)
+}
+
+// DisconnectBlock is invoked by the index manager when a block has been
+// disconnected from the main chain.  This indexer removes the
+// outpoint-to-spender mapping for every output spent by the transactions in
+// the block.
+//
+// This is part of the Indexer interface.
+func (idx *SpendIndex) DisconnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, _ PrevScripter) error {
+	// NOTE: The fact that the block can disapprove the regular tree of the
+	// previous block is ignored when disconnecting blocks because it is also
+	// ignored when connecting the block.  See the comments in ConnectBlock for
+	// the specifics.
+	return dbRemoveSpendIndexEntries(dbTx, block)
+}
+
+// Entry returns details about the transaction input that spent the provided
+// outpoint from the spend index.  When there is no entry for the provided
+// outpoint, nil will be returned for the both the entry and the error.
+//
+// This function is safe for concurrent access.
+func (idx *SpendIndex) Entry(outpoint *wire.OutPoint) (*SpendIndexEntry, error) {
+	var entry *SpendIndexEntry
+	err := idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		entry, err = dbFetchSpendIndexEntry(dbTx, outpoint)
+		return err
+	})
+	return entry, err
+}
+
+// NewSpendIndex returns a new instance of an indexer that is used to create a
+// mapping of every spent transaction output in the blockchain to the
+// transaction input that spent it and the block that contains it.
+//
+// It implements the Indexer interface which plugs into the IndexManager that in
+// turn is used by the blockchain package.  This allows the index to be
+// seamlessly maintained along with the chain.
+func NewSpendIndex(db database.DB) *SpendIndex {
+	return &SpendIndex{db: db}
+}
+
+// DropSpendIndex drops the spend index from the provided database if it
+// exists.
+func DropSpendIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, spendIndexKey, spendIndexName)
+}
+
+// DropIndex drops the spend index from the provided database if it exists.
+func (*SpendIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropSpendIndex(ctx, db)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestSpendIndexEntrySerialization ensures serializing and deserializing spend
+// index entries works as expected.
+func TestSpendIndexEntrySerialization(t *testing.T) {
+	entry := SpendIndexEntry{
+		SpenderHash: chainhash.HashH([]byte("spender")),
+		InputIndex:  3,
+		BlockHash:   chainhash.HashH([]byte("block")),
+		BlockHeight: 12345,
+	}
+
+	serialized := make([]byte, spendEntrySize)
+	putSpendIndexEntry(serialized, &entry)
+	decoded, err := decodeSpendIndexEntry(serialized)
+	if err != nil {
+		t.Fatalf("unexpected error decoding entry: %v", err)
+	}
+	if !reflect.DeepEqual(*decoded, entry) {
+		t.Fatalf("mismatched entry -- got %+v, want %+v", *decoded, entry)
+	}
+
+	// Ensure truncated data is rejected.
+	_, err = decodeSpendIndexEntry(serialized[:spendEntrySize-1])
+	if !isDeserializeErr(err) {
+		t.Fatalf("unexpected error for truncated entry -- got %v, want "+
+			"errDeserialize", err)
+	}
+}
+
+// TestSpentOutPoints ensures the outpoints spent by a block are reported with
+// the correct spender details while skipping the coinbase.
+func TestSpentOutPoints(t *testing.T) {
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+		wire.MaxPrevOutIndex, wire.TxTreeRegular), 0, nil))
+	coinbase.AddTxOut(wire.NewTxOut(0, nil))
+
+	prev1 := wire.NewOutPoint(&chainhash.Hash{0x01}, 0, wire.TxTreeRegular)
+	prev2 := wire.NewOutPoint(&chainhash.Hash{0x02}, 5, wire.TxTreeStake)
+	spendTx := wire.NewMsgTx()
+	spendTx.AddTxIn(wire.NewTxIn(prev1, 0, nil))
+	spendTx.AddTxIn(wire.NewTxIn(prev2, 0, nil))
+	spendTx.AddTxOut(wire.NewTxOut(0, nil))
+
+	msgBlock := &wire.MsgBlock{
+		Header:       wire.BlockHeader{Height: 10},
+		Transactions: []*wire.MsgTx{coinbase, spendTx},
+	}
+	block := dcrutil.NewBlock(msgBlock)
+
+	type spend struct {
+		outpoint   wire.OutPoint
+		spender    chainhash.Hash
+		inputIndex uint32
+	}
+	var got []spend
+	err := spentOutPoints(block, func(op *wire.OutPoint, spender *chainhash.Hash, inputIndex uint32) error {
+		got = append(got, spend{*op, *spender, inputIndex})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	spendHash := spendTx.TxHash()
+	want := []spend{
+		{*prev1, spendHash, 0},
+		{*prev2, spendHash, 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched spends -- got %+v, want %+v", got, want)
+	}
+}
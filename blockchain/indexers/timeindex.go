@@ -0,0 +1,307 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+const (
+	// timeIndexName is the human-readable name for the index.
+	timeIndexName = "block timestamp index"
+
+	// timeIndexVersion is the current version of the block timestamp index.
+	timeIndexVersion = 1
+
+	// timeKeySize is the size of a block timestamp index key.  It consists
+	// of 8 bytes timestamp + 4 bytes block height.
+	timeKeySize = 8 + 4
+)
+
+var (
+	// timeIndexKey is the key of the block timestamp index and the db bucket
+	// used to house it.
+	timeIndexKey = []byte("timeidx")
+)
+
+// -----------------------------------------------------------------------------
+// The block timestamp index consists of an entry for every block in the main
+// chain keyed by the timestamp in its header.  The keys are serialized in
+// big endian so the natural ordering of the keys in the database matches the
+// ordering of the timestamps, which allows efficient range queries.  The block
+// height is included in the key since multiple blocks may have the same
+// timestamp.
+//
+// Note that block timestamps are not required to be strictly increasing, so
+// the ordering of the entries does not necessarily match the ordering of the
+// blocks in the chain.
+//
+// The serialized format for the keys and values in the block timestamp index
+// bucket is:
+//
+//   <timestamp><block height> = <block hash>
+//
+//   Field           Type              Size
+//   timestamp       int64             8 bytes
+//   block height    uint32            4 bytes
+//   block hash      chainhash.Hash    32 bytes
+//   -----
+//   Total: 44 bytes
+// -----------------------------------------------------------------------------
+
+// TimeIndexEntry houses information about an entry in the block timestamp
+// index.
+type TimeIndexEntry struct {
+	// Hash is the hash of the block.
+	Hash chainhash.Hash
+
+	// Height is the height of the block.
+	Height uint32
+
+	// Timestamp is the timestamp in the header of the block.
+	Timestamp time.Time
+}
+
+// timeIndexKeyFor returns the serialized block timestamp index key for the
+// provided timestamp and block height.
+func timeIndexKeyFor(timestamp int64, height uint32) []byte {
+	key := make([]byte, timeKeySize)
+	binary.BigEndian.PutUint64(key, uint64(timestamp))
+	binary.BigEndian.PutUint32(key[8:], height)
+	return key
+}
+
+// decodeTimeIndexEntry decodes the passed serialized key and value from the
+// block timestamp index into the returned struct.  An error is returned when
+// either of them does not have the expected number of bytes.
+func decodeTimeIndexEntry(key, value []byte) (*TimeIndexEntry, error) {
+	if len(key) != timeKeySize || len(value) != chainhash.HashSize {
+		return nil, errDeserialize("unexpected end of data")
+	}
+
+	var entry TimeIndexEntry
+	timestamp := int64(binary.BigEndian.Uint64(key))
+	entry.Timestamp = time.Unix(timestamp, 0)
+	entry.Height = binary.BigEndian.Uint32(key[8:])
+	copy(entry.Hash[:], value)
+	return &entry, nil
+}
+
+// dbTimeIndexCursorEntry decodes the entry the passed cursor currently points
+// to while converting any decoding errors into database corruption errors.
+func dbTimeIndexCursorEntry(cursor database.Cursor) (*TimeIndexEntry, error) {
+	entry, err := decodeTimeIndexEntry(cursor.Key(), cursor.Value())
+	if err != nil {
+		return nil, database.Error{
+			ErrorCode: database.ErrCorruption,
+			Description: fmt.Sprintf("corrupt block timestamp index "+
+				"entry for key %x: %v", cursor.Key(), err),
+		}
+	}
+	return entry, nil
+}
+
+// dbFetchNearestTimeIndexEntry uses an existing database transaction to fetch
+// the block with the timestamp closest to the provided one.  Ties are resolved
+// in favor of the earlier block.  When the index is empty, nil will be returned
+// for both the entry and the error.
+func dbFetchNearestTimeIndexEntry(dbTx database.Tx, timestamp int64) (*TimeIndexEntry, error) {
+	cursor := dbTx.Metadata().Bucket(timeIndexKey).Cursor()
+
+	// Find the first entry at or after the timestamp along with the entry
+	// immediately before it, if any.
+	var before, after *TimeIndexEntry
+	var err error
+	if cursor.Seek(timeIndexKeyFor(timestamp, 0)) {
+		after, err = dbTimeIndexCursorEntry(cursor)
+		if err != nil {
+			return nil, err
+		}
+		if cursor.Prev() {
+			before, err = dbTimeIndexCursorEntry(cursor)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else if cursor.Last() {
+		before, err = dbTimeIndexCursorEntry(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case before == nil:
+		return after, nil
+	case after == nil:
+		return before, nil
+	}
+
+	if after.Timestamp.Unix()-timestamp < timestamp-before.Timestamp.Unix() {
+		return after, nil
+	}
+	return before, nil
+}
+
+// dbFetchTimeIndexRange uses an existing database transaction to fetch up to
+// the provided maximum number of blocks with timestamps in the range
+// [start, end) ordered by their timestamps.
+func dbFetchTimeIndexRange(dbTx database.Tx, start, end int64, maxEntries int) ([]TimeIndexEntry, error) {
+	var entries []TimeIndexEntry
+	endKey := timeIndexKeyFor(end, 0)
+	cursor := dbTx.Metadata().Bucket(timeIndexKey).Cursor()
+	for ok := cursor.Seek(timeIndexKeyFor(start, 0)); ok &&
+		len(entries) < maxEntries; ok = cursor.Next() {
+
+		if bytes.Compare(cursor.Key(), endKey) >= 0 {
+			break
+		}
+
+		entry, err := dbTimeIndexCursorEntry(cursor)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, *entry)
+	}
+	return entries, nil
+}
+
+// TimeIndex implements a block timestamp index.  That is to say, it supports
+// querying the blocks in the main chain by the timestamps in their headers.
+type TimeIndex struct {
+	db database.DB
+}
+
+// Ensure the TimeIndex type implements the Indexer interface.
+var _ Indexer = (*TimeIndex)(nil)
+
+// Ensure the TimeIndex type implements the IndexDropper interface.
+var _ IndexDropper = (*TimeIndex)(nil)
+
+// Init is only provided to satisfy the Indexer interface as there is nothing
+// to initialize for this index.
+//
+// This is part of the Indexer interface.
+func (idx *TimeIndex) Init() error {
+	return nil
+}
+
+// Key returns the database key to use for the index as a byte slice.
+//
+// This is part of the Indexer interface.
+func (idx *TimeIndex) Key() []byte {
+	return timeIndexKey
+}
+
+// Name returns the human-readable name of the index.
+//
+// This is part of the Indexer interface.
+func (idx *TimeIndex) Name() string {
+	return timeIndexName
+}
+
+// Version returns the current version of the index.
+//
+// This is part of the Indexer interface.
+func (idx *TimeIndex) Version() uint32 {
+	return timeIndexVersion
+}
+
+// Create is invoked when the indexer manager determines the index needs
+// to be created for the first time.  It creates the bucket for the block
+// timestamp index.
+//
+// This is part of the Indexer interface.
+func (idx *TimeIndex) Create(dbTx database.Tx) error {
+	_, err := dbTx.Metadata().CreateBucket(timeIndexKey)
+	return err
+}
+
+// ConnectBlock is invoked by the index manager when a new block has been
+// connected to the main chain.  This indexer adds a timestamp-to-block mapping
+// for the passed block.
+//
+// This is part of the Indexer interface.
+func (idx *TimeIndex) ConnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, _ PrevScripter) error {
+	header := &block.MsgBlock().Header
+	key := timeIndexKeyFor(header.Timestamp.Unix(), header.Height)
+	timeIndex := dbTx.Metadata().Bucket(timeIndexKey)
+	return timeIndex.Put(key, block.Hash()[:])
+}
+
+// DisconnectBlock is invoked by the index manager when a block has been
+// disconnected from the main chain.  This indexer removes the
+// timestamp-to-block mapping for the passed block.
+//
+// This is part of the Indexer interface.
+func (idx *TimeIndex) DisconnectBlock(dbTx database.Tx, block, parent *dcrutil.Block, _ PrevScripter) error {
+	header := &block.MsgBlock().Header
+	key := timeIndexKeyFor(header.Timestamp.Unix(), header.Height)
+	timeIndex := dbTx.Metadata().Bucket(timeIndexKey)
+	return timeIndex.Delete(key)
+}
+
+// NearestBlock returns the block in the main chain with the timestamp closest
+// to the provided time.  Ties are resolved in favor of the earlier block.  When
+// the index does not contain any blocks, nil will be returned for both the
+// entry and the error.
+//
+// This function is safe for concurrent access.
+func (idx *TimeIndex) NearestBlock(t time.Time) (*TimeIndexEntry, error) {
+	var entry *TimeIndexEntry
+	err := idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		entry, err = dbFetchNearestTimeIndexEntry(dbTx, t.Unix())
+		return err
+	})
+	return entry, err
+}
+
+// BlocksInRange returns up to the provided maximum number of blocks in the main
+// chain with timestamps at or after the start time and before the end time
+// ordered by their timestamps.
+//
+// This function is safe for concurrent access.
+func (idx *TimeIndex) BlocksInRange(start, end time.Time, maxEntries int) ([]TimeIndexEntry, error) {
+	var entries []TimeIndexEntry
+	err := idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		entries, err = dbFetchTimeIndexRange(dbTx, start.Unix(), end.Unix(),
+			maxEntries)
+		return err
+	})
+	return entries, err
+}
+
+// NewTimeIndex returns a new instance of an indexer that is used to create a
+// mapping of the timestamps of all blocks in the main chain to the respective
+// block hashes and heights.
+//
+// It implements the Indexer interface which plugs into the IndexManager that in
+// turn is used by the blockchain package.  This allows the index to be
+// seamlessly maintained along with the chain.
+func NewTimeIndex(db database.DB) *TimeIndex {
+	return &TimeIndex{db: db}
+}
+
+// DropTimeIndex drops the block timestamp index from the provided database if
+// it exists.
+func DropTimeIndex(ctx context.Context, db database.DB) error {
+	return dropFlatIndex(ctx, db, timeIndexKey, timeIndexName)
+}
+
+// DropIndex drops the block timestamp index from the provided database if it
+// exists.
+func (*TimeIndex) DropIndex(ctx context.Context, db database.DB) error {
+	return DropTimeIndex(ctx, db)
+}
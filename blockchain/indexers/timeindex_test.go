@@ -0,0 +1,140 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package indexers
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/database/v2"
+	_ "github.com/decred/dcrd/database/v2/ffldb"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestTimeIndex ensures the block timestamp index returns the expected blocks
+// when querying for the nearest block and blocks within a time range.
+func TestTimeIndex(t *testing.T) {
+	dbPath, err := ioutil.TempDir("", "timeindex")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dbPath)
+
+	db, err := database.Create("ffldb", filepath.Join(dbPath, "db"),
+		wire.SimNet)
+	if err != nil {
+		t.Fatalf("unable to create database: %v", err)
+	}
+	defer db.Close()
+
+	idx := NewTimeIndex(db)
+	err = db.Update(func(dbTx database.Tx) error {
+		return idx.Create(dbTx)
+	})
+	if err != nil {
+		t.Fatalf("unable to create index: %v", err)
+	}
+
+	// Connect blocks with timestamps that are not strictly increasing to
+	// ensure the index orders them by timestamp.
+	timestamps := []int64{1000, 1600, 1300, 2000, 2000}
+	blocks := make([]*dcrutil.Block, 0, len(timestamps))
+	for i, timestamp := range timestamps {
+		block := dcrutil.NewBlock(&wire.MsgBlock{
+			Header: wire.BlockHeader{
+				Height:    uint32(i),
+				Timestamp: time.Unix(timestamp, 0),
+			},
+		})
+		blocks = append(blocks, block)
+		err := db.Update(func(dbTx database.Tx) error {
+			return idx.ConnectBlock(dbTx, block, nil, nil)
+		})
+		if err != nil {
+			t.Fatalf("unable to connect block %d: %v", i, err)
+		}
+	}
+
+	entryFor := func(height uint32) TimeIndexEntry {
+		return TimeIndexEntry{
+			Hash:      *blocks[height].Hash(),
+			Height:    height,
+			Timestamp: time.Unix(timestamps[height], 0),
+		}
+	}
+
+	nearestTests := []struct {
+		name      string
+		timestamp int64
+		want      uint32
+	}{
+		{"before first block", 10, 0},
+		{"exact match", 1300, 2},
+		{"closer to later block", 1500, 1},
+		{"tie favors earlier block", 1150, 0},
+		{"after last block", 5000, 4},
+	}
+	for _, test := range nearestTests {
+		entry, err := idx.NearestBlock(time.Unix(test.timestamp, 0))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if entry == nil || !reflect.DeepEqual(*entry, entryFor(test.want)) {
+			t.Errorf("%q: mismatched entry -- got %+v, want %+v", test.name,
+				entry, entryFor(test.want))
+		}
+	}
+
+	rangeTests := []struct {
+		name       string
+		start, end int64
+		maxEntries int
+		want       []uint32
+	}{
+		{"all blocks", 0, 3000, 10, []uint32{0, 2, 1, 3, 4}},
+		{"end is exclusive", 1000, 2000, 10, []uint32{0, 2, 1}},
+		{"limited count", 1000, 3000, 2, []uint32{0, 2}},
+		{"empty range", 1700, 1900, 10, nil},
+	}
+	for _, test := range rangeTests {
+		entries, err := idx.BlocksInRange(time.Unix(test.start, 0),
+			time.Unix(test.end, 0), test.maxEntries)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		var want []TimeIndexEntry
+		for _, height := range test.want {
+			want = append(want, entryFor(height))
+		}
+		if !reflect.DeepEqual(entries, want) {
+			t.Errorf("%q: mismatched entries -- got %+v, want %+v",
+				test.name, entries, want)
+		}
+	}
+
+	// Ensure disconnecting the last block removes it from the index.
+	err = db.Update(func(dbTx database.Tx) error {
+		return idx.DisconnectBlock(dbTx, blocks[4], nil, nil)
+	})
+	if err != nil {
+		t.Fatalf("unable to disconnect block: %v", err)
+	}
+	entries, err := idx.BlocksInRange(time.Unix(2000, 0), time.Unix(3000, 0),
+		10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []TimeIndexEntry{entryFor(3)}; !reflect.DeepEqual(entries, want) {
+		t.Fatalf("mismatched entries after disconnect -- got %+v, want %+v",
+			entries, want)
+	}
+}
@@ -8,6 +8,7 @@ package blockchain
 import (
 	"fmt"
 
+	"github.com/decred/dcrd/blockchain/stake/v3"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/dcrutil/v3"
 )
@@ -72,6 +73,10 @@ const (
 	// NTSpentAndMissedTickets indicates newly maturing tickets from a newly
 	// accepted block.
 	NTNewTickets
+
+	// NTTicketLifecycle indicates all ticket state transitions caused by a
+	// newly connected block.
+	NTTicketLifecycle
 )
 
 // notificationTypeStrings is a map of notification types back to their constant
@@ -86,6 +91,7 @@ var notificationTypeStrings = map[NotificationType]string{
 	NTReorganization:        "NTReorganization",
 	NTSpentAndMissedTickets: "NTSpentAndMissedTickets",
 	NTNewTickets:            "NTNewTickets",
+	NTTicketLifecycle:       "NTTicketLifecycle",
 }
 
 // String returns the NotificationType in human-readable form.
@@ -138,6 +144,59 @@ type TicketNotificationsData struct {
 	TicketsNew      []chainhash.Hash
 }
 
+// TicketLifecycleNtfnsData is the structure for data indicating the ticket
+// state transitions that resulted from connecting a block to the main chain.
+type TicketLifecycleNtfnsData struct {
+	Hash   chainhash.Hash
+	Height int64
+
+	// Live are the tickets that matured and entered the live ticket pool.
+	Live []chainhash.Hash
+
+	// Selected are the tickets selected to vote on the next block.
+	Selected []chainhash.Hash
+
+	// Voted are the tickets that were spent by a vote.
+	Voted []chainhash.Hash
+
+	// Missed are the tickets that were selected to vote but were not spent
+	// by a vote.  Expired tickets are not included.
+	Missed []chainhash.Hash
+
+	// Expired are the tickets that expired without being selected.
+	Expired []chainhash.Hash
+
+	// Revoked are the missed or expired tickets that were revoked.
+	Revoked []chainhash.Hash
+}
+
+// ticketLifecycleNtfnsData classifies the ticket state transitions recorded in
+// the provided stake undo data, which must be the undo data of the stake node
+// of the block with the provided hash and height.  The winners are the tickets
+// selected to vote on the next block.
+func ticketLifecycleNtfnsData(hash chainhash.Hash, height int64, winners []chainhash.Hash, undoData stake.UndoTicketDataSlice) *TicketLifecycleNtfnsData {
+	data := &TicketLifecycleNtfnsData{
+		Hash:     hash,
+		Height:   height,
+		Selected: winners,
+	}
+	for _, undo := range undoData {
+		switch {
+		case undo.Spent:
+			data.Voted = append(data.Voted, undo.TicketHash)
+		case undo.Revoked:
+			data.Revoked = append(data.Revoked, undo.TicketHash)
+		case undo.Expired:
+			data.Expired = append(data.Expired, undo.TicketHash)
+		case undo.Missed:
+			data.Missed = append(data.Missed, undo.TicketHash)
+		default:
+			data.Live = append(data.Live, undo.TicketHash)
+		}
+	}
+	return data
+}
+
 // Notification defines notification that is sent to the caller via the callback
 // function provided during the call to New and consists of a notification type
 // as well as associated data that depends on the type as follows:
@@ -150,6 +209,7 @@ type TicketNotificationsData struct {
 //  - NTReorganization:        *ReorganizationNtfnsData
 //  - NTSpentAndMissedTickets: *TicketNotificationsData
 //  - NTNewTickets:            *TicketNotificationsData
+//  - NTTicketLifecycle:       *TicketLifecycleNtfnsData
 type Notification struct {
 	Type NotificationType
 	Data interface{}
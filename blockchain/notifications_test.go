@@ -0,0 +1,126 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blockchain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestTicketLifecycleNtfnsData ensures the ticket state transitions recorded in
+// stake undo data are classified into the expected ticket lifecycle
+// notification fields.
+func TestTicketLifecycleNtfnsData(t *testing.T) {
+	t.Parallel()
+
+	// Create some unique ticket hashes to use throughout the tests.
+	ticket := func(b byte) chainhash.Hash {
+		return chainhash.Hash{b}
+	}
+	blockHash := chainhash.Hash{0xff}
+	const blockHeight = 4096
+
+	tests := []struct {
+		name     string                    // test description
+		winners  []chainhash.Hash          // tickets selected for next block
+		undoData stake.UndoTicketDataSlice // stake undo data for the block
+		want     TicketLifecycleNtfnsData  // expected notification data
+	}{{
+		name: "no transitions",
+		want: TicketLifecycleNtfnsData{},
+	}, {
+		name:    "winners are selected",
+		winners: []chainhash.Hash{ticket(1), ticket(2)},
+		want: TicketLifecycleNtfnsData{
+			Selected: []chainhash.Hash{ticket(1), ticket(2)},
+		},
+	}, {
+		name: "new tickets become live",
+		undoData: stake.UndoTicketDataSlice{
+			{TicketHash: ticket(1), TicketHeight: 100},
+			{TicketHash: ticket(2), TicketHeight: 100},
+		},
+		want: TicketLifecycleNtfnsData{
+			Live: []chainhash.Hash{ticket(1), ticket(2)},
+		},
+	}, {
+		name: "spent tickets voted",
+		undoData: stake.UndoTicketDataSlice{
+			{TicketHash: ticket(1), Spent: true},
+		},
+		want: TicketLifecycleNtfnsData{
+			Voted: []chainhash.Hash{ticket(1)},
+		},
+	}, {
+		name: "missed tickets",
+		undoData: stake.UndoTicketDataSlice{
+			{TicketHash: ticket(1), Missed: true},
+		},
+		want: TicketLifecycleNtfnsData{
+			Missed: []chainhash.Hash{ticket(1)},
+		},
+	}, {
+		name: "expired tickets are not reported as missed",
+		undoData: stake.UndoTicketDataSlice{
+			{TicketHash: ticket(1), Missed: true, Expired: true},
+		},
+		want: TicketLifecycleNtfnsData{
+			Expired: []chainhash.Hash{ticket(1)},
+		},
+	}, {
+		name: "revoked missed tickets",
+		undoData: stake.UndoTicketDataSlice{
+			{TicketHash: ticket(1), Missed: true, Revoked: true},
+		},
+		want: TicketLifecycleNtfnsData{
+			Revoked: []chainhash.Hash{ticket(1)},
+		},
+	}, {
+		name: "revoked expired tickets",
+		undoData: stake.UndoTicketDataSlice{
+			{TicketHash: ticket(1), Missed: true, Expired: true,
+				Revoked: true},
+		},
+		want: TicketLifecycleNtfnsData{
+			Revoked: []chainhash.Hash{ticket(1)},
+		},
+	}, {
+		name:    "mixed transitions preserve undo data order",
+		winners: []chainhash.Hash{ticket(10)},
+		undoData: stake.UndoTicketDataSlice{
+			{TicketHash: ticket(1), Spent: true},
+			{TicketHash: ticket(2), Missed: true},
+			{TicketHash: ticket(3), Missed: true, Expired: true},
+			{TicketHash: ticket(4), Spent: true},
+			{TicketHash: ticket(5), Missed: true, Revoked: true},
+			{TicketHash: ticket(6), Missed: true, Expired: true},
+			{TicketHash: ticket(7)},
+			{TicketHash: ticket(8), Missed: true},
+		},
+		want: TicketLifecycleNtfnsData{
+			Live:     []chainhash.Hash{ticket(7)},
+			Selected: []chainhash.Hash{ticket(10)},
+			Voted:    []chainhash.Hash{ticket(1), ticket(4)},
+			Missed:   []chainhash.Hash{ticket(2), ticket(8)},
+			Expired:  []chainhash.Hash{ticket(3), ticket(6)},
+			Revoked:  []chainhash.Hash{ticket(5)},
+		},
+	}}
+
+	for _, test := range tests {
+		got := ticketLifecycleNtfnsData(blockHash, blockHeight, test.winners,
+			test.undoData)
+		want := test.want
+		want.Hash = blockHash
+		want.Height = blockHeight
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("%q: mismatched data -- got %+v, want %+v", test.name,
+				*got, want)
+		}
+	}
+}
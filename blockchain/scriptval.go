@@ -32,6 +32,7 @@ type txValidator struct {
 	utxoView     *UtxoViewpoint
 	flags        txscript.ScriptFlags
 	sigCache     *txscript.SigCache
+	sigBatch     *txscript.SigBatch
 }
 
 // sendResult sends the result of a script pair validation on the internal
@@ -103,8 +104,14 @@ out:
 				break out
 			}
 
-			// Execute the script pair.
-			if err := vm.Execute(); err != nil {
+			// Execute the script pair while deferring signature
+			// verification to the batch when there is one.
+			if v.sigBatch != nil {
+				err = vm.ExecuteBatched(v.sigBatch)
+			} else {
+				err = vm.Execute()
+			}
+			if err != nil {
 				str := fmt.Sprintf("failed to validate input "+
 					"%s:%d which references output %s:%d - "+
 					"%v (input script bytes %x, prev output "+
@@ -182,13 +189,16 @@ func (v *txValidator) Validate(items []*txValidateItem) error {
 }
 
 // newTxValidator returns a new instance of txValidator to be used for
-// validating transaction scripts asynchronously.
-func newTxValidator(utxoView *UtxoViewpoint, flags txscript.ScriptFlags, sigCache *txscript.SigCache) *txValidator {
+// validating transaction scripts asynchronously.  The verification of the
+// signatures that support batch verification is deferred to the provided
+// signature batch when it is not nil.
+func newTxValidator(utxoView *UtxoViewpoint, flags txscript.ScriptFlags, sigCache *txscript.SigCache, sigBatch *txscript.SigBatch) *txValidator {
 	return &txValidator{
 		validateChan: make(chan *txValidateItem),
 		resultChan:   make(chan error),
 		utxoView:     utxoView,
 		sigCache:     sigCache,
+		sigBatch:     sigBatch,
 		flags:        flags,
 	}
 }
@@ -215,7 +225,7 @@ func ValidateTransactionScripts(tx *dcrutil.Tx, utxoView *UtxoViewpoint, flags t
 	}
 
 	// Validate all of the inputs.
-	return newTxValidator(utxoView, flags, sigCache).Validate(txValItems)
+	return newTxValidator(utxoView, flags, sigCache, nil).Validate(txValItems)
 }
 
 // checkBlockScripts executes and validates the scripts for all transactions in
@@ -256,6 +266,22 @@ func checkBlockScripts(block *dcrutil.Block, utxoView *UtxoViewpoint, txTree boo
 		}
 	}
 
-	// Validate all of the inputs.
-	return newTxValidator(utxoView, scriptFlags, sigCache).Validate(txValItems)
+	// Validate all of the inputs while deferring the verification of the
+	// signatures that support batch verification and then verify them
+	// together as a batch since it is significantly faster.
+	sigBatch := txscript.NewSigBatch()
+	validator := newTxValidator(utxoView, scriptFlags, sigCache, sigBatch)
+	if err := validator.Validate(txValItems); err != nil {
+		return err
+	}
+	if sigBatch.Verify() {
+		return nil
+	}
+
+	// A failed batch does not identify which signatures are invalid, so
+	// validate all of the inputs again while verifying the signatures
+	// individually in order to determine the specific error.
+	log.Debugf("Batch verification of %d signatures in block %v failed -- "+
+		"validating signatures individually", sigBatch.Len(), block.Hash())
+	return newTxValidator(utxoView, scriptFlags, sigCache, nil).Validate(txValItems)
 }
@@ -157,6 +157,18 @@ func (sn *Node) LiveTickets() []chainhash.Hash {
 	return tickets
 }
 
+// LiveTicketsByHeight returns the number of live tickets for this stake node
+// keyed by the height at which they matured and entered the live ticket pool.
+func (sn *Node) LiveTicketsByHeight() map[uint32]uint32 {
+	counts := make(map[uint32]uint32)
+	sn.liveTickets.ForEach(func(k tickettreap.Key, v *tickettreap.Value) bool {
+		counts[v.Height]++
+		return true
+	})
+
+	return counts
+}
+
 // PoolSize returns the size of the live ticket pool.
 func (sn *Node) PoolSize() int {
 	return sn.liveTickets.Len()
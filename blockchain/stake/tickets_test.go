@@ -300,6 +300,14 @@ func TestTicketDBLongChain(t *testing.T) {
 			t.Errorf("bad number of live tickets: want %v, got %v",
 				header.PoolSize, len(bestNode.LiveTickets()))
 		}
+		var numByHeight uint32
+		for _, count := range bestNode.LiveTicketsByHeight() {
+			numByHeight += count
+		}
+		if header.PoolSize != numByHeight {
+			t.Errorf("bad number of live tickets by height: want %v, "+
+				"got %v", header.PoolSize, numByHeight)
+		}
 		if header.FinalState != bestNode.FinalState() {
 			t.Errorf("bad final state: want %x, got %x",
 				header.FinalState, bestNode.FinalState())
@@ -87,6 +87,19 @@ func (b *BlockChain) LiveTickets() ([]chainhash.Hash, error) {
 	return sn.LiveTickets(), nil
 }
 
+// LiveTicketsByHeight returns the number of currently live tickets keyed by the
+// height at which they matured and entered the live ticket pool.  The height
+// the tickets were purchased at is TicketMaturity blocks prior.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) LiveTicketsByHeight() map[uint32]uint32 {
+	b.chainLock.RLock()
+	sn := b.bestChain.Tip().stakeNode
+	b.chainLock.RUnlock()
+
+	return sn.LiveTicketsByHeight()
+}
+
 // MissedTickets returns all currently missed tickets from the stake database.
 //
 // This function is NOT safe for concurrent access.
@@ -10,6 +10,7 @@ import (
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
 )
 
 // ThresholdState define the various threshold states used when voting on
@@ -446,6 +447,11 @@ func (b *BlockChain) nextThresholdState(version uint32, prevNode *blockNode, che
 //
 // This function MUST be called with the chain state lock held (for writes).
 func (b *BlockChain) deploymentState(prevNode *blockNode, version uint32, deploymentID string) (ThresholdStateTuple, error) {
+	// Use the forced outcome of the deployment when there is one.
+	if state, ok := b.forcedDeployments[deploymentID]; ok {
+		return state, nil
+	}
+
 	for k := range b.chainParams.Deployments[version] {
 		if b.chainParams.Deployments[version][k].Vote.Id == deploymentID {
 			checker := deploymentChecker{
@@ -591,6 +597,59 @@ func (b *BlockChain) NextThresholdState(hash *chainhash.Hash, version uint32, de
 	return state, err
 }
 
+// ForceDeploymentChoice forces the outcome of the vote on the provided
+// deployment to the provided choice, regardless of the votes cast, for all
+// blocks that are subsequently validated or built upon.  This allows test
+// suites to deterministically script agenda activation scenarios.
+//
+// The deployment becomes active with the choice when it is neither the abstain
+// nor the no choice, and it fails when it is the no choice.  Forcing the abstain
+// choice removes any previously forced outcome so the deployment state is once
+// again determined by the votes.
+//
+// Forcing outcomes is only permitted on the simulation and regression test
+// networks since the node would otherwise disagree with the rest of the network
+// about the active consensus rules.  All nodes in a test network must force the
+// same outcomes for the same reason.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ForceDeploymentChoice(deploymentID, choiceID string) error {
+	if net := b.chainParams.Net; net != wire.SimNet && net != wire.RegNet {
+		return fmt.Errorf("deployment outcomes may only be forced on the "+
+			"simulation and regression test networks, not %s", net)
+	}
+
+	version, ok := b.deploymentVers[deploymentID]
+	if !ok {
+		return DeploymentError(deploymentID)
+	}
+	for _, deployment := range b.chainParams.Deployments[version] {
+		if deployment.Vote.Id != deploymentID {
+			continue
+		}
+		for i, choice := range deployment.Vote.Choices {
+			if choice.Id != choiceID {
+				continue
+			}
+
+			b.chainLock.Lock()
+			switch {
+			case choice.IsAbstain:
+				delete(b.forcedDeployments, deploymentID)
+			case choice.IsNo:
+				b.forcedDeployments[deploymentID] = newThresholdState(
+					ThresholdFailed, uint32(i))
+			default:
+				b.forcedDeployments[deploymentID] = newThresholdState(
+					ThresholdActive, uint32(i))
+			}
+			b.chainLock.Unlock()
+			return nil
+		}
+	}
+	return ChoiceError(choiceID)
+}
+
 // isLNFeaturesAgendaActive returns whether or not the LN features agenda vote,
 // as defined in DCP0002 and DCP0003 has passed and is now active from the point
 // of view of the passed block node.
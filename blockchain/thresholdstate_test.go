@@ -5,6 +5,7 @@
 package blockchain
 
 import (
+	"errors"
 	"fmt"
 	"math"
 	"testing"
@@ -605,3 +606,62 @@ func TestThresholdState(t *testing.T) {
 	g.TestThresholdStateChoice(testDummy1ID, ThresholdActive, testDummy1YesIndex)
 	g.TestThresholdStateChoice(testDummy2ID, ThresholdFailed, testDummy2NoIndex)
 }
+
+// TestForceDeploymentChoice ensures forcing the outcome of a deployment
+// overrides its threshold state as expected and is only permitted on the test
+// networks intended for it.
+func TestForceDeploymentChoice(t *testing.T) {
+	// Ensure forcing outcomes is not permitted on the main network.
+	const deploymentID = chaincfg.VoteIDLNFeatures
+	mainChain := newFakeChain(chaincfg.MainNetParams())
+	if err := mainChain.ForceDeploymentChoice(deploymentID, "yes"); err == nil {
+		t.Fatal("ForceDeploymentChoice: did not receive expected error on " +
+			"mainnet")
+	}
+
+	// Ensure unknown deployments and choices are rejected.
+	params := chaincfg.RegNetParams()
+	bc := newFakeChain(params)
+	var deploymentErr DeploymentError
+	err := bc.ForceDeploymentChoice("bogus", "yes")
+	if !errors.As(err, &deploymentErr) {
+		t.Fatalf("ForceDeploymentChoice: unexpected error -- got %v, want "+
+			"%T", err, deploymentErr)
+	}
+	var choiceErr ChoiceError
+	err = bc.ForceDeploymentChoice(deploymentID, "bogus")
+	if !errors.As(err, &choiceErr) {
+		t.Fatalf("ForceDeploymentChoice: unexpected error -- got %v, want "+
+			"%T", err, choiceErr)
+	}
+
+	tests := []struct {
+		choiceID string
+		want     ThresholdStateTuple
+	}{
+		{"yes", newThresholdState(ThresholdActive, 2)},
+		{"no", newThresholdState(ThresholdFailed, 1)},
+		{"abstain", newThresholdState(ThresholdDefined, invalidChoice)},
+	}
+
+	version := bc.deploymentVers[deploymentID]
+	tip := bc.bestChain.Tip()
+	for _, test := range tests {
+		err := bc.ForceDeploymentChoice(deploymentID, test.choiceID)
+		if err != nil {
+			t.Fatalf("%s: ForceDeploymentChoice: unexpected error: %v",
+				test.choiceID, err)
+		}
+		bc.chainLock.Lock()
+		state, err := bc.deploymentState(tip, version, deploymentID)
+		bc.chainLock.Unlock()
+		if err != nil {
+			t.Fatalf("%s: deploymentState: unexpected error: %v",
+				test.choiceID, err)
+		}
+		if state != test.want {
+			t.Fatalf("%s: unexpected state -- got %+v, want %+v",
+				test.choiceID, state, test.want)
+		}
+	}
+}
@@ -100,3 +100,70 @@ func (b *blockProgressLogger) logBlockHeight(block *dcrutil.Block) {
 func (b *blockProgressLogger) SetLastLogTime(time time.Time) {
 	b.lastBlockLogTime = time
 }
+
+// syncRateWindow is the period of time over which the recent block processing
+// rates reported by a syncRateTracker are calculated.
+const syncRateWindow = time.Minute
+
+// syncRateSample houses the time a block was processed and its serialized
+// size.
+type syncRateSample struct {
+	processed time.Time
+	size      int64
+}
+
+// syncRateTracker tracks the rate at which blocks are processed over a recent
+// window of time in order to provide an estimate of the download rate and time
+// remaining while syncing.
+//
+// The tracker is NOT safe for concurrent access.
+type syncRateTracker struct {
+	start   time.Time
+	samples []syncRateSample
+}
+
+// prune removes all samples that are older than the rate window as of the
+// provided time.
+func (t *syncRateTracker) prune(now time.Time) {
+	var i int
+	for i < len(t.samples) && now.Sub(t.samples[i].processed) > syncRateWindow {
+		i++
+	}
+	t.samples = t.samples[i:]
+}
+
+// addBlock records that a block of the provided serialized size was processed
+// at the provided time.
+func (t *syncRateTracker) addBlock(now time.Time, size int64) {
+	if t.start.IsZero() {
+		t.start = now
+	}
+	t.prune(now)
+	t.samples = append(t.samples, syncRateSample{processed: now, size: size})
+}
+
+// rates returns the number of blocks and bytes processed per second over the
+// rate window as of the provided time.  When the tracker has been running for
+// less than the full window, the rates are calculated over the time since the
+// first block was processed instead.
+func (t *syncRateTracker) rates(now time.Time) (float64, float64) {
+	t.prune(now)
+	if len(t.samples) == 0 {
+		return 0, 0
+	}
+
+	elapsed := now.Sub(t.start)
+	if elapsed > syncRateWindow {
+		elapsed = syncRateWindow
+	}
+	if elapsed < time.Second {
+		elapsed = time.Second
+	}
+
+	var bytes int64
+	for _, sample := range t.samples {
+		bytes += sample.size
+	}
+	seconds := elapsed.Seconds()
+	return float64(len(t.samples)) / seconds, float64(bytes) / seconds
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestSyncRateTracker ensures the sync rate tracker calculates the recent block
+// processing rates as expected.
+func TestSyncRateTracker(t *testing.T) {
+	var tracker syncRateTracker
+	start := time.Unix(1577836800, 0)
+
+	// Ensure no rates are reported before any blocks are processed.
+	blocks, bytes := tracker.rates(start)
+	if blocks != 0 || bytes != 0 {
+		t.Fatalf("unexpected initial rates -- got %v blocks/s, %v bytes/s",
+			blocks, bytes)
+	}
+
+	// Process a block every 100ms for 10 seconds and ensure the rates are
+	// calculated over the time since the first block.
+	now := start
+	for i := 0; i < 100; i++ {
+		tracker.addBlock(now, 1000)
+		now = now.Add(100 * time.Millisecond)
+	}
+	blocks, bytes = tracker.rates(now)
+	if blocks != 10 || bytes != 10000 {
+		t.Fatalf("unexpected rates -- got %v blocks/s, %v bytes/s, want 10 "+
+			"blocks/s, 10000 bytes/s", blocks, bytes)
+	}
+
+	// Process a block every second for longer than the rate window and
+	// ensure only the blocks within the window are considered.
+	for i := 0; i < 120; i++ {
+		tracker.addBlock(now, 500)
+		now = now.Add(time.Second)
+	}
+	blocks, bytes = tracker.rates(now)
+	if blocks != 1 || bytes != 500 {
+		t.Fatalf("unexpected rates -- got %v blocks/s, %v bytes/s, want 1 "+
+			"block/s, 500 bytes/s", blocks, bytes)
+	}
+
+	// Ensure the rates drop to zero once no blocks have been processed for
+	// the entire window.
+	blocks, bytes = tracker.rates(now.Add(2 * syncRateWindow))
+	if blocks != 0 || bytes != 0 {
+		t.Fatalf("unexpected stalled rates -- got %v blocks/s, %v bytes/s",
+			blocks, bytes)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"sync"
@@ -23,16 +24,59 @@ import (
 	"github.com/decred/dcrd/database/v2"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/fees/v2"
+	"github.com/decred/dcrd/internal/rpcserver"
 	"github.com/decred/dcrd/mempool/v4"
 	peerpkg "github.com/decred/dcrd/peer/v2"
 	"github.com/decred/dcrd/wire"
 )
 
 const (
-	// minInFlightBlocks is the minimum number of blocks that should be
-	// in the request queue for headers-first mode before requesting
-	// more.
+	// minInFlightBlocks and maxInFlightBlocks are the minimum and maximum
+	// number of blocks that may be requested from a peer at once.  The
+	// actual number is adaptively sized between these limits based on the
+	// measured latency and throughput of the peer.
 	minInFlightBlocks = 10
+	maxInFlightBlocks = 1024
+
+	// minInFlightTxns and maxInFlightTxns are the minimum and maximum number
+	// of transactions that may be requested from a peer at once.  The actual
+	// number is adaptively sized between these limits based on the measured
+	// latency and throughput of the peer.
+	minInFlightTxns = 100
+	maxInFlightTxns = 5000
+
+	// maxPendingRequests is the maximum number of advertised inventory items
+	// that are queued per peer to be requested once the request windows of
+	// the peer have room.
+	maxPendingRequests = wire.MaxInvPerMsg
+
+	// syncPeerCheckInterval is the interval at which the quality of the
+	// data served by the sync peer is checked.
+	syncPeerCheckInterval = time.Second * 15
+
+	// syncPeerStallTimeout is the amount of time the sync peer may go
+	// without providing any blocks or headers while it has blocks the local
+	// chain does not before it is considered stalled.
+	syncPeerStallTimeout = time.Second * 45
+
+	// minSyncPeerBlocksPerSec and minSyncPeerBytesPerSec are the rates the
+	// sync peer must provide either blocks or bytes over the sync rate
+	// window while it has blocks the local chain does not in order to not
+	// be considered slow.  Both rates are considered since the chain
+	// contains both long runs of small blocks and large blocks that take
+	// some time to validate.
+	minSyncPeerBlocksPerSec = 0.5
+	minSyncPeerBytesPerSec  = 16 * 1024
+
+	// invalidDataPenalty is the penalty applied to the serving quality
+	// score of a peer each time it sends invalid data.  Stalls and slow
+	// service each carry a penalty of one.
+	invalidDataPenalty = 2
+
+	// maxSyncPeerPenalty is the penalty at which the sync peer is switched
+	// and at which peers are only considered as sync peer candidates when
+	// there are no others.
+	maxSyncPeerPenalty = 4
 
 	// maxOrphanBlocks is the maximum number of orphan blocks that can be
 	// queued.
@@ -109,9 +153,10 @@ type donePeerMsg struct {
 // txMsg packages a Decred tx message and the peer it came from together
 // so the block handler has access to that information.
 type txMsg struct {
-	tx    *dcrutil.Tx
-	peer  *peerpkg.Peer
-	reply chan struct{}
+	tx        *dcrutil.Tx
+	peer      *peerpkg.Peer
+	rateLimit bool
+	reply     chan struct{}
 }
 
 // getSyncPeerMsg is a message type to be sent across the message channel for
@@ -217,6 +262,12 @@ type processTransactionMsg struct {
 	reply         chan processTransactionResponse
 }
 
+// getSyncStatusMsg is a message type to be sent across the message channel for
+// retrieving details about the progress of syncing the chain.
+type getSyncStatusMsg struct {
+	reply chan *rpcserver.SyncStatus
+}
+
 // isCurrentMsg is a message type to be sent across the message channel for
 // requesting whether or not the block manager believes it is synced with
 // the currently connected peers.
@@ -248,6 +299,10 @@ type PeerNotifier interface {
 	// TransactionConfirmed marks the provided single confirmation transaction
 	// as no longer needing rebroadcasting.
 	TransactionConfirmed(tx *dcrutil.Tx)
+
+	// ReportPoorService marks the provided peer as having served poorly, such
+	// as by stalling or sending invalid data while syncing from it.
+	ReportPoorService(p *peerpkg.Peer)
 }
 
 // blockManangerConfig is a configuration struct for a blockManager.
@@ -278,6 +333,30 @@ type peerSyncState struct {
 	syncCandidate   bool
 	requestedTxns   map[chainhash.Hash]struct{}
 	requestedBlocks map[chainhash.Hash]struct{}
+
+	// The following fields are used to limit the number of requests that
+	// are in flight to the peer at once.  Advertised inventory that does
+	// not fit in the request windows is queued until responses arrive.
+	blockWindow     *requestWindow
+	txWindow        *requestWindow
+	pendingRequests []*wire.InvVect
+
+	// The following fields track the quality of the data served by the
+	// peer.  They are used to switch away from sync peers that stall, are
+	// slow, or send invalid data and to prefer other peers when selecting
+	// a new sync peer.
+	stalls       int
+	slowServes   int
+	invalidData  int
+	syncStart    time.Time
+	lastProgress time.Time
+	served       syncRateTracker
+}
+
+// penalty returns the serving quality penalty for the peer.  Higher values
+// indicate poorer service.
+func (state *peerSyncState) penalty() int {
+	return state.stalls + state.slowServes + state.invalidData*invalidDataPenalty
 }
 
 // orphanBlock represents a block for which the parent is not yet available.  It
@@ -298,6 +377,7 @@ type blockManager struct {
 	requestedTxns   map[chainhash.Hash]struct{}
 	requestedBlocks map[chainhash.Hash]struct{}
 	progressLogger  *blockProgressLogger
+	syncRate        syncRateTracker
 	syncPeer        *peerpkg.Peer
 	msgChan         chan interface{}
 	wg              sync.WaitGroup
@@ -418,6 +498,7 @@ func (b *blockManager) startSync() {
 
 	best := b.cfg.Chain.BestSnapshot()
 	var bestPeer *peerpkg.Peer
+	var bestPenalty int
 	for peer, state := range b.peerStates {
 		if !state.syncCandidate {
 			continue
@@ -434,12 +515,19 @@ func (b *blockManager) startSync() {
 			continue
 		}
 
-		// the best sync candidate is the most updated peer
-		if bestPeer == nil {
-			bestPeer = peer
+		// The best sync candidate is the most updated peer among those
+		// that have served well.  Peers that have served poorly are only
+		// selected when there are no better candidates.
+		penalty := state.penalty()
+		if penalty > maxSyncPeerPenalty {
+			penalty = maxSyncPeerPenalty
 		}
-		if bestPeer.LastBlock() < peer.LastBlock() {
+		if bestPeer == nil || penalty < bestPenalty ||
+			(penalty == bestPenalty &&
+				bestPeer.LastBlock() < peer.LastBlock()) {
+
 			bestPeer = peer
+			bestPenalty = penalty
 		}
 	}
 
@@ -501,6 +589,10 @@ func (b *blockManager) startSync() {
 			}
 		}
 		b.syncPeer = bestPeer
+		state := b.peerStates[bestPeer]
+		state.syncStart = time.Now()
+		state.lastProgress = state.syncStart
+		state.served = syncRateTracker{}
 		b.syncHeightMtx.Lock()
 		b.syncHeight = bestPeer.LastBlock()
 		b.syncHeightMtx.Unlock()
@@ -552,6 +644,8 @@ func (b *blockManager) handleNewPeerMsg(peer *peerpkg.Peer) {
 		syncCandidate:   isSyncCandidate,
 		requestedTxns:   make(map[chainhash.Hash]struct{}),
 		requestedBlocks: make(map[chainhash.Hash]struct{}),
+		blockWindow:     newRequestWindow(minInFlightBlocks, maxInFlightBlocks),
+		txWindow:        newRequestWindow(minInFlightTxns, maxInFlightTxns),
 	}
 
 	// Start syncing by choosing the best candidate if needed.
@@ -594,16 +688,89 @@ func (b *blockManager) handleDonePeerMsg(peer *peerpkg.Peer) {
 	}
 
 	// Attempt to find a new peer to sync from if the quitting peer is the
-	// sync peer.  Also, reset the headers-first state if in headers-first
-	// mode so
+	// sync peer.
 	if b.syncPeer == peer {
-		b.syncPeer = nil
-		if b.headersFirstMode {
-			best := b.cfg.Chain.BestSnapshot()
-			b.resetHeaderState(&best.Hash, best.Height)
+		b.switchSyncPeer()
+	}
+}
+
+// switchSyncPeer clears the current sync peer and attempts to find a new peer
+// to sync from.  Also, the headers-first state is reset if in headers-first
+// mode so the headers are downloaded from the new sync peer.
+func (b *blockManager) switchSyncPeer() {
+	b.syncPeer = nil
+	if b.headersFirstMode {
+		best := b.cfg.Chain.BestSnapshot()
+		b.resetHeaderState(&best.Hash, best.Height)
+	}
+	b.startSync()
+}
+
+// checkSyncPeer checks the quality of the data served by the sync peer and
+// switches to a new sync peer when it has stalled, is slow, or has accumulated
+// too large of a penalty for poor service.  Peers that are switched away from
+// are reported as having served poorly.
+//
+// It MUST be called from the block handler goroutine.
+func (b *blockManager) checkSyncPeer() {
+	peer := b.syncPeer
+	if peer == nil {
+		return
+	}
+	state, exists := b.peerStates[peer]
+	if !exists {
+		return
+	}
+
+	// There is nothing to check when the peer does not have any blocks the
+	// local chain does not.
+	best := b.cfg.Chain.BestSnapshot()
+	if peer.LastBlock() <= best.Height {
+		return
+	}
+
+	now := time.Now()
+	switch {
+	case now.Sub(state.lastProgress) > syncPeerStallTimeout:
+		bmgrLog.Infof("Sync peer %s stalled -- switching sync peer", peer)
+		state.stalls++
+
+	case now.Sub(state.syncStart) >= syncRateWindow &&
+		b.hasOtherSyncCandidates(peer, best.Height):
+
+		blocksPerSec, bytesPerSec := state.served.rates(now)
+		if blocksPerSec >= minSyncPeerBlocksPerSec ||
+			bytesPerSec >= minSyncPeerBytesPerSec {
+
+			return
+		}
+		bmgrLog.Infof("Sync peer %s is slow (%.2f blocks/s, %.0f bytes/s) "+
+			"-- switching sync peer", peer, blocksPerSec, bytesPerSec)
+		state.slowServes++
+
+	case state.penalty() >= maxSyncPeerPenalty &&
+		b.hasOtherSyncCandidates(peer, best.Height):
+
+		bmgrLog.Infof("Sync peer %s has served poorly -- switching sync "+
+			"peer", peer)
+
+	default:
+		return
+	}
+
+	b.cfg.PeerNotifier.ReportPoorService(peer)
+	b.switchSyncPeer()
+}
+
+// hasOtherSyncCandidates returns whether or not there are any sync candidates
+// other than the provided peer that have blocks after the provided height.
+func (b *blockManager) hasOtherSyncCandidates(peer *peerpkg.Peer, height int64) bool {
+	for p, state := range b.peerStates {
+		if p != peer && state.syncCandidate && p.LastBlock() > height {
+			return true
 		}
-		b.startSync()
 	}
+	return false
 }
 
 // errToWireRejectCode determines the wire rejection code and description for a
@@ -716,9 +883,9 @@ func (b *blockManager) handleTxMsg(tmsg *txMsg) {
 
 	// Process the transaction to include validation, insertion in the
 	// memory pool, orphan handling, etc.
-	allowOrphans := cfg.MaxOrphanTxs > 0
+	allowOrphans := b.cfg.TxMemPool.MaxOrphanTxs() > 0
 	acceptedTxs, err := b.cfg.TxMemPool.ProcessTransaction(tmsg.tx,
-		allowOrphans, true, true, mempool.Tag(tmsg.peer.ID()))
+		allowOrphans, tmsg.rateLimit, true, mempool.Tag(tmsg.peer.ID()))
 
 	// Remove transaction from request maps. Either the mempool/chain
 	// already knows about it and as such we shouldn't have any more
@@ -727,6 +894,11 @@ func (b *blockManager) handleTxMsg(tmsg *txMsg) {
 	delete(state.requestedTxns, *txHash)
 	delete(b.requestedTxns, *txHash)
 
+	// Request more of the inventory advertised by the peer now that there
+	// is room in its request window.
+	state.txWindow.received(txHash, time.Now())
+	b.requestPending(peer, state)
+
 	if err != nil {
 		// Do not request this transaction again until a new block
 		// has been processed.
@@ -976,6 +1148,52 @@ func (b *blockManager) processBlockAndOrphans(block *dcrutil.Block, flags blockc
 	return forkLen, false, nil
 }
 
+// syncStatus returns details about the progress of syncing the chain.
+//
+// This function MUST be called from the block handler goroutine.
+func (b *blockManager) syncStatus() *rpcserver.SyncStatus {
+	best := b.cfg.Chain.BestSnapshot()
+	status := &rpcserver.SyncStatus{
+		Current:      b.current(),
+		HeadersFirst: b.headersFirstMode,
+		BlockHeight:  best.Height,
+		HeaderHeight: best.Height,
+		SyncHeight:   b.SyncHeight(),
+	}
+	if b.syncPeer != nil {
+		status.SyncPeerID = b.syncPeer.ID()
+	}
+
+	// The best known header is the final one in the header list when
+	// downloading headers first.
+	if b.headersFirstMode {
+		if e := b.headerList.Back(); e != nil {
+			node := e.Value.(*headerNode)
+			if node.height > status.HeaderHeight {
+				status.HeaderHeight = node.height
+			}
+		}
+	}
+
+	// Estimate the verification progress the same way as the
+	// getblockchaininfo RPC.
+	if status.SyncHeight > 0 {
+		status.VerificationProgress = math.Min(
+			float64(best.Height)/float64(status.SyncHeight), 1.0)
+	}
+
+	// Estimate the time remaining based on the recent rate blocks have been
+	// processed.
+	status.BlocksPerSecond, status.BytesPerSecond = b.syncRate.rates(time.Now())
+	remaining := status.SyncHeight - best.Height
+	if !status.Current && remaining > 0 && status.BlocksPerSecond > 0 {
+		secs := float64(remaining) / status.BlocksPerSecond
+		status.ETA = time.Duration(secs * float64(time.Second))
+	}
+
+	return status
+}
+
 // current returns true if we believe we are synced with our peers, false if we
 // still have blocks to check
 func (b *blockManager) current() bool {
@@ -1046,6 +1264,20 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	delete(state.requestedBlocks, *blockHash)
 	delete(b.requestedBlocks, *blockHash)
 
+	// Request more of the inventory advertised by the peer now that there
+	// is room in its request window.  This is done prior to processing the
+	// block so the peer is able to send more data in the meantime.
+	now := time.Now()
+	state.blockWindow.received(blockHash, now)
+	b.requestPending(peer, state)
+
+	// Track the rate the peer serves blocks while it is the sync peer.
+	if peer == b.syncPeer {
+		state.lastProgress = now
+		state.served.addBlock(now,
+			int64(bmsg.block.MsgBlock().SerializeSize()))
+	}
+
 	// Process the block to include validation, best chain selection, orphan
 	// handling, etc.
 	forkLen, isOrphan, err := b.processBlockAndOrphans(bmsg.block, behaviorFlags)
@@ -1058,6 +1290,15 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 		if errors.As(err, &rErr) {
 			bmgrLog.Infof("Rejected block %v from %s: %v", blockHash,
 				peer, err)
+
+			// Penalize the peer for serving an invalid block.
+			// Duplicate blocks are not penalized since they are
+			// expected to occasionally be received from multiple
+			// peers.
+			if rErr.ErrorCode != blockchain.ErrDuplicateBlock {
+				state.invalidData++
+				b.cfg.PeerNotifier.ReportPoorService(peer)
+			}
 		} else {
 			bmgrLog.Errorf("Failed to process block %v: %v",
 				blockHash, err)
@@ -1095,6 +1336,8 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 		// When the block is not an orphan, log information about it and
 		// update the chain state.
 		b.progressLogger.logBlockHeight(bmsg.block)
+		b.syncRate.addBlock(time.Now(),
+			int64(bmsg.block.MsgBlock().SerializeSize()))
 
 		if onMainChain {
 			// Notify stake difficulty subscribers and prune invalidated
@@ -1139,11 +1382,10 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 	}
 
 	// This is headers-first mode, so if the block is not a checkpoint
-	// request more blocks using the header list when the request queue is
-	// getting short.
+	// request more blocks using the header list to keep the request window
+	// of the sync peer full.
 	if !isCheckpointBlock {
-		if b.startHeader != nil &&
-			len(state.requestedBlocks) < minInFlightBlocks {
+		if b.startHeader != nil {
 			b.fetchHeaderBlocks()
 		}
 		return
@@ -1186,7 +1428,9 @@ func (b *blockManager) handleBlockMsg(bmsg *blockMsg) {
 }
 
 // fetchHeaderBlocks creates and sends a request to the syncPeer for the next
-// list of blocks to be downloaded based on the current list of headers.
+// list of blocks to be downloaded based on the current list of headers.  The
+// number of blocks requested is limited by the room in the request window of
+// the sync peer.
 func (b *blockManager) fetchHeaderBlocks() {
 	// Nothing to do if there is no start header.
 	if b.startHeader == nil {
@@ -1194,10 +1438,18 @@ func (b *blockManager) fetchHeaderBlocks() {
 		return
 	}
 
+	// Nothing to do when the request window of the sync peer is full.
+	now := time.Now()
+	syncPeerState := b.peerStates[b.syncPeer]
+	available := syncPeerState.blockWindow.available(now)
+	if available == 0 {
+		return
+	}
+
 	// Build up a getdata request for the list of blocks the headers
 	// describe.  The size hint will be limited to wire.MaxInvPerMsg by
 	// the function, so no need to double check it here.
-	gdmsg := wire.NewMsgGetDataSizeHint(uint(b.headerList.Len()))
+	gdmsg := wire.NewMsgGetDataSizeHint(uint(available))
 	numRequested := 0
 	for e := b.startHeader; e != nil; e = e.Next() {
 		node, ok := e.Value.(*headerNode)
@@ -1216,8 +1468,8 @@ func (b *blockManager) fetchHeaderBlocks() {
 		}
 		if !haveInv {
 			b.requestedBlocks[*node.hash] = struct{}{}
-			syncPeerState := b.peerStates[b.syncPeer]
 			syncPeerState.requestedBlocks[*node.hash] = struct{}{}
+			syncPeerState.blockWindow.requested(node.hash, now)
 			err = gdmsg.AddInvVect(iv)
 			if err != nil {
 				bmgrLog.Warnf("Failed to add invvect while fetching "+
@@ -1226,7 +1478,7 @@ func (b *blockManager) fetchHeaderBlocks() {
 			numRequested++
 		}
 		b.startHeader = e.Next()
-		if numRequested >= wire.MaxInvPerMsg {
+		if numRequested >= available {
 			break
 		}
 	}
@@ -1238,7 +1490,7 @@ func (b *blockManager) fetchHeaderBlocks() {
 // handleHeadersMsg handles headers messages from all peers.
 func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 	peer := hmsg.peer
-	_, exists := b.peerStates[peer]
+	state, exists := b.peerStates[peer]
 	if !exists {
 		bmgrLog.Warnf("Received headers message from unknown peer %s", peer)
 		return
@@ -1290,6 +1542,7 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 			bmgrLog.Warnf("Received block header that does not "+
 				"properly connect to the chain from peer %s "+
 				"-- disconnecting", peer.Addr())
+			b.cfg.PeerNotifier.ReportPoorService(peer)
 			peer.Disconnect()
 			return
 		}
@@ -1307,6 +1560,7 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 					"expected checkpoint hash of %s -- "+
 					"disconnecting", node.height, node.hash,
 					peer.Addr(), b.nextCheckpoint.Hash)
+				b.cfg.PeerNotifier.ReportPoorService(peer)
 				peer.Disconnect()
 				return
 			}
@@ -1314,6 +1568,11 @@ func (b *blockManager) handleHeadersMsg(hmsg *headersMsg) {
 		}
 	}
 
+	// Headers that connect from the sync peer count as progress.
+	if peer == b.syncPeer {
+		state.lastProgress = time.Now()
+	}
+
 	// When this header is a checkpoint, switch to fetching the blocks for
 	// all of the headers since the last checkpoint.
 	if receivedCheckpoint {
@@ -1516,49 +1775,115 @@ func (b *blockManager) handleInvMsg(imsg *invMsg) {
 		}
 	}
 
-	// Request as much as possible at once.
-	numRequested := 0
+	// Request as much of the inventory as the request windows of the peer
+	// allow and queue the remainder to be requested as responses arrive.
+	// New inventory is queued behind any that is already pending to
+	// preserve the order it was advertised in.
+	if len(state.pendingRequests) > 0 {
+		b.queuePending(state, requestQueue)
+		b.requestPending(peer, state)
+		return
+	}
+	b.queuePending(state, b.sendRequests(peer, state, requestQueue))
+}
+
+// queuePending adds the provided inventory to the queue of inventory that is
+// pending a request to the peer associated with the provided state.  Inventory
+// that would cause the queue to exceed the maximum allowed is discarded.
+func (b *blockManager) queuePending(state *peerSyncState, invVects []*wire.InvVect) {
+	room := maxPendingRequests - len(state.pendingRequests)
+	if len(invVects) > room {
+		bmgrLog.Debugf("Discarding %d advertised inventory items that "+
+			"exceed the pending request limit", len(invVects)-room)
+		invVects = invVects[:room]
+	}
+	state.pendingRequests = append(state.pendingRequests, invVects...)
+}
+
+// sendRequests requests as much of the provided inventory from the peer as its
+// request windows allow and returns the inventory that did not fit.  Inventory
+// that already has a pending request is skipped.
+func (b *blockManager) sendRequests(peer *peerpkg.Peer, state *peerSyncState, invVects []*wire.InvVect) []*wire.InvVect {
+	now := time.Now()
+	availBlocks := state.blockWindow.available(now)
+	availTxns := state.txWindow.available(now)
+
+	// NOTE: The maximum window sizes are less than wire.MaxInvPerMsg, so a
+	// single getdata message is always sufficient.
+	var deferred []*wire.InvVect
 	gdmsg := wire.NewMsgGetData()
-	for _, iv := range requestQueue {
+	for _, iv := range invVects {
 		switch iv.Type {
 		case wire.InvTypeBlock:
 			// Request the block if there is not already a pending
-			// request.
-			if _, exists := b.requestedBlocks[iv.Hash]; !exists {
-				b.requestedBlocks[iv.Hash] = struct{}{}
-				b.limitMap(b.requestedBlocks, maxRequestedBlocks)
-				state.requestedBlocks[iv.Hash] = struct{}{}
-				gdmsg.AddInvVect(iv)
-				numRequested++
+			// request and there is room in the request window.
+			if _, exists := b.requestedBlocks[iv.Hash]; exists {
+				continue
+			}
+			if availBlocks == 0 {
+				deferred = append(deferred, iv)
+				continue
 			}
+			b.requestedBlocks[iv.Hash] = struct{}{}
+			b.limitMap(b.requestedBlocks, maxRequestedBlocks)
+			state.requestedBlocks[iv.Hash] = struct{}{}
+			state.blockWindow.requested(&iv.Hash, now)
+			gdmsg.AddInvVect(iv)
+			availBlocks--
 
 		case wire.InvTypeTx:
 			// Request the transaction if there is not already a
-			// pending request.
-			if _, exists := b.requestedTxns[iv.Hash]; !exists {
-				b.requestedTxns[iv.Hash] = struct{}{}
-				b.limitMap(b.requestedTxns, maxRequestedTxns)
-				state.requestedTxns[iv.Hash] = struct{}{}
-				gdmsg.AddInvVect(iv)
-				numRequested++
+			// pending request and there is room in the request
+			// window.
+			if _, exists := b.requestedTxns[iv.Hash]; exists {
+				continue
 			}
-		}
-
-		if numRequested == wire.MaxInvPerMsg {
-			// Send full getdata message and reset.
-			//
-			// NOTE: There should never be more than wire.MaxInvPerMsg
-			// in the inv request, so we could return after the
-			// QueueMessage, but this is more safe.
-			imsg.peer.QueueMessage(gdmsg, nil)
-			gdmsg = wire.NewMsgGetData()
-			numRequested = 0
+			if availTxns == 0 {
+				deferred = append(deferred, iv)
+				continue
+			}
+			b.requestedTxns[iv.Hash] = struct{}{}
+			b.limitMap(b.requestedTxns, maxRequestedTxns)
+			state.requestedTxns[iv.Hash] = struct{}{}
+			state.txWindow.requested(&iv.Hash, now)
+			gdmsg.AddInvVect(iv)
+			availTxns--
 		}
 	}
 
 	if len(gdmsg.InvList) > 0 {
 		peer.QueueMessage(gdmsg, nil)
 	}
+	return deferred
+}
+
+// requestPending requests as much of the inventory that is pending a request to
+// the peer as its request windows allow.  Pending inventory that has since
+// become known, such as by being received from another peer, is discarded.
+func (b *blockManager) requestPending(peer *peerpkg.Peer, state *peerSyncState) {
+	if len(state.pendingRequests) == 0 {
+		return
+	}
+
+	invVects := make([]*wire.InvVect, 0, len(state.pendingRequests))
+	for _, iv := range state.pendingRequests {
+		if iv.Type == wire.InvTypeTx {
+			if _, exists := b.rejectedTxns[iv.Hash]; exists {
+				continue
+			}
+		}
+		haveInv, err := b.haveInventory(iv)
+		if err != nil {
+			bmgrLog.Warnf("Unexpected failure when checking for "+
+				"existing inventory during pending request "+
+				"processing: %v", err)
+			continue
+		}
+		if !haveInv {
+			invVects = append(invVects, iv)
+		}
+	}
+	state.pendingRequests = b.sendRequests(peer, state, invVects)
 }
 
 // limitMap is a helper function for maps that require a maximum limit by
@@ -1586,6 +1911,9 @@ func (b *blockManager) limitMap(m map[chainhash.Hash]struct{}, limit int) {
 // important because the block manager controls which blocks are needed and how
 // the fetching should proceed.
 func (b *blockManager) blockHandler() {
+	syncPeerTicker := time.NewTicker(syncPeerCheckInterval)
+	defer syncPeerTicker.Stop()
+
 out:
 	for {
 		select {
@@ -1711,10 +2039,16 @@ out:
 			case isCurrentMsg:
 				msg.reply <- b.current()
 
+			case getSyncStatusMsg:
+				msg.reply <- b.syncStatus()
+
 			default:
 				bmgrLog.Warnf("Invalid message type in block handler: %T", msg)
 			}
 
+		case <-syncPeerTicker.C:
+			b.checkSyncPeer()
+
 		case <-b.quit:
 			break out
 		}
@@ -1990,6 +2324,11 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 
 			// Notify registered websocket clients of incoming block.
 			r.ntfnMgr.NotifyBlockConnected(block)
+
+			// Notify gRPC notification streams of incoming block.
+			if r.grpcServer != nil {
+				r.grpcServer.NotifyBlockConnected(block)
+			}
 		}
 
 		if b.cfg.BgBlkTmplGenerator != nil {
@@ -2022,6 +2361,19 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 			r.ntfnMgr.NotifyNewTickets(tnd)
 		}
 
+	// Stake tickets changed state in the most recently connected block.
+	case blockchain.NTTicketLifecycle:
+		tlnd, ok := notification.Data.(*blockchain.TicketLifecycleNtfnsData)
+		if !ok {
+			bmgrLog.Warnf("Ticket lifecycle notification is not " +
+				"TicketLifecycleNtfnsData")
+			break
+		}
+
+		if r := b.cfg.RpcServer(); r != nil {
+			r.ntfnMgr.NotifyTicketLifecycle(tlnd)
+		}
+
 	// A block has been disconnected from the main block chain.
 	case blockchain.NTBlockDisconnected:
 		blockSlice, ok := notification.Data.([]*dcrutil.Block)
@@ -2099,6 +2451,11 @@ func (b *blockManager) handleBlockchainNotification(notification *blockchain.Not
 
 			// Notify registered websocket clients.
 			r.ntfnMgr.NotifyBlockDisconnected(block)
+
+			// Notify gRPC notification streams.
+			if r.grpcServer != nil {
+				r.grpcServer.NotifyBlockDisconnected(block)
+			}
 		}
 
 	// Chain reorganization has commenced.
@@ -2138,15 +2495,16 @@ func (b *blockManager) NewPeer(peer *peerpkg.Peer) {
 }
 
 // QueueTx adds the passed transaction message and peer to the block handling
-// queue.
-func (b *blockManager) QueueTx(tx *dcrutil.Tx, peer *peerpkg.Peer, done chan struct{}) {
+// queue.  The rate limit flag specifies whether or not free and low-fee
+// transactions from the peer are subject to rate limiting.
+func (b *blockManager) QueueTx(tx *dcrutil.Tx, peer *peerpkg.Peer, rateLimit bool, done chan struct{}) {
 	// Don't accept more transactions if we're shutting down.
 	if atomic.LoadInt32(&b.shutdown) != 0 {
 		done <- struct{}{}
 		return
 	}
 
-	b.msgChan <- &txMsg{tx: tx, peer: peer, reply: done}
+	b.msgChan <- &txMsg{tx: tx, peer: peer, rateLimit: rateLimit, reply: done}
 }
 
 // QueueBlock adds the passed block message and peer to the block handling queue.
@@ -2227,6 +2585,14 @@ func (b *blockManager) SyncPeerID() int32 {
 	return <-reply
 }
 
+// SyncStatus returns details about the progress of syncing the chain with the
+// network.
+func (b *blockManager) SyncStatus() *rpcserver.SyncStatus {
+	reply := make(chan *rpcserver.SyncStatus)
+	b.msgChan <- getSyncStatusMsg{reply: reply}
+	return <-reply
+}
+
 // RequestFromPeer allows an outside caller to request blocks or transactions
 // from a peer. The requests are logged in the blockmanager's internal map of
 // requests so they do not later ban the peer for sending the respective data.
@@ -2271,6 +2637,7 @@ func (b *blockManager) requestFromPeer(p *peerpkg.Peer, blocks, txs []*chainhash
 
 		state.requestedBlocks[*bh] = struct{}{}
 		b.requestedBlocks[*bh] = struct{}{}
+		state.blockWindow.requested(bh, time.Now())
 	}
 
 	// Add the vote transactions to the request.
@@ -2308,6 +2675,7 @@ func (b *blockManager) requestFromPeer(p *peerpkg.Peer, blocks, txs []*chainhash
 
 		state.requestedTxns[*vh] = struct{}{}
 		b.requestedTxns[*vh] = struct{}{}
+		state.txWindow.requested(vh, time.Now())
 	}
 
 	if len(msgResp.InvList) > 0 {
@@ -2481,7 +2849,7 @@ func warnMultipleDBs() {
 	// This is intentionally not using the known db types which depend
 	// on the database types compiled into the binary since we want to
 	// detect legacy db types as well.
-	dbTypes := []string{"ffldb", "leveldb", "sqlite"}
+	dbTypes := []string{"ffldb", "ffbolt", "leveldb", "sqlite"}
 	duplicateDbPaths := make([]string, 0, len(dbTypes)-1)
 	for _, dbType := range dbTypes {
 		if dbType == cfg.DbType {
@@ -5,12 +5,15 @@
 package main
 
 import (
-	"crypto/elliptic"
+	"crypto/ed25519"
 	"crypto/x509"
 	"encoding/pem"
 	"io/ioutil"
 	"os"
 	"testing"
+	"time"
+
+	"github.com/decred/dcrd/certgen"
 )
 
 // TestCertCreationWithHosts creates a certificate pair with extra hosts and
@@ -32,7 +35,12 @@ func TestCertCreationWithHosts(t *testing.T) {
 
 	// Generate cert pair with extra hosts.
 	hostnames := []string{"hostname1", "hostname2"}
-	err = genCertPair(certFile.Name(), keyFile.Name(), hostnames, elliptic.P521())
+	err = genCertPair(certFile.Name(), keyFile.Name(), "P-521",
+		&certgen.Options{
+			Organization: "dcrd autogenerated cert",
+			ValidUntil:   time.Now().Add(defaultRPCCertValidity),
+			Hosts:        hostnames,
+		})
 	if err != nil {
 		t.Fatalf("Certificate was not created correctly: %s", err)
 	}
@@ -73,8 +81,63 @@ func TestCertCreationWithOutHosts(t *testing.T) {
 	defer os.Remove(keyFile.Name())
 
 	// Generate cert pair with no extra hosts.
-	err = genCertPair(certFile.Name(), keyFile.Name(), nil, elliptic.P521())
+	err = genCertPair(certFile.Name(), keyFile.Name(), "P-521",
+		&certgen.Options{
+			Organization: "dcrd autogenerated cert",
+			ValidUntil:   time.Now().Add(defaultRPCCertValidity),
+		})
 	if err != nil {
 		t.Fatalf("Certificate was not created correctly: %s", err)
 	}
 }
+
+// TestCertCreationEd25519 ensures creating a certificate pair with Ed25519 keys
+// that is only valid for the specified hosts works as intended.
+func TestCertCreationEd25519(t *testing.T) {
+	certFile, err := ioutil.TempFile("", "certfile")
+	if err != nil {
+		t.Fatalf("Unable to create temp certfile: %s", err)
+	}
+	certFile.Close()
+	defer os.Remove(certFile.Name())
+
+	keyFile, err := ioutil.TempFile("", "keyfile")
+	if err != nil {
+		t.Fatalf("Unable to create temp keyfile: %s", err)
+	}
+	keyFile.Close()
+	defer os.Remove(keyFile.Name())
+
+	// Generate cert pair with Ed25519 keys and only the specified hosts.
+	err = genCertPair(certFile.Name(), keyFile.Name(), ed25519TLSCurve,
+		&certgen.Options{
+			Organization:   "dcrd autogenerated cert",
+			ValidUntil:     time.Now().Add(defaultRPCCertValidity),
+			Hosts:          []string{"dcrd.bogus"},
+			NoDefaultHosts: true,
+		})
+	if err != nil {
+		t.Fatalf("Certificate was not created correctly: %s", err)
+	}
+	certBytes, err := ioutil.ReadFile(certFile.Name())
+	if err != nil {
+		t.Fatalf("Unable to read the certfile: %s", err)
+	}
+	pemCert, _ := pem.Decode(certBytes)
+	x509Cert, err := x509.ParseCertificate(pemCert.Bytes)
+	if err != nil {
+		t.Fatalf("Unable to parse the certificate: %s", err)
+	}
+
+	// Ensure the certificate uses Ed25519 keys and is only valid for the
+	// specified host.
+	if _, ok := x509Cert.PublicKey.(ed25519.PublicKey); !ok {
+		t.Fatalf("unexpected public key type %T", x509Cert.PublicKey)
+	}
+	if err := x509Cert.VerifyHostname("dcrd.bogus"); err != nil {
+		t.Fatalf("failed to verify host: %v", err)
+	}
+	if err := x509Cert.VerifyHostname("localhost"); err == nil {
+		t.Fatal("certificate is unexpectedly valid for localhost")
+	}
+}
@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2015 The btcsuite developers
-// Copyright (c) 2015-2017 The Decred developers
+// Copyright (c) 2015-2020 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -7,6 +7,7 @@ package certgen
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
@@ -22,19 +23,83 @@ import (
 	"time"
 )
 
+// Options houses the options used when generating a certificate.
+type Options struct {
+	// Organization is the organization in the subject of the certificate.
+	Organization string
+
+	// ValidUntil is the time at which the certificate expires.
+	ValidUntil time.Time
+
+	// Hosts is a list of additional host names and IP addresses the
+	// certificate is valid for.  Entries may optionally include a port,
+	// which is ignored.
+	Hosts []string
+
+	// NoDefaultHosts omits the host name of the machine, its local
+	// interface addresses, and all variants of localhost from the
+	// certificate so it is only valid for the additional hosts.  This is
+	// useful when the certificate is served from behind a load balancer or
+	// from within a container where the local names and addresses are not
+	// the ones clients connect to.
+	NoDefaultHosts bool
+}
+
 // NewTLSCertPair returns a new PEM-encoded x.509 certificate pair with new
 // ECDSA keys.  The machine's local interface addresses and all variants of IPv4
 // and IPv6 localhost are included as valid IP addresses.
 func NewTLSCertPair(curve elliptic.Curve, organization string, validUntil time.Time, extraHosts []string) (cert, key []byte, err error) {
-	now := time.Now()
-	if validUntil.Before(now) {
-		return nil, nil, errors.New("validUntil would create an already-expired certificate")
+	return NewTLSCertPairWithOptions(curve, &Options{
+		Organization: organization,
+		ValidUntil:   validUntil,
+		Hosts:        extraHosts,
+	})
+}
+
+// NewTLSCertPairWithOptions returns a new PEM-encoded x.509 certificate pair
+// with new ECDSA keys using the provided options.
+func NewTLSCertPairWithOptions(curve elliptic.Curve, opts *Options) (cert, key []byte, err error) {
+	template, err := newCertTemplate(opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	priv, err := ecdsa.GenerateKey(curve, rand.Reader)
 	if err != nil {
 		return nil, nil, err
 	}
+	keybytes, err := x509.MarshalECPrivateKey(priv)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal private key: %v", err)
+	}
+
+	return encodeCertPair(template, &priv.PublicKey, priv, "EC PRIVATE KEY",
+		keybytes)
+}
+
+// CertExpiry returns the time at which the provided PEM-encoded x.509
+// certificate expires.  This allows callers to regenerate certificates that
+// have expired or are about to.
+func CertExpiry(cert []byte) (time.Time, error) {
+	pemCert, _ := pem.Decode(cert)
+	if pemCert == nil || pemCert.Type != "CERTIFICATE" {
+		return time.Time{}, errors.New("no PEM-encoded certificate found")
+	}
+	x509Cert, err := x509.ParseCertificate(pemCert.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return x509Cert.NotAfter, nil
+}
+
+// newCertTemplate returns a self-signed certificate template for the provided
+// options.
+func newCertTemplate(opts *Options) (*x509.Certificate, error) {
+	now := time.Now()
+	validUntil := opts.ValidUntil
+	if validUntil.Before(now) {
+		return nil, errors.New("validUntil would create an already-expired certificate")
+	}
 
 	// end of ASN.1 time
 	endOfTime := time.Date(2049, 12, 31, 23, 59, 59, 0, time.UTC)
@@ -45,20 +110,11 @@ func NewTLSCertPair(curve elliptic.Curve, organization string, validUntil time.T
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate serial number: %s", err)
-	}
-
-	host, err := os.Hostname()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	ipAddresses := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
-	dnsNames := []string{host}
-	if host != "localhost" {
-		dnsNames = append(dnsNames, "localhost")
+		return nil, fmt.Errorf("failed to generate serial number: %s", err)
 	}
 
+	var ipAddresses []net.IP
+	var dnsNames []string
 	addIP := func(ipAddr net.IP) {
 		for _, ip := range ipAddresses {
 			if ip.Equal(ipAddr) {
@@ -76,18 +132,32 @@ func NewTLSCertPair(curve elliptic.Curve, organization string, validUntil time.T
 		dnsNames = append(dnsNames, host)
 	}
 
-	addrs, err := interfaceAddrs()
-	if err != nil {
-		return nil, nil, err
-	}
-	for _, a := range addrs {
-		ipAddr, _, err := net.ParseCIDR(a.String())
-		if err == nil {
-			addIP(ipAddr)
+	var commonName string
+	if !opts.NoDefaultHosts {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		commonName = host
+
+		addIP(net.ParseIP("127.0.0.1"))
+		addIP(net.ParseIP("::1"))
+		addHost(host)
+		addHost("localhost")
+
+		addrs, err := interfaceAddrs()
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			ipAddr, _, err := net.ParseCIDR(a.String())
+			if err == nil {
+				addIP(ipAddr)
+			}
 		}
 	}
 
-	for _, hostStr := range extraHosts {
+	for _, hostStr := range opts.Hosts {
 		host, _, err := net.SplitHostPort(hostStr)
 		if err != nil {
 			host = hostStr
@@ -98,12 +168,21 @@ func NewTLSCertPair(curve elliptic.Curve, organization string, validUntil time.T
 			addHost(host)
 		}
 	}
+	if len(dnsNames) == 0 && len(ipAddresses) == 0 {
+		return nil, errors.New("certificate would not be valid for any hosts")
+	}
+
+	// Use the first host name as the common name when the default hosts
+	// are omitted.
+	if commonName == "" && len(dnsNames) > 0 {
+		commonName = dnsNames[0]
+	}
 
-	template := x509.Certificate{
+	template := &x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
-			Organization: []string{organization},
-			CommonName:   host,
+			Organization: []string{opts.Organization},
+			CommonName:   commonName,
 		},
 		NotBefore: now.Add(-time.Hour * 24),
 		NotAfter:  validUntil,
@@ -116,9 +195,15 @@ func NewTLSCertPair(curve elliptic.Curve, organization string, validUntil time.T
 		DNSNames:    dnsNames,
 		IPAddresses: ipAddresses,
 	}
+	return template, nil
+}
 
-	derBytes, err := x509.CreateCertificate(rand.Reader, &template,
-		&template, &priv.PublicKey, priv)
+// encodeCertPair self-signs the provided certificate template with the
+// provided key and returns the PEM-encoded certificate along with the
+// provided marshalled private key PEM-encoded with the given block type.
+func encodeCertPair(template *x509.Certificate, pub crypto.PublicKey, priv crypto.Signer, keyType string, keybytes []byte) (cert, key []byte, err error) {
+	derBytes, err := x509.CreateCertificate(rand.Reader, template,
+		template, pub, priv)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create certificate: %v", err)
 	}
@@ -129,13 +214,8 @@ func NewTLSCertPair(curve elliptic.Curve, organization string, validUntil time.T
 		return nil, nil, fmt.Errorf("failed to encode certificate: %v", err)
 	}
 
-	keybytes, err := x509.MarshalECPrivateKey(priv)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to marshal private key: %v", err)
-	}
-
 	keyBuf := &bytes.Buffer{}
-	err = pem.Encode(keyBuf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keybytes})
+	err = pem.Encode(keyBuf, &pem.Block{Type: keyType, Bytes: keybytes})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to encode private key: %v", err)
 	}
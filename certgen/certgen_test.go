@@ -123,3 +123,76 @@ func TestNewTLSCertPair(t *testing.T) {
 		t.Fatal("generated cert does not have valid basic constraints")
 	}
 }
+
+// TestNoDefaultHosts ensures certificates generated without the default hosts
+// are only valid for the specified hosts.
+func TestNoDefaultHosts(t *testing.T) {
+	validUntil := time.Now().Add(time.Hour)
+	hosts := []string{"dcrd.bogus:9109", "192.0.2.1"}
+	cert, _, err := certgen.NewTLSCertPairWithOptions(elliptic.P256(),
+		&certgen.Options{
+			Organization:   "test autogenerated cert",
+			ValidUntil:     validUntil,
+			Hosts:          hosts,
+			NoDefaultHosts: true,
+		})
+	if err != nil {
+		t.Fatalf("failed with unexpected error: %v", err)
+	}
+	pemCert, _ := pem.Decode(cert)
+	if pemCert == nil {
+		t.Fatalf("pem.Decode was unable to decode the certificate")
+	}
+	x509Cert, err := x509.ParseCertificate(pemCert.Bytes)
+	if err != nil {
+		t.Fatalf("failed with unexpected error: %v", err)
+	}
+
+	// Ensure only the specified hosts are present and the first host name
+	// is used as the common name.
+	if len(x509Cert.DNSNames) != 1 || x509Cert.DNSNames[0] != "dcrd.bogus" {
+		t.Fatalf("unexpected DNS names: %v", x509Cert.DNSNames)
+	}
+	if len(x509Cert.IPAddresses) != 1 ||
+		!x509Cert.IPAddresses[0].Equal(net.ParseIP("192.0.2.1")) {
+
+		t.Fatalf("unexpected IP addresses: %v", x509Cert.IPAddresses)
+	}
+	if x509Cert.Subject.CommonName != "dcrd.bogus" {
+		t.Fatalf("unexpected common name: %v", x509Cert.Subject.CommonName)
+	}
+	if err := x509Cert.VerifyHostname("localhost"); err == nil {
+		t.Fatal("certificate is unexpectedly valid for localhost")
+	}
+
+	// Ensure an error is returned when the certificate would not be valid for
+	// any hosts.
+	_, _, err = certgen.NewTLSCertPairWithOptions(elliptic.P256(),
+		&certgen.Options{ValidUntil: validUntil, NoDefaultHosts: true})
+	if err == nil {
+		t.Fatal("expected error for certificate without any hosts")
+	}
+}
+
+// TestCertExpiry ensures the expiry of generated certificates is reported as
+// expected.
+func TestCertExpiry(t *testing.T) {
+	validUntil := time.Unix(time.Now().Add(time.Hour).Unix(), 0)
+	cert, _, err := certgen.NewTLSCertPair(elliptic.P256(), "test", validUntil,
+		nil)
+	if err != nil {
+		t.Fatalf("failed with unexpected error: %v", err)
+	}
+	expiry, err := certgen.CertExpiry(cert)
+	if err != nil {
+		t.Fatalf("failed with unexpected error: %v", err)
+	}
+	if !expiry.Equal(validUntil) {
+		t.Fatalf("unexpected expiry -- got %v, want %v", expiry, validUntil)
+	}
+
+	// Ensure data that is not a certificate is rejected.
+	if _, err := certgen.CertExpiry([]byte("bogus")); err == nil {
+		t.Fatal("expected error for invalid certificate")
+	}
+}
@@ -12,5 +12,12 @@ communications.
 
 ECDSA certificates are supported on all Go versions.  Beginning with Go 1.13,
 this package additionally includes support for Ed25519 certificates.
+
+The hosts and validity period of generated certificates may be configured via
+Options.  By default, certificates are valid for the host name of the machine,
+its local interface addresses, and localhost in addition to any specified
+hosts.  The local hosts may be omitted for certificates that are served from
+behind a load balancer or within a container.  CertExpiry may be used to
+determine when an existing certificate needs to be regenerated.
 */
 package certgen
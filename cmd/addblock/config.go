@@ -41,7 +41,9 @@ type config struct {
 	InFile            string `short:"i" long:"infile" description:"File containing the block(s)"`
 	NoExistsAddrIndex bool   `long:"noexistsaddrindex" description:"Do not build a full index of which addresses were ever seen on the blockchain"`
 	TxIndex           bool   `long:"txindex" description:"Build a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
-	AddrIndex         bool   `long:"addrindex" description:"Build a full address-based transaction index which makes the searchrawtransactions RPC available"`
+	AddrIndex         bool   `long:"addrindex" description:"Build a full address-based transaction index which makes the searchrawtransactions and getaddressutxos RPCs available"`
+	SpendIndex        bool   `long:"spendindex" description:"Build an index of spent transaction outputs which makes the getspentinfo RPC available"`
+	TimeIndex         bool   `long:"timeindex" description:"Build an index of block timestamps which makes the getblockbytime and getblocksbytime RPCs available"`
 	Progress          int    `short:"p" long:"progress" description:"Show a progress message each time this number of seconds have passed -- Use 0 to disable progress announcements"`
 }
 
@@ -324,6 +324,14 @@ func newBlockImporter(db database.DB, r io.ReadSeeker) (*blockImporter, error) {
 		log.Info("Address index is enabled")
 		indexes = append(indexes, indexers.NewAddrIndex(db, activeNetParams))
 	}
+	if cfg.SpendIndex {
+		log.Info("Spend index is enabled")
+		indexes = append(indexes, indexers.NewSpendIndex(db))
+	}
+	if cfg.TimeIndex {
+		log.Info("Block timestamp index is enabled")
+		indexes = append(indexes, indexers.NewTimeIndex(db))
+	}
 	if !cfg.NoExistsAddrIndex {
 		log.Info("Exists address index is enabled")
 		indexes = append(indexes, indexers.NewExistsAddrIndex(db,
@@ -25,6 +25,8 @@ type config struct {
 	Years        int      `short:"y" long:"years" description:"How many years a certificate is valid for"`
 	Organization string   `short:"o" long:"org" description:"Organization in certificate"`
 	ExtraHosts   []string `short:"H" long:"host" description:"Additional hosts/IPs to create certificate for"`
+	NoLocalHosts bool     `short:"L" long:"nolocalhosts" description:"Do not include the host name, local interface addresses, and localhost in the certificate"`
+	Curve        string   `short:"c" long:"curve" description:"Curve to use when generating the keypair {P-256, P-521, Ed25519}"`
 	Force        bool     `short:"f" long:"force" description:"Force overwriting of any old certs and keys"`
 }
 
@@ -32,6 +34,7 @@ func main() {
 	cfg := config{
 		Years:        10,
 		Organization: "gencerts",
+		Curve:        "P-521",
 	}
 	parser := flags.NewParser(&cfg, flags.Default)
 	_, err := parser.Parse()
@@ -61,8 +64,23 @@ func main() {
 		}
 	}
 
-	validUntil := time.Now().Add(time.Duration(cfg.Years) * 365 * 24 * time.Hour)
-	cert, key, err := certgen.NewTLSCertPair(elliptic.P521(), cfg.Organization, validUntil, cfg.ExtraHosts)
+	opts := &certgen.Options{
+		Organization:   cfg.Organization,
+		ValidUntil:     time.Now().Add(time.Duration(cfg.Years) * 365 * 24 * time.Hour),
+		Hosts:          cfg.ExtraHosts,
+		NoDefaultHosts: cfg.NoLocalHosts,
+	}
+	var cert, key []byte
+	switch cfg.Curve {
+	case "P-256":
+		cert, key, err = certgen.NewTLSCertPairWithOptions(elliptic.P256(), opts)
+	case "P-521":
+		cert, key, err = certgen.NewTLSCertPairWithOptions(elliptic.P521(), opts)
+	case "Ed25519":
+		cert, key, err = certgen.NewEd25519TLSCertPairWithOptions(opts)
+	default:
+		err = fmt.Errorf("unsupported curve %s", cfg.Curve)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "cannot generate certificate pair: %v\n", err)
 		os.Exit(1)
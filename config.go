@@ -19,10 +19,10 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/decred/dcrd/addrmgr"
 	"github.com/decred/dcrd/connmgr/v3"
 	"github.com/decred/dcrd/database/v2"
 	_ "github.com/decred/dcrd/database/v2/ffldb"
@@ -31,6 +31,7 @@ import (
 	"github.com/decred/dcrd/mempool/v4"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/sampleconfig"
+	"github.com/decred/dcrd/wire"
 	"github.com/decred/go-socks/socks"
 	"github.com/decred/slog"
 	flags "github.com/jessevdk/go-flags"
@@ -49,8 +50,11 @@ const (
 	defaultMaxRPCClients         = 10
 	defaultMaxRPCWebsockets      = 25
 	defaultMaxRPCConcurrentReqs  = 20
+	defaultRPCRateBurst          = 20
 	defaultDbType                = "ffldb"
+	defaultPeersFileFormat       = "json"
 	defaultFreeTxRelayLimit      = 15.0
+	defaultFreeTxRelayWindow     = time.Minute * 10
 	defaultBlockMinSize          = 0
 	defaultBlockMaxSize          = 375000
 	blockMaxSizeMin              = 1000
@@ -63,9 +67,12 @@ const (
 	defaultMaxOrphanTxSize       = mempool.MaxStandardTxSize
 	defaultSigCacheMaxSize       = 100000
 	defaultTxIndex               = false
+	defaultSpendIndex            = false
+	defaultTimeIndex             = false
 	defaultNoExistsAddrIndex     = false
 	defaultNoCFilters            = false
 	defaultTLSCurve              = "P-521"
+	defaultRPCCertValidity       = time.Hour * 24 * 365 * 10
 	defaultDialTimeout           = time.Second * 30
 	defaultPeerIdleTimeout       = time.Second * 120
 )
@@ -107,25 +114,45 @@ type config struct {
 	AddPeers             []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
 	ConnectPeers         []string      `long:"connect" description:"Connect only to the specified peers at startup"`
 	DisableListen        bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
-	Listeners            []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108)"`
+	Listeners            []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 9108, testnet: 19108) -- NOTE: Per-listener settings may follow the address as comma-separated options: net=ipv4|ipv6|onion to only accept the given network types, whitelist=<ip or network>, maxinbound=<n>, and externalip=<address to advertise>"`
 	MaxSameIP            int           `long:"maxsameip" description:"Max number of connections with the same IP -- 0 to disable"`
 	MaxPeers             int           `long:"maxpeers" description:"Max number of inbound and outbound peers"`
+	MaxUploadTarget      uint64        `long:"maxuploadtarget" description:"Max number of MiB to upload to peers over a rolling 24 hour window -- historical blocks are no longer served to non-whitelisted peers when the target is approached while new blocks and transactions continue to be relayed (0 = unlimited)"`
 	DisableBanning       bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
 	BanDuration          time.Duration `long:"banduration" description:"How long to ban misbehaving peers.  Valid time units are {s, m, h}.  Minimum 1 second"`
 	BanThreshold         uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
-	Whitelists           []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
+	Whitelists           []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned or limited by the peer and RPC connection and request limits. (eg. 192.168.1.0/24 or ::1)"`
+	AllowUserAgents      []string      `long:"allowuseragent" description:"Only allow peers with a user agent that matches the provided pattern -- may be specified multiple times.  Patterns may contain * wildcards and may be suffixed with @<n> to also require at least protocol version n (eg. /dcrwire:0.4.0/dcrd:1.6.*@8)"`
+	DenyUserAgents       []string      `long:"denyuseragent" description:"Refuse peers with a user agent that matches the provided pattern -- may be specified multiple times.  Patterns may contain * wildcards (eg. *BrokenNode*)"`
+	MessageRateLimits    []string      `long:"msgratelimit" description:"Limit the rate of messages with a command received from each peer as <command>:<messages>/<interval> -- may be specified multiple times.  Peers that exceed the limit are disconnected.  A limit of 0 messages removes the default limit for the command (default: addr:10/1m)"`
+	MessageSizeLimits    []string      `long:"msgsizelimit" description:"Limit the payload size of messages with a command received from peers as <command>:<bytes> -- may be specified multiple times.  Peers that exceed the limit are disconnected (eg. headers:100000)"`
+	ASMap                string        `long:"asmap" description:"Path to an asmap file used to group peer addresses by the autonomous system they are announced by instead of by network prefix to make eclipse attacks harder"`
+	AddrBlocklists       []string      `long:"addrblocklist" description:"Path to a file of IP networks (eg. 192.168.1.0/24) and onion address patterns (eg. abc*.onion), one per line, whose peer addresses are never stored -- may be specified multiple times"`
+	PeersFileFormat      string        `long:"peersfileformat" description:"Format used to save known peer addresses {json, binary} -- binary is faster to load and save when many addresses are known"`
 	RPCUser              string        `short:"u" long:"rpcuser" description:"Username for RPC connections"`
 	RPCPass              string        `short:"P" long:"rpcpass" default-mask:"-" description:"Password for RPC connections"`
 	RPCLimitUser         string        `long:"rpclimituser" description:"Username for limited RPC connections"`
 	RPCLimitPass         string        `long:"rpclimitpass" default-mask:"-" description:"Password for limited RPC connections"`
+	RPCAuth              []string      `long:"rpcauth" default-mask:"-" description:"Add RPC credentials bound to a permission scope in the form user:pass:scope where scope is one of admin, readonly, or mining"`
 	RPCListeners         []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 9109, testnet: 19109)"`
+	GRPCListeners        []string      `long:"grpclisten" description:"Add an interface/port to listen for gRPC connections (default port: 9119, testnet: 19119) -- NOTE: The gRPC server is disabled unless at least one interface is specified and requires the RPC server"`
 	RPCCert              string        `long:"rpccert" description:"File containing the certificate file"`
 	RPCKey               string        `long:"rpckey" description:"File containing the certificate key"`
-	TLSCurve             string        `long:"tlscurve" description:"Curve to use when generating TLS keypairs"`
+	RPCClientCAs         string        `long:"rpcclientcafile" description:"File containing the certificate authorities used to verify TLS client certificates -- enables authenticating RPC clients by client certificate as an alternative to passwords"`
+	RPCClientCerts       []string      `long:"rpcclientcert" description:"Grant a permission scope to the TLS client certificates with the given subject common name in the form commonname:scope where scope is one of admin, readonly, or mining -- all verified client certificates are granted admin when none are specified"`
+	TLSCurve             string        `long:"tlscurve" description:"Curve to use when generating TLS keypairs {P-256, P-521, Ed25519}"`
+	RPCCertValidity      time.Duration `long:"rpccertvalidity" description:"The duration generated RPC certificates are valid for. Valid time units are {s,m,h}."`
+	RPCCertRegen         bool          `long:"rpccertregen" description:"Regenerate the RPC certificate and key on startup when the certificate has expired or is about to expire"`
+	RPCCertNoLocalHosts  bool          `long:"rpccertnolocalhosts" description:"Do not include the host name, local interface addresses, and localhost in generated RPC certificates so they are only valid for the names and addresses specified by altdnsnames"`
 	RPCMaxClients        int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
 	RPCMaxWebsockets     int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
 	RPCMaxConcurrentReqs int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
-	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass or rpclimituser/rpclimitpass is specified"`
+	RPCRateLimit         float64       `long:"rpcratelimit" description:"Max average number of RPC requests per second allowed from each client IP -- 0 to disable"`
+	RPCRateBurst         int           `long:"rpcrateburst" description:"Max number of RPC requests each client IP may make in a burst before being limited by --rpcratelimit"`
+	RPCMaxClientReqs     int           `long:"rpcmaxclientreqs" description:"Max number of concurrent RPC requests allowed from each client IP -- 0 to disable"`
+	MetricsListeners     []string      `long:"metricslisten" description:"Add an interface/port to serve Prometheus metrics over HTTP at /metrics (default port: 9120, testnet: 19120) -- NOTE: The metrics server is disabled unless at least one interface is specified and does not require authentication"`
+	RESTEnable           bool          `long:"rest" description:"Enable the read-only REST interface on the RPC listeners under /rest/ -- NOTE: Requests must use the same authentication as RPC connections"`
+	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server -- NOTE: The RPC server is disabled by default if no rpcuser/rpcpass, rpclimituser/rpclimitpass, rpcauth, or rpcclientcafile is specified"`
 	DisableTLS           bool          `long:"notls" description:"Disable TLS for the RPC server -- NOTE: This is only allowed if the RPC server is bound to localhost"`
 	DialTimeout          time.Duration `long:"dialtimeout" description:"How long to wait for TCP connection completion.  Valid time units are {s, m, h}.  Minimum 1 second"`
 	DisableSeeders       bool          `long:"noseeders" description:"Disable seeding for peer discovery"`
@@ -138,10 +165,13 @@ type config struct {
 	OnionProxyPass       string        `long:"onionpass" default-mask:"-" description:"Password for onion proxy server"`
 	NoOnion              bool          `long:"noonion" description:"Disable connecting to tor hidden services"`
 	NoDiscoverIP         bool          `long:"nodiscoverip" description:"Disable automatic network address discovery"`
+	StunServers          []string      `long:"stunserver" description:"Add a STUN server (host:port) to query for the public IPv4 and IPv6 addresses to advertise -- NOTE: Not used when external IPs are specified or a proxy is used"`
 	TorIsolation         bool          `long:"torisolation" description:"Enable Tor stream isolation by randomizing user credentials for each connection."`
+	CJDNS                bool          `long:"cjdns" description:"Treat addresses in the cjdns IPv6 address block (fc00::/8) as reachable -- only enable when this node is connected to a cjdns network"`
 	TestNet              bool          `long:"testnet" description:"Use the test network"`
 	SimNet               bool          `long:"simnet" description:"Use the simulation test network"`
 	RegNet               bool          `long:"regnet" description:"Use the regression test network"`
+	NetParams            string        `long:"netparams" description:"Use the custom private network defined by the provided network parameters file"`
 	DisableCheckpoints   bool          `long:"nocheckpoints" description:"Disable built-in checkpoints.  Don't do this unless you know what you're doing."`
 	DbType               string        `long:"dbtype" description:"Database backend to use for the Block Chain"`
 	Profile              string        `long:"profile" description:"Enable HTTP profiling on given [addr:]port -- NOTE port must be between 1024 and 65536"`
@@ -153,6 +183,9 @@ type config struct {
 	Upnp                 bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
 	MinRelayTxFee        float64       `long:"minrelaytxfee" description:"The minimum transaction fee in DCR/kB to be considered a non-zero fee."`
 	FreeTxRelayLimit     float64       `long:"limitfreerelay" description:"Limit relay of transactions with no transaction fee to the given amount in thousands of bytes per minute"`
+	FreeTxRelayWindow    time.Duration `long:"freerelaywindow" description:"Approximate window over which transactions with no transaction fee are tracked for the limitfreerelay rate limit.  Valid time units are {s, m, h}.  Minimum 1 second"`
+	WhitelistFreeRelay   bool          `long:"whitelistfreerelay" description:"Exempt transactions with no transaction fee relayed by whitelisted peers from rate limiting"`
+	FreeRelayWhitelists  []string      `long:"freerelaywhitelist" description:"Add an IP network or IP of whitelisted peers whose transactions with no transaction fee are exempt from rate limiting. (eg. 192.168.1.0/24 or ::1)"`
 	NoRelayPriority      bool          `long:"norelaypriority" description:"Do not require free or low-fee transactions to have high priority for relaying"`
 	MaxOrphanTxs         int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
 	Generate             bool          `long:"generate" description:"Generate (mine) coins using the CPU"`
@@ -165,13 +198,17 @@ type config struct {
 	NoMiningStateSync    bool          `long:"nominingstatesync" description:"Disable synchronizing the mining state with other nodes"`
 	AllowUnsyncedMining  bool          `long:"allowunsyncedmining" description:"Allow block templates to be generated even when the chain is not considered synced on networks other than the main network.  This is automatically enabled when the simnet option is set.  Don't do this unless you know what you're doing."`
 	AllowOldVotes        bool          `long:"allowoldvotes" description:"Enable the addition of very old votes to the mempool"`
-	BlocksOnly           bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
+	BlocksOnly           bool          `long:"blocksonly" description:"Disable transaction relay, which is announced to peers during the handshake, while still receiving blocks and votes"`
 	AcceptNonStd         bool          `long:"acceptnonstd" description:"Accept and relay non-standard transactions to the network regardless of the default settings for the active network."`
 	RejectNonStd         bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
 	TxIndex              bool          `long:"txindex" description:"Maintain a full hash-based transaction index which makes all transactions available via the getrawtransaction RPC"`
 	DropTxIndex          bool          `long:"droptxindex" description:"Deletes the hash-based transaction index from the database on start up and then exits."`
-	AddrIndex            bool          `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions RPC available"`
+	AddrIndex            bool          `long:"addrindex" description:"Maintain a full address-based transaction index which makes the searchrawtransactions and getaddressutxos RPCs available"`
 	DropAddrIndex        bool          `long:"dropaddrindex" description:"Deletes the address-based transaction index from the database on start up and then exits."`
+	SpendIndex           bool          `long:"spendindex" description:"Maintain an index of spent transaction outputs which makes the getspentinfo RPC available"`
+	DropSpendIndex       bool          `long:"dropspendindex" description:"Deletes the spent transaction output index from the database on start up and then exits."`
+	TimeIndex            bool          `long:"timeindex" description:"Maintain an index of block timestamps which makes the getblockbytime and getblocksbytime RPCs available"`
+	DropTimeIndex        bool          `long:"droptimeindex" description:"Deletes the block timestamp index from the database on start up and then exits."`
 	NoExistsAddrIndex    bool          `long:"noexistsaddrindex" description:"Disable the exists address index, which tracks whether or not an address has even been used."`
 	DropExistsAddrIndex  bool          `long:"dropexistsaddrindex" description:"Deletes the exists address index from the database on start up and then exits."`
 	NoCFilters           bool          `long:"nocfilters" description:"Disable compact filtering (CF) support"`
@@ -179,7 +216,7 @@ type config struct {
 	PipeRx               uint          `long:"piperx" description:"File descriptor of read end pipe to enable parent -> child process communication"`
 	PipeTx               uint          `long:"pipetx" description:"File descriptor of write end pipe to enable parent <- child process communication"`
 	LifetimeEvents       bool          `long:"lifetimeevents" description:"Send lifetime notifications over the TX pipe"`
-	AltDNSNames          []string      `long:"altdnsnames" description:"Specify additional DNS names to use when generating the RPC server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
+	AltDNSNames          []string      `long:"altdnsnames" description:"Specify additional DNS names and IP addresses to use when generating the RPC server certificate" env:"DCRD_ALT_DNSNAMES" env-delim:","`
 	PeerIdleTimeout      time.Duration `long:"peeridletimeout" description:"The duration of inactivity before a peer is timed out. Valid time units are {s,m,h}. Minimum 15 seconds."`
 	onionlookup          func(string) ([]net.IP, error)
 	lookup               func(string) ([]net.IP, error)
@@ -187,7 +224,16 @@ type config struct {
 	dial                 func(context.Context, string, string) (net.Conn, error)
 	miningAddrs          []dcrutil.Address
 	minRelayTxFee        dcrutil.Amount
+	rpcAuthUsers         []*rpcAuthUser
+	rpcClientCertPerms   map[string]rpcPermission
 	whitelists           []*net.IPNet
+	freeRelayWhitelists  []*net.IPNet
+	userAgentFilter      *userAgentFilter
+	peerMessageLimits    *wire.MessageLimits
+	asmap                *addrmgr.ASMap
+	addrBlocklists       []*addrmgr.Blocklist
+	peersFileFormat      addrmgr.PeersFileFormat
+	listenerConfigs      map[string]*listenerConfig
 	ipv4NetInfo          types.NetworksResult
 	ipv6NetInfo          types.NetworksResult
 	onionNetInfo         types.NetworksResult
@@ -362,6 +408,31 @@ func normalizeAddress(addr, defaultPort string) string {
 	return addr
 }
 
+// parseWhitelist parses the provided whitelist value, which may either be an IP
+// network in CIDR notation or a single IP address, into an IP network.
+func parseWhitelist(addr string) (*net.IPNet, error) {
+	_, ipnet, err := net.ParseCIDR(addr)
+	if err == nil {
+		return ipnet, nil
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("the whitelist value of '%s' is invalid", addr)
+	}
+	var bits int
+	if ip.To4() == nil {
+		// IPv6
+		bits = 128
+	} else {
+		bits = 32
+	}
+	return &net.IPNet{
+		IP:   ip,
+		Mask: net.CIDRMask(bits, bits),
+	}, nil
+}
+
 // normalizeAddresses returns a new slice with all the passed peer addresses
 // normalized with the given default port, and all duplicates removed.
 func normalizeAddresses(addrs []string, defaultPort string) []string {
@@ -518,14 +589,18 @@ func loadConfig() (*config, []string, error) {
 		RPCMaxClients:        defaultMaxRPCClients,
 		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
 		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
+		RPCRateBurst:         defaultRPCRateBurst,
 		DataDir:              defaultDataDir,
 		LogDir:               defaultLogDir,
 		DbType:               defaultDbType,
+		PeersFileFormat:      defaultPeersFileFormat,
 		RPCKey:               defaultRPCKeyFile,
 		TLSCurve:             defaultTLSCurve,
+		RPCCertValidity:      defaultRPCCertValidity,
 		RPCCert:              defaultRPCCertFile,
 		MinRelayTxFee:        mempool.DefaultMinRelayTxFee.ToCoin(),
 		FreeTxRelayLimit:     defaultFreeTxRelayLimit,
+		FreeTxRelayWindow:    defaultFreeTxRelayWindow,
 		BlockMinSize:         defaultBlockMinSize,
 		BlockMaxSize:         defaultBlockMaxSize,
 		BlockPrioritySize:    mempool.DefaultBlockPrioritySize,
@@ -536,6 +611,8 @@ func loadConfig() (*config, []string, error) {
 		AllowUnsyncedMining:  defaultAllowUnsyncedMining,
 		TxIndex:              defaultTxIndex,
 		AddrIndex:            defaultAddrIndex,
+		SpendIndex:           defaultSpendIndex,
+		TimeIndex:            defaultTimeIndex,
 		AllowOldVotes:        defaultAllowOldVotes,
 		NoExistsAddrIndex:    defaultNoExistsAddrIndex,
 		NoCFilters:           defaultNoCFilters,
@@ -714,9 +791,25 @@ func loadConfig() (*config, []string, error) {
 		numNets++
 		cfg.params = &regNetParams
 	}
+	if cfg.NetParams != "" {
+		numNets++
+		// Also disable dns seeding on custom networks since they are
+		// private.
+		netParamsFile := cleanAndExpandPath(cfg.NetParams)
+		customParams, err := loadCustomNetParams(netParamsFile)
+		if err != nil {
+			str := "%s: invalid network parameters file %s: %v"
+			err := fmt.Errorf(str, funcName, netParamsFile, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.params = customParams
+		cfg.DisableSeeders = true
+	}
 	if numNets > 1 {
-		str := "%s: the testnet, regnet, and simnet params can't be " +
-			"used together -- choose one of the three"
+		str := "%s: the testnet, regnet, simnet, and netparams params " +
+			"can't be used together -- choose one of the four"
 		err := fmt.Errorf(str, funcName)
 		fmt.Fprintln(os.Stderr, err)
 		fmt.Fprintln(os.Stderr, usageMessage)
@@ -795,29 +888,15 @@ func loadConfig() (*config, []string, error) {
 
 	// Validate format of profile, can be an address:port, or just a port.
 	if cfg.Profile != "" {
-		// if profile is just a number, then add a default host of "127.0.0.1" such that Profile is a valid tcp address
-		if _, err := strconv.Atoi(cfg.Profile); err == nil {
-			cfg.Profile = net.JoinHostPort("127.0.0.1", cfg.Profile)
-		}
-
-		// check the Profile is a valid address
-		_, portStr, err := net.SplitHostPort(cfg.Profile)
+		profileAddr, err := normalizeProfileAddr(cfg.Profile)
 		if err != nil {
-			str := "%s: profile: %s"
+			str := "%s: profile: %v"
 			err := fmt.Errorf(str, funcName, err)
 			fmt.Fprintln(os.Stderr, err)
 			fmt.Fprintln(os.Stderr, usageMessage)
 			return nil, nil, err
 		}
-
-		// finally, check the port is in range
-		if port, _ := strconv.Atoi(portStr); port < 1024 || port > 65535 {
-			str := "%s: profile: address %s: port must be between 1024 and 65535"
-			err := fmt.Errorf(str, funcName, cfg.Profile)
-			fmt.Fprintln(os.Stderr, err)
-			fmt.Fprintln(os.Stderr, usageMessage)
-			return nil, nil, err
-		}
+		cfg.Profile = profileAddr
 	}
 
 	// Don't allow ban durations that are too short.
@@ -850,36 +929,118 @@ func loadConfig() (*config, []string, error) {
 
 	// Validate any given whitelisted IP addresses and networks.
 	if len(cfg.Whitelists) > 0 {
-		var ip net.IP
 		cfg.whitelists = make([]*net.IPNet, 0, len(cfg.Whitelists))
-
 		for _, addr := range cfg.Whitelists {
-			_, ipnet, err := net.ParseCIDR(addr)
+			ipnet, err := parseWhitelist(addr)
 			if err != nil {
-				ip = net.ParseIP(addr)
-				if ip == nil {
-					str := "%s: the whitelist value of '%s' is invalid"
-					err = fmt.Errorf(str, funcName, addr)
-					fmt.Fprintln(os.Stderr, err)
-					fmt.Fprintln(os.Stderr, usageMessage)
-					return nil, nil, err
-				}
-				var bits int
-				if ip.To4() == nil {
-					// IPv6
-					bits = 128
-				} else {
-					bits = 32
-				}
-				ipnet = &net.IPNet{
-					IP:   ip,
-					Mask: net.CIDRMask(bits, bits),
-				}
+				err = fmt.Errorf("%s: %v", funcName, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
 			}
 			cfg.whitelists = append(cfg.whitelists, ipnet)
 		}
 	}
 
+	// Validate any given IP addresses and networks of whitelisted peers that
+	// are exempt from free transaction rate limiting.
+	if len(cfg.FreeRelayWhitelists) > 0 {
+		cfg.freeRelayWhitelists = make([]*net.IPNet, 0,
+			len(cfg.FreeRelayWhitelists))
+		for _, addr := range cfg.FreeRelayWhitelists {
+			ipnet, err := parseWhitelist(addr)
+			if err != nil {
+				err = fmt.Errorf("%s: %v", funcName, err)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+			cfg.freeRelayWhitelists = append(cfg.freeRelayWhitelists, ipnet)
+		}
+	}
+
+	// Validate any given user agent patterns.
+	cfg.userAgentFilter, err = newUserAgentFilter(cfg.AllowUserAgents,
+		cfg.DenyUserAgents)
+	if err != nil {
+		err = fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Validate any given peer message limits.
+	cfg.peerMessageLimits, err = newPeerMessageLimits(cfg.MessageRateLimits,
+		cfg.MessageSizeLimits)
+	if err != nil {
+		err = fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Validate the peers file format.
+	switch cfg.PeersFileFormat {
+	case addrmgr.PeersFileJSON.String():
+		cfg.peersFileFormat = addrmgr.PeersFileJSON
+	case addrmgr.PeersFileBinary.String():
+		cfg.peersFileFormat = addrmgr.PeersFileBinary
+	default:
+		str := "%s: the specified peers file format [%v] is invalid -- " +
+			"supported formats [%v %v]"
+		err := fmt.Errorf(str, funcName, cfg.PeersFileFormat,
+			addrmgr.PeersFileJSON, addrmgr.PeersFileBinary)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Load the asmap when one is specified.
+	if cfg.ASMap != "" {
+		cfg.ASMap = cleanAndExpandPath(cfg.ASMap)
+		cfg.asmap, err = addrmgr.LoadASMap(cfg.ASMap)
+		if err != nil {
+			err = fmt.Errorf("%s: unable to load asmap %s: %v", funcName,
+				cfg.ASMap, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+
+	// Load the address blocklists.
+	for i, path := range cfg.AddrBlocklists {
+		path = cleanAndExpandPath(path)
+		cfg.AddrBlocklists[i] = path
+		blocklist, err := addrmgr.LoadBlocklist(path)
+		if err != nil {
+			err = fmt.Errorf("%s: unable to load address blocklist %s: %v",
+				funcName, path, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.addrBlocklists = append(cfg.addrBlocklists, blocklist)
+	}
+
+	// Parse and remove any per-listener options from the listen addresses.
+	cfg.listenerConfigs = make(map[string]*listenerConfig)
+	for i, listener := range cfg.Listeners {
+		addr, lc, err := parseListenerOptions(listener)
+		if err != nil {
+			str := "%s: the listen value of '%s' is invalid: %v"
+			err := fmt.Errorf(str, funcName, listener, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.Listeners[i] = addr
+		if lc != nil {
+			addr = normalizeAddress(addr, cfg.params.DefaultPort)
+			cfg.listenerConfigs[addr] = lc
+		}
+	}
+
 	// --addPeer and --connect do not mix.
 	if len(cfg.AddPeers) > 0 && len(cfg.ConnectPeers) > 0 {
 		str := "%s: the --addpeer and --connect options can not be " +
@@ -930,9 +1091,91 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
-	// The RPC server is disabled if no username or password is provided.
-	if (cfg.RPCUser == "" || cfg.RPCPass == "") &&
-		(cfg.RPCLimitUser == "" || cfg.RPCLimitPass == "") {
+	// Combine the legacy admin and limited users with any additional
+	// credentials specified via --rpcauth while ensuring all usernames are
+	// unique.
+	if cfg.RPCUser != "" && cfg.RPCPass != "" {
+		cfg.rpcAuthUsers = append(cfg.rpcAuthUsers, &rpcAuthUser{
+			user: cfg.RPCUser,
+			pass: cfg.RPCPass,
+			perm: rpcPermAdmin,
+		})
+	}
+	if cfg.RPCLimitUser != "" && cfg.RPCLimitPass != "" {
+		cfg.rpcAuthUsers = append(cfg.rpcAuthUsers, &rpcAuthUser{
+			user: cfg.RPCLimitUser,
+			pass: cfg.RPCLimitPass,
+			perm: rpcPermReadOnly,
+		})
+	}
+	for _, auth := range cfg.RPCAuth {
+		authUser, err := parseRPCAuth(auth)
+		if err != nil {
+			str := "%s: invalid --rpcauth: %v"
+			err := fmt.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		for _, u := range cfg.rpcAuthUsers {
+			if u.user == authUser.user {
+				str := "%s: username %q is specified by more than " +
+					"one set of RPC credentials"
+				err := fmt.Errorf(str, funcName, authUser.user)
+				fmt.Fprintln(os.Stderr, err)
+				fmt.Fprintln(os.Stderr, usageMessage)
+				return nil, nil, err
+			}
+		}
+		cfg.rpcAuthUsers = append(cfg.rpcAuthUsers, authUser)
+	}
+
+	// Parse the permissions granted to client certificates while ensuring
+	// they are only specified when client certificate authentication is
+	// enabled.
+	if len(cfg.RPCClientCerts) > 0 && cfg.RPCClientCAs == "" {
+		str := "%s: the --rpcclientcert option requires --rpcclientcafile"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	cfg.rpcClientCertPerms = make(map[string]rpcPermission,
+		len(cfg.RPCClientCerts))
+	for _, clientCert := range cfg.RPCClientCerts {
+		commonName, perm, err := parseRPCClientCert(clientCert)
+		if err != nil {
+			str := "%s: invalid --rpcclientcert: %v"
+			err := fmt.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		if _, ok := cfg.rpcClientCertPerms[commonName]; ok {
+			str := "%s: client certificate %q is specified by more " +
+				"than one --rpcclientcert option"
+			err := fmt.Errorf(str, funcName, commonName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+		cfg.rpcClientCertPerms[commonName] = perm
+	}
+	if cfg.RPCClientCAs != "" {
+		cfg.RPCClientCAs = cleanAndExpandPath(cfg.RPCClientCAs)
+		if cfg.DisableTLS {
+			str := "%s: the --rpcclientcafile and --notls options " +
+				"may not be used together"
+			err := fmt.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, nil, err
+		}
+	}
+
+	// The RPC server is disabled if no credentials are provided and client
+	// certificate authentication is not enabled.
+	if len(cfg.rpcAuthUsers) == 0 && cfg.RPCClientCAs == "" {
 		cfg.DisableRPC = true
 	}
 
@@ -958,6 +1201,32 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Validate the per client RPC limits.
+	if cfg.RPCRateLimit < 0 {
+		str := "%s: the rpcratelimit option may not be less than 0 " +
+			"-- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RPCRateLimit)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.RPCRateLimit > 0 && cfg.RPCRateBurst < 1 {
+		str := "%s: the rpcrateburst option may not be less than 1 " +
+			"when the rpcratelimit option is set -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCRateBurst)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+	if cfg.RPCMaxClientReqs < 0 {
+		str := "%s: the rpcmaxclientreqs option may not be less than 0 " +
+			"-- parsed [%d]"
+		err := fmt.Errorf(str, funcName, cfg.RPCMaxClientReqs)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Validate the minrelaytxfee.
 	cfg.minRelayTxFee, err = dcrutil.NewAmount(cfg.MinRelayTxFee)
 	if err != nil {
@@ -968,6 +1237,16 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// Don't allow free transaction relay windows that are too short.
+	if cfg.FreeTxRelayWindow < time.Second {
+		str := "%s: the freerelaywindow option may not be less than 1s " +
+			"-- parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.FreeTxRelayWindow)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// Ensure the specified max block size is not larger than the network will
 	// allow.  1000 bytes is subtracted from the max to account for overhead.
 	blockMaxSizeMax := uint32(cfg.params.MaximumBlockSizes[0]) - 1000
@@ -1029,6 +1308,26 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, err
 	}
 
+	// --spendindex and --dropspendindex do not mix.
+	if cfg.SpendIndex && cfg.DropSpendIndex {
+		err := fmt.Errorf("%s: the --spendindex and --dropspendindex "+
+			"options may not be activated at the same time",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// --timeindex and --droptimeindex do not mix.
+	if cfg.TimeIndex && cfg.DropTimeIndex {
+		err := fmt.Errorf("%s: the --timeindex and --droptimeindex "+
+			"options may not be activated at the same time",
+			funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
 	// !--noexistsaddrindex and --dropexistsaddrindex do not mix.
 	if !cfg.NoExistsAddrIndex && cfg.DropExistsAddrIndex {
 		err := fmt.Errorf("dropexistsaddrindex cannot be activated when " +
@@ -1095,6 +1394,16 @@ func loadConfig() (*config, []string, error) {
 	cfg.RPCListeners = normalizeAddresses(cfg.RPCListeners,
 		cfg.params.rpcPort)
 
+	// Add default port to all gRPC listener addresses if needed and remove
+	// duplicate addresses.
+	cfg.GRPCListeners = normalizeAddresses(cfg.GRPCListeners,
+		cfg.params.grpcPort)
+
+	// Add default port to all metrics listener addresses if needed and
+	// remove duplicate addresses.
+	cfg.MetricsListeners = normalizeAddresses(cfg.MetricsListeners,
+		cfg.params.metricsPort)
+
 	// Only allow TLS to be disabled if the RPC is bound to localhost
 	// addresses.
 	if !cfg.DisableRPC && cfg.DisableTLS {
@@ -1103,7 +1412,11 @@ func loadConfig() (*config, []string, error) {
 			"127.0.0.1": {},
 			"::1":       {},
 		}
-		for _, addr := range cfg.RPCListeners {
+		listeners := make([]string, 0, len(cfg.RPCListeners)+
+			len(cfg.GRPCListeners))
+		listeners = append(listeners, cfg.RPCListeners...)
+		listeners = append(listeners, cfg.GRPCListeners...)
+		for _, addr := range listeners {
 			host, _, err := net.SplitHostPort(addr)
 			if err != nil {
 				str := "%s: RPC listen interface '%s' is " +
@@ -1250,8 +1563,30 @@ func loadConfig() (*config, []string, error) {
 	}
 
 	// Prevent using an unsupported curve.
-	if _, err := tlsCurve(cfg.TLSCurve); err != nil {
+	if cfg.TLSCurve != ed25519TLSCurve {
+		if _, err := tlsCurve(cfg.TLSCurve); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return nil, nil, err
+		}
+	}
+
+	// Don't allow RPC certificates that are not valid for any duration.
+	if cfg.RPCCertValidity <= 0 {
+		str := "%s: the rpccertvalidity option must be positive -- " +
+			"parsed [%v]"
+		err := fmt.Errorf(str, funcName, cfg.RPCCertValidity)
 		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, nil, err
+	}
+
+	// Don't allow RPC certificates that are not valid for any hosts.
+	if cfg.RPCCertNoLocalHosts && len(cfg.AltDNSNames) == 0 {
+		str := "%s: the rpccertnolocalhosts option requires at least " +
+			"one altdnsnames entry"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
 		return nil, nil, err
 	}
 
@@ -1291,6 +1626,11 @@ func dcrdLookup(host string) ([]net.IP, error) {
 	return cfg.lookup(host)
 }
 
+// ed25519TLSCurve is the config option that indicates Ed25519 keys are used
+// when generating TLS keypairs.  It is handled separately from the ECDSA curves
+// returned by tlsCurve since Ed25519 is not an elliptic.Curve.
+const ed25519TLSCurve = "Ed25519"
+
 // tlsCurve returns the correct curve given a config option indicating the
 // curve to use or an error if the curve does not exist.
 func tlsCurve(curve string) (elliptic.Curve, error) {
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -25,9 +26,16 @@ var (
 
 	// maxRetryDuration is the max duration of time retrying of a persistent
 	// connection is allowed to grow to.  This is necessary since the retry
-	// logic uses a backoff mechanism which increases the interval base times
-	// the number of retries that have been done.
+	// logic uses an exponential backoff mechanism which doubles the interval
+	// for each retry that has been done.
 	maxRetryDuration = time.Minute * 5
+
+	// minStableConnDuration is the minimum duration of time a persistent
+	// connection must remain established to be considered healthy.  The
+	// retry backoff of a persistent connection is only reset once it is
+	// healthy so that peers which repeatedly connect and then immediately
+	// disconnect continue to back off.
+	minStableConnDuration = time.Minute
 )
 
 const (
@@ -43,6 +51,10 @@ const (
 	// defaultTargetOutbound is the default number of outbound connections to
 	// maintain.
 	defaultTargetOutbound = uint32(8)
+
+	// defaultMaxConcurrentRetries is the default maximum number of retries of
+	// persistent connections that may be dialing at once.
+	defaultMaxConcurrentRetries = uint32(4)
 )
 
 // ConnState represents the state of the requested connection.
@@ -75,12 +87,15 @@ type ConnReq struct {
 	// be accessed outside of it.
 	//
 	// retryCount is the number of times a permanent connection request that
-	// fails to connect has been retried since the last successful connection.
+	// fails to connect has been retried since the last healthy connection.
+	//
+	// connectedAt is the time the most recent connection was established.
 	//
 	// conn is the underlying network connection.  It will be nil before a
 	// connection has been established.
-	retryCount uint32
-	conn       net.Conn
+	retryCount  uint32
+	connectedAt time.Time
+	conn        net.Conn
 
 	// Addr is the address to connect to.
 	Addr net.Addr
@@ -88,7 +103,8 @@ type ConnReq struct {
 	// Permanent specifies whether or not the connection request represents what
 	// should be treated as a permanent connection, meaning the connection
 	// manager will try to always maintain the connection including retries with
-	// increasing backoff timeouts.
+	// jittered exponential backoff timeouts that are independent of those of
+	// other connection requests.
 	Permanent bool
 }
 
@@ -148,6 +164,12 @@ type Config struct {
 	// requests. Defaults to 5s.
 	RetryDuration time.Duration
 
+	// MaxConcurrentRetries is the maximum number of retries of permanent
+	// connection requests that may be dialing at once.  This prevents
+	// permanent connections that repeatedly fail from monopolizing dial
+	// attempts.  Defaults to 4.
+	MaxConcurrentRetries uint32
+
 	// OnConnection is a callback that is fired when a new outbound
 	// connection is established.
 	OnConnection func(*ConnReq, net.Conn)
@@ -234,16 +256,38 @@ type ConnManager struct {
 	// outside of it.
 	failedAttempts uint64
 
+	// retrySem limits the number of retries of permanent connection requests
+	// that may be dialing at once.
+	retrySem chan struct{}
+
 	// requests is used internally to interact with the connection handler
 	// goroutine.
 	requests chan interface{}
 }
 
+// retryDelay returns the duration to wait before the provided retry of a
+// permanent connection request.  The duration starts at the configured retry
+// duration and doubles with each retry up to the max retry duration.  It is
+// also jittered to a random duration between half of that and the full
+// duration so that permanent connections which fail at the same time do not
+// retry in lockstep.
+func (cm *ConnManager) retryDelay(retryCount uint32) time.Duration {
+	d := cm.cfg.RetryDuration
+	for i := uint32(1); i < retryCount && d < maxRetryDuration; i++ {
+		d *= 2
+	}
+	if d > maxRetryDuration {
+		d = maxRetryDuration
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
 // handleFailedConn handles a connection failed due to a disconnect or any
-// other failure. If permanent, it retries the connection after the configured
-// retry duration. Otherwise, if required, it makes a new connection request.
-// After maxFailedConnectionAttempts new connections will be retried after the
-// configured retry duration.
+// other failure. If permanent, it retries the connection after a jittered
+// exponential backoff. Otherwise, if required, it makes a new connection
+// request.  After maxFailedConnectionAttempts new connections will be retried
+// after the configured retry duration.
 func (cm *ConnManager) handleFailedConn(ctx context.Context, c *ConnReq) {
 	// Ignore during shutdown.
 	if ctx.Err() != nil {
@@ -252,17 +296,28 @@ func (cm *ConnManager) handleFailedConn(ctx context.Context, c *ConnReq) {
 
 	if c.Permanent {
 		c.retryCount++
-		d := time.Duration(c.retryCount) * cm.cfg.RetryDuration
-		if d > maxRetryDuration {
-			d = maxRetryDuration
-		}
+		d := cm.retryDelay(c.retryCount)
 		log.Debugf("Retrying connection to %v in %v", c, d)
-		select {
-		case <-time.After(d):
-			go cm.Connect(ctx, c)
-		case <-cm.quit:
-			return
-		}
+
+		// Wait to retry independently of the connection handler so the
+		// backoff of a permanent connection does not delay any other
+		// connections.  The number of retries dialing at once is limited
+		// so permanent connections that repeatedly fail can't monopolize
+		// dial attempts.
+		go func() {
+			select {
+			case <-time.After(d):
+			case <-cm.quit:
+				return
+			}
+			select {
+			case cm.retrySem <- struct{}{}:
+			case <-cm.quit:
+				return
+			}
+			cm.Connect(ctx, c)
+			<-cm.retrySem
+		}()
 	} else if cm.cfg.GetNewAddress != nil {
 		cm.failedAttempts++
 		if cm.failedAttempts >= maxFailedAttempts {
@@ -322,9 +377,15 @@ out:
 				connReq.updateState(ConnEstablished)
 				connReq.conn = msg.conn
 				conns[connReq.id] = connReq
+				connReq.connectedAt = time.Now()
 				log.Debugf("Connected to %v", connReq)
-				connReq.retryCount = 0
-				cm.failedAttempts = 0
+
+				// The retries of permanent connection requests
+				// are tracked independently and only reset once
+				// the connection has proven to be healthy.
+				if !connReq.Permanent {
+					cm.failedAttempts = 0
+				}
 
 				delete(pending, connReq.id)
 
@@ -358,6 +419,15 @@ out:
 				log.Debugf("Disconnected from %v", connReq)
 				delete(conns, msg.id)
 
+				// Reset the retry backoff of permanent
+				// connections that remained established long
+				// enough to be considered healthy.
+				if connReq.Permanent &&
+					time.Since(connReq.connectedAt) >= minStableConnDuration {
+
+					connReq.retryCount = 0
+				}
+
 				if connReq.conn != nil {
 					connReq.conn.Close()
 				}
@@ -691,8 +761,12 @@ func New(cfg *Config) (*ConnManager, error) {
 	if cfg.TargetOutbound == 0 {
 		cfg.TargetOutbound = defaultTargetOutbound
 	}
+	if cfg.MaxConcurrentRetries == 0 {
+		cfg.MaxConcurrentRetries = defaultMaxConcurrentRetries
+	}
 	cm := ConnManager{
 		cfg:      *cfg, // Copy so caller can't mutate
+		retrySem: make(chan struct{}, cfg.MaxConcurrentRetries),
 		requests: make(chan interface{}),
 		quit:     make(chan struct{}),
 	}
@@ -398,6 +398,120 @@ func TestMaxRetryDuration(t *testing.T) {
 	wg.Wait()
 }
 
+// TestRetryDelay ensures the retry delay of permanent connection requests grows
+// exponentially up to the max retry duration with jitter.
+func TestRetryDelay(t *testing.T) {
+	// This test relies on the current value of the max retry duration defined
+	// in the tests, so assert it.
+	if maxRetryDuration != 2*time.Millisecond {
+		t.Fatalf("max retry duration of %v is not the required value for test",
+			maxRetryDuration)
+	}
+
+	cmgr, err := New(&Config{
+		RetryDuration: 500 * time.Microsecond,
+		Dial:          mockDialer,
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	tests := []struct {
+		retryCount uint32
+		want       time.Duration
+	}{
+		{retryCount: 1, want: 500 * time.Microsecond},
+		{retryCount: 2, want: time.Millisecond},
+		{retryCount: 3, want: 2 * time.Millisecond},
+		{retryCount: 4, want: 2 * time.Millisecond},
+		{retryCount: 100, want: 2 * time.Millisecond},
+	}
+	for _, test := range tests {
+		for i := 0; i < 10; i++ {
+			d := cmgr.retryDelay(test.retryCount)
+			if d < test.want/2 || d > test.want {
+				t.Fatalf("retry %d: delay %v is not in range [%v, %v]",
+					test.retryCount, d, test.want/2, test.want)
+			}
+		}
+	}
+}
+
+// TestMaxConcurrentRetries ensures the number of retries of permanent
+// connection requests that are dialing at once is limited.
+func TestMaxConcurrentRetries(t *testing.T) {
+	const numConns = 4
+	var mtx sync.Mutex
+	var dialing, maxDialing int
+	dialed := make(map[string]bool)
+	slowDialer := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// Fail the initial dial of every address immediately so they are
+		// all retried.
+		mtx.Lock()
+		if !dialed[addr] {
+			dialed[addr] = true
+			mtx.Unlock()
+			return nil, errors.New("network down")
+		}
+		dialing++
+		if dialing > maxDialing {
+			maxDialing = dialing
+		}
+		mtx.Unlock()
+
+		time.Sleep(2 * time.Millisecond)
+
+		mtx.Lock()
+		dialing--
+		mtx.Unlock()
+		return mockDialer(ctx, network, addr)
+	}
+
+	connected := make(chan *ConnReq)
+	cmgr, err := New(&Config{
+		RetryDuration:        time.Millisecond,
+		MaxConcurrentRetries: 1,
+		Dial:                 slowDialer,
+		OnConnection: func(c *ConnReq, conn net.Conn) {
+			connected <- c
+		},
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	ctx, shutdown, wg := runConnMgrAsync(context.Background(), cmgr)
+
+	for i := 0; i < numConns; i++ {
+		cr := &ConnReq{
+			Addr: &net.TCPAddr{
+				IP:   net.ParseIP("127.0.0.1"),
+				Port: 18555 + i,
+			},
+			Permanent: true,
+		}
+		go cmgr.Connect(ctx, cr)
+	}
+	for i := 0; i < numConns; i++ {
+		select {
+		case <-connected:
+		case <-time.After(time.Second):
+			t.Fatal("timeout waiting for retried connections")
+		}
+	}
+
+	mtx.Lock()
+	gotMaxDialing := maxDialing
+	mtx.Unlock()
+	if gotMaxDialing != 1 {
+		t.Fatalf("unexpected max concurrent retries -- got %d, want 1",
+			gotMaxDialing)
+	}
+
+	// Ensure clean shutdown of connection manager.
+	shutdown()
+	wg.Wait()
+}
+
 // TestNetworkFailure tests that the connection manager handles a network
 // failure gracefully.
 func TestNetworkFailure(t *testing.T) {
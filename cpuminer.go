@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -31,12 +32,22 @@ const (
 	// update to the hashes per second monitor.
 	hpsUpdateSecs = 10
 
-	// hashUpdateSec is the number of seconds each worker waits in between
-	// notifying the speed monitor with how many hashes have been completed
-	// while they are actively searching for a solution.  This is done to
-	// reduce the amount of syncs between the workers that must be done to
-	// keep track of the hashes per second.
-	hashUpdateSecs = 15
+	// workerUpdateInterval is the amount of time each worker waits in
+	// between notifying the speed monitor with how many hashes have been
+	// completed, checking whether mining is paused, and updating the block
+	// time while they are actively searching for a solution.  This is done
+	// to reduce the amount of syncs between the workers that must be done.
+	workerUpdateInterval = 333 * time.Millisecond
+
+	// maxNumWorkers is the maximum number of workers that may be used to
+	// solve a block.  Each worker searches a distinct range of the extra
+	// nonce space that is identified by the worker id in the most
+	// significant byte of the extra nonce.
+	maxNumWorkers = 256
+
+	// extraNonceRangeBits is the number of bits of the extra nonce that are
+	// searched by each worker.
+	extraNonceRangeBits = 56
 
 	// maxSimnetToMine is the maximum number of blocks to mine on HEAD~1
 	// for simnet so that you don't run out of memory if tickets for
@@ -46,9 +57,8 @@ const (
 
 var (
 	// defaultNumWorkers is the default number of workers to use for mining
-	// and is based on the number of processor cores.  This helps ensure the
-	// system stays reasonably responsive under heavy load.
-	defaultNumWorkers = uint32(1)
+	// and is based on the number of processor cores.
+	defaultNumWorkers = uint32(runtime.NumCPU())
 
 	// littleEndian is a convenience variable since binary.LittleEndian is
 	// quite long.
@@ -114,10 +124,13 @@ type cpuminerConfig struct {
 
 // CPUMiner provides facilities for solving blocks (mining) using the CPU in
 // a concurrency-safe manner.  It consists of two main goroutines -- a speed
-// monitor and a controller for worker goroutines which generate and solve
-// blocks.  The number of goroutines can be set via the SetMaxGoRoutines
-// function, but the default is based on the number of processor cores in the
-// system which is typically sufficient.
+// monitor and a controller which generates block templates and solves them
+// with multiple worker goroutines.  The number of workers can be set via the
+// SetNumWorkers function, but the default is based on the number of processor
+// cores in the system which is typically sufficient.
+//
+// Mining may also be paused and resumed via the Pause and Resume functions
+// which is primarily useful to control block production in test networks.
 type CPUMiner struct {
 	numWorkers uint32 // update atomically
 
@@ -134,6 +147,11 @@ type CPUMiner struct {
 	speedStats        speedStats
 	quit              chan struct{}
 
+	// resumed is closed when mining is resumed after being paused.  It is
+	// nil when mining is not paused.
+	pauseMtx sync.Mutex
+	resumed  chan struct{}
+
 	// This is a map that keeps track of how many blocks have
 	// been mined on each parent by the CPUMiner. It is only
 	// for use in simulation networks, to diminish memory
@@ -233,32 +251,46 @@ func (m *CPUMiner) submitBlock(block *dcrutil.Block) bool {
 	return true
 }
 
-// solveBlock attempts to find some combination of a nonce, extra nonce, and
-// current timestamp which makes the passed block hash to a value less than the
-// target difficulty.  The timestamp is updated periodically and the passed
-// block is modified with all tweaks during this process.  This means that
-// when the function returns true, the block is ready for submission.
-//
-// This function will return early with false when conditions that trigger a
-// stale block such as a new block showing up or periodically when there are
-// new transactions and enough time has elapsed without finding a solution.
-func (m *CPUMiner) solveBlock(ctx context.Context, msgBlock *wire.MsgBlock, stats *speedStats, ticker *time.Ticker) bool {
-	// Choose a random extra nonce offset for this block template and
-	// worker.
-	enOffset, err := wire.RandomUint64()
-	if err != nil {
-		minrLog.Errorf("Unexpected error while generating random "+
-			"extra nonce offset: %v", err)
-		enOffset = 0
+// workerExtraNonce returns the extra nonce to use for the provided worker and
+// position in the range of the extra nonce space searched by the worker.
+func workerExtraNonce(worker uint32, extraNonce uint64) uint64 {
+	const rangeMask = 1<<extraNonceRangeBits - 1
+	return uint64(worker)<<extraNonceRangeBits | extraNonce&rangeMask
+}
+
+// waitWhilePaused blocks while mining is paused.  It returns false when the
+// provided context is canceled before mining is resumed.
+func (m *CPUMiner) waitWhilePaused(ctx context.Context) bool {
+	m.pauseMtx.Lock()
+	resumed := m.resumed
+	m.pauseMtx.Unlock()
+	if resumed == nil {
+		return ctx.Err() == nil
 	}
 
-	// Create a couple of convenience variables.
-	header := &msgBlock.Header
-	targetDifficulty := standalone.CompactToBig(header.Bits)
+	select {
+	case <-resumed:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
 
-	// Initial state.
-	lastGenerated := time.Now()
-	lastTxUpdate := m.g.txSource.LastUpdated()
+// solveHeader attempts to find some combination of a nonce, extra nonce, and
+// current timestamp which makes the passed block header hash to a value less
+// than the target difficulty.  Only the range of the extra nonce space that
+// belongs to the provided worker is searched starting from the provided offset.
+// The timestamp is updated periodically and the passed header is modified with
+// all tweaks during this process.  This means that when the function returns
+// true, the header is solved.
+//
+// This function will return early with false when the provided context is
+// canceled.
+func (m *CPUMiner) solveHeader(ctx context.Context, header *wire.BlockHeader, worker uint32, enOffset uint64, stats *speedStats) bool {
+	ticker := time.NewTicker(workerUpdateInterval)
+	defer ticker.Stop()
+
+	targetDifficulty := standalone.CompactToBig(header.Bits)
 	hashesCompleted := uint64(0)
 
 	// Note that the entire extra nonce range is iterated and the offset is
@@ -268,12 +300,13 @@ func (m *CPUMiner) solveBlock(ctx context.Context, msgBlock *wire.MsgBlock, stat
 	// a solution is found.
 	for extraNonce := uint64(0); ; extraNonce++ {
 		// Update the extra nonce in the block template header with the
-		// new value.
-		littleEndian.PutUint64(header.ExtraData[:], extraNonce+enOffset)
+		// new value from the range that belongs to the worker.
+		littleEndian.PutUint64(header.ExtraData[:],
+			workerExtraNonce(worker, extraNonce+enOffset))
 
 		// Search through the entire nonce range for a solution while
-		// periodically checking for early quit and stale block
-		// conditions along with updates to the speed monitor.
+		// periodically checking for early quit along with updates to the
+		// speed monitor and block time.
 		//
 		// This loop differs from the outer one in that it does not run
 		// forever, thus allowing the extraNonce field to be updated
@@ -285,29 +318,24 @@ func (m *CPUMiner) solveBlock(ctx context.Context, msgBlock *wire.MsgBlock, stat
 		for nonce := uint32(0); ; nonce++ {
 			select {
 			case <-ctx.Done():
+				stats.AddTotalHashes(hashesCompleted)
 				return false
 
 			case <-ticker.C:
 				stats.AddTotalHashes(hashesCompleted)
 				hashesCompleted = 0
 
-				// The current block is stale if the memory pool
-				// has been updated since the block template was
-				// generated and it has been at least 3 seconds,
-				// or if it's been one minute.
-				now := time.Now()
-				if (lastTxUpdate != m.g.txSource.LastUpdated() &&
-					now.After(lastGenerated.Add(3*time.Second))) ||
-					now.After(lastGenerated.Add(60*time.Second)) {
+				if !m.waitWhilePaused(ctx) {
 					return false
 				}
 
-				err = m.g.UpdateBlockTime(header)
+				err := m.g.UpdateBlockTime(header)
 				if err != nil {
 					minrLog.Warnf("CPU miner unable to update block template "+
 						"time: %v", err)
 					return false
 				}
+				targetDifficulty = standalone.CompactToBig(header.Bits)
 
 			default:
 				// Non-blocking select to fall through
@@ -332,24 +360,108 @@ func (m *CPUMiner) solveBlock(ctx context.Context, msgBlock *wire.MsgBlock, stat
 	}
 }
 
-// generateBlocks is a worker that is controlled by the miningWorkerController.
-// It is self contained in that it creates block templates and attempts to solve
-// them while detecting when it is performing stale work and reacting
-// accordingly by generating a new block template.  When a block is solved, it
-// is submitted.
+// solveBlock attempts to solve the passed block template using the provided
+// number of worker goroutines that each search a distinct range of the extra
+// nonce space.  The passed block is updated with the solution found by the
+// first worker to solve it.  This means that when the function returns true,
+// the block is ready for submission.
 //
-// It must be run as a goroutine.
-func (m *CPUMiner) generateBlocks(ctx context.Context) {
-	minrLog.Tracef("Starting generate blocks worker")
+// This function will return early with false when conditions that trigger a
+// stale block such as a new block showing up or periodically when there are
+// new transactions and enough time has elapsed without finding a solution.  It
+// also returns early when the number of workers is updated so the new number
+// of workers can be used.
+func (m *CPUMiner) solveBlock(ctx context.Context, msgBlock *wire.MsgBlock, numWorkers uint32, stats *speedStats) bool {
+	if numWorkers == 0 {
+		numWorkers = 1
+	}
+	if numWorkers > maxNumWorkers {
+		numWorkers = maxNumWorkers
+	}
 
-	// Start a ticker which is used to signal checks for stale work and
-	// updates to the speed monitor.
-	ticker := time.NewTicker(333 * time.Millisecond)
+	// Choose a random extra nonce offset for this block template.
+	enOffset, err := wire.RandomUint64()
+	if err != nil {
+		minrLog.Errorf("Unexpected error while generating random "+
+			"extra nonce offset: %v", err)
+		enOffset = 0
+	}
+
+	// Launch the workers with their own copy of the header to solve.  The
+	// solved channel is buffered so workers never block when reporting a
+	// solution.
+	ctx, cancel := context.WithCancel(ctx)
+	solved := make(chan wire.BlockHeader, numWorkers)
+	var wg sync.WaitGroup
+	for i := uint32(0); i < numWorkers; i++ {
+		wg.Add(1)
+		go func(worker uint32, header wire.BlockHeader) {
+			defer wg.Done()
+			if m.solveHeader(ctx, &header, worker, enOffset, stats) {
+				solved <- header
+			}
+		}(i, msgBlock.Header)
+	}
+
+	// Initial state.
+	lastGenerated := time.Now()
+	lastTxUpdate := m.g.txSource.LastUpdated()
+	ticker := time.NewTicker(workerUpdateInterval)
 	defer ticker.Stop()
 
+	var solution *wire.BlockHeader
+out:
+	for {
+		select {
+		case header := <-solved:
+			solution = &header
+			break out
+
+		case <-ctx.Done():
+			break out
+
+		case <-m.updateNumWorkers:
+			break out
+
+		case <-ticker.C:
+			// Mining is not stale while it is paused.
+			if m.IsPaused() {
+				continue
+			}
+
+			// The current block is stale if the memory pool has been
+			// updated since the block template was generated and it has
+			// been at least 3 seconds, or if it's been one minute.
+			now := time.Now()
+			if (lastTxUpdate != m.g.txSource.LastUpdated() &&
+				now.After(lastGenerated.Add(3*time.Second))) ||
+				now.After(lastGenerated.Add(60*time.Second)) {
+				break out
+			}
+		}
+	}
+
+	// Stop the remaining workers and wait for them to exit.
+	cancel()
+	wg.Wait()
+	if solution == nil {
+		return false
+	}
+	msgBlock.Header = *solution
+	return true
+}
+
+// generateBlocks is controlled by the miningWorkerController.  It is self
+// contained in that it creates block templates and attempts to solve them with
+// the configured number of workers while detecting when it is performing stale
+// work and reacting accordingly by generating a new block template.  When a
+// block is solved, it is submitted.
+func (m *CPUMiner) generateBlocks(ctx context.Context) {
+	minrLog.Tracef("Starting generate blocks")
+
 	for {
-		// Quit when the miner is stopped.
-		if ctx.Err() != nil {
+		// Quit when the miner is stopped and wait while it is paused.
+		if !m.waitWhilePaused(ctx) {
 			break
 		}
 
@@ -415,7 +527,8 @@ func (m *CPUMiner) generateBlocks(ctx context.Context) {
 		// with false when conditions that trigger a stale block, so
 		// a new block template can be generated.  When the return is
 		// true a solution was found, so submit the solved block.
-		if m.solveBlock(ctx, template.Block, &m.speedStats, ticker) {
+		numWorkers := atomic.LoadUint32(&m.numWorkers)
+		if m.solveBlock(ctx, template.Block, numWorkers, &m.speedStats) {
 			block := dcrutil.NewBlock(template.Block)
 			m.submitBlock(block)
 
@@ -425,70 +538,27 @@ func (m *CPUMiner) generateBlocks(ctx context.Context) {
 		}
 	}
 
-	m.workerWg.Done()
-	minrLog.Tracef("Generate blocks worker done")
+	minrLog.Tracef("Generate blocks done")
 }
 
-// miningWorkerController launches the worker goroutines that are used to
-// generate block templates and solve them.  It also provides the ability to
-// dynamically adjust the number of running worker goroutines.
+// miningWorkerController generates block templates and solves them with the
+// configured number of worker goroutines until the miner is stopped.  Changes
+// to the number of workers take effect by abandoning the current attempt to
+// solve a block and starting a new one with the updated number of workers.
 //
 // It must be run as a goroutine.
 func (m *CPUMiner) miningWorkerController(ctx context.Context) {
-	// launchWorkers groups common code to launch a specified number of
-	// workers for generating blocks.
-	var runningWorkers []context.CancelFunc
-	launchWorkers := func(numWorkers uint32) {
-		for i := uint32(0); i < numWorkers; i++ {
-			wCtx, wCancel := context.WithCancel(ctx)
-			runningWorkers = append(runningWorkers, wCancel)
-
-			m.workerWg.Add(1)
-			go m.generateBlocks(wCtx)
-		}
-	}
-
-	// Launch the current number of workers by default.
-	numWorkers := atomic.LoadUint32(&m.numWorkers)
-	runningWorkers = make([]context.CancelFunc, 0, numWorkers)
-	launchWorkers(numWorkers)
-
-out:
-	for {
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
 		select {
-		// Update the number of running workers.
-		case <-m.updateNumWorkers:
-			numRunning := uint32(len(runningWorkers))
-			numWorkers := atomic.LoadUint32(&m.numWorkers)
-
-			// No change.
-			if numWorkers == numRunning {
-				continue
-			}
-
-			// Add new workers.
-			if numWorkers > numRunning {
-				launchWorkers(numWorkers - numRunning)
-				continue
-			}
-
-			// Signal the most recently created goroutines to exit.
-			for i := numRunning - 1; i >= numWorkers; i-- {
-				runningWorkers[i]()
-				runningWorkers[i] = nil
-				runningWorkers = runningWorkers[:i]
-			}
-
 		case <-m.quit:
-			for _, wCancel := range runningWorkers {
-				wCancel()
-			}
-			break out
+			cancel()
+		case <-ctx.Done():
 		}
-	}
+	}()
 
-	// Wait until all workers shut down.
-	m.workerWg.Wait()
+	m.generateBlocks(ctx)
+	cancel()
 	m.wg.Done()
 }
 
@@ -567,7 +637,8 @@ func (m *CPUMiner) HashesPerSecond() float64 {
 // SetNumWorkers sets the number of workers to create which solve blocks.  Any
 // negative values will cause a default number of workers to be used which is
 // based on the number of processor cores in the system.  A value of 0 will
-// cause all CPU mining to be stopped.
+// cause all CPU mining to be stopped.  The number of workers is limited to a
+// maximum of 256.
 //
 // This function is safe for concurrent access.
 func (m *CPUMiner) SetNumWorkers(numWorkers int32) {
@@ -576,17 +647,59 @@ func (m *CPUMiner) SetNumWorkers(numWorkers int32) {
 	}
 
 	// Use default if provided value is negative.
-	if numWorkers < 0 {
+	switch {
+	case numWorkers < 0:
 		atomic.StoreUint32(&m.numWorkers, defaultNumWorkers)
-	} else {
+	case numWorkers > maxNumWorkers:
+		atomic.StoreUint32(&m.numWorkers, maxNumWorkers)
+	default:
 		atomic.StoreUint32(&m.numWorkers, uint32(numWorkers))
 	}
 
-	// When the miner is already running, notify the controller about the
-	// the change.
-	if m.started {
-		m.updateNumWorkers <- struct{}{}
+	// Notify the controller about the change in case the miner is already
+	// running.  The notification is dropped when one is already pending.
+	select {
+	case m.updateNumWorkers <- struct{}{}:
+	default:
+	}
+}
+
+// Pause pauses all CPU mining, including the discrete generation of blocks via
+// GenerateNBlocks, until Resume is called.  Calling this function when mining
+// is already paused will have no effect.
+//
+// This function is safe for concurrent access.
+func (m *CPUMiner) Pause() {
+	m.pauseMtx.Lock()
+	if m.resumed == nil {
+		m.resumed = make(chan struct{})
+		minrLog.Infof("CPU miner paused")
 	}
+	m.pauseMtx.Unlock()
+}
+
+// Resume resumes CPU mining that was previously paused via Pause.  Calling this
+// function when mining is not paused will have no effect.
+//
+// This function is safe for concurrent access.
+func (m *CPUMiner) Resume() {
+	m.pauseMtx.Lock()
+	if m.resumed != nil {
+		close(m.resumed)
+		m.resumed = nil
+		minrLog.Infof("CPU miner resumed")
+	}
+	m.pauseMtx.Unlock()
+}
+
+// IsPaused returns whether or not CPU mining is currently paused.
+//
+// This function is safe for concurrent access.
+func (m *CPUMiner) IsPaused() bool {
+	m.pauseMtx.Lock()
+	defer m.pauseMtx.Unlock()
+
+	return m.resumed != nil
 }
 
 // NumWorkers returns the number of workers which are running to solve blocks.
@@ -600,8 +713,10 @@ func (m *CPUMiner) NumWorkers() int32 {
 // contained in that it creates block templates and attempts to solve them while
 // detecting when it is performing stale work and reacting accordingly by
 // generating a new block template.  When a block is solved, it is submitted.
-// The function returns a list of the hashes of generated blocks.
-func (m *CPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainhash.Hash, error) {
+// The blocks pay to the provided address or, when it is nil, to a random one of
+// the configured mining addresses.  The function returns a list of the hashes
+// of generated blocks.
+func (m *CPUMiner) GenerateNBlocks(ctx context.Context, n uint32, payToAddr dcrutil.Address) ([]*chainhash.Hash, error) {
 	// Respond with an error if server is already mining.
 	m.Lock()
 	if m.started || m.discreteMining {
@@ -619,17 +734,14 @@ func (m *CPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainhash.
 	i := uint32(0)
 	blockHashes := make([]*chainhash.Hash, n)
 
-	// Start a ticker which is used to signal checks for stale work.
-	ticker := time.NewTicker(time.Second * hashUpdateSecs)
-	defer ticker.Stop()
-
 	for {
-		// Read updateNumWorkers in case someone tries a `setgenerate` while
-		// we're generating. We can ignore it as the `generate` RPC call only
-		// uses 1 worker.
-		select {
-		case <-m.updateNumWorkers:
-		default:
+		// Wait while mining is paused.
+		if !m.waitWhilePaused(ctx) {
+			m.Lock()
+			m.started = false
+			m.discreteMining = false
+			m.Unlock()
+			return nil, ctx.Err()
 		}
 
 		// Grab the lock used for block submission, since the current block will
@@ -637,14 +749,17 @@ func (m *CPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainhash.
 		// template on a block that is in the process of becoming stale.
 		m.submitBlockLock.Lock()
 
-		// Choose a payment address at random.
-		rand.Seed(time.Now().UnixNano())
-		payToAddr := m.cfg.MiningAddrs[rand.Intn(len(m.cfg.MiningAddrs))]
+		// Choose a payment address at random when one is not provided.
+		blockPayToAddr := payToAddr
+		if blockPayToAddr == nil {
+			rand.Seed(time.Now().UnixNano())
+			blockPayToAddr = m.cfg.MiningAddrs[rand.Intn(len(m.cfg.MiningAddrs))]
+		}
 
 		// Create a new block template using the available transactions
 		// in the memory pool as a source of transactions to potentially
 		// include in the block.
-		template, err := m.g.NewBlockTemplate(payToAddr)
+		template, err := m.g.NewBlockTemplate(blockPayToAddr)
 		m.submitBlockLock.Unlock()
 		if err != nil {
 			errStr := fmt.Sprintf("Failed to create new block "+
@@ -664,7 +779,8 @@ func (m *CPUMiner) GenerateNBlocks(ctx context.Context, n uint32) ([]*chainhash.
 		// a new block template can be generated.  When the return is
 		// true a solution was found, so submit the solved block.
 		var stats speedStats
-		if m.solveBlock(ctx, template.Block, &stats, ticker) {
+		numWorkers := atomic.LoadUint32(&m.numWorkers)
+		if m.solveBlock(ctx, template.Block, numWorkers, &stats) {
 			block := dcrutil.NewBlock(template.Block)
 			m.submitBlock(block)
 			blockHashes[i] = block.Hash()
@@ -690,7 +806,7 @@ func newCPUMiner(cfg *cpuminerConfig) *CPUMiner {
 		g:                 cfg.BlockTemplateGenerator,
 		cfg:               cfg,
 		numWorkers:        defaultNumWorkers,
-		updateNumWorkers:  make(chan struct{}),
+		updateNumWorkers:  make(chan struct{}, 1),
 		queryHashesPerSec: make(chan float64),
 		minedOnParents:    make(map[chainhash.Hash]uint8),
 	}
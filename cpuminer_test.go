@@ -0,0 +1,77 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestWorkerExtraNonce ensures the extra nonces searched by each worker are
+// confined to a distinct range identified by the worker.
+func TestWorkerExtraNonce(t *testing.T) {
+	tests := []struct {
+		worker     uint32
+		extraNonce uint64
+		want       uint64
+	}{
+		{worker: 0, extraNonce: 0, want: 0},
+		{worker: 0, extraNonce: 0x00ffffffffffffff, want: 0x00ffffffffffffff},
+		{worker: 1, extraNonce: 0, want: 0x0100000000000000},
+		{worker: 1, extraNonce: 0x1234, want: 0x0100000000001234},
+		{worker: 2, extraNonce: 0xff00000000000001, want: 0x0200000000000001},
+		{worker: 255, extraNonce: ^uint64(0), want: ^uint64(0)},
+	}
+
+	for _, test := range tests {
+		got := workerExtraNonce(test.worker, test.extraNonce)
+		if got != test.want {
+			t.Errorf("worker %d, extra nonce %x: unexpected result -- got "+
+				"%x, want %x", test.worker, test.extraNonce, got, test.want)
+		}
+	}
+}
+
+// TestCPUMinerPause ensures pausing and resuming the CPU miner blocks and
+// releases waiters as expected.
+func TestCPUMinerPause(t *testing.T) {
+	m := newCPUMiner(&cpuminerConfig{})
+	if m.IsPaused() {
+		t.Fatal("new miner is paused")
+	}
+	if !m.waitWhilePaused(context.Background()) {
+		t.Fatal("wait on unpaused miner did not return true")
+	}
+
+	// Ensure waiting while paused does not return until resumed.
+	m.Pause()
+	m.Pause()
+	if !m.IsPaused() {
+		t.Fatal("miner is not paused")
+	}
+	resumed := make(chan bool)
+	go func() { resumed <- m.waitWhilePaused(context.Background()) }()
+	select {
+	case <-resumed:
+		t.Fatal("wait returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	m.Resume()
+	if !<-resumed {
+		t.Fatal("wait on resumed miner did not return true")
+	}
+	if m.IsPaused() {
+		t.Fatal("miner is paused after resume")
+	}
+
+	// Ensure waiting while paused returns false when the context is canceled.
+	m.Pause()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if m.waitWhilePaused(ctx) {
+		t.Fatal("wait with canceled context returned true")
+	}
+}
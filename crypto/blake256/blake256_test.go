@@ -9,6 +9,7 @@ import (
 	"bytes"
 	"fmt"
 	"hash"
+	"math/rand"
 	"testing"
 )
 
@@ -176,6 +177,35 @@ func TestTwoWrites(t *testing.T) {
 	}
 }
 
+// TestBlockGeneric ensures the compression function selected for the platform
+// produces the same results as the pure Go implementation for random blocks,
+// salts, and counters.
+func TestBlockGeneric(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		var d digest
+		for j := range d.h {
+			d.h[j] = rng.Uint32()
+		}
+		for j := range d.s {
+			d.s[j] = rng.Uint32()
+		}
+		d.t = rng.Uint64()
+		d.nullt = i%4 == 0
+		p := make([]byte, BlockSize*(1+rng.Intn(4)))
+		rng.Read(p)
+
+		want := d
+		blockGeneric(&want, p)
+		got := d
+		block(&got, p)
+		if got != want {
+			t.Fatalf("#%d: mismatched state -- got %+v, want %+v", i, got,
+				want)
+		}
+	}
+}
+
 var bufIn = make([]byte, 8<<10)
 var bufOut = make([]byte, 32)
 
@@ -5,7 +5,7 @@
 
 // BLAKE-256 block step.
 // In its own file so that a faster assembly or C version
-// can be substituted easily.
+// can be substituted easily.  See blake256block_amd64.go.
 
 package blake256
 
@@ -28,7 +28,9 @@ const (
 	cst15 = 0xB5470917
 )
 
-func block(d *digest, p []uint8) {
+// blockGeneric is the pure Go implementation of the compression function.  It
+// is used when there is no faster implementation for the platform.
+func blockGeneric(d *digest, p []uint8) {
 	h0, h1, h2, h3, h4, h5, h6, h7 := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
 	s0, s1, s2, s3 := d.s[0], d.s[1], d.s[2], d.s[3]
 
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build amd64,!gccgo,!appengine
+
+package blake256
+
+// useSSE41 indicates whether or not the processor supports the SSSE3 and
+// SSE4.1 instructions needed by the vectorized compression function.
+var useSSE41 = supportsSSE41()
+
+// cpuid executes the CPUID instruction with the provided leaf and subleaf and
+// returns the resulting registers.  It is implemented in assembly.
+//
+//go:noescape
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// blockSSE41 compresses all full blocks in p using SSSE3 and SSE4.1
+// instructions.  It is implemented in assembly.
+//
+//go:noescape
+func blockSSE41(h *[8]uint32, s *[4]uint32, t *uint64, nullt bool, p []byte)
+
+// supportsSSE41 returns whether or not the processor supports the SSSE3 and
+// SSE4.1 instructions.
+func supportsSSE41() bool {
+	const (
+		ssse3Bit = 1 << 9
+		sse41Bit = 1 << 19
+	)
+	maxLeaf, _, _, _ := cpuid(0, 0)
+	if maxLeaf < 1 {
+		return false
+	}
+	_, _, ecx, _ := cpuid(1, 0)
+	return ecx&ssse3Bit != 0 && ecx&sse41Bit != 0
+}
+
+// block compresses all full blocks in p using the fastest implementation
+// supported by the processor.
+func block(d *digest, p []uint8) {
+	if !useSSE41 {
+		blockGeneric(d, p)
+		return
+	}
+	blockSSE41(&d.h, &d.s, &d.t, d.nullt, p)
+}
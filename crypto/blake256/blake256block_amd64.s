@@ -0,0 +1,436 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build amd64,!gccgo,!appengine
+
+#include "textflag.h"
+
+// The compression function is vectorized by storing each row of the 4x4 state
+// matrix in a single register so the G function is applied to all four columns
+// and then all four diagonals at once.
+//
+// X0 = v0..v3, X1 = v4..v7, X2 = v8..v11, X3 = v12..v15
+// X4, X5 = message words xor constants for the current half round
+// X6 = scratch
+// X7 = rotate right by 16 shuffle mask
+// X8 = rotate right by 8 shuffle mask
+// X9 = big endian to little endian shuffle mask
+// X10 = salt
+// X11 = counter
+// X12, X13 = chain value
+
+// ROTR rotates each 32-bit word of x right by n bits using t as scratch.
+#define ROTR(x, t, n, m) \
+	MOVO  x, t;   \
+	PSRLL $n, x;  \
+	PSLLL $m, t;  \
+	POR   t, x
+
+// HALFG applies half of the G function to all four columns of the state.
+#define HALFG(msg, rotA, rotBn, rotBm) \
+	PADDL  msg, X0;  \
+	PADDL  X1, X0;   \
+	PXOR   X0, X3;   \
+	PSHUFB rotA, X3; \
+	PADDL  X3, X2;   \
+	PXOR   X2, X1;   \
+	ROTR(X1, X6, rotBn, rotBm)
+
+// G applies the G function to all four columns of the state given the message
+// words xored with the constants in m1 and m2.
+#define G(m1, m2) \
+	HALFG(m1, X7, 12, 20); \
+	HALFG(m2, X8, 7, 25)
+
+// DIAGONALIZE rotates the rows of the state so the diagonals are in columns.
+#define DIAGONALIZE \
+	PSHUFD $0x39, X1, X1; \
+	PSHUFD $0x4e, X2, X2; \
+	PSHUFD $0x93, X3, X3
+
+// UNDIAGONALIZE rotates the rows of the state back to their original
+// positions.
+#define UNDIAGONALIZE \
+	PSHUFD $0x93, X1, X1; \
+	PSHUFD $0x4e, X2, X2; \
+	PSHUFD $0x39, X3, X3
+
+// LOADMSG loads the message words at the provided indices from the message
+// buffer pointed to by SI into x and xors them with the constants at the
+// provided offset in the constant table.
+#define LOADMSG(x, i0, i1, i2, i3, off) \
+	MOVL   (i0*4)(SI), x;        \
+	PINSRD $1, (i1*4)(SI), x;    \
+	PINSRD $2, (i2*4)(SI), x;    \
+	PINSRD $3, (i3*4)(SI), x;    \
+	PXOR   ·roundConsts+off(SB), x
+
+// ROUND performs a full round using the message permutation at the provided
+// index into the constant table.
+#define ROUND(s0, s1, s2, s3, s4, s5, s6, s7, s8, s9, s10, s11, s12, s13, s14, s15, off) \
+	LOADMSG(X4, s0, s2, s4, s6, off);          \
+	LOADMSG(X5, s1, s3, s5, s7, off+16);       \
+	G(X4, X5);                                 \
+	DIAGONALIZE;                               \
+	LOADMSG(X4, s8, s10, s12, s14, off+32);    \
+	LOADMSG(X5, s9, s11, s13, s15, off+48);    \
+	G(X4, X5);                                 \
+	UNDIAGONALIZE
+
+// roundConsts houses the constants that are xored with the message words in
+// each round in the order they are loaded by the ROUND macro.
+
+DATA ·roundConsts+0x000(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x004(SB)/4, $0x03707344
+DATA ·roundConsts+0x008(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x00c(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x010(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x014(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x018(SB)/4, $0xa4093822
+DATA ·roundConsts+0x01c(SB)/4, $0x082efa98
+DATA ·roundConsts+0x020(SB)/4, $0x38d01377
+DATA ·roundConsts+0x024(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x028(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x02c(SB)/4, $0xb5470917
+DATA ·roundConsts+0x030(SB)/4, $0x452821e6
+DATA ·roundConsts+0x034(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x038(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x03c(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x040(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x044(SB)/4, $0x452821e6
+DATA ·roundConsts+0x048(SB)/4, $0xb5470917
+DATA ·roundConsts+0x04c(SB)/4, $0x082efa98
+DATA ·roundConsts+0x050(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x054(SB)/4, $0xa4093822
+DATA ·roundConsts+0x058(SB)/4, $0x38d01377
+DATA ·roundConsts+0x05c(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x060(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x064(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x068(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x06c(SB)/4, $0x03707344
+DATA ·roundConsts+0x070(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x074(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x078(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x07c(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x080(SB)/4, $0x452821e6
+DATA ·roundConsts+0x084(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x088(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x08c(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x090(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x094(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x098(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x09c(SB)/4, $0xb5470917
+DATA ·roundConsts+0x0a0(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x0a4(SB)/4, $0x082efa98
+DATA ·roundConsts+0x0a8(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x0ac(SB)/4, $0xa4093822
+DATA ·roundConsts+0x0b0(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x0b4(SB)/4, $0x03707344
+DATA ·roundConsts+0x0b8(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x0bc(SB)/4, $0x38d01377
+DATA ·roundConsts+0x0c0(SB)/4, $0x38d01377
+DATA ·roundConsts+0x0c4(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x0c8(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x0cc(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x0d0(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x0d4(SB)/4, $0x03707344
+DATA ·roundConsts+0x0d8(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x0dc(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x0e0(SB)/4, $0x082efa98
+DATA ·roundConsts+0x0e4(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x0e8(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x0ec(SB)/4, $0x452821e6
+DATA ·roundConsts+0x0f0(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x0f4(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x0f8(SB)/4, $0xa4093822
+DATA ·roundConsts+0x0fc(SB)/4, $0xb5470917
+DATA ·roundConsts+0x100(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x104(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x108(SB)/4, $0xa4093822
+DATA ·roundConsts+0x10c(SB)/4, $0xb5470917
+DATA ·roundConsts+0x110(SB)/4, $0x38d01377
+DATA ·roundConsts+0x114(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x118(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x11c(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x120(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x124(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x128(SB)/4, $0x452821e6
+DATA ·roundConsts+0x12c(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x130(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x134(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x138(SB)/4, $0x082efa98
+DATA ·roundConsts+0x13c(SB)/4, $0x03707344
+DATA ·roundConsts+0x140(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x144(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x148(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x14c(SB)/4, $0x03707344
+DATA ·roundConsts+0x150(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x154(SB)/4, $0x082efa98
+DATA ·roundConsts+0x158(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x15c(SB)/4, $0x452821e6
+DATA ·roundConsts+0x160(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x164(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x168(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x16c(SB)/4, $0x38d01377
+DATA ·roundConsts+0x170(SB)/4, $0xa4093822
+DATA ·roundConsts+0x174(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x178(SB)/4, $0xb5470917
+DATA ·roundConsts+0x17c(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x180(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x184(SB)/4, $0xb5470917
+DATA ·roundConsts+0x188(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x18c(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x190(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x194(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x198(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x19c(SB)/4, $0xa4093822
+DATA ·roundConsts+0x1a0(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x1a4(SB)/4, $0x03707344
+DATA ·roundConsts+0x1a8(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x1ac(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x1b0(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x1b4(SB)/4, $0x082efa98
+DATA ·roundConsts+0x1b8(SB)/4, $0x38d01377
+DATA ·roundConsts+0x1bc(SB)/4, $0x452821e6
+DATA ·roundConsts+0x1c0(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x1c4(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x1c8(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x1cc(SB)/4, $0x38d01377
+DATA ·roundConsts+0x1d0(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x1d4(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x1d8(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x1dc(SB)/4, $0x03707344
+DATA ·roundConsts+0x1e0(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x1e4(SB)/4, $0xa4093822
+DATA ·roundConsts+0x1e8(SB)/4, $0x082efa98
+DATA ·roundConsts+0x1ec(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x1f0(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x1f4(SB)/4, $0xb5470917
+DATA ·roundConsts+0x1f8(SB)/4, $0x452821e6
+DATA ·roundConsts+0x1fc(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x200(SB)/4, $0xb5470917
+DATA ·roundConsts+0x204(SB)/4, $0x38d01377
+DATA ·roundConsts+0x208(SB)/4, $0x03707344
+DATA ·roundConsts+0x20c(SB)/4, $0x452821e6
+DATA ·roundConsts+0x210(SB)/4, $0x082efa98
+DATA ·roundConsts+0x214(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x218(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x21c(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x220(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x224(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x228(SB)/4, $0xa4093822
+DATA ·roundConsts+0x22c(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x230(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x234(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x238(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x23c(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x240(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x244(SB)/4, $0xa4093822
+DATA ·roundConsts+0x248(SB)/4, $0x082efa98
+DATA ·roundConsts+0x24c(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x250(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x254(SB)/4, $0x452821e6
+DATA ·roundConsts+0x258(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x25c(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x260(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x264(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x268(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x26c(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x270(SB)/4, $0xb5470917
+DATA ·roundConsts+0x274(SB)/4, $0x38d01377
+DATA ·roundConsts+0x278(SB)/4, $0x03707344
+DATA ·roundConsts+0x27c(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x280(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x284(SB)/4, $0x03707344
+DATA ·roundConsts+0x288(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x28c(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x290(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x294(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x298(SB)/4, $0xa4093822
+DATA ·roundConsts+0x29c(SB)/4, $0x082efa98
+DATA ·roundConsts+0x2a0(SB)/4, $0x38d01377
+DATA ·roundConsts+0x2a4(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x2a8(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x2ac(SB)/4, $0xb5470917
+DATA ·roundConsts+0x2b0(SB)/4, $0x452821e6
+DATA ·roundConsts+0x2b4(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x2b8(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x2bc(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x2c0(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x2c4(SB)/4, $0x452821e6
+DATA ·roundConsts+0x2c8(SB)/4, $0xb5470917
+DATA ·roundConsts+0x2cc(SB)/4, $0x082efa98
+DATA ·roundConsts+0x2d0(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x2d4(SB)/4, $0xa4093822
+DATA ·roundConsts+0x2d8(SB)/4, $0x38d01377
+DATA ·roundConsts+0x2dc(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x2e0(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x2e4(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x2e8(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x2ec(SB)/4, $0x03707344
+DATA ·roundConsts+0x2f0(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x2f4(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x2f8(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x2fc(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x300(SB)/4, $0x452821e6
+DATA ·roundConsts+0x304(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x308(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x30c(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x310(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x314(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x318(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x31c(SB)/4, $0xb5470917
+DATA ·roundConsts+0x320(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x324(SB)/4, $0x082efa98
+DATA ·roundConsts+0x328(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x32c(SB)/4, $0xa4093822
+DATA ·roundConsts+0x330(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x334(SB)/4, $0x03707344
+DATA ·roundConsts+0x338(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x33c(SB)/4, $0x38d01377
+DATA ·roundConsts+0x340(SB)/4, $0x38d01377
+DATA ·roundConsts+0x344(SB)/4, $0x85a308d3
+DATA ·roundConsts+0x348(SB)/4, $0xc0ac29b7
+DATA ·roundConsts+0x34c(SB)/4, $0x3f84d5b5
+DATA ·roundConsts+0x350(SB)/4, $0xec4e6c89
+DATA ·roundConsts+0x354(SB)/4, $0x03707344
+DATA ·roundConsts+0x358(SB)/4, $0xc97c50dd
+DATA ·roundConsts+0x35c(SB)/4, $0x34e90c6c
+DATA ·roundConsts+0x360(SB)/4, $0x082efa98
+DATA ·roundConsts+0x364(SB)/4, $0xbe5466cf
+DATA ·roundConsts+0x368(SB)/4, $0x243f6a88
+DATA ·roundConsts+0x36c(SB)/4, $0x452821e6
+DATA ·roundConsts+0x370(SB)/4, $0x13198a2e
+DATA ·roundConsts+0x374(SB)/4, $0x299f31d0
+DATA ·roundConsts+0x378(SB)/4, $0xa4093822
+DATA ·roundConsts+0x37c(SB)/4, $0xb5470917
+GLOBL ·roundConsts(SB), RODATA|NOPTR, $896
+
+// rotr16Mask is a shuffle mask that rotates each 32-bit word right by 16 bits.
+DATA ·rotr16Mask+0x00(SB)/8, $0x0504070601000302
+DATA ·rotr16Mask+0x08(SB)/8, $0x0d0c0f0e09080b0a
+GLOBL ·rotr16Mask(SB), RODATA|NOPTR, $16
+
+// rotr8Mask is a shuffle mask that rotates each 32-bit word right by 8 bits.
+DATA ·rotr8Mask+0x00(SB)/8, $0x0407060500030201
+DATA ·rotr8Mask+0x08(SB)/8, $0x0c0f0e0d080b0a09
+GLOBL ·rotr8Mask(SB), RODATA|NOPTR, $16
+
+// bswapMask is a shuffle mask that reverses the bytes of each 32-bit word.
+DATA ·bswapMask+0x00(SB)/8, $0x0405060700010203
+DATA ·bswapMask+0x08(SB)/8, $0x0c0d0e0f08090a0b
+GLOBL ·bswapMask(SB), RODATA|NOPTR, $16
+
+// initConsts houses the constants used to initialize the last two rows of the
+// state.
+DATA ·initConsts+0x00(SB)/4, $0x243f6a88
+DATA ·initConsts+0x04(SB)/4, $0x85a308d3
+DATA ·initConsts+0x08(SB)/4, $0x13198a2e
+DATA ·initConsts+0x0c(SB)/4, $0x03707344
+DATA ·initConsts+0x10(SB)/4, $0xa4093822
+DATA ·initConsts+0x14(SB)/4, $0x299f31d0
+DATA ·initConsts+0x18(SB)/4, $0x082efa98
+DATA ·initConsts+0x1c(SB)/4, $0xec4e6c89
+GLOBL ·initConsts(SB), RODATA|NOPTR, $32
+
+// func blockSSE41(h *[8]uint32, s *[4]uint32, t *uint64, nullt bool, p []byte)
+TEXT ·blockSSE41(SB), NOSPLIT, $64-56
+	MOVQ h+0(FP), AX
+	MOVQ s+8(FP), BX
+	MOVQ t+16(FP), CX
+	MOVBQZX nullt+24(FP), DX
+	MOVQ p_base+32(FP), DI
+	MOVQ p_len+40(FP), R8
+	SHRQ $6, R8
+	JZ   done
+
+	MOVOU ·rotr16Mask(SB), X7
+	MOVOU ·rotr8Mask(SB), X8
+	MOVOU ·bswapMask(SB), X9
+	MOVOU (BX), X10
+	MOVOU (AX), X12
+	MOVOU 16(AX), X13
+
+	// The message words are converted to little endian and stored on the
+	// stack so they can be gathered in the order specified by the round
+	// permutations.
+	MOVQ SP, SI
+
+loop:
+	// Update the counter and zero it for the final block when requested.
+	MOVQ  (CX), R9
+	ADDQ  $512, R9
+	MOVQ  R9, (CX)
+	PXOR  X11, X11
+	TESTQ DX, DX
+	JNZ   nocounter
+	MOVQ  R9, X11
+	PSHUFD $0x50, X11, X11
+
+nocounter:
+	MOVOU 0(DI), X4
+	PSHUFB X9, X4
+	MOVOU X4, 0(SI)
+	MOVOU 16(DI), X4
+	PSHUFB X9, X4
+	MOVOU X4, 16(SI)
+	MOVOU 32(DI), X4
+	PSHUFB X9, X4
+	MOVOU X4, 32(SI)
+	MOVOU 48(DI), X4
+	PSHUFB X9, X4
+	MOVOU X4, 48(SI)
+
+	// Initialize the state from the chain value, salt, counter, and
+	// constants.
+	MOVO  X12, X0
+	MOVO  X13, X1
+	MOVOU ·initConsts(SB), X2
+	PXOR  X10, X2
+	MOVOU ·initConsts+16(SB), X3
+	PXOR  X11, X3
+
+	ROUND(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 0x000)
+	ROUND(14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3, 0x040)
+	ROUND(11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4, 0x080)
+	ROUND(7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8, 0x0c0)
+	ROUND(9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13, 0x100)
+	ROUND(2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9, 0x140)
+	ROUND(12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11, 0x180)
+	ROUND(13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10, 0x1c0)
+	ROUND(6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5, 0x200)
+	ROUND(10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0, 0x240)
+	ROUND(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 0x280)
+	ROUND(14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3, 0x2c0)
+	ROUND(11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4, 0x300)
+	ROUND(7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8, 0x340)
+
+	// Finalize the chain value.
+	PXOR X2, X0
+	PXOR X10, X0
+	PXOR X0, X12
+	PXOR X3, X1
+	PXOR X10, X1
+	PXOR X1, X13
+
+	ADDQ $64, DI
+	DECQ R8
+	JNZ  loop
+
+	MOVOU X12, (AX)
+	MOVOU X13, 16(AX)
+
+done:
+	RET
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
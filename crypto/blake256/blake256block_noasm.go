@@ -0,0 +1,13 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// +build !amd64 gccgo appengine
+
+package blake256
+
+// block compresses all full blocks in p.  There is no faster implementation
+// for this platform, so it uses the pure Go implementation.
+func block(d *digest, p []uint8) {
+	blockGeneric(d, p)
+}
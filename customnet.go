@@ -0,0 +1,268 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+	flags "github.com/jessevdk/go-flags"
+)
+
+const (
+	// maxCustomNetTicketsPerBlock is the maximum number of votes per block a
+	// custom network may require.  It ensures the maximum number of new
+	// tickets per block, which is four times the number of votes, fits in
+	// the fresh stake field of the block header.
+	maxCustomNetTicketsPerBlock = 63
+
+	// maxCustomNetTargetTimePerBlock is the maximum target time between
+	// blocks a custom network may use.
+	maxCustomNetTargetTimePerBlock = time.Hour
+)
+
+// customNetNameRegexp defines the characters that are allowed in the name of a
+// custom network.  The name is used as the name of the directory the data and
+// logs for the network are stored in, so it is intentionally restrictive.
+var customNetNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// customNetConfig defines the settings that may be provided in a network
+// parameters file to define a custom private network.  Settings that are not
+// provided, or are set to zero, keep the value of the base network.
+type customNetConfig struct {
+	Base        string `long:"base" description:"The network the custom network is derived from {regnet, simnet}"`
+	Name        string `long:"name" description:"The unique name of the network which is also used to namespace the data and log directories"`
+	Net         string `long:"net" description:"The magic bytes that identify the network as a 32-bit integer (eg. 0xa1b2c3d4)"`
+	Port        string `long:"port" description:"The default port for peer-to-peer connections"`
+	RPCPort     string `long:"rpcport" description:"The default port for RPC connections"`
+	GRPCPort    string `long:"grpcport" description:"The default port for gRPC connections"`
+	MetricsPort string `long:"metricsport" description:"The default port for the metrics server"`
+
+	GenesisTimestamp int64 `long:"genesistimestamp" description:"The timestamp of the genesis block in seconds since the Unix epoch"`
+
+	TargetTimePerBlock       time.Duration `long:"targettimeperblock" description:"The target time between blocks.  Valid time units are {s, m, h}"`
+	BaseSubsidy              int64         `long:"basesubsidy" description:"The starting block subsidy in atoms"`
+	MulSubsidy               int64         `long:"mulsubsidy" description:"The multiplier applied to the subsidy at each reduction interval"`
+	DivSubsidy               int64         `long:"divsubsidy" description:"The divisor applied to the subsidy at each reduction interval"`
+	SubsidyReductionInterval int64         `long:"subsidyreductioninterval" description:"The number of blocks between subsidy reductions"`
+	WorkRewardProportion     uint16        `long:"workrewardproportion" description:"The comparative proportion of the subsidy paid to proof-of-work miners"`
+	StakeRewardProportion    uint16        `long:"stakerewardproportion" description:"The comparative proportion of the subsidy paid to voters"`
+	BlockTaxProportion       uint16        `long:"blocktaxproportion" description:"The comparative proportion of the subsidy paid to the treasury"`
+
+	MinimumStakeDiff      int64  `long:"minimumstakediff" description:"The minimum ticket price in atoms"`
+	TicketPoolSize        uint16 `long:"ticketpoolsize" description:"The target size of the ticket pool in blocks"`
+	TicketsPerBlock       uint16 `long:"ticketsperblock" description:"The number of votes per block"`
+	TicketMaturity        uint16 `long:"ticketmaturity" description:"The number of blocks before purchased tickets are eligible to vote"`
+	TicketExpiry          uint32 `long:"ticketexpiry" description:"The number of blocks after maturity before unvoted tickets expire"`
+	CoinbaseMaturity      uint16 `long:"coinbasematurity" description:"The number of blocks before coinbase outputs may be spent"`
+	StakeEnabledHeight    int64  `long:"stakeenabledheight" description:"The height at which tickets may first be purchased"`
+	StakeValidationHeight int64  `long:"stakevalidationheight" description:"The height at which votes are first required"`
+}
+
+// isValidPort returns whether or not the provided string is a valid non-zero
+// port number.
+func isValidPort(port string) bool {
+	p, err := strconv.ParseUint(port, 10, 16)
+	return err == nil && p != 0
+}
+
+// customNetParams returns the parameters for the custom network defined by the
+// provided settings.  The settings are validated to ensure the network is
+// internally consistent and does not conflict with any of the standard
+// networks.
+func customNetParams(c *customNetConfig) (*params, error) {
+	// Start with a fresh copy of the parameters of the base network so they
+	// may be modified without affecting the standard networks.
+	var p params
+	switch c.Base {
+	case "regnet":
+		p = regNetParams
+		p.Params = chaincfg.RegNetParams()
+	case "simnet":
+		p = simNetParams
+		p.Params = chaincfg.SimNetParams()
+	default:
+		return nil, fmt.Errorf("base network must be regnet or simnet, got %q",
+			c.Base)
+	}
+
+	// Ensure the network has a unique name and magic bytes.
+	standardNets := []*params{&mainNetParams, &testNet3Params, &simNetParams,
+		&regNetParams}
+	if !customNetNameRegexp.MatchString(c.Name) {
+		return nil, fmt.Errorf("name %q must be 1 to 32 lowercase letters, "+
+			"digits, underscores, or hyphens", c.Name)
+	}
+	net, err := strconv.ParseUint(c.Net, 0, 32)
+	if err != nil || net == 0 {
+		return nil, fmt.Errorf("net %q must be a non-zero 32-bit integer",
+			c.Net)
+	}
+	for _, standard := range standardNets {
+		if c.Name == standard.Name {
+			return nil, fmt.Errorf("name %q conflicts with a standard "+
+				"network", c.Name)
+		}
+		if wire.CurrencyNet(net) == standard.Net {
+			return nil, fmt.Errorf("net %#08x conflicts with the %s "+
+				"network", net, standard.Name)
+		}
+	}
+	p.Name = c.Name
+	p.Net = wire.CurrencyNet(net)
+	p.DNSSeeds = nil
+
+	// Override the default ports.
+	ports := []struct {
+		name  string
+		value string
+		dest  *string
+	}{
+		{"port", c.Port, &p.DefaultPort},
+		{"rpcport", c.RPCPort, &p.rpcPort},
+		{"grpcport", c.GRPCPort, &p.grpcPort},
+		{"metricsport", c.MetricsPort, &p.metricsPort},
+	}
+	for _, port := range ports {
+		if port.value == "" {
+			continue
+		}
+		if !isValidPort(port.value) {
+			return nil, fmt.Errorf("%s %q is not a valid port", port.name,
+				port.value)
+		}
+		*port.dest = port.value
+	}
+
+	// Give the network a distinct genesis block when requested.
+	if c.GenesisTimestamp < 0 {
+		return nil, fmt.Errorf("genesistimestamp %d must not be negative",
+			c.GenesisTimestamp)
+	}
+	if c.GenesisTimestamp != 0 {
+		p.GenesisBlock.Header.Timestamp = time.Unix(c.GenesisTimestamp, 0)
+		p.GenesisHash = p.GenesisBlock.BlockHash()
+	}
+
+	// Override the block time and subsidy parameters.
+	if c.TargetTimePerBlock != 0 {
+		if c.TargetTimePerBlock < time.Second ||
+			c.TargetTimePerBlock > maxCustomNetTargetTimePerBlock {
+
+			return nil, fmt.Errorf("targettimeperblock %v must be between "+
+				"1s and %v", c.TargetTimePerBlock,
+				maxCustomNetTargetTimePerBlock)
+		}
+		p.TargetTimePerBlock = c.TargetTimePerBlock
+		p.TargetTimespan = c.TargetTimePerBlock *
+			time.Duration(p.WorkDiffWindowSize)
+	}
+	if c.BaseSubsidy < 0 || c.BaseSubsidy > dcrutil.MaxAmount {
+		return nil, fmt.Errorf("basesubsidy %d must be between 1 and %d",
+			c.BaseSubsidy, int64(dcrutil.MaxAmount))
+	}
+	if c.BaseSubsidy != 0 {
+		p.BaseSubsidy = c.BaseSubsidy
+	}
+	if c.MulSubsidy != 0 {
+		p.MulSubsidy = c.MulSubsidy
+	}
+	if c.DivSubsidy != 0 {
+		p.DivSubsidy = c.DivSubsidy
+	}
+	if p.MulSubsidy <= 0 || p.DivSubsidy <= 0 || p.MulSubsidy > p.DivSubsidy {
+		return nil, fmt.Errorf("mulsubsidy %d and divsubsidy %d must be "+
+			"positive and mulsubsidy must not exceed divsubsidy",
+			p.MulSubsidy, p.DivSubsidy)
+	}
+	if c.SubsidyReductionInterval < 0 {
+		return nil, fmt.Errorf("subsidyreductioninterval %d must be "+
+			"positive", c.SubsidyReductionInterval)
+	}
+	if c.SubsidyReductionInterval != 0 {
+		p.SubsidyReductionInterval = c.SubsidyReductionInterval
+	}
+	if c.WorkRewardProportion != 0 || c.StakeRewardProportion != 0 ||
+		c.BlockTaxProportion != 0 {
+
+		// The proportions are only meaningful relative to one another,
+		// so they are always overridden together.
+		p.WorkRewardProportion = c.WorkRewardProportion
+		p.StakeRewardProportion = c.StakeRewardProportion
+		p.BlockTaxProportion = c.BlockTaxProportion
+	}
+
+	// Override the stake parameters.
+	if c.MinimumStakeDiff < 0 || c.MinimumStakeDiff > dcrutil.MaxAmount {
+		return nil, fmt.Errorf("minimumstakediff %d must be between 1 and "+
+			"%d", c.MinimumStakeDiff, int64(dcrutil.MaxAmount))
+	}
+	if c.MinimumStakeDiff != 0 {
+		p.MinimumStakeDiff = c.MinimumStakeDiff
+	}
+	if c.TicketPoolSize != 0 {
+		p.TicketPoolSize = c.TicketPoolSize
+	}
+	if c.TicketsPerBlock != 0 {
+		if c.TicketsPerBlock > maxCustomNetTicketsPerBlock {
+			return nil, fmt.Errorf("ticketsperblock %d must not exceed %d",
+				c.TicketsPerBlock, maxCustomNetTicketsPerBlock)
+		}
+		p.TicketsPerBlock = c.TicketsPerBlock
+		p.MaxFreshStakePerBlock = uint8(4 * c.TicketsPerBlock)
+		p.RuleChangeActivationQuorum = p.RuleChangeActivationInterval *
+			uint32(c.TicketsPerBlock) / 10
+	}
+	if c.TicketMaturity != 0 {
+		p.TicketMaturity = c.TicketMaturity
+	}
+	if c.TicketExpiry != 0 {
+		p.TicketExpiry = c.TicketExpiry
+	}
+	if p.TicketExpiry < uint32(p.TicketPoolSize) {
+		return nil, fmt.Errorf("ticketexpiry %d must not be less than "+
+			"ticketpoolsize %d", p.TicketExpiry, p.TicketPoolSize)
+	}
+	if c.CoinbaseMaturity != 0 {
+		p.CoinbaseMaturity = c.CoinbaseMaturity
+	}
+	if c.StakeEnabledHeight != 0 {
+		p.StakeEnabledHeight = c.StakeEnabledHeight
+	}
+	if c.StakeValidationHeight != 0 {
+		p.StakeValidationHeight = c.StakeValidationHeight
+	}
+	minStakeEnabledHeight := int64(p.CoinbaseMaturity) +
+		int64(p.TicketMaturity)
+	if p.StakeEnabledHeight < minStakeEnabledHeight {
+		return nil, fmt.Errorf("stakeenabledheight %d must be at least "+
+			"coinbasematurity + ticketmaturity (%d)", p.StakeEnabledHeight,
+			minStakeEnabledHeight)
+	}
+	if p.StakeValidationHeight <= p.StakeEnabledHeight {
+		return nil, fmt.Errorf("stakevalidationheight %d must be greater "+
+			"than stakeenabledheight %d", p.StakeValidationHeight,
+			p.StakeEnabledHeight)
+	}
+
+	return &p, nil
+}
+
+// loadCustomNetParams parses the network parameters file at the provided path
+// and returns the parameters for the custom network it defines.
+func loadCustomNetParams(path string) (*params, error) {
+	var c customNetConfig
+	parser := flags.NewParser(&c, flags.None)
+	err := flags.NewIniParser(parser).ParseFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return customNetParams(&c)
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestLoadCustomNetParams ensures a network parameters file is parsed into the
+// expected custom network parameters without modifying the base network.
+func TestLoadCustomNetParams(t *testing.T) {
+	dir, err := ioutil.TempDir("", "customnettest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	const netParamsFile = `
+base=regnet
+name=privnet
+net=0xa1b2c3d4
+port=28655
+rpcport=28656
+genesistimestamp=1577836800
+targettimeperblock=30s
+basesubsidy=100000000
+ticketsperblock=3
+coinbasematurity=20
+ticketmaturity=20
+stakeenabledheight=40
+stakevalidationheight=200
+`
+	path := filepath.Join(dir, "netparams.conf")
+	if err := ioutil.WriteFile(path, []byte(netParamsFile), 0600); err != nil {
+		t.Fatalf("unable to write params file: %v", err)
+	}
+
+	p, err := loadCustomNetParams(path)
+	if err != nil {
+		t.Fatalf("loadCustomNetParams: unexpected error: %v", err)
+	}
+	if p.Name != "privnet" || p.Net != wire.CurrencyNet(0xa1b2c3d4) {
+		t.Fatalf("unexpected network identity: %s %v", p.Name, p.Net)
+	}
+	if p.DefaultPort != "28655" || p.rpcPort != "28656" ||
+		p.grpcPort != regNetParams.grpcPort {
+
+		t.Fatalf("unexpected ports: %s %s %s", p.DefaultPort, p.rpcPort,
+			p.grpcPort)
+	}
+	if p.GenesisBlock.Header.Timestamp.Unix() != 1577836800 ||
+		p.GenesisHash != p.GenesisBlock.BlockHash() ||
+		p.GenesisHash == regNetParams.GenesisHash {
+
+		t.Fatalf("unexpected genesis block %v", p.GenesisHash)
+	}
+	if p.TargetTimePerBlock != 30*time.Second ||
+		p.TargetTimespan != 30*time.Second*time.Duration(p.WorkDiffWindowSize) {
+
+		t.Fatalf("unexpected block time %v (timespan %v)",
+			p.TargetTimePerBlock, p.TargetTimespan)
+	}
+	if p.BaseSubsidy != 100000000 || p.MulSubsidy != regNetParams.MulSubsidy {
+		t.Fatalf("unexpected subsidy %d * %d", p.BaseSubsidy, p.MulSubsidy)
+	}
+	if p.TicketsPerBlock != 3 || p.MaxFreshStakePerBlock != 12 ||
+		p.StakeValidationHeight != 200 {
+
+		t.Fatalf("unexpected stake params: %d %d %d", p.TicketsPerBlock,
+			p.MaxFreshStakePerBlock, p.StakeValidationHeight)
+	}
+
+	// Ensure the base network parameters were not modified.
+	if regNetParams.Name != "regnet" || regNetParams.TicketsPerBlock != 5 ||
+		regNetParams.GenesisHash != regNetParams.GenesisBlock.BlockHash() {
+
+		t.Fatal("base network parameters were modified")
+	}
+
+	// Ensure unknown settings are rejected.
+	err = ioutil.WriteFile(path, []byte(netParamsFile+"bogus=1\n"), 0600)
+	if err != nil {
+		t.Fatalf("unable to write params file: %v", err)
+	}
+	if _, err := loadCustomNetParams(path); err == nil {
+		t.Fatal("loadCustomNetParams: did not receive expected error for " +
+			"unknown setting")
+	}
+}
+
+// TestCustomNetParamsInvalid ensures custom network settings that conflict
+// with the standard networks or are outside of the safe bounds are rejected.
+func TestCustomNetParamsInvalid(t *testing.T) {
+	valid := func() customNetConfig {
+		return customNetConfig{Base: "simnet", Name: "privnet", Net: "12345"}
+	}
+	c := valid()
+	if _, err := customNetParams(&c); err != nil {
+		t.Fatalf("customNetParams: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *customNetConfig)
+	}{
+		{"mainnet base", func(c *customNetConfig) { c.Base = "mainnet" }},
+		{"missing name", func(c *customNetConfig) { c.Name = "" }},
+		{"name with path", func(c *customNetConfig) { c.Name = "../x" }},
+		{"standard name", func(c *customNetConfig) { c.Name = "testnet3" }},
+		{"missing net", func(c *customNetConfig) { c.Net = "" }},
+		{"standard net", func(c *customNetConfig) {
+			c.Net = "0xd9b400f9" // mainnet
+		}},
+		{"invalid port", func(c *customNetConfig) { c.RPCPort = "65536" }},
+		{"negative genesis time", func(c *customNetConfig) {
+			c.GenesisTimestamp = -1
+		}},
+		{"block time too short", func(c *customNetConfig) {
+			c.TargetTimePerBlock = time.Millisecond
+		}},
+		{"subsidy increases", func(c *customNetConfig) {
+			c.MulSubsidy = 102
+		}},
+		{"too many votes", func(c *customNetConfig) {
+			c.TicketsPerBlock = maxCustomNetTicketsPerBlock + 1
+		}},
+		{"expiry before pool size", func(c *customNetConfig) {
+			c.TicketExpiry = 1
+		}},
+		{"stake enabled too early", func(c *customNetConfig) {
+			c.StakeEnabledHeight = 1
+		}},
+		{"validation before enabled", func(c *customNetConfig) {
+			c.StakeValidationHeight = 32
+		}},
+	}
+
+	for _, test := range tests {
+		c := valid()
+		test.modify(&c)
+		if _, err := customNetParams(&c); err == nil {
+			t.Errorf("%s: did not receive expected error", test.name)
+		}
+	}
+}
@@ -26,11 +26,16 @@ var (
 	shutdownChannel = make(chan error)
 )
 
+// blockDbPath returns the path to the block database given a database type.
+func blockDbPath(dbType string) string {
+	// The database name is based on the database type.
+	dbName := blockDbNamePrefix + "_" + dbType
+	return filepath.Join(cfg.DataDir, dbName)
+}
+
 // loadBlockDB opens the block database and returns a handle to it.
 func loadBlockDB() (database.DB, error) {
-	// The database name is based on the database type.
-	dbName := blockDbNamePrefix + "_" + cfg.DbType
-	dbPath := filepath.Join(cfg.DataDir, dbName)
+	dbPath := blockDbPath(cfg.DbType)
 
 	log.Infof("Loading block database from '%s'", dbPath)
 	db, err := database.Open(cfg.DbType, dbPath, activeNetParams.Net)
@@ -90,6 +95,10 @@ func realMain() error {
 	parser.AddCommand("fetchblockregion",
 		"Fetch the specified block region from the database", "",
 		&blockRegionCfg)
+	parser.AddCommand("migrate",
+		"Migrate the block database to a different database backend",
+		"Migrate the block database to a different database backend.  "+
+			"The existing database is not modified.", &migrateCfg)
 
 	// Parse command line and invoke the Execute function for the specified
 	// command.
@@ -0,0 +1,68 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/database/v2/ffldb"
+)
+
+// migrateCmd defines the configuration options for the migrate command.
+type migrateCmd struct {
+	ToDbType string `long:"todbtype" description:"Database backend to migrate the block database to"`
+}
+
+var (
+	// migrateCfg defines the configuration options for the command.
+	migrateCfg = migrateCmd{
+		ToDbType: "ffbolt",
+	}
+)
+
+// Execute is the main entry point for the command.  It's invoked by the parser.
+func (cmd *migrateCmd) Execute(args []string) error {
+	// Setup the global config options and ensure they are valid.
+	if err := setupGlobalConfig(); err != nil {
+		return err
+	}
+
+	// Validate the destination database type.
+	if !validDbType(cmd.ToDbType) {
+		str := "the specified database type to migrate to [%v] is " +
+			"invalid -- supported types %v"
+		return fmt.Errorf(str, cmd.ToDbType, knownDbTypes)
+	}
+	if cmd.ToDbType == cfg.DbType {
+		return fmt.Errorf("the database is already of type %v",
+			cfg.DbType)
+	}
+
+	// Stop the migration and remove the partially migrated database on
+	// Ctrl+C.
+	interrupt := make(chan struct{})
+	addInterruptHandler(func() {
+		log.Infof("Stopping the migration...")
+		close(interrupt)
+	})
+
+	// Perform the migration.  It is performed directly rather than
+	// waiting on the shutdown channel since the interrupt handler only
+	// stops the migration, which then removes the partially migrated
+	// database before returning.
+	srcPath := blockDbPath(cfg.DbType)
+	dstPath := blockDbPath(cmd.ToDbType)
+	err := ffldb.Migrate(srcPath, cfg.DbType, dstPath, cmd.ToDbType,
+		activeNetParams.Net, interrupt)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Migrated the block database to %q.  Run dcrd with "+
+		"--dbtype=%s to use it.  The original database at %q may be "+
+		"removed once the migrated one is confirmed to work.", dstPath,
+		cmd.ToDbType, srcPath)
+	return nil
+}
@@ -0,0 +1,549 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/database/v2"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	// boltMetadataDbName is the name used for the bbolt metadata database.
+	boltMetadataDbName = "metadata.bolt"
+
+	// boltOpenTimeout is the maximum amount of time to wait to obtain the
+	// file lock on the bbolt metadata database.
+	boltOpenTimeout = 5 * time.Second
+
+	// boltCompactSuffix is the suffix appended to the path of the bbolt
+	// metadata database to form the path of the temporary file used while
+	// compacting it.
+	boltCompactSuffix = ".compact"
+
+	// boltCompactBatchSize is the approximate number of bytes of metadata
+	// that are written to the compacted database at a time.
+	boltCompactBatchSize = 64 * 1024 * 1024 // 64 MB
+)
+
+// boltMetadataBucket is the name of the single bbolt bucket that houses all of
+// the metadata keys.  The database provides its own nested bucket semantics on
+// top of a flat key space via key prefixes, so there is no need to map them to
+// bbolt buckets.
+var boltMetadataBucket = []byte("metadata")
+
+// boltMetadataPath returns the path to the bbolt metadata database within the
+// given database path.
+func boltMetadataPath(dbPath string) string {
+	return filepath.Join(dbPath, boltMetadataDbName)
+}
+
+// boltIter wraps a bbolt cursor to provide the additional functionality needed
+// to satisfy the leveldb iterator.Iterator interface.
+type boltIter struct {
+	cursor   *bolt.Cursor
+	start    []byte
+	limit    []byte
+	key      []byte
+	value    []byte
+	isNew    bool
+	released bool
+}
+
+// Enforce boltIter implements the leveldb iterator.Iterator interface.
+var _ iterator.Iterator = (*boltIter)(nil)
+
+// setPosition sets the current key/value pair of the iterator to the provided
+// one from the underlying cursor while respecting the range the iterator is
+// limited to.  It returns whether or not the iterator is valid.
+func (iter *boltIter) setPosition(key, value []byte) bool {
+	if key == nil || (iter.start != nil && bytes.Compare(key, iter.start) < 0) ||
+		(iter.limit != nil && bytes.Compare(key, iter.limit) >= 0) {
+
+		iter.key, iter.value = nil, nil
+		return false
+	}
+	iter.key, iter.value = key, value
+	return true
+}
+
+// First positions the iterator at the first key/value pair and returns whether
+// or not the pair exists.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) First() bool {
+	if iter.released {
+		return false
+	}
+	iter.isNew = false
+	if iter.start != nil {
+		return iter.setPosition(iter.cursor.Seek(iter.start))
+	}
+	return iter.setPosition(iter.cursor.First())
+}
+
+// Last positions the iterator at the last key/value pair and returns whether or
+// not the pair exists.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Last() bool {
+	if iter.released {
+		return false
+	}
+	iter.isNew = false
+	if iter.limit != nil {
+		// The last pair in range is the one before the limit key or the
+		// final pair when there is nothing at or after the limit.
+		if key, _ := iter.cursor.Seek(iter.limit); key != nil {
+			return iter.setPosition(iter.cursor.Prev())
+		}
+	}
+	return iter.setPosition(iter.cursor.Last())
+}
+
+// Next moves the iterator one key/value pair forward and returns whether or not
+// the pair exists.  When invoked on a newly created iterator it will position
+// the iterator at the first pair.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Next() bool {
+	if iter.isNew {
+		return iter.First()
+	}
+	if iter.key == nil {
+		return false
+	}
+	return iter.setPosition(iter.cursor.Next())
+}
+
+// Prev moves the iterator one key/value pair backward and returns whether or
+// not the pair exists.  When invoked on a newly created iterator it will
+// position the iterator at the last pair.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Prev() bool {
+	if iter.isNew {
+		return iter.Last()
+	}
+	if iter.key == nil {
+		return false
+	}
+	return iter.setPosition(iter.cursor.Prev())
+}
+
+// Seek positions the iterator at the first key/value pair that is greater than
+// or equal to the passed seek key.  Returns false if no suitable key was found.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Seek(key []byte) bool {
+	if iter.released {
+		return false
+	}
+	iter.isNew = false
+	if iter.start != nil && bytes.Compare(key, iter.start) < 0 {
+		key = iter.start
+	}
+	return iter.setPosition(iter.cursor.Seek(key))
+}
+
+// Valid indicates whether the iterator is positioned at a valid key/value pair.
+// It will be considered invalid when the iterator is newly created or exhausted.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Valid() bool {
+	return iter.key != nil
+}
+
+// Key returns the current key the iterator is pointing to or nil when the
+// iterator is not valid.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Key() []byte {
+	return iter.key
+}
+
+// Value returns the current value the iterator is pointing to or nil when the
+// iterator is not valid.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Value() []byte {
+	return iter.value
+}
+
+// Error is only provided to satisfy the iterator interface as bbolt cursors do
+// not return errors.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Error() error {
+	return nil
+}
+
+// SetReleaser is only provided to satisfy the iterator interface as there is no
+// need to override it.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) SetReleaser(releaser util.Releaser) {
+}
+
+// Release marks the iterator released so it no longer accesses the underlying
+// cursor.  The cursor itself is released along with the snapshot.
+//
+// This is part of the leveldb iterator.Iterator interface implementation.
+func (iter *boltIter) Release() {
+	iter.released = true
+	iter.key, iter.value = nil, nil
+}
+
+// boltSnapshot houses a read-only bbolt transaction and implements the
+// metadataSnapshot interface.
+type boltSnapshot struct {
+	tx     *bolt.Tx
+	bucket *bolt.Bucket
+}
+
+// Enforce boltSnapshot implements the metadataSnapshot interface.
+var _ metadataSnapshot = (*boltSnapshot)(nil)
+
+// Has returns whether or not the passed key exists.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *boltSnapshot) Has(key []byte) bool {
+	return s.bucket.Get(key) != nil
+}
+
+// Get returns a copy of the value for the passed key.  It returns nil when the
+// key does not exist.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *boltSnapshot) Get(key []byte) []byte {
+	// The value is copied since bbolt values are only valid for the life of
+	// the transaction.
+	value := s.bucket.Get(key)
+	if value == nil {
+		return nil
+	}
+	return copySlice(value)
+}
+
+// NewIterator returns a new iterator over the key/value pairs in the snapshot
+// limited to the provided range.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *boltSnapshot) NewIterator(slice *util.Range) iterator.Iterator {
+	iter := &boltIter{cursor: s.bucket.Cursor(), isNew: true}
+	if slice != nil {
+		iter.start, iter.limit = slice.Start, slice.Limit
+	}
+	return iter
+}
+
+// Release releases the snapshot by rolling back the underlying read-only
+// transaction.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *boltSnapshot) Release() {
+	_ = s.tx.Rollback()
+}
+
+// boltStore houses the metadata in a bbolt database and implements the
+// metadataStore interface.
+//
+// Unlike leveldb, bbolt is a copy-on-write B+tree that serves readers from a
+// memory-mapped file without locking and never performs background
+// compactions, which provides more consistent read throughput under concurrent
+// access.
+type boltStore struct {
+	path string
+
+	// mtx protects the underlying database which is replaced when the
+	// store is compacted.
+	mtx sync.RWMutex
+	db  *bolt.DB
+}
+
+// Enforce boltStore implements the metadataStore interface.
+var _ metadataStore = (*boltStore)(nil)
+
+// Snapshot returns a snapshot of the bbolt database at the current point in
+// time.
+//
+// This is part of the metadataStore interface implementation.
+func (s *boltStore) Snapshot() (metadataSnapshot, error) {
+	s.mtx.RLock()
+	tx, err := s.db.Begin(false)
+	s.mtx.RUnlock()
+	if err != nil {
+		str := "failed to open transaction"
+		return nil, convertErr(str, err)
+	}
+	bucket := tx.Bucket(boltMetadataBucket)
+	if bucket == nil {
+		_ = tx.Rollback()
+		str := "metadata bucket does not exist"
+		return nil, makeDbErr(database.ErrCorruption, str, nil)
+	}
+	return &boltSnapshot{tx: tx, bucket: bucket}, nil
+}
+
+// Write atomically stores all of the passed key/value pairs and removes all of
+// the passed keys using a bbolt read-write transaction.
+//
+// This is part of the metadataStore interface implementation.
+func (s *boltStore) Write(puts, removes TreapForEacher) error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltMetadataBucket)
+		if bucket == nil {
+			str := "metadata bucket does not exist"
+			return makeDbErr(database.ErrCorruption, str, nil)
+		}
+
+		var innerErr error
+		puts.ForEach(func(k, v []byte) bool {
+			if dbErr := bucket.Put(k, v); dbErr != nil {
+				str := fmt.Sprintf("failed to put key %q to bolt "+
+					"transaction", k)
+				innerErr = convertErr(str, dbErr)
+				return false
+			}
+			return true
+		})
+		if innerErr != nil {
+			return innerErr
+		}
+
+		removes.ForEach(func(k, v []byte) bool {
+			if dbErr := bucket.Delete(k); dbErr != nil {
+				str := fmt.Sprintf("failed to delete key %q from "+
+					"bolt transaction", k)
+				innerErr = convertErr(str, dbErr)
+				return false
+			}
+			return true
+		})
+		return innerErr
+	})
+	if err != nil {
+		if _, ok := err.(database.Error); ok {
+			return err
+		}
+		return convertErr("failed to commit bolt transaction", err)
+	}
+	return nil
+}
+
+// Stats returns statistics about the on-disk storage used by the bbolt
+// database.  The live size is the number of bytes of the pages that are in use
+// by the metadata, which requires traversing all of the pages.
+//
+// This is part of the metadataStore interface implementation.
+func (s *boltStore) Stats() (*metadataStats, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	fi, err := os.Stat(s.path)
+	if err != nil {
+		return nil, makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	stats := metadataStats{numFiles: 1, size: uint64(fi.Size())}
+	err = s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltMetadataBucket)
+		if bucket == nil {
+			str := "metadata bucket does not exist"
+			return makeDbErr(database.ErrCorruption, str, nil)
+		}
+		bucketStats := bucket.Stats()
+		stats.liveSize = uint64(bucketStats.BranchInuse +
+			bucketStats.LeafInuse)
+		return nil
+	})
+	if err != nil {
+		if _, ok := err.(database.Error); ok {
+			return nil, err
+		}
+		return nil, convertErr("failed to open transaction", err)
+	}
+	return &stats, nil
+}
+
+// compactBoltDB copies all of the metadata in the provided source bbolt
+// database to a new bbolt database at the provided path.  Since the new
+// database only contains the live data and it is written in key order, the
+// pages are fully utilized.
+func compactBoltDB(src *bolt.DB, dstPath string) error {
+	dst, err := openBoltDB(dstPath)
+	if err != nil {
+		return err
+	}
+
+	srcTx, err := src.Begin(false)
+	if err != nil {
+		_ = dst.Close()
+		return convertErr("failed to open transaction", err)
+	}
+	defer srcTx.Rollback()
+	srcBucket := srcTx.Bucket(boltMetadataBucket)
+	if srcBucket == nil {
+		_ = dst.Close()
+		str := "metadata bucket does not exist"
+		return makeDbErr(database.ErrCorruption, str, nil)
+	}
+
+	// Copy the metadata in batches to limit memory usage.  The cursor
+	// position is carried across batches since the source transaction is
+	// held open for the duration of the copy.
+	cursor := srcBucket.Cursor()
+	k, v := cursor.First()
+	create := true
+	for create || k != nil {
+		err := dst.Update(func(tx *bolt.Tx) error {
+			var dstBucket *bolt.Bucket
+			if create {
+				var err error
+				dstBucket, err = tx.CreateBucket(boltMetadataBucket)
+				if err != nil {
+					return err
+				}
+				create = false
+			} else {
+				dstBucket = tx.Bucket(boltMetadataBucket)
+			}
+
+			// Keys are inserted in order, so fill the pages completely
+			// rather than leaving room for future inserts.
+			dstBucket.FillPercent = 1.0
+			var batchSize int
+			for ; k != nil && batchSize < boltCompactBatchSize; k, v = cursor.Next() {
+				if err := dstBucket.Put(k, v); err != nil {
+					return err
+				}
+				batchSize += len(k) + len(v)
+			}
+			return nil
+		})
+		if err != nil {
+			_ = dst.Close()
+			return convertErr("failed to write compacted metadata", err)
+		}
+	}
+
+	if err := dst.Close(); err != nil {
+		return convertErr("failed to close compacted metadata", err)
+	}
+	return nil
+}
+
+// Compact compacts the bbolt database by copying all of the live metadata to a
+// new file and then replacing the existing database with it.  Snapshots may be
+// taken while the copy is in progress, however, taking new snapshots blocks
+// while the existing database is replaced and the replacement waits for all
+// outstanding snapshots to be released.
+//
+// This is part of the metadataStore interface implementation.
+func (s *boltStore) Compact() error {
+	// Copy the metadata to a temporary file.  The caller ensures there are
+	// no concurrent writes, so the copy is complete and the database can be
+	// read without holding the lock since it is only replaced below.
+	compactPath := s.path + boltCompactSuffix
+	_ = os.Remove(compactPath)
+	if err := compactBoltDB(s.db, compactPath); err != nil {
+		_ = os.Remove(compactPath)
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// The existing database must be closed before replacing it since open
+	// files can't be replaced on some platforms.  Note that this blocks
+	// until all outstanding snapshots are released.
+	if err := s.db.Close(); err != nil {
+		_ = os.Remove(compactPath)
+		str := "failed to close underlying bolt database"
+		return convertErr(str, err)
+	}
+
+	// Reopen the database regardless of whether or not the replacement
+	// succeeds so the store remains usable.
+	renameErr := os.Rename(compactPath, s.path)
+	db, err := openBoltDB(s.path)
+	if err != nil {
+		return err
+	}
+	s.db = db
+	if renameErr != nil {
+		_ = os.Remove(compactPath)
+		return makeDbErr(database.ErrDriverSpecific, renameErr.Error(),
+			renameErr)
+	}
+	return nil
+}
+
+// Close closes the underlying bbolt database.
+//
+// This is part of the metadataStore interface implementation.
+func (s *boltStore) Close() error {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if err := s.db.Close(); err != nil {
+		str := "failed to close underlying bolt database"
+		return convertErr(str, err)
+	}
+	return nil
+}
+
+// openBoltDB opens the bbolt database at the provided path with the options
+// used for the metadata.  The database is created if it does not exist.
+func openBoltDB(path string) (*bolt.DB, error) {
+	// The freelist is not synced to disk since it is rebuilt when the
+	// database is opened, which considerably reduces the amount of data
+	// written on every commit.
+	opts := bolt.Options{
+		Timeout:        boltOpenTimeout,
+		NoFreelistSync: true,
+		FreelistType:   bolt.FreelistMapType,
+	}
+	db, err := bolt.Open(path, 0600, &opts)
+	if err != nil {
+		return nil, convertErr(err.Error(), err)
+	}
+	return db, nil
+}
+
+// openBoltStore opens the bbolt metadata database at the provided path.  The
+// database is created when the create flag is set and it is an error if it
+// already exists in that case.
+func openBoltStore(path string, create bool) (*boltStore, error) {
+	if _, err := os.Stat(path); create && err == nil {
+		str := fmt.Sprintf("file %q already exists", path)
+		return nil, makeDbErr(database.ErrDbExists, str, nil)
+	}
+
+	db, err := openBoltDB(path)
+	if err != nil {
+		return nil, err
+	}
+	if create {
+		err := db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucket(boltMetadataBucket)
+			return err
+		})
+		if err != nil {
+			_ = db.Close()
+			str := "failed to create metadata bucket"
+			return nil, convertErr(str, err)
+		}
+	}
+	return &boltStore{path: path, db: db}, nil
+}
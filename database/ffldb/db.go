@@ -10,10 +10,10 @@ import (
 	"encoding/binary"
 	"fmt"
 	"os"
-	"path/filepath"
 	"runtime"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/database/v2"
@@ -22,10 +22,9 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/comparer"
 	ldberrors "github.com/syndtr/goleveldb/leveldb/errors"
-	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
-	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
+	bolt "go.etcd.io/bbolt"
 )
 
 const (
@@ -131,9 +130,9 @@ func makeDbErr(c database.ErrorCode, desc string, err error) database.Error {
 	return database.Error{ErrorCode: c, Description: desc, Err: err}
 }
 
-// convertErr converts the passed leveldb error into a database error with an
-// equivalent error code and the passed description.  It also sets the passed
-// error as the underlying error.
+// convertErr converts the passed leveldb or bbolt error into a database error
+// with an equivalent error code and the passed description.  It also sets the
+// passed error as the underlying error.
 func convertErr(desc string, ldbErr error) database.Error {
 	// Use the driver-specific error code by default.  The code below will
 	// update this with the converted error if it's recognized.
@@ -144,8 +143,12 @@ func convertErr(desc string, ldbErr error) database.Error {
 	case ldberrors.IsCorrupted(ldbErr):
 		code = database.ErrCorruption
 
+	case ldbErr == bolt.ErrInvalid, ldbErr == bolt.ErrChecksum,
+		ldbErr == bolt.ErrVersionMismatch:
+		code = database.ErrCorruption
+
 	// Database open/create errors.
-	case ldbErr == leveldb.ErrClosed:
+	case ldbErr == leveldb.ErrClosed, ldbErr == bolt.ErrDatabaseNotOpen:
 		code = database.ErrDbNotOpen
 
 	// Transaction errors.
@@ -153,6 +156,8 @@ func convertErr(desc string, ldbErr error) database.Error {
 		code = database.ErrTxClosed
 	case ldbErr == leveldb.ErrIterReleased:
 		code = database.ErrTxClosed
+	case ldbErr == bolt.ErrTxClosed:
+		code = database.ErrTxClosed
 	}
 
 	return database.Error{ErrorCode: code, Description: desc, Err: ldbErr}
@@ -1787,19 +1792,23 @@ type db struct {
 	writeLock sync.Mutex   // Limit to one write transaction at a time.
 	closeLock sync.RWMutex // Make database close block while txns active.
 	closed    bool         // Is the database closed?
+	dbType    string       // Driver type the database was opened with.
 	store     *blockStore  // Handles read/writing blocks to flat files.
-	cache     *dbCache     // Cache layer which wraps underlying leveldb DB.
+	cache     *dbCache     // Cache layer which wraps underlying metadata DB.
 }
 
-// Enforce db implements the database.DB interface.
-var _ database.DB = (*db)(nil)
+// Enforce db implements the database.DB and database.Maintainer interfaces.
+var (
+	_ database.DB         = (*db)(nil)
+	_ database.Maintainer = (*db)(nil)
+)
 
 // Type returns the database driver type the current database instance was
 // created with.
 //
 // This function is part of the database.DB interface implementation.
 func (db *db) Type() string {
-	return dbType
+	return db.dbType
 }
 
 // begin is the implementation function for the Begin database method.  See its
@@ -1976,7 +1985,7 @@ func (db *db) Close() error {
 	// cache and clear all state without the individual locks.
 
 	// Close the database cache which will flush any existing entries to
-	// disk and close the underlying leveldb database.  Any error is saved
+	// disk and close the underlying metadata database.  Any error is saved
 	// and returned at the end after the remaining cleanup since the
 	// database will be marked closed even if this fails given there is no
 	// good way for the caller to recover from a failure here anyways.
@@ -1998,6 +2007,86 @@ func (db *db) Close() error {
 	return closeErr
 }
 
+// StorageStats returns statistics about the on-disk storage used by the
+// database.  Calculating the statistics requires scanning all of the metadata,
+// so it can take a while for large databases.
+//
+// This function is part of the database.Maintainer interface implementation.
+func (db *db) StorageStats() (*database.StorageStats, error) {
+	// Grab a read lock against the database to ensure it is not closed
+	// while the statistics are being calculated.
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+	if db.closed {
+		return nil, makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr,
+			nil)
+	}
+
+	metaStats, err := db.cache.metaStore.Stats()
+	if err != nil {
+		return nil, err
+	}
+	stats := database.StorageStats{
+		MetadataFiles:    metaStats.numFiles,
+		MetadataSize:     metaStats.size,
+		MetadataLiveSize: metaStats.liveSize,
+	}
+	if metaStats.size > metaStats.liveSize {
+		stats.MetadataGarbageSize = metaStats.size - metaStats.liveSize
+	}
+
+	// Gather the number and total size of the flat block files.  Files that
+	// do not exist are skipped.
+	wc := db.store.writeCursor
+	wc.RLock()
+	lastFileNum := wc.curFileNum
+	wc.RUnlock()
+	for fileNum := uint32(0); fileNum <= lastFileNum; fileNum++ {
+		fi, err := os.Stat(blockFilePath(db.store.basePath, fileNum))
+		if err != nil {
+			continue
+		}
+		stats.BlockFiles++
+		stats.BlockFilesSize += uint64(fi.Size())
+	}
+
+	return &stats, nil
+}
+
+// Compact flushes the database cache and compacts the underlying metadata
+// database in order to reclaim space that is no longer used by live data.  Read
+// transactions may continue while the compaction is in progress, however, write
+// transactions will block until it completes.
+//
+// This function is part of the database.Maintainer interface implementation.
+func (db *db) Compact() error {
+	// Grab the write lock to prevent any writes to the metadata while it is
+	// being compacted along with a read lock against the database to
+	// ensure it is not closed.
+	db.writeLock.Lock()
+	defer db.writeLock.Unlock()
+	db.closeLock.RLock()
+	defer db.closeLock.RUnlock()
+	if db.closed {
+		return makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr, nil)
+	}
+
+	// Flush the cache so all of the metadata is in the underlying database
+	// before compacting it.
+	if err := db.cache.flush(); err != nil {
+		return err
+	}
+
+	log.Infof("Compacting %s metadata database", db.dbType)
+	start := time.Now()
+	if err := db.cache.metaStore.Compact(); err != nil {
+		return err
+	}
+	log.Infof("Compacted %s metadata database in %v", db.dbType,
+		time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
 // fileExists reports whether the named file or directory exists.
 func fileExists(name string) bool {
 	if _, err := os.Stat(name); err != nil {
@@ -2010,11 +2099,11 @@ func fileExists(name string) bool {
 
 // initDB creates the initial buckets and values used by the package.  This is
 // mainly in a separate function for testing purposes.
-func initDB(ldb *leveldb.DB) error {
+func initDB(store metadataStore) error {
 	// The starting block file write cursor location is file num 0, offset
 	// 0.
-	batch := new(leveldb.Batch)
-	batch.Put(bucketizedKey(metadataBucketID, writeLocKeyName),
+	puts := treap.NewMutable()
+	puts.Put(bucketizedKey(metadataBucketID, writeLocKeyName),
 		serializeWriteRow(0, 0))
 
 	// Create block index bucket and set the current bucket id.
@@ -2023,25 +2112,20 @@ func initDB(ldb *leveldb.DB) error {
 	// there is no need to store the bucket index data for the metadata
 	// bucket in the database.  However, the first bucket ID to use does
 	// need to account for it to ensure there are no key collisions.
-	batch.Put(bucketIndexKey(metadataBucketID, blockIdxBucketName),
+	puts.Put(bucketIndexKey(metadataBucketID, blockIdxBucketName),
 		blockIdxBucketID[:])
-	batch.Put(curBucketIDKeyName, blockIdxBucketID[:])
+	puts.Put(curBucketIDKeyName, blockIdxBucketID[:])
 
-	// Write everything as a single batch.
-	if err := ldb.Write(batch, nil); err != nil {
-		str := fmt.Sprintf("failed to initialize metadata database: %v",
-			err)
-		return convertErr(str, err)
-	}
-
-	return nil
+	// Write everything atomically.
+	return store.Write(puts, treap.NewMutable())
 }
 
-// openDB opens the database at the provided path.  database.ErrDbDoesNotExist
-// is returned if the database doesn't exist and the create flag is not set.
-func openDB(dbPath string, network wire.CurrencyNet, create bool) (database.DB, error) {
+// openDB opens the database of the provided driver type at the provided path.
+// database.ErrDbDoesNotExist is returned if the database doesn't exist and the
+// create flag is not set.
+func openDB(dbPath, dbType string, network wire.CurrencyNet, create bool) (database.DB, error) {
 	// Error if the database doesn't exist and the create flag is not set.
-	metadataDbPath := filepath.Join(dbPath, metadataDbName)
+	metadataDbPath := metadataStorePath(dbPath, dbType)
 	dbExists := fileExists(metadataDbPath)
 	if !create && !dbExists {
 		str := fmt.Sprintf("database %q does not exist", metadataDbPath)
@@ -2050,32 +2134,26 @@ func openDB(dbPath string, network wire.CurrencyNet, create bool) (database.DB,
 
 	// Ensure the full path to the database exists.
 	if !dbExists {
-		// The error can be ignored here since the call to
-		// leveldb.OpenFile will fail if the directory couldn't be
+		// The error can be ignored here since the call to open the
+		// metadata database will fail if the directory couldn't be
 		// created.
 		_ = os.MkdirAll(dbPath, 0700)
 	}
 
 	// Open the metadata database (will create it if needed).
-	opts := opt.Options{
-		ErrorIfExist: create,
-		Strict:       opt.DefaultStrict,
-		Compression:  opt.NoCompression,
-		Filter:       filter.NewBloomFilter(10),
-	}
-	ldb, err := leveldb.OpenFile(metadataDbPath, &opts)
+	metaStore, err := openMetadataStore(dbPath, dbType, create)
 	if err != nil {
-		return nil, convertErr(err.Error(), err)
+		return nil, err
 	}
 
 	// Create the block store which includes scanning the existing flat
 	// block files to find what the current write cursor position is
 	// according to the data that is actually on disk.  Also create the
-	// database cache which wraps the underlying leveldb database to provide
-	// write caching.
+	// database cache which wraps the underlying metadata database to
+	// provide write caching.
 	store := newBlockStore(dbPath, network)
-	cache := newDbCache(ldb, store, defaultCacheSize, defaultFlushSecs)
-	pdb := &db{store: store, cache: cache}
+	cache := newDbCache(metaStore, store, defaultCacheSize, defaultFlushSecs)
+	pdb := &db{dbType: dbType, store: store, cache: cache}
 
 	// Perform any reconciliation needed between the block and metadata as
 	// well as database initialization, if needed.
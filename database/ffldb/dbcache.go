@@ -7,12 +7,10 @@ package ffldb
 
 import (
 	"bytes"
-	"fmt"
 	"sync"
 	"time"
 
 	"github.com/decred/dcrd/database/v2/internal/treap"
-	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 	"github.com/syndtr/goleveldb/leveldb/util"
 )
@@ -273,7 +271,7 @@ func (iter *dbCacheIterator) Error() error {
 // dbCacheSnapshot defines a snapshot of the database cache and underlying
 // database at a particular point in time.
 type dbCacheSnapshot struct {
-	dbSnapshot    *leveldb.Snapshot
+	dbSnapshot    metadataSnapshot
 	pendingKeys   *treap.Immutable
 	pendingRemove *treap.Immutable
 }
@@ -289,8 +287,7 @@ func (snap *dbCacheSnapshot) Has(key []byte) bool {
 	}
 
 	// Consult the database.
-	hasKey, _ := snap.dbSnapshot.Has(key, nil)
-	return hasKey
+	return snap.dbSnapshot.Has(key)
 }
 
 // Get returns the value for the passed key.  The function will return nil when
@@ -305,20 +302,25 @@ func (snap *dbCacheSnapshot) Get(key []byte) []byte {
 	}
 
 	// Consult the database.
-	value, err := snap.dbSnapshot.Get(key, nil)
-	if err != nil {
-		return nil
-	}
-	return value
+	return snap.dbSnapshot.Get(key)
 }
 
 // Release releases the snapshot.
 func (snap *dbCacheSnapshot) Release() {
-	snap.dbSnapshot.Release()
+	snap.releaseDB()
 	snap.pendingKeys = nil
 	snap.pendingRemove = nil
 }
 
+// releaseDB releases the snapshot of the underlying database while leaving the
+// snapshot of the cache intact.  It is safe to call multiple times.
+func (snap *dbCacheSnapshot) releaseDB() {
+	if snap.dbSnapshot != nil {
+		snap.dbSnapshot.Release()
+		snap.dbSnapshot = nil
+	}
+}
+
 // NewIterator returns a new iterator for the snapshot.  The newly returned
 // iterator is not pointing to a valid item until a call to one of the methods
 // to position it is made.
@@ -328,7 +330,7 @@ func (snap *dbCacheSnapshot) Release() {
 // can be nil if the functionality is not desired.
 func (snap *dbCacheSnapshot) NewIterator(slice *util.Range) *dbCacheIterator {
 	return &dbCacheIterator{
-		dbIter:        snap.dbSnapshot.NewIterator(slice, nil),
+		dbIter:        snap.dbSnapshot.NewIterator(slice),
 		cacheIter:     newLdbCacheIter(snap, slice),
 		cacheSnapshot: snap,
 	}
@@ -342,8 +344,8 @@ func (snap *dbCacheSnapshot) NewIterator(slice *util.Range) *dbCacheIterator {
 // can commit transactions at will without incurring large performance hits due
 // to frequent disk syncs.
 type dbCache struct {
-	// ldb is the underlying leveldb DB for metadata.
-	ldb *leveldb.DB
+	// metaStore is the underlying key/value store for metadata.
+	metaStore metadataStore
 
 	// store is used to sync blocks to flat files.
 	store *blockStore
@@ -384,10 +386,9 @@ type dbCache struct {
 //
 // The snapshot must be released after use by calling Release.
 func (c *dbCache) Snapshot() (*dbCacheSnapshot, error) {
-	dbSnapshot, err := c.ldb.GetSnapshot()
+	dbSnapshot, err := c.metaStore.Snapshot()
 	if err != nil {
-		str := "failed to open transaction"
-		return nil, convertErr(str, err)
+		return nil, err
 	}
 
 	// Since the cached keys to be added and removed use an immutable treap,
@@ -403,30 +404,6 @@ func (c *dbCache) Snapshot() (*dbCacheSnapshot, error) {
 	return cacheSnapshot, nil
 }
 
-// updateDB invokes the passed function in the context of a managed leveldb
-// transaction.  Any errors returned from the user-supplied function will cause
-// the transaction to be rolled back and are returned from this function.
-// Otherwise, the transaction is committed when the user-supplied function
-// returns a nil error.
-func (c *dbCache) updateDB(fn func(ldbTx *leveldb.Transaction) error) error {
-	// Start a leveldb transaction.
-	ldbTx, err := c.ldb.OpenTransaction()
-	if err != nil {
-		return convertErr("failed to open ldb transaction", err)
-	}
-
-	if err := fn(ldbTx); err != nil {
-		ldbTx.Discard()
-		return err
-	}
-
-	// Commit the leveldb transaction and convert any errors as needed.
-	if err := ldbTx.Commit(); err != nil {
-		return convertErr("failed to commit leveldb transaction", err)
-	}
-	return nil
-}
-
 // TreapForEacher is an interface which allows iteration of a treap in ascending
 // order using a user-supplied callback for each key/value pair.  It mainly
 // exists so both mutable and immutable treaps can be atomically committed to
@@ -438,34 +415,7 @@ type TreapForEacher interface {
 // commitTreaps atomically commits all of the passed pending add/update/remove
 // updates to the underlying database.
 func (c *dbCache) commitTreaps(pendingKeys, pendingRemove TreapForEacher) error {
-	// Perform all leveldb updates using an atomic transaction.
-	return c.updateDB(func(ldbTx *leveldb.Transaction) error {
-		var innerErr error
-		pendingKeys.ForEach(func(k, v []byte) bool {
-			if dbErr := ldbTx.Put(k, v, nil); dbErr != nil {
-				str := fmt.Sprintf("failed to put key %q to "+
-					"ldb transaction", k)
-				innerErr = convertErr(str, dbErr)
-				return false
-			}
-			return true
-		})
-		if innerErr != nil {
-			return innerErr
-		}
-
-		pendingRemove.ForEach(func(k, v []byte) bool {
-			if dbErr := ldbTx.Delete(k, nil); dbErr != nil {
-				str := fmt.Sprintf("failed to delete "+
-					"key %q from ldb transaction",
-					k)
-				innerErr = convertErr(str, dbErr)
-				return false
-			}
-			return true
-		})
-		return innerErr
-	})
+	return c.metaStore.Write(pendingKeys, pendingRemove)
 }
 
 // flush flushes the database cache to persistent storage.  This involves
@@ -497,7 +447,7 @@ func (c *dbCache) flush() error {
 		return nil
 	}
 
-	// Perform all leveldb updates using an atomic transaction.
+	// Perform all database updates using an atomic transaction.
 	if err := c.commitTreaps(cachedKeys, cachedRemove); err != nil {
 		return err
 	}
@@ -557,11 +507,18 @@ func (c *dbCache) commitTx(tx *transaction) error {
 	// Flush the cache and write the current transaction directly to the
 	// database if a flush is needed.
 	if c.needsFlush(tx) {
+		// Release the snapshot of the underlying database held by the
+		// transaction prior to flushing since some stores, such as bbolt,
+		// are unable to grow while the goroutine performing the write
+		// holds a snapshot open.  Only the cached pending keys of the
+		// snapshot are needed from this point on.
+		tx.snapshot.releaseDB()
+
 		if err := c.flush(); err != nil {
 			return err
 		}
 
-		// Perform all leveldb updates using an atomic transaction.
+		// Perform all database updates using an atomic transaction.
 		err := c.commitTreaps(tx.pendingKeys, tx.pendingRemove)
 		if err != nil {
 			return err
@@ -610,7 +567,7 @@ func (c *dbCache) commitTx(tx *transaction) error {
 }
 
 // Close cleanly shuts down the database cache by syncing all data and closing
-// the underlying leveldb database.
+// the underlying database.
 //
 // This function MUST be called with the database write lock held.
 func (c *dbCache) Close() error {
@@ -619,26 +576,21 @@ func (c *dbCache) Close() error {
 		// Even if there is an error while flushing, attempt to close
 		// the underlying database.  The error is ignored since it would
 		// mask the flush error.
-		_ = c.ldb.Close()
+		_ = c.metaStore.Close()
 		return err
 	}
 
-	// Close the underlying leveldb database.
-	if err := c.ldb.Close(); err != nil {
-		str := "failed to close underlying leveldb database"
-		return convertErr(str, err)
-	}
-
-	return nil
+	// Close the underlying database.
+	return c.metaStore.Close()
 }
 
 // newDbCache returns a new database cache instance backed by the provided
-// leveldb instance.  The cache will be flushed to leveldb when the max size
+// metadata store.  The cache will be flushed to the store when the max size
 // exceeds the provided value or it has been longer than the provided interval
 // since the last flush.
-func newDbCache(ldb *leveldb.DB, store *blockStore, maxSize uint64, flushIntervalSecs uint32) *dbCache {
+func newDbCache(metaStore metadataStore, store *blockStore, maxSize uint64, flushIntervalSecs uint32) *dbCache {
 	return &dbCache{
-		ldb:           ldb,
+		metaStore:     metaStore,
 		store:         store,
 		maxSize:       maxSize,
 		flushInterval: time.Second * time.Duration(flushIntervalSecs),
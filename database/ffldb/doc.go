@@ -1,5 +1,5 @@
 // Copyright (c) 2015-2016 The btcsuite developers
-// Copyright (c) 2016 The Decred developers
+// Copyright (c) 2016-2020 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -11,6 +11,14 @@ This driver is the recommended driver for use with dcrd.  It makes use leveldb
 for the metadata, flat files for block storage, and checksums in key areas to
 ensure data integrity.
 
+Metadata Backends
+
+The metadata may alternatively be housed in bbolt by using the database type of
+"ffbolt".  Both database types share the same flat files for block storage.
+bbolt is a copy-on-write B+tree that serves concurrent readers without locking
+and never performs background compactions.  An existing database may be
+converted from one type to the other with the Migrate function.
+
 Usage
 
 This package is a driver to the database package and provides the database type
@@ -16,11 +16,17 @@ import (
 var log = slog.Disabled
 
 const (
+	// dbType is the database type name for the driver that houses the
+	// metadata in leveldb.
 	dbType = "ffldb"
+
+	// boltDbType is the database type name for the driver that houses the
+	// metadata in bbolt.
+	boltDbType = "ffbolt"
 )
 
 // parseArgs parses the arguments from the database Open/Create methods.
-func parseArgs(funcName string, args ...interface{}) (string, wire.CurrencyNet, error) {
+func parseArgs(dbType, funcName string, args ...interface{}) (string, wire.CurrencyNet, error) {
 	if len(args) != 2 {
 		return "", 0, fmt.Errorf("invalid arguments to %s.%s -- "+
 			"expected database path and block network", dbType,
@@ -42,26 +48,30 @@ func parseArgs(funcName string, args ...interface{}) (string, wire.CurrencyNet,
 	return dbPath, network, nil
 }
 
-// openDBDriver is the callback provided during driver registration that opens
-// an existing database for use.
-func openDBDriver(args ...interface{}) (database.DB, error) {
-	dbPath, network, err := parseArgs("Open", args...)
-	if err != nil {
-		return nil, err
-	}
+// openDBDriver returns the callback provided during driver registration that
+// opens an existing database of the provided type for use.
+func openDBDriver(dbType string) func(args ...interface{}) (database.DB, error) {
+	return func(args ...interface{}) (database.DB, error) {
+		dbPath, network, err := parseArgs(dbType, "Open", args...)
+		if err != nil {
+			return nil, err
+		}
 
-	return openDB(dbPath, network, false)
+		return openDB(dbPath, dbType, network, false)
+	}
 }
 
-// createDBDriver is the callback provided during driver registration that
-// creates, initializes, and opens a database for use.
-func createDBDriver(args ...interface{}) (database.DB, error) {
-	dbPath, network, err := parseArgs("Create", args...)
-	if err != nil {
-		return nil, err
-	}
+// createDBDriver returns the callback provided during driver registration that
+// creates, initializes, and opens a database of the provided type for use.
+func createDBDriver(dbType string) func(args ...interface{}) (database.DB, error) {
+	return func(args ...interface{}) (database.DB, error) {
+		dbPath, network, err := parseArgs(dbType, "Create", args...)
+		if err != nil {
+			return nil, err
+		}
 
-	return openDB(dbPath, network, true)
+		return openDB(dbPath, dbType, network, true)
+	}
 }
 
 // useLogger is the callback provided during driver registration that sets the
@@ -71,15 +81,19 @@ func useLogger(logger slog.Logger) {
 }
 
 func init() {
-	// Register the driver.
-	driver := database.Driver{
-		DbType:    dbType,
-		Create:    createDBDriver,
-		Open:      openDBDriver,
-		UseLogger: useLogger,
-	}
-	if err := database.RegisterDriver(driver); err != nil {
-		panic(fmt.Sprintf("Failed to register database driver '%s': %v",
-			dbType, err))
+	// Register a driver for each of the supported metadata databases.  The
+	// drivers share the same flat block files and only differ in how the
+	// metadata is stored.
+	for _, dbType := range []string{dbType, boltDbType} {
+		driver := database.Driver{
+			DbType:    dbType,
+			Create:    createDBDriver(dbType),
+			Open:      openDBDriver(dbType),
+			UseLogger: useLogger,
+		}
+		if err := database.RegisterDriver(driver); err != nil {
+			panic(fmt.Sprintf("Failed to register database driver "+
+				"'%s': %v", dbType, err))
+		}
 	}
 }
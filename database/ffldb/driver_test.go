@@ -19,8 +19,14 @@ import (
 	"github.com/decred/dcrd/dcrutil/v3"
 )
 
-// dbType is the database type name for this driver.
-const dbType = "ffldb"
+const (
+	// dbType is the database type name for this driver.
+	dbType = "ffldb"
+
+	// boltDbType is the database type name for the driver that houses the
+	// metadata in bbolt.
+	boltDbType = "ffbolt"
+)
 
 // TestCreateOpenFail ensures that errors related to creating and opening a
 // database are handled properly.
@@ -149,12 +155,20 @@ func TestCreateOpenFail(t *testing.T) {
 }
 
 // TestPersistence ensures that values stored are still valid after closing and
-// reopening the database.
+// reopening the database for all of the supported metadata databases.
 func TestPersistence(t *testing.T) {
 	t.Parallel()
 
+	for _, dbType := range []string{dbType, boltDbType} {
+		testPersistence(t, dbType)
+	}
+}
+
+// testPersistence ensures that values stored are still valid after closing and
+// reopening a database of the provided type.
+func testPersistence(t *testing.T, dbType string) {
 	// Create a new database to run tests against.
-	dbPath := filepath.Join(os.TempDir(), "ffldb-persistencetest-v2")
+	dbPath := filepath.Join(os.TempDir(), dbType+"-persistencetest-v2")
 	_ = os.RemoveAll(dbPath)
 	db, err := database.Create(dbType, dbPath, blockDataNet)
 	if err != nil {
@@ -256,12 +270,21 @@ func TestPersistence(t *testing.T) {
 	}
 }
 
-// TestInterface performs all interfaces tests for this database driver.
+// TestInterface performs all interfaces tests for this database driver for all
+// of the supported metadata databases.
 func TestInterface(t *testing.T) {
 	t.Parallel()
 
+	for _, dbType := range []string{dbType, boltDbType} {
+		testInterfaceForType(t, dbType)
+	}
+}
+
+// testInterfaceForType performs all interface tests against a database of the
+// provided type.
+func testInterfaceForType(t *testing.T, dbType string) {
 	// Create a new database to run tests against.
-	dbPath := filepath.Join(os.TempDir(), "ffldb-interfacetest-v2")
+	dbPath := filepath.Join(os.TempDir(), dbType+"-interfacetest-v2")
 	_ = os.RemoveAll(dbPath)
 	db, err := database.Create(dbType, dbPath, blockDataNet)
 	if err != nil {
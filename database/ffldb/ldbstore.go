@@ -0,0 +1,211 @@
+// Copyright (c) 2015-2016 The btcsuite developers
+// Copyright (c) 2016-2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/decred/dcrd/database/v2"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// ldbMetadataPath returns the path to the leveldb metadata database within the
+// given database path.
+func ldbMetadataPath(dbPath string) string {
+	return filepath.Join(dbPath, metadataDbName)
+}
+
+// ldbSnapshot houses a leveldb snapshot and implements the metadataSnapshot
+// interface.
+type ldbSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+// Enforce ldbSnapshot implements the metadataSnapshot interface.
+var _ metadataSnapshot = (*ldbSnapshot)(nil)
+
+// Has returns whether or not the passed key exists.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *ldbSnapshot) Has(key []byte) bool {
+	hasKey, _ := s.snap.Has(key, nil)
+	return hasKey
+}
+
+// Get returns a copy of the value for the passed key.  It returns nil when the
+// key does not exist.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *ldbSnapshot) Get(key []byte) []byte {
+	value, err := s.snap.Get(key, nil)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+// NewIterator returns a new iterator over the key/value pairs in the snapshot
+// limited to the provided range.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *ldbSnapshot) NewIterator(slice *util.Range) iterator.Iterator {
+	return s.snap.NewIterator(slice, nil)
+}
+
+// Release releases the snapshot.
+//
+// This is part of the metadataSnapshot interface implementation.
+func (s *ldbSnapshot) Release() {
+	s.snap.Release()
+}
+
+// ldbStore houses the metadata in a leveldb database and implements the
+// metadataStore interface.
+type ldbStore struct {
+	path string
+	ldb  *leveldb.DB
+}
+
+// Enforce ldbStore implements the metadataStore interface.
+var _ metadataStore = (*ldbStore)(nil)
+
+// Snapshot returns a snapshot of the leveldb database at the current point in
+// time.
+//
+// This is part of the metadataStore interface implementation.
+func (s *ldbStore) Snapshot() (metadataSnapshot, error) {
+	snap, err := s.ldb.GetSnapshot()
+	if err != nil {
+		str := "failed to open transaction"
+		return nil, convertErr(str, err)
+	}
+	return &ldbSnapshot{snap: snap}, nil
+}
+
+// Write atomically stores all of the passed key/value pairs and removes all of
+// the passed keys using a leveldb transaction.
+//
+// This is part of the metadataStore interface implementation.
+func (s *ldbStore) Write(puts, removes TreapForEacher) error {
+	// Start a leveldb transaction.
+	ldbTx, err := s.ldb.OpenTransaction()
+	if err != nil {
+		return convertErr("failed to open ldb transaction", err)
+	}
+
+	var innerErr error
+	puts.ForEach(func(k, v []byte) bool {
+		if dbErr := ldbTx.Put(k, v, nil); dbErr != nil {
+			str := fmt.Sprintf("failed to put key %q to ldb transaction",
+				k)
+			innerErr = convertErr(str, dbErr)
+			return false
+		}
+		return true
+	})
+	if innerErr == nil {
+		removes.ForEach(func(k, v []byte) bool {
+			if dbErr := ldbTx.Delete(k, nil); dbErr != nil {
+				str := fmt.Sprintf("failed to delete key %q from "+
+					"ldb transaction", k)
+				innerErr = convertErr(str, dbErr)
+				return false
+			}
+			return true
+		})
+	}
+	if innerErr != nil {
+		ldbTx.Discard()
+		return innerErr
+	}
+
+	// Commit the leveldb transaction and convert any errors as needed.
+	if err := ldbTx.Commit(); err != nil {
+		return convertErr("failed to commit leveldb transaction", err)
+	}
+	return nil
+}
+
+// Stats returns statistics about the on-disk storage used by the leveldb
+// database.  The live size is calculated by summing the sizes of all keys and
+// values, which requires iterating the entire database.
+//
+// This is part of the metadataStore interface implementation.
+func (s *ldbStore) Stats() (*metadataStats, error) {
+	files, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return nil, makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	var stats metadataStats
+	for _, file := range files {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		stats.numFiles++
+		stats.size += uint64(file.Size())
+	}
+
+	snap, err := s.ldb.GetSnapshot()
+	if err != nil {
+		return nil, convertErr("failed to open snapshot", err)
+	}
+	defer snap.Release()
+	iter := snap.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		stats.liveSize += uint64(len(iter.Key()) + len(iter.Value()))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, convertErr("failed to iterate metadata", err)
+	}
+	return &stats, nil
+}
+
+// Compact compacts the entire leveldb database which discards deleted and
+// overwritten entries.  Reads and writes are still allowed while the compaction
+// is in progress.
+//
+// This is part of the metadataStore interface implementation.
+func (s *ldbStore) Compact() error {
+	if err := s.ldb.CompactRange(util.Range{}); err != nil {
+		return convertErr("failed to compact leveldb database", err)
+	}
+	return nil
+}
+
+// Close closes the underlying leveldb database.
+//
+// This is part of the metadataStore interface implementation.
+func (s *ldbStore) Close() error {
+	if err := s.ldb.Close(); err != nil {
+		str := "failed to close underlying leveldb database"
+		return convertErr(str, err)
+	}
+	return nil
+}
+
+// openLdbStore opens the leveldb metadata database at the provided path.  The
+// database is created when the create flag is set and it is an error if it
+// already exists in that case.
+func openLdbStore(path string, create bool) (*ldbStore, error) {
+	opts := opt.Options{
+		ErrorIfExist: create,
+		Strict:       opt.DefaultStrict,
+		Compression:  opt.NoCompression,
+		Filter:       filter.NewBloomFilter(10),
+	}
+	ldb, err := leveldb.OpenFile(path, &opts)
+	if err != nil {
+		return nil, convertErr(err.Error(), err)
+	}
+	return &ldbStore{path: path, ldb: ldb}, nil
+}
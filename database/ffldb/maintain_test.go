@@ -0,0 +1,207 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+// TestCompact ensures compacting the supported metadata databases reclaims the
+// space used by removed data while preserving the live data and that the
+// reported storage statistics are sane.
+func TestCompact(t *testing.T) {
+	t.Parallel()
+
+	for _, dbType := range []string{dbType, boltDbType} {
+		testCompact(t, dbType)
+	}
+}
+
+// testCompact ensures compacting a database of the provided type reclaims the
+// space used by removed data while preserving the live data.
+func testCompact(t *testing.T, dbType string) {
+	dbPath := filepath.Join(os.TempDir(), dbType+"-compacttest-v2")
+	_ = os.RemoveAll(dbPath)
+	defer os.RemoveAll(dbPath)
+	db, err := database.Create(dbType, dbPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("%s: Create: unexpected error: %v", dbType, err)
+	}
+	defer db.Close()
+	maintainer, ok := db.(database.Maintainer)
+	if !ok {
+		t.Fatalf("%s: database does not implement Maintainer", dbType)
+	}
+
+	// Store a block along with a large number of keys, most of which are
+	// subsequently removed.  The database is compacted after adding the
+	// keys to ensure they are flushed to the underlying metadata database.
+	const numKeys = 20000
+	const keepEvery = 10
+	bucketKey := []byte("compactbucket")
+	value := bytes.Repeat([]byte{0x55}, 100)
+	genesisBlock := dcrutil.NewBlock(chaincfg.MainNetParams().GenesisBlock)
+	err = db.Update(func(tx database.Tx) error {
+		if _, err := tx.Metadata().CreateBucket(bucketKey); err != nil {
+			return err
+		}
+		return tx.StoreBlock(genesisBlock)
+	})
+	if err != nil {
+		t.Fatalf("%s: Update: unexpected error: %v", dbType, err)
+	}
+	var key [4]byte
+	err = db.Update(func(tx database.Tx) error {
+		bucket := tx.Metadata().Bucket(bucketKey)
+		for i := uint32(0); i < numKeys; i++ {
+			binary.BigEndian.PutUint32(key[:], i)
+			if err := bucket.Put(key[:], value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s: Update: unexpected error: %v", dbType, err)
+	}
+	if err := maintainer.Compact(); err != nil {
+		t.Fatalf("%s: Compact: unexpected error: %v", dbType, err)
+	}
+	err = db.Update(func(tx database.Tx) error {
+		bucket := tx.Metadata().Bucket(bucketKey)
+		for i := uint32(0); i < numKeys; i++ {
+			if i%keepEvery == 0 {
+				continue
+			}
+			binary.BigEndian.PutUint32(key[:], i)
+			if err := bucket.Delete(key[:]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s: Update: unexpected error: %v", dbType, err)
+	}
+
+	// Ensure the statistics prior to compaction are sane.
+	before, err := maintainer.StorageStats()
+	if err != nil {
+		t.Fatalf("%s: StorageStats: unexpected error: %v", dbType, err)
+	}
+	if before.MetadataFiles == 0 || before.MetadataSize == 0 {
+		t.Fatalf("%s: StorageStats: unexpected empty metadata stats: %+v",
+			dbType, before)
+	}
+	if before.MetadataLiveSize+before.MetadataGarbageSize !=
+		before.MetadataSize {
+
+		t.Fatalf("%s: StorageStats: live and garbage sizes do not sum "+
+			"to total size: %+v", dbType, before)
+	}
+	if before.BlockFiles != 1 || before.BlockFilesSize == 0 {
+		t.Fatalf("%s: StorageStats: unexpected block file stats: %+v",
+			dbType, before)
+	}
+
+	// Compact the database while a read transaction is active to ensure
+	// compaction does not interfere with readers and ensure it reclaimed
+	// space.
+	tx, err := db.Begin(false)
+	if err != nil {
+		t.Fatalf("%s: Begin: unexpected error: %v", dbType, err)
+	}
+	compactErr := make(chan error, 1)
+	go func() { compactErr <- maintainer.Compact() }()
+	if tx.Metadata().Bucket(bucketKey).Get([]byte{0, 0, 0, 0}) == nil {
+		t.Fatalf("%s: Get: unexpected nil value during compaction", dbType)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("%s: Rollback: unexpected error: %v", dbType, err)
+	}
+	if err := <-compactErr; err != nil {
+		t.Fatalf("%s: Compact: unexpected error: %v", dbType, err)
+	}
+
+	// Note that leveldb removes obsolete files asynchronously, so allow some
+	// time for the size to reflect the compaction.
+	var after *database.StorageStats
+	for i := 0; i < 50; i++ {
+		after, err = maintainer.StorageStats()
+		if err != nil {
+			t.Fatalf("%s: StorageStats: unexpected error: %v", dbType,
+				err)
+		}
+		if after.MetadataSize < before.MetadataSize {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if after.MetadataSize >= before.MetadataSize {
+		t.Fatalf("%s: Compact: metadata size did not shrink - before "+
+			"%d, after %d", dbType, before.MetadataSize,
+			after.MetadataSize)
+	}
+	if after.BlockFiles != before.BlockFiles ||
+		after.BlockFilesSize != before.BlockFilesSize {
+
+		t.Fatalf("%s: Compact: block file stats changed - before %+v, "+
+			"after %+v", dbType, before, after)
+	}
+
+	// Ensure all of the live data is still intact and the database remains
+	// writable.
+	err = db.Update(func(tx database.Tx) error {
+		bucket := tx.Metadata().Bucket(bucketKey)
+		var numFound int
+		err := bucket.ForEach(func(k, v []byte) error {
+			i := binary.BigEndian.Uint32(k)
+			if i%keepEvery != 0 || !bytes.Equal(v, value) {
+				return fmt.Errorf("unexpected entry %d", i)
+			}
+			numFound++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if numFound != numKeys/keepEvery {
+			return fmt.Errorf("found %d keys, want %d", numFound,
+				numKeys/keepEvery)
+		}
+		hasBlock, err := tx.HasBlock(genesisBlock.Hash())
+		if err != nil {
+			return err
+		}
+		if !hasBlock {
+			return fmt.Errorf("block %v missing", genesisBlock.Hash())
+		}
+		return bucket.Put([]byte("newkey"), value)
+	})
+	if err != nil {
+		t.Fatalf("%s: Update: unexpected error: %v", dbType, err)
+	}
+
+	// Ensure the maintenance functions fail once the database is closed.
+	if err := db.Close(); err != nil {
+		t.Fatalf("%s: Close: unexpected error: %v", dbType, err)
+	}
+	err = maintainer.Compact()
+	if !checkDbError(t, "Compact closed", err, database.ErrDbNotOpen) {
+		return
+	}
+	_, err = maintainer.StorageStats()
+	checkDbError(t, "StorageStats closed", err, database.ErrDbNotOpen)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb
+
+import (
+	"fmt"
+
+	"github.com/decred/dcrd/database/v2"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// metadataSnapshot defines a read-only snapshot of a metadata store at a
+// particular point in time.
+type metadataSnapshot interface {
+	// Has returns whether or not the passed key exists.
+	Has(key []byte) bool
+
+	// Get returns a copy of the value for the passed key.  It returns nil
+	// when the key does not exist.
+	Get(key []byte) []byte
+
+	// NewIterator returns a new iterator over the key/value pairs in the
+	// snapshot limited to the provided range.  The start key of the range
+	// is inclusive and the limit key is exclusive.  Either or both can be
+	// nil if the functionality is not desired.
+	//
+	// The keys and values returned by the iterator are only valid until it
+	// is moved.
+	NewIterator(slice *util.Range) iterator.Iterator
+
+	// Release releases the snapshot.  It must not be used afterwards.
+	Release()
+}
+
+// metadataStore defines the interface for the underlying key/value store that
+// persists the metadata.  It allows the database to house the metadata in
+// different storage engines while sharing the same flat block files, caching,
+// and bucket semantics.
+type metadataStore interface {
+	// Snapshot returns a snapshot of the store at the current point in
+	// time.  The snapshot must be released after use by calling Release.
+	Snapshot() (metadataSnapshot, error)
+
+	// Write atomically stores all of the passed key/value pairs and removes
+	// all of the passed keys.
+	Write(puts, removes TreapForEacher) error
+
+	// Stats returns statistics about the on-disk storage used by the store.
+	Stats() (*metadataStats, error)
+
+	// Compact compacts the store in order to reclaim space that is no
+	// longer used by live data.  It must only be called when there are no
+	// concurrent writes.
+	Compact() error
+
+	// Close cleanly closes the store.
+	Close() error
+}
+
+// metadataStats houses statistics about the on-disk storage used by a metadata
+// store.
+type metadataStats struct {
+	numFiles uint32 // Number of files that house the store.
+	size     uint64 // Total size of the files in bytes.
+	liveSize uint64 // Number of bytes used by live data.
+}
+
+// metadataStorePath returns the path to the metadata store for the provided
+// database type within the given database path.
+func metadataStorePath(dbPath, driverType string) string {
+	if driverType == boltDbType {
+		return boltMetadataPath(dbPath)
+	}
+	return ldbMetadataPath(dbPath)
+}
+
+// openMetadataStore opens the metadata store for the provided database type
+// within the given database path.  The store is created when the create flag
+// is set and it is an error if it already exists in that case.
+func openMetadataStore(dbPath, driverType string, create bool) (metadataStore, error) {
+	switch driverType {
+	case dbType:
+		return openLdbStore(ldbMetadataPath(dbPath), create)
+	case boltDbType:
+		return openBoltStore(boltMetadataPath(dbPath), create)
+	}
+
+	str := fmt.Sprintf("unsupported metadata database type %q",
+		driverType)
+	return nil, makeDbErr(database.ErrDbUnknownType, str, nil)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/database/v2/internal/treap"
+	"github.com/decred/dcrd/wire"
+)
+
+// migrateBatchSize is the approximate number of bytes of metadata that are
+// written to the destination database at a time during a migration.
+const migrateBatchSize = 64 * 1024 * 1024 // 64 MB
+
+// interruptRequested returns true when the provided channel has been closed.
+// This simplifies early shutdown slightly since the caller can just use an if
+// statement instead of a select.
+func interruptRequested(interrupt <-chan struct{}) bool {
+	select {
+	case <-interrupt:
+		return true
+	default:
+	}
+
+	return false
+}
+
+// errMigrateInterrupted is the error returned when a migration is interrupted.
+var errMigrateInterrupted = makeDbErr(database.ErrDriverSpecific,
+	"migration interrupted", nil)
+
+// copyBlockFile copies the flat block file at the provided source path to the
+// provided destination path and syncs it to disk.
+func copyBlockFile(srcPath, dstPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(dstPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0666)
+	if err != nil {
+		return makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	if err := dst.Sync(); err != nil {
+		_ = dst.Close()
+		return makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	if err := dst.Close(); err != nil {
+		return makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	return nil
+}
+
+// migrate copies the flat block files and all metadata from the provided open
+// source database to a new metadata database of the provided type at the
+// destination path.
+func migrate(src *db, dstPath, dstType string, interrupt <-chan struct{}) error {
+	// Copy the flat block files.  The source database is not modified by
+	// the migration, so the files up to and including the current write
+	// file are all that exist.
+	wc := src.store.writeCursor
+	wc.RLock()
+	lastFileNum := wc.curFileNum
+	wc.RUnlock()
+	for fileNum := uint32(0); fileNum <= lastFileNum; fileNum++ {
+		if interruptRequested(interrupt) {
+			return errMigrateInterrupted
+		}
+
+		srcFilePath := blockFilePath(src.store.basePath, fileNum)
+		if !fileExists(srcFilePath) {
+			continue
+		}
+		log.Debugf("Copying block file %d of %d", fileNum, lastFileNum)
+		err := copyBlockFile(srcFilePath, blockFilePath(dstPath, fileNum))
+		if err != nil {
+			return err
+		}
+	}
+
+	// Copy all of the metadata in batches to limit memory usage.
+	dstStore, err := openMetadataStore(dstPath, dstType, true)
+	if err != nil {
+		return err
+	}
+	snapshot, err := src.cache.metaStore.Snapshot()
+	if err != nil {
+		_ = dstStore.Close()
+		return err
+	}
+	defer snapshot.Release()
+
+	var numKeys uint64
+	puts := treap.NewMutable()
+	removes := treap.NewMutable()
+	iter := snapshot.NewIterator(nil)
+	defer iter.Release()
+	for iter.Next() {
+		if interruptRequested(interrupt) {
+			_ = dstStore.Close()
+			return errMigrateInterrupted
+		}
+
+		// The iterator keys and values are only valid until it is moved,
+		// so copy them.
+		puts.Put(copySlice(iter.Key()), copySlice(iter.Value()))
+		numKeys++
+		if puts.Size() >= migrateBatchSize {
+			if err := dstStore.Write(puts, removes); err != nil {
+				_ = dstStore.Close()
+				return err
+			}
+			puts.Reset()
+			log.Debugf("Copied %d metadata entries", numKeys)
+		}
+	}
+	if err := iter.Error(); err != nil {
+		_ = dstStore.Close()
+		return convertErr("failed to iterate metadata", err)
+	}
+	if err := dstStore.Write(puts, removes); err != nil {
+		_ = dstStore.Close()
+		return err
+	}
+	log.Infof("Copied %d metadata entries", numKeys)
+
+	return dstStore.Close()
+}
+
+// Migrate copies the existing database of the provided source type at the
+// source path to a new database of the provided destination type at the
+// destination path.  The supported types are the database types registered by
+// this package.  The source database is not modified.
+//
+// The migration may be interrupted by closing the provided interrupt channel,
+// in which case, as well as in the case of any other errors, the partially
+// created destination database is removed.
+//
+// Returns the following errors:
+//   - ErrDbDoesNotExist if the source database does not exist
+//   - ErrDbExists if the destination path already exists
+//   - ErrDbUnknownType if either of the types is not supported
+func Migrate(srcPath, srcType, dstPath, dstType string, network wire.CurrencyNet, interrupt <-chan struct{}) error {
+	for _, driverType := range []string{srcType, dstType} {
+		if driverType != dbType && driverType != boltDbType {
+			str := fmt.Sprintf("unsupported database type %q",
+				driverType)
+			return makeDbErr(database.ErrDbUnknownType, str, nil)
+		}
+	}
+	if fileExists(dstPath) {
+		str := fmt.Sprintf("destination %q already exists", dstPath)
+		return makeDbErr(database.ErrDbExists, str, nil)
+	}
+
+	// Open the source database which also ensures it is consistent with
+	// the flat block files.
+	srcDB, err := openDB(srcPath, srcType, network, false)
+	if err != nil {
+		return err
+	}
+	defer srcDB.Close()
+
+	if err := os.MkdirAll(dstPath, 0700); err != nil {
+		return makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+	}
+	log.Infof("Migrating %s database at %q to %s database at %q", srcType,
+		srcPath, dstType, dstPath)
+	if err := migrate(srcDB.(*db), dstPath, dstType, interrupt); err != nil {
+		_ = os.RemoveAll(dstPath)
+		return err
+	}
+
+	// Ensure the migrated database opens and is consistent with the copied
+	// flat block files.
+	dstDB, err := openDB(dstPath, dstType, network, false)
+	if err != nil {
+		_ = os.RemoveAll(dstPath)
+		return err
+	}
+	return dstDB.Close()
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ffldb_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/database/v2"
+	"github.com/decred/dcrd/database/v2/ffldb"
+	"github.com/decred/dcrd/dcrutil/v3"
+)
+
+// TestMigrate ensures migrating a database between the supported metadata
+// databases preserves the metadata and blocks.
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	srcPath := filepath.Join(os.TempDir(), "ffldb-migratesrc-v2")
+	dstPath := filepath.Join(os.TempDir(), "ffldb-migratedst-v2")
+	_ = os.RemoveAll(srcPath)
+	_ = os.RemoveAll(dstPath)
+	defer os.RemoveAll(srcPath)
+	defer os.RemoveAll(dstPath)
+
+	// Create a source database with some metadata and a block.
+	db, err := database.Create(dbType, srcPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+	bucketKey := []byte("bucket1")
+	storeValues := map[string]string{
+		"b1key1": "foo1",
+		"b1key2": "foo2",
+		"b1key3": "",
+	}
+	mainNetParams := chaincfg.MainNetParams()
+	genesisBlock := dcrutil.NewBlock(mainNetParams.GenesisBlock)
+	genesisHash := &mainNetParams.GenesisHash
+	err = db.Update(func(tx database.Tx) error {
+		bucket, err := tx.Metadata().CreateBucket(bucketKey)
+		if err != nil {
+			return err
+		}
+		for k, v := range storeValues {
+			if err := bucket.Put([]byte(k), []byte(v)); err != nil {
+				return err
+			}
+		}
+		return tx.StoreBlock(genesisBlock)
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("Update: unexpected error: %v", err)
+	}
+
+	// Ensure migrating with an unsupported type fails.
+	err = ffldb.Migrate(srcPath, dbType, dstPath, "memdb", blockDataNet, nil)
+	if !checkDbError(t, "Migrate unknown type", err, database.ErrDbUnknownType) {
+		return
+	}
+
+	// Ensure migrating a database that does not exist fails.
+	err = ffldb.Migrate(srcPath, boltDbType, dstPath, dbType, blockDataNet,
+		nil)
+	if !checkDbError(t, "Migrate no source", err, database.ErrDbDoesNotExist) {
+		return
+	}
+
+	// Migrate the database and ensure the destination has all of the
+	// original data.
+	err = ffldb.Migrate(srcPath, dbType, dstPath, boltDbType, blockDataNet,
+		nil)
+	if err != nil {
+		t.Fatalf("Migrate: unexpected error: %v", err)
+	}
+	db, err = database.Open(boltDbType, dstPath, blockDataNet)
+	if err != nil {
+		t.Fatalf("Open: unexpected error: %v", err)
+	}
+	defer db.Close()
+	err = db.View(func(tx database.Tx) error {
+		bucket := tx.Metadata().Bucket(bucketKey)
+		if bucket == nil {
+			return fmt.Errorf("Bucket: unexpected nil bucket")
+		}
+		for k, v := range storeValues {
+			gotVal := bucket.Get([]byte(k))
+			if gotVal == nil || !bytes.Equal(gotVal, []byte(v)) {
+				return fmt.Errorf("Get: key '%s' does not match "+
+					"expected value - got %q, want %q", k, gotVal,
+					v)
+			}
+		}
+
+		wantBytes, _ := genesisBlock.Bytes()
+		gotBytes, err := tx.FetchBlock(genesisHash)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(gotBytes, wantBytes) {
+			return fmt.Errorf("FetchBlock: stored block mismatch")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("View: unexpected error: %v", err)
+	}
+
+	// Ensure migrating to an existing destination fails.
+	err = ffldb.Migrate(srcPath, dbType, dstPath, boltDbType, blockDataNet,
+		nil)
+	checkDbError(t, "Migrate existing destination", err, database.ErrDbExists)
+}
@@ -138,12 +138,21 @@ func TestConvertErr(t *testing.T) {
 }
 
 // TestCornerCases ensures several corner cases which can happen when opening
-// a database and/or block files work as expected.
+// a database and/or block files work as expected for all of the supported
+// metadata databases.
 func TestCornerCases(t *testing.T) {
 	t.Parallel()
 
+	for _, driverType := range []string{dbType, boltDbType} {
+		testCornerCases(t, driverType)
+	}
+}
+
+// testCornerCases ensures several corner cases which can happen when opening
+// a database of the provided type and/or block files work as expected.
+func testCornerCases(t *testing.T, driverType string) {
 	// Create a file at the database path to force the open below to fail.
-	dbPath := filepath.Join(os.TempDir(), "ffldb-errors-v2")
+	dbPath := filepath.Join(os.TempDir(), driverType+"-errors-v2")
 	_ = os.RemoveAll(dbPath)
 	fi, err := os.Create(dbPath)
 	if err != nil {
@@ -156,7 +165,7 @@ func TestCornerCases(t *testing.T) {
 	// directory is needed.
 	testName := "openDB: fail due to file at target location"
 	wantErrCode := database.ErrDriverSpecific
-	idb, err := openDB(dbPath, blockDataNet, true)
+	idb, err := openDB(dbPath, driverType, blockDataNet, true)
 	if !checkDbError(t, testName, err, wantErrCode) {
 		if err == nil {
 			idb.Close()
@@ -168,7 +177,7 @@ func TestCornerCases(t *testing.T) {
 	// Remove the file and create the database to run tests against.  It
 	// should be successful this time.
 	_ = os.RemoveAll(dbPath)
-	idb, err = openDB(dbPath, blockDataNet, true)
+	idb, err = openDB(dbPath, driverType, blockDataNet, true)
 	if err != nil {
 		t.Errorf("openDB: unexpected error: %v", err)
 		return
@@ -191,22 +200,22 @@ func TestCornerCases(t *testing.T) {
 	}
 	_ = os.RemoveAll(filePath)
 
-	// Close the underlying leveldb database out from under the database.
-	ldb := idb.(*db).cache.ldb
-	ldb.Close()
+	// Close the underlying metadata database out from under the database.
+	metaStore := idb.(*db).cache.metaStore
+	metaStore.Close()
 
 	// Ensure initialization errors in the underlying database work as
 	// expected.
 	testName = "initDB: reinitialization"
 	wantErrCode = database.ErrDbNotOpen
-	err = initDB(ldb)
+	err = initDB(metaStore)
 	if !checkDbError(t, testName, err, wantErrCode) {
 		return
 	}
 
-	// Ensure the View handles errors in the underlying leveldb database
+	// Ensure the View handles errors in the underlying metadata database
 	// properly.
-	testName = "View: underlying leveldb error"
+	testName = "View: underlying metadata database error"
 	wantErrCode = database.ErrDbNotOpen
 	err = idb.View(func(tx database.Tx) error {
 		return nil
@@ -215,9 +224,9 @@ func TestCornerCases(t *testing.T) {
 		return
 	}
 
-	// Ensure the Update handles errors in the underlying leveldb database
+	// Ensure the Update handles errors in the underlying metadata database
 	// properly.
-	testName = "Update: underlying leveldb error"
+	testName = "Update: underlying metadata database error"
 	err = idb.Update(func(tx database.Tx) error {
 		return nil
 	})
@@ -13,7 +13,7 @@ require (
 	github.com/onsi/ginkgo v1.11.0 // indirect
 	github.com/onsi/gomega v1.8.1 // indirect
 	github.com/syndtr/goleveldb v1.0.1-0.20190923125748-758128399b1d
-	golang.org/x/sys v0.0.0-20191010194322-b09406accb47 // indirect
+	go.etcd.io/bbolt v1.3.5
 )
 
 replace (
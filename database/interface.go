@@ -476,3 +476,44 @@ type DB interface {
 	// back or committed).
 	Close() error
 }
+
+// StorageStats houses statistics about the on-disk storage used by a database.
+type StorageStats struct {
+	// MetadataFiles is the number of files that house the metadata.
+	MetadataFiles uint32
+
+	// MetadataSize is the total size in bytes of the files that house the
+	// metadata.
+	MetadataSize uint64
+
+	// MetadataLiveSize is the number of bytes of the metadata files that
+	// are used by live data.
+	MetadataLiveSize uint64
+
+	// MetadataGarbageSize is the number of bytes of the metadata files that
+	// are not used by live data and can be reclaimed by compaction.
+	MetadataGarbageSize uint64
+
+	// BlockFiles is the number of flat files that house the blocks.
+	BlockFiles uint32
+
+	// BlockFilesSize is the total size in bytes of the flat block files.
+	BlockFilesSize uint64
+}
+
+// Maintainer defines an optional interface that a DB may implement to provide
+// online storage maintenance.  Callers are expected to use a type assertion on
+// a DB to determine if the functionality is supported.
+type Maintainer interface {
+	// StorageStats returns statistics about the on-disk storage used by the
+	// database.  Calculating the statistics may require scanning all of
+	// the metadata, so it can take a while for large databases.
+	StorageStats() (*StorageStats, error)
+
+	// Compact compacts the underlying storage in order to reclaim space
+	// that is no longer used by live data, such as after a large amount of
+	// data has been removed.  The database remains usable while the
+	// compaction is performed, however, write transactions will block
+	// until it completes.
+	Compact() error
+}
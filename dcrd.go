@@ -7,9 +7,8 @@ package main
 
 import (
 	"fmt"
-	"net/http"
-	_ "net/http/pprof"
 	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
 	"runtime/pprof"
@@ -61,21 +60,21 @@ func dcrdMain() error {
 		dcrdLog.Info("File logging disabled")
 	}
 
-	// Enable http profiling server if requested.
+	// Enable http profiling server if requested.  The profiler is also
+	// provided to the RPC server so the server can be started and stopped
+	// and profiles can be captured at runtime.
+	prof := newProfiler(filepath.Join(cfg.HomeDir, defaultProfileDirname))
 	if cfg.Profile != "" {
-		go func() {
-			listenAddr := cfg.Profile
-			dcrdLog.Infof("Creating profiling server "+
-				"listening on %s", listenAddr)
-			profileRedirect := http.RedirectHandler("/debug/pprof",
-				http.StatusSeeOther)
-			http.Handle("/", profileRedirect)
-			err := http.ListenAndServe(listenAddr, nil)
-			if err != nil {
-				fatalf(err.Error())
-			}
-		}()
+		if err := prof.StartServer(cfg.Profile); err != nil {
+			dcrdLog.Errorf("Unable to start profiling server: %v", err)
+			return err
+		}
 	}
+	defer func() {
+		if prof.ServerAddr() != "" {
+			_ = prof.StopServer()
+		}
+	}()
 
 	// Write cpu profile if requested.
 	if cfg.CPUProfile != "" {
@@ -158,6 +157,22 @@ func dcrdMain() error {
 
 		return nil
 	}
+	if cfg.DropSpendIndex {
+		if err := indexers.DropSpendIndex(ctx, db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
+	if cfg.DropTimeIndex {
+		if err := indexers.DropTimeIndex(ctx, db); err != nil {
+			dcrdLog.Errorf("%v", err)
+			return err
+		}
+
+		return nil
+	}
 	if cfg.DropExistsAddrIndex {
 		if err := indexers.DropExistsAddrIndex(ctx, db); err != nil {
 			dcrdLog.Errorf("%v", err)
@@ -178,7 +193,7 @@ func dcrdMain() error {
 	// Create server and start it.
 	lifetimeNotifier.notifyStartupEvent(lifetimeEventP2PServer)
 	svr, err := newServer(ctx, cfg.Listeners, db, cfg.params.Params,
-		cfg.DataDir)
+		cfg.DataDir, prof)
 	if err != nil {
 		dcrdLog.Errorf("Unable to start server: %v", err)
 		return err
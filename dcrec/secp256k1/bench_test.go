@@ -93,6 +93,27 @@ func BenchmarkScalarMult(b *testing.B) {
 	}
 }
 
+// BenchmarkMultiScalarMult benchmarks multiplying and adding 64 points with
+// the MultiScalarMultNonConst function.
+func BenchmarkMultiScalarMult(b *testing.B) {
+	const numPoints = 64
+	scalars := make([]ModNScalar, numPoints)
+	points := make([]JacobianPoint, numPoints)
+	k := new(ModNScalar).SetHex("d74bf844b0862475103d96a611cf2d898447e288d34b360bc885cb8ce7c00575")
+	for i := 0; i < numPoints; i++ {
+		k.Square()
+		scalars[i].Set(k)
+		ScalarBaseMultNonConst(k, &points[i])
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	var result JacobianPoint
+	for i := 0; i < b.N; i++ {
+		MultiScalarMultNonConst(scalars, points, &result)
+	}
+}
+
 // BenchmarkNAF benchmarks the NAF function.
 func BenchmarkNAF(b *testing.B) {
 	k := fromHex("d74bf844b0862475103d96a611cf2d898447e288d34b360bc885cb8ce7c00575")
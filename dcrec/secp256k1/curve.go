@@ -6,8 +6,10 @@
 package secp256k1
 
 import (
+	"encoding/binary"
 	"encoding/hex"
 	"math/big"
+	"math/bits"
 )
 
 // References:
@@ -770,6 +772,193 @@ func ScalarBaseMultNonConst(k *ModNScalar, result *JacobianPoint) {
 	result.Set(&q)
 }
 
+// wnafWindow is the window size used to represent scalars in width-w
+// non-adjacent form for multi-scalar multiplication.  Larger windows reduce the
+// number of point additions at the cost of larger tables of precomputed odd
+// multiples for each point.
+const wnafWindow = 5
+
+// wnaf returns the width-w non-adjacent form (wNAF) of the provided scalar as a
+// slice of signed digits ordered from least to most significant.  Every
+// non-zero digit is odd and less than 2^(w-1) in absolute value and there is at
+// most one non-zero digit in any w consecutive digits.
+//
+// The window size must be in the range [2, 8].
+func wnaf(k *ModNScalar, w uint) []int8 {
+	// Convert the scalar to little-endian 64-bit words with an additional word
+	// to house the carry that results from subtracting negative digits.
+	kBytes := k.Bytes()
+	var words [5]uint64
+	for i := 0; i < 4; i++ {
+		words[i] = binary.BigEndian.Uint64(kBytes[24-i*8 : 32-i*8])
+	}
+
+	window := uint64(1) << w
+	mask := window - 1
+	halfWindow := window >> 1
+	digits := make([]int8, 0, 257)
+	for words[0]|words[1]|words[2]|words[3]|words[4] != 0 {
+		// Select an odd digit in the range (-2^(w-1), 2^(w-1)) that clears the
+		// low w bits of k when subtracted from it.
+		var digit int8
+		if words[0]&1 == 1 {
+			lowBits := words[0] & mask
+			if lowBits < halfWindow {
+				digit = int8(lowBits)
+				words[0] -= lowBits
+			} else {
+				digit = -int8(window - lowBits)
+				var carry uint64
+				words[0], carry = bits.Add64(words[0], window-lowBits, 0)
+				for i := 1; i < len(words) && carry != 0; i++ {
+					words[i], carry = bits.Add64(words[i], 0, carry)
+				}
+			}
+		}
+		digits = append(digits, digit)
+
+		// k >>= 1
+		for i := 0; i < len(words)-1; i++ {
+			words[i] = words[i]>>1 | words[i+1]<<63
+		}
+		words[len(words)-1] >>= 1
+	}
+	return digits
+}
+
+// batchToAffine converts the provided Jacobian points to affine coordinates in
+// the same way as ToAffine while only performing a single field inversion for
+// all of them by making use of Montgomery's trick.
+//
+// NOTE: The points must not be the point at infinity.
+func batchToAffine(points []*JacobianPoint) {
+	if len(points) == 0 {
+		return
+	}
+
+	// Calculate the running products of the z values such that
+	// products[i] = z_0 * z_1 * ... * z_i.
+	products := make([]FieldVal, len(points))
+	products[0].Set(&points[0].Z)
+	for i := 1; i < len(points); i++ {
+		products[i].Mul2(&products[i-1], &points[i].Z)
+	}
+
+	// Invert the product of all of the z values and then work backwards to
+	// calculate the inverse of each individual z value while removing it from
+	// the inverted product.
+	var zInv, tempZ FieldVal
+	inv := new(FieldVal).Set(&products[len(points)-1]).Inverse()
+	for i := len(points) - 1; i >= 0; i-- {
+		p := points[i]
+		if i > 0 {
+			zInv.Mul2(inv, &products[i-1]) // zInv = Z_i^-1
+			inv.Mul(&p.Z)                  // inv = (z_0 * ... * z_(i-1))^-1
+		} else {
+			zInv.Set(inv)
+		}
+		tempZ.SquareVal(&zInv)    // tempZ = Z^-2
+		p.X.Mul(&tempZ)           // X = X/Z^2 (mag: 1)
+		p.Y.Mul(tempZ.Mul(&zInv)) // Y = Y/Z^3 (mag: 1)
+		p.Z.SetInt(1)             // Z = 1 (mag: 1)
+
+		// Normalize the x and y values.
+		p.X.Normalize()
+		p.Y.Normalize()
+	}
+}
+
+// MultiScalarMultNonConst computes the sum of the products of each of the
+// provided scalars with its corresponding point, k1*P1 + k2*P2 + ... + kn*Pn,
+// and stores the result in the provided Jacobian point in *non-constant* time.
+//
+// This is significantly faster than multiplying each point individually and
+// adding the results since the point doublings are shared among all of the
+// products by interleaving the width-w non-adjacent form representations of
+// the scalars.
+//
+// The number of scalars and points must be the same or the function will
+// panic.
+//
+// NOTE: The points must be normalized for this function to return the correct
+// result.  The resulting point will be normalized.
+func MultiScalarMultNonConst(scalars []ModNScalar, points []JacobianPoint, result *JacobianPoint) {
+	if len(scalars) != len(points) {
+		panic("the number of scalars and points must be the same")
+	}
+
+	// Convert each scalar to wNAF and precompute the odd multiples of its
+	// associated point up to the maximum digit, P, 3P, 5P, ..., (2^(w-1)-1)P.
+	const tableSize = 1 << (wnafWindow - 2)
+	type multiple struct {
+		digits []int8
+		table  [tableSize]JacobianPoint
+	}
+	multiples := make([]multiple, len(points))
+	var maxDigits int
+	for i := range points {
+		m := &multiples[i]
+		m.digits = wnaf(&scalars[i], wnafWindow)
+		if len(m.digits) == 0 {
+			continue
+		}
+		if len(m.digits) > maxDigits {
+			maxDigits = len(m.digits)
+		}
+
+		var twoP JacobianPoint
+		m.table[0].Set(&points[i])
+		DoubleNonConst(&points[i], &twoP)
+		for j := 1; j < tableSize; j++ {
+			AddNonConst(&m.table[j-1], &twoP, &m.table[j])
+		}
+	}
+
+	// Convert all of the precomputed points to affine since adding points with
+	// a z value of one is faster.  The inversions are batched via Montgomery's
+	// trick so that only a single inversion is needed for all of them.
+	tables := make([]*JacobianPoint, 0, len(multiples)*tableSize)
+	for i := range multiples {
+		m := &multiples[i]
+		if len(m.digits) == 0 {
+			continue
+		}
+		for j := range m.table {
+			if !m.table[j].Z.IsZero() {
+				tables = append(tables, &m.table[j])
+			}
+		}
+	}
+	batchToAffine(tables)
+
+	// Add left-to-right while sharing the doublings among all of the products.
+	var q, negP JacobianPoint
+	for i := maxDigits - 1; i >= 0; i-- {
+		// Q = 2 * Q
+		DoubleNonConst(&q, &q)
+
+		for j := range multiples {
+			m := &multiples[j]
+			if i >= len(m.digits) {
+				continue
+			}
+			switch digit := m.digits[i]; {
+			case digit > 0:
+				AddNonConst(&q, &m.table[digit>>1], &q)
+
+			case digit < 0:
+				// The group law for elliptic curves states that -P(x, y) is
+				// P(x, -y).
+				negP.Set(&m.table[(-digit)>>1])
+				negP.Y.Negate(1).Normalize()
+				AddNonConst(&q, &negP, &q)
+			}
+		}
+	}
+
+	result.Set(&q)
+}
+
 // naf takes a positive integer k and returns the Non-Adjacent Form (NAF) as two
 // byte slices.  The first is where 1s will be.  The second is where -1s will
 // be.  NAF is convenient in that on average, only 1/3rd of its values are
@@ -625,6 +625,90 @@ func TestScalarMultRand(t *testing.T) {
 	}
 }
 
+// TestWNAFRand ensures the width-w non-adjacent form of random scalars has the
+// expected properties and represents the original scalar.
+func TestWNAFRand(t *testing.T) {
+	for w := uint(2); w <= 8; w++ {
+		for i := 0; i < 256; i++ {
+			data := make([]byte, 32)
+			if _, err := rand.Read(data); err != nil {
+				t.Fatalf("failed to read random data at %d", i)
+			}
+			var k ModNScalar
+			k.SetByteSlice(data)
+			want := new(big.Int).SetBytes(data)
+			want.Mod(want, curveParams.N)
+
+			digits := wnaf(&k, w)
+			got := new(big.Int)
+			lastNonZero := -int(w)
+			for j := len(digits) - 1; j >= 0; j-- {
+				digit := int(digits[j])
+				got.Lsh(got, 1)
+				got.Add(got, big.NewInt(int64(digit)))
+				if digit == 0 {
+					continue
+				}
+				if digit&1 == 0 || digit >= 1<<(w-1) || digit <= -(1<<(w-1)) {
+					t.Fatalf("w=%d, k=%x: invalid digit %d", w, data, digit)
+				}
+				if lastNonZero-j < int(w) && lastNonZero >= 0 {
+					t.Fatalf("w=%d, k=%x: non-zero digits %d and %d are "+
+						"too close", w, data, lastNonZero, j)
+				}
+				lastNonZero = j
+			}
+			if got.Cmp(want) != 0 {
+				t.Fatalf("w=%d: wrong wNAF for %x -- got %x, want %x", w,
+					data, got, want)
+			}
+		}
+	}
+}
+
+// TestMultiScalarMultRand ensures multi-scalar multiplication of random scalars
+// and points produces the same result as multiplying each point individually
+// and adding the results.
+func TestMultiScalarMultRand(t *testing.T) {
+	for _, numPoints := range []int{0, 1, 2, 5, 32} {
+		scalars := make([]ModNScalar, numPoints)
+		points := make([]JacobianPoint, numPoints)
+		var want JacobianPoint
+		for i := 0; i < numPoints; i++ {
+			data := make([]byte, 64)
+			if _, err := rand.Read(data); err != nil {
+				t.Fatalf("failed to read random data at %d", i)
+			}
+			var pointScalar ModNScalar
+			pointScalar.SetByteSlice(data[:32])
+			ScalarBaseMultNonConst(&pointScalar, &points[i])
+
+			// Make every other point use a non-unit z coordinate.
+			if i%2 == 1 {
+				DoubleNonConst(&points[i], &points[i])
+			}
+
+			scalars[i].SetByteSlice(data[32:])
+			if i == 2 {
+				scalars[i].Zero()
+			}
+			var product JacobianPoint
+			ScalarMultNonConst(&scalars[i], &points[i], &product)
+			AddNonConst(&want, &product, &want)
+		}
+
+		var got JacobianPoint
+		MultiScalarMultNonConst(scalars, points, &got)
+		got.ToAffine()
+		want.ToAffine()
+		if !got.IsStrictlyEqual(&want) {
+			t.Fatalf("%d points: mismatched result -- got (%v, %v, %v), "+
+				"want (%v, %v, %v)", numPoints, got.X, got.Y, got.Z, want.X,
+				want.Y, want.Z)
+		}
+	}
+}
+
 func TestSplitK(t *testing.T) {
 	tests := []struct {
 		k      string
@@ -0,0 +1,92 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// batchEntry houses a signature along with the hash and public key it is
+// associated with for the purposes of batch verification.
+type batchEntry struct {
+	sig    *Signature
+	hash   []byte
+	pubKey *secp256k1.PublicKey
+}
+
+// BatchVerifier houses signatures along with the hashes and public keys they
+// are associated with in order to verify them together as a batch.
+//
+// Unlike Schnorr signatures, the point equations of ECDSA signatures can't be
+// combined since the signatures only commit to the x coordinate of their nonce
+// points modulo the group order.  Instead, verifying a batch amortizes the
+// modular inversions of the S values that are otherwise needed for every
+// signature into a single inversion for the entire batch.
+//
+// The zero value is an empty batch that is ready to use.
+//
+// The verifier is NOT safe for concurrent access.
+type BatchVerifier struct {
+	entries []batchEntry
+}
+
+// Add adds the provided signature along with the hash and public key it is
+// associated with to the batch.
+func (v *BatchVerifier) Add(sig *Signature, hash []byte, pubKey *secp256k1.PublicKey) {
+	v.entries = append(v.entries, batchEntry{sig, hash, pubKey})
+}
+
+// Len returns the number of signatures in the batch.
+func (v *BatchVerifier) Len() int {
+	return len(v.entries)
+}
+
+// Verify returns whether or not all of the signatures in the batch are valid
+// for their associated hashes and public keys.  It returns true for an empty
+// batch.
+//
+// Note that a failed batch does not identify which signatures are invalid, so
+// callers that need to know must verify the signatures individually.
+func (v *BatchVerifier) Verify() bool {
+	if len(v.entries) == 0 {
+		return true
+	}
+
+	// Fail if R and S are not in [1, N-1] for any of the signatures and
+	// calculate the running products of the S values such that
+	// products[i] = S_0 * S_1 * ... * S_i.
+	products := make([]secp256k1.ModNScalar, len(v.entries))
+	for i := range v.entries {
+		sig := v.entries[i].sig
+		if sig.r.IsZero() || sig.s.IsZero() {
+			return false
+		}
+		if i == 0 {
+			products[i].Set(&sig.s)
+			continue
+		}
+		products[i].Mul2(&products[i-1], &sig.s)
+	}
+
+	// Invert the product of all of the S values and then work backwards to
+	// calculate the inverse of each individual S value while removing it from
+	// the inverted product.  Each signature is verified with its inverse along
+	// the way.
+	var w secp256k1.ModNScalar
+	inv := new(secp256k1.ModNScalar).InverseValNonConst(&products[len(v.entries)-1])
+	for i := len(v.entries) - 1; i >= 0; i-- {
+		entry := &v.entries[i]
+		if i > 0 {
+			w.Mul2(inv, &products[i-1])
+			inv.Mul(&entry.sig.s)
+		} else {
+			w.Set(inv)
+		}
+		if !entry.sig.verifyWithInverse(entry.hash, entry.pubKey, &w) {
+			return false
+		}
+	}
+	return true
+}
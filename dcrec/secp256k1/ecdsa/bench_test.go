@@ -8,6 +8,7 @@ package ecdsa
 import (
 	"encoding/hex"
 	"math/big"
+	"math/rand"
 	"testing"
 
 	"github.com/decred/dcrd/dcrec/secp256k1/v3"
@@ -192,3 +193,36 @@ func BenchmarkRecoverCompact(b *testing.B) {
 		_, _, _ = RecoverCompact(compactSig, msgHash)
 	}
 }
+
+// BenchmarkBatchVerify benchmarks how long it takes to verify a batch of 64
+// ECDSA signatures.
+func BenchmarkBatchVerify(b *testing.B) {
+	const batchSize = 64
+	entries := randBatchTestEntries(b, rand.New(rand.NewSource(1)), batchSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var v BatchVerifier
+		for _, entry := range entries {
+			v.Add(entry.sig, entry.hash, entry.pubKey)
+		}
+		v.Verify()
+	}
+}
+
+// BenchmarkBatchVerifyIndividually benchmarks how long it takes to verify the
+// same 64 ECDSA signatures as BenchmarkBatchVerify individually for
+// comparison.
+func BenchmarkBatchVerifyIndividually(b *testing.B) {
+	const batchSize = 64
+	entries := randBatchTestEntries(b, rand.New(rand.NewSource(1)), batchSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, entry := range entries {
+			entry.sig.Verify(entry.hash, entry.pubKey)
+		}
+	}
+}
@@ -225,17 +225,31 @@ func (sig *Signature) Verify(hash []byte, pubKey *secp256k1.PublicKey) bool {
 		return false
 	}
 
+	// Step 3.
+	//
+	// w = S^-1 mod N
+	//
+	// Note that this is calculated prior to step 2 since the remaining steps
+	// are shared with batch verification which calculates the inverses of
+	// all of the S values in the batch together.
+	w := new(secp256k1.ModNScalar).InverseValNonConst(&sig.s)
+
+	// Steps 2 and 4-10.
+	return sig.verifyWithInverse(hash, pubKey, w)
+}
+
+// verifyWithInverse performs steps 2 and 4-10 of the modified ECDSA
+// verification algorithm described in Verify given the modular multiplicative
+// inverse w of the S value of the signature.
+//
+// NOTE: The caller MUST ensure R and S are in the range [1, N-1].
+func (sig *Signature) verifyWithInverse(hash []byte, pubKey *secp256k1.PublicKey, w *secp256k1.ModNScalar) bool {
 	// Step 2.
 	//
 	// e = H(m)
 	var e secp256k1.ModNScalar
 	e.SetByteSlice(hash)
 
-	// Step 3.
-	//
-	// w = S^-1 mod N
-	w := new(secp256k1.ModNScalar).InverseValNonConst(&sig.s)
-
 	// Step 4.
 	//
 	// u1 = e * w mod N
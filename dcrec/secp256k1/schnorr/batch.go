@@ -0,0 +1,202 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"encoding/binary"
+
+	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// batchCoefficientSize is the size of the pseudorandom coefficients used to
+// combine the signatures in a batch.  The probability of an invalid batch
+// verifying is at most 2^-128 with coefficients of this size.
+const batchCoefficientSize = 16
+
+// batchEntry houses a signature along with the hash and public key it is
+// associated with for the purposes of batch verification.
+type batchEntry struct {
+	sig    *Signature
+	hash   []byte
+	pubKey *secp256k1.PublicKey
+}
+
+// BatchVerifier houses signatures along with the hashes and public keys they
+// are associated with in order to verify them together as a batch.  Verifying
+// a batch is significantly faster than verifying each of the signatures
+// individually since the point multiplications are combined into a single
+// multi-scalar multiplication.
+//
+// The zero value is an empty batch that is ready to use.
+//
+// The verifier is NOT safe for concurrent access.
+type BatchVerifier struct {
+	entries []batchEntry
+}
+
+// Add adds the provided signature along with the hash and public key it is
+// associated with to the batch.
+func (v *BatchVerifier) Add(sig *Signature, hash []byte, pubKey *secp256k1.PublicKey) {
+	v.entries = append(v.entries, batchEntry{sig, hash, pubKey})
+}
+
+// Len returns the number of signatures in the batch.
+func (v *BatchVerifier) Len() int {
+	return len(v.entries)
+}
+
+// batchSeed returns a hash that commits to every signature, public key, and
+// hash in the batch.  It is used to derive the coefficients that combine the
+// signatures so they can't be predicted prior to choosing the batch contents.
+func (v *BatchVerifier) batchSeed() [blake256.Size]byte {
+	hasher := blake256.New()
+	for i := range v.entries {
+		entry := &v.entries[i]
+		hasher.Write(entry.sig.Serialize())
+		hasher.Write(entry.pubKey.SerializeCompressed())
+		hasher.Write(entry.hash)
+	}
+	var seed [blake256.Size]byte
+	copy(seed[:], hasher.Sum(nil))
+	return seed
+}
+
+// Verify returns whether or not all of the signatures in the batch are valid
+// for their associated hashes and public keys.  It returns true for an empty
+// batch.
+//
+// Note that a failed batch does not identify which signatures are invalid, so
+// callers that need to know must verify the signatures individually.
+func (v *BatchVerifier) Verify() bool {
+	// There is nothing to gain from batching a single signature.
+	switch len(v.entries) {
+	case 0:
+		return true
+	case 1:
+		entry := &v.entries[0]
+		return entry.sig.Verify(entry.hash, entry.pubKey)
+	}
+
+	// The algorithm for verifying a batch of EC-Schnorr-DCRv0 signatures is
+	// a combination of the individual verification algorithm described in
+	// README.md with random linear combinations.  It is reproduced here for
+	// reference:
+	//
+	// G = curve generator
+	// u = number of signatures in the batch
+	// Q_i = public keys
+	// m_i = messages
+	// r_i, s_i = signatures
+	//
+	// 1. Fail if any m_i is not 32 bytes
+	// 2. Fail if any Q_i is not a point on the curve
+	// 3. e_i = BLAKE-256(r_i || m_i) (Ensure r_i is padded to 32 bytes)
+	// 4. Fail if any e_i >= n
+	// 5. Fail if there is not a point R_i with x coordinate r_i and an even y
+	//    coordinate
+	// 6. a_0 = 1 and a_i = 128-bit coefficients derived from a hash that
+	//    commits to the entire batch for i = 1..u-1
+	// 7. Verified if (a_0*s_0 + ... + a_(u-1)*s_(u-1))*G +
+	//    a_0*e_0*Q_0 + ... + a_(u-1)*e_(u-1)*Q_(u-1) -
+	//    a_0*R_0 - ... - a_(u-1)*R_(u-1) is the point at infinity
+	//
+	// The individual algorithm verifies R_i = s_i*G + e_i*Q_i has an even y
+	// coordinate and an x coordinate equal to r_i.  That is equivalent to
+	// verifying that a_i*(s_i*G + e_i*Q_i - R_i) is the point at infinity with
+	// R_i as defined in step 5, so all of the signatures are valid when the sum
+	// of those points is the point at infinity, except with probability at
+	// most 2^-128 for an invalid batch due to the coefficients.
+	seed := v.batchSeed()
+	var coefficientInput [blake256.Size + 4]byte
+	copy(coefficientInput[:], seed[:])
+
+	numEntries := len(v.entries)
+	scalars := make([]secp256k1.ModNScalar, 0, numEntries*2)
+	points := make([]secp256k1.JacobianPoint, 0, numEntries*2)
+	var sumS secp256k1.ModNScalar
+	for i := range v.entries {
+		entry := &v.entries[i]
+
+		// Step 1.
+		//
+		// Fail if any m_i is not 32 bytes
+		if len(entry.hash) != scalarSize {
+			return false
+		}
+
+		// Step 2.
+		//
+		// Fail if any Q_i is not a point on the curve
+		if !entry.pubKey.IsOnCurve() {
+			return false
+		}
+
+		// Step 3.
+		//
+		// e_i = BLAKE-256(r_i || m_i) (Ensure r_i is padded to 32 bytes)
+		var commitmentInput [scalarSize * 2]byte
+		entry.sig.r.PutBytesUnchecked(commitmentInput[0:scalarSize])
+		copy(commitmentInput[scalarSize:], entry.hash)
+		commitment := blake256.Sum256(commitmentInput[:])
+
+		// Step 4.
+		//
+		// Fail if any e_i >= n
+		var e secp256k1.ModNScalar
+		if overflow := e.SetBytes(&commitment); overflow != 0 {
+			return false
+		}
+
+		// Step 5.
+		//
+		// Fail if there is not a point R_i with x coordinate r_i and an even y
+		// coordinate
+		//
+		// Note that the negation of R_i, which is the point with the same x
+		// coordinate and the odd y coordinate, is calculated directly since it
+		// is what is needed in step 7.
+		var negR secp256k1.JacobianPoint
+		negR.X.Set(&entry.sig.r)
+		if !secp256k1.DecompressY(&negR.X, true, &negR.Y) {
+			return false
+		}
+		negR.Y.Normalize()
+		negR.Z.SetInt(1)
+
+		// Step 6.
+		//
+		// a_0 = 1 and a_i = 128-bit coefficients derived from a hash that
+		// commits to the entire batch for i = 1..u-1
+		var a secp256k1.ModNScalar
+		if i == 0 {
+			a.SetInt(1)
+		} else {
+			binary.BigEndian.PutUint32(coefficientInput[blake256.Size:],
+				uint32(i))
+			coefficientHash := blake256.Sum256(coefficientInput[:])
+			a.SetByteSlice(coefficientHash[:batchCoefficientSize])
+		}
+
+		// Accumulate the terms needed in step 7.
+		var Q secp256k1.JacobianPoint
+		entry.pubKey.AsJacobian(&Q)
+		var aS secp256k1.ModNScalar
+		sumS.Add(aS.Mul2(&a, &entry.sig.s))
+		scalars = append(scalars, *e.Mul(&a), a)
+		points = append(points, Q, negR)
+	}
+
+	// Step 7.
+	//
+	// Verified if (a_0*s_0 + ... + a_(u-1)*s_(u-1))*G +
+	// a_0*e_0*Q_0 + ... + a_(u-1)*e_(u-1)*Q_(u-1) -
+	// a_0*R_0 - ... - a_(u-1)*R_(u-1) is the point at infinity
+	var sG, sum, result secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&sumS, &sG)
+	secp256k1.MultiScalarMultNonConst(scalars, points, &sum)
+	secp256k1.AddNonConst(&sG, &sum, &result)
+	return (result.X.IsZero() && result.Y.IsZero()) || result.Z.IsZero()
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+)
+
+// batchTestEntry houses a signature along with the hash and public key it is
+// associated with for use in the batch verification tests.
+type batchTestEntry struct {
+	sig    *Signature
+	hash   []byte
+	pubKey *secp256k1.PublicKey
+}
+
+// randBatchTestEntries returns the provided number of valid signatures for
+// randomly-generated private keys and hashes using the provided rng.
+func randBatchTestEntries(t testing.TB, rng *rand.Rand, num int) []batchTestEntry {
+	entries := make([]batchTestEntry, 0, num)
+	for i := 0; i < num; i++ {
+		var buf [32]byte
+		if _, err := rng.Read(buf[:]); err != nil {
+			t.Fatalf("failed to read random private key: %v", err)
+		}
+		var privKeyScalar secp256k1.ModNScalar
+		privKeyScalar.SetBytes(&buf)
+		privKey := secp256k1.NewPrivateKey(&privKeyScalar)
+
+		hash := make([]byte, 32)
+		if _, err := rng.Read(hash); err != nil {
+			t.Fatalf("failed to read random hash: %v", err)
+		}
+		sig, err := Sign(privKey, hash)
+		if err != nil {
+			t.Fatalf("failed to sign\nprivate key: %x\nhash: %x",
+				privKey.Serialize(), hash)
+		}
+		entries = append(entries, batchTestEntry{sig, hash, privKey.PubKey()})
+	}
+	return entries
+}
+
+// TestBatchVerify ensures batches of valid signatures verify and batches that
+// contain any invalid signatures do not.
+func TestBatchVerify(t *testing.T) {
+	// Use a unique random seed each test instance and log it if the tests fail.
+	seed := time.Now().Unix()
+	rng := rand.New(rand.NewSource(seed))
+	defer func(t *testing.T, seed int64) {
+		if t.Failed() {
+			t.Logf("random seed: %d", seed)
+		}
+	}(t, seed)
+
+	// Ensure an empty batch verifies.
+	var empty BatchVerifier
+	if !empty.Verify() {
+		t.Fatal("empty batch did not verify")
+	}
+
+	for _, batchSize := range []int{1, 2, 3, 16, 64} {
+		entries := randBatchTestEntries(t, rng, batchSize)
+		var v BatchVerifier
+		for _, entry := range entries {
+			v.Add(entry.sig, entry.hash, entry.pubKey)
+		}
+		if v.Len() != batchSize {
+			t.Fatalf("batch size %d: unexpected length %d", batchSize,
+				v.Len())
+		}
+		if !v.Verify() {
+			t.Fatalf("batch size %d: valid batch did not verify", batchSize)
+		}
+
+		// Ensure the batch fails to verify when a random bit in one of the
+		// signatures is changed, one of the hashes is changed, or two of the
+		// public keys are swapped.
+		badIdx := rng.Intn(batchSize)
+		badSigBytes := entries[badIdx].sig.Serialize()
+		badSigBytes[rng.Intn(len(badSigBytes))] ^= 1 << uint(rng.Intn(7))
+		badSig, err := ParseSignature(badSigBytes)
+		if err != nil {
+			t.Fatalf("failed to create bad signature: %v", err)
+		}
+		badHash := make([]byte, len(entries[badIdx].hash))
+		copy(badHash, entries[badIdx].hash)
+		badHash[rng.Intn(len(badHash))] ^= 1 << uint(rng.Intn(7))
+
+		mutations := []struct {
+			name   string
+			mutate func(entries []batchTestEntry)
+		}{{
+			name: "bad signature",
+			mutate: func(entries []batchTestEntry) {
+				entries[badIdx].sig = badSig
+			},
+		}, {
+			name: "bad hash",
+			mutate: func(entries []batchTestEntry) {
+				entries[badIdx].hash = badHash
+			},
+		}, {
+			name: "short hash",
+			mutate: func(entries []batchTestEntry) {
+				entries[badIdx].hash = entries[badIdx].hash[:31]
+			},
+		}, {
+			name: "swapped public keys",
+			mutate: func(entries []batchTestEntry) {
+				if len(entries) < 2 {
+					entries[0].pubKey = randBatchTestEntries(t, rng, 1)[0].pubKey
+					return
+				}
+				entries[0].pubKey, entries[1].pubKey = entries[1].pubKey,
+					entries[0].pubKey
+			},
+		}}
+		for _, test := range mutations {
+			badEntries := make([]batchTestEntry, len(entries))
+			copy(badEntries, entries)
+			test.mutate(badEntries)
+
+			var v BatchVerifier
+			for _, entry := range badEntries {
+				v.Add(entry.sig, entry.hash, entry.pubKey)
+			}
+			if v.Verify() {
+				t.Fatalf("batch size %d: %s: invalid batch verified",
+					batchSize, test.name)
+			}
+		}
+	}
+}
@@ -6,6 +6,7 @@ package schnorr
 
 import (
 	"encoding/hex"
+	"math/rand"
 	"testing"
 
 	"github.com/decred/dcrd/dcrec/secp256k1/v3"
@@ -113,3 +114,36 @@ func BenchmarkSigSerialize(b *testing.B) {
 		sig.Serialize()
 	}
 }
+
+// BenchmarkBatchVerify benchmarks how long it takes to verify a batch of 64
+// Schnorr signatures.
+func BenchmarkBatchVerify(b *testing.B) {
+	const batchSize = 64
+	entries := randBatchTestEntries(b, rand.New(rand.NewSource(1)), batchSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var v BatchVerifier
+		for _, entry := range entries {
+			v.Add(entry.sig, entry.hash, entry.pubKey)
+		}
+		v.Verify()
+	}
+}
+
+// BenchmarkBatchVerifyIndividually benchmarks how long it takes to verify the
+// same 64 Schnorr signatures as BenchmarkBatchVerify individually for
+// comparison.
+func BenchmarkBatchVerifyIndividually(b *testing.B) {
+	const batchSize = 64
+	entries := randBatchTestEntries(b, rand.New(rand.NewSource(1)), batchSize)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, entry := range entries {
+			entry.sig.Verify(entry.hash, entry.pubKey)
+		}
+	}
+}
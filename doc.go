@@ -35,32 +35,118 @@ Application Options:
                             listen interfaces via --listen
       --listen=             Add an interface/port to listen for connections
                             (default all interfaces port: 9108, testnet: 19108)
+                            -- Per-listener settings may follow the address as
+                            comma-separated options: net=ipv4|ipv6|onion to
+                            only accept the given network types,
+                            whitelist=<ip or network>, maxinbound=<n>, and
+                            externalip=<address to advertise>
       --maxsameip=          Max number of connections with the same IP -- 0 to
                             disable (default: 5)
       --maxpeers=           Max number of inbound and outbound peers (125)
+      --maxuploadtarget=    Max number of MiB to upload to peers over a rolling
+                            24 hour window -- historical blocks are no longer
+                            served to non-whitelisted peers when the target is
+                            approached while new blocks and transactions
+                            continue to be relayed (0 = unlimited) (default: 0)
       --nobanning           Disable banning of misbehaving peers
       --banduration=        How long to ban misbehaving peers.  Valid time units
                             are {s, m, h}.  Minimum 1 second (24h0m0s)
       --banthreshold=       Maximum allowed ban score before disconnecting and
                             banning misbehaving peers.
-      --whitelist=          Add an IP network or IP that will not be banned.
-                            (eg. 192.168.1.0/24 or ::1)
+      --whitelist=          Add an IP network or IP that will not be banned or
+                            limited by the peer and RPC connection and request
+                            limits. (eg. 192.168.1.0/24 or ::1)
+      --allowuseragent=     Only allow peers with a user agent that matches the
+                            provided pattern -- may be specified multiple
+                            times.  Patterns may contain * wildcards and may be
+                            suffixed with @<n> to also require at least
+                            protocol version n (eg.
+                            /dcrwire:0.4.0/dcrd:1.6.*@8)
+      --denyuseragent=      Refuse peers with a user agent that matches the
+                            provided pattern -- may be specified multiple
+                            times.  Patterns may contain * wildcards (eg.
+                            *BrokenNode*)
+      --msgratelimit=       Limit the rate of messages with a command received
+                            from each peer as
+                            <command>:<messages>/<interval> -- may be
+                            specified multiple times.  Peers that exceed the
+                            limit are disconnected.  A limit of 0 messages
+                            removes the default limit for the command
+                            (default: addr:10/1m)
+      --msgsizelimit=       Limit the payload size of messages with a command
+                            received from peers as <command>:<bytes> -- may be
+                            specified multiple times.  Peers that exceed the
+                            limit are disconnected (eg. headers:100000)
+      --asmap=              Path to an asmap file used to group peer addresses
+                            by the autonomous system they are announced by
+                            instead of by network prefix to make eclipse
+                            attacks harder
+      --addrblocklist=      Path to a file of IP networks (eg.
+                            192.168.1.0/24) and onion address patterns (eg.
+                            abc*.onion), one per line, whose peer addresses
+                            are never stored -- may be specified multiple times
+      --peersfileformat=    Format used to save known peer addresses {json,
+                            binary} -- binary is faster to load and save when
+                            many addresses are known (default: json)
   -u, --rpcuser=            Username for RPC connections
   -P, --rpcpass=            Password for RPC connections
       --rpclimituser=       Username for limited RPC connections
       --rpclimitpass=       Password for limited RPC connections
+      --rpcauth=            Add RPC credentials bound to a permission scope in
+                            the form user:pass:scope where scope is one of
+                            admin, readonly, or mining
       --rpclisten=          Add an interface/port to listen for RPC connections
                             (default port: 9109, testnet: 19109)
+      --grpclisten=         Add an interface/port to listen for gRPC connections
+                            (default port: 9119, testnet: 19119) -- NOTE: The
+                            gRPC server is disabled unless at least one
+                            interface is specified and requires the RPC server
       --rpccert=            File containing the certificate file
       --rpckey=             File containing the certificate key
+      --rpcclientcafile=    File containing the certificate authorities used to
+                            verify TLS client certificates -- enables
+                            authenticating RPC clients by client certificate as
+                            an alternative to passwords
+      --rpcclientcert=      Grant a permission scope to the TLS client
+                            certificates with the given subject common name in
+                            the form commonname:scope where scope is one of
+                            admin, readonly, or mining -- all verified client
+                            certificates are granted admin when none are
+                            specified
       --tlscurve=           Curve to use when generating the TLS keypair
-                            (default: P-521)
+                            {P-256, P-521, Ed25519} (default: P-521)
+      --rpccertvalidity=    The duration generated RPC certificates are valid
+                            for. Valid time units are {s,m,h}.
+                            (default: 87600h)
+      --rpccertregen        Regenerate the RPC certificate and key on startup
+                            when the certificate has expired or is about to
+                            expire
+      --rpccertnolocalhosts Do not include the host name, local interface
+                            addresses, and localhost in generated RPC
+                            certificates so they are only valid for the names
+                            and addresses specified by altdnsnames
       --rpcmaxclients=      Max number of RPC clients for standard connections
                             (10)
       --rpcmaxwebsockets=   Max number of RPC websocket connections (25)
+      --rpcratelimit=       Max average number of RPC requests per second
+                            allowed from each client IP -- 0 to disable
+      --rpcrateburst=       Max number of RPC requests each client IP may make
+                            in a burst before being limited by --rpcratelimit
+                            (20)
+      --rpcmaxclientreqs=   Max number of concurrent RPC requests allowed from
+                            each client IP -- 0 to disable
+      --metricslisten=      Add an interface/port to serve Prometheus metrics
+                            over HTTP at /metrics (default port: 9120, testnet:
+                            19120) -- NOTE: The metrics server is disabled
+                            unless at least one interface is specified and does
+                            not require authentication
+      --rest                Enable the read-only REST interface on the RPC
+                            listeners under /rest/ -- NOTE: Requests must use
+                            the same authentication as RPC connections
       --norpc               Disable built-in RPC server -- NOTE: The RPC server
-                            is disabled by default if no rpcuser/rpcpass or
-                            rpclimituser/rpclimitpass is specified
+                            is disabled by default if no rpcuser/rpcpass,
+                            rpclimituser/rpclimitpass, rpcauth, or
+                            rpcclientcafile is specified
       --notls               Disable TLS for the RPC server -- NOTE: This is only
                             allowed if the RPC server is bound to localhost
       --dialtimeout=        How long to wait for TCP connection completion.
@@ -69,6 +155,10 @@ Application Options:
       --nodnsseed           Disable DNS seeding for peers
       --externalip=         Add an ip to the list of local addresses we claim to
                             listen on to peers
+      --stunserver=         Add a STUN server (host:port) to query for the
+                            public IPv4 and IPv6 addresses to advertise --
+                            NOTE: Not used when external IPs are specified or
+                            a proxy is used
       --proxy=              Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)
       --proxyuser=          Username for proxy server
       --proxypass=          Password for proxy server
@@ -79,6 +169,9 @@ Application Options:
       --noonion             Disable connecting to tor hidden services
       --torisolation        Enable Tor stream isolation by randomizing user
                             credentials for each connection.
+      --cjdns               Treat addresses in the cjdns IPv6 address block
+                            (fc00::/8) as reachable -- only enable when this
+                            node is connected to a cjdns network
       --testnet             Use the test network
       --simnet              Use the simulation test network
       --regnet              Use the regression test network
@@ -104,6 +197,15 @@ Application Options:
       --limitfreerelay=     Limit relay of transactions with no transaction fee
                             to the given amount in thousands of bytes per
                             minute (15)
+      --freerelaywindow=    Approximate window over which transactions with no
+                            transaction fee are tracked for the limitfreerelay
+                            rate limit.  Valid time units are {s, m, h}.
+                            Minimum 1 second (10m0s)
+      --whitelistfreerelay  Exempt transactions with no transaction fee relayed
+                            by whitelisted peers from rate limiting
+      --freerelaywhitelist= Add an IP network or IP of whitelisted peers whose
+                            transactions with no transaction fee are exempt
+                            from rate limiting. (eg. 192.168.1.0/24 or ::1)
       --norelaypriority     Do not require free or low-fee transactions to have
                             high priority for relaying
       --maxorphantx=        Max number of orphan transactions to keep in memory
@@ -129,14 +231,16 @@ Application Options:
       --allowoldvotes       Enable the addition of very old votes to the mempool
       --sigcachemaxsize=    The maximum number of entries in the signature
                             verification cache.
-      --blocksonly          Do not accept transactions from remote peers.
+      --blocksonly          Disable transaction relay, which is announced to
+                            peers during the handshake, while still receiving
+                            blocks and votes
       --acceptnonstd        Accept and relay non-standard transactions to
                             the network regardless of the default settings
                             for the active network.
       --rejectnonstd        Reject non-standard transactions regardless of the
                             default settings for the active network.
-      --altdnsnames:        Specify additional dns names to use when
-                            generating the rpc server certificate
+      --altdnsnames:        Specify additional dns names and ip addresses to
+                            use when generating the rpc server certificate
                             [supports DCRD_ALT_DNSNAMES environment variable]
       --peeridletimeout     The duration of inactivity before a peer is timed
                             out. Valid time units are {s,m,h}.
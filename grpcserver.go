@@ -0,0 +1,477 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/rpc/dcrdrpc"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// grpcNtfnQueueSize is the maximum number of notifications that may be queued
+// for a single gRPC notification stream.  Streams that fall further behind
+// than this are terminated so a slow client can't stall the node.
+const grpcNtfnQueueSize = 256
+
+// grpcServer provides the gRPC interface of the node.  It is owned by the RPC
+// server and shares its handlers, credentials, and TLS configuration.
+type grpcServer struct {
+	dcrdrpc.UnimplementedChainServiceServer
+
+	rpc       *rpcServer
+	server    *grpc.Server
+	listeners []net.Listener
+
+	// The following fields track the notification streams and are
+	// protected by the mutex.
+	ntfnMtx      sync.Mutex
+	blockNtfns   map[chan *dcrdrpc.BlockNotification]struct{}
+	mempoolNtfns map[chan *dcrdrpc.MempoolNotification]struct{}
+}
+
+// newGRPCServer returns a new gRPC server which serves the provided listeners
+// and uses the provided RPC server to handle requests.  TLS is not used when
+// the TLS configuration is nil.
+func newGRPCServer(rpc *rpcServer, listeners []net.Listener, tlsConfig *tls.Config) *grpcServer {
+	s := &grpcServer{
+		rpc:          rpc,
+		listeners:    listeners,
+		blockNtfns:   make(map[chan *dcrdrpc.BlockNotification]struct{}),
+		mempoolNtfns: make(map[chan *dcrdrpc.MempoolNotification]struct{}),
+	}
+	opts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.server = grpc.NewServer(opts...)
+	dcrdrpc.RegisterChainServiceServer(s.server, s)
+	return s
+}
+
+// grpcMethods maps the full names of the gRPC methods to the JSON-RPC methods
+// that provide the equivalent functionality.  A client is only authorized to
+// invoke a gRPC method when its credentials authorize the equivalent JSON-RPC
+// method.
+var grpcMethods = map[string]string{
+	"/dcrdrpc.ChainService/BestBlock":            "getbestblock",
+	"/dcrdrpc.ChainService/BlockHash":            "getblockhash",
+	"/dcrdrpc.ChainService/Block":                "getblock",
+	"/dcrdrpc.ChainService/BlockHeader":          "getblockheader",
+	"/dcrdrpc.ChainService/Transaction":          "getrawtransaction",
+	"/dcrdrpc.ChainService/MempoolTransactions":  "getrawmempool",
+	"/dcrdrpc.ChainService/SendTransaction":      "sendrawtransaction",
+	"/dcrdrpc.ChainService/BlockNotifications":   "notifyblocks",
+	"/dcrdrpc.ChainService/MempoolNotifications": "notifynewtransactions",
+}
+
+// authenticate ensures the request associated with the provided context was
+// made over a connection with a permitted TLS client certificate or carries the
+// same HTTP basic authentication credentials required by the JSON-RPC server in
+// its authorization metadata and that those credentials authorize the provided
+// gRPC method.
+func (s *grpcServer) authenticate(ctx context.Context, fullMethod string) error {
+	var tlsState *tls.ConnectionState
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			tlsState = &tlsInfo.State
+		}
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	_, perm, err := s.rpc.checkAuthHeader(tlsState, md.Get("authorization"),
+		grpcRemoteAddr(ctx), true)
+	if err != nil {
+		return status.Error(codes.Unauthenticated, "invalid credentials")
+	}
+	if !perm.allows(grpcMethods[fullMethod]) {
+		return status.Errorf(codes.PermissionDenied, "%s user not "+
+			"authorized for this method", perm)
+	}
+	return nil
+}
+
+// grpcRemoteAddr returns the address of the peer associated with the provided
+// context or an empty string when it is not known.
+func grpcRemoteAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// limitClient reserves a request for the client associated with the provided
+// context with the client limiter of the RPC server.  A function that must be
+// called once the request has completed is returned on success.
+func (s *grpcServer) limitClient(ctx context.Context) (func(), error) {
+	release, err := s.rpc.clientLimiter.acquire(grpcRemoteAddr(ctx))
+	if err != nil {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return release, nil
+}
+
+// unaryInterceptor authenticates and limits all unary requests before handling
+// them.
+func (s *grpcServer) unaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := s.authenticate(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	release, err := s.limitClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return handler(ctx, req)
+}
+
+// streamInterceptor authenticates and limits all streaming requests before
+// handling them.  Opening a stream counts as a single request that completes
+// immediately so long-lived notification streams do not count towards the
+// concurrent request limit of the client.
+func (s *grpcServer) streamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.authenticate(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	release, err := s.limitClient(ss.Context())
+	if err != nil {
+		return err
+	}
+	release()
+	return handler(srv, ss)
+}
+
+// Run serves the gRPC listeners until the provided context is cancelled.
+func (s *grpcServer) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, listener := range s.listeners {
+		wg.Add(1)
+		go func(listener net.Listener) {
+			rpcsLog.Infof("gRPC server listening on %s", listener.Addr())
+			err := s.server.Serve(listener)
+			if err != nil {
+				rpcsLog.Errorf("gRPC listener %s failed: %v",
+					listener.Addr(), err)
+			}
+			rpcsLog.Tracef("gRPC listener done for %s", listener.Addr())
+			wg.Done()
+		}(listener)
+	}
+
+	<-ctx.Done()
+	s.server.Stop()
+	wg.Wait()
+}
+
+// grpcError converts the provided error, which is typically an RPC error
+// returned by a JSON-RPC handler, to a gRPC status error.
+func grpcError(err error) error {
+	var rpcErr *dcrjson.RPCError
+	if !errors.As(err, &rpcErr) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	switch rpcErr.Code {
+	case dcrjson.ErrRPCBlockNotFound:
+		return status.Error(codes.NotFound, rpcErr.Message)
+	case dcrjson.ErrRPCInvalidParameter, dcrjson.ErrRPCDecodeHexString,
+		dcrjson.ErrRPCInvalidParams.Code:
+		return status.Error(codes.InvalidArgument, rpcErr.Message)
+	case dcrjson.ErrRPCDuplicateTx:
+		return status.Error(codes.AlreadyExists, rpcErr.Message)
+	case dcrjson.ErrRPCMisc:
+		return status.Error(codes.FailedPrecondition, rpcErr.Message)
+	}
+	return status.Error(codes.Internal, rpcErr.Message)
+}
+
+// parseGRPCHash returns the hash encoded by the provided bytes or an invalid
+// argument error when they are not a valid hash.
+func parseGRPCHash(b []byte) (*chainhash.Hash, error) {
+	hash, err := chainhash.NewHash(b)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return hash, nil
+}
+
+// confirmations returns the number of confirmations of the block with the
+// provided hash and height or -1 when it is not in the main chain.
+func (s *grpcServer) confirmations(hash *chainhash.Hash, height int64) int64 {
+	chain := s.rpc.cfg.Chain
+	if !chain.MainChainHasBlock(hash) {
+		return -1
+	}
+	return 1 + chain.BestSnapshot().Height - height
+}
+
+// BestBlock returns the hash and height of the tip of the main chain.
+func (s *grpcServer) BestBlock(context.Context, *dcrdrpc.BestBlockRequest) (*dcrdrpc.BestBlockResponse, error) {
+	best := s.rpc.cfg.Chain.BestSnapshot()
+	return &dcrdrpc.BestBlockResponse{
+		Hash:   best.Hash[:],
+		Height: best.Height,
+	}, nil
+}
+
+// BlockHash returns the hash of the main chain block at a height.
+func (s *grpcServer) BlockHash(_ context.Context, req *dcrdrpc.BlockHashRequest) (*dcrdrpc.BlockHashResponse, error) {
+	hash, err := s.rpc.cfg.Chain.BlockHashByHeight(req.Height)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "no block at height %d",
+			req.Height)
+	}
+	return &dcrdrpc.BlockHashResponse{Hash: hash[:]}, nil
+}
+
+// Block returns a block by its hash.
+func (s *grpcServer) Block(_ context.Context, req *dcrdrpc.BlockRequest) (*dcrdrpc.BlockResponse, error) {
+	hash, err := parseGRPCHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	block, err := s.rpc.cfg.Chain.BlockByHash(hash)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "block not found: %v",
+			hash)
+	}
+	blockBytes, err := block.Bytes()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &dcrdrpc.BlockResponse{
+		Block:         blockBytes,
+		Height:        block.Height(),
+		Confirmations: s.confirmations(hash, block.Height()),
+	}, nil
+}
+
+// BlockHeader returns a block header by its block hash.
+func (s *grpcServer) BlockHeader(_ context.Context, req *dcrdrpc.BlockHeaderRequest) (*dcrdrpc.BlockHeaderResponse, error) {
+	hash, err := parseGRPCHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	header, err := s.rpc.cfg.Chain.HeaderByHash(hash)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "block not found: %v",
+			hash)
+	}
+	var buf bytes.Buffer
+	if err := header.Serialize(&buf); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &dcrdrpc.BlockHeaderResponse{
+		Header:        buf.Bytes(),
+		Confirmations: s.confirmations(hash, int64(header.Height)),
+	}, nil
+}
+
+// Transaction returns a transaction from the mempool or, when the transaction
+// index is enabled, the main chain.
+func (s *grpcServer) Transaction(ctx context.Context, req *dcrdrpc.TransactionRequest) (*dcrdrpc.TransactionResponse, error) {
+	hash, err := parseGRPCHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	verbose := 1
+	result, err := handleGetRawTransaction(ctx, s.rpc,
+		&types.GetRawTransactionCmd{Txid: hash.String(), Verbose: &verbose})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	txResult := result.(*types.TxRawResult)
+	txBytes, err := hex.DecodeString(txResult.Hex)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &dcrdrpc.TransactionResponse{
+		Transaction:   txBytes,
+		BlockHeight:   txResult.BlockHeight,
+		Confirmations: txResult.Confirmations,
+	}
+	if txResult.BlockHash != "" {
+		blockHash, err := chainhash.NewHashFromStr(txResult.BlockHash)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		resp.BlockHash = blockHash[:]
+	}
+	return resp, nil
+}
+
+// MempoolTransactions returns the hashes of all transactions in the mempool.
+func (s *grpcServer) MempoolTransactions(context.Context, *dcrdrpc.MempoolTransactionsRequest) (*dcrdrpc.MempoolTransactionsResponse, error) {
+	txHashes := s.rpc.cfg.TxMemPool.TxHashes()
+	hashes := make([][]byte, 0, len(txHashes))
+	for _, hash := range txHashes {
+		hashes = append(hashes, hash[:])
+	}
+	return &dcrdrpc.MempoolTransactionsResponse{Hashes: hashes}, nil
+}
+
+// SendTransaction submits a transaction to the mempool and relays it to the
+// network.
+func (s *grpcServer) SendTransaction(ctx context.Context, req *dcrdrpc.SendTransactionRequest) (*dcrdrpc.SendTransactionResponse, error) {
+	allowHighFees := req.AllowHighFees
+	result, err := handleSendRawTransaction(ctx, s.rpc,
+		&types.SendRawTransactionCmd{
+			HexTx:         hex.EncodeToString(req.Transaction),
+			AllowHighFees: &allowHighFees,
+		})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	hash, err := chainhash.NewHashFromStr(result.(string))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &dcrdrpc.SendTransactionResponse{Hash: hash[:]}, nil
+}
+
+// errGRPCNtfnBacklog is returned to notification streams that are terminated
+// because the client is not keeping up with the notifications.
+var errGRPCNtfnBacklog = status.Error(codes.ResourceExhausted,
+	"notification backlog exceeded")
+
+// BlockNotifications streams a notification whenever a block is connected to
+// or disconnected from the main chain.
+func (s *grpcServer) BlockNotifications(_ *dcrdrpc.BlockNotificationsRequest, stream dcrdrpc.ChainService_BlockNotificationsServer) error {
+	c := make(chan *dcrdrpc.BlockNotification, grpcNtfnQueueSize)
+	s.ntfnMtx.Lock()
+	s.blockNtfns[c] = struct{}{}
+	s.ntfnMtx.Unlock()
+	defer func() {
+		s.ntfnMtx.Lock()
+		delete(s.blockNtfns, c)
+		s.ntfnMtx.Unlock()
+	}()
+
+	for {
+		select {
+		case ntfn, ok := <-c:
+			if !ok {
+				return errGRPCNtfnBacklog
+			}
+			if err := stream.Send(ntfn); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// MempoolNotifications streams a notification whenever a new transaction is
+// accepted into the mempool.
+func (s *grpcServer) MempoolNotifications(_ *dcrdrpc.MempoolNotificationsRequest, stream dcrdrpc.ChainService_MempoolNotificationsServer) error {
+	c := make(chan *dcrdrpc.MempoolNotification, grpcNtfnQueueSize)
+	s.ntfnMtx.Lock()
+	s.mempoolNtfns[c] = struct{}{}
+	s.ntfnMtx.Unlock()
+	defer func() {
+		s.ntfnMtx.Lock()
+		delete(s.mempoolNtfns, c)
+		s.ntfnMtx.Unlock()
+	}()
+
+	for {
+		select {
+		case ntfn, ok := <-c:
+			if !ok {
+				return errGRPCNtfnBacklog
+			}
+			if err := stream.Send(ntfn); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// notifyBlock queues a block notification of the provided type for the
+// provided block to all block notification streams.
+func (s *grpcServer) notifyBlock(ntfnType dcrdrpc.BlockNotification_Type, block *dcrutil.Block) {
+	var buf bytes.Buffer
+	if err := block.MsgBlock().Header.Serialize(&buf); err != nil {
+		rpcsLog.Errorf("Failed to serialize header for gRPC block "+
+			"notification: %v", err)
+		return
+	}
+	ntfn := &dcrdrpc.BlockNotification{
+		Type:   ntfnType,
+		Header: buf.Bytes(),
+	}
+
+	s.ntfnMtx.Lock()
+	for c := range s.blockNtfns {
+		select {
+		case c <- ntfn:
+		default:
+			delete(s.blockNtfns, c)
+			close(c)
+		}
+	}
+	s.ntfnMtx.Unlock()
+}
+
+// NotifyBlockConnected notifies all block notification streams that the
+// provided block was connected to the main chain.
+//
+// This function is safe for concurrent access.
+func (s *grpcServer) NotifyBlockConnected(block *dcrutil.Block) {
+	s.notifyBlock(dcrdrpc.BlockNotification_CONNECTED, block)
+}
+
+// NotifyBlockDisconnected notifies all block notification streams that the
+// provided block was disconnected from the main chain.
+//
+// This function is safe for concurrent access.
+func (s *grpcServer) NotifyBlockDisconnected(block *dcrutil.Block) {
+	s.notifyBlock(dcrdrpc.BlockNotification_DISCONNECTED, block)
+}
+
+// NotifyMempoolTx notifies all mempool notification streams that the provided
+// transaction was accepted into the mempool.
+//
+// This function is safe for concurrent access.
+func (s *grpcServer) NotifyMempoolTx(tx *dcrutil.Tx) {
+	var buf bytes.Buffer
+	buf.Grow(tx.MsgTx().SerializeSize())
+	if err := tx.MsgTx().Serialize(&buf); err != nil {
+		rpcsLog.Errorf("Failed to serialize transaction for gRPC mempool "+
+			"notification: %v", err)
+		return
+	}
+	ntfn := &dcrdrpc.MempoolNotification{Transaction: buf.Bytes()}
+
+	s.ntfnMtx.Lock()
+	for c := range s.mempoolNtfns {
+		select {
+		case c <- ntfn:
+		default:
+			delete(s.mempoolNtfns, c)
+			close(c)
+		}
+	}
+	s.ntfnMtx.Unlock()
+}
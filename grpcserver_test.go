@@ -0,0 +1,92 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/rpc/dcrdrpc"
+	"github.com/decred/dcrd/wire"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TestGRPCError ensures errors returned by the JSON-RPC handlers are converted
+// to the expected gRPC status codes.
+func TestGRPCError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want codes.Code
+	}{{
+		name: "block not found",
+		err:  &dcrjson.RPCError{Code: dcrjson.ErrRPCBlockNotFound},
+		want: codes.NotFound,
+	}, {
+		name: "no tx info",
+		err:  rpcNoTxInfoError(&zeroHash),
+		want: codes.NotFound,
+	}, {
+		name: "decode hex",
+		err:  rpcDecodeHexError("zz"),
+		want: codes.InvalidArgument,
+	}, {
+		name: "duplicate tx",
+		err:  rpcDuplicateTxError("dup"),
+		want: codes.AlreadyExists,
+	}, {
+		name: "rule error",
+		err:  rpcRuleError("rejected"),
+		want: codes.FailedPrecondition,
+	}, {
+		name: "internal rpc error",
+		err:  rpcInternalError("oops", ""),
+		want: codes.Internal,
+	}, {
+		name: "non rpc error",
+		err:  errors.New("oops"),
+		want: codes.Internal,
+	}}
+
+	for _, test := range tests {
+		got := status.Code(grpcError(test.err))
+		if got != test.want {
+			t.Errorf("%q: unexpected code -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestGRPCNotificationBacklog ensures notifications are queued for streams
+// and that streams which fall too far behind are removed and closed.
+func TestGRPCNotificationBacklog(t *testing.T) {
+	s := newGRPCServer(&rpcServer{}, nil, nil)
+	fast := make(chan *dcrdrpc.MempoolNotification, 2)
+	slow := make(chan *dcrdrpc.MempoolNotification, 1)
+	s.mempoolNtfns[fast] = struct{}{}
+	s.mempoolNtfns[slow] = struct{}{}
+
+	tx := dcrutil.NewTx(wire.NewMsgTx())
+	s.NotifyMempoolTx(tx)
+	s.NotifyMempoolTx(tx)
+
+	if len(fast) != 2 {
+		t.Fatalf("unexpected number of queued notifications -- got %d, "+
+			"want 2", len(fast))
+	}
+	if _, ok := s.mempoolNtfns[slow]; ok {
+		t.Fatal("slow notification stream was not removed")
+	}
+	<-slow
+	if _, ok := <-slow; ok {
+		t.Fatal("slow notification stream was not closed")
+	}
+	if _, ok := s.mempoolNtfns[fast]; !ok {
+		t.Fatal("fast notification stream was removed")
+	}
+}
@@ -5,6 +5,8 @@
 package rpcserver
 
 import (
+	"time"
+
 	"github.com/decred/dcrd/blockchain/v3"
 	"github.com/decred/dcrd/blockchain/v3/indexers"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -70,6 +72,10 @@ type ConnManager interface {
 	// network for all peers.
 	NetTotals() (uint64, uint64)
 
+	// UploadTarget returns details about the upload target that limits the
+	// serving of historical blocks.
+	UploadTarget() *UploadTarget
+
 	// ConnectedPeers returns an array consisting of all connected peers.
 	ConnectedPeers() []Peer
 
@@ -92,6 +98,84 @@ type ConnManager interface {
 
 	// AddedNodeInfo returns information describing persistent (added) nodes.
 	AddedNodeInfo() []Peer
+
+	// MaxPeers returns the maximum number of inbound and outbound peers
+	// that are currently allowed.
+	MaxPeers() int
+
+	// SetMaxPeers sets the maximum number of inbound and outbound peers that
+	// are allowed.  Existing peers are not disconnected when the limit is
+	// lowered.
+	SetMaxPeers(maxPeers int)
+
+	// BanDuration returns the duration misbehaving peers are currently
+	// banned for.
+	BanDuration() time.Duration
+
+	// SetBanDuration sets the duration misbehaving peers are banned for.
+	SetBanDuration(banDuration time.Duration)
+}
+
+// UploadTarget houses details about the upload target that limits the serving
+// of historical blocks to peers over a rolling window of time.
+type UploadTarget struct {
+	// TimeFrame is the rolling window of time the target applies to.
+	TimeFrame time.Duration
+
+	// Target is the maximum number of bytes to upload during the time
+	// frame.  It is zero when there is no target.
+	Target uint64
+
+	// BytesLeft is the number of bytes that may be uploaded before the
+	// target is reached.
+	BytesLeft uint64
+
+	// TargetReached indicates whether or not the target has been reached.
+	TargetReached bool
+
+	// ServeHistoricalBlocks indicates whether or not historical blocks are
+	// currently being served to non-whitelisted peers.
+	ServeHistoricalBlocks bool
+}
+
+// SyncStatus houses details about the progress of syncing the chain with the
+// network.
+type SyncStatus struct {
+	// SyncPeerID is the id of the current peer being synced with or zero
+	// when there is none.
+	SyncPeerID int32
+
+	// Current indicates whether or not the sync manager believes the chain
+	// is current as compared to the rest of the network.
+	Current bool
+
+	// HeadersFirst indicates whether or not the sync manager is downloading
+	// headers prior to the blocks they commit to.
+	HeadersFirst bool
+
+	// BlockHeight is the height of the current best chain.
+	BlockHeight int64
+
+	// HeaderHeight is the height of the best known header.  It is the same
+	// as the block height unless headers are being downloaded first.
+	HeaderHeight int64
+
+	// SyncHeight is the latest known block height being synced to.
+	SyncHeight int64
+
+	// VerificationProgress is an estimate of the fraction of the blocks that
+	// have been downloaded and verified.
+	VerificationProgress float64
+
+	// BlocksPerSecond and BytesPerSecond are the recent rates at which
+	// blocks have been processed.
+	BlocksPerSecond float64
+	BytesPerSecond  float64
+
+	// ETA is the estimated amount of time remaining until the chain is
+	// synced to the sync height.  It is zero when the chain is current or
+	// the estimate is not available.
+	ETA time.Duration
 }
 
 // SyncManager represents a sync manager for use with the RPC server.
@@ -129,6 +213,10 @@ type SyncManager interface {
 	// SyncHeight returns latest known block being synced to.
 	SyncHeight() int64
 
+	// SyncStatus returns details about the progress of syncing the chain
+	// with the network.
+	SyncStatus() *SyncStatus
+
 	// ProcessTransaction relays the provided transaction validation and
 	// insertion into the memory pool.
 	ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool, rateLimit bool,
@@ -45,6 +45,13 @@ var (
 	// if needed.  It MUST only contain characters from semanticBuildAlphabet
 	// per the semantic versioning spec.
 	BuildMetadata = ""
+
+	// Commit is defined as a variable so it can be set to the revision of the
+	// source code the application was built from during the build process
+	// with:
+	// '-ldflags "-X github.com/decred/dcrd/internal/version.Commit=foo"'
+	// It is empty when the revision is not known.
+	Commit = ""
 )
 
 // String returns the application version as a properly formed string per the
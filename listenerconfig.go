@@ -0,0 +1,204 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	// listenerNetIPv4, listenerNetIPv6, and listenerNetOnion are the network
+	// types that may be accepted by a listener.  Connections from a local Tor
+	// hidden service originate from a loopback address, so the onion network
+	// type accepts connections from loopback addresses.
+	listenerNetIPv4  = "ipv4"
+	listenerNetIPv6  = "ipv6"
+	listenerNetOnion = "onion"
+)
+
+// listenerConfig houses the settings that only apply to connections accepted by
+// a specific listen address.  They are specified as comma-separated key=value
+// options following the listen address.  For example:
+//
+//	--listen=10.0.0.1:9108,net=ipv4,whitelist=10.0.0.0/8,maxinbound=25
+//	--listen=127.0.0.1:9108,net=onion,externalip=xyz.onion
+type listenerConfig struct {
+	// nets is the set of network types connections are accepted from.  All
+	// network types are accepted when it is empty.
+	nets map[string]struct{}
+
+	// whitelists are the networks and IPs, in addition to the global ones,
+	// that are whitelisted when they connect via the listener.
+	whitelists []*net.IPNet
+
+	// maxInbound is the maximum number of inbound peers accepted via the
+	// listener.  There is no limit other than the global one when it is 0.
+	maxInbound int
+
+	// externalIPs are the addresses advertised to peers for the listener in
+	// place of the address it is bound to.
+	externalIPs []string
+}
+
+// parseListenerOptions parses the provided listen address along with any
+// comma-separated per-listener options that follow it.  It returns the listen
+// address without the options and the parsed listener config, which is nil
+// when no options are specified.
+func parseListenerOptions(listener string) (string, *listenerConfig, error) {
+	parts := strings.Split(listener, ",")
+	addr := strings.TrimSpace(parts[0])
+	if len(parts) == 1 {
+		return addr, nil, nil
+	}
+
+	lc := &listenerConfig{nets: make(map[string]struct{})}
+	for _, option := range parts[1:] {
+		kv := strings.SplitN(option, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return "", nil, fmt.Errorf("option '%s' is not of the form "+
+				"key=value", option)
+		}
+		key, value := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+
+		switch key {
+		case "net":
+			switch value {
+			case listenerNetIPv4, listenerNetIPv6, listenerNetOnion:
+			default:
+				return "", nil, fmt.Errorf("network type '%s' is not one "+
+					"of %s, %s, or %s", value, listenerNetIPv4,
+					listenerNetIPv6, listenerNetOnion)
+			}
+			lc.nets[value] = struct{}{}
+
+		case "whitelist":
+			ipnet, err := parseWhitelist(value)
+			if err != nil {
+				return "", nil, err
+			}
+			lc.whitelists = append(lc.whitelists, ipnet)
+
+		case "maxinbound":
+			maxInbound, err := strconv.Atoi(value)
+			if err != nil || maxInbound < 1 {
+				return "", nil, fmt.Errorf("max inbound value of '%s' "+
+					"must be a positive integer", value)
+			}
+			lc.maxInbound = maxInbound
+
+		case "externalip":
+			lc.externalIPs = append(lc.externalIPs, value)
+
+		default:
+			return "", nil, fmt.Errorf("unknown option '%s'", key)
+		}
+	}
+
+	return addr, lc, nil
+}
+
+// hasListenerExternalIPs returns whether or not any of the provided listener
+// configs specify external addresses to advertise.
+func hasListenerExternalIPs(cfgs map[string]*listenerConfig) bool {
+	for _, lc := range cfgs {
+		if len(lc.externalIPs) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the IP of the provided address or nil if it does not have
+// one.
+func remoteIP(addr net.Addr) net.IP {
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+	return net.ParseIP(host)
+}
+
+// acceptsAddr returns whether or not the network type of the provided remote
+// address is accepted by the listener.
+//
+// This function is safe to call on a nil listener config.
+func (lc *listenerConfig) acceptsAddr(addr net.Addr) bool {
+	if lc == nil || len(lc.nets) == 0 {
+		return true
+	}
+
+	ip := remoteIP(addr)
+	if ip == nil {
+		return false
+	}
+	if _, ok := lc.nets[listenerNetOnion]; ok && ip.IsLoopback() {
+		return true
+	}
+	netType := listenerNetIPv6
+	if ip.To4() != nil {
+		netType = listenerNetIPv4
+	}
+	_, ok := lc.nets[netType]
+	return ok
+}
+
+// isWhitelisted returns whether or not the IP of the provided remote address is
+// included in the networks and IPs whitelisted by the listener.
+//
+// This function is safe to call on a nil listener config.
+func (lc *listenerConfig) isWhitelisted(addr net.Addr) bool {
+	if lc == nil || len(lc.whitelists) == 0 {
+		return false
+	}
+
+	ip := remoteIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, ipnet := range lc.whitelists {
+		if ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// configuredListener wraps a listener that has per-listener settings so the
+// connections it accepts carry them.
+type configuredListener struct {
+	net.Listener
+	cfg *listenerConfig
+}
+
+// Accept waits for and returns the next connection to the listener along with
+// the settings of the listener.
+//
+// This is part of the net.Listener interface.
+func (l *configuredListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &listenerConn{Conn: conn, cfg: l.cfg}, nil
+}
+
+// listenerConn is a connection accepted by a listener that has per-listener
+// settings.
+type listenerConn struct {
+	net.Conn
+	cfg *listenerConfig
+}
+
+// connListenerConfig returns the settings of the listener that accepted the
+// provided connection or nil when it does not have any.
+func connListenerConfig(conn net.Conn) *listenerConfig {
+	if c, ok := conn.(*listenerConn); ok {
+		return c.cfg
+	}
+	return nil
+}
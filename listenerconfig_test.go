@@ -0,0 +1,134 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"net"
+	"testing"
+)
+
+// TestParseListenerOptions ensures per-listener options are parsed from listen
+// addresses as expected.
+func TestParseListenerOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		listener   string
+		wantAddr   string
+		wantNil    bool
+		wantErr    bool
+		nets       int
+		whitelists int
+		maxInbound int
+		externals  int
+	}{{
+		name:     "no options",
+		listener: "[::1]:9108",
+		wantAddr: "[::1]:9108",
+		wantNil:  true,
+	}, {
+		name: "all options",
+		listener: "10.0.0.1:9108,net=ipv4,net=onion,whitelist=10.0.0.0/8," +
+			"whitelist=::1,maxinbound=25,externalip=1.2.3.4",
+		wantAddr:   "10.0.0.1:9108",
+		nets:       2,
+		whitelists: 2,
+		maxInbound: 25,
+		externals:  1,
+	}, {
+		name:     "unknown network type",
+		listener: ":9108,net=i2p",
+		wantErr:  true,
+	}, {
+		name:     "invalid whitelist",
+		listener: ":9108,whitelist=bogus",
+		wantErr:  true,
+	}, {
+		name:     "zero max inbound",
+		listener: ":9108,maxinbound=0",
+		wantErr:  true,
+	}, {
+		name:     "missing value",
+		listener: ":9108,externalip=",
+		wantErr:  true,
+	}, {
+		name:     "unknown option",
+		listener: ":9108,maxoutbound=8",
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		addr, lc, err := parseListenerOptions(test.listener)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if test.wantErr {
+			continue
+		}
+		if addr != test.wantAddr {
+			t.Errorf("%q: unexpected address -- got %s, want %s", test.name,
+				addr, test.wantAddr)
+			continue
+		}
+		if (lc == nil) != test.wantNil {
+			t.Errorf("%q: unexpected nil config -- got %v, want %v",
+				test.name, lc == nil, test.wantNil)
+			continue
+		}
+		if lc == nil {
+			continue
+		}
+		if len(lc.nets) != test.nets ||
+			len(lc.whitelists) != test.whitelists ||
+			lc.maxInbound != test.maxInbound ||
+			len(lc.externalIPs) != test.externals {
+
+			t.Errorf("%q: unexpected config -- got %+v", test.name, lc)
+		}
+	}
+}
+
+// TestListenerConfigAddrs ensures listener configs accept and whitelist remote
+// addresses as expected.
+func TestListenerConfigAddrs(t *testing.T) {
+	_, lc, err := parseListenerOptions(":9108,net=ipv6,net=onion," +
+		"whitelist=192.168.1.0/24")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		addr        string
+		accepted    bool
+		whitelisted bool
+	}{
+		{addr: "1.2.3.4:9108", accepted: false, whitelisted: false},
+		{addr: "192.168.1.5:9108", accepted: false, whitelisted: true},
+		{addr: "[2001:db8::1]:9108", accepted: true, whitelisted: false},
+		{addr: "127.0.0.1:51234", accepted: true, whitelisted: false},
+	}
+	for _, test := range tests {
+		addr, err := net.ResolveTCPAddr("tcp", test.addr)
+		if err != nil {
+			t.Fatalf("unable to resolve %s: %v", test.addr, err)
+		}
+		if got := lc.acceptsAddr(addr); got != test.accepted {
+			t.Errorf("%s: unexpected accepted -- got %v, want %v",
+				test.addr, got, test.accepted)
+		}
+		if got := lc.isWhitelisted(addr); got != test.whitelisted {
+			t.Errorf("%s: unexpected whitelisted -- got %v, want %v",
+				test.addr, got, test.whitelisted)
+		}
+	}
+
+	// Ensure a nil config accepts all addresses and whitelists none.
+	var nilConfig *listenerConfig
+	addr := &net.TCPAddr{IP: net.ParseIP("1.2.3.4"), Port: 9108}
+	if !nilConfig.acceptsAddr(addr) || nilConfig.isWhitelisted(addr) {
+		t.Fatal("nil listener config does not use defaults")
+	}
+}
@@ -172,13 +172,3 @@ func pickNoun(n uint64, singular, plural string) string {
 	}
 	return plural
 }
-
-// fatalf logs a string, then cleanly exits.
-func fatalf(str string) {
-	dcrdLog.Errorf("Unable to create profiler: %v", str)
-	os.Stdout.Sync()
-	if logRotator != nil {
-		logRotator.Close()
-	}
-	os.Exit(1)
-}
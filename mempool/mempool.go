@@ -54,6 +54,11 @@ const (
 	// pushes in a transaction, after which it is considered non-standard.
 	maxNullDataOutputs = 4
 
+	// defaultFreeTxRelayWindow is the default approximate window over which
+	// the exponentially decaying total of free and low-fee transactions is
+	// tracked by the rate limiter.
+	defaultFreeTxRelayWindow = time.Minute * 10
+
 	// orphanTTL is the maximum amount of time an orphan is allowed to
 	// stay in the orphan pool before it expires and is evicted during the
 	// next scan.
@@ -164,6 +169,12 @@ type Policy struct {
 	// per minute that transactions with no fee are rate limited to.
 	FreeTxRelayLimit float64
 
+	// FreeTxRelayWindow defines the approximate window over which the
+	// exponentially decaying total of free and low-fee transactions used by
+	// the rate limiter is tracked.  A value of zero results in the default
+	// window of ten minutes.
+	FreeTxRelayWindow time.Duration
+
 	// MaxOrphanTxs is the maximum number of orphan transactions
 	// that can be queued.
 	MaxOrphanTxs int
@@ -247,7 +258,8 @@ type orphanTx struct {
 // peers.
 type TxPool struct {
 	// The following variables must only be used atomically.
-	lastUpdated int64 // last time pool was updated.
+	lastUpdated  int64  // last time pool was updated.
+	numThrottled uint64 // number of txns rejected by the rate limiter.
 
 	mtx  sync.RWMutex
 	cfg  Config
@@ -1484,14 +1496,19 @@ func (mp *TxPool) maybeAcceptTransaction(tx *dcrutil.Tx, isNew, rateLimit, allow
 	// This applies to non-stake transactions only.
 	if rateLimit && txFee < minFee && txType == stake.TxTypeRegular {
 		nowUnix := time.Now().Unix()
-		// Decay passed data with an exponentially decaying ~10 minute
-		// window.
-		mp.pennyTotal *= math.Pow(1.0-1.0/600.0,
+		// Decay passed data with an exponentially decaying window which
+		// defaults to ~10 minutes.
+		window := defaultFreeTxRelayWindow
+		if mp.cfg.Policy.FreeTxRelayWindow >= time.Second {
+			window = mp.cfg.Policy.FreeTxRelayWindow
+		}
+		mp.pennyTotal *= math.Pow(1.0-1.0/window.Seconds(),
 			float64(nowUnix-mp.lastPennyUnix))
 		mp.lastPennyUnix = nowUnix
 
 		// Are we still over the limit?
 		if mp.pennyTotal >= mp.cfg.Policy.FreeTxRelayLimit*10*1000 {
+			atomic.AddUint64(&mp.numThrottled, 1)
 			str := fmt.Sprintf("transaction %v has been rejected "+
 				"by the rate limiter due to low fees", txHash)
 			return nil, txRuleError(wire.RejectInsufficientFee,
@@ -1984,6 +2001,74 @@ func (mp *TxPool) LastUpdated() time.Time {
 	return time.Unix(atomic.LoadInt64(&mp.lastUpdated), 0)
 }
 
+// NumRateLimited returns the number of free and low-fee transactions that
+// have been rejected by the rate limiter since the pool was created.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) NumRateLimited() uint64 {
+	return atomic.LoadUint64(&mp.numThrottled)
+}
+
+// MinRelayTxFee returns the minimum transaction fee per kB that is currently
+// required for a transaction to be treated as a non-zero fee transaction.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) MinRelayTxFee() dcrutil.Amount {
+	mp.mtx.RLock()
+	fee := mp.cfg.Policy.MinRelayTxFee
+	mp.mtx.RUnlock()
+	return fee
+}
+
+// SetMinRelayTxFee sets the minimum transaction fee per kB that is required
+// for a transaction to be treated as a non-zero fee transaction.  It only
+// applies to transactions that are subsequently processed.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) SetMinRelayTxFee(fee dcrutil.Amount) {
+	mp.mtx.Lock()
+	mp.cfg.Policy.MinRelayTxFee = fee
+	mp.mtx.Unlock()
+}
+
+// MaxOrphanTxs returns the maximum number of orphan transactions that are
+// currently allowed to be kept in the orphan pool.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) MaxOrphanTxs() int {
+	mp.mtx.RLock()
+	maxOrphans := mp.cfg.Policy.MaxOrphanTxs
+	mp.mtx.RUnlock()
+	return maxOrphans
+}
+
+// SetMaxOrphanTxs sets the maximum number of orphan transactions that are
+// allowed to be kept in the orphan pool.  Random orphans are evicted as needed
+// when the orphan pool exceeds the new limit.  It returns the number of
+// orphans that were evicted.
+//
+// This function is safe for concurrent access.
+func (mp *TxPool) SetMaxOrphanTxs(maxOrphans int) int {
+	mp.mtx.Lock()
+	mp.cfg.Policy.MaxOrphanTxs = maxOrphans
+
+	// Evict random orphans until the pool no longer exceeds the limit.  See
+	// limitNumOrphans for why random iteration order is acceptable here.
+	var numEvicted int
+	for _, otx := range mp.orphans {
+		if len(mp.orphans) <= maxOrphans {
+			break
+		}
+
+		// Don't remove redeemers in the case of a random eviction since
+		// it is quite possible they might be needed again shortly.
+		mp.removeOrphan(otx.tx, false)
+		numEvicted++
+	}
+	mp.mtx.Unlock()
+	return numEvicted
+}
+
 // New returns a new memory pool for validating and storing standalone
 // transactions until they are mined into a block.
 func New(cfg *Config) *TxPool {
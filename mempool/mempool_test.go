@@ -1170,7 +1170,7 @@ func TestOrphanReject(t *testing.T) {
 
 		// Ensure no transactions were reported as accepted.
 		if len(acceptedTxns) != 0 {
-			t.Fatal("ProcessTransaction: reported %d accepted "+
+			t.Fatalf("ProcessTransaction: reported %d accepted "+
 				"transactions from failed orphan attempt",
 				len(acceptedTxns))
 		}
@@ -1245,6 +1245,72 @@ func TestOrphanEviction(t *testing.T) {
 	}
 }
 
+// TestRuntimePolicy ensures the policy settings that may be changed while the
+// pool is running are reported correctly and lowering the maximum number of
+// orphans evicts orphans as needed.
+func TestRuntimePolicy(t *testing.T) {
+	t.Parallel()
+
+	harness, outputs, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	txPool := harness.txPool
+
+	// Ensure the minimum relay fee is updated.
+	origFee := harness.txPool.cfg.Policy.MinRelayTxFee
+	if fee := txPool.MinRelayTxFee(); fee != origFee {
+		t.Fatalf("MinRelayTxFee: unexpected fee -- got %v, want %v", fee,
+			origFee)
+	}
+	newFee := origFee * 2
+	txPool.SetMinRelayTxFee(newFee)
+	if fee := txPool.MinRelayTxFee(); fee != newFee {
+		t.Fatalf("MinRelayTxFee: unexpected fee -- got %v, want %v", fee,
+			newFee)
+	}
+
+	// Add several orphans and ensure lowering the max allowed orphans below
+	// the number in the pool evicts the excess.
+	const numOrphans = 5
+	chainedTxns, err := harness.CreateTxChain(outputs[0], numOrphans+1)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns[1:] {
+		_, err := txPool.ProcessTransaction(tx, true, false, true, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept valid "+
+				"orphan %v", err)
+		}
+	}
+	numEvicted := txPool.SetMaxOrphanTxs(2)
+	if numEvicted != numOrphans-2 {
+		t.Fatalf("SetMaxOrphanTxs: unexpected number of evictions -- "+
+			"got %d, want %d", numEvicted, numOrphans-2)
+	}
+	if maxOrphans := txPool.MaxOrphanTxs(); maxOrphans != 2 {
+		t.Fatalf("MaxOrphanTxs: unexpected max -- got %d, want 2",
+			maxOrphans)
+	}
+	var numRemaining int
+	for _, tx := range chainedTxns[1:] {
+		if txPool.IsOrphanInPool(tx.Hash()) {
+			numRemaining++
+		}
+	}
+	if numRemaining != 2 {
+		t.Fatalf("unexpected number of remaining orphans -- got %d, "+
+			"want 2", numRemaining)
+	}
+
+	// Ensure raising the limit does not evict anything.
+	if numEvicted := txPool.SetMaxOrphanTxs(10); numEvicted != 0 {
+		t.Fatalf("SetMaxOrphanTxs: unexpected number of evictions -- "+
+			"got %d, want 0", numEvicted)
+	}
+}
+
 // TestExpirationPruning ensures that transactions that expire without being
 // mined are removed.
 func TestExpirationPruning(t *testing.T) {
@@ -2294,3 +2360,62 @@ func TestRemoveDoubleSpends(t *testing.T) {
 			"exist in pool.", ticket.Hash())
 	}
 }
+
+// TestFreeTxRateLimit ensures that free transactions are rejected by the rate
+// limiter once the configured limit has been reached, that the number of
+// throttled transactions is tracked, and that transactions which are not
+// subject to rate limiting are still accepted.
+func TestFreeTxRateLimit(t *testing.T) {
+	t.Parallel()
+
+	harness, spendableOuts, err := newPoolHarness(chaincfg.MainNetParams())
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+
+	// Configure a limit that is small enough for a single free transaction
+	// to exhaust it.
+	harness.txPool.cfg.Policy.FreeTxRelayLimit = 0.0001
+	harness.txPool.cfg.Policy.FreeTxRelayWindow = time.Minute
+
+	// Create a chain of free transactions to use throughout the test.
+	txns, err := harness.CreateTxChain(spendableOuts[0], 2)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+
+	// Ensure the first free transaction is accepted since the limit has not
+	// been reached yet.
+	_, err = harness.txPool.ProcessTransaction(txns[0], false, true, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept initial tx: %v", err)
+	}
+	testPoolMembership(tc, txns[0], false, true)
+	if n := harness.txPool.NumRateLimited(); n != 0 {
+		t.Fatalf("unexpected number of rate limited txns: got %d, want 0", n)
+	}
+
+	// Ensure the second free transaction is rejected by the rate limiter and
+	// that it is counted as such.
+	_, err = harness.txPool.ProcessTransaction(txns[1], false, true, true, 0)
+	if !IsErrorCode(err, ErrInsufficientFee) {
+		t.Fatalf("ProcessTransaction: did not get expected "+
+			"ErrInsufficientFee: %v", err)
+	}
+	testPoolMembership(tc, txns[1], false, false)
+	if n := harness.txPool.NumRateLimited(); n != 1 {
+		t.Fatalf("unexpected number of rate limited txns: got %d, want 1", n)
+	}
+
+	// Ensure the same transaction is accepted when it is exempt from rate
+	// limiting and that the number of throttled transactions is unchanged.
+	_, err = harness.txPool.ProcessTransaction(txns[1], false, false, true, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept exempt tx: %v", err)
+	}
+	testPoolMembership(tc, txns[1], false, true)
+	if n := harness.txPool.NumRateLimited(); n != 1 {
+		t.Fatalf("unexpected number of rate limited txns: got %d, want 1", n)
+	}
+}
@@ -0,0 +1,494 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/addrmgr"
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/internal/version"
+	"github.com/decred/dcrd/wire"
+)
+
+const (
+	// metricsPath is the path the metrics server serves metrics at.
+	metricsPath = "/metrics"
+
+	// metricsContentType is the content type of the Prometheus text-based
+	// exposition format served by the metrics server.
+	metricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	// metricsReadTimeout is the maximum amount of time the metrics server
+	// waits for a scrape request to be read.
+	metricsReadTimeout = 10 * time.Second
+)
+
+// rpcLatencyBuckets are the upper bounds, in seconds, of the buckets of the
+// RPC request duration histograms.
+var rpcLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25,
+	0.5, 1, 2.5, 5, 10}
+
+// metricsOnionCatNet is the IPv6 address block used to encode Tor onion
+// addresses (fd87:d87e:eb43::/48).
+var metricsOnionCatNet = net.IPNet{
+	IP:   net.ParseIP("fd87:d87e:eb43::"),
+	Mask: net.CIDRMask(48, 128),
+}
+
+// metricsLabelEscaper escapes label values per the Prometheus text-based
+// exposition format.
+var metricsLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricsWriter accumulates metrics in the Prometheus text-based exposition
+// format.
+type metricsWriter struct {
+	bytes.Buffer
+}
+
+// family writes the help and type lines that describe the metric family with
+// the provided name.  It must be called once before writing the samples of the
+// family.
+func (w *metricsWriter) family(name, typ, help string) {
+	w.WriteString("# HELP " + name + " " + help + "\n")
+	w.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// sample writes a single sample of the metric with the provided name and
+// value.  The labels are provided as alternating names and values.
+func (w *metricsWriter) sample(name string, value float64, labels ...string) {
+	w.WriteString(name)
+	if len(labels) > 0 {
+		w.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			w.WriteString(labels[i] + `="`)
+			w.WriteString(metricsLabelEscaper.Replace(labels[i+1]))
+			w.WriteByte('"')
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	switch {
+	case math.IsInf(value, 1):
+		w.WriteString("+Inf")
+	case math.IsInf(value, -1):
+		w.WriteString("-Inf")
+	default:
+		w.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	w.WriteByte('\n')
+}
+
+// gauge writes a metric family with the provided name that consists of a
+// single unlabeled gauge sample.
+func (w *metricsWriter) gauge(name, help string, value float64) {
+	w.family(name, "gauge", help)
+	w.sample(name, value)
+}
+
+// counter writes a metric family with the provided name that consists of a
+// single unlabeled counter sample.
+func (w *metricsWriter) counter(name, help string, value float64) {
+	w.family(name, "counter", help)
+	w.sample(name, value)
+}
+
+// latencyHistogram tracks the distribution of the durations of a single type
+// of request.
+type latencyHistogram struct {
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// rpcLatencyStats tracks the distribution of the time taken to handle RPC
+// requests for each method.
+type rpcLatencyStats struct {
+	mtx     sync.Mutex
+	methods map[string]*latencyHistogram
+}
+
+// newRPCLatencyStats returns a new instance that is ready to track the time
+// taken to handle RPC requests.
+func newRPCLatencyStats() *rpcLatencyStats {
+	return &rpcLatencyStats{methods: make(map[string]*latencyHistogram)}
+}
+
+// observe records that a request for the provided RPC method took the provided
+// amount of time to handle.
+//
+// This function is safe for concurrent access and may be called on a nil
+// instance, in which case nothing is recorded.
+func (s *rpcLatencyStats) observe(method string, d time.Duration) {
+	if s == nil {
+		return
+	}
+
+	seconds := d.Seconds()
+	s.mtx.Lock()
+	h, ok := s.methods[method]
+	if !ok {
+		h = &latencyHistogram{buckets: make([]uint64, len(rpcLatencyBuckets))}
+		s.methods[method] = h
+	}
+	for i, upperBound := range rpcLatencyBuckets {
+		if seconds <= upperBound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+	s.mtx.Unlock()
+}
+
+// write writes the RPC request duration histograms to the provided metrics
+// writer.
+//
+// This function is safe for concurrent access and may be called on a nil
+// instance, in which case no histograms are written.
+func (s *rpcLatencyStats) write(w *metricsWriter) {
+	if s == nil {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	methods := make([]string, 0, len(s.methods))
+	for method := range s.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	const name = "dcrd_rpc_request_duration_seconds"
+	w.family(name, "histogram", "Time taken to handle RPC requests by method.")
+	for _, method := range methods {
+		h := s.methods[method]
+		for i, upperBound := range rpcLatencyBuckets {
+			w.sample(name+"_bucket", float64(h.buckets[i]), "method",
+				method, "le", strconv.FormatFloat(upperBound, 'g', -1, 64))
+		}
+		w.sample(name+"_bucket", float64(h.count), "method", method, "le",
+			"+Inf")
+		w.sample(name+"_sum", h.sum, "method", method)
+		w.sample(name+"_count", float64(h.count), "method", method)
+	}
+}
+
+// peerNetwork returns the name of the network the provided peer address
+// belongs to for use as a metric label.
+func peerNetwork(na *wire.NetAddress) string {
+	switch {
+	case na == nil:
+		return "unknown"
+	case na.IP.To4() != nil:
+		return "ipv4"
+	case metricsOnionCatNet.Contains(na.IP):
+		return "onion"
+	default:
+		return "ipv6"
+	}
+}
+
+// mempoolTxTypeLabel returns the name of the provided transaction type for use
+// as a metric label.  The names match those accepted by getrawmempool.
+func mempoolTxTypeLabel(txType stake.TxType) string {
+	switch txType {
+	case stake.TxTypeSStx:
+		return "tickets"
+	case stake.TxTypeSSGen:
+		return "votes"
+	case stake.TxTypeSSRtx:
+		return "revocations"
+	default:
+		return "regular"
+	}
+}
+
+// addrManagerNetworks are the types of networks known addresses are counted by
+// in the address manager metrics.
+var addrManagerNetworks = []addrmgr.NetworkAddress{addrmgr.IPv4Address,
+	addrmgr.IPv6Address, addrmgr.OnionAddress, addrmgr.CJDNSAddress}
+
+// bucketStates returns the number of the provided buckets that are empty,
+// partially filled, and full given their counts and maximum size.
+func bucketStates(counts []int, size int) (empty, partial, full int) {
+	for _, count := range counts {
+		switch {
+		case count == 0:
+			empty++
+		case count >= size:
+			full++
+		default:
+			partial++
+		}
+	}
+	return empty, partial, full
+}
+
+// writeAddrManagerMetrics writes the provided address manager metrics to the
+// provided metrics writer.
+func writeAddrManagerMetrics(w *metricsWriter, m *addrmgr.Metrics) {
+	w.family("dcrd_addrmgr_addresses", "gauge", "Number of known "+
+		"addresses by table.")
+	w.sample("dcrd_addrmgr_addresses", float64(m.NewAddresses), "table",
+		"new")
+	w.sample("dcrd_addrmgr_addresses", float64(m.TriedAddresses), "table",
+		"tried")
+
+	w.family("dcrd_addrmgr_network_addresses", "gauge", "Number of known "+
+		"addresses by network.")
+	for _, network := range addrManagerNetworks {
+		w.sample("dcrd_addrmgr_network_addresses",
+			float64(m.AddressesByNetwork[network]), "network",
+			network.String())
+	}
+
+	w.family("dcrd_addrmgr_buckets", "gauge", "Number of address buckets "+
+		"by table and fill state.")
+	tables := []struct {
+		name   string
+		counts []int
+		size   int
+	}{
+		{"new", m.NewBucketCounts, m.NewBucketSize},
+		{"tried", m.TriedBucketCounts, m.TriedBucketSize},
+	}
+	for _, table := range tables {
+		empty, partial, full := bucketStates(table.counts, table.size)
+		w.sample("dcrd_addrmgr_buckets", float64(empty), "table",
+			table.name, "state", "empty")
+		w.sample("dcrd_addrmgr_buckets", float64(partial), "table",
+			table.name, "state", "partial")
+		w.sample("dcrd_addrmgr_buckets", float64(full), "table",
+			table.name, "state", "full")
+	}
+
+	w.counter("dcrd_addrmgr_attempts_total", "Total number of connection "+
+		"attempts to known addresses.", float64(m.Attempts))
+	w.counter("dcrd_addrmgr_promotions_total", "Total number of addresses "+
+		"promoted to the tried table.", float64(m.Promotions))
+	w.counter("dcrd_addrmgr_evictions_total", "Total number of addresses "+
+		"evicted to make room for other addresses.", float64(m.Evictions))
+	w.counter("dcrd_addrmgr_expirations_total", "Total number of "+
+		"addresses forgotten for no longer being usable.",
+		float64(m.Expirations))
+
+	const name = "dcrd_addrmgr_save_duration_seconds"
+	w.family(name, "summary", "Time taken to save the known addresses to "+
+		"disk.")
+	w.sample(name+"_sum", m.SaveDuration.Seconds())
+	w.sample(name+"_count", float64(m.Saves))
+	w.gauge("dcrd_addrmgr_last_save_duration_seconds", "Time taken by "+
+		"the most recent save of the known addresses to disk.",
+		m.LastSaveDuration.Seconds())
+}
+
+// dirSize returns the total size of all regular files in the directory tree
+// rooted at the provided path.
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// metricsServer provides an HTTP endpoint that exposes metrics about the node
+// in the Prometheus text-based exposition format so it can be scraped directly
+// by monitoring systems.
+type metricsServer struct {
+	server    *server
+	listeners []net.Listener
+	wg        sync.WaitGroup
+}
+
+// newMetricsServer returns a new metrics server that serves metrics about the
+// provided server on the provided listeners once it is run.
+func newMetricsServer(s *server, listeners []net.Listener) *metricsServer {
+	return &metricsServer{
+		server:    s,
+		listeners: listeners,
+	}
+}
+
+// handleMetrics responds to scrape requests with the current metrics.
+func (m *metricsServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 Method Not Allowed.",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	var mw metricsWriter
+	m.writeMetrics(&mw)
+	w.Header().Set("Content-Type", metricsContentType)
+	w.Write(mw.Bytes())
+}
+
+// writeMetrics writes the current metrics about the server to the provided
+// metrics writer.
+func (m *metricsServer) writeMetrics(w *metricsWriter) {
+	s := m.server
+
+	w.family("dcrd_info", "gauge", "Version and network of the node.")
+	w.sample("dcrd_info", 1, "version", version.String(), "network",
+		s.chainParams.Name)
+
+	// Chain and sync state.
+	best := s.chain.BestSnapshot()
+	syncHeight := s.blockManager.SyncHeight()
+	var syncProgress float64
+	if syncHeight > 0 {
+		syncProgress = math.Min(float64(best.Height)/float64(syncHeight), 1)
+	}
+	var current float64
+	if s.chain.IsCurrent() {
+		current = 1
+	}
+	w.gauge("dcrd_chain_height", "Height of the best chain.",
+		float64(best.Height))
+	if header, err := s.chain.HeaderByHash(&best.Hash); err == nil {
+		w.gauge("dcrd_chain_best_block_timestamp_seconds", "Timestamp of "+
+			"the best block.", float64(header.Timestamp.Unix()))
+	}
+	w.gauge("dcrd_chain_total_transactions", "Total number of "+
+		"transactions in the best chain.", float64(best.TotalTxns))
+	w.gauge("dcrd_chain_current", "Whether or not the chain believes it "+
+		"is current (1) or still syncing (0).", current)
+	w.gauge("dcrd_sync_height", "Height of the best chain known to the "+
+		"sync peers.", float64(syncHeight))
+	w.gauge("dcrd_sync_progress", "Estimated fraction of the chain that "+
+		"has been synced.", syncProgress)
+
+	// Peers by direction and network.
+	directions := []string{"inbound", "outbound"}
+	networks := []string{"ipv4", "ipv6", "onion", "unknown"}
+	peerCounts := make(map[[2]string]int)
+	for _, sp := range s.Peers() {
+		direction := "outbound"
+		if sp.Inbound() {
+			direction = "inbound"
+		}
+		peerCounts[[2]string{direction, peerNetwork(sp.NA())}]++
+	}
+	w.family("dcrd_peers", "gauge", "Number of connected peers by "+
+		"direction and network.")
+	for _, direction := range directions {
+		for _, network := range networks {
+			count := peerCounts[[2]string{direction, network}]
+			w.sample("dcrd_peers", float64(count), "direction", direction,
+				"network", network)
+		}
+	}
+	w.counter("dcrd_peer_received_bytes_total", "Total number of bytes "+
+		"received from peers.", float64(atomic.LoadUint64(&s.bytesReceived)))
+	w.counter("dcrd_peer_sent_bytes_total", "Total number of bytes sent "+
+		"to peers.", float64(atomic.LoadUint64(&s.bytesSent)))
+
+	// Address manager health.
+	writeAddrManagerMetrics(w, s.addrManager.Metrics())
+
+	// Mempool sizes by transaction type.
+	txTypes := []string{"regular", "tickets", "votes", "revocations"}
+	mempoolTxns := make(map[string]int, len(txTypes))
+	mempoolBytes := make(map[string]int, len(txTypes))
+	for _, txD := range s.txMemPool.TxDescs() {
+		txType := mempoolTxTypeLabel(txD.Type)
+		mempoolTxns[txType]++
+		mempoolBytes[txType] += txD.Tx.MsgTx().SerializeSize()
+	}
+	w.family("dcrd_mempool_transactions", "gauge", "Number of "+
+		"transactions in the mempool by type.")
+	for _, txType := range txTypes {
+		w.sample("dcrd_mempool_transactions", float64(mempoolTxns[txType]),
+			"type", txType)
+	}
+	w.family("dcrd_mempool_bytes", "gauge", "Serialized size of the "+
+		"transactions in the mempool by type.")
+	for _, txType := range txTypes {
+		w.sample("dcrd_mempool_bytes", float64(mempoolBytes[txType]),
+			"type", txType)
+	}
+	w.counter("dcrd_mempool_rate_limited_total", "Total number of free "+
+		"and low-fee transactions rejected by the mempool rate limiter.",
+		float64(s.txMemPool.NumRateLimited()))
+
+	// RPC server state when it is enabled.
+	if rpc := s.rpcServer; rpc != nil {
+		w.gauge("dcrd_rpc_clients", "Number of connected standard RPC "+
+			"clients.", float64(atomic.LoadInt32(&rpc.numClients)))
+		w.gauge("dcrd_rpc_websocket_clients", "Number of connected "+
+			"websocket RPC clients.", float64(rpc.ntfnMgr.NumClients()))
+		limitInfo := rpc.clientLimiter.info()
+		w.family("dcrd_rpc_limited_requests_total", "counter", "Total "+
+			"number of RPC requests rejected by the per client limits.")
+		w.sample("dcrd_rpc_limited_requests_total",
+			float64(limitInfo.RateLimited), "limit", "rate")
+		w.sample("dcrd_rpc_limited_requests_total",
+			float64(limitInfo.ConcurrencyLimited), "limit", "concurrency")
+		rpc.latencyStats.write(w)
+	}
+
+	// Database size on disk.  The in-memory database does not have one.
+	if cfg.DbType != "memdb" {
+		if size, err := dirSize(blockDbPath(cfg.DbType)); err == nil {
+			w.gauge("dcrd_database_size_bytes", "Size of the block "+
+				"database on disk.", float64(size))
+		} else {
+			srvrLog.Debugf("Unable to determine database size: %v", err)
+		}
+	}
+}
+
+// Run starts the metrics server and blocks until the provided context is
+// cancelled.
+func (m *metricsServer) Run(ctx context.Context) {
+	srvrLog.Trace("Starting metrics server")
+	mux := http.NewServeMux()
+	mux.HandleFunc(metricsPath, m.handleMetrics)
+	httpServer := &http.Server{
+		Handler:     mux,
+		ReadTimeout: metricsReadTimeout,
+	}
+	for _, listener := range m.listeners {
+		m.wg.Add(1)
+		go func(listener net.Listener) {
+			srvrLog.Infof("Metrics server listening on %s", listener.Addr())
+			httpServer.Serve(listener)
+			srvrLog.Tracef("Metrics listener done for %s", listener.Addr())
+			m.wg.Done()
+		}(listener)
+	}
+
+	<-ctx.Done()
+	if err := httpServer.Close(); err != nil {
+		srvrLog.Errorf("Problem shutting down metrics server: %v", err)
+	}
+	m.wg.Wait()
+	srvrLog.Trace("Metrics server stopped")
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/addrmgr"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestMetricsWriter ensures metrics are written in the Prometheus text-based
+// exposition format.
+func TestMetricsWriter(t *testing.T) {
+	var w metricsWriter
+	w.gauge("dcrd_chain_height", "Height of the best chain.", 1234)
+	w.family("dcrd_peers", "gauge", "Number of connected peers.")
+	w.sample("dcrd_peers", 8, "direction", "outbound", "network", "ipv4")
+	w.sample("escaped", 0.5, "label", "a\"b\\c\nd")
+	w.sample("infinite", math.Inf(1))
+
+	want := "# HELP dcrd_chain_height Height of the best chain.\n" +
+		"# TYPE dcrd_chain_height gauge\n" +
+		"dcrd_chain_height 1234\n" +
+		"# HELP dcrd_peers Number of connected peers.\n" +
+		"# TYPE dcrd_peers gauge\n" +
+		"dcrd_peers{direction=\"outbound\",network=\"ipv4\"} 8\n" +
+		"escaped{label=\"a\\\"b\\\\c\\nd\"} 0.5\n" +
+		"infinite +Inf\n"
+	if got := w.String(); got != want {
+		t.Fatalf("unexpected metrics output -- got:\n%s\nwant:\n%s", got,
+			want)
+	}
+}
+
+// TestRPCLatencyStats ensures RPC request durations are tracked in cumulative
+// histogram buckets per method.
+func TestRPCLatencyStats(t *testing.T) {
+	s := newRPCLatencyStats()
+	s.observe("getblockcount", 2*time.Millisecond)
+	s.observe("getblockcount", 20*time.Second)
+	s.observe("getbestblock", time.Millisecond)
+
+	var w metricsWriter
+	s.write(&w)
+	want := "# HELP dcrd_rpc_request_duration_seconds Time taken to handle " +
+		"RPC requests by method.\n" +
+		"# TYPE dcrd_rpc_request_duration_seconds histogram\n"
+	for _, method := range []string{"getbestblock", "getblockcount"} {
+		counts := []string{"1", "1", "1", "1", "1", "1", "1", "1", "1", "1",
+			"1", "1", "1"}
+		sum, count := "0.001", "1"
+		if method == "getblockcount" {
+			counts = []string{"0", "1", "1", "1", "1", "1", "1", "1", "1",
+				"1", "1", "1", "2"}
+			sum, count = "20.002", "2"
+		}
+		bounds := []string{"0.001", "0.005", "0.01", "0.025", "0.05", "0.1",
+			"0.25", "0.5", "1", "2.5", "5", "10", "+Inf"}
+		for i, bound := range bounds {
+			want += "dcrd_rpc_request_duration_seconds_bucket{method=\"" +
+				method + "\",le=\"" + bound + "\"} " + counts[i] + "\n"
+		}
+		want += "dcrd_rpc_request_duration_seconds_sum{method=\"" + method +
+			"\"} " + sum + "\n"
+		want += "dcrd_rpc_request_duration_seconds_count{method=\"" +
+			method + "\"} " + count + "\n"
+	}
+	if got := w.String(); got != want {
+		t.Fatalf("unexpected metrics output -- got:\n%s\nwant:\n%s", got,
+			want)
+	}
+
+	// A nil instance must not record or write anything.
+	var nilStats *rpcLatencyStats
+	nilStats.observe("getblockcount", time.Second)
+	var nilWriter metricsWriter
+	nilStats.write(&nilWriter)
+	if nilWriter.Len() != 0 {
+		t.Fatalf("unexpected output for nil stats: %s", nilWriter.String())
+	}
+}
+
+// TestPeerNetwork ensures peer addresses are classified into the expected
+// networks.
+func TestPeerNetwork(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"127.0.0.1", "ipv4"},
+		{"::ffff:1.2.3.4", "ipv4"},
+		{"2001:db8::1", "ipv6"},
+		{"fd87:d87e:eb43:1:2:3:4:5", "onion"},
+	}
+	for _, test := range tests {
+		na := &wire.NetAddress{IP: net.ParseIP(test.ip)}
+		if got := peerNetwork(na); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.ip, got, test.want)
+		}
+	}
+	if got := peerNetwork(nil); got != "unknown" {
+		t.Errorf("nil address: got %q, want %q", got, "unknown")
+	}
+}
+
+// TestAddrManagerMetrics ensures address manager metrics are written with the
+// expected bucket fill states and network counts.
+func TestAddrManagerMetrics(t *testing.T) {
+	m := &addrmgr.Metrics{
+		NewAddresses:      3,
+		TriedAddresses:    2,
+		NewBucketCounts:   []int{0, 1, 2},
+		TriedBucketCounts: []int{2, 0},
+		NewBucketSize:     2,
+		TriedBucketSize:   4,
+		AddressesByNetwork: map[addrmgr.NetworkAddress]int{
+			addrmgr.IPv4Address:  4,
+			addrmgr.OnionAddress: 1,
+		},
+		Attempts:         7,
+		Promotions:       2,
+		Evictions:        1,
+		Saves:            2,
+		SaveDuration:     1500 * time.Millisecond,
+		LastSaveDuration: 500 * time.Millisecond,
+	}
+
+	var w metricsWriter
+	writeAddrManagerMetrics(&w, m)
+	got := w.String()
+	wantSamples := []string{
+		"dcrd_addrmgr_addresses{table=\"new\"} 3\n",
+		"dcrd_addrmgr_addresses{table=\"tried\"} 2\n",
+		"dcrd_addrmgr_network_addresses{network=\"ipv4\"} 4\n",
+		"dcrd_addrmgr_network_addresses{network=\"ipv6\"} 0\n",
+		"dcrd_addrmgr_network_addresses{network=\"onion\"} 1\n",
+		"dcrd_addrmgr_network_addresses{network=\"cjdns\"} 0\n",
+		"dcrd_addrmgr_buckets{table=\"new\",state=\"empty\"} 1\n",
+		"dcrd_addrmgr_buckets{table=\"new\",state=\"partial\"} 1\n",
+		"dcrd_addrmgr_buckets{table=\"new\",state=\"full\"} 1\n",
+		"dcrd_addrmgr_buckets{table=\"tried\",state=\"empty\"} 1\n",
+		"dcrd_addrmgr_buckets{table=\"tried\",state=\"partial\"} 1\n",
+		"dcrd_addrmgr_buckets{table=\"tried\",state=\"full\"} 0\n",
+		"dcrd_addrmgr_attempts_total 7\n",
+		"dcrd_addrmgr_promotions_total 2\n",
+		"dcrd_addrmgr_evictions_total 1\n",
+		"dcrd_addrmgr_expirations_total 0\n",
+		"dcrd_addrmgr_save_duration_seconds_sum 1.5\n",
+		"dcrd_addrmgr_save_duration_seconds_count 2\n",
+		"dcrd_addrmgr_last_save_duration_seconds 0.5\n",
+	}
+	for _, sample := range wantSamples {
+		if !strings.Contains(got, sample) {
+			t.Errorf("missing sample %q in metrics output:\n%s", sample,
+				got)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/v3"
+)
+
+// mockTimeSource wraps a median time source to allow the adjusted time to be
+// advanced on demand.  It is only used on the test networks so test suites can
+// deterministically script scenarios that depend on the passage of time
+// without waiting for it to actually elapse.
+type mockTimeSource struct {
+	blockchain.MedianTimeSource
+
+	// offset is the additional number of nanoseconds the adjusted time has
+	// been advanced by.  It must be accessed atomically.
+	offset int64
+}
+
+// Ensure the mockTimeSource type implements the blockchain.MedianTimeSource
+// interface.
+var _ blockchain.MedianTimeSource = (*mockTimeSource)(nil)
+
+// newMockTimeSource returns a new time source that wraps the provided median
+// time source.
+func newMockTimeSource(timeSource blockchain.MedianTimeSource) *mockTimeSource {
+	return &mockTimeSource{MedianTimeSource: timeSource}
+}
+
+// AdjustedTime returns the current time adjusted by the median time offset of
+// the wrapped time source plus the amount of time it has been advanced by.
+//
+// This function is safe for concurrent access and is part of the
+// blockchain.MedianTimeSource interface implementation.
+func (m *mockTimeSource) AdjustedTime() time.Time {
+	return m.MedianTimeSource.AdjustedTime().Add(m.advanced())
+}
+
+// Offset returns the median time offset of the wrapped time source plus the
+// amount of time it has been advanced by.
+//
+// This function is safe for concurrent access and is part of the
+// blockchain.MedianTimeSource interface implementation.
+func (m *mockTimeSource) Offset() time.Duration {
+	return m.MedianTimeSource.Offset() + m.advanced()
+}
+
+// advanced returns the total amount of time the time source has been advanced
+// by.
+func (m *mockTimeSource) advanced() time.Duration {
+	return time.Duration(atomic.LoadInt64(&m.offset))
+}
+
+// Advance moves the adjusted time forward by the provided duration and returns
+// the new adjusted time.
+//
+// This function is safe for concurrent access.
+func (m *mockTimeSource) Advance(d time.Duration) time.Time {
+	atomic.AddInt64(&m.offset, int64(d))
+	return m.AdjustedTime()
+}
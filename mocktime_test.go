@@ -0,0 +1,38 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/v3"
+)
+
+// TestMockTimeSource ensures advancing the mock time source moves both the
+// adjusted time and the offset forward by the expected amount.
+func TestMockTimeSource(t *testing.T) {
+	timeSource := newMockTimeSource(blockchain.NewMedianTime())
+	if offset := timeSource.Offset(); offset != 0 {
+		t.Fatalf("unexpected initial offset -- got %v, want 0", offset)
+	}
+
+	// The median time source only has one second precision.
+	before := time.Now().Truncate(time.Second)
+	adjusted := timeSource.Advance(time.Hour)
+	if adjusted.Before(before.Add(time.Hour)) {
+		t.Fatalf("adjusted time %v was not advanced by an hour from %v",
+			adjusted, before)
+	}
+	timeSource.Advance(30 * time.Minute)
+	if offset := timeSource.Offset(); offset != 90*time.Minute {
+		t.Fatalf("unexpected offset -- got %v, want %v", offset,
+			90*time.Minute)
+	}
+	if got := timeSource.AdjustedTime(); got.Before(adjusted.Add(30 * time.Minute)) {
+		t.Fatalf("unexpected adjusted time -- got %v, want at least %v", got,
+			adjusted.Add(30*time.Minute))
+	}
+}
@@ -13,7 +13,9 @@ import (
 // network and test networks.
 type params struct {
 	*chaincfg.Params
-	rpcPort string
+	rpcPort     string
+	grpcPort    string
+	metricsPort string
 }
 
 // mainNetParams contains parameters specific to the main network
@@ -23,27 +25,35 @@ type params struct {
 // it does not handle on to dcrd.  This approach allows the wallet process
 // to emulate the full reference implementation RPC API.
 var mainNetParams = params{
-	Params:  chaincfg.MainNetParams(),
-	rpcPort: "9109",
+	Params:      chaincfg.MainNetParams(),
+	rpcPort:     "9109",
+	grpcPort:    "9119",
+	metricsPort: "9120",
 }
 
 // testNet3Params contains parameters specific to the test network (version 3)
 // (wire.TestNet3).
 var testNet3Params = params{
-	Params:  chaincfg.TestNet3Params(),
-	rpcPort: "19109",
+	Params:      chaincfg.TestNet3Params(),
+	rpcPort:     "19109",
+	grpcPort:    "19119",
+	metricsPort: "19120",
 }
 
 // simNetParams contains parameters specific to the simulation test network
 // (wire.SimNet).
 var simNetParams = params{
-	Params:  chaincfg.SimNetParams(),
-	rpcPort: "19556",
+	Params:      chaincfg.SimNetParams(),
+	rpcPort:     "19556",
+	grpcPort:    "19558",
+	metricsPort: "19559",
 }
 
 // regNetParams contains parameters specific to the regression test
 // network (wire.RegNet).
 var regNetParams = params{
-	Params:  chaincfg.RegNetParams(),
-	rpcPort: "18656",
+	Params:      chaincfg.RegNetParams(),
+	rpcPort:     "18656",
+	grpcPort:    "18658",
+	metricsPort: "18659",
 }
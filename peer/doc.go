@@ -34,6 +34,7 @@ A quick overview of the major features peer provides are as follows:
  - Inventory message batching and send trickling with known inventory detection
    and avoidance
  - Automatic periodic keep-alive pinging and pong responses
+ - Ping round trip time percentiles and jitter over recent pings
  - Random nonce generation and self connection detection
  - Snapshottable peer statistics such as the total number of bytes read and
    written, the remote address, user agent, and negotiated protocol version
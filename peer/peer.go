@@ -26,7 +26,7 @@ import (
 
 const (
 	// MaxProtocolVersion is the max protocol version the peer supports.
-	MaxProtocolVersion = wire.CFilterV2Version
+	MaxProtocolVersion = wire.BlocksOnlyVoteRelayVersion
 
 	// outputBufferSize is the number of elements the output channels use.
 	outputBufferSize = 5000
@@ -122,7 +122,8 @@ type MessageListeners struct {
 	// OnTx is invoked when a peer receives a tx wire message.
 	OnTx func(p *Peer, msg *wire.MsgTx)
 
-	// OnBlock is invoked when a peer receives a block wire message.
+	// OnBlock is invoked when a peer receives a block wire message.  The
+	// raw bytes of the block are nil when the StreamBlocks option is set.
 	OnBlock func(p *Peer, msg *wire.MsgBlock, buf []byte)
 
 	// OnCFilter is invoked when a peer receives a cfilter wire message.
@@ -266,6 +267,20 @@ type Config struct {
 	// IdleTimeout is the duration of inactivity before a peer is timed
 	// out in seconds.
 	IdleTimeout time.Duration
+
+	// MessageLimits specifies optional per-command limits on the size and
+	// rate of messages received from the peer.  They are enforced before
+	// the messages are deserialized.  Peers that send messages which exceed
+	// any of the limits are disconnected.  This may be nil in which case
+	// only the limits defined by the wire protocol are enforced.
+	MessageLimits *wire.MessageLimits
+
+	// StreamBlocks specifies that block messages are decoded as they are
+	// read from the remote peer instead of first reading their entire
+	// payload into memory, which reduces peak memory usage when many blocks
+	// are being downloaded.  The raw bytes passed to the OnBlock listener
+	// are nil when it is set.
+	StreamBlocks bool
 }
 
 // minUint32 is a helper function to return the minimum of two uint32s.
@@ -368,6 +383,14 @@ type StatsSnap struct {
 	LastPingNonce  uint64
 	LastPingTime   time.Time
 	LastPingMicros int64
+
+	// PingP50Micros, PingP95Micros, and PingJitterMicros are the median
+	// and 95th percentile round trip times and the mean absolute
+	// difference between consecutive round trip times of the most recent
+	// pings.
+	PingP50Micros    int64
+	PingP95Micros    int64
+	PingJitterMicros int64
 }
 
 // HashFunc is a function which returns a block hash, height and error
@@ -425,6 +448,10 @@ type Peer struct {
 	cfg     Config
 	inbound bool
 
+	// msgLimiter enforces the configured message limits.  It is only
+	// accessed by the goroutine reading messages from the remote peer.
+	msgLimiter *wire.MessageLimiter
+
 	flagsMtx             sync.Mutex // protects the peer flags below
 	na                   *wire.NetAddress
 	id                   int32
@@ -456,6 +483,7 @@ type Peer struct {
 	lastPingNonce      uint64    // Set to nonce if we have a pending ping.
 	lastPingTime       time.Time // Time we sent last ping.
 	lastPingMicros     int64     // Time for last ping to return.
+	pingStats          pingStats // Stats for recent pings.
 
 	stallControl  chan stallControlMsg
 	outputQueue   chan outMsg
@@ -544,6 +572,10 @@ func (p *Peer) StatsSnapshot() *StatsSnap {
 		LastPingNonce:  p.lastPingNonce,
 		LastPingMicros: p.lastPingMicros,
 		LastPingTime:   p.lastPingTime,
+
+		PingP50Micros:    p.pingStats.percentile(0.5),
+		PingP95Micros:    p.pingStats.percentile(0.95),
+		PingJitterMicros: p.pingStats.jitter(),
 	}
 
 	p.statsMtx.RUnlock()
@@ -966,6 +998,7 @@ func (p *Peer) handlePongMsg(msg *wire.MsgPong) {
 		p.lastPingMicros = time.Since(p.lastPingTime).Nanoseconds()
 		p.lastPingMicros /= 1000 // convert to usec.
 		p.lastPingNonce = 0
+		p.pingStats.add(p.lastPingMicros)
 	}
 	p.statsMtx.Unlock()
 }
@@ -976,8 +1009,12 @@ func (p *Peer) readMessage() (wire.Message, []byte, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	n, msg, buf, err := wire.ReadMessageN(p.conn, p.ProtocolVersion(),
-		p.cfg.Net)
+	readMessageN := wire.ReadMessageLimitedN
+	if p.cfg.StreamBlocks {
+		readMessageN = wire.ReadMessageStreamN
+	}
+	n, msg, buf, err := readMessageN(p.conn, p.ProtocolVersion(), p.cfg.Net,
+		p.msgLimiter)
 	atomic.AddUint64(&p.bytesReceived, uint64(n))
 	if p.cfg.Listeners.OnRead != nil {
 		p.cfg.Listeners.OnRead(p, n, msg, err)
@@ -1273,6 +1310,15 @@ out:
 		// is done.  The timer is reset below for the next iteration if
 		// needed.
 		rmsg, buf, err := p.readMessage()
+		if errors.Is(err, wire.ErrMsgRateExceeded) {
+			// Peers that send messages faster than the configured
+			// rate are misbehaving.  Silently discarding the messages
+			// instead could stall the peer when they are responses to
+			// requests, so disconnect the peer.
+			log.Warnf("Peer %s exceeded the message rate limit: %v -- "+
+				"disconnecting", p, err)
+			break out
+		}
 		if err != nil {
 			// Only log the error and send reject message if the
 			// local peer is not forcibly disconnecting and the
@@ -2098,6 +2144,9 @@ func newPeerBase(cfgOrig *Config, inbound bool) *Peer {
 		services:        cfg.Services,
 		protocolVersion: protocolVersion,
 	}
+	if cfg.MessageLimits != nil {
+		p.msgLimiter = wire.NewMessageLimiter(cfg.MessageLimits)
+	}
 	return &p
 }
 
@@ -773,6 +773,82 @@ func TestDuplicateVersionMsg(t *testing.T) {
 	}
 }
 
+// TestMessageRateLimit ensures that receiving messages faster than the
+// configured rate results in the peer being disconnected.
+func TestMessageRateLimit(t *testing.T) {
+	// Create a pair of peers that are connected to each other using a fake
+	// connection where the inbound peer only permits a single getaddr
+	// message per hour.
+	verack := make(chan struct{})
+	outCfg := &Config{
+		Listeners: MessageListeners{
+			OnVerAck: func(p *Peer, msg *wire.MsgVerAck) {
+				verack <- struct{}{}
+			},
+		},
+		UserAgentName:    "peer",
+		UserAgentVersion: "1.0",
+		Net:              wire.MainNet,
+		Services:         0,
+	}
+	inCfg := *outCfg
+	inCfg.MessageLimits = &wire.MessageLimits{
+		Rates: map[string]wire.MessageRate{
+			wire.CmdGetAddr: {Messages: 1, Interval: time.Hour},
+		},
+	}
+	inConn, outConn := pipe(
+		&conn{laddr: "10.0.0.1:9108", raddr: "10.0.0.2:9108"},
+		&conn{laddr: "10.0.0.2:9108", raddr: "10.0.0.1:9108"},
+	)
+	outPeer, err := NewOutboundPeer(outCfg, inConn.laddr)
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected err: %v\n", err)
+	}
+	outPeer.AssociateConnection(outConn)
+	inPeer := NewInboundPeer(&inCfg)
+	inPeer.AssociateConnection(inConn)
+
+	// Wait for the veracks from the initial protocol version negotiation.
+	for i := 0; i < 2; i++ {
+		select {
+		case <-verack:
+		case <-time.After(time.Second):
+			t.Fatal("verack timeout")
+		}
+	}
+
+	disconnected := make(chan struct{}, 1)
+	go func() {
+		inPeer.WaitForDisconnect()
+		disconnected <- struct{}{}
+	}()
+
+	// Ensure a message within the permitted rate does not result in a
+	// disconnect.
+	done := make(chan struct{})
+	outPeer.QueueMessage(wire.NewMsgGetAddr(), done)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("send getaddr timeout")
+	}
+	select {
+	case <-disconnected:
+		t.Fatal("peer disconnected within the permitted rate")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Ensure the peer disconnects once the permitted rate is exceeded.
+	outPeer.QueueMessage(wire.NewMsgGetAddr(), nil)
+	select {
+	case <-disconnected:
+	case <-time.After(time.Second):
+		t.Fatal("peer did not disconnect")
+	}
+	outPeer.Disconnect()
+}
+
 // TestNetFallback ensures the network is set to the expected value in
 // accordance with the parameters.
 func TestNetFallback(t *testing.T) {
@@ -0,0 +1,79 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"math"
+	"sort"
+)
+
+// maxPingSamples is the maximum number of ping round trip times that are kept
+// in the sliding window used to calculate ping statistics.  With the default
+// ping interval, this covers roughly the most recent hour.
+const maxPingSamples = 32
+
+// pingStats tracks the round trip times of the most recent pings to a peer in
+// order to provide statistics that are less sensitive to outliers than the
+// time of the latest ping alone.
+//
+// The stats are NOT safe for concurrent access.
+type pingStats struct {
+	samples []int64 // Round trip times in microseconds.
+	next    int     // Index the next sample replaces once the window is full.
+}
+
+// add records the provided round trip time in microseconds, replacing the
+// oldest sample once the window is full.
+func (s *pingStats) add(micros int64) {
+	if len(s.samples) < maxPingSamples {
+		s.samples = append(s.samples, micros)
+		return
+	}
+	s.samples[s.next] = micros
+	s.next = (s.next + 1) % maxPingSamples
+}
+
+// percentile returns the provided percentile, in the range [0, 1], of the
+// round trip times in the window using the nearest-rank method.  Zero is
+// returned when there are no samples.
+func (s *pingStats) percentile(p float64) int64 {
+	if len(s.samples) == 0 {
+		return 0
+	}
+	sorted := make([]int64, len(s.samples))
+	copy(sorted, s.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := int(math.Ceil(p * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// jitter returns the mean absolute difference between consecutive round trip
+// times in the window.  Zero is returned when there are fewer than two
+// samples.
+func (s *pingStats) jitter() int64 {
+	n := len(s.samples)
+	if n < 2 {
+		return 0
+	}
+
+	// The samples are in chronological order starting from the index the
+	// next sample replaces.
+	var total int64
+	prev := s.samples[s.next%n]
+	for i := 1; i < n; i++ {
+		sample := s.samples[(s.next+i)%n]
+		diff := sample - prev
+		if diff < 0 {
+			diff = -diff
+		}
+		total += diff
+		prev = sample
+	}
+	return total / int64(n-1)
+}
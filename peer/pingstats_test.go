@@ -0,0 +1,68 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package peer
+
+import (
+	"testing"
+)
+
+// TestPingStats ensures the ping statistics are calculated as expected over the
+// sliding window of recent pings.
+func TestPingStats(t *testing.T) {
+	var stats pingStats
+
+	// Ensure zero values are reported when there are no samples.
+	if p50 := stats.percentile(0.5); p50 != 0 {
+		t.Fatalf("unexpected p50 with no samples: %d", p50)
+	}
+	if jitter := stats.jitter(); jitter != 0 {
+		t.Fatalf("unexpected jitter with no samples: %d", jitter)
+	}
+
+	// Ensure the percentiles and jitter are calculated as expected before
+	// the window is full.
+	for _, micros := range []int64{100, 300, 200, 400} {
+		stats.add(micros)
+	}
+	tests := []struct {
+		name string
+		got  int64
+		want int64
+	}{
+		{"p50", stats.percentile(0.5), 200},
+		{"p95", stats.percentile(0.95), 400},
+		{"min", stats.percentile(0), 100},
+		{"jitter", stats.jitter(), (200 + 100 + 200) / 3},
+	}
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Fatalf("%s: unexpected value -- got %d, want %d", test.name,
+				test.got, test.want)
+		}
+	}
+
+	// Ensure the oldest samples are replaced once the window is full and
+	// the jitter is calculated from the samples in chronological order.
+	for i := 0; i < maxPingSamples; i++ {
+		micros := int64(1000)
+		if i == maxPingSamples-1 {
+			micros = 2000
+		}
+		stats.add(micros)
+	}
+	if len(stats.samples) != maxPingSamples {
+		t.Fatalf("unexpected number of samples: %d", len(stats.samples))
+	}
+	if p50 := stats.percentile(0.5); p50 != 1000 {
+		t.Fatalf("unexpected p50 after window is full: %d", p50)
+	}
+	if p100 := stats.percentile(1); p100 != 2000 {
+		t.Fatalf("unexpected max after window is full: %d", p100)
+	}
+	if jitter, want := stats.jitter(), int64(1000/(maxPingSamples-1)); jitter != want {
+		t.Fatalf("unexpected jitter after window is full -- got %d, want %d",
+			jitter, want)
+	}
+}
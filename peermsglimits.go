@@ -0,0 +1,119 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// defaultPeerMessageRates are the default rates imposed on messages received
+// from peers in addition to the limits defined by the wire protocol.  Addresses
+// are only requested once per connection and otherwise only periodically
+// advertised, so there is no reason for peers to send address messages
+// frequently.
+var defaultPeerMessageRates = map[string]wire.MessageRate{
+	wire.CmdAddr: {Messages: 10, Interval: time.Minute},
+}
+
+// parseMessageLimitCommand splits the provided message limit into the command
+// it applies to and the limit itself.  Message limits are of the form
+// <command>:<limit>.
+func parseMessageLimitCommand(s string) (string, string, error) {
+	i := strings.IndexByte(s, ':')
+	if i == -1 {
+		return "", "", fmt.Errorf("message limit %q is not of the form "+
+			"<command>:<limit>", s)
+	}
+	command, limit := s[:i], s[i+1:]
+	if command == "" || len(command) > wire.CommandSize {
+		return "", "", fmt.Errorf("invalid command in message limit %q", s)
+	}
+	return command, limit, nil
+}
+
+// parseMessageRate parses the provided message rate limit which is of the form
+// <command>:<messages>/<interval>, such as addr:10/1m to permit up to 10 addr
+// messages per minute.  A limit of zero messages means the command is not
+// rate limited.
+func parseMessageRate(s string) (string, wire.MessageRate, error) {
+	command, limit, err := parseMessageLimitCommand(s)
+	if err != nil {
+		return "", wire.MessageRate{}, err
+	}
+	parts := strings.Split(limit, "/")
+	if len(parts) != 2 {
+		return "", wire.MessageRate{}, fmt.Errorf("message rate limit %q "+
+			"is not of the form <command>:<messages>/<interval>", s)
+	}
+	messages, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return "", wire.MessageRate{}, fmt.Errorf("invalid number of "+
+			"messages in message rate limit %q", s)
+	}
+	interval, err := time.ParseDuration(parts[1])
+	if err != nil || interval <= 0 {
+		return "", wire.MessageRate{}, fmt.Errorf("invalid interval in "+
+			"message rate limit %q", s)
+	}
+	rate := wire.MessageRate{Messages: uint32(messages), Interval: interval}
+	return command, rate, nil
+}
+
+// parseMessageSize parses the provided message size limit which is of the form
+// <command>:<bytes>, such as headers:100000 to limit the payload of headers
+// messages to 100000 bytes.  A limit of zero bytes means only the limit defined
+// by the wire protocol applies.
+func parseMessageSize(s string) (string, uint32, error) {
+	command, limit, err := parseMessageLimitCommand(s)
+	if err != nil {
+		return "", 0, err
+	}
+	maxSize, err := strconv.ParseUint(limit, 10, 32)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid size in message size limit %q", s)
+	}
+	return command, uint32(maxSize), nil
+}
+
+// newPeerMessageLimits returns the limits imposed on messages received from
+// peers given the provided message rate and size limits.  The rate limits
+// override the default rate limits for the same commands.
+func newPeerMessageLimits(rates, sizes []string) (*wire.MessageLimits, error) {
+	limits := &wire.MessageLimits{
+		MaxSizes: make(map[string]uint32, len(sizes)),
+		Rates:    make(map[string]wire.MessageRate, len(rates)),
+	}
+	for command, rate := range defaultPeerMessageRates {
+		limits.Rates[command] = rate
+	}
+	for _, s := range rates {
+		command, rate, err := parseMessageRate(s)
+		if err != nil {
+			return nil, err
+		}
+		if rate.Messages == 0 {
+			delete(limits.Rates, command)
+			continue
+		}
+		limits.Rates[command] = rate
+	}
+	for _, s := range sizes {
+		command, maxSize, err := parseMessageSize(s)
+		if err != nil {
+			return nil, err
+		}
+		if maxSize == 0 {
+			delete(limits.MaxSizes, command)
+			continue
+		}
+		limits.MaxSizes[command] = maxSize
+	}
+	return limits, nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/wire"
+)
+
+// TestPeerMessageLimits ensures the configured peer message limits are parsed
+// and combined with the defaults as intended.
+func TestPeerMessageLimits(t *testing.T) {
+	tests := []struct {
+		name      string                      // test description
+		rates     []string                    // configured rate limits
+		sizes     []string                    // configured size limits
+		wantRates map[string]wire.MessageRate // expected rate limits
+		wantSizes map[string]uint32           // expected size limits
+		wantErr   bool                        // whether an error is expected
+	}{{
+		name:      "defaults",
+		wantRates: defaultPeerMessageRates,
+		wantSizes: map[string]uint32{},
+	}, {
+		name:  "additional limits",
+		rates: []string{"headers:100/1m"},
+		sizes: []string{"headers:100000", "inv:5000"},
+		wantRates: map[string]wire.MessageRate{
+			wire.CmdAddr:    {Messages: 10, Interval: time.Minute},
+			wire.CmdHeaders: {Messages: 100, Interval: time.Minute},
+		},
+		wantSizes: map[string]uint32{
+			wire.CmdHeaders: 100000,
+			wire.CmdInv:     5000,
+		},
+	}, {
+		name:  "override default rate",
+		rates: []string{"addr:5/30s"},
+		wantRates: map[string]wire.MessageRate{
+			wire.CmdAddr: {Messages: 5, Interval: 30 * time.Second},
+		},
+		wantSizes: map[string]uint32{},
+	}, {
+		name:      "remove default rate",
+		rates:     []string{"addr:0/1m"},
+		wantRates: map[string]wire.MessageRate{},
+		wantSizes: map[string]uint32{},
+	}, {
+		name:      "zero size is unlimited",
+		sizes:     []string{"headers:100000", "headers:0"},
+		wantRates: defaultPeerMessageRates,
+		wantSizes: map[string]uint32{},
+	}, {
+		name:    "rate without command",
+		rates:   []string{"10/1m"},
+		wantErr: true,
+	}, {
+		name:    "rate with empty command",
+		rates:   []string{":10/1m"},
+		wantErr: true,
+	}, {
+		name:    "rate with command that is too long",
+		rates:   []string{"thiscommandistoolong:10/1m"},
+		wantErr: true,
+	}, {
+		name:    "rate without interval",
+		rates:   []string{"addr:10"},
+		wantErr: true,
+	}, {
+		name:    "rate with invalid messages",
+		rates:   []string{"addr:-1/1m"},
+		wantErr: true,
+	}, {
+		name:    "rate with invalid interval",
+		rates:   []string{"addr:10/1x"},
+		wantErr: true,
+	}, {
+		name:    "rate with zero interval",
+		rates:   []string{"addr:10/0s"},
+		wantErr: true,
+	}, {
+		name:    "size with invalid bytes",
+		sizes:   []string{"headers:big"},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		limits, err := newPeerMessageLimits(test.rates, test.sizes)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !reflect.DeepEqual(limits.Rates, test.wantRates) {
+			t.Errorf("%q: unexpected rates -- got %v, want %v", test.name,
+				limits.Rates, test.wantRates)
+		}
+		if !reflect.DeepEqual(limits.MaxSizes, test.wantSizes) {
+			t.Errorf("%q: unexpected sizes -- got %v, want %v", test.name,
+				limits.MaxSizes, test.wantSizes)
+		}
+	}
+
+	// Ensure the defaults are not modified.
+	want := wire.MessageRate{Messages: 10, Interval: time.Minute}
+	if len(defaultPeerMessageRates) != 1 ||
+		defaultPeerMessageRates[wire.CmdAddr] != want {
+
+		t.Fatalf("default rates were modified: %v", defaultPeerMessageRates)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	runtimepprof "runtime/pprof"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultProfileListen is the address the profiling server listens on
+	// when it is started via RPC without an explicit address.
+	defaultProfileListen = "127.0.0.1:6061"
+
+	// defaultProfileDirname is the name of the directory within the home
+	// directory that profiles written via RPC are stored in.
+	defaultProfileDirname = "profiles"
+
+	// cpuProfileName is the name used to request a CPU profile.  It is not
+	// one of the runtime profiles since it is captured over a period of
+	// time.
+	cpuProfileName = "cpu"
+
+	// maxCPUProfileSeconds is the maximum number of seconds a CPU profile
+	// may be captured for via RPC.
+	maxCPUProfileSeconds = 600
+
+	// profileServerShutdownTimeout is the maximum amount of time to wait
+	// for in-flight requests to the profiling server to complete when it
+	// is stopped.
+	profileServerShutdownTimeout = 5 * time.Second
+)
+
+var (
+	// errProfileServerRunning is returned when attempting to start the
+	// profiling server while it is already running.
+	errProfileServerRunning = errors.New("profiling server is already running")
+
+	// errProfileServerNotRunning is returned when attempting to stop the
+	// profiling server while it is not running.
+	errProfileServerNotRunning = errors.New("profiling server is not running")
+)
+
+// normalizeProfileAddr returns the provided profiling server listen address
+// with a default host of 127.0.0.1 added when it is only a port.  An error is
+// returned if the address is invalid or the port is not between 1024 and 65535.
+func normalizeProfileAddr(addr string) (string, error) {
+	if _, err := strconv.Atoi(addr); err == nil {
+		addr = net.JoinHostPort("127.0.0.1", addr)
+	}
+
+	_, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if port, _ := strconv.Atoi(portStr); port < 1024 || port > 65535 {
+		return "", fmt.Errorf("address %s: port must be between 1024 and "+
+			"65535", addr)
+	}
+	return addr, nil
+}
+
+// isRuntimeProfile returns whether or not the provided name is one of the
+// profiles provided by the runtime such as heap or goroutine.
+func isRuntimeProfile(name string) bool {
+	return runtimepprof.Lookup(name) != nil
+}
+
+// profiler provides runtime control over the profiling facilities of the
+// process.  It allows the pprof HTTP server to be started and stopped and
+// profiles to be captured to files without restarting the process.
+type profiler struct {
+	// dir is the directory that profiles requested by file name are
+	// written to.
+	dir string
+
+	mtx        sync.Mutex
+	server     *http.Server
+	serverAddr string
+}
+
+// newProfiler returns a new profiler that writes profiles requested by file
+// name to the provided directory.
+func newProfiler(dir string) *profiler {
+	return &profiler{dir: dir}
+}
+
+// StartServer starts the pprof HTTP server on the provided listen address.  The
+// address may be a port only, in which case the server only listens on the
+// loopback interface.
+//
+// This function is safe for concurrent access.
+func (p *profiler) StartServer(listenAddr string) error {
+	addr, err := normalizeProfileAddr(listenAddr)
+	if err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.server != nil {
+		return errProfileServerRunning
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/", http.RedirectHandler("/debug/pprof", http.StatusSeeOther))
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	server := &http.Server{Handler: mux}
+	go func() {
+		err := server.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			dcrdLog.Errorf("Profiling server failed: %v", err)
+		}
+	}()
+	p.server = server
+	p.serverAddr = listener.Addr().String()
+	dcrdLog.Infof("Profiling server listening on %s", p.serverAddr)
+	return nil
+}
+
+// StopServer stops the pprof HTTP server.
+//
+// This function is safe for concurrent access.
+func (p *profiler) StopServer() error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if p.server == nil {
+		return errProfileServerNotRunning
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(),
+		profileServerShutdownTimeout)
+	defer cancel()
+	err := p.server.Shutdown(ctx)
+	if err != nil {
+		_ = p.server.Close()
+	}
+	dcrdLog.Infof("Profiling server on %s stopped", p.serverAddr)
+	p.server = nil
+	p.serverAddr = ""
+	return nil
+}
+
+// ServerAddr returns the address the pprof HTTP server is listening on or an
+// empty string when it is not running.
+//
+// This function is safe for concurrent access.
+func (p *profiler) ServerAddr() string {
+	p.mtx.Lock()
+	addr := p.serverAddr
+	p.mtx.Unlock()
+	return addr
+}
+
+// ProfilePath returns the path within the profile directory for the provided
+// file name.  An error is returned when the name is not a plain file name so
+// profiles can't be written outside of the directory.
+func (p *profiler) ProfilePath(fileName string) (string, error) {
+	if fileName == "" || fileName == "." || fileName == ".." ||
+		filepath.Base(fileName) != fileName {
+
+		return "", fmt.Errorf("invalid profile file name %q", fileName)
+	}
+	return filepath.Join(p.dir, fileName), nil
+}
+
+// createProfileFile creates the file at the provided path, along with the
+// directory that contains it when needed, and returns it.
+func createProfileFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+// WriteCPUProfile captures a CPU profile for the provided duration and writes
+// it to the file at the provided path.  The capture is stopped early and the
+// partial profile is written when the provided context is canceled.  An error
+// is returned if a CPU profile is already being captured.
+//
+// This function is safe for concurrent access.
+func (p *profiler) WriteCPUProfile(ctx context.Context, path string, duration time.Duration) error {
+	f, err := createProfileFile(path)
+	if err != nil {
+		return err
+	}
+	if err := runtimepprof.StartCPUProfile(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	dcrdLog.Infof("Capturing CPU profile to %s for %v", path, duration)
+	select {
+	case <-time.After(duration):
+	case <-ctx.Done():
+	}
+	runtimepprof.StopCPUProfile()
+	return f.Close()
+}
+
+// WriteProfile writes the named runtime profile, such as heap or goroutine, to
+// the file at the provided path.  See the runtime/pprof package for the
+// available profiles.
+//
+// This function is safe for concurrent access.
+func (p *profiler) WriteProfile(name, path string) error {
+	profile := runtimepprof.Lookup(name)
+	if profile == nil {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	f, err := createProfileFile(path)
+	if err != nil {
+		return err
+	}
+	if err := profile.WriteTo(f, 0); err != nil {
+		_ = f.Close()
+		return err
+	}
+	dcrdLog.Infof("Wrote %s profile to %s", name, path)
+	return f.Close()
+}
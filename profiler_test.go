@@ -0,0 +1,134 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestNormalizeProfileAddr ensures profiling server listen addresses are
+// normalized and validated as expected.
+func TestNormalizeProfileAddr(t *testing.T) {
+	tests := []struct {
+		name    string
+		addr    string
+		want    string
+		wantErr bool
+	}{{
+		name: "port only",
+		addr: "6061",
+		want: "127.0.0.1:6061",
+	}, {
+		name: "all interfaces",
+		addr: ":6061",
+		want: ":6061",
+	}, {
+		name: "ipv6 host and port",
+		addr: "[::1]:6061",
+		want: "[::1]:6061",
+	}, {
+		name:    "port too low",
+		addr:    "1023",
+		wantErr: true,
+	}, {
+		name:    "port too high",
+		addr:    "127.0.0.1:65536",
+		wantErr: true,
+	}, {
+		name:    "missing port",
+		addr:    "127.0.0.1",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		got, err := normalizeProfileAddr(test.addr)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error state - got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: unexpected address - got %q, want %q",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestProfiler ensures the profiler restricts profiles to its directory, writes
+// the requested profiles, and rejects invalid server operations.
+func TestProfiler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profilertest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	profileDir := filepath.Join(dir, defaultProfileDirname)
+	prof := newProfiler(profileDir)
+
+	// Ensure file names that would escape the profile directory are
+	// rejected.
+	for _, fileName := range []string{"", ".", "..", "../heap.pprof",
+		filepath.Join("sub", "heap.pprof")} {
+
+		if _, err := prof.ProfilePath(fileName); err == nil {
+			t.Errorf("ProfilePath(%q): did not receive expected error",
+				fileName)
+		}
+	}
+
+	// Ensure runtime and CPU profiles are written to the profile directory
+	// which is created as needed.
+	heapPath, err := prof.ProfilePath("heap.pprof")
+	if err != nil {
+		t.Fatalf("ProfilePath: unexpected error: %v", err)
+	}
+	if filepath.Dir(heapPath) != profileDir {
+		t.Fatalf("ProfilePath: unexpected path %q", heapPath)
+	}
+	if err := prof.WriteProfile("heap", heapPath); err != nil {
+		t.Fatalf("WriteProfile: unexpected error: %v", err)
+	}
+	if fi, err := os.Stat(heapPath); err != nil || fi.Size() == 0 {
+		t.Fatalf("WriteProfile: profile not written: %v", err)
+	}
+	if !isRuntimeProfile("goroutine") || isRuntimeProfile(cpuProfileName) {
+		t.Fatal("isRuntimeProfile: unexpected result")
+	}
+	err = prof.WriteProfile("bogus", filepath.Join(profileDir, "bogus"))
+	if err == nil {
+		t.Fatal("WriteProfile: did not receive expected error for unknown " +
+			"profile")
+	}
+
+	// Ensure canceling the context stops a CPU profile capture early.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cpuPath := filepath.Join(profileDir, "cpu.pprof")
+	err = prof.WriteCPUProfile(ctx, cpuPath, time.Minute)
+	if err != nil {
+		t.Fatalf("WriteCPUProfile: unexpected error: %v", err)
+	}
+	if _, err := os.Stat(cpuPath); err != nil {
+		t.Fatalf("WriteCPUProfile: profile not written: %v", err)
+	}
+
+	// Ensure invalid server operations are rejected.
+	if err := prof.StartServer("80"); err == nil {
+		t.Fatal("StartServer: did not receive expected error for " +
+			"privileged port")
+	}
+	if err := prof.StopServer(); err != errProfileServerNotRunning {
+		t.Fatalf("StopServer: unexpected error - got %v, want %v", err,
+			errProfileServerNotRunning)
+	}
+	if addr := prof.ServerAddr(); addr != "" {
+		t.Fatalf("ServerAddr: unexpected address %q", addr)
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"math"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+const (
+	// requestWindowTimeout is the amount of time after which a request that
+	// has not been responded to no longer counts against the request window
+	// of a peer.  This prevents requests the peer never responds to, such as
+	// those for data it no longer has, from permanently reducing the number
+	// of requests that may be in flight.
+	requestWindowTimeout = time.Minute * 2
+
+	// requestWindowEWMAWeight is the weight given to new samples when
+	// updating the moving average of the interval between responses.
+	requestWindowEWMAWeight = 0.1
+
+	// requestWindowHeadroom is the multiplier applied to the estimated
+	// number of requests required to keep a peer busy for the duration of a
+	// round trip.  It allows the window to grow when the peer is able to
+	// respond faster than it has been asked to.
+	requestWindowHeadroom = 2
+)
+
+// requestWindow tracks the in-flight requests to a peer for a specific kind of
+// data along with estimates of the latency and throughput of the peer for those
+// requests in order to adaptively size the number of requests that may be in
+// flight at once.
+//
+// The window is sized to the number of requests the peer is able to respond to
+// during a single round trip, which is the minimum required to avoid stalling
+// between requests, with some headroom to allow the window to grow as the
+// throughput of the peer increases.  The minimum observed latency is used as
+// the round trip time since the latency of requests that are queued behind
+// other requests grows with the size of the window itself.
+//
+// The window is NOT safe for concurrent access.
+type requestWindow struct {
+	minSize int
+	maxSize int
+
+	inFlight     map[chainhash.Hash]time.Time
+	minLatency   time.Duration
+	avgInterval  time.Duration
+	lastResponse time.Time
+}
+
+// newRequestWindow returns a new request window that is limited to the provided
+// minimum and maximum number of in-flight requests.
+func newRequestWindow(minSize, maxSize int) *requestWindow {
+	return &requestWindow{
+		minSize:  minSize,
+		maxSize:  maxSize,
+		inFlight: make(map[chainhash.Hash]time.Time),
+	}
+}
+
+// requested records that a request for the provided hash was sent at the
+// provided time.
+func (w *requestWindow) requested(hash *chainhash.Hash, now time.Time) {
+	w.inFlight[*hash] = now
+}
+
+// received records that a response for the provided hash was received at the
+// provided time and updates the latency and throughput estimates accordingly.
+// Responses for hashes that were not requested are ignored.
+func (w *requestWindow) received(hash *chainhash.Hash, now time.Time) {
+	sent, ok := w.inFlight[*hash]
+	if !ok {
+		return
+	}
+	delete(w.inFlight, *hash)
+
+	latency := now.Sub(sent)
+	if w.minLatency == 0 || latency < w.minLatency {
+		w.minLatency = latency
+	}
+
+	// Only consider the interval between responses when the peer was busy
+	// responding to requests for the entire interval, since otherwise it
+	// reflects the rate the requests were made rather than the rate the
+	// peer is able to respond to them.
+	if !w.lastResponse.IsZero() && w.lastResponse.After(sent) {
+		interval := now.Sub(w.lastResponse)
+		if w.avgInterval == 0 {
+			w.avgInterval = interval
+		} else {
+			w.avgInterval = time.Duration(float64(w.avgInterval)*
+				(1-requestWindowEWMAWeight) +
+				float64(interval)*requestWindowEWMAWeight)
+		}
+	}
+	w.lastResponse = now
+}
+
+// expire removes all in-flight requests that were sent longer ago than the
+// request window timeout as of the provided time.
+func (w *requestWindow) expire(now time.Time) {
+	for hash, sent := range w.inFlight {
+		if now.Sub(sent) > requestWindowTimeout {
+			delete(w.inFlight, hash)
+		}
+	}
+}
+
+// size returns the current maximum number of requests that may be in flight
+// based on the estimated latency and throughput of the peer.
+func (w *requestWindow) size() int {
+	// Use the minimum size until there are enough responses to estimate
+	// the throughput.
+	if w.avgInterval == 0 {
+		return w.minSize
+	}
+
+	// Prevent division by zero when responses arrive faster than the
+	// resolution of the clock.
+	interval := w.avgInterval
+	if interval < time.Microsecond {
+		interval = time.Microsecond
+	}
+	perRoundTrip := float64(w.minLatency) / float64(interval)
+	size := math.Ceil(perRoundTrip * requestWindowHeadroom)
+	if size < float64(w.minSize) {
+		return w.minSize
+	}
+	if size > float64(w.maxSize) {
+		return w.maxSize
+	}
+	return int(size)
+}
+
+// available returns the number of additional requests that may be sent as of
+// the provided time without exceeding the size of the window.
+func (w *requestWindow) available(now time.Time) int {
+	w.expire(now)
+	available := w.size() - len(w.inFlight)
+	if available < 0 {
+		return 0
+	}
+	return available
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+)
+
+// TestRequestWindow ensures the request window adapts its size to the measured
+// latency and throughput of a peer and limits the number of in-flight requests
+// accordingly.
+func TestRequestWindow(t *testing.T) {
+	const minSize, maxSize = 4, 100
+	w := newRequestWindow(minSize, maxSize)
+	now := time.Unix(1577836800, 0)
+
+	// Ensure the minimum size is used prior to any responses.
+	if got := w.available(now); got != minSize {
+		t.Fatalf("unexpected initial available requests -- got %d, want %d",
+			got, minSize)
+	}
+
+	// Simulate a peer with a round trip latency of 100ms that is able to
+	// respond to a request every 10ms while all requests it is able to
+	// respond to are in flight.  The window should grow to twice the number
+	// of requests the peer is able to respond to in a single round trip.
+	var next uint32
+	sent := make(map[chainhash.Hash]time.Time)
+	var queue []chainhash.Hash
+	lastResponse := now
+	for i := 0; i < 1000; i++ {
+		for n := w.available(now); n > 0; n-- {
+			var hash chainhash.Hash
+			next++
+			hash[0], hash[1], hash[2] = byte(next), byte(next>>8), byte(next>>16)
+			w.requested(&hash, now)
+			sent[hash] = now
+			queue = append(queue, hash)
+		}
+
+		// Respond to the oldest request once it has been in flight for
+		// the round trip latency and the peer has had time to respond.
+		hash := queue[0]
+		queue = queue[1:]
+		respondTime := sent[hash].Add(100 * time.Millisecond)
+		if minNext := lastResponse.Add(10 * time.Millisecond); respondTime.Before(minNext) {
+			respondTime = minNext
+		}
+		now = respondTime
+		lastResponse = now
+		w.received(&hash, now)
+	}
+	if got := w.size(); got < 18 || got > 22 {
+		t.Fatalf("unexpected adapted window size -- got %d, want ~20", got)
+	}
+
+	// Ensure the window is limited to the maximum size for very fast peers.
+	w.avgInterval = time.Nanosecond
+	if got := w.size(); got != maxSize {
+		t.Fatalf("unexpected window size for fast peer -- got %d, want %d",
+			got, maxSize)
+	}
+
+	// Ensure requests that are never responded to eventually stop counting
+	// against the window.
+	w.avgInterval = 0
+	w.inFlight = make(map[chainhash.Hash]time.Time)
+	for i := 0; i < minSize; i++ {
+		w.requested(&chainhash.Hash{byte(i)}, now)
+	}
+	if got := w.available(now); got != 0 {
+		t.Fatalf("unexpected available requests for full window -- got %d, "+
+			"want 0", got)
+	}
+	if got := w.available(now.Add(requestWindowTimeout + 1)); got != minSize {
+		t.Fatalf("unexpected available requests after timeout -- got %d, "+
+			"want %d", got, minSize)
+	}
+}
@@ -0,0 +1,468 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package dcrdrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion7
+
+// ChainServiceClient is the client API for ChainService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChainServiceClient interface {
+	// BestBlock returns the hash and height of the tip of the main chain.
+	BestBlock(ctx context.Context, in *BestBlockRequest, opts ...grpc.CallOption) (*BestBlockResponse, error)
+	// BlockHash returns the hash of the main chain block at a height.
+	BlockHash(ctx context.Context, in *BlockHashRequest, opts ...grpc.CallOption) (*BlockHashResponse, error)
+	// Block returns a block by its hash.
+	Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	// BlockHeader returns a block header by its block hash.
+	BlockHeader(ctx context.Context, in *BlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeaderResponse, error)
+	// Transaction returns a transaction from the mempool or, when the
+	// transaction index is enabled, the main chain.
+	Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// MempoolTransactions returns the hashes of all transactions in the
+	// mempool.
+	MempoolTransactions(ctx context.Context, in *MempoolTransactionsRequest, opts ...grpc.CallOption) (*MempoolTransactionsResponse, error)
+	// SendTransaction submits a transaction to the mempool and relays it to
+	// the network.
+	SendTransaction(ctx context.Context, in *SendTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error)
+	// BlockNotifications streams a notification whenever a block is
+	// connected to or disconnected from the main chain.
+	BlockNotifications(ctx context.Context, in *BlockNotificationsRequest, opts ...grpc.CallOption) (ChainService_BlockNotificationsClient, error)
+	// MempoolNotifications streams a notification whenever a new transaction
+	// is accepted into the mempool.
+	MempoolNotifications(ctx context.Context, in *MempoolNotificationsRequest, opts ...grpc.CallOption) (ChainService_MempoolNotificationsClient, error)
+}
+
+type chainServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChainServiceClient(cc grpc.ClientConnInterface) ChainServiceClient {
+	return &chainServiceClient{cc}
+}
+
+func (c *chainServiceClient) BestBlock(ctx context.Context, in *BestBlockRequest, opts ...grpc.CallOption) (*BestBlockResponse, error) {
+	out := new(BestBlockResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/BestBlock", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) BlockHash(ctx context.Context, in *BlockHashRequest, opts ...grpc.CallOption) (*BlockHashResponse, error) {
+	out := new(BlockHashResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/BlockHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) Block(ctx context.Context, in *BlockRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/Block", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) BlockHeader(ctx context.Context, in *BlockHeaderRequest, opts ...grpc.CallOption) (*BlockHeaderResponse, error) {
+	out := new(BlockHeaderResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/BlockHeader", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) Transaction(ctx context.Context, in *TransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/Transaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) MempoolTransactions(ctx context.Context, in *MempoolTransactionsRequest, opts ...grpc.CallOption) (*MempoolTransactionsResponse, error) {
+	out := new(MempoolTransactionsResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/MempoolTransactions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) SendTransaction(ctx context.Context, in *SendTransactionRequest, opts ...grpc.CallOption) (*SendTransactionResponse, error) {
+	out := new(SendTransactionResponse)
+	err := c.cc.Invoke(ctx, "/dcrdrpc.ChainService/SendTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chainServiceClient) BlockNotifications(ctx context.Context, in *BlockNotificationsRequest, opts ...grpc.CallOption) (ChainService_BlockNotificationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ChainService_serviceDesc.Streams[0], "/dcrdrpc.ChainService/BlockNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chainServiceBlockNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ChainService_BlockNotificationsClient interface {
+	Recv() (*BlockNotification, error)
+	grpc.ClientStream
+}
+
+type chainServiceBlockNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *chainServiceBlockNotificationsClient) Recv() (*BlockNotification, error) {
+	m := new(BlockNotification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *chainServiceClient) MempoolNotifications(ctx context.Context, in *MempoolNotificationsRequest, opts ...grpc.CallOption) (ChainService_MempoolNotificationsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ChainService_serviceDesc.Streams[1], "/dcrdrpc.ChainService/MempoolNotifications", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chainServiceMempoolNotificationsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ChainService_MempoolNotificationsClient interface {
+	Recv() (*MempoolNotification, error)
+	grpc.ClientStream
+}
+
+type chainServiceMempoolNotificationsClient struct {
+	grpc.ClientStream
+}
+
+func (x *chainServiceMempoolNotificationsClient) Recv() (*MempoolNotification, error) {
+	m := new(MempoolNotification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ChainServiceServer is the server API for ChainService service.
+// All implementations must embed UnimplementedChainServiceServer
+// for forward compatibility
+type ChainServiceServer interface {
+	// BestBlock returns the hash and height of the tip of the main chain.
+	BestBlock(context.Context, *BestBlockRequest) (*BestBlockResponse, error)
+	// BlockHash returns the hash of the main chain block at a height.
+	BlockHash(context.Context, *BlockHashRequest) (*BlockHashResponse, error)
+	// Block returns a block by its hash.
+	Block(context.Context, *BlockRequest) (*BlockResponse, error)
+	// BlockHeader returns a block header by its block hash.
+	BlockHeader(context.Context, *BlockHeaderRequest) (*BlockHeaderResponse, error)
+	// Transaction returns a transaction from the mempool or, when the
+	// transaction index is enabled, the main chain.
+	Transaction(context.Context, *TransactionRequest) (*TransactionResponse, error)
+	// MempoolTransactions returns the hashes of all transactions in the
+	// mempool.
+	MempoolTransactions(context.Context, *MempoolTransactionsRequest) (*MempoolTransactionsResponse, error)
+	// SendTransaction submits a transaction to the mempool and relays it to
+	// the network.
+	SendTransaction(context.Context, *SendTransactionRequest) (*SendTransactionResponse, error)
+	// BlockNotifications streams a notification whenever a block is
+	// connected to or disconnected from the main chain.
+	BlockNotifications(*BlockNotificationsRequest, ChainService_BlockNotificationsServer) error
+	// MempoolNotifications streams a notification whenever a new transaction
+	// is accepted into the mempool.
+	MempoolNotifications(*MempoolNotificationsRequest, ChainService_MempoolNotificationsServer) error
+	mustEmbedUnimplementedChainServiceServer()
+}
+
+// UnimplementedChainServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedChainServiceServer struct {
+}
+
+func (UnimplementedChainServiceServer) BestBlock(context.Context, *BestBlockRequest) (*BestBlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BestBlock not implemented")
+}
+func (UnimplementedChainServiceServer) BlockHash(context.Context, *BlockHashRequest) (*BlockHashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockHash not implemented")
+}
+func (UnimplementedChainServiceServer) Block(context.Context, *BlockRequest) (*BlockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Block not implemented")
+}
+func (UnimplementedChainServiceServer) BlockHeader(context.Context, *BlockHeaderRequest) (*BlockHeaderResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BlockHeader not implemented")
+}
+func (UnimplementedChainServiceServer) Transaction(context.Context, *TransactionRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Transaction not implemented")
+}
+func (UnimplementedChainServiceServer) MempoolTransactions(context.Context, *MempoolTransactionsRequest) (*MempoolTransactionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MempoolTransactions not implemented")
+}
+func (UnimplementedChainServiceServer) SendTransaction(context.Context, *SendTransactionRequest) (*SendTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendTransaction not implemented")
+}
+func (UnimplementedChainServiceServer) BlockNotifications(*BlockNotificationsRequest, ChainService_BlockNotificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method BlockNotifications not implemented")
+}
+func (UnimplementedChainServiceServer) MempoolNotifications(*MempoolNotificationsRequest, ChainService_MempoolNotificationsServer) error {
+	return status.Errorf(codes.Unimplemented, "method MempoolNotifications not implemented")
+}
+func (UnimplementedChainServiceServer) mustEmbedUnimplementedChainServiceServer() {}
+
+// UnsafeChainServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChainServiceServer will
+// result in compilation errors.
+type UnsafeChainServiceServer interface {
+	mustEmbedUnimplementedChainServiceServer()
+}
+
+func RegisterChainServiceServer(s grpc.ServiceRegistrar, srv ChainServiceServer) {
+	s.RegisterService(&_ChainService_serviceDesc, srv)
+}
+
+func _ChainService_BestBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BestBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).BestBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/BestBlock",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).BestBlock(ctx, req.(*BestBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_BlockHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).BlockHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/BlockHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).BlockHash(ctx, req.(*BlockHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_Block_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).Block(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/Block",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).Block(ctx, req.(*BlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_BlockHeader_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockHeaderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).BlockHeader(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/BlockHeader",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).BlockHeader(ctx, req.(*BlockHeaderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_Transaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).Transaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/Transaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).Transaction(ctx, req.(*TransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_MempoolTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MempoolTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).MempoolTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/MempoolTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).MempoolTransactions(ctx, req.(*MempoolTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_SendTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).SendTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dcrdrpc.ChainService/SendTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).SendTransaction(ctx, req.(*SendTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChainService_BlockNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BlockNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChainServiceServer).BlockNotifications(m, &chainServiceBlockNotificationsServer{stream})
+}
+
+type ChainService_BlockNotificationsServer interface {
+	Send(*BlockNotification) error
+	grpc.ServerStream
+}
+
+type chainServiceBlockNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *chainServiceBlockNotificationsServer) Send(m *BlockNotification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ChainService_MempoolNotifications_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(MempoolNotificationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChainServiceServer).MempoolNotifications(m, &chainServiceMempoolNotificationsServer{stream})
+}
+
+type ChainService_MempoolNotificationsServer interface {
+	Send(*MempoolNotification) error
+	grpc.ServerStream
+}
+
+type chainServiceMempoolNotificationsServer struct {
+	grpc.ServerStream
+}
+
+func (x *chainServiceMempoolNotificationsServer) Send(m *MempoolNotification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _ChainService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dcrdrpc.ChainService",
+	HandlerType: (*ChainServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BestBlock",
+			Handler:    _ChainService_BestBlock_Handler,
+		},
+		{
+			MethodName: "BlockHash",
+			Handler:    _ChainService_BlockHash_Handler,
+		},
+		{
+			MethodName: "Block",
+			Handler:    _ChainService_Block_Handler,
+		},
+		{
+			MethodName: "BlockHeader",
+			Handler:    _ChainService_BlockHeader_Handler,
+		},
+		{
+			MethodName: "Transaction",
+			Handler:    _ChainService_Transaction_Handler,
+		},
+		{
+			MethodName: "MempoolTransactions",
+			Handler:    _ChainService_MempoolTransactions_Handler,
+		},
+		{
+			MethodName: "SendTransaction",
+			Handler:    _ChainService_SendTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "BlockNotifications",
+			Handler:       _ChainService_BlockNotifications_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MempoolNotifications",
+			Handler:       _ChainService_MempoolNotifications_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "api.proto",
+}
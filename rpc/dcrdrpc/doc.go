@@ -0,0 +1,13 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+/*
+Package dcrdrpc provides the protocol buffer definitions and generated gRPC
+client and server code for the dcrd gRPC interface.
+
+The service definitions are in api.proto, which may be used to generate clients
+in any language supported by gRPC.  Run regen.sh after modifying api.proto to
+regenerate the Go code.
+*/
+package dcrdrpc
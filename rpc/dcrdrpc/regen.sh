@@ -0,0 +1,7 @@
+#!/bin/sh
+
+# Regenerates the Go code from api.proto.  Requires protoc along with the
+# protoc-gen-go v1.25.0 and protoc-gen-go-grpc v1.0.1 plugins.
+protoc -I. api.proto \
+	--go_out=. --go_opt=paths=source_relative \
+	--go-grpc_out=. --go-grpc_opt=paths=source_relative
@@ -45,6 +45,18 @@ const (
 	NDisconnect NodeSubCmd = "disconnect"
 )
 
+// ProfileServerSubCmd defines the type used in the profileserver JSON-RPC
+// command for the sub command field.
+type ProfileServerSubCmd string
+
+const (
+	// PSStart indicates the profiling server should be started.
+	PSStart ProfileServerSubCmd = "start"
+
+	// PSStop indicates the profiling server should be stopped.
+	PSStop ProfileServerSubCmd = "stop"
+)
+
 // AddNodeCmd defines the addnode JSON-RPC command.
 type AddNodeCmd struct {
 	Addr   string
@@ -60,6 +72,19 @@ func NewAddNodeCmd(addr string, subCmd AddNodeSubCmd) *AddNodeCmd {
 	}
 }
 
+// AdvanceTimeCmd defines the advancetime JSON-RPC command.
+type AdvanceTimeCmd struct {
+	Seconds int64
+}
+
+// NewAdvanceTimeCmd returns a new instance which can be used to issue an
+// advancetime JSON-RPC command.
+func NewAdvanceTimeCmd(seconds int64) *AdvanceTimeCmd {
+	return &AdvanceTimeCmd{
+		Seconds: seconds,
+	}
+}
+
 // SStxInput represents the inputs to an SStx transaction. Specifically a
 // transactionsha and output number pair, along with the output amounts.
 type SStxInput struct {
@@ -78,6 +103,15 @@ type SStxCommitOut struct {
 	ChangeAmt  int64  `json:"changeamt"`
 }
 
+// CompactDatabaseCmd defines the compactdatabase JSON-RPC command.
+type CompactDatabaseCmd struct{}
+
+// NewCompactDatabaseCmd returns a new instance which can be used to issue a
+// compactdatabase JSON-RPC command.
+func NewCompactDatabaseCmd() *CompactDatabaseCmd {
+	return &CompactDatabaseCmd{}
+}
+
 // CreateRawSStxCmd is a type handling custom marshaling and
 // unmarshaling of createrawsstx JSON RPC commands.
 type CreateRawSStxCmd struct {
@@ -185,6 +219,19 @@ func NewDecodeScriptCmd(hexScript string) *DecodeScriptCmd {
 	}
 }
 
+// DeleteAddressCmd defines the deleteaddress JSON-RPC command.
+type DeleteAddressCmd struct {
+	Addr string
+}
+
+// NewDeleteAddressCmd returns a new instance which can be used to issue a
+// deleteaddress JSON-RPC command.
+func NewDeleteAddressCmd(addr string) *DeleteAddressCmd {
+	return &DeleteAddressCmd{
+		Addr: addr,
+	}
+}
+
 // EstimateFeeCmd defines the estimatefee JSON-RPC command.
 type EstimateFeeCmd struct {
 	NumBlocks int64
@@ -330,6 +377,21 @@ func NewExistsMempoolTxsCmd(txHashes []string) *ExistsMempoolTxsCmd {
 	}
 }
 
+// ForceVoteCmd defines the forcevote JSON-RPC command.
+type ForceVoteCmd struct {
+	AgendaID string
+	ChoiceID string
+}
+
+// NewForceVoteCmd returns a new instance which can be used to issue a
+// forcevote JSON-RPC command.
+func NewForceVoteCmd(agendaID, choiceID string) *ForceVoteCmd {
+	return &ForceVoteCmd{
+		AgendaID: agendaID,
+		ChoiceID: choiceID,
+	}
+}
+
 // GenerateCmd defines the generate JSON-RPC command.
 type GenerateCmd struct {
 	NumBlocks uint32
@@ -343,6 +405,21 @@ func NewGenerateCmd(numBlocks uint32) *GenerateCmd {
 	}
 }
 
+// GenerateToAddressCmd defines the generatetoaddress JSON-RPC command.
+type GenerateToAddressCmd struct {
+	NumBlocks uint32
+	Address   string
+}
+
+// NewGenerateToAddressCmd returns a new instance which can be used to issue a
+// generatetoaddress JSON-RPC command.
+func NewGenerateToAddressCmd(numBlocks uint32, address string) *GenerateToAddressCmd {
+	return &GenerateToAddressCmd{
+		NumBlocks: numBlocks,
+		Address:   address,
+	}
+}
+
 // GetAddedNodeInfoCmd defines the getaddednodeinfo JSON-RPC command.
 type GetAddedNodeInfoCmd struct {
 	DNS  bool
@@ -361,6 +438,44 @@ func NewGetAddedNodeInfoCmd(dns bool, node *string) *GetAddedNodeInfoCmd {
 	}
 }
 
+// GetAddressUTXOsCmd defines the getaddressutxos JSON-RPC command.
+type GetAddressUTXOsCmd struct {
+	Address        string
+	Skip           *int  `jsonrpcdefault:"0"`
+	Count          *int  `jsonrpcdefault:"100"`
+	IncludeMempool *bool `jsonrpcdefault:"true"`
+}
+
+// NewGetAddressUTXOsCmd returns a new instance which can be used to issue a
+// getaddressutxos JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetAddressUTXOsCmd(address string, skip, count *int, includeMempool *bool) *GetAddressUTXOsCmd {
+	return &GetAddressUTXOsCmd{
+		Address:        address,
+		Skip:           skip,
+		Count:          count,
+		IncludeMempool: includeMempool,
+	}
+}
+
+// GetAddrManInfoCmd defines the getaddrmaninfo JSON-RPC command.
+type GetAddrManInfoCmd struct {
+	Verbose *bool `jsonrpcdefault:"false"`
+}
+
+// NewGetAddrManInfoCmd returns a new instance which can be used to issue a
+// getaddrmaninfo JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetAddrManInfoCmd(verbose *bool) *GetAddrManInfoCmd {
+	return &GetAddrManInfoCmd{
+		Verbose: verbose,
+	}
+}
+
 // GetBestBlockCmd defines the getbestblock JSON-RPC command.
 type GetBestBlockCmd struct{}
 
@@ -384,6 +499,7 @@ type GetBlockCmd struct {
 	Hash      string
 	Verbose   *bool `jsonrpcdefault:"true"`
 	VerboseTx *bool `jsonrpcdefault:"false"`
+	PrevOut   *bool `jsonrpcdefault:"false"`
 }
 
 // NewGetBlockCmd returns a new instance which can be used to issue a getblock
@@ -399,6 +515,34 @@ func NewGetBlockCmd(hash string, verbose, verboseTx *bool) *GetBlockCmd {
 	}
 }
 
+// NewGetBlockWithPrevOutCmd returns a new instance which can be used to issue a
+// getblock JSON-RPC command that optionally includes the previous outputs
+// spent by the inputs of the transactions.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlockWithPrevOutCmd(hash string, verbose, verboseTx, prevOut *bool) *GetBlockCmd {
+	return &GetBlockCmd{
+		Hash:      hash,
+		Verbose:   verbose,
+		VerboseTx: verboseTx,
+		PrevOut:   prevOut,
+	}
+}
+
+// GetBlockByTimeCmd defines the getblockbytime JSON-RPC command.
+type GetBlockByTimeCmd struct {
+	Timestamp int64
+}
+
+// NewGetBlockByTimeCmd returns a new instance which can be used to issue a
+// getblockbytime JSON-RPC command.
+func NewGetBlockByTimeCmd(timestamp int64) *GetBlockByTimeCmd {
+	return &GetBlockByTimeCmd{
+		Timestamp: timestamp,
+	}
+}
+
 // GetBlockChainInfoCmd defines the getblockchaininfo JSON-RPC command.
 type GetBlockChainInfoCmd struct{}
 
@@ -445,6 +589,26 @@ func NewGetBlockHeaderCmd(hash string, verbose *bool) *GetBlockHeaderCmd {
 	}
 }
 
+// GetBlocksByTimeCmd defines the getblocksbytime JSON-RPC command.
+type GetBlocksByTimeCmd struct {
+	Start int64
+	End   int64
+	Count *int `jsonrpcdefault:"100"`
+}
+
+// NewGetBlocksByTimeCmd returns a new instance which can be used to issue a
+// getblocksbytime JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlocksByTimeCmd(start, end int64, count *int) *GetBlocksByTimeCmd {
+	return &GetBlocksByTimeCmd{
+		Start: start,
+		End:   end,
+		Count: count,
+	}
+}
+
 // GetBlockSubsidyCmd defines the getblocksubsidy JSON-RPC command.
 type GetBlockSubsidyCmd struct {
 	Height int64
@@ -460,6 +624,45 @@ func NewGetBlockSubsidyCmd(height int64, voters uint16) *GetBlockSubsidyCmd {
 	}
 }
 
+// TemplateRequest is a request object as defined in BIP22
+// (https://en.bitcoin.it/wiki/BIP_0022) and BIP23
+// (https://en.bitcoin.it/wiki/BIP_0023).  Only the proposal mode, which is
+// used to submit a candidate block for validation without broadcasting it, is
+// supported.  It is optionally provided as a pointer argument to
+// GetBlockTemplateCmd.
+type TemplateRequest struct {
+	Mode string `json:"mode,omitempty"`
+
+	// Data is the hex-encoded serialized block that is being proposed when
+	// the mode is proposal.
+	Data string `json:"data,omitempty"`
+}
+
+// GetBlockTemplateCmd defines the getblocktemplate JSON-RPC command.
+type GetBlockTemplateCmd struct {
+	Request *TemplateRequest
+}
+
+// NewGetBlockTemplateCmd returns a new instance which can be used to issue a
+// getblocktemplate JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetBlockTemplateCmd(request *TemplateRequest) *GetBlockTemplateCmd {
+	return &GetBlockTemplateCmd{
+		Request: request,
+	}
+}
+
+// GetBuildInfoCmd defines the getbuildinfo JSON-RPC command.
+type GetBuildInfoCmd struct{}
+
+// NewGetBuildInfoCmd returns a new instance which can be used to issue a
+// getbuildinfo JSON-RPC command.
+func NewGetBuildInfoCmd() *GetBuildInfoCmd {
+	return &GetBuildInfoCmd{}
+}
+
 // GetCFilterCmd defines the getcfilter JSON-RPC command.
 type GetCFilterCmd struct {
 	Hash       string
@@ -503,6 +706,42 @@ func NewGetCFilterV2Cmd(hash string) *GetCFilterV2Cmd {
 	}
 }
 
+// GetCFilterV2HeadersCmd defines the getcfilterv2headers JSON-RPC command.
+type GetCFilterV2HeadersCmd struct {
+	StartHeight int64
+	Count       *int `jsonrpcdefault:"2000"`
+}
+
+// NewGetCFilterV2HeadersCmd returns a new instance which can be used to issue a
+// getcfilterv2headers JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetCFilterV2HeadersCmd(startHeight int64, count *int) *GetCFilterV2HeadersCmd {
+	return &GetCFilterV2HeadersCmd{
+		StartHeight: startHeight,
+		Count:       count,
+	}
+}
+
+// GetCFiltersV2Cmd defines the getcfiltersv2 JSON-RPC command.
+type GetCFiltersV2Cmd struct {
+	StartHeight int64
+	Count       *int `jsonrpcdefault:"100"`
+}
+
+// NewGetCFiltersV2Cmd returns a new instance which can be used to issue a
+// getcfiltersv2 JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetCFiltersV2Cmd(startHeight int64, count *int) *GetCFiltersV2Cmd {
+	return &GetCFiltersV2Cmd{
+		StartHeight: startHeight,
+		Count:       count,
+	}
+}
+
 // GetChainTipsCmd defines the getchaintips JSON-RPC command.
 type GetChainTipsCmd struct{}
 
@@ -539,6 +778,15 @@ func NewGetCurrentNetCmd() *GetCurrentNetCmd {
 	return &GetCurrentNetCmd{}
 }
 
+// GetDatabaseInfoCmd defines the getdatabaseinfo JSON-RPC command.
+type GetDatabaseInfoCmd struct{}
+
+// NewGetDatabaseInfoCmd returns a new instance which can be used to issue a
+// getdatabaseinfo JSON-RPC command.
+func NewGetDatabaseInfoCmd() *GetDatabaseInfoCmd {
+	return &GetDatabaseInfoCmd{}
+}
+
 // GetDifficultyCmd defines the getdifficulty JSON-RPC command.
 type GetDifficultyCmd struct{}
 
@@ -566,6 +814,15 @@ func NewGetHashesPerSecCmd() *GetHashesPerSecCmd {
 	return &GetHashesPerSecCmd{}
 }
 
+// GetIndexInfoCmd defines the getindexinfo JSON-RPC command.
+type GetIndexInfoCmd struct{}
+
+// NewGetIndexInfoCmd returns a new instance which can be used to issue a
+// getindexinfo JSON-RPC command.
+func NewGetIndexInfoCmd() *GetIndexInfoCmd {
+	return &GetIndexInfoCmd{}
+}
+
 // GetInfoCmd defines the getinfo JSON-RPC command.
 type GetInfoCmd struct{}
 
@@ -675,9 +932,17 @@ const (
 )
 
 // GetRawMempoolCmd defines the getmempool JSON-RPC command.
+//
+// The optional Count and Cursor fields page through the results in ascending
+// order of the transaction hashes.  When Count is set, at most that many
+// transactions are returned.  When Cursor is set, only transactions with hashes
+// that sort after it are returned, so the final hash of one page may be passed
+// as the cursor to request the next page.
 type GetRawMempoolCmd struct {
 	Verbose *bool `jsonrpcdefault:"false"`
 	TxType  *string
+	Count   *int
+	Cursor  *string
 }
 
 // NewGetRawMempoolCmd returns a new instance which can be used to issue a
@@ -692,6 +957,20 @@ func NewGetRawMempoolCmd(verbose *bool, txType *string) *GetRawMempoolCmd {
 	}
 }
 
+// NewGetRawMempoolPageCmd returns a new instance which can be used to issue a
+// getrawmempool JSON-RPC command that requests a single page of results.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewGetRawMempoolPageCmd(verbose *bool, txType *string, count *int, cursor *string) *GetRawMempoolCmd {
+	return &GetRawMempoolCmd{
+		Verbose: verbose,
+		TxType:  txType,
+		Count:   count,
+		Cursor:  cursor,
+	}
+}
+
 // GetRawTransactionCmd defines the getrawtransaction JSON-RPC command.
 //
 // NOTE: This field is an int versus a bool to remain compatible with Bitcoin
@@ -713,6 +992,45 @@ func NewGetRawTransactionCmd(txHash string, verbose *int) *GetRawTransactionCmd
 	}
 }
 
+// GetRPCLimitInfoCmd defines the getrpclimitinfo JSON-RPC command.
+type GetRPCLimitInfoCmd struct{}
+
+// NewGetRPCLimitInfoCmd returns a new instance which can be used to issue a
+// getrpclimitinfo JSON-RPC command.
+func NewGetRPCLimitInfoCmd() *GetRPCLimitInfoCmd {
+	return &GetRPCLimitInfoCmd{}
+}
+
+// GetScriptStatsCmd defines the getscriptstats JSON-RPC command.
+type GetScriptStatsCmd struct {
+	StartHeight int64
+	EndHeight   *int64
+}
+
+// NewGetScriptStatsCmd returns a new instance which can be used to issue a
+// getscriptstats JSON-RPC command.
+func NewGetScriptStatsCmd(startHeight int64, endHeight *int64) *GetScriptStatsCmd {
+	return &GetScriptStatsCmd{
+		StartHeight: startHeight,
+		EndHeight:   endHeight,
+	}
+}
+
+// GetSpentInfoCmd defines the getspentinfo JSON-RPC command.
+type GetSpentInfoCmd struct {
+	Txid string
+	Vout uint32
+}
+
+// NewGetSpentInfoCmd returns a new instance which can be used to issue a
+// getspentinfo JSON-RPC command.
+func NewGetSpentInfoCmd(txHash string, vout uint32) *GetSpentInfoCmd {
+	return &GetSpentInfoCmd{
+		Txid: txHash,
+		Vout: vout,
+	}
+}
+
 // GetStakeDifficultyCmd is a type handling custom marshaling and
 // unmarshaling of getstakedifficulty JSON RPC commands.
 type GetStakeDifficultyCmd struct{}
@@ -753,6 +1071,30 @@ func NewGetStakeVersionsCmd(hash string, count int32) *GetStakeVersionsCmd {
 	}
 }
 
+// GetSyncStatusCmd defines the getsyncstatus JSON-RPC command.
+type GetSyncStatusCmd struct{}
+
+// NewGetSyncStatusCmd returns a new instance which can be used to issue a
+// getsyncstatus JSON-RPC command.
+func NewGetSyncStatusCmd() *GetSyncStatusCmd {
+	return &GetSyncStatusCmd{}
+}
+
+// GetTicketPoolInfoCmd defines the getticketpoolinfo JSON-RPC command.
+type GetTicketPoolInfoCmd struct {
+	Buckets *uint32 `jsonrpcdefault:"10"`
+	Windows *uint32 `jsonrpcdefault:"8"`
+}
+
+// NewGetTicketPoolInfoCmd returns a new instance which can be used to issue a
+// getticketpoolinfo JSON-RPC command.
+func NewGetTicketPoolInfoCmd(buckets, windows *uint32) *GetTicketPoolInfoCmd {
+	return &GetTicketPoolInfoCmd{
+		Buckets: buckets,
+		Windows: windows,
+	}
+}
+
 // GetTicketPoolValueCmd defines the getticketpoolvalue JSON-RPC command.
 type GetTicketPoolValueCmd struct{}
 
@@ -805,6 +1147,21 @@ func NewGetVoteInfoCmd(version uint32) *GetVoteInfoCmd {
 	}
 }
 
+// GetVoteStatsCmd defines the getvotestats JSON-RPC command.  It returns vote
+// version and agenda choice statistics for the current stake version interval.
+// Optionally, Count indicates how many intervals to return.
+type GetVoteStatsCmd struct {
+	Count *int32 `jsonrpcdefault:"1"`
+}
+
+// NewGetVoteStatsCmd returns a new instance which can be used to issue a
+// getvotestats JSON-RPC command.
+func NewGetVoteStatsCmd(count *int32) *GetVoteStatsCmd {
+	return &GetVoteStatsCmd{
+		Count: count,
+	}
+}
+
 // GetWorkCmd defines the getwork JSON-RPC command.
 type GetWorkCmd struct {
 	Data *string
@@ -848,7 +1205,13 @@ func NewHelpCmd(command *string) *HelpCmd {
 
 // LiveTicketsCmd is a type handling custom marshaling and
 // unmarshaling of livetickets JSON RPC commands.
-type LiveTicketsCmd struct{}
+//
+// The optional Count and Cursor fields page through the tickets in ascending
+// order of their hashes in the same manner as GetRawMempoolCmd.
+type LiveTicketsCmd struct {
+	Count  *int
+	Cursor *string
+}
 
 // NewLiveTicketsCmd returns a new instance which can be used to issue a JSON-RPC
 // livetickets command.
@@ -856,9 +1219,27 @@ func NewLiveTicketsCmd() *LiveTicketsCmd {
 	return &LiveTicketsCmd{}
 }
 
+// NewLiveTicketsPageCmd returns a new instance which can be used to issue a
+// JSON-RPC livetickets command that requests a single page of results.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewLiveTicketsPageCmd(count *int, cursor *string) *LiveTicketsCmd {
+	return &LiveTicketsCmd{
+		Count:  count,
+		Cursor: cursor,
+	}
+}
+
 // MissedTicketsCmd is a type handling custom marshaling and
 // unmarshaling of missedtickets JSON RPC commands.
-type MissedTicketsCmd struct{}
+//
+// The optional Count and Cursor fields page through the tickets in ascending
+// order of their hashes in the same manner as GetRawMempoolCmd.
+type MissedTicketsCmd struct {
+	Count  *int
+	Cursor *string
+}
 
 // NewMissedTicketsCmd returns a new instance which can be used to issue a JSON-RPC
 // missedtickets command.
@@ -866,6 +1247,18 @@ func NewMissedTicketsCmd() *MissedTicketsCmd {
 	return &MissedTicketsCmd{}
 }
 
+// NewMissedTicketsPageCmd returns a new instance which can be used to issue a
+// JSON-RPC missedtickets command that requests a single page of results.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewMissedTicketsPageCmd(count *int, cursor *string) *MissedTicketsCmd {
+	return &MissedTicketsCmd{
+		Count:  count,
+		Cursor: cursor,
+	}
+}
+
 // NodeCmd defines the dropnode JSON-RPC command.
 type NodeCmd struct {
 	SubCmd        NodeSubCmd `jsonrpcusage:"\"connect|remove|disconnect\""`
@@ -886,6 +1279,19 @@ func NewNodeCmd(subCmd NodeSubCmd, target string, connectSubCmd *string) *NodeCm
 	}
 }
 
+// PauseGenerateCmd defines the pausegenerate JSON-RPC command.
+type PauseGenerateCmd struct {
+	Pause bool
+}
+
+// NewPauseGenerateCmd returns a new instance which can be used to issue a
+// pausegenerate JSON-RPC command.
+func NewPauseGenerateCmd(pause bool) *PauseGenerateCmd {
+	return &PauseGenerateCmd{
+		Pause: pause,
+	}
+}
+
 // PingCmd defines the ping JSON-RPC command.
 type PingCmd struct{}
 
@@ -895,6 +1301,21 @@ func NewPingCmd() *PingCmd {
 	return &PingCmd{}
 }
 
+// ProfileServerCmd defines the profileserver JSON-RPC command.
+type ProfileServerCmd struct {
+	SubCmd ProfileServerSubCmd `jsonrpcusage:"\"start|stop\""`
+	Listen *string
+}
+
+// NewProfileServerCmd returns a new instance which can be used to issue a
+// profileserver JSON-RPC command.
+func NewProfileServerCmd(subCmd ProfileServerSubCmd, listen *string) *ProfileServerCmd {
+	return &ProfileServerCmd{
+		SubCmd: subCmd,
+		Listen: listen,
+	}
+}
+
 // RebroadcastMissedCmd is a type handling custom marshaling and
 // unmarshaling of rebroadcastwinners JSON RPC commands.
 type RebroadcastMissedCmd struct{}
@@ -979,6 +1400,21 @@ func NewSetGenerateCmd(generate bool, genProcLimit *int) *SetGenerateCmd {
 	}
 }
 
+// SetPolicyCmd defines the setpolicy JSON-RPC command.
+type SetPolicyCmd struct {
+	Setting string `jsonrpcusage:"\"minrelaytxfee|maxorphantx|maxpeers|banduration\""`
+	Value   string
+}
+
+// NewSetPolicyCmd returns a new instance which can be used to issue a setpolicy
+// JSON-RPC command.
+func NewSetPolicyCmd(setting, value string) *SetPolicyCmd {
+	return &SetPolicyCmd{
+		Setting: setting,
+		Value:   value,
+	}
+}
+
 // StopCmd defines the stop JSON-RPC command.
 type StopCmd struct{}
 
@@ -1126,17 +1562,40 @@ type VersionCmd struct{}
 // version command.
 func NewVersionCmd() *VersionCmd { return new(VersionCmd) }
 
+// WriteProfileCmd defines the writeprofile JSON-RPC command.
+type WriteProfileCmd struct {
+	Profile string `jsonrpcusage:"\"cpu|heap|allocs|goroutine|block|mutex|threadcreate\""`
+	File    string
+	Seconds *uint32 `jsonrpcdefault:"30"`
+}
+
+// NewWriteProfileCmd returns a new instance which can be used to issue a
+// writeprofile JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewWriteProfileCmd(profile, file string, seconds *uint32) *WriteProfileCmd {
+	return &WriteProfileCmd{
+		Profile: profile,
+		File:    file,
+		Seconds: seconds,
+	}
+}
+
 func init() {
 	// No special flags for commands in this file.
 	flags := dcrjson.UsageFlag(0)
 
 	dcrjson.MustRegister(Method("addnode"), (*AddNodeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("advancetime"), (*AdvanceTimeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("compactdatabase"), (*CompactDatabaseCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawssrtx"), (*CreateRawSSRtxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawsstx"), (*CreateRawSStxCmd)(nil), flags)
 	dcrjson.MustRegister(Method("createrawtransaction"), (*CreateRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("debuglevel"), (*DebugLevelCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decoderawtransaction"), (*DecodeRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("decodescript"), (*DecodeScriptCmd)(nil), flags)
+	dcrjson.MustRegister(Method("deleteaddress"), (*DeleteAddressCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatefee"), (*EstimateFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatesmartfee"), (*EstimateSmartFeeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("estimatestakediff"), (*EstimateStakeDiffCmd)(nil), flags)
@@ -1147,27 +1606,39 @@ func init() {
 	dcrjson.MustRegister(Method("existsliveticket"), (*ExistsLiveTicketCmd)(nil), flags)
 	dcrjson.MustRegister(Method("existslivetickets"), (*ExistsLiveTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("existsmempooltxs"), (*ExistsMempoolTxsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("forcevote"), (*ForceVoteCmd)(nil), flags)
 	dcrjson.MustRegister(Method("generate"), (*GenerateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("generatetoaddress"), (*GenerateToAddressCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getaddednodeinfo"), (*GetAddedNodeInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getaddressutxos"), (*GetAddressUTXOsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getaddrmaninfo"), (*GetAddrManInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getbestblock"), (*GetBestBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getbestblockhash"), (*GetBestBlockHashCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblock"), (*GetBlockCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblockbytime"), (*GetBlockByTimeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockchaininfo"), (*GetBlockChainInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockcount"), (*GetBlockCountCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockhash"), (*GetBlockHashCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblockheader"), (*GetBlockHeaderCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblocksbytime"), (*GetBlocksByTimeCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getblocksubsidy"), (*GetBlockSubsidyCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getblocktemplate"), (*GetBlockTemplateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getbuildinfo"), (*GetBuildInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilter"), (*GetCFilterCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilterheader"), (*GetCFilterHeaderCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcfilterv2"), (*GetCFilterV2Cmd)(nil), flags)
+	dcrjson.MustRegister(Method("getcfilterv2headers"), (*GetCFilterV2HeadersCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getcfiltersv2"), (*GetCFiltersV2Cmd)(nil), flags)
 	dcrjson.MustRegister(Method("getchaintips"), (*GetChainTipsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcoinsupply"), (*GetCoinSupplyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getconnectioncount"), (*GetConnectionCountCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getcurrentnet"), (*GetCurrentNetCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getdatabaseinfo"), (*GetDatabaseInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getdifficulty"), (*GetDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getgenerate"), (*GetGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gethashespersec"), (*GetHashesPerSecCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getheaders"), (*GetHeadersCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getindexinfo"), (*GetIndexInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getinfo"), (*GetInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmempoolinfo"), (*GetMempoolInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getmininginfo"), (*GetMiningInfoCmd)(nil), flags)
@@ -1177,25 +1648,34 @@ func init() {
 	dcrjson.MustRegister(Method("getpeerinfo"), (*GetPeerInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawmempool"), (*GetRawMempoolCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getrawtransaction"), (*GetRawTransactionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getrpclimitinfo"), (*GetRPCLimitInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getscriptstats"), (*GetScriptStatsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getspentinfo"), (*GetSpentInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakedifficulty"), (*GetStakeDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversioninfo"), (*GetStakeVersionInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getstakeversions"), (*GetStakeVersionsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getsyncstatus"), (*GetSyncStatusCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getticketpoolinfo"), (*GetTicketPoolInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getticketpoolvalue"), (*GetTicketPoolValueCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettxout"), (*GetTxOutCmd)(nil), flags)
 	dcrjson.MustRegister(Method("gettxoutsetinfo"), (*GetTxOutSetInfoCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getvoteinfo"), (*GetVoteInfoCmd)(nil), flags)
+	dcrjson.MustRegister(Method("getvotestats"), (*GetVoteStatsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("getwork"), (*GetWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("help"), (*HelpCmd)(nil), flags)
 	dcrjson.MustRegister(Method("livetickets"), (*LiveTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("missedtickets"), (*MissedTicketsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("node"), (*NodeCmd)(nil), flags)
+	dcrjson.MustRegister(Method("pausegenerate"), (*PauseGenerateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ping"), (*PingCmd)(nil), flags)
+	dcrjson.MustRegister(Method("profileserver"), (*ProfileServerCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastmissed"), (*RebroadcastMissedCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rebroadcastwinners"), (*RebroadcastWinnersCmd)(nil), flags)
 	dcrjson.MustRegister(Method("regentemplate"), (*RegenTemplateCmd)(nil), flags)
 	dcrjson.MustRegister(Method("searchrawtransactions"), (*SearchRawTransactionsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("sendrawtransaction"), (*SendRawTransactionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("setgenerate"), (*SetGenerateCmd)(nil), flags)
+	dcrjson.MustRegister(Method("setpolicy"), (*SetPolicyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stop"), (*StopCmd)(nil), flags)
 	dcrjson.MustRegister(Method("submitblock"), (*SubmitBlockCmd)(nil), flags)
 	dcrjson.MustRegister(Method("ticketfeeinfo"), (*TicketFeeInfoCmd)(nil), flags)
@@ -1206,4 +1686,5 @@ func init() {
 	dcrjson.MustRegister(Method("verifychain"), (*VerifyChainCmd)(nil), flags)
 	dcrjson.MustRegister(Method("verifymessage"), (*VerifyMessageCmd)(nil), flags)
 	dcrjson.MustRegister(Method("version"), (*VersionCmd)(nil), flags)
+	dcrjson.MustRegister(Method("writeprofile"), (*WriteProfileCmd)(nil), flags)
 }
@@ -41,6 +41,28 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"addnode","params":["127.0.0.1","remove"],"id":1}`,
 			unmarshalled: &AddNodeCmd{Addr: "127.0.0.1", SubCmd: ANRemove},
 		},
+		{
+			name: "advancetime",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("advancetime"), 3600)
+			},
+			staticCmd: func() interface{} {
+				return NewAdvanceTimeCmd(3600)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"advancetime","params":[3600],"id":1}`,
+			unmarshalled: &AdvanceTimeCmd{Seconds: 3600},
+		},
+		{
+			name: "compactdatabase",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("compactdatabase"))
+			},
+			staticCmd: func() interface{} {
+				return NewCompactDatabaseCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"compactdatabase","params":[],"id":1}`,
+			unmarshalled: &CompactDatabaseCmd{},
+		},
 		{
 			name: "createrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -129,6 +151,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"decodescript","params":["00",1],"id":1}`,
 			unmarshalled: &DecodeScriptCmd{HexScript: "00", Version: dcrjson.Uint16(1)},
 		},
+		{
+			name: "deleteaddress",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("deleteaddress"), "12.1.2.3:9108")
+			},
+			staticCmd: func() interface{} {
+				return NewDeleteAddressCmd("12.1.2.3:9108")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"deleteaddress","params":["12.1.2.3:9108"],"id":1}`,
+			unmarshalled: &DeleteAddressCmd{Addr: "12.1.2.3:9108"},
+		},
 		{
 			name: "estimatefee",
 			newCmd: func() (interface{}, error) {
@@ -171,6 +204,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Mode:          EstimateSmartFeeModeAddr(EstimateSmartFeeConservative),
 			},
 		},
+		{
+			name: "forcevote",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("forcevote"), "lnfeatures", "yes")
+			},
+			staticCmd: func() interface{} {
+				return NewForceVoteCmd("lnfeatures", "yes")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"forcevote","params":["lnfeatures","yes"],"id":1}`,
+			unmarshalled: &ForceVoteCmd{AgendaID: "lnfeatures", ChoiceID: "yes"},
+		},
 		{
 			name: "generate",
 			newCmd: func() (interface{}, error) {
@@ -184,6 +228,20 @@ func TestChainSvrCmds(t *testing.T) {
 				NumBlocks: 1,
 			},
 		},
+		{
+			name: "generatetoaddress",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("generatetoaddress"), 1, "SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc")
+			},
+			staticCmd: func() interface{} {
+				return NewGenerateToAddressCmd(1, "SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"generatetoaddress","params":[1,"SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc"],"id":1}`,
+			unmarshalled: &GenerateToAddressCmd{
+				NumBlocks: 1,
+				Address:   "SsWKp7wtdTZYabYFYSc9cnxhwFEjA5g4pFc",
+			},
+		},
 		{
 			name: "getaddednodeinfo",
 			newCmd: func() (interface{}, error) {
@@ -209,6 +267,66 @@ func TestChainSvrCmds(t *testing.T) {
 				Node: dcrjson.String("127.0.0.1"),
 			},
 		},
+		{
+			name: "getaddressutxos",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddressutxos"), "1Address")
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressUTXOsCmd("1Address", nil, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressutxos","params":["1Address"],"id":1}`,
+			unmarshalled: &GetAddressUTXOsCmd{
+				Address:        "1Address",
+				Skip:           dcrjson.Int(0),
+				Count:          dcrjson.Int(100),
+				IncludeMempool: dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getaddressutxos optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddressutxos"), "1Address", 5,
+					10, false)
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddressUTXOsCmd("1Address", dcrjson.Int(5),
+					dcrjson.Int(10), dcrjson.Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressutxos","params":["1Address",5,10,false],"id":1}`,
+			unmarshalled: &GetAddressUTXOsCmd{
+				Address:        "1Address",
+				Skip:           dcrjson.Int(5),
+				Count:          dcrjson.Int(10),
+				IncludeMempool: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getaddrmaninfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddrmaninfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddrManInfoCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddrmaninfo","params":[],"id":1}`,
+			unmarshalled: &GetAddrManInfoCmd{
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getaddrmaninfo optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getaddrmaninfo"), true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetAddrManInfoCmd(dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddrmaninfo","params":[true],"id":1}`,
+			unmarshalled: &GetAddrManInfoCmd{
+				Verbose: dcrjson.Bool(true),
+			},
+		},
 		{
 			name: "getbestblock",
 			newCmd: func() (interface{}, error) {
@@ -244,6 +362,7 @@ func TestChainSvrCmds(t *testing.T) {
 				Hash:      "123",
 				Verbose:   dcrjson.Bool(true),
 				VerboseTx: dcrjson.Bool(false),
+				PrevOut:   dcrjson.Bool(false),
 			},
 		},
 		{
@@ -263,6 +382,7 @@ func TestChainSvrCmds(t *testing.T) {
 				Hash:      "123",
 				Verbose:   dcrjson.Bool(true),
 				VerboseTx: dcrjson.Bool(false),
+				PrevOut:   dcrjson.Bool(false),
 			},
 		},
 		{
@@ -278,6 +398,38 @@ func TestChainSvrCmds(t *testing.T) {
 				Hash:      "123",
 				Verbose:   dcrjson.Bool(true),
 				VerboseTx: dcrjson.Bool(true),
+				PrevOut:   dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "getblock required optional3",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblock"), "123", true, true,
+					true)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockWithPrevOutCmd("123", dcrjson.Bool(true),
+					dcrjson.Bool(true), dcrjson.Bool(true))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblock","params":["123",true,true,true],"id":1}`,
+			unmarshalled: &GetBlockCmd{
+				Hash:      "123",
+				Verbose:   dcrjson.Bool(true),
+				VerboseTx: dcrjson.Bool(true),
+				PrevOut:   dcrjson.Bool(true),
+			},
+		},
+		{
+			name: "getblockbytime",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblockbytime"), 1577836800)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockByTimeCmd(1577836800)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblockbytime","params":[1577836800],"id":1}`,
+			unmarshalled: &GetBlockByTimeCmd{
+				Timestamp: 1577836800,
 			},
 		},
 		{
@@ -327,6 +479,36 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Bool(true),
 			},
 		},
+		{
+			name: "getblocksbytime",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocksbytime"), 1577836800, 1577923200)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlocksByTimeCmd(1577836800, 1577923200, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocksbytime","params":[1577836800,1577923200],"id":1}`,
+			unmarshalled: &GetBlocksByTimeCmd{
+				Start: 1577836800,
+				End:   1577923200,
+				Count: dcrjson.Int(100),
+			},
+		},
+		{
+			name: "getblocksbytime optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocksbytime"), 1577836800, 1577923200, 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlocksByTimeCmd(1577836800, 1577923200, dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocksbytime","params":[1577836800,1577923200,10],"id":1}`,
+			unmarshalled: &GetBlocksByTimeCmd{
+				Start: 1577836800,
+				End:   1577923200,
+				Count: dcrjson.Int(10),
+			},
+		},
 		{
 			name: "getblocksubsidy",
 			newCmd: func() (interface{}, error) {
@@ -341,6 +523,49 @@ func TestChainSvrCmds(t *testing.T) {
 				Voters: 256,
 			},
 		},
+		{
+			name: "getblocktemplate",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocktemplate"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetBlockTemplateCmd(nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getblocktemplate","params":[],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{Request: nil},
+		},
+		{
+			name: "getblocktemplate optional - proposal",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getblocktemplate"),
+					`{"mode":"proposal","data":"0102"}`)
+			},
+			staticCmd: func() interface{} {
+				template := TemplateRequest{
+					Mode: "proposal",
+					Data: "0102",
+				}
+				return NewGetBlockTemplateCmd(&template)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getblocktemplate","params":[{"mode":"proposal","data":"0102"}],"id":1}`,
+			unmarshalled: &GetBlockTemplateCmd{
+				Request: &TemplateRequest{
+					Mode: "proposal",
+					Data: "0102",
+				},
+			},
+		},
+		{
+			name: "getbuildinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getbuildinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetBuildInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getbuildinfo","params":[],"id":1}`,
+			unmarshalled: &GetBuildInfoCmd{},
+		},
 		{
 			name: "getcfilter",
 			newCmd: func() (interface{}, error) {
@@ -382,6 +607,62 @@ func TestChainSvrCmds(t *testing.T) {
 				BlockHash: "123",
 			},
 		},
+		{
+			name: "getcfilterv2headers",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcfilterv2headers"), 100)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCFilterV2HeadersCmd(100, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcfilterv2headers","params":[100],"id":1}`,
+			unmarshalled: &GetCFilterV2HeadersCmd{
+				StartHeight: 100,
+				Count:       dcrjson.Int(2000),
+			},
+		},
+		{
+			name: "getcfilterv2headers optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcfilterv2headers"), 100, 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCFilterV2HeadersCmd(100, dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcfilterv2headers","params":[100,10],"id":1}`,
+			unmarshalled: &GetCFilterV2HeadersCmd{
+				StartHeight: 100,
+				Count:       dcrjson.Int(10),
+			},
+		},
+		{
+			name: "getcfiltersv2",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcfiltersv2"), 100)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCFiltersV2Cmd(100, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcfiltersv2","params":[100],"id":1}`,
+			unmarshalled: &GetCFiltersV2Cmd{
+				StartHeight: 100,
+				Count:       dcrjson.Int(100),
+			},
+		},
+		{
+			name: "getcfiltersv2 optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getcfiltersv2"), 100, 10)
+			},
+			staticCmd: func() interface{} {
+				return NewGetCFiltersV2Cmd(100, dcrjson.Int(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getcfiltersv2","params":[100,10],"id":1}`,
+			unmarshalled: &GetCFiltersV2Cmd{
+				StartHeight: 100,
+				Count:       dcrjson.Int(10),
+			},
+		},
 		{
 			name: "getchaintips",
 			newCmd: func() (interface{}, error) {
@@ -415,6 +696,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"getcurrentnet","params":[],"id":1}`,
 			unmarshalled: &GetCurrentNetCmd{},
 		},
+		{
+			name: "getdatabaseinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getdatabaseinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetDatabaseInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getdatabaseinfo","params":[],"id":1}`,
+			unmarshalled: &GetDatabaseInfoCmd{},
+		},
 		{
 			name: "getdifficulty",
 			newCmd: func() (interface{}, error) {
@@ -448,6 +740,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"gethashespersec","params":[],"id":1}`,
 			unmarshalled: &GetHashesPerSecCmd{},
 		},
+		{
+			name: "getindexinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getindexinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetIndexInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getindexinfo","params":[],"id":1}`,
+			unmarshalled: &GetIndexInfoCmd{},
+		},
 		{
 			name: "getinfo",
 			newCmd: func() (interface{}, error) {
@@ -596,6 +899,25 @@ func TestChainSvrCmds(t *testing.T) {
 				TxType:  dcrjson.String("all"),
 			},
 		},
+		{
+			name: "getrawmempool pagination",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getrawmempool"), true, "all",
+					100, "123")
+			},
+			staticCmd: func() interface{} {
+				return NewGetRawMempoolPageCmd(dcrjson.Bool(true),
+					dcrjson.String("all"), dcrjson.Int(100),
+					dcrjson.String("123"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getrawmempool","params":[true,"all",100,"123"],"id":1}`,
+			unmarshalled: &GetRawMempoolCmd{
+				Verbose: dcrjson.Bool(true),
+				TxType:  dcrjson.String("all"),
+				Count:   dcrjson.Int(100),
+				Cursor:  dcrjson.String("123"),
+			},
+		},
 		{
 			name: "getrawtransaction",
 			newCmd: func() (interface{}, error) {
@@ -624,6 +946,59 @@ func TestChainSvrCmds(t *testing.T) {
 				Verbose: dcrjson.Int(1),
 			},
 		},
+		{
+			name: "getrpclimitinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getrpclimitinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetRPCLimitInfoCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getrpclimitinfo","params":[],"id":1}`,
+			unmarshalled: &GetRPCLimitInfoCmd{},
+		},
+		{
+			name: "getscriptstats",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getscriptstats"), 100)
+			},
+			staticCmd: func() interface{} {
+				return NewGetScriptStatsCmd(100, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getscriptstats","params":[100],"id":1}`,
+			unmarshalled: &GetScriptStatsCmd{
+				StartHeight: 100,
+				EndHeight:   nil,
+			},
+		},
+		{
+			name: "getscriptstats optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getscriptstats"), 100, 200)
+			},
+			staticCmd: func() interface{} {
+				return NewGetScriptStatsCmd(100, dcrjson.Int64(200))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getscriptstats","params":[100,200],"id":1}`,
+			unmarshalled: &GetScriptStatsCmd{
+				StartHeight: 100,
+				EndHeight:   dcrjson.Int64(200),
+			},
+		},
+		{
+			name: "getspentinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getspentinfo"), "123", 1)
+			},
+			staticCmd: func() interface{} {
+				return NewGetSpentInfoCmd("123", 1)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getspentinfo","params":["123",1],"id":1}`,
+			unmarshalled: &GetSpentInfoCmd{
+				Txid: "123",
+				Vout: 1,
+			},
+		},
 		{
 			name: "getstakeversions",
 			newCmd: func() (interface{}, error) {
@@ -638,6 +1013,46 @@ func TestChainSvrCmds(t *testing.T) {
 				Count: 1,
 			},
 		},
+		{
+			name: "getsyncstatus",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getsyncstatus"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetSyncStatusCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getsyncstatus","params":[],"id":1}`,
+			unmarshalled: &GetSyncStatusCmd{},
+		},
+		{
+			name: "getticketpoolinfo",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getticketpoolinfo"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetTicketPoolInfoCmd(nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getticketpoolinfo","params":[],"id":1}`,
+			unmarshalled: &GetTicketPoolInfoCmd{
+				Buckets: dcrjson.Uint32(10),
+				Windows: dcrjson.Uint32(8),
+			},
+		},
+		{
+			name: "getticketpoolinfo optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getticketpoolinfo"), 5, 2)
+			},
+			staticCmd: func() interface{} {
+				return NewGetTicketPoolInfoCmd(dcrjson.Uint32(5),
+					dcrjson.Uint32(2))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getticketpoolinfo","params":[5,2],"id":1}`,
+			unmarshalled: &GetTicketPoolInfoCmd{
+				Buckets: dcrjson.Uint32(5),
+				Windows: dcrjson.Uint32(2),
+			},
+		},
 		{
 			name: "gettxout",
 			newCmd: func() (interface{}, error) {
@@ -692,6 +1107,32 @@ func TestChainSvrCmds(t *testing.T) {
 				Version: 1,
 			},
 		},
+		{
+			name: "getvotestats",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getvotestats"))
+			},
+			staticCmd: func() interface{} {
+				return NewGetVoteStatsCmd(nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getvotestats","params":[],"id":1}`,
+			unmarshalled: &GetVoteStatsCmd{
+				Count: dcrjson.Int32(1),
+			},
+		},
+		{
+			name: "getvotestats optional",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("getvotestats"), 3)
+			},
+			staticCmd: func() interface{} {
+				return NewGetVoteStatsCmd(dcrjson.Int32(3))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getvotestats","params":[3],"id":1}`,
+			unmarshalled: &GetVoteStatsCmd{
+				Count: dcrjson.Int32(3),
+			},
+		},
 		{
 			name: "getwork",
 			newCmd: func() (interface{}, error) {
@@ -744,6 +1185,56 @@ func TestChainSvrCmds(t *testing.T) {
 				Command: dcrjson.String("getblock"),
 			},
 		},
+		{
+			name: "livetickets",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("livetickets"))
+			},
+			staticCmd: func() interface{} {
+				return NewLiveTicketsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"livetickets","params":[],"id":1}`,
+			unmarshalled: &LiveTicketsCmd{},
+		},
+		{
+			name: "livetickets pagination",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("livetickets"), 10, "123")
+			},
+			staticCmd: func() interface{} {
+				return NewLiveTicketsPageCmd(dcrjson.Int(10), dcrjson.String("123"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"livetickets","params":[10,"123"],"id":1}`,
+			unmarshalled: &LiveTicketsCmd{
+				Count:  dcrjson.Int(10),
+				Cursor: dcrjson.String("123"),
+			},
+		},
+		{
+			name: "missedtickets",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("missedtickets"))
+			},
+			staticCmd: func() interface{} {
+				return NewMissedTicketsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"missedtickets","params":[],"id":1}`,
+			unmarshalled: &MissedTicketsCmd{},
+		},
+		{
+			name: "missedtickets pagination",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("missedtickets"), 10, "123")
+			},
+			staticCmd: func() interface{} {
+				return NewMissedTicketsPageCmd(dcrjson.Int(10), dcrjson.String("123"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"missedtickets","params":[10,"123"],"id":1}`,
+			unmarshalled: &MissedTicketsCmd{
+				Count:  dcrjson.Int(10),
+				Cursor: dcrjson.String("123"),
+			},
+		},
 		{
 			name: "node option remove",
 			newCmd: func() (interface{}, error) {
@@ -787,6 +1278,19 @@ func TestChainSvrCmds(t *testing.T) {
 				ConnectSubCmd: dcrjson.String("perm"),
 			},
 		},
+		{
+			name: "pausegenerate",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("pausegenerate"), true)
+			},
+			staticCmd: func() interface{} {
+				return NewPauseGenerateCmd(true)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"pausegenerate","params":[true],"id":1}`,
+			unmarshalled: &PauseGenerateCmd{
+				Pause: true,
+			},
+		},
 		{
 			name: "ping",
 			newCmd: func() (interface{}, error) {
@@ -798,6 +1302,31 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"ping","params":[],"id":1}`,
 			unmarshalled: &PingCmd{},
 		},
+		{
+			name: "profileserver",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("profileserver"), PSStop)
+			},
+			staticCmd: func() interface{} {
+				return NewProfileServerCmd(PSStop, nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"profileserver","params":["stop"],"id":1}`,
+			unmarshalled: &ProfileServerCmd{SubCmd: PSStop},
+		},
+		{
+			name: "profileserver listen",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("profileserver"), PSStart, "127.0.0.1:6061")
+			},
+			staticCmd: func() interface{} {
+				return NewProfileServerCmd(PSStart, dcrjson.String("127.0.0.1:6061"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"profileserver","params":["start","127.0.0.1:6061"],"id":1}`,
+			unmarshalled: &ProfileServerCmd{
+				SubCmd: PSStart,
+				Listen: dcrjson.String("127.0.0.1:6061"),
+			},
+		},
 		{
 			name: "searchrawtransactions",
 			newCmd: func() (interface{}, error) {
@@ -995,6 +1524,20 @@ func TestChainSvrCmds(t *testing.T) {
 				GenProcLimit: dcrjson.Int(6),
 			},
 		},
+		{
+			name: "setpolicy",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("setpolicy"), "banduration", "12h")
+			},
+			staticCmd: func() interface{} {
+				return NewSetPolicyCmd("banduration", "12h")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setpolicy","params":["banduration","12h"],"id":1}`,
+			unmarshalled: &SetPolicyCmd{
+				Setting: "banduration",
+				Value:   "12h",
+			},
+		},
 		{
 			name: "stop",
 			newCmd: func() (interface{}, error) {
@@ -1109,6 +1652,36 @@ func TestChainSvrCmds(t *testing.T) {
 				Message:   "test",
 			},
 		},
+		{
+			name: "writeprofile",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("writeprofile"), "heap", "heap.pprof")
+			},
+			staticCmd: func() interface{} {
+				return NewWriteProfileCmd("heap", "heap.pprof", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"writeprofile","params":["heap","heap.pprof"],"id":1}`,
+			unmarshalled: &WriteProfileCmd{
+				Profile: "heap",
+				File:    "heap.pprof",
+				Seconds: dcrjson.Uint32(30),
+			},
+		},
+		{
+			name: "writeprofile seconds",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("writeprofile"), "cpu", "cpu.pprof", 10)
+			},
+			staticCmd: func() interface{} {
+				return NewWriteProfileCmd("cpu", "cpu.pprof", dcrjson.Uint32(10))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"writeprofile","params":["cpu","cpu.pprof",10],"id":1}`,
+			unmarshalled: &WriteProfileCmd{
+				Profile: "cpu",
+				File:    "cpu.pprof",
+				Seconds: dcrjson.Uint32(10),
+			},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
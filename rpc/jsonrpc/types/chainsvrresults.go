@@ -40,6 +40,8 @@ type EstimateStakeDiffResult struct {
 	Min      float64  `json:"min"`
 	Max      float64  `json:"max"`
 	Expected float64  `json:"expected"`
+	Lower    float64  `json:"lower"`
+	Upper    float64  `json:"upper"`
 	User     *float64 `json:"user,omitempty"`
 }
 
@@ -57,6 +59,66 @@ type GetAddedNodeInfoResult struct {
 	Addresses *[]GetAddedNodeInfoResultAddr `json:"addresses,omitempty"`
 }
 
+// GetAddressUTXOsResult models an unspent transaction output returned by the
+// getaddressutxos command.  Unconfirmed outputs have a height and number of
+// confirmations of zero.
+type GetAddressUTXOsResult struct {
+	TxID          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Tree          int8    `json:"tree"`
+	Amount        float64 `json:"amount"`
+	ScriptPubKey  string  `json:"scriptpubkey"`
+	ScriptVersion uint16  `json:"scriptversion"`
+	Height        int64   `json:"height"`
+	Confirmations int64   `json:"confirmations"`
+}
+
+// AddrManNetworkInfo models the number of known addresses on a type of network
+// returned by the getaddrmaninfo command.
+type AddrManNetworkInfo struct {
+	New   int `json:"new"`
+	Tried int `json:"tried"`
+}
+
+// AddrManBucketsInfo models the occupancy of the new or tried buckets returned
+// by the getaddrmaninfo command.
+type AddrManBucketsInfo struct {
+	Count int `json:"count"`
+	Size  int `json:"size"`
+	Used  int `json:"used"`
+	Full  int `json:"full"`
+}
+
+// AddrManAddressInfo models a known address returned by the getaddrmaninfo
+// command when the verbose flag is set.
+type AddrManAddressInfo struct {
+	Address         string `json:"address"`
+	Source          string `json:"source"`
+	Network         string `json:"network"`
+	Services        string `json:"services"`
+	LastSeen        int64  `json:"lastseen"`
+	Attempts        int    `json:"attempts"`
+	LastAttempt     int64  `json:"lastattempt"`
+	LastSuccess     int64  `json:"lastsuccess"`
+	Tried           bool   `json:"tried"`
+	UserAgent       string `json:"useragent,omitempty"`
+	ProtocolVersion uint32 `json:"protocolversion,omitempty"`
+	LastBlock       int64  `json:"lastblock,omitempty"`
+}
+
+// GetAddrManInfoResult models the data returned from the getaddrmaninfo
+// command.  The addresses are only included when the verbose flag is set.
+type GetAddrManInfoResult struct {
+	New              int                           `json:"new"`
+	Tried            int                           `json:"tried"`
+	Networks         map[string]AddrManNetworkInfo `json:"networks"`
+	NewBuckets       AddrManBucketsInfo            `json:"newbuckets"`
+	TriedBuckets     AddrManBucketsInfo            `json:"triedbuckets"`
+	UserAgents       map[string]int                `json:"useragents"`
+	ProtocolVersions map[string]int                `json:"protocolversions"`
+	Addresses        []AddrManAddressInfo          `json:"addresses,omitempty"`
+}
+
 // GetBlockVerboseResult models the data from the getblock command when the
 // verbose flag is set.  When the verbose flag is not set, getblock returns a
 // hex-encoded string.  Contains Decred additions.
@@ -104,6 +166,14 @@ type GetBestBlockResult struct {
 	Height int64  `json:"height"`
 }
 
+// GetBlockByTimeResult models the data returned from the getblockbytime
+// command and each entry returned from the getblocksbytime command.
+type GetBlockByTimeResult struct {
+	Hash   string `json:"hash"`
+	Height int64  `json:"height"`
+	Time   int64  `json:"time"`
+}
+
 // GetBlockChainInfoResult models the data returned from the getblockchaininfo
 // command.
 type GetBlockChainInfoResult struct {
@@ -159,6 +229,16 @@ type GetBlockSubsidyResult struct {
 	Total     int64 `json:"total"`
 }
 
+// GetBuildInfoResult models the data returned from the getbuildinfo command.
+type GetBuildInfoResult struct {
+	Version      string   `json:"version"`
+	Commit       string   `json:"commit"`
+	GoVersion    string   `json:"goversion"`
+	OS           string   `json:"os"`
+	Arch         string   `json:"arch"`
+	Capabilities []string `json:"capabilities"`
+}
+
 // GetChainTipsResult models the data returns from the getchaintips command.
 type GetChainTipsResult struct {
 	Height    int64  `json:"height"`
@@ -175,12 +255,46 @@ type GetCFilterV2Result struct {
 	ProofHashes []string `json:"proofhashes"`
 }
 
+// GetCFilterV2HeaderResult models the data returned for each block from the
+// getcfilterv2headers command.
+type GetCFilterV2HeaderResult struct {
+	BlockHash   string   `json:"blockhash"`
+	Height      int64    `json:"height"`
+	Header      string   `json:"header"`
+	FilterHash  string   `json:"filterhash"`
+	ProofIndex  uint32   `json:"proofindex"`
+	ProofHashes []string `json:"proofhashes"`
+	Committed   bool     `json:"committed"`
+}
+
+// GetDatabaseInfoResult models the data returned from the getdatabaseinfo and
+// compactdatabase commands.
+type GetDatabaseInfoResult struct {
+	Type                string `json:"type"`
+	MetadataFiles       uint32 `json:"metadatafiles"`
+	MetadataSize        uint64 `json:"metadatasize"`
+	MetadataLiveSize    uint64 `json:"metadatalivesize"`
+	MetadataGarbageSize uint64 `json:"metadatagarbagesize"`
+	BlockFiles          uint32 `json:"blockfiles"`
+	BlockFilesSize      uint64 `json:"blockfilessize"`
+}
+
 // GetHeadersResult models the data returned by the chain server getheaders
 // command.
 type GetHeadersResult struct {
 	Headers []string `json:"headers"`
 }
 
+// GetIndexInfoResult models the data returned for each enabled index from the
+// getindexinfo command.
+type GetIndexInfoResult struct {
+	Name         string `json:"name"`
+	Height       int64  `json:"height"`
+	Synced       bool   `json:"synced"`
+	TargetHeight int64  `json:"targetheight,omitempty"`
+	ETA          int64  `json:"eta,omitempty"`
+}
+
 // InfoChainResult models the data returned by the chain server getinfo command.
 type InfoChainResult struct {
 	Version         int32   `json:"version"`
@@ -192,14 +306,18 @@ type InfoChainResult struct {
 	Difficulty      float64 `json:"difficulty"`
 	TestNet         bool    `json:"testnet"`
 	RelayFee        float64 `json:"relayfee"`
+	MaxOrphanTx     int     `json:"maxorphantx"`
+	MaxPeers        int     `json:"maxpeers"`
+	BanDuration     int64   `json:"banduration"`
 	Errors          string  `json:"errors"`
 }
 
 // GetMempoolInfoResult models the data returned from the getmempoolinfo
 // command.
 type GetMempoolInfoResult struct {
-	Size  int64 `json:"size"`
-	Bytes int64 `json:"bytes"`
+	Size        int64  `json:"size"`
+	Bytes       int64  `json:"bytes"`
+	RateLimited uint64 `json:"ratelimited"`
 }
 
 // GetMiningInfoResult models the data from the getmininginfo command.
@@ -213,6 +331,7 @@ type GetMiningInfoResult struct {
 	Errors           string  `json:"errors"`
 	Generate         bool    `json:"generate"`
 	GenProcLimit     int32   `json:"genproclimit"`
+	GeneratePaused   bool    `json:"generatepaused"`
 	HashesPerSec     int64   `json:"hashespersec"`
 	NetworkHashPS    int64   `json:"networkhashps"`
 	PooledTx         uint64  `json:"pooledtx"`
@@ -252,9 +371,20 @@ type GetNetworkInfoResult struct {
 
 // GetNetTotalsResult models the data returned from the getnettotals command.
 type GetNetTotalsResult struct {
-	TotalBytesRecv uint64 `json:"totalbytesrecv"`
-	TotalBytesSent uint64 `json:"totalbytessent"`
-	TimeMillis     int64  `json:"timemillis"`
+	TotalBytesRecv uint64             `json:"totalbytesrecv"`
+	TotalBytesSent uint64             `json:"totalbytessent"`
+	TimeMillis     int64              `json:"timemillis"`
+	UploadTarget   UploadTargetResult `json:"uploadtarget"`
+}
+
+// UploadTargetResult models the upload target data returned as part of the
+// getnettotals command.
+type UploadTargetResult struct {
+	TimeFrame             int64  `json:"timeframe"`
+	Target                uint64 `json:"target"`
+	TargetReached         bool   `json:"targetreached"`
+	ServeHistoricalBlocks bool   `json:"servehistoricalblocks"`
+	BytesLeftInCycle      uint64 `json:"bytesleftincycle"`
 }
 
 // GetPeerInfoResult models the data returned from the getpeerinfo command.
@@ -272,6 +402,9 @@ type GetPeerInfoResult struct {
 	TimeOffset     int64   `json:"timeoffset"`
 	PingTime       float64 `json:"pingtime"`
 	PingWait       float64 `json:"pingwait,omitempty"`
+	PingP50        float64 `json:"pingp50"`
+	PingP95        float64 `json:"pingp95"`
+	PingJitter     float64 `json:"pingjitter"`
 	Version        uint32  `json:"version"`
 	SubVer         string  `json:"subver"`
 	Inbound        bool    `json:"inbound"`
@@ -311,6 +444,47 @@ type TxRawResult struct {
 	Blocktime     int64  `json:"blocktime,omitempty"`
 }
 
+// RPCClientLimitInfo models the request statistics of a single client that
+// are returned as part of the getrpclimitinfo command.
+type RPCClientLimitInfo struct {
+	Address            string `json:"address"`
+	InFlight           int64  `json:"inflight"`
+	Requests           uint64 `json:"requests"`
+	RateLimited        uint64 `json:"ratelimited"`
+	ConcurrencyLimited uint64 `json:"concurrencylimited"`
+}
+
+// GetRPCLimitInfoResult models the data returned from the getrpclimitinfo
+// command.
+type GetRPCLimitInfoResult struct {
+	RateLimit          float64              `json:"ratelimit"`
+	RateBurst          int64                `json:"rateburst"`
+	MaxClientRequests  int64                `json:"maxclientrequests"`
+	RateLimited        uint64               `json:"ratelimited"`
+	ConcurrencyLimited uint64               `json:"concurrencylimited"`
+	Clients            []RPCClientLimitInfo `json:"clients"`
+}
+
+// GetScriptStatsResult models the data returned from the getscriptstats
+// command.
+type GetScriptStatsResult struct {
+	StartHeight           int64             `json:"startheight"`
+	EndHeight             int64             `json:"endheight"`
+	NumScripts            uint64            `json:"numscripts"`
+	NumUnparsable         uint64            `json:"numunparsable"`
+	NumUnsupportedVersion uint64            `json:"numunsupportedversion"`
+	Opcodes               map[string]uint64 `json:"opcodes"`
+	Classes               map[string]uint64 `json:"classes"`
+}
+
+// GetSpentInfoResult models the data returned from the getspentinfo command.
+type GetSpentInfoResult struct {
+	TxID      string `json:"txid"`
+	Vin       uint32 `json:"vin"`
+	BlockHash string `json:"blockhash"`
+	Height    int64  `json:"height"`
+}
+
 // GetStakeDifficultyResult models the data returned from the
 // getstakedifficulty command.
 type GetStakeDifficultyResult struct {
@@ -361,6 +535,46 @@ type GetStakeVersionsResult struct {
 	StakeVersions []StakeVersions `json:"stakeversions"`
 }
 
+// TicketPoolPriceBucket describes the number of live tickets that were
+// purchased for a price within a given range.
+type TicketPoolPriceBucket struct {
+	MinPrice float64 `json:"minprice"`
+	MaxPrice float64 `json:"maxprice"`
+	Count    uint32  `json:"count"`
+}
+
+// TicketPoolWindow describes the number of live tickets associated with a
+// range of block heights.
+type TicketPoolWindow struct {
+	StartHeight int64  `json:"startheight"`
+	EndHeight   int64  `json:"endheight"`
+	Count       uint32 `json:"count"`
+}
+
+// GetSyncStatusResult models the data returned from the getsyncstatus command.
+type GetSyncStatusResult struct {
+	SyncPeerID           int32   `json:"syncpeerid"`
+	Current              bool    `json:"current"`
+	HeadersFirst         bool    `json:"headersfirst"`
+	BlockHeight          int64   `json:"blockheight"`
+	HeaderHeight         int64   `json:"headerheight"`
+	SyncHeight           int64   `json:"syncheight"`
+	VerificationProgress float64 `json:"verificationprogress"`
+	BlocksPerSecond      float64 `json:"blockspersecond"`
+	BytesPerSecond       float64 `json:"bytespersecond"`
+	ETASeconds           int64   `json:"etaseconds"`
+}
+
+// GetTicketPoolInfoResult models the data returned from the getticketpoolinfo
+// command.
+type GetTicketPoolInfoResult struct {
+	Height       int64                   `json:"height"`
+	PoolSize     uint32                  `json:"poolsize"`
+	PriceBuckets []TicketPoolPriceBucket `json:"pricebuckets"`
+	Ages         []TicketPoolWindow      `json:"ages"`
+	Expirations  []TicketPoolWindow      `json:"expirations"`
+}
+
 // GetTxOutResult models the data from the gettxout command.
 type GetTxOutResult struct {
 	BestBlock     string             `json:"bestblock"`
@@ -417,6 +631,50 @@ type GetVoteInfoResult struct {
 	Agendas       []Agenda `json:"agendas,omitempty"`
 }
 
+// VoteVersionStats models the number and percentage of votes cast with a
+// given vote version within an interval along with the change in percentage
+// compared to the prior interval.
+type VoteVersionStats struct {
+	Version    uint32  `json:"version"`
+	Count      uint32  `json:"count"`
+	Percentage float64 `json:"percentage"`
+	Trend      float64 `json:"trend"`
+}
+
+// VoteChoiceStats models the number and percentage of votes cast for a given
+// agenda choice within an interval.
+type VoteChoiceStats struct {
+	ID         string  `json:"id"`
+	Count      uint32  `json:"count"`
+	Percentage float64 `json:"percentage"`
+}
+
+// AgendaVoteStats models the choices cast for a given agenda within an
+// interval.
+type AgendaVoteStats struct {
+	ID       string            `json:"id"`
+	Version  uint32            `json:"version"`
+	NumVotes uint32            `json:"numvotes"`
+	Choices  []VoteChoiceStats `json:"choices"`
+}
+
+// VoteStatsInterval models the vote version and agenda choice statistics for
+// an interval.
+type VoteStatsInterval struct {
+	StartHeight  int64              `json:"startheight"`
+	EndHeight    int64              `json:"endheight"`
+	NumVotes     uint32             `json:"numvotes"`
+	VoteVersions []VoteVersionStats `json:"voteversions"`
+	Agendas      []AgendaVoteStats  `json:"agendas"`
+}
+
+// GetVoteStatsResult models the data returned from the getvotestats command.
+type GetVoteStatsResult struct {
+	CurrentHeight int64               `json:"currentheight"`
+	Hash          string              `json:"hash"`
+	Intervals     []VoteStatsInterval `json:"intervals"`
+}
+
 // GetWorkResult models the data from the getwork command.
 type GetWorkResult struct {
 	Data   string `json:"data"`
@@ -575,6 +833,7 @@ type Vin struct {
 	BlockHeight uint32     `json:"blockheight"`
 	BlockIndex  uint32     `json:"blockindex"`
 	ScriptSig   *ScriptSig `json:"scriptSig"`
+	PrevOut     *PrevOut   `json:"prevOut,omitempty"`
 }
 
 // IsCoinBase returns a bool to show if a Vin is a Coinbase one or not.
@@ -632,6 +891,7 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 		BlockHeight uint32     `json:"blockheight"`
 		BlockIndex  uint32     `json:"blockindex"`
 		ScriptSig   *ScriptSig `json:"scriptSig"`
+		PrevOut     *PrevOut   `json:"prevOut,omitempty"`
 	}{
 		Txid:        v.Txid,
 		Vout:        v.Vout,
@@ -641,6 +901,7 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 		BlockHeight: v.BlockHeight,
 		BlockIndex:  v.BlockIndex,
 		ScriptSig:   v.ScriptSig,
+		PrevOut:     v.PrevOut,
 	}
 	return json.Marshal(txStruct)
 }
@@ -649,6 +910,7 @@ func (v *Vin) MarshalJSON() ([]byte, error) {
 type PrevOut struct {
 	Addresses []string `json:"addresses,omitempty"`
 	Value     float64  `json:"value"`
+	Type      string   `json:"type,omitempty"`
 }
 
 // VinPrevOut is like Vin except it includes PrevOut.  It is used by searchrawtransaction
@@ -43,6 +43,25 @@ func TestChainSvrCustomResults(t *testing.T) {
 			},
 			expected: `{"txid":"123","vout":1,"tree":0,"sequence":4294967295,"amountin":0,"blockheight":0,"blockindex":0,"scriptSig":{"asm":"0","hex":"00"}}`,
 		},
+		{
+			name: "custom vin marshal with prevout",
+			result: &Vin{
+				Txid: "123",
+				Vout: 1,
+				Tree: 0,
+				ScriptSig: &ScriptSig{
+					Asm: "0",
+					Hex: "00",
+				},
+				PrevOut: &PrevOut{
+					Addresses: []string{"addr1"},
+					Value:     1.5,
+					Type:      "pubkeyhash",
+				},
+				Sequence: 4294967295,
+			},
+			expected: `{"txid":"123","vout":1,"tree":0,"sequence":4294967295,"amountin":0,"blockheight":0,"blockindex":0,"scriptSig":{"asm":"0","hex":"00"},"prevOut":{"addresses":["addr1"],"value":1.5,"type":"pubkeyhash"}}`,
+		},
 		{
 			name: "custom vinprevout marshal with coinbase",
 			result: &VinPrevOut{
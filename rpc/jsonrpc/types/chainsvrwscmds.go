@@ -51,8 +51,28 @@ func NewLoadTxFilterCmd(reload bool, addresses []string, outPoints []OutPoint) *
 	}
 }
 
+// NotifyBlocksPayload defines the type used in the notifyblocks JSON-RPC
+// command for the Payload command field.
+type NotifyBlocksPayload string
+
+const (
+	// NBPHeader indicates blockconnected notifications should only include
+	// the block header.
+	NBPHeader NotifyBlocksPayload = "header"
+
+	// NBPTxIDs indicates blockconnected notifications should include the
+	// block header along with the hashes of all transactions in the block.
+	NBPTxIDs NotifyBlocksPayload = "txids"
+
+	// NBPFull indicates blockconnected notifications should include the
+	// full serialized block.
+	NBPFull NotifyBlocksPayload = "full"
+)
+
 // NotifyBlocksCmd defines the notifyblocks JSON-RPC command.
-type NotifyBlocksCmd struct{}
+type NotifyBlocksCmd struct {
+	Payload *string
+}
 
 // NewNotifyBlocksCmd returns a new instance which can be used to issue a
 // notifyblocks JSON-RPC command.
@@ -60,8 +80,23 @@ func NewNotifyBlocksCmd() *NotifyBlocksCmd {
 	return &NotifyBlocksCmd{}
 }
 
+// NewNotifyBlocksWithPayloadCmd returns a new instance which can be used to
+// issue a notifyblocks JSON-RPC command that requests additional block data in
+// blockconnected notifications.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewNotifyBlocksWithPayloadCmd(payload *string) *NotifyBlocksCmd {
+	return &NotifyBlocksCmd{
+		Payload: payload,
+	}
+}
+
 // NotifyWorkCmd defines the notifywork JSON-RPC command.
-type NotifyWorkCmd struct{}
+type NotifyWorkCmd struct {
+	Verbose     *bool `jsonrpcdefault:"false"`
+	ShareTarget *string
+}
 
 // NewNotifyWorkCmd returns a new instance which can be used to issue a
 // notifywork JSON-RPC command.
@@ -69,6 +104,19 @@ func NewNotifyWorkCmd() *NotifyWorkCmd {
 	return &NotifyWorkCmd{}
 }
 
+// NewNotifyWorkWithOptionsCmd returns a new instance which can be used to
+// issue a notifywork JSON-RPC command that requests verbose work
+// notifications and an optional share target.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewNotifyWorkWithOptionsCmd(verbose *bool, shareTarget *string) *NotifyWorkCmd {
+	return &NotifyWorkCmd{
+		Verbose:     verbose,
+		ShareTarget: shareTarget,
+	}
+}
+
 // NotifyWinningTicketsCmd is a type handling custom marshaling and
 // unmarshaling of notifywinningtickets JSON websocket extension
 // commands.
@@ -102,6 +150,17 @@ func NewNotifyNewTicketsCmd() *NotifyNewTicketsCmd {
 	return &NotifyNewTicketsCmd{}
 }
 
+// NotifyTicketLifecycleCmd is a type handling custom marshaling and
+// unmarshaling of notifyticketlifecycle JSON websocket extension
+// commands.
+type NotifyTicketLifecycleCmd struct {
+}
+
+// NewNotifyTicketLifecycleCmd creates a new NotifyTicketLifecycleCmd.
+func NewNotifyTicketLifecycleCmd() *NotifyTicketLifecycleCmd {
+	return &NotifyTicketLifecycleCmd{}
+}
+
 // NotifyStakeDifficultyCmd is a type handling custom marshaling and
 // unmarshaling of notifystakedifficulty JSON websocket extension
 // commands.
@@ -131,9 +190,28 @@ func NewStopNotifyWorkCmd() *StopNotifyWorkCmd {
 	return &StopNotifyWorkCmd{}
 }
 
+// NotifyNewTransactionsFilter restricts the transactions a client is notified
+// about by the notifynewtransactions JSON-RPC command.  A transaction matches
+// the filter when it matches every criterion that is specified and it matches
+// a criterion when it matches any of its entries.
+type NotifyNewTransactionsFilter struct {
+	// Addresses matches transactions with an output that pays to any of the
+	// addresses.
+	Addresses []string `json:"addresses,omitempty"`
+
+	// ScriptClasses matches transactions with an output script of any of
+	// the script classes such as pubkeyhash or stakesubmission.
+	ScriptClasses []string `json:"scriptclasses,omitempty"`
+
+	// TxTypes matches transactions of any of the types regular, tickets,
+	// votes, or revocations.
+	TxTypes []string `json:"txtypes,omitempty"`
+}
+
 // NotifyNewTransactionsCmd defines the notifynewtransactions JSON-RPC command.
 type NotifyNewTransactionsCmd struct {
 	Verbose *bool `jsonrpcdefault:"false"`
+	Filter  *NotifyNewTransactionsFilter
 }
 
 // NewNotifyNewTransactionsCmd returns a new instance which can be used to issue
@@ -147,6 +225,47 @@ func NewNotifyNewTransactionsCmd(verbose *bool) *NotifyNewTransactionsCmd {
 	}
 }
 
+// NewNotifyNewTransactionsFilteredCmd returns a new instance which can be used
+// to issue a notifynewtransactions JSON-RPC command that only requests
+// notifications for transactions matching the provided filter.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will use the default value.
+func NewNotifyNewTransactionsFilteredCmd(verbose *bool, filter *NotifyNewTransactionsFilter) *NotifyNewTransactionsCmd {
+	return &NotifyNewTransactionsCmd{
+		Verbose: verbose,
+		Filter:  filter,
+	}
+}
+
+// NotifyUTXOsCmd defines the notifyutxos JSON-RPC command.  The addresses,
+// hex-encoded output scripts, and outpoints are added to those previously
+// registered by the client.
+type NotifyUTXOsCmd struct {
+	Addresses []string
+	Scripts   []string
+	OutPoints []OutPoint
+}
+
+// NewNotifyUTXOsCmd returns a new instance which can be used to issue a
+// notifyutxos JSON-RPC command.
+func NewNotifyUTXOsCmd(addresses, scripts []string, outPoints []OutPoint) *NotifyUTXOsCmd {
+	return &NotifyUTXOsCmd{
+		Addresses: addresses,
+		Scripts:   scripts,
+		OutPoints: outPoints,
+	}
+}
+
+// StopNotifyUTXOsCmd defines the stopnotifyutxos JSON-RPC command.
+type StopNotifyUTXOsCmd struct{}
+
+// NewStopNotifyUTXOsCmd returns a new instance which can be used to issue a
+// stopnotifyutxos JSON-RPC command.
+func NewStopNotifyUTXOsCmd() *StopNotifyUTXOsCmd {
+	return &StopNotifyUTXOsCmd{}
+}
+
 // SessionCmd defines the session JSON-RPC command.
 type SessionCmd struct{}
 
@@ -195,9 +314,13 @@ func init() {
 		(*NotifyStakeDifficultyCmd)(nil), flags)
 	dcrjson.MustRegister(Method("notifywinningtickets"),
 		(*NotifyWinningTicketsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifyticketlifecycle"),
+		(*NotifyTicketLifecycleCmd)(nil), flags)
+	dcrjson.MustRegister(Method("notifyutxos"), (*NotifyUTXOsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("session"), (*SessionCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifyblocks"), (*StopNotifyBlocksCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifywork"), (*StopNotifyWorkCmd)(nil), flags)
 	dcrjson.MustRegister(Method("stopnotifynewtransactions"), (*StopNotifyNewTransactionsCmd)(nil), flags)
+	dcrjson.MustRegister(Method("stopnotifyutxos"), (*StopNotifyUTXOsCmd)(nil), flags)
 	dcrjson.MustRegister(Method("rescan"), (*RescanCmd)(nil), flags)
 }
@@ -74,6 +74,17 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"notifynewtickets","params":[],"id":1}`,
 			unmarshalled: &NotifyNewTicketsCmd{},
 		},
+		{
+			name: "notifyticketlifecycle",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyticketlifecycle"))
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyTicketLifecycleCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"notifyticketlifecycle","params":[],"id":1}`,
+			unmarshalled: &NotifyTicketLifecycleCmd{},
+		},
 		{
 			name: "notifystakedifficulty",
 			newCmd: func() (interface{}, error) {
@@ -96,6 +107,19 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"notifyblocks","params":[],"id":1}`,
 			unmarshalled: &NotifyBlocksCmd{},
 		},
+		{
+			name: "notifyblocks payload",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyblocks"), "txids")
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyBlocksWithPayloadCmd(dcrjson.String("txids"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"notifyblocks","params":["txids"],"id":1}`,
+			unmarshalled: &NotifyBlocksCmd{
+				Payload: dcrjson.String("txids"),
+			},
+		},
 		{
 			name: "notifywork",
 			newCmd: func() (interface{}, error) {
@@ -104,8 +128,25 @@ func TestChainSvrWsCmds(t *testing.T) {
 			staticCmd: func() interface{} {
 				return NewNotifyWorkCmd()
 			},
-			marshalled:   `{"jsonrpc":"1.0","method":"notifywork","params":[],"id":1}`,
-			unmarshalled: &NotifyWorkCmd{},
+			marshalled: `{"jsonrpc":"1.0","method":"notifywork","params":[],"id":1}`,
+			unmarshalled: &NotifyWorkCmd{
+				Verbose: dcrjson.Bool(false),
+			},
+		},
+		{
+			name: "notifywork verbose share target",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifywork"), true, "00ff")
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyWorkWithOptionsCmd(dcrjson.Bool(true),
+					dcrjson.String("00ff"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"notifywork","params":[true,"00ff"],"id":1}`,
+			unmarshalled: &NotifyWorkCmd{
+				Verbose:     dcrjson.Bool(true),
+				ShareTarget: dcrjson.String("00ff"),
+			},
 		},
 		{
 			name: "stopnotifyblocks",
@@ -155,6 +196,28 @@ func TestChainSvrWsCmds(t *testing.T) {
 				Verbose: dcrjson.Bool(true),
 			},
 		},
+		{
+			name: "notifynewtransactions filter",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifynewtransactions"), false,
+					`{"addresses":["DsExampleAddr"],"txtypes":["tickets","votes"]}`)
+			},
+			staticCmd: func() interface{} {
+				return NewNotifyNewTransactionsFilteredCmd(dcrjson.Bool(false),
+					&NotifyNewTransactionsFilter{
+						Addresses: []string{"DsExampleAddr"},
+						TxTypes:   []string{"tickets", "votes"},
+					})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"notifynewtransactions","params":[false,{"addresses":["DsExampleAddr"],"txtypes":["tickets","votes"]}],"id":1}`,
+			unmarshalled: &NotifyNewTransactionsCmd{
+				Verbose: dcrjson.Bool(false),
+				Filter: &NotifyNewTransactionsFilter{
+					Addresses: []string{"DsExampleAddr"},
+					TxTypes:   []string{"tickets", "votes"},
+				},
+			},
+		},
 		{
 			name: "stopnotifynewtransactions",
 			newCmd: func() (interface{}, error) {
@@ -166,6 +229,44 @@ func TestChainSvrWsCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifynewtransactions","params":[],"id":1}`,
 			unmarshalled: &StopNotifyNewTransactionsCmd{},
 		},
+		{
+			name: "notifyutxos",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("notifyutxos"),
+					[]string{"DsAddr"}, []string{"76a914"},
+					`[{"hash":"0000000000000000000000000000000000000000000000000000000000000123","tree":0,"index":1}]`)
+			},
+			staticCmd: func() interface{} {
+				outPoints := []OutPoint{{
+					Hash:  "0000000000000000000000000000000000000000000000000000000000000123",
+					Tree:  0,
+					Index: 1,
+				}}
+				return NewNotifyUTXOsCmd([]string{"DsAddr"},
+					[]string{"76a914"}, outPoints)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"notifyutxos","params":[["DsAddr"],["76a914"],[{"hash":"0000000000000000000000000000000000000000000000000000000000000123","tree":0,"index":1}]],"id":1}`,
+			unmarshalled: &NotifyUTXOsCmd{
+				Addresses: []string{"DsAddr"},
+				Scripts:   []string{"76a914"},
+				OutPoints: []OutPoint{{
+					Hash:  "0000000000000000000000000000000000000000000000000000000000000123",
+					Tree:  0,
+					Index: 1,
+				}},
+			},
+		},
+		{
+			name: "stopnotifyutxos",
+			newCmd: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("stopnotifyutxos"))
+			},
+			staticCmd: func() interface{} {
+				return NewStopNotifyUTXOsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"stopnotifyutxos","params":[],"id":1}`,
+			unmarshalled: &StopNotifyUTXOsCmd{},
+		},
 		{
 			name: "rescan",
 			newCmd: func() (interface{}, error) {
@@ -24,7 +24,7 @@ const (
 
 	// WorkNtfnMethod is the method used for notifications from
 	// the chain server that a new block template has been generated.
-	WorkNtfnMethod = "work"
+	WorkNtfnMethod Method = "work"
 
 	// ReorganizationNtfnMethod is the method used for notifications that the
 	// block chain is in the process of a reorganization.
@@ -56,12 +56,33 @@ const (
 	// WinningTicketsNtfnMethod is the method of the daemon winningtickets
 	// notification.
 	WinningTicketsNtfnMethod Method = "winningtickets"
+
+	// TicketLifecycleNtfnMethod is the method of the daemon ticketlifecycle
+	// notification.
+	TicketLifecycleNtfnMethod Method = "ticketlifecycle"
+
+	// UTXOCreatedNtfnMethod is the method of the daemon utxocreated
+	// notification.
+	UTXOCreatedNtfnMethod Method = "utxocreated"
+
+	// UTXOSpentNtfnMethod is the method of the daemon utxospent
+	// notification.
+	UTXOSpentNtfnMethod Method = "utxospent"
 )
 
+// BlockConnectedPayload models the additional block data included in a
+// blockconnected notification when requested by the notifyblocks command.
+type BlockConnectedPayload struct {
+	TxIDs  []string `json:"txids,omitempty"`
+	STxIDs []string `json:"stxids,omitempty"`
+	Block  string   `json:"block,omitempty"`
+}
+
 // BlockConnectedNtfn defines the blockconnected JSON-RPC notification.
 type BlockConnectedNtfn struct {
-	Header        string   `json:"header"`
-	SubscribedTxs []string `json:"subscribedtxs"`
+	Header        string                 `json:"header"`
+	SubscribedTxs []string               `json:"subscribedtxs"`
+	Payload       *BlockConnectedPayload `json:"payload,omitempty"`
 }
 
 // NewBlockConnectedNtfn returns a new instance which can be used to issue a
@@ -73,6 +94,20 @@ func NewBlockConnectedNtfn(header string, subscribedTxs []string) *BlockConnecte
 	}
 }
 
+// NewBlockConnectedWithPayloadNtfn returns a new instance which can be used to
+// issue a blockconnected JSON-RPC notification that includes additional block
+// data.
+//
+// The parameters which are pointers indicate they are optional.  Passing nil
+// for optional parameters will omit them.
+func NewBlockConnectedWithPayloadNtfn(header string, subscribedTxs []string, payload *BlockConnectedPayload) *BlockConnectedNtfn {
+	return &BlockConnectedNtfn{
+		Header:        header,
+		SubscribedTxs: subscribedTxs,
+		Payload:       payload,
+	}
+}
+
 // BlockDisconnectedNtfn defines the blockdisconnected JSON-RPC notification.
 type BlockDisconnectedNtfn struct {
 	Header string `json:"header"`
@@ -105,11 +140,21 @@ func NewNewTicketsNtfn(hash string, height int32, stakeDiff int64, tickets []str
 	}
 }
 
+// WorkNtfnDetails models the additional details about the block template
+// included in a work notification when requested by the notifywork command.
+type WorkNtfnDetails struct {
+	TemplateID  string `json:"templateid"`
+	Height      int64  `json:"height"`
+	PrevBlock   string `json:"prevblock"`
+	ShareTarget string `json:"sharetarget,omitempty"`
+}
+
 // WorkNtfn defines the work JSON-RPC notification.
 type WorkNtfn struct {
-	Data   string `json:"data"`
-	Target string `json:"target"`
-	Reason string `json:"reason"`
+	Data    string           `json:"data"`
+	Target  string           `json:"target"`
+	Reason  string           `json:"reason"`
+	Details *WorkNtfnDetails `json:"details,omitempty"`
 }
 
 // NewWorkNtfn returns a new instance which can be used to issue a
@@ -234,6 +279,89 @@ func NewWinningTicketsNtfn(hash string, height int32, tickets map[string]string)
 	}
 }
 
+// TicketLifecycleNtfn is a type handling custom marshaling and unmarshaling
+// of ticketlifecycle JSON websocket notifications.  It reports every ticket
+// whose state changed as a result of connecting the block with the given hash
+// and height to the main chain.
+type TicketLifecycleNtfn struct {
+	Hash     string
+	Height   int32
+	Live     []string
+	Selected []string
+	Voted    []string
+	Missed   []string
+	Expired  []string
+	Revoked  []string
+}
+
+// NewTicketLifecycleNtfn creates a new TicketLifecycleNtfn.
+func NewTicketLifecycleNtfn(hash string, height int32, live, selected, voted,
+	missed, expired, revoked []string) *TicketLifecycleNtfn {
+
+	return &TicketLifecycleNtfn{
+		Hash:     hash,
+		Height:   height,
+		Live:     live,
+		Selected: selected,
+		Voted:    voted,
+		Missed:   missed,
+		Expired:  expired,
+		Revoked:  revoked,
+	}
+}
+
+// UTXOCreatedNtfn defines the utxocreated JSON-RPC notification.  It reports an
+// output that pays to a registered address or script.  The block hash is empty
+// and the block height is -1 when the transaction that created the output was
+// accepted to the memory pool.
+type UTXOCreatedNtfn struct {
+	OutPoint    OutPoint
+	Amount      int64
+	Script      string
+	BlockHash   string
+	BlockHeight int64
+}
+
+// NewUTXOCreatedNtfn returns a new instance which can be used to issue a
+// utxocreated JSON-RPC notification.
+func NewUTXOCreatedNtfn(outPoint OutPoint, amount int64, script string,
+	blockHash string, blockHeight int64) *UTXOCreatedNtfn {
+
+	return &UTXOCreatedNtfn{
+		OutPoint:    outPoint,
+		Amount:      amount,
+		Script:      script,
+		BlockHash:   blockHash,
+		BlockHeight: blockHeight,
+	}
+}
+
+// UTXOSpentNtfn defines the utxospent JSON-RPC notification.  It reports the
+// transaction input that spends a watched output.  The block hash is empty and
+// the block height is -1 when the spending transaction was accepted to the
+// memory pool.
+type UTXOSpentNtfn struct {
+	OutPoint    OutPoint
+	SpenderTxID string
+	InputIndex  uint32
+	BlockHash   string
+	BlockHeight int64
+}
+
+// NewUTXOSpentNtfn returns a new instance which can be used to issue a
+// utxospent JSON-RPC notification.
+func NewUTXOSpentNtfn(outPoint OutPoint, spenderTxID string, inputIndex uint32,
+	blockHash string, blockHeight int64) *UTXOSpentNtfn {
+
+	return &UTXOSpentNtfn{
+		OutPoint:    outPoint,
+		SpenderTxID: spenderTxID,
+		InputIndex:  inputIndex,
+		BlockHash:   blockHash,
+		BlockHeight: blockHeight,
+	}
+}
+
 func init() {
 	// The commands in this file are only usable by websockets and are
 	// notifications.
@@ -250,4 +378,7 @@ func init() {
 	dcrjson.MustRegister(SpentAndMissedTicketsNtfnMethod, (*SpentAndMissedTicketsNtfn)(nil), flags)
 	dcrjson.MustRegister(StakeDifficultyNtfnMethod, (*StakeDifficultyNtfn)(nil), flags)
 	dcrjson.MustRegister(WinningTicketsNtfnMethod, (*WinningTicketsNtfn)(nil), flags)
+	dcrjson.MustRegister(TicketLifecycleNtfnMethod, (*TicketLifecycleNtfn)(nil), flags)
+	dcrjson.MustRegister(UTXOCreatedNtfnMethod, (*UTXOCreatedNtfn)(nil), flags)
+	dcrjson.MustRegister(UTXOSpentNtfnMethod, (*UTXOSpentNtfn)(nil), flags)
 }
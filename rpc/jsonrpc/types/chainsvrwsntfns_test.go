@@ -43,6 +43,29 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				SubscribedTxs: []string{"tx0", "tx1"},
 			},
 		},
+		{
+			name: "blockconnected payload",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("blockconnected"), "header",
+					[]string{}, `{"txids":["tx0"],"stxids":["tx1"]}`)
+			},
+			staticNtfn: func() interface{} {
+				return NewBlockConnectedWithPayloadNtfn("header", []string{},
+					&BlockConnectedPayload{
+						TxIDs:  []string{"tx0"},
+						STxIDs: []string{"tx1"},
+					})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"blockconnected","params":["header",[],{"txids":["tx0"],"stxids":["tx1"]}],"id":null}`,
+			unmarshalled: &BlockConnectedNtfn{
+				Header:        "header",
+				SubscribedTxs: []string{},
+				Payload: &BlockConnectedPayload{
+					TxIDs:  []string{"tx0"},
+					STxIDs: []string{"tx1"},
+				},
+			},
+		},
 		{
 			name: "blockdisconnected",
 			newNtfn: func() (interface{}, error) {
@@ -72,6 +95,69 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Tickets:   []string{"a", "b"},
 			},
 		},
+		{
+			name: "ticketlifecycle",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("ticketlifecycle"), "123", 100,
+					[]string{"a"}, []string{"b"}, []string{"c"},
+					[]string{"d"}, []string{"e"}, []string{"f"})
+			},
+			staticNtfn: func() interface{} {
+				return NewTicketLifecycleNtfn("123", 100, []string{"a"},
+					[]string{"b"}, []string{"c"}, []string{"d"},
+					[]string{"e"}, []string{"f"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"ticketlifecycle","params":["123",100,["a"],["b"],["c"],["d"],["e"],["f"]],"id":null}`,
+			unmarshalled: &TicketLifecycleNtfn{
+				Hash:     "123",
+				Height:   100,
+				Live:     []string{"a"},
+				Selected: []string{"b"},
+				Voted:    []string{"c"},
+				Missed:   []string{"d"},
+				Expired:  []string{"e"},
+				Revoked:  []string{"f"},
+			},
+		},
+		{
+			name: "utxocreated",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("utxocreated"),
+					`{"hash":"123","tree":0,"index":1}`, 100000000,
+					"76a914", "456", 100)
+			},
+			staticNtfn: func() interface{} {
+				op := OutPoint{Hash: "123", Tree: 0, Index: 1}
+				return NewUTXOCreatedNtfn(op, 100000000, "76a914", "456", 100)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"utxocreated","params":[{"hash":"123","tree":0,"index":1},100000000,"76a914","456",100],"id":null}`,
+			unmarshalled: &UTXOCreatedNtfn{
+				OutPoint:    OutPoint{Hash: "123", Tree: 0, Index: 1},
+				Amount:      100000000,
+				Script:      "76a914",
+				BlockHash:   "456",
+				BlockHeight: 100,
+			},
+		},
+		{
+			name: "utxospent",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("utxospent"),
+					`{"hash":"123","tree":1,"index":0}`, "789", 2, "", -1)
+			},
+			staticNtfn: func() interface{} {
+				op := OutPoint{Hash: "123", Tree: 1, Index: 0}
+				return NewUTXOSpentNtfn(op, "789", 2, "", -1)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"utxospent","params":[{"hash":"123","tree":1,"index":0},"789",2,"",-1],"id":null}`,
+			unmarshalled: &UTXOSpentNtfn{
+				OutPoint:    OutPoint{Hash: "123", Tree: 1, Index: 0},
+				SpenderTxID: "789",
+				InputIndex:  2,
+				BlockHash:   "",
+				BlockHeight: -1,
+			},
+		},
 		{
 			name: "relevanttxaccepted",
 			newNtfn: func() (interface{}, error) {
@@ -160,6 +246,58 @@ func TestChainSvrWsNtfns(t *testing.T) {
 				Tickets:     map[string]string{"a": "b"},
 			},
 		},
+		{
+			name: "work",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("work"), "data", "target",
+					"newparent")
+			},
+			staticNtfn: func() interface{} {
+				return &WorkNtfn{
+					Data:   "data",
+					Target: "target",
+					Reason: "newparent",
+				}
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"work","params":["data","target","newparent"],"id":null}`,
+			unmarshalled: &WorkNtfn{
+				Data:   "data",
+				Target: "target",
+				Reason: "newparent",
+			},
+		},
+		{
+			name: "work details",
+			newNtfn: func() (interface{}, error) {
+				return dcrjson.NewCmd(Method("work"), "data", "target",
+					"newvotes", `{"templateid":"id","height":100,"prevblock":"prev","sharetarget":"share"}`)
+			},
+			staticNtfn: func() interface{} {
+				return &WorkNtfn{
+					Data:   "data",
+					Target: "target",
+					Reason: "newvotes",
+					Details: &WorkNtfnDetails{
+						TemplateID:  "id",
+						Height:      100,
+						PrevBlock:   "prev",
+						ShareTarget: "share",
+					},
+				}
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"work","params":["data","target","newvotes",{"templateid":"id","height":100,"prevblock":"prev","sharetarget":"share"}],"id":null}`,
+			unmarshalled: &WorkNtfn{
+				Data:   "data",
+				Target: "target",
+				Reason: "newvotes",
+				Details: &WorkNtfnDetails{
+					TemplateID:  "id",
+					Height:      100,
+					PrevBlock:   "prev",
+					ShareTarget: "share",
+				},
+			},
+		},
 	}
 
 	t.Logf("Running %d tests", len(tests))
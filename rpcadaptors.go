@@ -6,6 +6,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/decred/dcrd/blockchain/v3"
 	"github.com/decred/dcrd/blockchain/v3/indexers"
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -166,6 +168,23 @@ func (cm *rpcConnManager) NetTotals() (uint64, uint64) {
 	return cm.server.NetTotals()
 }
 
+// UploadTarget returns details about the upload target that limits the serving
+// of historical blocks.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) UploadTarget() *rpcserver.UploadTarget {
+	budget := cm.server.uploadBudget
+	now := time.Now()
+	return &rpcserver.UploadTarget{
+		TimeFrame:             uploadBudgetWindow,
+		Target:                budget.target,
+		BytesLeft:             budget.bytesLeft(now),
+		TargetReached:         budget.targetReached(now),
+		ServeHistoricalBlocks: budget.serveHistorical(now),
+	}
+}
+
 // ConnectedPeers returns an array consisting of all connected peers.
 //
 // This function is safe for concurrent access and is part of the
@@ -244,6 +263,40 @@ func (cm *rpcConnManager) AddedNodeInfo() []rpcserver.Peer {
 	return peers
 }
 
+// MaxPeers returns the maximum number of inbound and outbound peers that are
+// currently allowed.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) MaxPeers() int {
+	return cm.server.MaxPeers()
+}
+
+// SetMaxPeers sets the maximum number of inbound and outbound peers that are
+// allowed.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) SetMaxPeers(maxPeers int) {
+	cm.server.SetMaxPeers(maxPeers)
+}
+
+// BanDuration returns the duration misbehaving peers are currently banned for.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) BanDuration() time.Duration {
+	return cm.server.BanDuration()
+}
+
+// SetBanDuration sets the duration misbehaving peers are banned for.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.ConnManager interface implementation.
+func (cm *rpcConnManager) SetBanDuration(banDuration time.Duration) {
+	cm.server.SetBanDuration(banDuration)
+}
+
 // rpcSyncMgr provides a block manager for use with the RPC server and
 // implements the rpcserver.SyncManager interface.
 type rpcSyncMgr struct {
@@ -317,6 +370,15 @@ func (b *rpcSyncMgr) SyncHeight() int64 {
 	return b.blockMgr.SyncHeight()
 }
 
+// SyncStatus returns details about the progress of syncing the chain with the
+// network.
+//
+// This function is safe for concurrent access and is part of the
+// rpcserver.SyncManager interface implementation.
+func (b *rpcSyncMgr) SyncStatus() *rpcserver.SyncStatus {
+	return b.blockMgr.SyncStatus()
+}
+
 // ProcessTransaction relays the provided transaction validation and insertion
 // into the memory pool.
 func (b *rpcSyncMgr) ProcessTransaction(tx *dcrutil.Tx, allowOrphans bool,
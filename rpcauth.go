@@ -0,0 +1,208 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// rpcPermission identifies the set of RPC methods a set of credentials is
+// authorized to invoke.
+type rpcPermission int
+
+const (
+	// rpcPermNone is the permission of clients that have not authenticated.
+	rpcPermNone rpcPermission = iota
+
+	// rpcPermAdmin permits all methods.
+	rpcPermAdmin
+
+	// rpcPermReadOnly permits the methods that are available to the limited
+	// user.  These methods query chain and mempool state and relay data to
+	// the network, but may not change the state of the server.
+	rpcPermReadOnly
+
+	// rpcPermMining permits the methods required by mining infrastructure
+	// to obtain and submit work along with a few basic chain queries.
+	rpcPermMining
+)
+
+// rpcPermissionStrings is a map of RPC permissions back to their constant
+// names for pretty printing and the names accepted by the --rpcauth option.
+var rpcPermissionStrings = map[rpcPermission]string{
+	rpcPermNone:     "none",
+	rpcPermAdmin:    "admin",
+	rpcPermReadOnly: "readonly",
+	rpcPermMining:   "mining",
+}
+
+// String returns the rpcPermission in human-readable form.
+func (p rpcPermission) String() string {
+	if s, ok := rpcPermissionStrings[p]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown rpcPermission (%d)", int(p))
+}
+
+// Commands that are available to credentials with the mining permission.
+var rpcMining = map[string]struct{}{
+	// Websockets commands
+	"notifyblocks": {},
+	"notifywork":   {},
+	"session":      {},
+
+	// Websockets AND HTTP/S commands
+	"help": {},
+
+	// HTTP/S-only commands
+	"getbestblock":       {},
+	"getbestblockhash":   {},
+	"getblockcount":      {},
+	"getblockhash":       {},
+	"getblockheader":     {},
+	"getblocksubsidy":    {},
+	"getblocktemplate":   {},
+	"getcurrentnet":      {},
+	"getdifficulty":      {},
+	"getinfo":            {},
+	"getmininginfo":      {},
+	"getnetworkhashps":   {},
+	"getstakedifficulty": {},
+	"getwork":            {},
+	"regentemplate":      {},
+	"submitblock":        {},
+	"version":            {},
+}
+
+// allows returns whether or not the permission authorizes the provided RPC
+// method.
+func (p rpcPermission) allows(method string) bool {
+	var ok bool
+	switch p {
+	case rpcPermAdmin:
+		ok = true
+	case rpcPermReadOnly:
+		_, ok = rpcLimited[method]
+	case rpcPermMining:
+		_, ok = rpcMining[method]
+	}
+	return ok
+}
+
+// rpcAuthUser houses a set of RPC credentials along with the permission they
+// grant.
+type rpcAuthUser struct {
+	user string
+	pass string
+	perm rpcPermission
+}
+
+// parseRPCScope returns the permission identified by the provided scope name
+// as accepted by the --rpcauth and --rpcclientcert options along with whether
+// or not the name is valid.
+func parseRPCScope(scope string) (rpcPermission, bool) {
+	for perm, name := range rpcPermissionStrings {
+		if perm != rpcPermNone && name == scope {
+			return perm, true
+		}
+	}
+	return rpcPermNone, false
+}
+
+// parseRPCAuth parses a set of RPC credentials in the form user:pass:scope as
+// accepted by the --rpcauth option.  The password may contain colons.
+func parseRPCAuth(s string) (*rpcAuthUser, error) {
+	userEnd := strings.Index(s, ":")
+	scopeStart := strings.LastIndex(s, ":")
+	if userEnd <= 0 || scopeStart == userEnd {
+		return nil, fmt.Errorf("malformed credentials -- must be in the " +
+			"form user:pass:scope")
+	}
+	user, pass, scope := s[:userEnd], s[userEnd+1:scopeStart], s[scopeStart+1:]
+	if pass == "" {
+		return nil, fmt.Errorf("empty password for user %q", user)
+	}
+	perm, ok := parseRPCScope(scope)
+	if !ok {
+		return nil, fmt.Errorf("unknown permission scope %q for user %q -- "+
+			"must be one of admin, readonly, or mining", scope, user)
+	}
+	return &rpcAuthUser{user: user, pass: pass, perm: perm}, nil
+}
+
+// parseRPCClientCert parses a client certificate subject common name and the
+// permission it grants in the form commonname:scope as accepted by the
+// --rpcclientcert option.  The common name may contain colons.
+func parseRPCClientCert(s string) (string, rpcPermission, error) {
+	scopeStart := strings.LastIndex(s, ":")
+	if scopeStart <= 0 {
+		return "", rpcPermNone, fmt.Errorf("malformed client certificate " +
+			"permission -- must be in the form commonname:scope")
+	}
+	commonName, scope := s[:scopeStart], s[scopeStart+1:]
+	perm, ok := parseRPCScope(scope)
+	if !ok {
+		return "", rpcPermNone, fmt.Errorf("unknown permission scope %q "+
+			"for client certificate %q -- must be one of admin, "+
+			"readonly, or mining", scope, commonName)
+	}
+	return commonName, perm, nil
+}
+
+// rpcCredential is the hash of an HTTP Basic authorization header value along
+// with the permission it grants.
+type rpcCredential struct {
+	authsha [sha256.Size]byte
+	perm    rpcPermission
+}
+
+// newRPCCredential returns the credential for the provided user.
+func newRPCCredential(u *rpcAuthUser) rpcCredential {
+	login := u.user + ":" + u.pass
+	auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	return rpcCredential{authsha: sha256.Sum256([]byte(auth)), perm: u.perm}
+}
+
+// matchCredential returns the permission granted by the credential that
+// matches the provided HTTP Basic authorization header value, or rpcPermNone
+// when there is no match.
+//
+// This check is time-constant with respect to the credential contents.  Every
+// credential is compared regardless of whether or not an earlier one matched.
+func matchCredential(creds []rpcCredential, authhdr string) rpcPermission {
+	authsha := sha256.Sum256([]byte(authhdr))
+	perm := rpcPermNone
+	for i := range creds {
+		cmp := subtle.ConstantTimeCompare(authsha[:], creds[i].authsha[:])
+		perm = rpcPermission(subtle.ConstantTimeSelect(cmp,
+			int(creds[i].perm), int(perm)))
+	}
+	return perm
+}
+
+// matchClientCert returns the permission granted to the client certificate
+// presented on the TLS connection with the provided state, or rpcPermNone when
+// the client did not present a certificate that was verified against the
+// configured client certificate authorities.
+//
+// Verified certificates are granted the permission associated with their
+// subject common name in the provided map.  All verified certificates are
+// granted the admin permission when the map is empty.
+func matchClientCert(perms map[string]rpcPermission, state *tls.ConnectionState) rpcPermission {
+	if state == nil || len(state.VerifiedChains) == 0 ||
+		len(state.VerifiedChains[0]) == 0 {
+
+		return rpcPermNone
+	}
+	if len(perms) == 0 {
+		return rpcPermAdmin
+	}
+	return perms[state.VerifiedChains[0][0].Subject.CommonName]
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"testing"
+
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+// TestParseRPCAuth ensures credentials specified via the --rpcauth option are
+// parsed as expected.
+func TestParseRPCAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    string
+		want    rpcAuthUser
+		wantErr bool
+	}{{
+		name: "admin",
+		auth: "user:pass:admin",
+		want: rpcAuthUser{user: "user", pass: "pass", perm: rpcPermAdmin},
+	}, {
+		name: "readonly",
+		auth: "monitor:pass:readonly",
+		want: rpcAuthUser{user: "monitor", pass: "pass", perm: rpcPermReadOnly},
+	}, {
+		name: "mining with colons in password",
+		auth: "pool:p:a:ss:mining",
+		want: rpcAuthUser{user: "pool", pass: "p:a:ss", perm: rpcPermMining},
+	}, {
+		name:    "missing scope",
+		auth:    "user:pass",
+		wantErr: true,
+	}, {
+		name:    "no separators",
+		auth:    "user",
+		wantErr: true,
+	}, {
+		name:    "empty username",
+		auth:    ":pass:admin",
+		wantErr: true,
+	}, {
+		name:    "empty password",
+		auth:    "user::admin",
+		wantErr: true,
+	}, {
+		name:    "unknown scope",
+		auth:    "user:pass:root",
+		wantErr: true,
+	}, {
+		name:    "none scope",
+		auth:    "user:pass:none",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		got, err := parseRPCAuth(test.auth)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if *got != test.want {
+			t.Errorf("%q: mismatched credentials -- got %+v, want %+v",
+				test.name, *got, test.want)
+		}
+	}
+}
+
+// TestRPCPermissionAllows ensures each permission authorizes the expected
+// methods and that every method granted to a restricted permission exists.
+func TestRPCPermissionAllows(t *testing.T) {
+	tests := []struct {
+		perm   rpcPermission
+		method string
+		want   bool
+	}{
+		{rpcPermNone, "getbestblock", false},
+		{rpcPermAdmin, "getbestblock", true},
+		{rpcPermAdmin, "stop", true},
+		{rpcPermReadOnly, "getbestblock", true},
+		{rpcPermReadOnly, "getwork", false},
+		{rpcPermReadOnly, "stop", false},
+		{rpcPermMining, "getwork", true},
+		{rpcPermMining, "submitblock", true},
+		{rpcPermMining, "getbestblock", true},
+		{rpcPermMining, "searchrawtransactions", false},
+		{rpcPermMining, "setgenerate", false},
+		{rpcPermMining, "stop", false},
+	}
+	for _, test := range tests {
+		if got := test.perm.allows(test.method); got != test.want {
+			t.Errorf("%v permission allows %q: got %v, want %v", test.perm,
+				test.method, got, test.want)
+		}
+	}
+
+	for method := range rpcMining {
+		_, ok := rpcHandlers[types.Method(method)]
+		_, wsOk := wsHandlers[types.Method(method)]
+		if !ok && !wsOk {
+			t.Errorf("mining method %q does not exist", method)
+		}
+	}
+}
+
+// TestMatchCredential ensures HTTP basic authorization header values are
+// matched to the permission of the associated credentials.
+func TestMatchCredential(t *testing.T) {
+	users := []*rpcAuthUser{
+		{user: "admin", pass: "adminpass", perm: rpcPermAdmin},
+		{user: "monitor", pass: "monitorpass", perm: rpcPermReadOnly},
+		{user: "pool", pass: "poolpass", perm: rpcPermMining},
+	}
+	creds := make([]rpcCredential, 0, len(users))
+	for _, u := range users {
+		creds = append(creds, newRPCCredential(u))
+	}
+
+	basic := func(login string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
+	}
+	tests := []struct {
+		authhdr string
+		want    rpcPermission
+	}{
+		{basic("admin:adminpass"), rpcPermAdmin},
+		{basic("monitor:monitorpass"), rpcPermReadOnly},
+		{basic("pool:poolpass"), rpcPermMining},
+		{basic("pool:adminpass"), rpcPermNone},
+		{basic(""), rpcPermNone},
+		{"", rpcPermNone},
+	}
+	for _, test := range tests {
+		if got := matchCredential(creds, test.authhdr); got != test.want {
+			t.Errorf("%q: got %v, want %v", test.authhdr, got, test.want)
+		}
+	}
+}
+
+// TestParseRPCClientCert ensures client certificate permissions specified via
+// the --rpcclientcert option are parsed as expected.
+func TestParseRPCClientCert(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientCert string
+		commonName string
+		perm       rpcPermission
+		wantErr    bool
+	}{{
+		name:       "readonly",
+		clientCert: "monitor:readonly",
+		commonName: "monitor",
+		perm:       rpcPermReadOnly,
+	}, {
+		name:       "mining with colons in common name",
+		clientCert: "pool:1:mining",
+		commonName: "pool:1",
+		perm:       rpcPermMining,
+	}, {
+		name:       "missing scope",
+		clientCert: "monitor",
+		wantErr:    true,
+	}, {
+		name:       "empty common name",
+		clientCert: ":admin",
+		wantErr:    true,
+	}, {
+		name:       "unknown scope",
+		clientCert: "monitor:root",
+		wantErr:    true,
+	}}
+
+	for _, test := range tests {
+		commonName, perm, err := parseRPCClientCert(test.clientCert)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if commonName != test.commonName || perm != test.perm {
+			t.Errorf("%q: mismatched result -- got %q:%v, want %q:%v",
+				test.name, commonName, perm, test.commonName, test.perm)
+		}
+	}
+}
+
+// TestMatchClientCert ensures verified TLS client certificates are matched to
+// the permission associated with their subject common name.
+func TestMatchClientCert(t *testing.T) {
+	verified := func(commonName string) *tls.ConnectionState {
+		cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+		return &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{cert}},
+		}
+	}
+	unverified := &tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{{
+			Subject: pkix.Name{CommonName: "monitor"},
+		}},
+	}
+	perms := map[string]rpcPermission{
+		"monitor": rpcPermReadOnly,
+		"pool":    rpcPermMining,
+	}
+
+	tests := []struct {
+		name  string
+		perms map[string]rpcPermission
+		state *tls.ConnectionState
+		want  rpcPermission
+	}{
+		{"no tls", perms, nil, rpcPermNone},
+		{"no certificate", perms, &tls.ConnectionState{}, rpcPermNone},
+		{"unverified certificate", perms, unverified, rpcPermNone},
+		{"readonly", perms, verified("monitor"), rpcPermReadOnly},
+		{"mining", perms, verified("pool"), rpcPermMining},
+		{"unlisted", perms, verified("other"), rpcPermNone},
+		{"no permissions configured", nil, verified("other"), rpcPermAdmin},
+	}
+	for _, test := range tests {
+		if got := matchClientCert(test.perms, test.state); got != test.want {
+			t.Errorf("%q: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
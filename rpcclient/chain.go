@@ -160,6 +160,32 @@ func (c *Client) GetBlockVerbose(ctx context.Context, blockHash *chainhash.Hash,
 	return c.GetBlockVerboseAsync(ctx, blockHash, verboseTx).Receive()
 }
 
+// GetBlockVerbosePrevOutAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetBlockVerbosePrevOut for the blocking version and more details.
+func (c *Client) GetBlockVerbosePrevOutAsync(ctx context.Context, blockHash *chainhash.Hash) FutureGetBlockVerboseResult {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+
+	cmd := chainjson.NewGetBlockWithPrevOutCmd(hash, dcrjson.Bool(true),
+		dcrjson.Bool(true), dcrjson.Bool(true))
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetBlockVerbosePrevOut returns a data structure from the server with
+// information about a main chain block given its hash along with all of its
+// transactions.  Each transaction input also includes the value, addresses, and
+// script type of the previous output it spends.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBlockVerbosePrevOut(ctx context.Context, blockHash *chainhash.Hash) (*chainjson.GetBlockVerboseResult, error) {
+	return c.GetBlockVerbosePrevOutAsync(ctx, blockHash).Receive()
+}
+
 // FutureGetBlockCountResult is a future promise to deliver the result of a
 // GetBlockCountAsync RPC invocation (or an applicable error).
 type FutureGetBlockCountResult chan *response
@@ -560,6 +586,36 @@ func (c *Client) GetRawMempool(ctx context.Context, txType chainjson.GetRawMempo
 	return c.GetRawMempoolAsync(ctx, txType).Receive()
 }
 
+// pageCursor returns the cursor parameter for a paginated request that
+// continues after the provided hash.  A nil hash requests the first page.
+func pageCursor(after *chainhash.Hash) *string {
+	if after == nil {
+		return nil
+	}
+	return dcrjson.String(after.String())
+}
+
+// GetRawMempoolPageAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetRawMempoolPage for the blocking version and more details.
+func (c *Client) GetRawMempoolPageAsync(ctx context.Context, txType chainjson.GetRawMempoolTxTypeCmd, count int, after *chainhash.Hash) FutureGetRawMempoolResult {
+	cmd := chainjson.NewGetRawMempoolPageCmd(dcrjson.Bool(false),
+		dcrjson.String(string(txType)), dcrjson.Int(count), pageCursor(after))
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetRawMempoolPage returns up to count hashes of transactions in the memory
+// pool for the given txType in ascending order.  Only the hashes that sort
+// after the provided hash are returned, so the final hash of one page may be
+// passed to request the next page.  Passing nil requests the first page.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetRawMempoolPage(ctx context.Context, txType chainjson.GetRawMempoolTxTypeCmd, count int, after *chainhash.Hash) ([]*chainhash.Hash, error) {
+	return c.GetRawMempoolPageAsync(ctx, txType, count, after).Receive()
+}
+
 // FutureGetRawMempoolVerboseResult is a future promise to deliver the result of
 // a GetRawMempoolVerboseAsync RPC invocation (or an applicable error).
 type FutureGetRawMempoolVerboseResult chan *response
@@ -603,6 +659,27 @@ func (c *Client) GetRawMempoolVerbose(ctx context.Context, txType chainjson.GetR
 	return c.GetRawMempoolVerboseAsync(ctx, txType).Receive()
 }
 
+// GetRawMempoolVerbosePageAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetRawMempoolVerbosePage for the blocking version and more details.
+func (c *Client) GetRawMempoolVerbosePageAsync(ctx context.Context, txType chainjson.GetRawMempoolTxTypeCmd, count int, after *chainhash.Hash) FutureGetRawMempoolVerboseResult {
+	cmd := chainjson.NewGetRawMempoolPageCmd(dcrjson.Bool(true),
+		dcrjson.String(string(txType)), dcrjson.Int(count), pageCursor(after))
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetRawMempoolVerbosePage returns a map of up to count transaction hashes in
+// the memory pool for the given txType to an associated data structure with
+// information about the transaction.  The page is selected in the same manner
+// as GetRawMempoolPage.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetRawMempoolVerbosePage(ctx context.Context, txType chainjson.GetRawMempoolTxTypeCmd, count int, after *chainhash.Hash) (map[string]chainjson.GetRawMempoolVerboseResult, error) {
+	return c.GetRawMempoolVerbosePageAsync(ctx, txType, count, after).Receive()
+}
+
 // FutureVerifyChainResult is a future promise to deliver the result of a
 // VerifyChainAsync, VerifyChainLevelAsyncRPC, or VerifyChainBlocksAsync
 // invocation (or an applicable error).
@@ -944,6 +1021,12 @@ func (r FutureGetCFilterV2Result) Receive() (*CFilterV2Result, error) {
 		return nil, err
 	}
 
+	return decodeCFilterV2Result(&filterResult)
+}
+
+// decodeCFilterV2Result decodes the passed getcfilterv2 result object into the
+// returned CFilterV2Result.
+func decodeCFilterV2Result(filterResult *chainjson.GetCFilterV2Result) (*CFilterV2Result, error) {
 	blockHash, err := chainhash.NewHashFromStr(filterResult.BlockHash)
 	if err != nil {
 		return nil, err
@@ -992,6 +1075,138 @@ func (c *Client) GetCFilterV2(ctx context.Context, blockHash *chainhash.Hash) (*
 	return c.GetCFilterV2Async(ctx, blockHash).Receive()
 }
 
+// FutureGetCFiltersV2Result is a future promise to deliver the result of a
+// GetCFiltersV2Async RPC invocation (or an applicable error).
+type FutureGetCFiltersV2Result chan *response
+
+// Receive waits for the response promised by the future and returns the
+// version 2 block filters for the requested range of blocks.
+func (r FutureGetCFiltersV2Result) Receive() ([]*CFilterV2Result, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var filterResults []chainjson.GetCFilterV2Result
+	err = json.Unmarshal(res, &filterResults)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*CFilterV2Result, 0, len(filterResults))
+	for i := range filterResults {
+		result, err := decodeCFilterV2Result(&filterResults[i])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// GetCFiltersV2Async returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetCFiltersV2 for the blocking version and more details.
+func (c *Client) GetCFiltersV2Async(ctx context.Context, startHeight int64, count *int) FutureGetCFiltersV2Result {
+	cmd := chainjson.NewGetCFiltersV2Cmd(startHeight, count)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetCFiltersV2 returns the version 2 block filters for up to count main chain
+// blocks starting at the given height along with the proofs that can be used to
+// prove the filters are committed to by the block headers.  A nil count uses
+// the server default.
+func (c *Client) GetCFiltersV2(ctx context.Context, startHeight int64, count *int) ([]*CFilterV2Result, error) {
+	return c.GetCFiltersV2Async(ctx, startHeight, count).Receive()
+}
+
+// CFilterV2HeaderResult is an entry in the result of calling the
+// GetCFilterV2Headers and GetCFilterV2HeadersAsync methods.
+type CFilterV2HeaderResult struct {
+	Header      wire.BlockHeader
+	FilterHash  chainhash.Hash
+	ProofIndex  uint32
+	ProofHashes []chainhash.Hash
+	Committed   bool
+}
+
+// FutureGetCFilterV2HeadersResult is a future promise to deliver the result of
+// a GetCFilterV2HeadersAsync RPC invocation (or an applicable error).
+type FutureGetCFilterV2HeadersResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// version 2 filter hashes along with the block headers and proofs that commit
+// to them.
+func (r FutureGetCFilterV2HeadersResult) Receive() ([]CFilterV2HeaderResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var headerResults []chainjson.GetCFilterV2HeaderResult
+	err = json.Unmarshal(res, &headerResults)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CFilterV2HeaderResult, 0, len(headerResults))
+	for i := range headerResults {
+		headerResult := &headerResults[i]
+		serializedHeader, err := hex.DecodeString(headerResult.Header)
+		if err != nil {
+			return nil, err
+		}
+		var header wire.BlockHeader
+		err = header.Deserialize(bytes.NewReader(serializedHeader))
+		if err != nil {
+			return nil, err
+		}
+
+		filterHash, err := chainhash.NewHashFromStr(headerResult.FilterHash)
+		if err != nil {
+			return nil, err
+		}
+
+		proofHashes := make([]chainhash.Hash, 0, len(headerResult.ProofHashes))
+		for _, proofHashStr := range headerResult.ProofHashes {
+			proofHash, err := chainhash.NewHashFromStr(proofHashStr)
+			if err != nil {
+				return nil, err
+			}
+			proofHashes = append(proofHashes, *proofHash)
+		}
+
+		results = append(results, CFilterV2HeaderResult{
+			Header:      header,
+			FilterHash:  *filterHash,
+			ProofIndex:  headerResult.ProofIndex,
+			ProofHashes: proofHashes,
+			Committed:   headerResult.Committed,
+		})
+	}
+	return results, nil
+}
+
+// GetCFilterV2HeadersAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetCFilterV2Headers for the blocking version and more details.
+func (c *Client) GetCFilterV2HeadersAsync(ctx context.Context, startHeight int64, count *int) FutureGetCFilterV2HeadersResult {
+	cmd := chainjson.NewGetCFilterV2HeadersCmd(startHeight, count)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetCFilterV2Headers returns the hashes of the version 2 block filters for up
+// to count main chain blocks starting at the given height along with the block
+// headers and the proofs that can be used to prove the filters are committed to
+// by the headers.  A nil count uses the server default.
+func (c *Client) GetCFilterV2Headers(ctx context.Context, startHeight int64, count *int) ([]CFilterV2HeaderResult, error) {
+	return c.GetCFilterV2HeadersAsync(ctx, startHeight, count).Receive()
+}
+
 // FutureEstimateSmartFeeResult is a future promise to deliver the result of a
 // EstimateSmartFee RPC invocation (or an applicable error).
 type FutureEstimateSmartFeeResult chan *response
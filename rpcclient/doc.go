@@ -56,6 +56,16 @@ the Receive method on the returned instance will either return the result
 immediately if it has already arrived, or block until it has.  This is useful
 since it provides the caller with greater control over concurrency.
 
+Cancellation
+
+All methods accept a context.Context which governs the lifetime of the request
+in both websocket and HTTP POST mode.  Should the context be canceled or its
+deadline be exceeded before the server replies, the request is abandoned and
+the error from the context is returned from both the synchronous methods and
+the Receive method of the futures returned by the asynchronous methods.  This
+allows callers to enforce deadlines instead of blocking indefinitely on a
+stalled server.
+
 Notifications
 
 The first important part of notifications is to realize that they will only
@@ -92,6 +102,12 @@ notifications are automatically re-registered and any in-flight commands are
 re-issued.  This means from the caller's perspective, the request simply takes
 longer to complete.
 
+Re-registered notifications include any transaction filter loaded via
+LoadTxFilter.  However, notifications that occur while the client is
+disconnected are not delivered.  Callers that need to detect such gaps may set
+the OnReconnected notification handler, which is invoked with the duration the
+client was disconnected once all notifications have been re-registered.
+
 The caller may invoke the Shutdown method on the client to force the client
 to cease reconnect attempts and return ErrClientShutdown for all outstanding
 commands.
@@ -99,6 +115,17 @@ commands.
 The automatic reconnection can be disabled by setting the DisableAutoReconnect
 flag to true in the connection config when creating the client.
 
+Multiple Servers
+
+A Pool may be used to survive outages of individual RPC servers.  It is created
+with the connection configurations of multiple servers in priority order and
+periodically checks their health, which includes whether or not their best
+chain is lagging behind the others when the MaxBlockLag option is set.  The Do
+method invokes a function with the client for the first healthy server and
+fails over to the next one should the request fail due to a connection issue.
+When the LoadBalanceReads option is set, the ReadClient and DoRead methods
+distribute requests across all healthy servers.
+
 Interacting with Dcrwallet
 
 This package only provides methods for dcrd RPCs.  Using the websocket
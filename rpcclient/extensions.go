@@ -8,8 +8,10 @@ package rpcclient
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/wire"
@@ -20,6 +22,92 @@ var (
 	zeroUint32 = uint32(0)
 )
 
+// FutureAdvanceTimeResult is a future promise to deliver the result of an
+// AdvanceTimeAsync RPC invocation (or an applicable error).
+type FutureAdvanceTimeResult chan *response
+
+// Receive waits for the response promised by the future and returns the new
+// adjusted time of the server.
+func (r FutureAdvanceTimeResult) Receive() (time.Time, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Unmarshal result as an int64.
+	var unixTime int64
+	err = json.Unmarshal(res, &unixTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(unixTime, 0), nil
+}
+
+// AdvanceTimeAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See AdvanceTime for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) AdvanceTimeAsync(ctx context.Context, d time.Duration) FutureAdvanceTimeResult {
+	cmd := chainjson.NewAdvanceTimeCmd(int64(d / time.Second))
+	return c.sendCmd(ctx, cmd)
+}
+
+// AdvanceTime advances the adjusted time of the server by the passed duration,
+// truncated to whole seconds, and returns the new adjusted time.  It is only
+// available on the simulation and regression test networks.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) AdvanceTime(ctx context.Context, d time.Duration) (time.Time, error) {
+	return c.AdvanceTimeAsync(ctx, d).Receive()
+}
+
+// FutureCompactDatabaseResult is a future promise to deliver the result of a
+// CompactDatabaseAsync RPC invocation (or an applicable error).
+type FutureCompactDatabaseResult chan *response
+
+// Receive waits for the response promised by the future and returns details
+// about the storage used by the database after it has been compacted.
+func (r FutureCompactDatabaseResult) Receive() (*chainjson.GetDatabaseInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a compactdatabase result object.
+	var info chainjson.GetDatabaseInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// CompactDatabaseAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See CompactDatabase for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CompactDatabaseAsync(ctx context.Context) FutureCompactDatabaseResult {
+	cmd := chainjson.NewCompactDatabaseCmd()
+	return c.sendCmd(ctx, cmd)
+}
+
+// CompactDatabase compacts the database of the server in order to reclaim
+// space that is no longer used by live data and returns details about the
+// storage used by it afterwards.  The call does not return until the
+// compaction completes, which can take a while for large databases.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) CompactDatabase(ctx context.Context) (*chainjson.GetDatabaseInfoResult, error) {
+	return c.CompactDatabaseAsync(ctx).Receive()
+}
+
 // FutureDebugLevelResult is a future promise to deliver the result of a
 // DebugLevelAsync RPC invocation (or an applicable error).
 type FutureDebugLevelResult chan *response
@@ -393,6 +481,87 @@ func (c *Client) ExistsMempoolTxs(ctx context.Context, hashes []*chainhash.Hash)
 	return c.ExistsMempoolTxsAsync(ctx, hashes).Receive()
 }
 
+// FutureForceVoteResult is a future promise to deliver the result of a
+// ForceVoteAsync RPC invocation (or an applicable error).
+type FutureForceVoteResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when performing the specified command.
+func (r FutureForceVoteResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// ForceVoteAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ForceVote for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ForceVoteAsync(ctx context.Context, agendaID, choiceID string) FutureForceVoteResult {
+	cmd := chainjson.NewForceVoteCmd(agendaID, choiceID)
+	return c.sendCmd(ctx, cmd)
+}
+
+// ForceVote forces the outcome of the vote on the passed agenda to the passed
+// choice regardless of the votes cast.  Forcing the abstain choice removes any
+// previously forced outcome.  It is only available on the simulation and
+// regression test networks.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ForceVote(ctx context.Context, agendaID, choiceID string) error {
+	return c.ForceVoteAsync(ctx, agendaID, choiceID).Receive()
+}
+
+// FutureGetAddressUTXOsResult is a future promise to deliver the result of a
+// GetAddressUTXOsAsync RPC invocation (or an applicable error).
+type FutureGetAddressUTXOsResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// unspent transaction outputs that pay to the requested address.
+func (r FutureGetAddressUTXOsResult) Receive() ([]chainjson.GetAddressUTXOsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of getaddressutxos result objects.
+	var utxos []chainjson.GetAddressUTXOsResult
+	err = json.Unmarshal(res, &utxos)
+	if err != nil {
+		return nil, err
+	}
+	return utxos, nil
+}
+
+// GetAddressUTXOsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetAddressUTXOs for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetAddressUTXOsAsync(ctx context.Context, address dcrutil.Address, skip, count int, includeMempool bool) FutureGetAddressUTXOsResult {
+	cmd := chainjson.NewGetAddressUTXOsCmd(address.Address(), &skip, &count,
+		&includeMempool)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetAddressUTXOs returns up to count unspent transaction outputs that pay to
+// the passed address in the order they were created after skipping the
+// provided number of them.  When includeMempool is set, the outputs created by
+// unconfirmed transactions are returned after the confirmed ones and outputs
+// spent by unconfirmed transactions are left out.
+//
+// The server must be running with the address index (--addrindex).
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetAddressUTXOs(ctx context.Context, address dcrutil.Address, skip, count int, includeMempool bool) ([]chainjson.GetAddressUTXOsResult, error) {
+	return c.GetAddressUTXOsAsync(ctx, address, skip, count,
+		includeMempool).Receive()
+}
+
 // FutureGetBestBlockResult is a future promise to deliver the result of a
 // GetBestBlockAsync RPC invocation (or an applicable error).
 type FutureGetBestBlockResult chan *response
@@ -441,6 +610,139 @@ func (c *Client) GetBestBlock(ctx context.Context) (*chainhash.Hash, int64, erro
 	return c.GetBestBlockAsync(ctx).Receive()
 }
 
+// FutureGetBlockByTimeResult is a future promise to deliver the result of a
+// GetBlockByTimeAsync RPC invocation (or an applicable error).
+type FutureGetBlockByTimeResult chan *response
+
+// Receive waits for the response promised by the future and returns the block
+// with the timestamp closest to the requested time.
+func (r FutureGetBlockByTimeResult) Receive() (*chainjson.GetBlockByTimeResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getblockbytime result object.
+	var block chainjson.GetBlockByTimeResult
+	err = json.Unmarshal(res, &block)
+	if err != nil {
+		return nil, err
+	}
+	return &block, nil
+}
+
+// GetBlockByTimeAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBlockByTime for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBlockByTimeAsync(ctx context.Context, t time.Time) FutureGetBlockByTimeResult {
+	cmd := chainjson.NewGetBlockByTimeCmd(t.Unix())
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetBlockByTime returns the hash, height, and timestamp of the block in the
+// main chain with the timestamp closest to the passed time.
+//
+// The server must be running with the block timestamp index (--timeindex).
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBlockByTime(ctx context.Context, t time.Time) (*chainjson.GetBlockByTimeResult, error) {
+	return c.GetBlockByTimeAsync(ctx, t).Receive()
+}
+
+// FutureGetBlocksByTimeResult is a future promise to deliver the result of a
+// GetBlocksByTimeAsync RPC invocation (or an applicable error).
+type FutureGetBlocksByTimeResult chan *response
+
+// Receive waits for the response promised by the future and returns the blocks
+// with timestamps within the requested range.
+func (r FutureGetBlocksByTimeResult) Receive() ([]chainjson.GetBlockByTimeResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of getblockbytime result objects.
+	var blocks []chainjson.GetBlockByTimeResult
+	err = json.Unmarshal(res, &blocks)
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// GetBlocksByTimeAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetBlocksByTime for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBlocksByTimeAsync(ctx context.Context, start, end time.Time, count int) FutureGetBlocksByTimeResult {
+	cmd := chainjson.NewGetBlocksByTimeCmd(start.Unix(), end.Unix(), &count)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetBlocksByTime returns up to count blocks in the main chain with timestamps
+// at or after the start time and before the end time ordered by their
+// timestamps.  Since block timestamps are not required to be strictly
+// increasing, the ordering does not necessarily match the order of the blocks
+// in the chain.  Callers may iterate a large range by issuing another request
+// that starts at the timestamp of the final returned block and skipping any
+// blocks that were already seen.
+//
+// The server must be running with the block timestamp index (--timeindex).
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBlocksByTime(ctx context.Context, start, end time.Time, count int) ([]chainjson.GetBlockByTimeResult, error) {
+	return c.GetBlocksByTimeAsync(ctx, start, end, count).Receive()
+}
+
+// FutureGetBuildInfoResult is a future promise to deliver the result of a
+// GetBuildInfoAsync RPC invocation (or an applicable error).
+type FutureGetBuildInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns details
+// about the build of the server and its enabled optional subsystems.
+func (r FutureGetBuildInfoResult) Receive() (*chainjson.GetBuildInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getbuildinfo result object.
+	var info chainjson.GetBuildInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetBuildInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetBuildInfo for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBuildInfoAsync(ctx context.Context) FutureGetBuildInfoResult {
+	cmd := chainjson.NewGetBuildInfoCmd()
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetBuildInfo returns details about the build of the server, such as its
+// version, source revision, and Go version, along with the optional subsystems
+// that are enabled.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetBuildInfo(ctx context.Context) (*chainjson.GetBuildInfoResult, error) {
+	return c.GetBuildInfoAsync(ctx).Receive()
+}
+
 // FutureGetCurrentNetResult is a future promise to deliver the result of a
 // GetCurrentNetAsync RPC invocation (or an applicable error).
 type FutureGetCurrentNetResult chan *response
@@ -482,6 +784,48 @@ func (c *Client) GetCurrentNet(ctx context.Context) (wire.CurrencyNet, error) {
 	return c.GetCurrentNetAsync(ctx).Receive()
 }
 
+// FutureGetDatabaseInfoResult is a future promise to deliver the result of a
+// GetDatabaseInfoAsync RPC invocation (or an applicable error).
+type FutureGetDatabaseInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns details
+// about the type of the database and the storage used by it.
+func (r FutureGetDatabaseInfoResult) Receive() (*chainjson.GetDatabaseInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getdatabaseinfo result object.
+	var info chainjson.GetDatabaseInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetDatabaseInfoAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function
+// on the returned instance.
+//
+// See GetDatabaseInfo for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetDatabaseInfoAsync(ctx context.Context) FutureGetDatabaseInfoResult {
+	cmd := chainjson.NewGetDatabaseInfoCmd()
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetDatabaseInfo returns details about the type of the database of the server
+// and the storage used by it such as the size of the live data and the amount
+// of space that can be reclaimed by compaction.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetDatabaseInfo(ctx context.Context) (*chainjson.GetDatabaseInfoResult, error) {
+	return c.GetDatabaseInfoAsync(ctx).Receive()
+}
+
 // FutureGetHeadersResult is a future promise to deliver the result of a
 // getheaders RPC invocation (or an applicable error).
 type FutureGetHeadersResult chan *response
@@ -530,6 +874,134 @@ func (c *Client) GetHeaders(ctx context.Context, blockLocators []*chainhash.Hash
 	return c.GetHeadersAsync(ctx, blockLocators, hashStop).Receive()
 }
 
+// FutureGetIndexInfoResult is a future promise to deliver the result of a
+// GetIndexInfoAsync RPC invocation (or an applicable error).
+type FutureGetIndexInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the status
+// of each enabled optional index.
+func (r FutureGetIndexInfoResult) Receive() ([]chainjson.GetIndexInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as an array of getindexinfo result objects.
+	var indexes []chainjson.GetIndexInfoResult
+	err = json.Unmarshal(res, &indexes)
+	if err != nil {
+		return nil, err
+	}
+	return indexes, nil
+}
+
+// GetIndexInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetIndexInfo for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetIndexInfoAsync(ctx context.Context) FutureGetIndexInfoResult {
+	cmd := chainjson.NewGetIndexInfoCmd()
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetIndexInfo returns the status of each enabled optional index including the
+// target height and estimated time remaining for any indexes that are being
+// caught up to the main chain.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetIndexInfo(ctx context.Context) ([]chainjson.GetIndexInfoResult, error) {
+	return c.GetIndexInfoAsync(ctx).Receive()
+}
+
+// FutureGetScriptStatsResult is a future promise to deliver the result of a
+// GetScriptStatsAsync RPC invocation (or an applicable error).
+type FutureGetScriptStatsResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// opcode and script class usage statistics for the requested range of blocks.
+func (r FutureGetScriptStatsResult) Receive() (*chainjson.GetScriptStatsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getscriptstats result object.
+	var stats chainjson.GetScriptStatsResult
+	err = json.Unmarshal(res, &stats)
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetScriptStatsAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetScriptStats for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetScriptStatsAsync(ctx context.Context, startHeight int64, endHeight *int64) FutureGetScriptStatsResult {
+	cmd := chainjson.NewGetScriptStatsCmd(startHeight, endHeight)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetScriptStats returns the number of times each opcode and public key script
+// class is used by the transactions in the main chain blocks from the start
+// height through the end height.  A nil end height uses the current best block.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetScriptStats(ctx context.Context, startHeight int64, endHeight *int64) (*chainjson.GetScriptStatsResult, error) {
+	return c.GetScriptStatsAsync(ctx, startHeight, endHeight).Receive()
+}
+
+// FutureGetSpentInfoResult is a future promise to deliver the result of a
+// GetSpentInfoAsync RPC invocation (or an applicable error).
+type FutureGetSpentInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// details of the transaction input that spent the requested output.
+func (r FutureGetSpentInfoResult) Receive() (*chainjson.GetSpentInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getspentinfo result object.
+	var spentInfo chainjson.GetSpentInfoResult
+	err = json.Unmarshal(res, &spentInfo)
+	if err != nil {
+		return nil, err
+	}
+	return &spentInfo, nil
+}
+
+// GetSpentInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetSpentInfo for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSpentInfoAsync(ctx context.Context, txHash *chainhash.Hash, index uint32) FutureGetSpentInfoResult {
+	cmd := chainjson.NewGetSpentInfoCmd(txHash.String(), index)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetSpentInfo returns the transaction input that spent the output identified
+// by the passed transaction hash and output index along with the block that
+// contains the spending transaction.
+//
+// The server must be running with the spend index (--spendindex).
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSpentInfo(ctx context.Context, txHash *chainhash.Hash, index uint32) (*chainjson.GetSpentInfoResult, error) {
+	return c.GetSpentInfoAsync(ctx, txHash, index).Receive()
+}
+
 // FutureGetStakeDifficultyResult is a future promise to deliver the result of a
 // GetStakeDifficultyAsync RPC invocation (or an applicable error).
 type FutureGetStakeDifficultyResult chan *response
@@ -653,6 +1125,91 @@ func (c *Client) GetStakeVersions(ctx context.Context, hash string, count int32)
 	return c.GetStakeVersionsAsync(ctx, hash, count).Receive()
 }
 
+// FutureGetSyncStatusResult is a future promise to deliver the result of a
+// GetSyncStatusAsync RPC invocation (or an applicable error).
+type FutureGetSyncStatusResult chan *response
+
+// Receive waits for the response promised by the future and returns details
+// about the progress of syncing the chain.
+func (r FutureGetSyncStatusResult) Receive() (*chainjson.GetSyncStatusResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getsyncstatus result object.
+	var status chainjson.GetSyncStatusResult
+	err = json.Unmarshal(res, &status)
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// GetSyncStatusAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetSyncStatus for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSyncStatusAsync(ctx context.Context) FutureGetSyncStatusResult {
+	cmd := chainjson.NewGetSyncStatusCmd()
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetSyncStatus returns details about the progress of syncing the chain with
+// the network such as the best block and header heights, an estimate of the
+// verification progress, the recent download rate, and the estimated time
+// remaining.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetSyncStatus(ctx context.Context) (*chainjson.GetSyncStatusResult, error) {
+	return c.GetSyncStatusAsync(ctx).Receive()
+}
+
+// FutureGetTicketPoolInfoResult is a future promise to deliver the result of a
+// GetTicketPoolInfoAsync RPC invocation (or an applicable error).
+type FutureGetTicketPoolInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the
+// composition of the live ticket pool.
+func (r FutureGetTicketPoolInfoResult) Receive() (*chainjson.GetTicketPoolInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getticketpoolinfo result object.
+	var gtpir chainjson.GetTicketPoolInfoResult
+	err = json.Unmarshal(res, &gtpir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gtpir, nil
+}
+
+// GetTicketPoolInfoAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See GetTicketPoolInfo for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetTicketPoolInfoAsync(ctx context.Context, buckets, windows *uint32) FutureGetTicketPoolInfoResult {
+	cmd := chainjson.NewGetTicketPoolInfoCmd(buckets, windows)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetTicketPoolInfo returns the composition of the live ticket pool by
+// purchase price, age, and projected expiration.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetTicketPoolInfo(ctx context.Context, buckets, windows *uint32) (*chainjson.GetTicketPoolInfoResult, error) {
+	return c.GetTicketPoolInfoAsync(ctx, buckets, windows).Receive()
+}
+
 // FutureGetTicketPoolValueResult is a future promise to deliver the result of a
 // GetTicketPoolValueAsync RPC invocation (or an applicable error).
 type FutureGetTicketPoolValueResult chan *response
@@ -700,6 +1257,48 @@ func (c *Client) GetTicketPoolValue(ctx context.Context) (dcrutil.Amount, error)
 	return c.GetTicketPoolValueAsync(ctx).Receive()
 }
 
+// FutureGetVoteStatsResult is a future promise to deliver the result of a
+// GetVoteStatsAsync RPC invocation (or an applicable error).
+type FutureGetVoteStatsResult chan *response
+
+// Receive waits for the response promised by the future and returns the vote
+// version and agenda choice statistics for the requested intervals.
+func (r FutureGetVoteStatsResult) Receive() (*chainjson.GetVoteStatsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getvotestats result object.
+	var gvsr chainjson.GetVoteStatsResult
+	err = json.Unmarshal(res, &gvsr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gvsr, nil
+}
+
+// GetVoteStatsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetVoteStats for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetVoteStatsAsync(ctx context.Context, count *int32) FutureGetVoteStatsResult {
+	cmd := chainjson.NewGetVoteStatsCmd(count)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetVoteStats returns vote version and agenda choice statistics for the
+// current and, optionally, prior stake version intervals.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetVoteStats(ctx context.Context, count *int32) (*chainjson.GetVoteStatsResult, error) {
+	return c.GetVoteStatsAsync(ctx, count).Receive()
+}
+
 // FutureGetVoteInfoResult is a future promise to deliver the result of a
 // GetVoteInfoAsync RPC invocation (or an applicable error).
 type FutureGetVoteInfoResult chan *response
@@ -789,6 +1388,26 @@ func (c *Client) LiveTickets(ctx context.Context) ([]*chainhash.Hash, error) {
 	return c.LiveTicketsAsync(ctx).Receive()
 }
 
+// LiveTicketsPageAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See LiveTicketsPage for the blocking version and more details.
+func (c *Client) LiveTicketsPageAsync(ctx context.Context, count int, after *chainhash.Hash) FutureLiveTicketsResult {
+	cmd := chainjson.NewLiveTicketsPageCmd(dcrjson.Int(count), pageCursor(after))
+	return c.sendCmd(ctx, cmd)
+}
+
+// LiveTicketsPage returns up to count currently live tickets in ascending order
+// of their hashes.  Only the tickets with hashes that sort after the provided
+// hash are returned, so the final hash of one page may be passed to request
+// the next page.  Passing nil requests the first page.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) LiveTicketsPage(ctx context.Context, count int, after *chainhash.Hash) ([]*chainhash.Hash, error) {
+	return c.LiveTicketsPageAsync(ctx, count, after).Receive()
+}
+
 // FutureMissedTicketsResult is a future promise to deliver the result
 // of a FutureMissedTicketsResultAsync RPC invocation (or an applicable error).
 type FutureMissedTicketsResult chan *response
@@ -836,6 +1455,90 @@ func (c *Client) MissedTickets(ctx context.Context) ([]*chainhash.Hash, error) {
 	return c.MissedTicketsAsync(ctx).Receive()
 }
 
+// MissedTicketsPageAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See MissedTicketsPage for the blocking version and more details.
+func (c *Client) MissedTicketsPageAsync(ctx context.Context, count int, after *chainhash.Hash) FutureMissedTicketsResult {
+	cmd := chainjson.NewMissedTicketsPageCmd(dcrjson.Int(count), pageCursor(after))
+	return c.sendCmd(ctx, cmd)
+}
+
+// MissedTicketsPage returns up to count currently missed tickets in ascending order
+// of their hashes.  Only the tickets with hashes that sort after the provided
+// hash are returned, so the final hash of one page may be passed to request
+// the next page.  Passing nil requests the first page.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) MissedTicketsPage(ctx context.Context, count int, after *chainhash.Hash) ([]*chainhash.Hash, error) {
+	return c.MissedTicketsPageAsync(ctx, count, after).Receive()
+}
+
+// FutureProfileServerResult is a future promise to deliver the result of a
+// ProfileServerAsync RPC invocation (or an applicable error).
+type FutureProfileServerResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when performing the specified command.
+func (r FutureProfileServerResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// ProfileServerAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See ProfileServer for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ProfileServerAsync(ctx context.Context, subCmd chainjson.ProfileServerSubCmd, listen *string) FutureProfileServerResult {
+	cmd := chainjson.NewProfileServerCmd(subCmd, listen)
+	return c.sendCmd(ctx, cmd)
+}
+
+// ProfileServer starts or stops the HTTP profiling server of the server
+// depending on the passed sub command.  The listen address is only used when
+// starting the profiling server and the server default is used when it is nil.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) ProfileServer(ctx context.Context, subCmd chainjson.ProfileServerSubCmd, listen *string) error {
+	return c.ProfileServerAsync(ctx, subCmd, listen).Receive()
+}
+
+// FutureSetPolicyResult is a future promise to deliver the result of a
+// SetPolicyAsync RPC invocation (or an applicable error).
+type FutureSetPolicyResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when performing the specified command.
+func (r FutureSetPolicyResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// SetPolicyAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See SetPolicy for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) SetPolicyAsync(ctx context.Context, setting, value string) FutureSetPolicyResult {
+	cmd := chainjson.NewSetPolicyCmd(setting, value)
+	return c.sendCmd(ctx, cmd)
+}
+
+// SetPolicy changes the passed relay or connection policy setting of the
+// server to the passed value without restarting it.  The supported settings
+// are minrelaytxfee, maxorphantx, maxpeers, and banduration.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) SetPolicy(ctx context.Context, setting, value string) error {
+	return c.SetPolicyAsync(ctx, setting, value).Receive()
+}
+
 // FutureSessionResult is a future promise to deliver the result of a
 // SessionAsync RPC invocation (or an applicable error).
 type FutureSessionResult chan *response
@@ -1077,3 +1780,47 @@ func (c *Client) VersionAsync(ctx context.Context) FutureVersionResult {
 func (c *Client) Version(ctx context.Context) (map[string]chainjson.VersionResult, error) {
 	return c.VersionAsync(ctx).Receive()
 }
+
+// FutureWriteProfileResult is a future promise to deliver the result of a
+// WriteProfileAsync RPC invocation (or an applicable error).
+type FutureWriteProfileResult chan *response
+
+// Receive waits for the response promised by the future and returns the full
+// path of the profile written by the server.
+func (r FutureWriteProfileResult) Receive() (string, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return "", err
+	}
+
+	// Unmarshal the result as a string.
+	var path string
+	err = json.Unmarshal(res, &path)
+	if err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// WriteProfileAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See WriteProfile for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) WriteProfileAsync(ctx context.Context, profile, file string, seconds *uint32) FutureWriteProfileResult {
+	cmd := chainjson.NewWriteProfileCmd(profile, file, seconds)
+	return c.sendCmd(ctx, cmd)
+}
+
+// WriteProfile requests the server to capture the named profile, such as cpu,
+// heap, or goroutine, and write it to the passed file name within its profiles
+// directory.  The seconds parameter is only used for CPU profiles and the
+// server default is used when it is nil.  The full path of the written profile
+// is returned.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) WriteProfile(ctx context.Context, profile, file string, seconds *uint32) (string, error) {
+	return c.WriteProfileAsync(ctx, profile, file, seconds).Receive()
+}
@@ -12,6 +12,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -109,6 +110,24 @@ type jsonRequest struct {
 	cmd            interface{}
 	marshalledJSON []byte
 	responseChan   chan *response
+
+	// respondOnce and done ensure only a single response is delivered for
+	// the request since it may be canceled via its context concurrently
+	// with the response from the server.
+	respondOnce sync.Once
+	done        chan struct{}
+}
+
+// respond delivers the provided response to the response channel of the
+// request unless a response was already delivered, such as when the request
+// was canceled via its context before the server replied.
+//
+// This function is safe for concurrent access.
+func (jReq *jsonRequest) respond(r *response) {
+	jReq.respondOnce.Do(func() {
+		jReq.responseChan <- r
+		close(jReq.done)
+	})
 }
 
 // Client represents a Decred RPC client which allows easy access to the
@@ -275,11 +294,31 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 	case *chainjson.NotifyNewTicketsCmd:
 		c.ntfnState.notifyNewTickets = true
 
+	case *chainjson.NotifyTicketLifecycleCmd:
+		c.ntfnState.notifyTicketLifecycle = true
+
 	case *chainjson.NotifyStakeDifficultyCmd:
 		c.ntfnState.notifyStakeDifficulty = true
 
+	case *chainjson.NotifyWorkCmd:
+		c.ntfnState.notifyWork = true
+		c.ntfnState.notifyWorkDetails = (bcmd.Verbose != nil &&
+			*bcmd.Verbose) || bcmd.ShareTarget != nil
+		c.ntfnState.notifyWorkShareTarget = nil
+		if bcmd.ShareTarget != nil {
+			shareTarget, err := hex.DecodeString(*bcmd.ShareTarget)
+			if err == nil {
+				c.ntfnState.notifyWorkShareTarget = shareTarget
+			}
+		}
+
 	case *chainjson.NotifyBlocksCmd:
 		c.ntfnState.notifyBlocks = true
+		c.ntfnState.notifyBlocksPayload = chainjson.NBPHeader
+		if bcmd.Payload != nil {
+			c.ntfnState.notifyBlocksPayload =
+				chainjson.NotifyBlocksPayload(*bcmd.Payload)
+		}
 
 	case *chainjson.NotifyNewTransactionsCmd:
 		if bcmd.Verbose != nil && *bcmd.Verbose {
@@ -287,6 +326,40 @@ func (c *Client) trackRegisteredNtfns(cmd interface{}) {
 		} else {
 			c.ntfnState.notifyNewTx = true
 		}
+		c.ntfnState.notifyNewTxFilter = bcmd.Filter
+
+	case *chainjson.LoadTxFilterCmd:
+		if bcmd.Reload || c.ntfnState.txFilterAddrs == nil {
+			c.ntfnState.txFilterAddrs = make(map[string]struct{})
+			c.ntfnState.txFilterOutPoints = make(map[chainjson.OutPoint]struct{})
+		}
+		for _, addr := range bcmd.Addresses {
+			c.ntfnState.txFilterAddrs[addr] = struct{}{}
+		}
+		for _, op := range bcmd.OutPoints {
+			c.ntfnState.txFilterOutPoints[op] = struct{}{}
+		}
+
+	case *chainjson.NotifyUTXOsCmd:
+		if c.ntfnState.utxoAddrs == nil {
+			c.ntfnState.utxoAddrs = make(map[string]struct{})
+			c.ntfnState.utxoScripts = make(map[string]struct{})
+			c.ntfnState.utxoOutPoints = make(map[chainjson.OutPoint]struct{})
+		}
+		for _, addr := range bcmd.Addresses {
+			c.ntfnState.utxoAddrs[addr] = struct{}{}
+		}
+		for _, script := range bcmd.Scripts {
+			c.ntfnState.utxoScripts[script] = struct{}{}
+		}
+		for _, op := range bcmd.OutPoints {
+			c.ntfnState.utxoOutPoints[op] = struct{}{}
+		}
+
+	case *chainjson.StopNotifyUTXOsCmd:
+		c.ntfnState.utxoAddrs = nil
+		c.ntfnState.utxoScripts = nil
+		c.ntfnState.utxoOutPoints = nil
 	}
 }
 
@@ -406,7 +479,7 @@ func (c *Client) handleMessage(msg []byte) {
 
 	// Deliver the response.
 	result, err := in.rawResponse.result()
-	request.responseChan <- &response{result: result, err: err}
+	request.respond(&response{result: result, err: err})
 }
 
 // shouldLogReadError returns whether or not the passed error, which is expected
@@ -544,8 +617,10 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 
 	// Reregister notifyblocks if needed.
 	if stateCopy.notifyBlocks {
-		log.Debugf("Reregistering [notifyblocks]")
-		if err := c.NotifyBlocks(ctx); err != nil {
+		log.Debugf("Reregistering [notifyblocks] (payload=%v)",
+			stateCopy.notifyBlocksPayload)
+		err := c.NotifyBlocksWithPayload(ctx, stateCopy.notifyBlocksPayload)
+		if err != nil {
 			return err
 		}
 	}
@@ -553,7 +628,14 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 	// Reregister notifywork if needed.
 	if stateCopy.notifyWork {
 		log.Debugf("Reregistering [notifywork]")
-		if err := c.NotifyWork(ctx); err != nil {
+		var err error
+		if stateCopy.notifyWorkDetails {
+			err = c.NotifyWorkWithDetails(ctx,
+				stateCopy.notifyWorkShareTarget)
+		} else {
+			err = c.NotifyWork(ctx)
+		}
+		if err != nil {
 			return err
 		}
 	}
@@ -582,6 +664,14 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 		}
 	}
 
+	// Reregister notifyticketlifecycle if needed.
+	if stateCopy.notifyTicketLifecycle {
+		log.Debugf("Reregistering [notifyticketlifecycle]")
+		if err := c.NotifyTicketLifecycle(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Reregister notifystakedifficulty if needed.
 	if stateCopy.notifyStakeDifficulty {
 		log.Debugf("Reregistering [notifystakedifficulty]")
@@ -594,7 +684,52 @@ func (c *Client) reregisterNtfns(ctx context.Context) error {
 	if stateCopy.notifyNewTx || stateCopy.notifyNewTxVerbose {
 		log.Debugf("Reregistering [notifynewtransactions] (verbose=%v)",
 			stateCopy.notifyNewTxVerbose)
-		err := c.NotifyNewTransactions(ctx, stateCopy.notifyNewTxVerbose)
+		err := c.NotifyFilteredNewTransactions(ctx,
+			stateCopy.notifyNewTxVerbose, stateCopy.notifyNewTxFilter)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Reload the transaction filter if needed.
+	if stateCopy.txFilterAddrs != nil {
+		addrs := make([]string, 0, len(stateCopy.txFilterAddrs))
+		for addr := range stateCopy.txFilterAddrs {
+			addrs = append(addrs, addr)
+		}
+		outPoints := make([]chainjson.OutPoint, 0,
+			len(stateCopy.txFilterOutPoints))
+		for op := range stateCopy.txFilterOutPoints {
+			outPoints = append(outPoints, op)
+		}
+		log.Debugf("Reloading [loadtxfilter] (addresses=%d, outpoints=%d)",
+			len(addrs), len(outPoints))
+		cmd := chainjson.NewLoadTxFilterCmd(true, addrs, outPoints)
+		err := FutureLoadTxFilterResult(c.sendCmd(ctx, cmd)).Receive()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Reregister notifyutxos if needed.
+	if stateCopy.utxoAddrs != nil {
+		addrs := make([]string, 0, len(stateCopy.utxoAddrs))
+		for addr := range stateCopy.utxoAddrs {
+			addrs = append(addrs, addr)
+		}
+		scripts := make([]string, 0, len(stateCopy.utxoScripts))
+		for script := range stateCopy.utxoScripts {
+			scripts = append(scripts, script)
+		}
+		outPoints := make([]chainjson.OutPoint, 0,
+			len(stateCopy.utxoOutPoints))
+		for op := range stateCopy.utxoOutPoints {
+			outPoints = append(outPoints, op)
+		}
+		log.Debugf("Reregistering [notifyutxos] (addresses=%d, scripts=%d, "+
+			"outpoints=%d)", len(addrs), len(scripts), len(outPoints))
+		cmd := chainjson.NewNotifyUTXOsCmd(addrs, scripts, outPoints)
+		err := FutureNotifyUTXOsResult(c.sendCmd(ctx, cmd)).Receive()
 		if err != nil {
 			return err
 		}
@@ -610,9 +745,11 @@ var ignoreResends = map[string]struct{}{
 }
 
 // resendRequests resends any requests that had not completed when the client
-// disconnected.  It is intended to be called once the client has reconnected as
-// a separate goroutine.
-func (c *Client) resendRequests(ctx context.Context) {
+// disconnected and notifies the caller of the reconnect once the notification
+// state has been re-established.  The passed duration is how long the client
+// was disconnected.  It is intended to be called once the client has
+// reconnected as a separate goroutine.
+func (c *Client) resendRequests(ctx context.Context, disconnectedFor time.Duration) {
 	// Set the notification state back up.  If anything goes wrong,
 	// disconnect the client.
 	if err := c.reregisterNtfns(ctx); err != nil {
@@ -621,6 +758,13 @@ func (c *Client) resendRequests(ctx context.Context) {
 		return
 	}
 
+	// Notify the caller the connection and all of its notifications have
+	// been re-established so it is able to detect and fill any gaps in the
+	// notifications it received while disconnected.
+	if c.ntfnHandlers != nil && c.ntfnHandlers.OnReconnected != nil {
+		c.ntfnHandlers.OnReconnected(disconnectedFor)
+	}
+
 	// Since it's possible to block on send and more requests might be
 	// added by the caller while resending, make a copy of all of the
 	// requests that need to be resent now and work from the copy.  This
@@ -668,10 +812,12 @@ func (c *Client) resendRequests(ctx context.Context) {
 func (c *Client) wsReconnectHandler(ctx context.Context) {
 out:
 	for {
+		var disconnectTime time.Time
 		select {
 		case <-c.disconnectChan():
 			// On disconnect, fallthrough to reestablish the
 			// connection.
+			disconnectTime = time.Now()
 
 		case <-c.shutdown:
 			break out
@@ -724,7 +870,7 @@ out:
 
 			// Reissue pending requests in another goroutine since
 			// the send can block.
-			go c.resendRequests(ctx)
+			go c.resendRequests(ctx, time.Since(disconnectTime))
 
 			// Break out of the reconnect loop back to wait for
 			// disconnect again.
@@ -743,7 +889,7 @@ func (c *Client) handleSendPostMessage(details *sendPostDetails) {
 	log.Tracef("Sending command [%s] with id %d", jReq.method, jReq.id)
 	httpResponse, err := c.httpClient.Do(details.httpRequest)
 	if err != nil {
-		jReq.responseChan <- &response{err: err}
+		jReq.respond(&response{err: err})
 		return
 	}
 
@@ -752,7 +898,7 @@ func (c *Client) handleSendPostMessage(details *sendPostDetails) {
 	httpResponse.Body.Close()
 	if err != nil {
 		err = fmt.Errorf("error reading json reply: %v", err)
-		jReq.responseChan <- &response{err: err}
+		jReq.respond(&response{err: err})
 		return
 	}
 
@@ -765,12 +911,12 @@ func (c *Client) handleSendPostMessage(details *sendPostDetails) {
 		// response bytes.
 		err = fmt.Errorf("status code: %d, response: %q",
 			httpResponse.StatusCode, string(respBytes))
-		jReq.responseChan <- &response{err: err}
+		jReq.respond(&response{err: err})
 		return
 	}
 
 	res, err := resp.result()
-	jReq.responseChan <- &response{result: res, err: err}
+	jReq.respond(&response{result: res, err: err})
 }
 
 // sendPostHandler handles all outgoing messages when the client is running
@@ -797,10 +943,10 @@ cleanup:
 	for {
 		select {
 		case details := <-c.sendPostChan:
-			details.jsonRequest.responseChan <- &response{
+			details.jsonRequest.respond(&response{
 				result: nil,
 				err:    ErrClientShutdown,
-			}
+			})
 
 		default:
 			break cleanup
@@ -818,13 +964,19 @@ func (c *Client) sendPostRequest(httpReq *http.Request, jReq *jsonRequest) {
 	// Don't send the message if shutting down.
 	select {
 	case <-c.shutdown:
-		jReq.responseChan <- &response{result: nil, err: ErrClientShutdown}
+		jReq.respond(&response{result: nil, err: ErrClientShutdown})
+		return
 	default:
 	}
 
-	c.sendPostChan <- &sendPostDetails{
+	// Don't block waiting for space in the send channel once the request
+	// is canceled since the error has already been delivered.
+	select {
+	case c.sendPostChan <- &sendPostDetails{
 		jsonRequest: jReq,
 		httpRequest: httpReq,
+	}:
+	case <-httpReq.Context().Done():
 	}
 }
 
@@ -862,7 +1014,7 @@ func (c *Client) sendPost(ctx context.Context, jReq *jsonRequest) {
 	bodyReader := bytes.NewReader(jReq.marshalledJSON)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bodyReader)
 	if err != nil {
-		jReq.responseChan <- &response{result: nil, err: err}
+		jReq.respond(&response{result: nil, err: err})
 		return
 	}
 	httpReq.Close = true
@@ -875,10 +1027,42 @@ func (c *Client) sendPost(ctx context.Context, jReq *jsonRequest) {
 	c.sendPostRequest(httpReq, jReq)
 }
 
+// cancelOnDone delivers the error of the provided context as the response to
+// the passed request should the context be canceled or its deadline exceeded
+// before the request receives a response.  Requests sent via the websocket
+// connection are also removed from the internal tracking structures so they
+// are not reissued on reconnect and any late reply from the server is ignored.
+//
+// This allows callers to enforce deadlines on requests without having to wait
+// on a stalled server.
+func (c *Client) cancelOnDone(ctx context.Context, jReq *jsonRequest) {
+	// Nothing to do when the context can never be canceled.
+	if ctx.Done() == nil {
+		return
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			if !c.config.HTTPPostMode {
+				c.removeRequest(jReq.id)
+			}
+			jReq.respond(&response{err: ctx.Err()})
+
+		case <-jReq.done:
+		}
+	}()
+}
+
 // sendRequest sends the passed json request to the associated server using the
 // provided response channel for the reply.  It handles both websocket and HTTP
 // POST mode depending on the configuration of the client.
+//
+// The request is canceled when the provided context is done before the server
+// replies, in which case the error of the context is delivered as the response.
 func (c *Client) sendRequest(ctx context.Context, jReq *jsonRequest) {
+	c.cancelOnDone(ctx, jReq)
+
 	// Choose which marshal and send function to use depending on whether
 	// the client running in HTTP POST mode or not.  When running in HTTP
 	// POST mode, the command is issued via an HTTP client.  Otherwise,
@@ -893,7 +1077,7 @@ func (c *Client) sendRequest(ctx context.Context, jReq *jsonRequest) {
 	select {
 	case <-c.connEstablished:
 	default:
-		jReq.responseChan <- &response{err: ErrClientNotConnected}
+		jReq.respond(&response{err: ErrClientNotConnected})
 		return
 	}
 
@@ -902,7 +1086,7 @@ func (c *Client) sendRequest(ctx context.Context, jReq *jsonRequest) {
 	// channel.  Then send the marshalled request via the websocket
 	// connection.
 	if err := c.addRequest(jReq); err != nil {
-		jReq.responseChan <- &response{err: err}
+		jReq.respond(&response{err: err})
 		return
 	}
 	log.Tracef("Sending command [%s] with id %d", jReq.method, jReq.id)
@@ -935,6 +1119,7 @@ func (c *Client) sendCmd(ctx context.Context, cmd interface{}) chan *response {
 		cmd:            cmd,
 		marshalledJSON: marshalledJSON,
 		responseChan:   responseChan,
+		done:           make(chan struct{}),
 	}
 	c.sendRequest(ctx, jReq)
 
@@ -1028,10 +1213,10 @@ func (c *Client) Disconnect() {
 	if c.config.DisableAutoReconnect {
 		for e := c.requestList.Front(); e != nil; e = e.Next() {
 			req := e.Value.(*jsonRequest)
-			req.responseChan <- &response{
+			req.respond(&response{
 				result: nil,
 				err:    ErrClientDisconnect,
-			}
+			})
 		}
 		c.removeAllRequests()
 		c.doShutdown()
@@ -1056,10 +1241,10 @@ func (c *Client) Shutdown() {
 	// Send the ErrClientShutdown error to any pending requests.
 	for e := c.requestList.Front(); e != nil; e = e.Next() {
 		req := e.Value.(*jsonRequest)
-		req.responseChan <- &response{
+		req.respond(&response{
 			result: nil,
 			err:    ErrClientShutdown,
-		}
+		})
 	}
 	c.removeAllRequests()
 
@@ -1125,6 +1310,15 @@ type ConnConfig struct {
 	// is true.
 	Certificates []byte
 
+	// ClientCertificate and ClientKey are the bytes for a PEM-encoded
+	// certificate and private key the client presents to the RPC server to
+	// authenticate with a TLS client certificate instead of or in addition
+	// to the User and Pass parameters.  Both must be set to present a
+	// client certificate.  They have no effect if the DisableTLS parameter
+	// is true.
+	ClientCertificate []byte
+	ClientKey         []byte
+
 	// Proxy specifies to connect through a SOCKS 5 proxy server.  It may
 	// be an empty string if a proxy is not required.
 	Proxy string
@@ -1158,6 +1352,32 @@ type ConnConfig struct {
 	HTTPPostMode bool
 }
 
+// newTLSConfig returns the TLS configuration for connections made with the
+// provided connection configuration.  It returns nil when TLS is disabled.
+func newTLSConfig(config *ConnConfig) (*tls.Config, error) {
+	if config.DisableTLS {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if len(config.Certificates) > 0 {
+		pool := x509.NewCertPool()
+		pool.AppendCertsFromPEM(config.Certificates)
+		tlsConfig.RootCAs = pool
+	}
+	if len(config.ClientCertificate) > 0 && len(config.ClientKey) > 0 {
+		keypair, err := tls.X509KeyPair(config.ClientCertificate,
+			config.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{keypair}
+	}
+	return tlsConfig, nil
+}
+
 // newHTTPClient returns a new http client that is configured according to the
 // proxy and TLS settings in the associated connection configuration.
 func newHTTPClient(config *ConnConfig) (*http.Client, error) {
@@ -1172,15 +1392,9 @@ func newHTTPClient(config *ConnConfig) (*http.Client, error) {
 	}
 
 	// Configure TLS if needed.
-	var tlsConfig *tls.Config
-	if !config.DisableTLS {
-		if len(config.Certificates) > 0 {
-			pool := x509.NewCertPool()
-			pool.AppendCertsFromPEM(config.Certificates)
-			tlsConfig = &tls.Config{
-				RootCAs: pool,
-			}
-		}
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
 	}
 
 	client := http.Client{
@@ -1197,17 +1411,12 @@ func newHTTPClient(config *ConnConfig) (*http.Client, error) {
 // details.
 func dial(config *ConnConfig) (*websocket.Conn, error) {
 	// Setup TLS if not disabled.
-	var tlsConfig *tls.Config
+	tlsConfig, err := newTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
 	var scheme = "ws"
 	if !config.DisableTLS {
-		tlsConfig = &tls.Config{
-			MinVersion: tls.VersionTLS12,
-		}
-		if len(config.Certificates) > 0 {
-			pool := x509.NewCertPool()
-			pool.AppendCertsFromPEM(config.Certificates)
-			tlsConfig.RootCAs = pool
-		}
 		scheme = "wss"
 	}
 
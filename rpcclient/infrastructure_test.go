@@ -4,7 +4,17 @@
 
 package rpcclient
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
 
 func TestClientStringer(t *testing.T) {
 	type test struct {
@@ -36,3 +46,124 @@ func TestClientStringer(t *testing.T) {
 		}
 	}
 }
+
+// TestTrackTxFilter ensures loading transaction filters updates the tracked
+// notification state used to reload the filter on reconnect as expected.
+func TestTrackTxFilter(t *testing.T) {
+	c, err := New(&ConnConfig{DisableConnectOnNew: true},
+		&NotificationHandlers{})
+	if err != nil {
+		t.Fatalf("rpcclient.New: %v", err)
+	}
+
+	op1 := chainjson.OutPoint{Hash: "00", Index: 1}
+	op2 := chainjson.OutPoint{Hash: "01", Tree: 1}
+	c.trackRegisteredNtfns(chainjson.NewLoadTxFilterCmd(false,
+		[]string{"addr1"}, []chainjson.OutPoint{op1}))
+	c.trackRegisteredNtfns(chainjson.NewLoadTxFilterCmd(false,
+		[]string{"addr2"}, nil))
+	state := c.ntfnState.Copy()
+	if len(state.txFilterAddrs) != 2 || len(state.txFilterOutPoints) != 1 {
+		t.Fatalf("unexpected filter state after adding -- got %d addrs "+
+			"and %d outpoints, want 2 and 1", len(state.txFilterAddrs),
+			len(state.txFilterOutPoints))
+	}
+
+	// Ensure reloading the filter replaces the existing state and that the
+	// previous copy is not modified.
+	c.trackRegisteredNtfns(chainjson.NewLoadTxFilterCmd(true,
+		[]string{"addr3"}, []chainjson.OutPoint{op2}))
+	if _, ok := c.ntfnState.txFilterAddrs["addr3"]; !ok ||
+		len(c.ntfnState.txFilterAddrs) != 1 {
+		t.Fatalf("unexpected filter addrs after reload: %v",
+			c.ntfnState.txFilterAddrs)
+	}
+	if _, ok := c.ntfnState.txFilterOutPoints[op2]; !ok ||
+		len(c.ntfnState.txFilterOutPoints) != 1 {
+		t.Fatalf("unexpected filter outpoints after reload: %v",
+			c.ntfnState.txFilterOutPoints)
+	}
+	if _, ok := state.txFilterAddrs["addr1"]; !ok || len(state.txFilterAddrs) != 2 {
+		t.Fatalf("copied filter state was modified: %v", state.txFilterAddrs)
+	}
+}
+
+// TestTrackNotifyUTXOs ensures registering for UTXO notifications updates the
+// tracked notification state used to reregister on reconnect as expected.
+func TestTrackNotifyUTXOs(t *testing.T) {
+	c, err := New(&ConnConfig{DisableConnectOnNew: true},
+		&NotificationHandlers{})
+	if err != nil {
+		t.Fatalf("rpcclient.New: %v", err)
+	}
+
+	op := chainjson.OutPoint{Hash: "00", Index: 1}
+	c.trackRegisteredNtfns(chainjson.NewNotifyUTXOsCmd([]string{"addr1"},
+		nil, []chainjson.OutPoint{op}))
+	c.trackRegisteredNtfns(chainjson.NewNotifyUTXOsCmd(nil,
+		[]string{"76a9"}, nil))
+	state := c.ntfnState.Copy()
+	if len(state.utxoAddrs) != 1 || len(state.utxoScripts) != 1 ||
+		len(state.utxoOutPoints) != 1 {
+
+		t.Fatalf("unexpected utxo state after adding -- got %d addrs, %d "+
+			"scripts, and %d outpoints, want 1, 1, and 1",
+			len(state.utxoAddrs), len(state.utxoScripts),
+			len(state.utxoOutPoints))
+	}
+
+	// Ensure stopping the notifications clears the state and that the
+	// previous copy is not modified.
+	c.trackRegisteredNtfns(chainjson.NewStopNotifyUTXOsCmd())
+	if c.ntfnState.utxoAddrs != nil || c.ntfnState.utxoScripts != nil ||
+		c.ntfnState.utxoOutPoints != nil {
+
+		t.Fatal("utxo state was not cleared after stopping notifications")
+	}
+	if _, ok := state.utxoOutPoints[op]; !ok {
+		t.Fatalf("copied utxo state was modified: %v", state.utxoOutPoints)
+	}
+}
+
+// TestRequestCancellation ensures requests that are canceled via their context
+// return the context error without waiting on a stalled server.
+func TestRequestCancellation(t *testing.T) {
+	stall := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-stall:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(stall)
+
+	c, err := New(&ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}, nil)
+	if err != nil {
+		t.Fatalf("rpcclient.New: %v", err)
+	}
+	defer c.Shutdown()
+
+	// Issue a request that will never complete on its own followed by one
+	// with a deadline that is queued behind it.
+	stalled := c.GetBlockCountAsync(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(),
+		50*time.Millisecond)
+	defer cancel()
+	_, err = c.GetBlockCount(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			context.DeadlineExceeded)
+	}
+
+	// Ensure the stalled request is not affected by the canceled one.
+	select {
+	case <-stalled:
+		t.Fatal("stalled request unexpectedly completed")
+	default:
+	}
+}
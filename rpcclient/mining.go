@@ -63,6 +63,23 @@ func (c *Client) Generate(ctx context.Context, numBlocks uint32) ([]*chainhash.H
 	return c.GenerateAsync(ctx, numBlocks).Receive()
 }
 
+// GenerateToAddressAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GenerateToAddress for the blocking version and more details.
+func (c *Client) GenerateToAddressAsync(ctx context.Context, numBlocks uint32, address dcrutil.Address) FutureGenerateResult {
+	cmd := chainjson.NewGenerateToAddressCmd(numBlocks, address.Address())
+	return c.sendCmd(ctx, cmd)
+}
+
+// GenerateToAddress generates numBlocks blocks that pay to the passed address
+// and returns their hashes.  It is only available on the simulation and
+// regression test networks.
+func (c *Client) GenerateToAddress(ctx context.Context, numBlocks uint32, address dcrutil.Address) ([]*chainhash.Hash, error) {
+	return c.GenerateToAddressAsync(ctx, numBlocks, address).Receive()
+}
+
 // FutureGetGenerateResult is a future promise to deliver the result of a
 // GetGenerateAsync RPC invocation (or an applicable error).
 type FutureGetGenerateResult chan *response
@@ -126,6 +143,32 @@ func (c *Client) SetGenerate(ctx context.Context, enable bool, numCPUs int) erro
 	return c.SetGenerateAsync(ctx, enable, numCPUs).Receive()
 }
 
+// FuturePauseGenerateResult is a future promise to deliver the result of a
+// PauseGenerateAsync RPC invocation (or an applicable error).
+type FuturePauseGenerateResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when pausing or resuming coin generation.
+func (r FuturePauseGenerateResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// PauseGenerateAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See PauseGenerate for the blocking version and more details.
+func (c *Client) PauseGenerateAsync(ctx context.Context, pause bool) FuturePauseGenerateResult {
+	cmd := chainjson.NewPauseGenerateCmd(pause)
+	return c.sendCmd(ctx, cmd)
+}
+
+// PauseGenerate pauses or resumes coin generation (mining) by the server.
+func (c *Client) PauseGenerate(ctx context.Context, pause bool) error {
+	return c.PauseGenerateAsync(ctx, pause).Receive()
+}
+
 // FutureGetHashesPerSecResult is a future promise to deliver the result of a
 // GetHashesPerSecAsync RPC invocation (or an applicable error).
 type FutureGetHashesPerSecResult chan *response
@@ -416,6 +459,58 @@ func (c *Client) SubmitBlock(ctx context.Context, block *dcrutil.Block, options
 	return c.SubmitBlockAsync(ctx, block, options).Receive()
 }
 
+// FutureProposeBlockResult is a future promise to deliver the result of a
+// ProposeBlockAsync RPC invocation (or an applicable error).
+type FutureProposeBlockResult chan *response
+
+// Receive waits for the response promised by the future and returns the reason
+// the block proposal was rejected or an empty string when it was accepted.
+func (r FutureProposeBlockResult) Receive() (string, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return "", err
+	}
+
+	if string(res) == "null" {
+		return "", nil
+	}
+
+	var reason string
+	err = json.Unmarshal(res, &reason)
+	if err != nil {
+		return "", err
+	}
+	return reason, nil
+}
+
+// ProposeBlockAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See ProposeBlock for the blocking version and more details.
+func (c *Client) ProposeBlockAsync(ctx context.Context, block *dcrutil.Block) FutureProposeBlockResult {
+	blockBytes, err := block.Bytes()
+	if err != nil {
+		return newFutureError(err)
+	}
+
+	request := &chainjson.TemplateRequest{
+		Mode: "proposal",
+		Data: hex.EncodeToString(blockBytes),
+	}
+	cmd := chainjson.NewGetBlockTemplateCmd(request)
+	return c.sendCmd(ctx, cmd)
+}
+
+// ProposeBlock fully validates the provided block as if it were connected to
+// the main chain without broadcasting it by issuing a getblocktemplate request
+// in proposal mode.  The proof of work is not checked, so the block may be
+// unsolved.  It returns the reason the block was rejected or an empty string
+// when it was accepted.
+func (c *Client) ProposeBlock(ctx context.Context, block *dcrutil.Block) (string, error) {
+	return c.ProposeBlockAsync(ctx, block).Receive()
+}
+
 // FutureRegenTemplateResult is a future promise to deliver the result of a
 // RegenTemplate RPC invocation (or an applicable error).
 type FutureRegenTemplateResult chan *response
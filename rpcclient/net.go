@@ -65,6 +65,81 @@ func (c *Client) AddNode(ctx context.Context, host string, command AddNodeComman
 	return c.AddNodeAsync(ctx, host, command).Receive()
 }
 
+// FutureDeleteAddressResult is a future promise to deliver the result of a
+// DeleteAddressAsync RPC invocation (or an applicable error).
+type FutureDeleteAddressResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if
+// any occurred when removing the address.
+func (r FutureDeleteAddressResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// DeleteAddressAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See DeleteAddress for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) DeleteAddressAsync(ctx context.Context, addr string) FutureDeleteAddressResult {
+	cmd := chainjson.NewDeleteAddressCmd(addr)
+	return c.sendCmd(ctx, cmd)
+}
+
+// DeleteAddress removes the passed known peer address from the address manager
+// of the server so it is no longer selected for connections or relayed to
+// other peers.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) DeleteAddress(ctx context.Context, addr string) error {
+	return c.DeleteAddressAsync(ctx, addr).Receive()
+}
+
+// FutureGetAddrManInfoResult is a future promise to deliver the result of a
+// GetAddrManInfoAsync RPC invocation (or an applicable error).
+type FutureGetAddrManInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns
+// information about the known peer addresses and the buckets of the address
+// manager.
+func (r FutureGetAddrManInfoResult) Receive() (*chainjson.GetAddrManInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getaddrmaninfo result object.
+	var info chainjson.GetAddrManInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// GetAddrManInfoAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on the
+// returned instance.
+//
+// See GetAddrManInfo for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetAddrManInfoAsync(ctx context.Context, verbose bool) FutureGetAddrManInfoResult {
+	cmd := chainjson.NewGetAddrManInfoCmd(&verbose)
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetAddrManInfo returns information about the known peer addresses and the
+// occupancy of the buckets of the address manager of the server.  Details
+// about every known address are included when verbose is set.
+//
+// NOTE: This is a dcrd extension.
+func (c *Client) GetAddrManInfo(ctx context.Context, verbose bool) (*chainjson.GetAddrManInfoResult, error) {
+	return c.GetAddrManInfoAsync(ctx, verbose).Receive()
+}
+
 // FutureGetAddedNodeInfoResult is a future promise to deliver the result of a
 // GetAddedNodeInfoAsync RPC invocation (or an applicable error).
 type FutureGetAddedNodeInfoResult chan *response
@@ -285,3 +360,41 @@ func (c *Client) GetNetTotalsAsync(ctx context.Context) FutureGetNetTotalsResult
 func (c *Client) GetNetTotals(ctx context.Context) (*chainjson.GetNetTotalsResult, error) {
 	return c.GetNetTotalsAsync(ctx).Receive()
 }
+
+// FutureGetRPCLimitInfoResult is a future promise to deliver the result of a
+// GetRPCLimitInfoAsync RPC invocation (or an applicable error).
+type FutureGetRPCLimitInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the per
+// client RPC request limits and statistics.
+func (r FutureGetRPCLimitInfoResult) Receive() (*chainjson.GetRPCLimitInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unmarshal result as a getrpclimitinfo result object.
+	var info chainjson.GetRPCLimitInfoResult
+	err = json.Unmarshal(res, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// GetRPCLimitInfoAsync returns an instance of a type that can be used to get
+// the result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See GetRPCLimitInfo for the blocking version and more details.
+func (c *Client) GetRPCLimitInfoAsync(ctx context.Context) FutureGetRPCLimitInfoResult {
+	cmd := chainjson.NewGetRPCLimitInfoCmd()
+	return c.sendCmd(ctx, cmd)
+}
+
+// GetRPCLimitInfo returns the per client RPC request limits configured on the
+// server along with statistics about the requests that have been limited.
+func (c *Client) GetRPCLimitInfo(ctx context.Context) (*chainjson.GetRPCLimitInfoResult, error) {
+	return c.GetRPCLimitInfoAsync(ctx).Receive()
+}
@@ -12,8 +12,10 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v2"
 	"github.com/decred/dcrd/wire"
@@ -33,26 +35,78 @@ var (
 // reconnect.
 type notificationState struct {
 	notifyBlocks                bool
+	notifyBlocksPayload         chainjson.NotifyBlocksPayload
 	notifyWork                  bool
+	notifyWorkDetails           bool
+	notifyWorkShareTarget       []byte
 	notifyWinningTickets        bool
 	notifySpentAndMissedTickets bool
 	notifyNewTickets            bool
+	notifyTicketLifecycle       bool
 	notifyStakeDifficulty       bool
 	notifyNewTx                 bool
 	notifyNewTxVerbose          bool
+	notifyNewTxFilter           *chainjson.NotifyNewTransactionsFilter
+	txFilterAddrs               map[string]struct{}
+	txFilterOutPoints           map[chainjson.OutPoint]struct{}
+	utxoAddrs                   map[string]struct{}
+	utxoScripts                 map[string]struct{}
+	utxoOutPoints               map[chainjson.OutPoint]struct{}
 }
 
 // Copy returns a deep copy of the receiver.
 func (s *notificationState) Copy() *notificationState {
 	var stateCopy notificationState
 	stateCopy.notifyBlocks = s.notifyBlocks
+	stateCopy.notifyBlocksPayload = s.notifyBlocksPayload
 	stateCopy.notifyWork = s.notifyWork
+	stateCopy.notifyWorkDetails = s.notifyWorkDetails
+	stateCopy.notifyWorkShareTarget = append([]byte(nil),
+		s.notifyWorkShareTarget...)
 	stateCopy.notifyWinningTickets = s.notifyWinningTickets
 	stateCopy.notifySpentAndMissedTickets = s.notifySpentAndMissedTickets
 	stateCopy.notifyNewTickets = s.notifyNewTickets
+	stateCopy.notifyTicketLifecycle = s.notifyTicketLifecycle
 	stateCopy.notifyStakeDifficulty = s.notifyStakeDifficulty
 	stateCopy.notifyNewTx = s.notifyNewTx
 	stateCopy.notifyNewTxVerbose = s.notifyNewTxVerbose
+	if s.notifyNewTxFilter != nil {
+		filter := *s.notifyNewTxFilter
+		filter.Addresses = append([]string(nil), filter.Addresses...)
+		filter.ScriptClasses = append([]string(nil), filter.ScriptClasses...)
+		filter.TxTypes = append([]string(nil), filter.TxTypes...)
+		stateCopy.notifyNewTxFilter = &filter
+	}
+	if s.txFilterAddrs != nil {
+		stateCopy.txFilterAddrs = make(map[string]struct{},
+			len(s.txFilterAddrs))
+		for addr := range s.txFilterAddrs {
+			stateCopy.txFilterAddrs[addr] = struct{}{}
+		}
+	}
+	if s.txFilterOutPoints != nil {
+		stateCopy.txFilterOutPoints = make(map[chainjson.OutPoint]struct{},
+			len(s.txFilterOutPoints))
+		for op := range s.txFilterOutPoints {
+			stateCopy.txFilterOutPoints[op] = struct{}{}
+		}
+	}
+	if s.utxoAddrs != nil {
+		stateCopy.utxoAddrs = make(map[string]struct{}, len(s.utxoAddrs))
+		for addr := range s.utxoAddrs {
+			stateCopy.utxoAddrs[addr] = struct{}{}
+		}
+		stateCopy.utxoScripts = make(map[string]struct{},
+			len(s.utxoScripts))
+		for script := range s.utxoScripts {
+			stateCopy.utxoScripts[script] = struct{}{}
+		}
+		stateCopy.utxoOutPoints = make(map[chainjson.OutPoint]struct{},
+			len(s.utxoOutPoints))
+		for op := range s.utxoOutPoints {
+			stateCopy.utxoOutPoints[op] = struct{}{}
+		}
+	}
 
 	return &stateCopy
 }
@@ -87,12 +141,34 @@ type NotificationHandlers struct {
 	// notification handlers, and is safe for blocking client requests.
 	OnClientConnected func()
 
+	// OnReconnected is invoked after the client reestablishes a dropped
+	// connection to the RPC server and has successfully reregistered all of
+	// the notifications that were active prior to the disconnect.  The
+	// passed duration is how long the client was disconnected.
+	//
+	// Notifications that would have been delivered while the client was
+	// disconnected are lost, so callers should use this to detect and fill
+	// any gaps, such as by comparing the current best block of the server
+	// with the last block they were notified about.  This callback is run
+	// async with the rest of the notification handlers, and is safe for
+	// blocking client requests.
+	OnReconnected func(disconnectedFor time.Duration)
+
 	// OnBlockConnected is invoked when a block is connected to the longest
 	// (best) chain.  It will only be invoked if a preceding call to
 	// NotifyBlocks has been made to register for the notification and the
 	// function is non-nil.
 	OnBlockConnected func(blockHeader []byte, transactions [][]byte)
 
+	// OnBlockConnectedPayload is invoked when a block is connected to the
+	// longest (best) chain along with the additional block data requested
+	// by a preceding call to NotifyBlocksWithPayload.  The payload is nil
+	// when no additional data was requested.  It will only be invoked if a
+	// preceding call to NotifyBlocks or NotifyBlocksWithPayload has been
+	// made to register for the notification and the function is non-nil.
+	OnBlockConnectedPayload func(blockHeader []byte, transactions [][]byte,
+		payload *chainjson.BlockConnectedPayload)
+
 	// OnBlockDisconnected is invoked when a block is disconnected from the
 	// longest (best) chain.  It will only be invoked if a preceding call to
 	// NotifyBlocks has been made to register for the notification and the
@@ -104,10 +180,39 @@ type NotificationHandlers struct {
 	// been made to register for the notification and the function is non-nil.
 	OnWork func(data []byte, target []byte, reason string)
 
+	// OnWorkDetails is invoked when a new block template is generated along
+	// with the additional details about the template requested by a
+	// preceding call to NotifyWorkWithDetails.  The details are nil when
+	// they were not requested.  It will only be invoked if a preceding call
+	// to NotifyWork or NotifyWorkWithDetails has been made to register for
+	// the notification and the function is non-nil.
+	OnWorkDetails func(data []byte, target []byte, reason string,
+		details *chainjson.WorkNtfnDetails)
+
 	// OnRelevantTxAccepted is invoked when an unmined transaction passes
 	// the client's transaction filter.
 	OnRelevantTxAccepted func(transaction []byte)
 
+	// OnUTXOCreated is invoked when an output that pays to an address or
+	// script registered via NotifyUTXOs is created by a transaction that is
+	// accepted to the memory pool or included in a block connected to the
+	// main chain.  The block hash is nil and the block height is -1 for
+	// transactions accepted to the memory pool.  It will only be invoked if
+	// a preceding call to NotifyUTXOs has been made to register for the
+	// notification and the function is non-nil.
+	OnUTXOCreated func(outPoint *wire.OutPoint, amount dcrutil.Amount,
+		pkScript []byte, blockHash *chainhash.Hash, blockHeight int64)
+
+	// OnUTXOSpent is invoked when an output watched due to a preceding call
+	// to NotifyUTXOs is spent by the input of a transaction that is accepted
+	// to the memory pool or included in a block connected to the main
+	// chain.  The block hash is nil and the block height is -1 for
+	// transactions accepted to the memory pool.  It will only be invoked if
+	// a preceding call to NotifyUTXOs has been made to register for the
+	// notification and the function is non-nil.
+	OnUTXOSpent func(outPoint *wire.OutPoint, spenderTx *chainhash.Hash,
+		inputIndex uint32, blockHash *chainhash.Hash, blockHeight int64)
+
 	// OnReorganization is invoked when the blockchain begins reorganizing.
 	// It will only be invoked if a preceding call to NotifyBlocks has been
 	// made to register for the notification and the function is non-nil.
@@ -140,6 +245,14 @@ type NotificationHandlers struct {
 		stakeDiff int64,
 		tickets []*chainhash.Hash)
 
+	// OnTicketLifecycle is invoked when a block is connected to the longest
+	// (best) chain and tickets change state as a result.  It will only be
+	// invoked if a preceding call to NotifyTicketLifecycle has been made to
+	// register for the notification and the function is non-nil.
+	OnTicketLifecycle func(hash *chainhash.Hash,
+		height int64,
+		live, selected, voted, missed, expired, revoked []*chainhash.Hash)
+
 	// OnStakeDifficulty is invoked when a block is connected to the longest
 	// (best) chain and a new stake difficulty is calculated.  It will only
 	// be invoked if a preceding call to NotifyStakeDifficulty has been
@@ -185,18 +298,26 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 	case chainjson.BlockConnectedNtfnMethod:
 		// Ignore the notification if the client is not interested in
 		// it.
-		if c.ntfnHandlers.OnBlockConnected == nil {
+		if c.ntfnHandlers.OnBlockConnected == nil &&
+			c.ntfnHandlers.OnBlockConnectedPayload == nil {
 			return
 		}
 
-		blockHeader, transactions, err := parseBlockConnectedParams(ntfn.Params)
+		blockHeader, transactions, payload, err :=
+			parseBlockConnectedParams(ntfn.Params)
 		if err != nil {
 			log.Warnf("Received invalid blockconnected "+
 				"notification: %v", err)
 			return
 		}
 
-		c.ntfnHandlers.OnBlockConnected(blockHeader, transactions)
+		if c.ntfnHandlers.OnBlockConnected != nil {
+			c.ntfnHandlers.OnBlockConnected(blockHeader, transactions)
+		}
+		if c.ntfnHandlers.OnBlockConnectedPayload != nil {
+			c.ntfnHandlers.OnBlockConnectedPayload(blockHeader,
+				transactions, payload)
+		}
 
 	// OnBlockDisconnected
 	case chainjson.BlockDisconnectedNtfnMethod:
@@ -219,17 +340,23 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 	case chainjson.WorkNtfnMethod:
 		// Ignore the notification if the client is not interested in
 		// it.
-		if c.ntfnHandlers.OnWork == nil {
+		if c.ntfnHandlers.OnWork == nil &&
+			c.ntfnHandlers.OnWorkDetails == nil {
 			return
 		}
 
-		data, target, reason, err := parseWorkParams(ntfn.Params)
+		data, target, reason, details, err := parseWorkParams(ntfn.Params)
 		if err != nil {
 			log.Warnf("Received invalid work notification: %v", err)
 			return
 		}
 
-		c.ntfnHandlers.OnWork(data, target, reason)
+		if c.ntfnHandlers.OnWork != nil {
+			c.ntfnHandlers.OnWork(data, target, reason)
+		}
+		if c.ntfnHandlers.OnWorkDetails != nil {
+			c.ntfnHandlers.OnWorkDetails(data, target, reason, details)
+		}
 
 	case chainjson.RelevantTxAcceptedNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -247,6 +374,43 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 
 		c.ntfnHandlers.OnRelevantTxAccepted(transaction)
 
+	// OnUTXOCreated
+	case chainjson.UTXOCreatedNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnUTXOCreated == nil {
+			return
+		}
+
+		outPoint, amount, pkScript, blockHash, blockHeight, err :=
+			parseUTXOCreatedNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid utxocreated notification: %v",
+				err)
+			return
+		}
+
+		c.ntfnHandlers.OnUTXOCreated(outPoint, amount, pkScript, blockHash,
+			blockHeight)
+
+	// OnUTXOSpent
+	case chainjson.UTXOSpentNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnUTXOSpent == nil {
+			return
+		}
+
+		outPoint, spenderTx, inputIndex, blockHash, blockHeight, err :=
+			parseUTXOSpentNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid utxospent notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnUTXOSpent(outPoint, spenderTx, inputIndex,
+			blockHash, blockHeight)
+
 	// OnReorganization
 	case chainjson.ReorganizationNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -325,6 +489,26 @@ func (c *Client) handleNotification(ntfn *rawNotification) {
 			stakeDifficulty,
 			tickets)
 
+	// OnTicketLifecycle
+	case chainjson.TicketLifecycleNtfnMethod:
+		// Ignore the notification if the client is not interested in
+		// it.
+		if c.ntfnHandlers.OnTicketLifecycle == nil {
+			return
+		}
+
+		blockHash, blockHeight, tickets, err :=
+			parseTicketLifecycleNtfnParams(ntfn.Params)
+		if err != nil {
+			log.Warnf("Received invalid ticket lifecycle "+
+				"notification: %v", err)
+			return
+		}
+
+		c.ntfnHandlers.OnTicketLifecycle(blockHash, blockHeight,
+			tickets[0], tickets[1], tickets[2], tickets[3],
+			tickets[4], tickets[5])
+
 	// OnStakeDifficulty
 	case chainjson.StakeDifficultyNtfnMethod:
 		// Ignore the notification if the client is not interested in
@@ -410,56 +594,72 @@ func parseHexParam(param json.RawMessage) ([]byte, error) {
 }
 
 // parseBlockConnectedParams parses out the parameters included in a
-// blockconnected notification.
-func parseBlockConnectedParams(params []json.RawMessage) (blockHeader []byte, transactions [][]byte, err error) {
-	if len(params) != 2 {
-		return nil, nil, wrongNumParams(len(params))
+// blockconnected notification.  The returned payload is nil when the
+// notification does not include any additional block data.
+func parseBlockConnectedParams(params []json.RawMessage) (blockHeader []byte, transactions [][]byte, payload *chainjson.BlockConnectedPayload, err error) {
+	if len(params) != 2 && len(params) != 3 {
+		return nil, nil, nil, wrongNumParams(len(params))
 	}
 
 	blockHeader, err = parseHexParam(params[0])
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	var hexTransactions []string
 	err = json.Unmarshal(params[1], &hexTransactions)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 	transactions = make([][]byte, len(hexTransactions))
 	for i, hexTx := range hexTransactions {
 		transactions[i], err = hex.DecodeString(hexTx)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, nil, err
 		}
 	}
 
-	return blockHeader, transactions, nil
+	if len(params) == 3 {
+		err = json.Unmarshal(params[2], &payload)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return blockHeader, transactions, payload, nil
 }
 
 // parseWorkParams parses out the parameters included in a
-// newwork notification.
-func parseWorkParams(params []json.RawMessage) (data, target []byte, reason string, err error) {
-	if len(params) != 3 {
-		return nil, nil, "", wrongNumParams(len(params))
+// newwork notification.  The returned details are nil when the notification
+// does not include them.
+func parseWorkParams(params []json.RawMessage) (data, target []byte, reason string, details *chainjson.WorkNtfnDetails, err error) {
+	if len(params) != 3 && len(params) != 4 {
+		return nil, nil, "", nil, wrongNumParams(len(params))
 	}
 
 	data, err = parseHexParam(params[0])
 	if err != nil {
-		return nil, nil, "", err
+		return nil, nil, "", nil, err
 	}
 
 	target, err = parseHexParam(params[1])
 	if err != nil {
-		return nil, nil, "", err
+		return nil, nil, "", nil, err
 	}
 
 	err = json.Unmarshal(params[2], &reason)
 	if err != nil {
-		return nil, nil, "", err
+		return nil, nil, "", nil, err
 	}
 
-	return data, target, reason, nil
+	if len(params) == 4 {
+		err = json.Unmarshal(params[3], &details)
+		if err != nil {
+			return nil, nil, "", nil, err
+		}
+	}
+
+	return data, target, reason, details, nil
 }
 
 // parseBlockDisconnectedParams parses out the parameters included in a
@@ -482,6 +682,113 @@ func parseRelevantTxAcceptedParams(params []json.RawMessage) (transaction []byte
 	return parseHexParam(params[0])
 }
 
+// parseUTXONtfnOutPoint parses out the outpoint from the outpoint parameter of
+// a UTXO notification.
+func parseUTXONtfnOutPoint(param json.RawMessage) (*wire.OutPoint, error) {
+	var op chainjson.OutPoint
+	err := json.Unmarshal(param, &op)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := chainhash.NewHashFromStr(op.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return wire.NewOutPoint(hash, op.Index, op.Tree), nil
+}
+
+// parseUTXONtfnBlock parses out the block hash and height from the block
+// parameters of a UTXO notification.  The block hash is nil when it is empty
+// due to the transaction being accepted to the memory pool.
+func parseUTXONtfnBlock(hashParam, heightParam json.RawMessage) (*chainhash.Hash, int64, error) {
+	var blockHashStr string
+	err := json.Unmarshal(hashParam, &blockHashStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	var blockHeight int64
+	err = json.Unmarshal(heightParam, &blockHeight)
+	if err != nil {
+		return nil, 0, err
+	}
+	if blockHashStr == "" {
+		return nil, blockHeight, nil
+	}
+	blockHash, err := chainhash.NewHashFromStr(blockHashStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	return blockHash, blockHeight, nil
+}
+
+// parseUTXOCreatedNtfnParams parses out the outpoint, amount, output script,
+// block hash, and block height from the parameters of a utxocreated
+// notification.
+func parseUTXOCreatedNtfnParams(params []json.RawMessage) (*wire.OutPoint,
+	dcrutil.Amount, []byte, *chainhash.Hash, int64, error) {
+
+	if len(params) != 5 {
+		return nil, 0, nil, nil, 0, wrongNumParams(len(params))
+	}
+
+	outPoint, err := parseUTXONtfnOutPoint(params[0])
+	if err != nil {
+		return nil, 0, nil, nil, 0, err
+	}
+	var amount int64
+	err = json.Unmarshal(params[1], &amount)
+	if err != nil {
+		return nil, 0, nil, nil, 0, err
+	}
+	pkScript, err := parseHexParam(params[2])
+	if err != nil {
+		return nil, 0, nil, nil, 0, err
+	}
+	blockHash, blockHeight, err := parseUTXONtfnBlock(params[3], params[4])
+	if err != nil {
+		return nil, 0, nil, nil, 0, err
+	}
+
+	return outPoint, dcrutil.Amount(amount), pkScript, blockHash,
+		blockHeight, nil
+}
+
+// parseUTXOSpentNtfnParams parses out the outpoint, spending transaction hash,
+// input index, block hash, and block height from the parameters of a utxospent
+// notification.
+func parseUTXOSpentNtfnParams(params []json.RawMessage) (*wire.OutPoint,
+	*chainhash.Hash, uint32, *chainhash.Hash, int64, error) {
+
+	if len(params) != 5 {
+		return nil, nil, 0, nil, 0, wrongNumParams(len(params))
+	}
+
+	outPoint, err := parseUTXONtfnOutPoint(params[0])
+	if err != nil {
+		return nil, nil, 0, nil, 0, err
+	}
+	var spenderTxStr string
+	err = json.Unmarshal(params[1], &spenderTxStr)
+	if err != nil {
+		return nil, nil, 0, nil, 0, err
+	}
+	spenderTx, err := chainhash.NewHashFromStr(spenderTxStr)
+	if err != nil {
+		return nil, nil, 0, nil, 0, err
+	}
+	var inputIndex uint32
+	err = json.Unmarshal(params[2], &inputIndex)
+	if err != nil {
+		return nil, nil, 0, nil, 0, err
+	}
+	blockHash, blockHeight, err := parseUTXONtfnBlock(params[3], params[4])
+	if err != nil {
+		return nil, nil, 0, nil, 0, err
+	}
+
+	return outPoint, spenderTx, inputIndex, blockHash, blockHeight, nil
+}
+
 func parseReorganizationNtfnParams(params []json.RawMessage) (*chainhash.Hash,
 	int32, *chainhash.Hash, int32, error) {
 	errorOut := func(err error) (*chainhash.Hash, int32, *chainhash.Hash,
@@ -718,6 +1025,55 @@ func parseNewTicketsNtfnParams(params []json.RawMessage) (*chainhash.Hash, int64
 	return sha, bh, stakeDiff, t, nil
 }
 
+// parseTicketLifecycleNtfnParams parses out the block hash, block height, and
+// the live, selected, voted, missed, expired, and revoked ticket hashes, in
+// that order, from a TicketLifecycle notification.
+func parseTicketLifecycleNtfnParams(params []json.RawMessage) (*chainhash.Hash, int64, [6][]*chainhash.Hash, error) {
+	var tickets [6][]*chainhash.Hash
+	if len(params) != 2+len(tickets) {
+		return nil, 0, tickets, wrongNumParams(len(params))
+	}
+
+	// Unmarshal first parameter as a string.
+	var blockHashStr string
+	err := json.Unmarshal(params[0], &blockHashStr)
+	if err != nil {
+		return nil, 0, tickets, err
+	}
+
+	// Create hash from block hash string.
+	blockHash, err := chainhash.NewHashFromStr(blockHashStr)
+	if err != nil {
+		return nil, 0, tickets, err
+	}
+
+	// Unmarshal second parameter as an integer.
+	var blockHeight int32
+	err = json.Unmarshal(params[1], &blockHeight)
+	if err != nil {
+		return nil, 0, tickets, err
+	}
+
+	// Unmarshal the remaining parameters as slices of ticket hashes.
+	for i := range tickets {
+		var hashStrs []string
+		err = json.Unmarshal(params[2+i], &hashStrs)
+		if err != nil {
+			return nil, 0, tickets, err
+		}
+		hashes := make([]*chainhash.Hash, len(hashStrs))
+		for j, hashStr := range hashStrs {
+			hashes[j], err = chainhash.NewHashFromStr(hashStr)
+			if err != nil {
+				return nil, 0, tickets, err
+			}
+		}
+		tickets[i] = hashes
+	}
+
+	return blockHash, int64(blockHeight), tickets, nil
+}
+
 // parseStakeDifficultyNtfnParams parses out the list of block hash, block
 // height, and stake difficulty from a WinningTickets notification.
 func parseStakeDifficultyNtfnParams(params []json.RawMessage) (
@@ -851,6 +1207,17 @@ func (r FutureNotifyWorkResult) Receive() error {
 //
 // NOTE: This is a dcrd extension and requires a websocket connection.
 func (c *Client) NotifyBlocksAsync(ctx context.Context) FutureNotifyBlocksResult {
+	return c.NotifyBlocksWithPayloadAsync(ctx, chainjson.NBPHeader)
+}
+
+// NotifyBlocksWithPayloadAsync returns an instance of a type that can be used
+// to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyBlocksWithPayload for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyBlocksWithPayloadAsync(ctx context.Context, payload chainjson.NotifyBlocksPayload) FutureNotifyBlocksResult {
 	// Not supported in HTTP POST mode.
 	if c.config.HTTPPostMode {
 		return newFutureError(ErrWebsocketsRequired)
@@ -862,7 +1229,13 @@ func (c *Client) NotifyBlocksAsync(ctx context.Context) FutureNotifyBlocksResult
 		return newNilFutureResult()
 	}
 
-	cmd := chainjson.NewNotifyBlocksCmd()
+	// Only specify the payload when additional data is requested to remain
+	// compatible with servers that do not support it.
+	var payloadStr *string
+	if payload != "" && payload != chainjson.NBPHeader {
+		payloadStr = (*string)(&payload)
+	}
+	cmd := chainjson.NewNotifyBlocksWithPayloadCmd(payloadStr)
 	return c.sendCmd(ctx, cmd)
 }
 
@@ -889,6 +1262,33 @@ func (c *Client) NotifyWorkAsync(ctx context.Context) FutureNotifyWorkResult {
 	return c.sendCmd(ctx, cmd)
 }
 
+// NotifyWorkWithDetailsAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyWorkWithDetails for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyWorkWithDetailsAsync(ctx context.Context, shareTarget []byte) FutureNotifyWorkResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return newFutureError(ErrWebsocketsRequired)
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return newNilFutureResult()
+	}
+
+	var shareTargetStr *string
+	if len(shareTarget) > 0 {
+		shareTargetStr = dcrjson.String(hex.EncodeToString(shareTarget))
+	}
+	cmd := chainjson.NewNotifyWorkWithOptionsCmd(dcrjson.Bool(true), shareTargetStr)
+	return c.sendCmd(ctx, cmd)
+}
+
 // NotifyBlocks registers the client to receive notifications when blocks are
 // connected and disconnected from the main chain.  The notifications are
 // delivered to the notification handlers associated with the client.  Calling
@@ -903,6 +1303,19 @@ func (c *Client) NotifyBlocks(ctx context.Context) error {
 	return c.NotifyBlocksAsync(ctx).Receive()
 }
 
+// NotifyBlocksWithPayload registers the client to receive notifications when
+// blocks are connected and disconnected from the main chain with the provided
+// additional block data included in the block connected notifications.  This
+// avoids the need to request the block data separately.
+//
+// The notifications delivered as a result of this call will be via one of
+// OnBlockConnected, OnBlockConnectedPayload, or OnBlockDisconnected.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyBlocksWithPayload(ctx context.Context, payload chainjson.NotifyBlocksPayload) error {
+	return c.NotifyBlocksWithPayloadAsync(ctx, payload).Receive()
+}
+
 // NotifyWork registers the client to receive notifications when a new block
 // template has been generated.
 //
@@ -914,6 +1327,21 @@ func (c *Client) NotifyWork(ctx context.Context) error {
 	return c.NotifyWorkAsync(ctx).Receive()
 }
 
+// NotifyWorkWithDetails registers the client to receive notifications when a
+// new block template has been generated along with additional details about
+// the template such as its identifier, height, and parent block.  The provided
+// little-endian share target, if any, is included in the details which allows
+// multiple subscribers, such as the individual stratum servers of a mining
+// pool, to independently track the share target they require.
+//
+// The notifications delivered as a result of this call will be via one of
+// OnWork or OnWorkDetails.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyWorkWithDetails(ctx context.Context, shareTarget []byte) error {
+	return c.NotifyWorkWithDetailsAsync(ctx, shareTarget).Receive()
+}
+
 // FutureNotifyWinningTicketsResult is a future promise to deliver the result of a
 // NotifyWinningTicketsAsync RPC invocation (or an applicable error).
 type FutureNotifyWinningTicketsResult chan *response
@@ -1062,6 +1490,56 @@ func (c *Client) NotifyNewTickets(ctx context.Context) error {
 	return c.NotifyNewTicketsAsync(ctx).Receive()
 }
 
+// FutureNotifyTicketLifecycleResult is a future promise to deliver the result
+// of a NotifyTicketLifecycleAsync RPC invocation (or an applicable error).
+type FutureNotifyTicketLifecycleResult chan *response
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r FutureNotifyTicketLifecycleResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// NotifyTicketLifecycleAsync returns an instance of a type that can be used to
+// get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyTicketLifecycle for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyTicketLifecycleAsync(ctx context.Context) FutureNotifyTicketLifecycleResult {
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return newFutureError(ErrWebsocketsRequired)
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return newNilFutureResult()
+	}
+
+	cmd := chainjson.NewNotifyTicketLifecycleCmd()
+
+	return c.sendCmd(ctx, cmd)
+}
+
+// NotifyTicketLifecycle registers the client to receive notifications when
+// blocks are connected to the main chain and tickets become live, are
+// selected to vote, vote, miss, expire, or are revoked.  The notifications are
+// delivered to the notification handlers associated with the client.  Calling
+// this function has no effect if there are no notification handlers and will
+// result in an error if the client is configured to run in HTTP POST mode.
+//
+// The notifications delivered as a result of this call will be via
+// OnTicketLifecycle.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyTicketLifecycle(ctx context.Context) error {
+	return c.NotifyTicketLifecycleAsync(ctx).Receive()
+}
+
 // FutureNotifyStakeDifficultyResult is a future promise to deliver the result of a
 // NotifyStakeDifficultyAsync RPC invocation (or an applicable error).
 type FutureNotifyStakeDifficultyResult chan *response
@@ -1131,6 +1609,32 @@ func (r FutureNotifyNewTransactionsResult) Receive() error {
 //
 // NOTE: This is a dcrd extension and requires a websocket connection.
 func (c *Client) NotifyNewTransactionsAsync(ctx context.Context, verbose bool) FutureNotifyNewTransactionsResult {
+	return c.NotifyFilteredNewTransactionsAsync(ctx, verbose, nil)
+}
+
+// NotifyNewTransactions registers the client to receive notifications every
+// time a new transaction is accepted to the memory pool.  The notifications are
+// delivered to the notification handlers associated with the client.  Calling
+// this function has no effect if there are no notification handlers and will
+// result in an error if the client is configured to run in HTTP POST mode.
+//
+// The notifications delivered as a result of this call will be via one of
+// OnTxAccepted (when verbose is false) or OnTxAcceptedVerbose (when verbose is
+// true).
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyNewTransactions(ctx context.Context, verbose bool) error {
+	return c.NotifyNewTransactionsAsync(ctx, verbose).Receive()
+}
+
+// NotifyFilteredNewTransactionsAsync returns an instance of a type that can be
+// used to get the result of the RPC at some future time by invoking the Receive
+// function on the returned instance.
+//
+// See NotifyFilteredNewTransactions for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyFilteredNewTransactionsAsync(ctx context.Context, verbose bool, filter *chainjson.NotifyNewTransactionsFilter) FutureNotifyNewTransactionsResult {
 	// Not supported in HTTP POST mode.
 	if c.config.HTTPPostMode {
 		return newFutureError(ErrWebsocketsRequired)
@@ -1142,23 +1646,20 @@ func (c *Client) NotifyNewTransactionsAsync(ctx context.Context, verbose bool) F
 		return newNilFutureResult()
 	}
 
-	cmd := chainjson.NewNotifyNewTransactionsCmd(&verbose)
+	cmd := chainjson.NewNotifyNewTransactionsFilteredCmd(&verbose, filter)
 	return c.sendCmd(ctx, cmd)
 }
 
-// NotifyNewTransactions registers the client to receive notifications every
-// time a new transaction is accepted to the memory pool.  The notifications are
-// delivered to the notification handlers associated with the client.  Calling
-// this function has no effect if there are no notification handlers and will
-// result in an error if the client is configured to run in HTTP POST mode.
+// NotifyFilteredNewTransactions registers the client to receive notifications
+// every time a new transaction that matches the provided filter is accepted to
+// the memory pool.  The filter is applied by the server and replaces any
+// previously registered filter.  A nil filter matches all transactions.
 //
-// The notifications delivered as a result of this call will be via one of
-// OnTxAccepted (when verbose is false) or OnTxAcceptedVerbose (when verbose is
-// true).
+// See NotifyNewTransactions for more details about the notifications.
 //
 // NOTE: This is a dcrd extension and requires a websocket connection.
-func (c *Client) NotifyNewTransactions(ctx context.Context, verbose bool) error {
-	return c.NotifyNewTransactionsAsync(ctx, verbose).Receive()
+func (c *Client) NotifyFilteredNewTransactions(ctx context.Context, verbose bool, filter *chainjson.NotifyNewTransactionsFilter) error {
+	return c.NotifyFilteredNewTransactionsAsync(ctx, verbose, filter).Receive()
 }
 
 // FutureLoadTxFilterResult is a future promise to deliver the result
@@ -1203,7 +1704,87 @@ func (c *Client) LoadTxFilterAsync(ctx context.Context, reload bool, addresses [
 // filter.  The filter is consistently updated based on inspected transactions
 // during mempool acceptance, block acceptance, and for all rescanned blocks.
 //
+// The addresses and outpoints loaded into the filter are tracked by the client
+// and automatically loaded again when it reconnects.  Note that outpoints the
+// server adds to the filter on its own as it inspects transactions are not
+// tracked.
+//
 // NOTE: This is a dcrd extension and requires a websocket connection.
 func (c *Client) LoadTxFilter(ctx context.Context, reload bool, addresses []dcrutil.Address, outPoints []wire.OutPoint) error {
 	return c.LoadTxFilterAsync(ctx, reload, addresses, outPoints).Receive()
 }
+
+// FutureNotifyUTXOsResult is a future promise to deliver the result of a
+// NotifyUTXOsAsync RPC invocation (or an applicable error).
+type FutureNotifyUTXOsResult chan *response
+
+// Receive waits for the response promised by the future and returns an error
+// if the registration was not successful.
+func (r FutureNotifyUTXOsResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// NotifyUTXOsAsync returns an instance of a type that can be used to get the
+// result of the RPC at some future time by invoking the Receive function on
+// the returned instance.
+//
+// See NotifyUTXOs for the blocking version and more details.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyUTXOsAsync(ctx context.Context, addresses []dcrutil.Address,
+	pkScripts [][]byte, outPoints []wire.OutPoint) FutureNotifyUTXOsResult {
+
+	// Not supported in HTTP POST mode.
+	if c.config.HTTPPostMode {
+		return newFutureError(ErrWebsocketsRequired)
+	}
+
+	// Ignore the notification if the client is not interested in
+	// notifications.
+	if c.ntfnHandlers == nil {
+		return newNilFutureResult()
+	}
+
+	addrStrs := make([]string, len(addresses))
+	for i, a := range addresses {
+		addrStrs[i] = a.Address()
+	}
+	scriptStrs := make([]string, len(pkScripts))
+	for i, pkScript := range pkScripts {
+		scriptStrs[i] = hex.EncodeToString(pkScript)
+	}
+	outPointObjects := make([]chainjson.OutPoint, len(outPoints))
+	for i := range outPoints {
+		outPointObjects[i] = chainjson.OutPoint{
+			Hash:  outPoints[i].Hash.String(),
+			Index: outPoints[i].Index,
+			Tree:  outPoints[i].Tree,
+		}
+	}
+
+	cmd := chainjson.NewNotifyUTXOsCmd(addrStrs, scriptStrs, outPointObjects)
+	return c.sendCmd(ctx, cmd)
+}
+
+// NotifyUTXOs registers the client to receive notifications whenever outputs
+// that pay to any of the passed addresses or output scripts are created or
+// spent by transactions accepted to the memory pool or included in blocks
+// connected to the main chain.  Spends are reported for outputs created after
+// registering along with the passed outpoints of existing unspent outputs.
+// The addresses, scripts, and outpoints are added to those previously
+// registered.  The notifications are delivered to the notification handlers
+// associated with the client.  Calling this function has no effect if there
+// are no notification handlers and will result in an error if the client is
+// configured to run in HTTP POST mode.
+//
+// The registered addresses, scripts, and outpoints are tracked by the client
+// and automatically registered again when it reconnects.
+//
+// The notifications delivered as a result of this call will be via
+// OnUTXOCreated and OnUTXOSpent.
+//
+// NOTE: This is a dcrd extension and requires a websocket connection.
+func (c *Client) NotifyUTXOs(ctx context.Context, addresses []dcrutil.Address, pkScripts [][]byte, outPoints []wire.OutPoint) error {
+	return c.NotifyUTXOsAsync(ctx, addresses, pkScripts, outPoints).Receive()
+}
@@ -0,0 +1,418 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+const (
+	// defaultHealthCheckInterval is the default interval between health
+	// checks of the endpoints in a pool.
+	defaultHealthCheckInterval = time.Second * 30
+
+	// healthCheckTimeout is the maximum amount of time a single endpoint
+	// health check is allowed to take before the endpoint is considered
+	// unhealthy.
+	healthCheckTimeout = time.Second * 10
+)
+
+var (
+	// ErrNoEndpoints is an error to describe the condition where a pool is
+	// created without any endpoints.
+	ErrNoEndpoints = errors.New("no RPC server endpoints specified")
+
+	// ErrNoHealthyEndpoints is an error to describe the condition where none
+	// of the endpoints in a pool are currently healthy.
+	ErrNoHealthyEndpoints = errors.New("no healthy RPC server endpoints " +
+		"are available")
+)
+
+// PoolConfig describes the endpoints and behavior of a pool of clients.
+type PoolConfig struct {
+	// Endpoints houses the connection configurations of the RPC servers in
+	// the pool in priority order.  Requests are sent to the first healthy
+	// endpoint unless read load balancing is enabled.
+	//
+	// Websocket endpoints are connected in the background, so the
+	// DisableConnectOnNew field is ignored.
+	Endpoints []*ConnConfig
+
+	// HealthCheckInterval is the interval between health checks of the
+	// endpoints.  The default interval is used when it is zero.
+	HealthCheckInterval time.Duration
+
+	// MaxBlockLag is the maximum number of blocks the best chain of an
+	// endpoint may lag behind the best chain of the most up-to-date endpoint
+	// before it is considered unhealthy.  The check is disabled when it is
+	// zero.
+	MaxBlockLag int64
+
+	// LoadBalanceReads distributes the requests made via ReadClient and
+	// DoRead across all healthy endpoints in a round-robin fashion instead
+	// of always using the first healthy endpoint.
+	LoadBalanceReads bool
+}
+
+// PoolEndpointStatus describes the most recently known health of an endpoint
+// in a pool.
+type PoolEndpointStatus struct {
+	Host       string
+	Healthy    bool
+	BestHeight int64
+	LastError  error
+}
+
+// poolEndpoint houses a client for an endpoint in a pool along with its most
+// recently known health.
+type poolEndpoint struct {
+	client     *Client
+	healthy    bool
+	bestHeight int64
+	lastErr    error
+}
+
+// Pool provides failover, health checking, and optional read load balancing
+// across clients for multiple RPC servers.
+//
+// Notifications are not supported by clients in a pool since they would be
+// delivered by multiple servers, so the pool is intended for request and
+// response usage.
+type Pool struct {
+	config PoolConfig
+
+	mtx       sync.Mutex
+	endpoints []*poolEndpoint
+	nextRead  int
+
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	shutdown sync.Once
+}
+
+// NewPool creates a new pool of clients for the endpoints in the provided
+// configuration and starts periodically checking their health.  All endpoints
+// are initially assumed to be healthy so requests may be made immediately.
+//
+// The Shutdown method must be called to stop the health checks and shutdown
+// the clients once the pool is no longer needed.
+func NewPool(config *PoolConfig) (*Pool, error) {
+	if len(config.Endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		config:    *config,
+		endpoints: make([]*poolEndpoint, 0, len(config.Endpoints)),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+	if p.config.HealthCheckInterval == 0 {
+		p.config.HealthCheckInterval = defaultHealthCheckInterval
+	}
+	for _, connCfg := range config.Endpoints {
+		// Websocket connections are established by the health checks so
+		// that endpoints which are down do not prevent creating the pool.
+		cfgCopy := *connCfg
+		cfgCopy.DisableConnectOnNew = true
+		client, err := New(&cfgCopy, nil)
+		if err != nil {
+			p.Shutdown()
+			return nil, err
+		}
+		p.endpoints = append(p.endpoints, &poolEndpoint{
+			client:  client,
+			healthy: true,
+		})
+	}
+
+	p.wg.Add(1)
+	go p.healthCheckHandler()
+	return p, nil
+}
+
+// Client returns the client for the first healthy endpoint in priority order.
+// ErrNoHealthyEndpoints is returned when none of the endpoints are healthy.
+//
+// This function is safe for concurrent access.
+func (p *Pool) Client() (*Client, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	for _, e := range p.endpoints {
+		if e.healthy {
+			return e.client, nil
+		}
+	}
+	return nil, ErrNoHealthyEndpoints
+}
+
+// readOrder returns the healthy endpoints in the order they should be tried
+// for read requests.
+//
+// This function MUST be called with the pool lock held.
+func (p *Pool) readOrder() []*poolEndpoint {
+	healthy := make([]*poolEndpoint, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		if e.healthy {
+			healthy = append(healthy, e)
+		}
+	}
+	if !p.config.LoadBalanceReads || len(healthy) == 0 {
+		return healthy
+	}
+
+	// Rotate the starting endpoint for each read so the requests are
+	// distributed across all of the healthy endpoints.
+	start := p.nextRead % len(healthy)
+	p.nextRead = start + 1
+	return append(healthy[start:], healthy[:start]...)
+}
+
+// ReadClient returns the client that should be used for a request that only
+// reads data.  This is the same as Client unless read load balancing is
+// enabled, in which case the healthy endpoints are returned in a round-robin
+// fashion.  ErrNoHealthyEndpoints is returned when none of the endpoints are
+// healthy.
+//
+// This function is safe for concurrent access.
+func (p *Pool) ReadClient() (*Client, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	order := p.readOrder()
+	if len(order) == 0 {
+		return nil, ErrNoHealthyEndpoints
+	}
+	return order[0].client, nil
+}
+
+// isEndpointFailure returns whether or not the provided error returned by a
+// request indicates a failure of the endpoint as opposed to an error returned
+// by the RPC server for the request itself.
+func isEndpointFailure(err error) bool {
+	var rpcErr *dcrjson.RPCError
+	return err != nil && !errors.As(err, &rpcErr)
+}
+
+// markFailed marks the provided endpoint as unhealthy due to the provided
+// error.
+func (p *Pool) markFailed(e *poolEndpoint, err error) {
+	p.mtx.Lock()
+	if e.healthy {
+		log.Warnf("Marking RPC server %s unhealthy: %v", e.client.config.Host,
+			err)
+	}
+	e.healthy = false
+	e.lastErr = err
+	p.mtx.Unlock()
+}
+
+// do invokes the provided function with the clients for the healthy endpoints
+// in the provided order until one of them succeeds.
+func (p *Pool) do(ctx context.Context, order []*poolEndpoint, fn func(*Client) error) error {
+	lastErr := ErrNoHealthyEndpoints
+	for _, e := range order {
+		p.mtx.Lock()
+		healthy := e.healthy
+		p.mtx.Unlock()
+		if !healthy {
+			continue
+		}
+
+		err := fn(e.client)
+		if !isEndpointFailure(err) {
+			return err
+		}
+
+		// Don't penalize the endpoint or try another one when the request
+		// failed due to the caller giving up on it.
+		if ctx.Err() != nil {
+			return err
+		}
+		p.markFailed(e, err)
+		lastErr = err
+	}
+	return lastErr
+}
+
+// Do invokes the provided function with the client for the first healthy
+// endpoint in priority order.  When the function returns an error that
+// indicates a failure of the endpoint, as opposed to an error returned by the
+// RPC server for the request, the endpoint is marked unhealthy and the function
+// is invoked again with the client for the next healthy endpoint.
+//
+// The error from the last invocation of the function is returned, or
+// ErrNoHealthyEndpoints when none of the endpoints are healthy.
+//
+// Since the function may be invoked more than once, it must be safe to repeat
+// the requests it makes.
+//
+// This function is safe for concurrent access.
+func (p *Pool) Do(ctx context.Context, fn func(*Client) error) error {
+	p.mtx.Lock()
+	order := append([]*poolEndpoint(nil), p.endpoints...)
+	p.mtx.Unlock()
+
+	return p.do(ctx, order, fn)
+}
+
+// DoRead is the same as Do except it is intended for requests that only read
+// data and therefore distributes them across the healthy endpoints when read
+// load balancing is enabled.
+//
+// This function is safe for concurrent access.
+func (p *Pool) DoRead(ctx context.Context, fn func(*Client) error) error {
+	p.mtx.Lock()
+	order := p.readOrder()
+	p.mtx.Unlock()
+
+	return p.do(ctx, order, fn)
+}
+
+// Status returns the most recently known health of each endpoint in the pool
+// in priority order.
+//
+// This function is safe for concurrent access.
+func (p *Pool) Status() []PoolEndpointStatus {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	status := make([]PoolEndpointStatus, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		status = append(status, PoolEndpointStatus{
+			Host:       e.client.config.Host,
+			Healthy:    e.healthy,
+			BestHeight: e.bestHeight,
+			LastError:  e.lastErr,
+		})
+	}
+	return status
+}
+
+// checkEndpoint checks the health of the provided endpoint by establishing its
+// connection when needed and querying its best block height.
+func (p *Pool) checkEndpoint(ctx context.Context, e *poolEndpoint) (int64, error) {
+	c := e.client
+	if !c.config.HTTPPostMode {
+		// Establish the initial websocket connection when needed.  Once
+		// connected, the client handles reconnecting on its own.
+		err := c.Connect(p.ctx, false)
+		if err != nil && !errors.Is(err, ErrClientAlreadyConnected) {
+			return 0, err
+		}
+		if c.Disconnected() {
+			return 0, ErrClientDisconnect
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	return c.GetBlockCount(ctx)
+}
+
+// CheckHealth immediately checks the health of all endpoints in the pool and
+// updates their status accordingly.  Endpoints are considered healthy when they
+// respond to requests and, when enabled via the MaxBlockLag field of the pool
+// configuration, are not lagging too far behind the other endpoints.
+//
+// This is invoked periodically by the pool, so callers typically will not need
+// to call it unless they wish to refresh the status sooner.
+//
+// This function is safe for concurrent access.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	p.mtx.Lock()
+	endpoints := append([]*poolEndpoint(nil), p.endpoints...)
+	p.mtx.Unlock()
+
+	// Check all of the endpoints concurrently.
+	heights := make([]int64, len(endpoints))
+	errs := make([]error, len(endpoints))
+	var wg sync.WaitGroup
+	wg.Add(len(endpoints))
+	for i, e := range endpoints {
+		go func(i int, e *poolEndpoint) {
+			heights[i], errs[i] = p.checkEndpoint(ctx, e)
+			wg.Done()
+		}(i, e)
+	}
+	wg.Wait()
+
+	var bestHeight int64
+	for i := range endpoints {
+		if errs[i] == nil && heights[i] > bestHeight {
+			bestHeight = heights[i]
+		}
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	for i, e := range endpoints {
+		healthy := errs[i] == nil
+		if healthy {
+			e.bestHeight = heights[i]
+			e.lastErr = nil
+			lag := bestHeight - heights[i]
+			if p.config.MaxBlockLag > 0 && lag > p.config.MaxBlockLag {
+				healthy = false
+				e.lastErr = errors.New("best chain lags behind other " +
+					"endpoints")
+			}
+		} else {
+			e.lastErr = errs[i]
+		}
+
+		if healthy != e.healthy {
+			if healthy {
+				log.Infof("RPC server %s is healthy", e.client.config.Host)
+			} else {
+				log.Warnf("Marking RPC server %s unhealthy: %v",
+					e.client.config.Host, e.lastErr)
+			}
+		}
+		e.healthy = healthy
+	}
+}
+
+// healthCheckHandler periodically checks the health of all endpoints in the
+// pool until it is shutdown.
+//
+// This function must be run as a goroutine.
+func (p *Pool) healthCheckHandler() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.config.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		p.CheckHealth(p.ctx)
+
+		select {
+		case <-ticker.C:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// Shutdown stops the health checks and shuts down the clients for all of the
+// endpoints in the pool.
+//
+// This function is safe for concurrent access.
+func (p *Pool) Shutdown() {
+	p.shutdown.Do(func() {
+		p.cancel()
+		p.wg.Wait()
+		for _, e := range p.endpoints {
+			e.client.Shutdown()
+		}
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package rpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// newTestPoolServer returns a test HTTP server that responds to all JSON-RPC
+// requests with the provided block count.
+func newTestPoolServer(blockCount int64) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"result":%d,"error":null,"id":1}`, blockCount)
+	}))
+}
+
+// testPoolConnConfig returns a connection config for the provided test server.
+func testPoolConnConfig(server *httptest.Server) *ConnConfig {
+	return &ConnConfig{
+		Host:         strings.TrimPrefix(server.URL, "http://"),
+		HTTPPostMode: true,
+		DisableTLS:   true,
+	}
+}
+
+// TestPool ensures a pool of clients performs health checks, failover, and read
+// load balancing as expected.
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	server1 := newTestPoolServer(100)
+	defer server1.Close()
+	server2 := newTestPoolServer(100)
+	defer server2.Close()
+	server3 := newTestPoolServer(90)
+	defer server3.Close()
+
+	pool, err := NewPool(&PoolConfig{
+		Endpoints: []*ConnConfig{
+			testPoolConnConfig(server1),
+			testPoolConnConfig(server2),
+			testPoolConnConfig(server3),
+		},
+		HealthCheckInterval: time.Hour,
+		MaxBlockLag:         5,
+		LoadBalanceReads:    true,
+	})
+	if err != nil {
+		t.Fatalf("NewPool: %v", err)
+	}
+	defer pool.Shutdown()
+
+	// Ensure the endpoint that lags behind is marked unhealthy.
+	ctx := context.Background()
+	pool.CheckHealth(ctx)
+	status := pool.Status()
+	wantHealthy := []bool{true, true, false}
+	for i, s := range status {
+		if s.Healthy != wantHealthy[i] {
+			t.Fatalf("endpoint %d: unexpected health -- got %v, want %v", i,
+				s.Healthy, wantHealthy[i])
+		}
+	}
+
+	// Ensure reads are distributed across the healthy endpoints.
+	client1, _ := pool.Client()
+	seen := make(map[*Client]int)
+	for i := 0; i < 4; i++ {
+		c, err := pool.ReadClient()
+		if err != nil {
+			t.Fatalf("ReadClient: %v", err)
+		}
+		seen[c]++
+	}
+	if len(seen) != 2 || seen[client1] != 2 {
+		t.Fatalf("reads not load balanced across healthy endpoints: %v", seen)
+	}
+
+	// Ensure errors returned by the RPC server for a request do not result
+	// in failover.
+	rpcErr := &dcrjson.RPCError{Code: dcrjson.ErrRPCInvalidParameter}
+	var calls int
+	err = pool.Do(ctx, func(c *Client) error {
+		calls++
+		return rpcErr
+	})
+	if !errors.Is(err, rpcErr) || calls != 1 {
+		t.Fatalf("unexpected result for RPC error -- got %v after %d calls",
+			err, calls)
+	}
+
+	// Ensure endpoint failures result in failover to the next healthy
+	// endpoint and mark the failed endpoint unhealthy.
+	server1.Close()
+	var height int64
+	var used []*Client
+	err = pool.Do(ctx, func(c *Client) error {
+		used = append(used, c)
+		var err error
+		height, err = c.GetBlockCount(ctx)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if height != 100 || len(used) != 2 || used[0] != client1 {
+		t.Fatalf("unexpected failover -- got height %d after %d calls",
+			height, len(used))
+	}
+	if c, _ := pool.Client(); c != used[1] {
+		t.Fatal("failed endpoint is still used as the primary")
+	}
+
+	// Ensure an error is returned once all endpoints fail.
+	server2.Close()
+	server3.Close()
+	pool.CheckHealth(ctx)
+	if _, err := pool.Client(); !errors.Is(err, ErrNoHealthyEndpoints) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrNoHealthyEndpoints)
+	}
+	err = pool.DoRead(ctx, func(c *Client) error { return nil })
+	if !errors.Is(err, ErrNoHealthyEndpoints) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrNoHealthyEndpoints)
+	}
+}
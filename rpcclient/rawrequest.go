@@ -64,6 +64,7 @@ func (c *Client) RawRequestAsync(ctx context.Context, method string, params []js
 		cmd:            nil,
 		marshalledJSON: marshalledJSON,
 		responseChan:   responseChan,
+		done:           make(chan struct{}),
 	}
 	c.sendRequest(ctx, jReq)
 
@@ -0,0 +1,227 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+// rpcLimiterPruneInterval is the minimum amount of time between passes that
+// remove clients which no longer need to be tracked by the client limiter.
+const rpcLimiterPruneInterval = time.Minute
+
+// rpcLimitError describes a request that was rejected because the client
+// exceeded either its request rate limit or its concurrent request limit.
+type rpcLimitError struct {
+	// description is a human-readable description of the limit that was
+	// exceeded.
+	description string
+
+	// retryAfter is the amount of time the client should wait before
+	// retrying the request.  It is zero when the client must instead wait
+	// for one of its outstanding requests to complete.
+	retryAfter time.Duration
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e *rpcLimitError) Error() string {
+	if e.retryAfter > 0 {
+		return fmt.Sprintf("%s -- retry after %v", e.description,
+			e.retryAfter)
+	}
+	return e.description
+}
+
+// rpcClientQuota houses the request quota and statistics of a single client.
+type rpcClientQuota struct {
+	tokens           float64
+	lastRefill       time.Time
+	inFlight         int
+	numRequests      uint64
+	numRateLimited   uint64
+	numConcurLimited uint64
+}
+
+// rpcClientLimiter enforces per client limits on the rate of RPC requests and
+// the number of concurrent RPC requests.  Clients are identified by the IP
+// address they connect from.
+//
+// The request rate is limited by a token bucket per client that holds up to
+// the configured burst of requests and is refilled at the configured rate.
+type rpcClientLimiter struct {
+	rate          float64
+	burst         float64
+	maxConcurrent int
+	timeNow       func() time.Time
+
+	mtx              sync.Mutex
+	clients          map[string]*rpcClientQuota
+	lastPrune        time.Time
+	numRateLimited   uint64
+	numConcurLimited uint64
+}
+
+// newRPCClientLimiter returns a new client limiter that allows each client to
+// make the provided number of requests per second with bursts of up to the
+// provided number of requests and at most the provided number of concurrent
+// requests.  Either limit is disabled when it is zero.  A nil limiter, which
+// imposes no limits, is returned when both limits are disabled.
+func newRPCClientLimiter(rate float64, burst, maxConcurrent int) *rpcClientLimiter {
+	if rate <= 0 && maxConcurrent <= 0 {
+		return nil
+	}
+	return &rpcClientLimiter{
+		rate:          rate,
+		burst:         float64(burst),
+		maxConcurrent: maxConcurrent,
+		timeNow:       time.Now,
+		clients:       make(map[string]*rpcClientQuota),
+	}
+}
+
+// clientKey returns the key used to identify the client connected from the
+// provided remote address.
+func clientKey(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+// refill adds the tokens accumulated by the provided client quota since it was
+// last refilled.
+//
+// This function MUST be called with the limiter mutex held (for writes).
+func (l *rpcClientLimiter) refill(q *rpcClientQuota, now time.Time) {
+	elapsed := now.Sub(q.lastRefill).Seconds()
+	q.tokens = math.Min(l.burst, q.tokens+elapsed*l.rate)
+	q.lastRefill = now
+}
+
+// prune removes all clients that have no outstanding requests and a full
+// token bucket since they are indistinguishable from new clients.
+//
+// This function MUST be called with the limiter mutex held (for writes).
+func (l *rpcClientLimiter) prune(now time.Time) {
+	for key, q := range l.clients {
+		if q.inFlight > 0 {
+			continue
+		}
+		if l.rate > 0 {
+			l.refill(q, now)
+			if q.tokens < l.burst {
+				continue
+			}
+		}
+		delete(l.clients, key)
+	}
+	l.lastPrune = now
+}
+
+// acquire attempts to reserve a request for the client connected from the
+// provided remote address.  A function that must be called once the request
+// has completed is returned on success.  An error of type rpcLimitError is
+// returned when the client has exceeded one of its limits.
+//
+// This function is safe for concurrent access and may be called on a nil
+// limiter, in which case requests are never limited.
+func (l *rpcClientLimiter) acquire(remoteAddr string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	key := clientKey(remoteAddr)
+	now := l.timeNow()
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if now.Sub(l.lastPrune) >= rpcLimiterPruneInterval {
+		l.prune(now)
+	}
+	q, ok := l.clients[key]
+	if !ok {
+		q = &rpcClientQuota{tokens: l.burst, lastRefill: now}
+		l.clients[key] = q
+	}
+	q.numRequests++
+
+	if l.maxConcurrent > 0 && q.inFlight >= l.maxConcurrent {
+		q.numConcurLimited++
+		l.numConcurLimited++
+		return nil, &rpcLimitError{
+			description: fmt.Sprintf("too many concurrent requests "+
+				"(max %d per client)", l.maxConcurrent),
+		}
+	}
+	if l.rate > 0 {
+		l.refill(q, now)
+		if q.tokens < 1 {
+			q.numRateLimited++
+			l.numRateLimited++
+			wait := time.Duration((1 - q.tokens) / l.rate * float64(time.Second))
+			return nil, &rpcLimitError{
+				description: fmt.Sprintf("request rate limit exceeded "+
+					"(max %g requests per second per client)", l.rate),
+				retryAfter: wait.Round(time.Millisecond),
+			}
+		}
+		q.tokens--
+	}
+
+	q.inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mtx.Lock()
+			q.inFlight--
+			l.mtx.Unlock()
+		})
+	}, nil
+}
+
+// info returns the configured limits along with statistics about the requests
+// that have been limited in total and for each currently tracked client.
+//
+// This function is safe for concurrent access and may be called on a nil
+// limiter.
+func (l *rpcClientLimiter) info() *types.GetRPCLimitInfoResult {
+	if l == nil {
+		return &types.GetRPCLimitInfoResult{Clients: []types.RPCClientLimitInfo{}}
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	clients := make([]types.RPCClientLimitInfo, 0, len(l.clients))
+	for key, q := range l.clients {
+		clients = append(clients, types.RPCClientLimitInfo{
+			Address:            key,
+			InFlight:           int64(q.inFlight),
+			Requests:           q.numRequests,
+			RateLimited:        q.numRateLimited,
+			ConcurrencyLimited: q.numConcurLimited,
+		})
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Address < clients[j].Address
+	})
+	return &types.GetRPCLimitInfoResult{
+		RateLimit:          l.rate,
+		RateBurst:          int64(l.burst),
+		MaxClientRequests:  int64(l.maxConcurrent),
+		RateLimited:        l.numRateLimited,
+		ConcurrencyLimited: l.numConcurLimited,
+		Clients:            clients,
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestRPCClientLimiterRate ensures the client limiter enforces the configured
+// request rate and burst per client.
+func TestRPCClientLimiterRate(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	l := newRPCClientLimiter(2, 3, 0)
+	l.timeNow = func() time.Time { return now }
+
+	// The full burst is available to a new client.
+	for i := 0; i < 3; i++ {
+		release, err := l.acquire("127.0.0.1:1000")
+		if err != nil {
+			t.Fatalf("request %d: unexpected error: %v", i, err)
+		}
+		release()
+	}
+
+	// Further requests are limited until enough time passes to refill a
+	// token and the error reports how long to wait.
+	_, err := l.acquire("127.0.0.1:1001")
+	var limitErr *rpcLimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("did not receive expected limit error -- got %v", err)
+	}
+	if limitErr.retryAfter != 500*time.Millisecond {
+		t.Fatalf("unexpected retry after -- got %v, want %v",
+			limitErr.retryAfter, 500*time.Millisecond)
+	}
+
+	// Other clients are not affected.
+	release, err := l.acquire("[::1]:1000")
+	if err != nil {
+		t.Fatalf("unexpected error for other client: %v", err)
+	}
+	release()
+
+	// A token is available once it has been refilled.
+	now = now.Add(500 * time.Millisecond)
+	release, err = l.acquire("127.0.0.1:1002")
+	if err != nil {
+		t.Fatalf("unexpected error after refill: %v", err)
+	}
+	release()
+	if _, err := l.acquire("127.0.0.1:1002"); err == nil {
+		t.Fatal("did not receive expected limit error after refill")
+	}
+
+	info := l.info()
+	if info.RateLimited != 2 || info.ConcurrencyLimited != 0 {
+		t.Fatalf("unexpected totals -- got %d rate limited and %d "+
+			"concurrency limited", info.RateLimited,
+			info.ConcurrencyLimited)
+	}
+	if len(info.Clients) != 2 || info.Clients[0].Address != "127.0.0.1" ||
+		info.Clients[0].Requests != 6 || info.Clients[0].RateLimited != 2 {
+
+		t.Fatalf("unexpected client stats: %+v", info.Clients)
+	}
+
+	// Idle clients with a full bucket are pruned.
+	now = now.Add(rpcLimiterPruneInterval)
+	if _, err := l.acquire("10.0.0.1:1000"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info := l.info(); len(info.Clients) != 1 {
+		t.Fatalf("unexpected number of clients after prune -- got %d, "+
+			"want 1", len(info.Clients))
+	}
+}
+
+// TestRPCClientLimiterConcurrency ensures the client limiter enforces the
+// configured number of concurrent requests per client.
+func TestRPCClientLimiterConcurrency(t *testing.T) {
+	l := newRPCClientLimiter(0, 0, 2)
+
+	release1, err := l.acquire("127.0.0.1:1000")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release2, err := l.acquire("127.0.0.1:1001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = l.acquire("127.0.0.1:1002")
+	var limitErr *rpcLimitError
+	if !errors.As(err, &limitErr) || limitErr.retryAfter != 0 {
+		t.Fatalf("did not receive expected limit error -- got %v", err)
+	}
+
+	// Releasing more than once must not free additional slots.
+	release1()
+	release1()
+	if _, err := l.acquire("127.0.0.1:1003"); err != nil {
+		t.Fatalf("unexpected error after release: %v", err)
+	}
+	if _, err := l.acquire("127.0.0.1:1004"); err == nil {
+		t.Fatal("did not receive expected limit error after double release")
+	}
+	release2()
+
+	if info := l.info(); info.ConcurrencyLimited != 2 {
+		t.Fatalf("unexpected concurrency limited total -- got %d, want 2",
+			info.ConcurrencyLimited)
+	}
+}
+
+// TestRPCClientLimiterDisabled ensures a disabled client limiter never limits
+// requests.
+func TestRPCClientLimiterDisabled(t *testing.T) {
+	l := newRPCClientLimiter(0, 20, 0)
+	if l != nil {
+		t.Fatal("expected nil limiter when all limits are disabled")
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := l.acquire("127.0.0.1:1000"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if info := l.info(); info.Clients == nil {
+		t.Fatal("expected non-nil clients for disabled limiter")
+	}
+}
+
+// TestWhitelistRPCLimits ensures clients connecting from whitelisted addresses
+// are exempt from the maximum number of RPC clients and the request limits
+// while other clients are limited.
+func TestWhitelistRPCLimits(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+	setTestWhitelists(t)
+
+	for _, test := range whitelistAddrTests {
+		now := time.Unix(1600000000, 0)
+		s := &rpcServer{clientLimiter: newRPCClientLimiter(1, 1, 0)}
+		s.clientLimiter.timeNow = func() time.Time { return now }
+
+		// Ensure only clients that are not whitelisted are refused once the
+		// maximum number of clients is reached.
+		s.incrementClients()
+		w := httptest.NewRecorder()
+		gotLimited := s.limitConnections(w, test.addr)
+		if wantLimited := !test.whitelisted; gotLimited != wantLimited {
+			t.Errorf("%q: unexpected connection limit result -- got %v, "+
+				"want %v", test.name, gotLimited, wantLimited)
+			continue
+		}
+		if gotLimited && w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%q: unexpected connection limit status -- got %d, "+
+				"want %d", test.name, w.Code, http.StatusServiceUnavailable)
+			continue
+		}
+
+		// Ensure only clients that are not whitelisted are refused once
+		// their request burst is exhausted.
+		for i := 0; i < 2; i++ {
+			w := httptest.NewRecorder()
+			release, ok := s.limitClient(w, test.addr)
+			wantOK := i == 0 || test.whitelisted
+			if ok != wantOK {
+				t.Errorf("%q: unexpected request %d limit result -- got "+
+					"%v, want %v", test.name, i, ok, wantOK)
+				break
+			}
+			if !ok {
+				if w.Code != http.StatusTooManyRequests {
+					t.Errorf("%q: unexpected request limit status -- "+
+						"got %d, want %d", test.name, w.Code,
+						http.StatusTooManyRequests)
+				}
+				break
+			}
+			release()
+		}
+	}
+}
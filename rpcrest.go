@@ -0,0 +1,362 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrjson/v3"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+)
+
+const (
+	// restPathPrefix is the URL path prefix under which the REST interface
+	// is served.
+	restPathPrefix = "/rest/"
+
+	// restMaxHeaders is the maximum number of headers that may be requested
+	// by a single REST headers query.
+	restMaxHeaders = 2000
+)
+
+// restFormat identifies the encoding of a REST response.
+type restFormat int
+
+const (
+	// restFormatJSON encodes the response as JSON.
+	restFormatJSON restFormat = iota
+
+	// restFormatHex encodes the response as a hex string.
+	restFormatHex
+
+	// restFormatBinary encodes the response as raw bytes.
+	restFormatBinary
+)
+
+// restFormatExtensions maps the file extensions that may be used to select a
+// REST response encoding to the format they select.
+var restFormatExtensions = map[string]restFormat{
+	".json": restFormatJSON,
+	".hex":  restFormatHex,
+	".bin":  restFormatBinary,
+}
+
+// restRequest is a parsed REST request.
+type restRequest struct {
+	resource string
+	params   []string
+	format   restFormat
+}
+
+// errRESTNotFound is returned when a REST request does not name a known
+// resource or the requested object does not exist.
+var errRESTNotFound = errors.New("not found")
+
+// errRESTUnsupportedFormat is returned when a REST resource can't be encoded
+// in the requested format.
+var errRESTUnsupportedFormat = errors.New("unsupported format")
+
+// restHandler describes a callback function used to handle a REST resource.
+// Handlers must return a hex-encoded string when the requested format is
+// either restFormatHex or restFormatBinary.
+type restHandler func(context.Context, *rpcServer, *restRequest) (interface{}, error)
+
+// restRoute describes a REST resource along with the number of path
+// parameters it expects and the JSON-RPC method that provides the equivalent
+// functionality.  A client is only authorized to request a REST resource when
+// its credentials authorize the equivalent JSON-RPC method.
+type restRoute struct {
+	numParams int
+	method    string
+	handler   restHandler
+}
+
+// restRoutes maps REST resource names to their routes.
+var restRoutes = map[string]restRoute{
+	"block":             {1, "getblock", restBlock},
+	"blockhashbyheight": {1, "getblockhash", restBlockHashByHeight},
+	"chaininfo":         {0, "getblockchaininfo", restChainInfo},
+	"headers":           {2, "getblockheader", restHeaders},
+	"mempool":           {1, "getrawmempool", restMempool},
+	"tx":                {1, "getrawtransaction", restTx},
+	"utxo":              {2, "gettxout", restUTXO},
+}
+
+// parseRESTPath parses the provided URL path, which must include the REST
+// prefix, into a REST request.  All requests must end with a file extension
+// that selects the encoding of the response, for example
+// /rest/block/<hash>.json.
+func parseRESTPath(urlPath string) (*restRequest, error) {
+	if !strings.HasPrefix(urlPath, restPathPrefix) {
+		return nil, errRESTNotFound
+	}
+	urlPath = strings.TrimPrefix(urlPath, restPathPrefix)
+
+	ext := path.Ext(urlPath)
+	format, ok := restFormatExtensions[ext]
+	if !ok {
+		return nil, fmt.Errorf("%w %q -- must be one of .json, .hex, "+
+			"or .bin", errRESTUnsupportedFormat, ext)
+	}
+	parts := strings.Split(strings.TrimSuffix(urlPath, ext), "/")
+
+	route, ok := restRoutes[parts[0]]
+	if !ok || len(parts)-1 != route.numParams {
+		return nil, errRESTNotFound
+	}
+	return &restRequest{
+		resource: parts[0],
+		params:   parts[1:],
+		format:   format,
+	}, nil
+}
+
+// restStatusCode returns the HTTP status code that corresponds to the
+// provided error returned by a REST handler.
+func restStatusCode(err error) int {
+	switch {
+	case errors.Is(err, errRESTNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errRESTUnsupportedFormat):
+		return http.StatusBadRequest
+	}
+
+	var rpcErr *dcrjson.RPCError
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case dcrjson.ErrRPCBlockNotFound:
+			return http.StatusNotFound
+		case dcrjson.ErrRPCInvalidParameter, dcrjson.ErrRPCDecodeHexString,
+			dcrjson.ErrRPCInvalidParams.Code:
+			return http.StatusBadRequest
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// restVerbose returns whether the provided REST request asks for a JSON
+// response and therefore requires the verbose form of RPC results.
+func restVerbose(req *restRequest) bool {
+	return req.format == restFormatJSON
+}
+
+// restBlock handles /rest/block/<hash>.
+func restBlock(ctx context.Context, s *rpcServer, req *restRequest) (interface{}, error) {
+	verbose := restVerbose(req)
+	return handleGetBlock(ctx, s, &types.GetBlockCmd{
+		Hash:      req.params[0],
+		Verbose:   &verbose,
+		VerboseTx: &verbose,
+	})
+}
+
+// restBlockHashByHeight handles /rest/blockhashbyheight/<height>.
+func restBlockHashByHeight(ctx context.Context, s *rpcServer, req *restRequest) (interface{}, error) {
+	if req.format == restFormatBinary {
+		return nil, errRESTUnsupportedFormat
+	}
+	height, err := strconv.ParseInt(req.params[0], 10, 64)
+	if err != nil {
+		return nil, rpcInvalidError("Invalid height %q", req.params[0])
+	}
+	result, err := handleGetBlockHash(ctx, s, &types.GetBlockHashCmd{
+		Index: height,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("block height %d %w", height,
+			errRESTNotFound)
+	}
+	if req.format == restFormatJSON {
+		return &types.GetBestBlockResult{
+			Hash:   result.(string),
+			Height: height,
+		}, nil
+	}
+	return result, nil
+}
+
+// restChainInfo handles /rest/chaininfo.
+func restChainInfo(ctx context.Context, s *rpcServer, req *restRequest) (interface{}, error) {
+	if req.format != restFormatJSON {
+		return nil, errRESTUnsupportedFormat
+	}
+	return handleGetBlockchainInfo(ctx, s, nil)
+}
+
+// restHeaders handles /rest/headers/<count>/<hash> by returning up to count
+// main chain headers starting with the header for the provided hash.
+func restHeaders(ctx context.Context, s *rpcServer, req *restRequest) (interface{}, error) {
+	count, err := strconv.Atoi(req.params[0])
+	if err != nil || count < 1 || count > restMaxHeaders {
+		return nil, rpcInvalidError("Invalid header count %q -- must "+
+			"be between 1 and %d", req.params[0], restMaxHeaders)
+	}
+
+	chain := s.cfg.Chain
+	verbose := restVerbose(req)
+	hashStr := req.params[1]
+	headers := make([]interface{}, 0, count)
+	var hexHeaders strings.Builder
+	for len(headers) < count {
+		result, err := handleGetBlockHeader(ctx, s,
+			&types.GetBlockHeaderCmd{Hash: hashStr, Verbose: &verbose})
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, result)
+		if !verbose {
+			hexHeaders.WriteString(result.(string))
+		}
+
+		// Move to the next header in the main chain, if any.
+		hash, err := chainhash.NewHashFromStr(hashStr)
+		if err != nil {
+			return nil, rpcDecodeHexError(hashStr)
+		}
+		if !chain.MainChainHasBlock(hash) {
+			break
+		}
+		header, err := chain.HeaderByHash(hash)
+		if err != nil {
+			return nil, rpcInternalError(err.Error(), "Failed to "+
+				"fetch header")
+		}
+		if int64(header.Height) >= chain.BestSnapshot().Height {
+			break
+		}
+		next, err := chain.BlockHashByHeight(int64(header.Height) + 1)
+		if err != nil {
+			break
+		}
+		hashStr = next.String()
+	}
+
+	if !verbose {
+		return hexHeaders.String(), nil
+	}
+	return headers, nil
+}
+
+// restMempool handles /rest/mempool/info and /rest/mempool/contents.
+func restMempool(ctx context.Context, s *rpcServer, req *restRequest) (interface{}, error) {
+	if req.format != restFormatJSON {
+		return nil, errRESTUnsupportedFormat
+	}
+	switch req.params[0] {
+	case "info":
+		return handleGetMempoolInfo(ctx, s, nil)
+	case "contents":
+		verbose := true
+		return handleGetRawMempool(ctx, s, &types.GetRawMempoolCmd{
+			Verbose: &verbose,
+		})
+	}
+	return nil, errRESTNotFound
+}
+
+// restTx handles /rest/tx/<hash>.
+func restTx(ctx context.Context, s *rpcServer, req *restRequest) (interface{}, error) {
+	verbose := 0
+	if restVerbose(req) {
+		verbose = 1
+	}
+	return handleGetRawTransaction(ctx, s, &types.GetRawTransactionCmd{
+		Txid:    req.params[0],
+		Verbose: &verbose,
+	})
+}
+
+// restUTXO handles /rest/utxo/<hash>/<index>.  The mempool is taken into
+// account when determining whether or not the output is unspent.
+func restUTXO(ctx context.Context, s *rpcServer, req *restRequest) (interface{}, error) {
+	if req.format != restFormatJSON {
+		return nil, errRESTUnsupportedFormat
+	}
+	vout, err := strconv.ParseUint(req.params[1], 10, 32)
+	if err != nil {
+		return nil, rpcInvalidError("Invalid output index %q",
+			req.params[1])
+	}
+	includeMempool := true
+	result, err := handleGetTxOut(ctx, s, &types.GetTxOutCmd{
+		Txid:           req.params[0],
+		Vout:           uint32(vout),
+		IncludeMempool: &includeMempool,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errRESTNotFound
+	}
+	return result, nil
+}
+
+// handleREST serves a read-only REST request on behalf of a client with the
+// provided permission by dispatching it to the handler for the requested
+// resource and writing the result in the requested format.
+func (s *rpcServer) handleREST(ctx context.Context, w http.ResponseWriter, r *http.Request, perm rpcPermission) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 Method not allowed.",
+			http.StatusMethodNotAllowed)
+		return
+	}
+
+	req, err := parseRESTPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), restStatusCode(err))
+		return
+	}
+	route := restRoutes[req.resource]
+	if !perm.allows(route.method) {
+		http.Error(w, fmt.Sprintf("%s user not authorized for this "+
+			"resource", perm), http.StatusForbidden)
+		return
+	}
+	result, err := route.handler(ctx, s, req)
+	if err != nil {
+		if restStatusCode(err) == http.StatusInternalServerError {
+			rpcsLog.Errorf("REST request %s failed: %v", r.URL.Path,
+				err)
+		}
+		http.Error(w, err.Error(), restStatusCode(err))
+		return
+	}
+
+	var reply []byte
+	switch req.format {
+	case restFormatJSON:
+		w.Header().Set("Content-Type", "application/json")
+		reply, err = json.Marshal(result)
+		reply = append(reply, '\n')
+
+	case restFormatHex:
+		w.Header().Set("Content-Type", "text/plain")
+		reply = []byte(result.(string) + "\n")
+
+	case restFormatBinary:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		reply, err = hex.DecodeString(result.(string))
+	}
+	if err != nil {
+		rpcsLog.Errorf("Failed to encode REST reply: %v", err)
+		http.Error(w, "500 Internal server error.",
+			http.StatusInternalServerError)
+		return
+	}
+	if _, err := w.Write(reply); err != nil {
+		rpcsLog.Errorf("Failed to write REST reply: %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/dcrjson/v3"
+)
+
+// TestParseRESTPath ensures REST request paths are parsed into the expected
+// resource, parameters, and format.
+func TestParseRESTPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    *restRequest
+		wantErr error
+	}{{
+		name: "block json",
+		path: "/rest/block/abcd.json",
+		want: &restRequest{
+			resource: "block",
+			params:   []string{"abcd"},
+			format:   restFormatJSON,
+		},
+	}, {
+		name: "headers binary",
+		path: "/rest/headers/5/abcd.bin",
+		want: &restRequest{
+			resource: "headers",
+			params:   []string{"5", "abcd"},
+			format:   restFormatBinary,
+		},
+	}, {
+		name: "chaininfo no params",
+		path: "/rest/chaininfo.json",
+		want: &restRequest{
+			resource: "chaininfo",
+			params:   []string{},
+			format:   restFormatJSON,
+		},
+	}, {
+		name: "tx hex",
+		path: "/rest/tx/abcd.hex",
+		want: &restRequest{
+			resource: "tx",
+			params:   []string{"abcd"},
+			format:   restFormatHex,
+		},
+	}, {
+		name:    "missing extension",
+		path:    "/rest/block/abcd",
+		wantErr: errRESTUnsupportedFormat,
+	}, {
+		name:    "unknown extension",
+		path:    "/rest/block/abcd.xml",
+		wantErr: errRESTUnsupportedFormat,
+	}, {
+		name:    "unknown resource",
+		path:    "/rest/wallet/abcd.json",
+		wantErr: errRESTNotFound,
+	}, {
+		name:    "too many params",
+		path:    "/rest/block/abcd/efgh.json",
+		wantErr: errRESTNotFound,
+	}, {
+		name:    "too few params",
+		path:    "/rest/utxo/abcd.json",
+		wantErr: errRESTNotFound,
+	}, {
+		name:    "wrong prefix",
+		path:    "/ws/block/abcd.json",
+		wantErr: errRESTNotFound,
+	}}
+
+	for _, test := range tests {
+		got, err := parseRESTPath(test.path)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("%q: unexpected error -- got %v, want %v", test.name,
+				err, test.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: mismatched request -- got %+v, want %+v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestRESTStatusCode ensures errors returned by REST handlers map to the
+// expected HTTP status codes.
+func TestRESTStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{{
+		name: "not found",
+		err:  fmt.Errorf("block height 10 %w", errRESTNotFound),
+		want: http.StatusNotFound,
+	}, {
+		name: "unsupported format",
+		err:  errRESTUnsupportedFormat,
+		want: http.StatusBadRequest,
+	}, {
+		name: "block not found",
+		err:  &dcrjson.RPCError{Code: dcrjson.ErrRPCBlockNotFound},
+		want: http.StatusNotFound,
+	}, {
+		name: "invalid parameter",
+		err:  rpcInvalidError("bad"),
+		want: http.StatusBadRequest,
+	}, {
+		name: "decode hex",
+		err:  rpcDecodeHexError("zz"),
+		want: http.StatusBadRequest,
+	}, {
+		name: "internal",
+		err:  rpcInternalError("oops", ""),
+		want: http.StatusInternalServerError,
+	}}
+
+	for _, test := range tests {
+		if got := restStatusCode(test.err); got != test.want {
+			t.Errorf("%q: unexpected status -- got %d, want %d",
+				test.name, got, test.want)
+		}
+	}
+}
@@ -9,8 +9,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/elliptic"
-	"crypto/sha256"
-	"crypto/subtle"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
@@ -32,6 +31,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode"
 
 	"github.com/gorilla/websocket"
 
@@ -97,6 +97,24 @@ const (
 	// sstxCommitmentString is the string to insert when a verbose
 	// transaction output's pkscript type is a ticket commitment.
 	sstxCommitmentString = "sstxcommitment"
+
+	// maxCFiltersV2PerRequest is the maximum number of version 2 filters the
+	// getcfiltersv2 RPC will return in a single request.
+	maxCFiltersV2PerRequest = 1000
+
+	// maxScriptStatsBlocks is the maximum number of blocks the getscriptstats
+	// RPC will analyze in a single request.
+	maxScriptStatsBlocks = 10000
+
+	// maxTicketPoolInfoBuckets is the maximum number of purchase price
+	// buckets the getticketpoolinfo RPC will distribute the live tickets
+	// into.
+	maxTicketPoolInfoBuckets = 100
+
+	// maxVoteStatsIntervals is the maximum number of stake version
+	// intervals the getvotestats RPC will return statistics for in a single
+	// request.
+	maxVoteStatsIntervals = 50
 )
 
 var (
@@ -135,12 +153,15 @@ type commandHandler func(context.Context, *rpcServer, interface{}) (interface{},
 var rpcHandlers map[types.Method]commandHandler
 var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"addnode":               handleAddNode,
+	"advancetime":           handleAdvanceTime,
+	"compactdatabase":       handleCompactDatabase,
 	"createrawsstx":         handleCreateRawSStx,
 	"createrawssrtx":        handleCreateRawSSRtx,
 	"createrawtransaction":  handleCreateRawTransaction,
 	"debuglevel":            handleDebugLevel,
 	"decoderawtransaction":  handleDecodeRawTransaction,
 	"decodescript":          handleDecodeScript,
+	"deleteaddress":         handleDeleteAddress,
 	"estimatefee":           handleEstimateFee,
 	"estimatesmartfee":      handleEstimateSmartFee,
 	"estimatestakediff":     handleEstimateStakeDiff,
@@ -151,27 +172,39 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"existslivetickets":     handleExistsLiveTickets,
 	"existsmempooltxs":      handleExistsMempoolTxs,
 	"existsmissedtickets":   handleExistsMissedTickets,
+	"forcevote":             handleForceVote,
 	"generate":              handleGenerate,
+	"generatetoaddress":     handleGenerateToAddress,
 	"getaddednodeinfo":      handleGetAddedNodeInfo,
+	"getaddressutxos":       handleGetAddressUTXOs,
+	"getaddrmaninfo":        handleGetAddrManInfo,
 	"getbestblock":          handleGetBestBlock,
 	"getbestblockhash":      handleGetBestBlockHash,
 	"getblock":              handleGetBlock,
+	"getblockbytime":        handleGetBlockByTime,
 	"getblockchaininfo":     handleGetBlockchainInfo,
 	"getblockcount":         handleGetBlockCount,
 	"getblockhash":          handleGetBlockHash,
 	"getblockheader":        handleGetBlockHeader,
+	"getblocksbytime":       handleGetBlocksByTime,
 	"getblocksubsidy":       handleGetBlockSubsidy,
+	"getblocktemplate":      handleGetBlockTemplate,
+	"getbuildinfo":          handleGetBuildInfo,
 	"getcfilter":            handleGetCFilter,
 	"getcfilterheader":      handleGetCFilterHeader,
 	"getcfilterv2":          handleGetCFilterV2,
+	"getcfilterv2headers":   handleGetCFilterV2Headers,
+	"getcfiltersv2":         handleGetCFiltersV2,
 	"getchaintips":          handleGetChainTips,
 	"getcoinsupply":         handleGetCoinSupply,
 	"getconnectioncount":    handleGetConnectionCount,
 	"getcurrentnet":         handleGetCurrentNet,
+	"getdatabaseinfo":       handleGetDatabaseInfo,
 	"getdifficulty":         handleGetDifficulty,
 	"getgenerate":           handleGetGenerate,
 	"gethashespersec":       handleGetHashesPerSec,
 	"getheaders":            handleGetHeaders,
+	"getindexinfo":          handleGetIndexInfo,
 	"getinfo":               handleGetInfo,
 	"getmempoolinfo":        handleGetMempoolInfo,
 	"getmininginfo":         handleGetMiningInfo,
@@ -181,11 +214,17 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"getpeerinfo":           handleGetPeerInfo,
 	"getrawmempool":         handleGetRawMempool,
 	"getrawtransaction":     handleGetRawTransaction,
+	"getrpclimitinfo":       handleGetRPCLimitInfo,
+	"getscriptstats":        handleGetScriptStats,
+	"getspentinfo":          handleGetSpentInfo,
 	"getstakedifficulty":    handleGetStakeDifficulty,
 	"getstakeversioninfo":   handleGetStakeVersionInfo,
 	"getstakeversions":      handleGetStakeVersions,
+	"getsyncstatus":         handleGetSyncStatus,
+	"getticketpoolinfo":     handleGetTicketPoolInfo,
 	"getticketpoolvalue":    handleGetTicketPoolValue,
 	"getvoteinfo":           handleGetVoteInfo,
+	"getvotestats":          handleGetVoteStats,
 	"gettxout":              handleGetTxOut,
 	"gettxoutsetinfo":       handleGetTxOutSetInfo,
 	"getwork":               handleGetWork,
@@ -193,11 +232,14 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"livetickets":           handleLiveTickets,
 	"missedtickets":         handleMissedTickets,
 	"node":                  handleNode,
+	"pausegenerate":         handlePauseGenerate,
 	"ping":                  handlePing,
+	"profileserver":         handleProfileServer,
 	"regentemplate":         handleRegenTemplate,
 	"searchrawtransactions": handleSearchRawTransactions,
 	"sendrawtransaction":    handleSendRawTransaction,
 	"setgenerate":           handleSetGenerate,
+	"setpolicy":             handleSetPolicy,
 	"stop":                  handleStop,
 	"submitblock":           handleSubmitBlock,
 	"ticketfeeinfo":         handleTicketFeeInfo,
@@ -208,6 +250,7 @@ var rpcHandlersBeforeInit = map[types.Method]commandHandler{
 	"verifychain":           handleVerifyChain,
 	"verifymessage":         handleVerifyMessage,
 	"version":               handleVersion,
+	"writeprofile":          handleWriteProfile,
 }
 
 // list of commands that we recognize, but for which dcrd has no support because
@@ -292,6 +335,7 @@ var rpcLimited = map[string]struct{}{
 	"notifyblocks":          {},
 	"notifynewtransactions": {},
 	"notifyreceived":        {},
+	"notifyutxos":           {},
 	"notifyspent":           {},
 	"rescan":                {},
 	"session":               {},
@@ -317,32 +361,43 @@ var rpcLimited = map[string]struct{}{
 	"existslivetickets":     {},
 	"existsmempooltxs":      {},
 	"existsmissedtickets":   {},
+	"getaddressutxos":       {},
 	"getbestblock":          {},
 	"getbestblockhash":      {},
 	"getblock":              {},
+	"getblockbytime":        {},
 	"getblockchaininfo":     {},
 	"getblockcount":         {},
 	"getblockhash":          {},
 	"getblockheader":        {},
+	"getblocksbytime":       {},
 	"getblocksubsidy":       {},
+	"getbuildinfo":          {},
 	"getcfilter":            {},
 	"getcfilterv2":          {},
+	"getcfilterv2headers":   {},
+	"getcfiltersv2":         {},
 	"getchaintips":          {},
 	"getcoinsupply":         {},
 	"getcurrentnet":         {},
 	"getdifficulty":         {},
 	"getheaders":            {},
+	"getindexinfo":          {},
 	"getinfo":               {},
 	"getnettotals":          {},
 	"getnetworkhashps":      {},
 	"getnetworkinfo":        {},
 	"getrawmempool":         {},
+	"getspentinfo":          {},
 	"getstakedifficulty":    {},
 	"getstakeversioninfo":   {},
 	"getstakeversions":      {},
+	"getsyncstatus":         {},
+	"getticketpoolinfo":     {},
 	"getrawtransaction":     {},
 	"gettxout":              {},
 	"getvoteinfo":           {},
+	"getvotestats":          {},
 	"livetickets":           {},
 	"missedtickets":         {},
 	"regentemplate":         {},
@@ -594,6 +649,84 @@ func messageToHex(msg wire.Message) (string, error) {
 	return hex.EncodeToString(buf.Bytes()), nil
 }
 
+// databaseInfo returns details about the database type and storage used by the
+// provided database or an RPC error when the database does not support storage
+// maintenance.
+func databaseInfo(db database.DB) (*types.GetDatabaseInfoResult, error) {
+	maintainer, ok := db.(database.Maintainer)
+	if !ok {
+		return nil, rpcMiscError(fmt.Sprintf("database type %q does not "+
+			"support storage maintenance", db.Type()))
+	}
+	stats, err := maintainer.StorageStats()
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not obtain database storage statistics")
+	}
+	return &types.GetDatabaseInfoResult{
+		Type:                db.Type(),
+		MetadataFiles:       stats.MetadataFiles,
+		MetadataSize:        stats.MetadataSize,
+		MetadataLiveSize:    stats.MetadataLiveSize,
+		MetadataGarbageSize: stats.MetadataGarbageSize,
+		BlockFiles:          stats.BlockFiles,
+		BlockFilesSize:      stats.BlockFilesSize,
+	}, nil
+}
+
+// testNetworkOnlyError returns an error suitable for returning from RPC
+// handlers that are only available on the simulation and regression test
+// networks when the server is running on any other network.  Otherwise, it
+// returns nil.
+func testNetworkOnlyError(s *rpcServer, method string) error {
+	net := s.cfg.ChainParams.Net
+	if net == wire.SimNet || net == wire.RegNet {
+		return nil
+	}
+	return &dcrjson.RPCError{
+		Code: dcrjson.ErrRPCMisc,
+		Message: fmt.Sprintf("%s is only available on the simnet and "+
+			"regnet networks, not %s", method, s.cfg.ChainParams.Name),
+	}
+}
+
+// handleAdvanceTime implements the advancetime command.
+func handleAdvanceTime(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	if err := testNetworkOnlyError(s, "advancetime"); err != nil {
+		return nil, err
+	}
+
+	c := cmd.(*types.AdvanceTimeCmd)
+	if c.Seconds <= 0 {
+		return nil, rpcInvalidError("Invalid number of seconds %d: must be "+
+			"greater than 0", c.Seconds)
+	}
+	timeSource, ok := s.cfg.TimeSource.(*mockTimeSource)
+	if !ok {
+		return nil, rpcInternalError("Time source does not support being "+
+			"advanced", "Configuration")
+	}
+
+	adjustedTime := timeSource.Advance(time.Duration(c.Seconds) * time.Second)
+	rpcsLog.Infof("Advanced adjusted time by %v to %v",
+		time.Duration(c.Seconds)*time.Second, adjustedTime)
+	return adjustedTime.Unix(), nil
+}
+
+// handleCompactDatabase implements the compactdatabase command.
+func handleCompactDatabase(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	maintainer, ok := s.cfg.DB.(database.Maintainer)
+	if !ok {
+		return nil, rpcMiscError(fmt.Sprintf("database type %q does not "+
+			"support storage maintenance", s.cfg.DB.Type()))
+	}
+	if err := maintainer.Compact(); err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not compact database")
+	}
+	return databaseInfo(s.cfg.DB)
+}
+
 // handleCreateRawTransaction handles createrawtransaction commands.
 func handleCreateRawTransaction(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.CreateRawTransactionCmd)
@@ -1093,6 +1226,46 @@ func createVinList(mtx *wire.MsgTx) []types.Vin {
 	return vinList
 }
 
+// addVinPrevOuts populates the previous output details of the passed JSON
+// objects for the inputs of the given transaction as created by createVinList
+// using the provided source of previous scripts.  The value of each previous
+// output is taken from the amount of the input which is committed to by the
+// fraud proof.
+func addVinPrevOuts(vinList []types.Vin, mtx *wire.MsgTx, prevScripts indexers.PrevScripter, chainParams *chaincfg.Params) {
+	// Coinbase transactions do not spend any previous outputs.
+	if standalone.IsCoinBaseTx(mtx) {
+		return
+	}
+
+	isSSGen := stake.IsSSGen(mtx)
+	for i, txIn := range mtx.TxIn {
+		// The null input of a stakebase does not spend a previous output.
+		if isSSGen && i == 0 {
+			continue
+		}
+
+		version, script, ok := prevScripts.PrevScript(&txIn.PreviousOutPoint)
+		if !ok {
+			continue
+		}
+
+		// Ignore the error here since an error means the script couldn't
+		// parse and there is no additional information about it anyways.
+		scriptClass, addrs, _, _ := txscript.ExtractPkScriptAddrs(version,
+			script, chainParams)
+		encodedAddrs := make([]string, len(addrs))
+		for j, addr := range addrs {
+			encodedAddrs[j] = addr.Address()
+		}
+
+		vinList[i].PrevOut = &types.PrevOut{
+			Addresses: encodedAddrs,
+			Value:     dcrutil.Amount(txIn.ValueIn).ToCoin(),
+			Type:      scriptClass.String(),
+		}
+	}
+}
+
 // createVoutList returns a slice of JSON objects for the outputs of the passed
 // transaction.
 func createVoutList(mtx *wire.MsgTx, chainParams *chaincfg.Params, filterAddrMap map[string]struct{}) []types.Vout {
@@ -1251,6 +1424,23 @@ func handleDecodeRawTransaction(_ context.Context, s *rpcServer, cmd interface{}
 	return txReply, nil
 }
 
+// handleDeleteAddress handles deleteaddress commands.
+func handleDeleteAddress(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.DeleteAddressCmd)
+
+	addr := normalizeAddress(c.Addr, s.cfg.ChainParams.DefaultPort)
+	na, err := s.cfg.AddrManager.DeserializeNetAddress(addr)
+	if err != nil {
+		return nil, rpcInvalidError("Invalid address %q: %v", c.Addr, err)
+	}
+	if !s.cfg.AddrManager.DeleteAddress(na) {
+		return nil, rpcInvalidError("Address %s is not known", addr)
+	}
+
+	// no data returned unless an error.
+	return nil, nil
+}
+
 // handleDecodeScript handles decodescript commands.
 func handleDecodeScript(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.DecodeScriptCmd)
@@ -1309,7 +1499,7 @@ func handleDecodeScript(_ context.Context, s *rpcServer, cmd interface{}) (inter
 // TODO this is a very basic implementation.  It should be
 // modified to match the bitcoin-core one.
 func handleEstimateFee(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
-	return cfg.minRelayTxFee.ToCoin(), nil
+	return s.cfg.TxMemPool.MinRelayTxFee().ToCoin(), nil
 }
 
 // handleEstimateSmartFee implements the estimatesmartfee command.
@@ -1337,6 +1527,48 @@ func handleEstimateSmartFee(_ context.Context, s *rpcServer, cmd interface{}) (i
 	return fee.ToCoin(), nil
 }
 
+// estimateTicketPurchases estimates the number of tickets that will be
+// purchased over the provided number of remaining blocks given the number of
+// tickets purchased in each of the sampled blocks.  Purchases in each block are
+// treated as independent of one another, so the expected number is the mean of
+// the samples scaled by the remaining blocks, while the lower and upper bounds
+// are the scenarios where the total purchases deviate from that by two
+// standard deviations (roughly a 95% confidence interval).  All results are
+// clamped to the range of possible purchases.
+func estimateTicketPurchases(samples []uint8, remaining, maxPerBlock int64) (int64, int64, int64) {
+	if len(samples) == 0 || remaining <= 0 {
+		return 0, 0, 0
+	}
+
+	var sum float64
+	for _, sample := range samples {
+		sum += float64(sample)
+	}
+	mean := sum / float64(len(samples))
+	var sumSquares float64
+	for _, sample := range samples {
+		diff := float64(sample) - mean
+		sumSquares += diff * diff
+	}
+	stdDev := math.Sqrt(sumSquares / float64(len(samples)))
+
+	clamp := func(tickets float64) int64 {
+		maxTickets := float64(maxPerBlock * remaining)
+		if tickets < 0 {
+			return 0
+		}
+		if tickets > maxTickets {
+			return int64(maxTickets)
+		}
+		return int64(tickets)
+	}
+	expected := mean * float64(remaining)
+	deviation := 2 * stdDev * math.Sqrt(float64(remaining))
+	return clamp(math.Floor(expected - deviation)),
+		clamp(math.Floor(expected)),
+		clamp(math.Ceil(expected + deviation))
+}
+
 // handleEstimateStakeDiff implements the estimatestakediff command.
 func handleEstimateStakeDiff(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.EstimateStakeDiffCmd)
@@ -1356,34 +1588,49 @@ func handleEstimateStakeDiff(_ context.Context, s *rpcServer, cmd interface{}) (
 			"estimate next maximum stake difficulty")
 	}
 
-	// The expected stake difficulty. Average the number of fresh stake
-	// since the last retarget to get the number of tickets per block,
-	// then use that to estimate the next stake difficulty.
+	// The expected stake difficulty along with its lower and upper bounds.
+	// The number of tickets purchased per block over the most recent full
+	// stake difficulty window is used to simulate the purchases over the
+	// remaining blocks of the current window.  A full window is sampled
+	// regardless of where the current window is at in order to avoid the
+	// wild swings that result from only having a few samples near the start
+	// of a window.
 	params := s.cfg.ChainParams
 	bestHeight := chain.BestSnapshot().Height
-	lastAdjustment := (bestHeight / params.StakeDiffWindowSize) *
-		params.StakeDiffWindowSize
 	nextAdjustment := ((bestHeight / params.StakeDiffWindowSize) + 1) *
 		params.StakeDiffWindowSize
-	totalTickets := 0
-	for i := lastAdjustment; i <= bestHeight; i++ {
+	sampleStart := bestHeight - params.StakeDiffWindowSize + 1
+	if sampleStart < 0 {
+		sampleStart = 0
+	}
+	samples := make([]uint8, 0, bestHeight-sampleStart+1)
+	for i := sampleStart; i <= bestHeight; i++ {
 		bh, err := chain.HeaderByHeight(i)
 		if err != nil {
 			return nil, rpcInternalError(err.Error(), "Could not "+
 				"estimate next stake difficulty")
 		}
-		totalTickets += int(bh.FreshStake)
+		samples = append(samples, bh.FreshStake)
+	}
+	remaining := nextAdjustment - bestHeight - 1
+	lowerTickets, expectedTickets, upperTickets := estimateTicketPurchases(
+		samples, remaining, int64(params.MaxFreshStakePerBlock))
+	lower, err := chain.EstimateNextStakeDifficulty(lowerTickets, false)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not "+
+			"estimate next lower bound stake difficulty")
 	}
-	blocksSince := float64(bestHeight - lastAdjustment + 1)
-	remaining := float64(nextAdjustment - bestHeight - 1)
-	averagePerBlock := float64(totalTickets) / blocksSince
-	expectedTickets := int64(math.Floor(averagePerBlock * remaining))
 	expected, err := chain.EstimateNextStakeDifficulty(expectedTickets,
 		false)
 	if err != nil {
 		return nil, rpcInternalError(err.Error(), "Could not "+
 			"estimate next stake difficulty")
 	}
+	upper, err := chain.EstimateNextStakeDifficulty(upperTickets, false)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not "+
+			"estimate next upper bound stake difficulty")
+	}
 
 	// User-specified stake difficulty, if they asked for one.
 	var userEstFltPtr *float64
@@ -1402,6 +1649,8 @@ func handleEstimateStakeDiff(_ context.Context, s *rpcServer, cmd interface{}) (
 		Min:      dcrutil.Amount(min).ToCoin(),
 		Max:      dcrutil.Amount(max).ToCoin(),
 		Expected: dcrutil.Amount(expected).ToCoin(),
+		Lower:    dcrutil.Amount(lower).ToCoin(),
+		Upper:    dcrutil.Amount(upper).ToCoin(),
 		User:     userEstFltPtr,
 	}, nil
 }
@@ -1612,6 +1861,28 @@ func handleExistsMempoolTxs(_ context.Context, s *rpcServer, cmd interface{}) (i
 	return hex.EncodeToString([]byte(set)), nil
 }
 
+// handleForceVote implements the forcevote command.
+func handleForceVote(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	if err := testNetworkOnlyError(s, "forcevote"); err != nil {
+		return nil, err
+	}
+
+	c := cmd.(*types.ForceVoteCmd)
+	err := s.cfg.Chain.ForceDeploymentChoice(c.AgendaID, c.ChoiceID)
+	if err != nil {
+		var dErr blockchain.DeploymentError
+		var cErr blockchain.ChoiceError
+		if errors.As(err, &dErr) || errors.As(err, &cErr) {
+			return nil, rpcInvalidError("%v", err)
+		}
+		return nil, rpcInternalError(err.Error(), "Could not force vote")
+	}
+
+	rpcsLog.Infof("Forced the outcome of agenda %q to choice %q", c.AgendaID,
+		c.ChoiceID)
+	return nil, nil
+}
+
 // handleGenerate handles generate commands.
 func handleGenerate(ctx context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	// Respond with an error if there are no addresses to pay the
@@ -1643,7 +1914,7 @@ func handleGenerate(ctx context.Context, s *rpcServer, cmd interface{}) (interfa
 	// Create a reply
 	reply := make([]string, c.NumBlocks)
 
-	blockHashes, err := s.cfg.CPUMiner.GenerateNBlocks(ctx, c.NumBlocks)
+	blockHashes, err := s.cfg.CPUMiner.GenerateNBlocks(ctx, c.NumBlocks, nil)
 	if err != nil {
 		return nil, rpcInternalError(err.Error(), "Could not generate blocks")
 	}
@@ -1657,6 +1928,44 @@ func handleGenerate(ctx context.Context, s *rpcServer, cmd interface{}) (interfa
 	return reply, nil
 }
 
+// handleGenerateToAddress handles generatetoaddress commands.
+func handleGenerateToAddress(ctx context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	if err := testNetworkOnlyError(s, "generatetoaddress"); err != nil {
+		return nil, err
+	}
+
+	c := cmd.(*types.GenerateToAddressCmd)
+	if c.NumBlocks == 0 {
+		return nil, rpcInvalidError("Invalid number of blocks")
+	}
+
+	// Decode the provided address.  This also ensures the network encoded
+	// with the address matches the network the server is currently on.
+	addr, err := dcrutil.DecodeAddress(c.Address, s.cfg.ChainParams)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v", err)
+	}
+
+	// Ensure the address is one of the supported types.
+	switch addr.(type) {
+	case *dcrutil.AddressPubKeyHash:
+	case *dcrutil.AddressScriptHash:
+	default:
+		return nil, rpcAddressKeyError("Invalid type: %T", addr)
+	}
+
+	blockHashes, err := s.cfg.CPUMiner.GenerateNBlocks(ctx, c.NumBlocks, addr)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not generate blocks")
+	}
+
+	reply := make([]string, 0, len(blockHashes))
+	for _, hash := range blockHashes {
+		reply = append(reply, hash.String())
+	}
+	return reply, nil
+}
+
 // handleGetAddedNodeInfo handles getaddednodeinfo commands.
 func handleGetAddedNodeInfo(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetAddedNodeInfoCmd)
@@ -1737,6 +2046,282 @@ func handleGetAddedNodeInfo(_ context.Context, s *rpcServer, cmd interface{}) (i
 	return results, nil
 }
 
+// addressOutputs returns the indices of the outputs of the passed transaction
+// that pay to the provided encoded address.
+func addressOutputs(mtx *wire.MsgTx, encodedAddr string, chainParams *chaincfg.Params) []uint32 {
+	var outputs []uint32
+	for i, txOut := range mtx.TxOut {
+		// Ignore the error here since an error means the script couldn't
+		// parse and there is no additional information about it anyways.
+		_, addrs, _, _ := txscript.ExtractPkScriptAddrs(txOut.Version,
+			txOut.PkScript, chainParams)
+		for _, addr := range addrs {
+			if addr.Address() == encodedAddr {
+				outputs = append(outputs, uint32(i))
+				break
+			}
+		}
+	}
+	return outputs
+}
+
+// handleGetAddressUTXOs implements the getaddressutxos command.
+func handleGetAddressUTXOs(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	// Respond with an error if the address index is not enabled.
+	addrIndex := s.cfg.AddrIndex
+	if addrIndex == nil {
+		return nil, rpcInternalError("Address index must be "+
+			"enabled (--addrindex)", "Configuration")
+	}
+
+	// Attempt to decode the supplied address.  This also ensures the network
+	// encoded with the address matches the network the server is currently on.
+	c := cmd.(*types.GetAddressUTXOsCmd)
+	params := s.cfg.ChainParams
+	addr, err := dcrutil.DecodeAddress(c.Address, params)
+	if err != nil {
+		return nil, rpcAddressKeyError("Could not decode address: %v",
+			err)
+	}
+	encodedAddr := addr.Address()
+
+	// Override the default number of requested entries if needed.  Also,
+	// just return now if the number of requested entries is zero to avoid
+	// extra work.
+	numRequested := 100
+	if c.Count != nil {
+		numRequested = *c.Count
+		if numRequested < 0 {
+			numRequested = 1
+		}
+	}
+	results := make([]types.GetAddressUTXOsResult, 0, numRequested)
+	if numRequested == 0 {
+		return results, nil
+	}
+
+	// Override the default number of entries to skip if needed.
+	var numToSkip int
+	if c.Skip != nil {
+		numToSkip = *c.Skip
+		if numToSkip < 0 {
+			numToSkip = 0
+		}
+	}
+
+	// Load the unconfirmed transactions that involve the address when the
+	// mempool is included and note all of the outputs they spend.  Any
+	// unconfirmed transaction that spends an output paying to the address is
+	// necessarily one of them.  The transactions are sorted by their hash so
+	// the pages of unconfirmed outputs are stable.
+	includeMempool := true
+	if c.IncludeMempool != nil {
+		includeMempool = *c.IncludeMempool
+	}
+	var mempoolTxns []*dcrutil.Tx
+	mempoolSpends := make(map[wire.OutPoint]struct{})
+	if includeMempool {
+		mempoolTxns = addrIndex.UnconfirmedTxnsForAddress(addr)
+		sort.Slice(mempoolTxns, func(i, j int) bool {
+			return bytes.Compare(mempoolTxns[i].Hash()[:],
+				mempoolTxns[j].Hash()[:]) < 0
+		})
+		for _, tx := range mempoolTxns {
+			for _, txIn := range tx.MsgTx().TxIn {
+				mempoolSpends[txIn.PreviousOutPoint] = struct{}{}
+			}
+		}
+	}
+
+	// addUnspent adds the provided output to the results when it is not
+	// spent by an unconfirmed transaction and the requested number of
+	// entries has already been skipped.  It returns whether or not the
+	// requested number of entries has been reached.
+	var numSkipped int
+	addUnspent := func(mtx *wire.MsgTx, txHash *chainhash.Hash, index uint32, height, confirmations int64) bool {
+		tree := wire.TxTreeRegular
+		if stake.DetermineTxType(mtx) != stake.TxTypeRegular {
+			tree = wire.TxTreeStake
+		}
+		outPoint := wire.OutPoint{Hash: *txHash, Index: index, Tree: tree}
+		if _, ok := mempoolSpends[outPoint]; ok {
+			return false
+		}
+		if numSkipped < numToSkip {
+			numSkipped++
+			return false
+		}
+
+		txOut := mtx.TxOut[index]
+		results = append(results, types.GetAddressUTXOsResult{
+			TxID:          txHash.String(),
+			Vout:          index,
+			Tree:          tree,
+			Amount:        dcrutil.Amount(txOut.Value).ToCoin(),
+			ScriptPubKey:  hex.EncodeToString(txOut.PkScript),
+			ScriptVersion: txOut.Version,
+			Height:        height,
+			Confirmations: confirmations,
+		})
+		return len(results) == numRequested
+	}
+
+	// Scan the transactions that involve the address in the order they
+	// appear in the chain in batches and add the outputs that pay to the
+	// address and are still unspent in the main chain.
+	const batchSize = 1000
+	best := s.cfg.Chain.BestSnapshot()
+	done := false
+	for offset := uint32(0); !done; offset += batchSize {
+		var serializedTxns [][]byte
+		err := s.cfg.DB.View(func(dbTx database.Tx) error {
+			idxEntries, _, err := addrIndex.EntriesForAddress(dbTx, addr,
+				offset, batchSize, false)
+			if err != nil {
+				return err
+			}
+			regions := make([]database.BlockRegion, 0, len(idxEntries))
+			for i := 0; i < len(idxEntries); i++ {
+				regions = append(regions, idxEntries[i].BlockRegion)
+			}
+
+			// Load the raw transaction bytes from the database.
+			serializedTxns, err = dbTx.FetchBlockRegions(regions)
+			return err
+		})
+		if err != nil {
+			context := "Failed to load address index entries"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		done = len(serializedTxns) < batchSize
+
+		for _, serializedTx := range serializedTxns {
+			var mtx wire.MsgTx
+			err := mtx.Deserialize(bytes.NewReader(serializedTx))
+			if err != nil {
+				context := "Failed to deserialize transaction"
+				return nil, rpcInternalError(err.Error(), context)
+			}
+			outputs := addressOutputs(&mtx, encodedAddr, params)
+			if len(outputs) == 0 {
+				continue
+			}
+
+			// Skip transactions that are fully spent.
+			txHash := mtx.TxHash()
+			entry, err := s.cfg.Chain.FetchUtxoEntry(&txHash)
+			if err != nil {
+				context := "Failed to fetch unspent outputs"
+				return nil, rpcInternalError(err.Error(), context)
+			}
+			if entry == nil {
+				continue
+			}
+
+			height := entry.BlockHeight()
+			confirmations := 1 + best.Height - height
+			for _, index := range outputs {
+				if entry.IsOutputSpent(index) {
+					continue
+				}
+				if addUnspent(&mtx, &txHash, index, height, confirmations) {
+					return results, nil
+				}
+			}
+		}
+	}
+
+	// Add the unconfirmed outputs that pay to the address last.
+	for _, tx := range mempoolTxns {
+		mtx := tx.MsgTx()
+		for _, index := range addressOutputs(mtx, encodedAddr, params) {
+			if addUnspent(mtx, tx.Hash(), index, 0, 0) {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// addrManBucketsInfo returns the occupancy of buckets with the provided number
+// of addresses in each and maximum size.
+func addrManBucketsInfo(counts []int, size int) types.AddrManBucketsInfo {
+	info := types.AddrManBucketsInfo{Count: len(counts), Size: size}
+	for _, count := range counts {
+		if count > 0 {
+			info.Used++
+		}
+		if count >= size {
+			info.Full++
+		}
+	}
+	return info
+}
+
+// unixOrZero returns the provided time as a unix timestamp or zero when it is
+// the zero time.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// handleGetAddrManInfo implements the getaddrmaninfo command.
+func handleGetAddrManInfo(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetAddrManInfoCmd)
+
+	verbose := c.Verbose != nil && *c.Verbose
+	snap := s.cfg.AddrManager.Snapshot(verbose)
+	result := &types.GetAddrManInfoResult{
+		New:   snap.NewAddresses,
+		Tried: snap.TriedAddresses,
+		Networks: make(map[string]types.AddrManNetworkInfo,
+			len(snap.Networks)),
+		NewBuckets: addrManBucketsInfo(snap.NewBucketCounts,
+			snap.NewBucketSize),
+		TriedBuckets: addrManBucketsInfo(snap.TriedBucketCounts,
+			snap.TriedBucketSize),
+		UserAgents:       snap.UserAgents,
+		ProtocolVersions: make(map[string]int, len(snap.ProtocolVersions)),
+	}
+	for network, counts := range snap.Networks {
+		result.Networks[network.String()] = types.AddrManNetworkInfo{
+			New:   counts.New,
+			Tried: counts.Tried,
+		}
+	}
+	for pver, count := range snap.ProtocolVersions {
+		result.ProtocolVersions[strconv.FormatUint(uint64(pver), 10)] = count
+	}
+	if !verbose {
+		return result, nil
+	}
+
+	result.Addresses = make([]types.AddrManAddressInfo, 0, len(snap.Addresses))
+	for _, ka := range snap.Addresses {
+		result.Addresses = append(result.Addresses, types.AddrManAddressInfo{
+			Address:         addrmgr.NetAddressKey(&ka.Addr),
+			Source:          addrmgr.NetAddressKey(&ka.Source),
+			Network:         addrmgr.Network(&ka.Addr).String(),
+			Services:        fmt.Sprintf("%08d", uint64(ka.Addr.Services)),
+			LastSeen:        ka.Addr.Timestamp.Unix(),
+			Attempts:        ka.Attempts,
+			LastAttempt:     unixOrZero(ka.LastAttempt),
+			LastSuccess:     unixOrZero(ka.LastSuccess),
+			Tried:           ka.Tried,
+			UserAgent:       ka.UserAgent,
+			ProtocolVersion: ka.ProtocolVersion,
+			LastBlock:       ka.LastBlock,
+		})
+	}
+	sort.Slice(result.Addresses, func(i, j int) bool {
+		return result.Addresses[i].Address < result.Addresses[j].Address
+	})
+	return result, nil
+}
+
 // handleGetBestBlock implements the getbestblock command.
 func handleGetBestBlock(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	// All other "get block" commands give either the height, the hash, or
@@ -1873,6 +2458,21 @@ func handleGetBlock(_ context.Context, s *rpcServer, cmd interface{}) (interface
 
 		blockReply.STx = stxNames
 	} else {
+		// Load the scripts of the previous outputs spent by the block from
+		// the spend journal when the previous output details are requested.
+		var prevScripts indexers.PrevScripter
+		if c.PrevOut != nil && *c.PrevOut {
+			if !chain.MainChainHasBlock(hash) {
+				return nil, rpcInvalidError("Previous output details " +
+					"are only available for blocks in the main chain")
+			}
+			prevScripts, err = chain.FetchPrevScripts(blk)
+			if err != nil {
+				return nil, rpcInternalError(err.Error(),
+					"Could not fetch previous outputs")
+			}
+		}
+
 		txns := blk.Transactions()
 		chainParams := s.cfg.ChainParams
 		rawTxns := make([]types.TxRawResult, len(txns))
@@ -1885,6 +2485,10 @@ func handleGetBlock(_ context.Context, s *rpcServer, cmd interface{}) (interface
 				return nil, rpcInternalError(err.Error(),
 					"Could not create transaction")
 			}
+			if prevScripts != nil {
+				addVinPrevOuts(rawTxn.Vin, tx.MsgTx(), prevScripts,
+					chainParams)
+			}
 			rawTxns[i] = *rawTxn
 		}
 		blockReply.RawTx = rawTxns
@@ -1900,6 +2504,10 @@ func handleGetBlock(_ context.Context, s *rpcServer, cmd interface{}) (interface
 				return nil, rpcInternalError(err.Error(),
 					"Could not create stake transaction")
 			}
+			if prevScripts != nil {
+				addVinPrevOuts(rawSTxn.Vin, tx.MsgTx(), prevScripts,
+					chainParams)
+			}
 			rawSTxns[i] = *rawSTxn
 		}
 		blockReply.RawSTx = rawSTxns
@@ -1908,6 +2516,39 @@ func handleGetBlock(_ context.Context, s *rpcServer, cmd interface{}) (interface
 	return blockReply, nil
 }
 
+// handleGetBlockByTime implements the getblockbytime command.
+func handleGetBlockByTime(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	// Respond with an error if the block timestamp index is not enabled.
+	timeIndex := s.cfg.TimeIndex
+	if timeIndex == nil {
+		return nil, rpcInternalError("Block timestamp index must be "+
+			"enabled (--timeindex)", "Configuration")
+	}
+
+	c := cmd.(*types.GetBlockByTimeCmd)
+	if c.Timestamp < 0 {
+		return nil, rpcInvalidError("Timestamp must not be negative")
+	}
+
+	entry, err := timeIndex.NearestBlock(time.Unix(c.Timestamp, 0))
+	if err != nil {
+		context := "Failed to retrieve block by time"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	if entry == nil {
+		return nil, &dcrjson.RPCError{
+			Code:    dcrjson.ErrRPCBlockNotFound,
+			Message: "No blocks have been indexed",
+		}
+	}
+
+	return &types.GetBlockByTimeResult{
+		Hash:   entry.Hash.String(),
+		Height: int64(entry.Height),
+		Time:   entry.Timestamp.Unix(),
+	}, nil
+}
+
 // handleGetBlockchainInfo implements the getblockchaininfo command.
 func handleGetBlockchainInfo(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	chain := s.cfg.Chain
@@ -2091,6 +2732,49 @@ func handleGetBlockHeader(_ context.Context, s *rpcServer, cmd interface{}) (int
 	return blockHeaderReply, nil
 }
 
+// handleGetBlocksByTime implements the getblocksbytime command.
+func handleGetBlocksByTime(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	// Respond with an error if the block timestamp index is not enabled.
+	timeIndex := s.cfg.TimeIndex
+	if timeIndex == nil {
+		return nil, rpcInternalError("Block timestamp index must be "+
+			"enabled (--timeindex)", "Configuration")
+	}
+
+	c := cmd.(*types.GetBlocksByTimeCmd)
+	if c.Start < 0 || c.End < c.Start {
+		return nil, rpcInvalidError("Start time must not be negative "+
+			"or after end time (start %d, end %d)", c.Start, c.End)
+	}
+
+	// Override the default number of requested entries if needed.
+	numRequested := 100
+	if c.Count != nil {
+		numRequested = *c.Count
+		if numRequested < 1 {
+			numRequested = 1
+		}
+	}
+
+	entries, err := timeIndex.BlocksInRange(time.Unix(c.Start, 0),
+		time.Unix(c.End, 0), numRequested)
+	if err != nil {
+		context := "Failed to retrieve blocks by time"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	results := make([]types.GetBlockByTimeResult, 0, len(entries))
+	for i := range entries {
+		entry := &entries[i]
+		results = append(results, types.GetBlockByTimeResult{
+			Hash:   entry.Hash.String(),
+			Height: int64(entry.Height),
+			Time:   entry.Timestamp.Unix(),
+		})
+	}
+	return results, nil
+}
+
 // handleGetBlockSubsidy implements the getblocksubsidy command.
 func handleGetBlockSubsidy(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetBlockSubsidyCmd)
@@ -2113,6 +2797,102 @@ func handleGetBlockSubsidy(_ context.Context, s *rpcServer, cmd interface{}) (in
 	return rep, nil
 }
 
+// blockProposalRejectReason returns the BIP0023 reason string for a block
+// proposal that was rejected due to the provided rule error.  Rule violations
+// that do not have a reason defined by BIP0022 are reported as "bad-" followed
+// by the violated rule, such as "bad-merkle-root" for ErrBadMerkleRoot.
+func blockProposalRejectReason(rErr blockchain.RuleError) string {
+	switch rErr.ErrorCode {
+	case blockchain.ErrDuplicateBlock:
+		return "duplicate"
+	case blockchain.ErrMissingParent, blockchain.ErrInvalidTemplateParent:
+		return "bad-prevblk"
+	}
+
+	// Convert the name of the error code to lowercase words separated by
+	// hyphens while keeping acronyms together.
+	name := []rune(strings.TrimPrefix(rErr.ErrorCode.String(), "Err"))
+	var reason strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := name[i-1]
+			nextIsLower := i+1 < len(name) && unicode.IsLower(name[i+1])
+			if !unicode.IsUpper(prev) || nextIsLower {
+				reason.WriteByte('-')
+			}
+		}
+		reason.WriteRune(unicode.ToLower(r))
+	}
+	if strings.HasPrefix(reason.String(), "bad-") {
+		return reason.String()
+	}
+	return "bad-" + reason.String()
+}
+
+// handleGetBlockTemplate implements the getblocktemplate command.  Only the
+// proposal mode defined by BIP0023 is supported since work for mining is
+// provided via getwork.
+func handleGetBlockTemplate(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetBlockTemplateCmd)
+	request := c.Request
+
+	// Default to template mode when the mode is not specified.
+	mode := "template"
+	if request != nil && request.Mode != "" {
+		mode = request.Mode
+	}
+	switch mode {
+	case "proposal":
+	case "template":
+		return nil, rpcInvalidError("Template mode is not supported -- " +
+			"use getwork to obtain work")
+	default:
+		return nil, rpcInvalidError("Invalid mode: %q", mode)
+	}
+
+	// Deserialize the proposed block.
+	if request.Data == "" {
+		return nil, rpcInvalidError("Data must contain the hex-encoded " +
+			"serialized block that is being proposed")
+	}
+	hexStr := request.Data
+	if len(hexStr)%2 != 0 {
+		hexStr = "0" + hexStr
+	}
+	serializedBlock, err := hex.DecodeString(hexStr)
+	if err != nil {
+		return nil, rpcDecodeHexError(hexStr)
+	}
+	block, err := dcrutil.NewBlockFromBytes(serializedBlock)
+	if err != nil {
+		return nil, rpcDeserializationError("Could not decode block: %v",
+			err)
+	}
+
+	// Reject blocks that are already known.
+	if s.cfg.Chain.HaveBlock(block.Hash()) {
+		return "duplicate", nil
+	}
+
+	// Fully validate the block as if it were connected to the main chain
+	// without checking the proof of work or broadcasting it.
+	err = s.cfg.Chain.CheckConnectBlockTemplate(block)
+	if err != nil {
+		// Anything other than a rule violation is an unexpected error, so
+		// return that error as an internal error.
+		var rErr blockchain.RuleError
+		if !errors.As(err, &rErr) {
+			context := "Unexpected error while validating block proposal"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+
+		rpcsLog.Debugf("Block proposal %s rejected: %v", block.Hash(), err)
+		return blockProposalRejectReason(rErr), nil
+	}
+
+	return nil, nil
+}
+
 // handleGetChainTips implements the getchaintips command.
 func handleGetChainTips(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	chainTips := s.cfg.Chain.ChainTips()
@@ -2143,6 +2923,11 @@ func handleGetCurrentNet(_ context.Context, s *rpcServer, cmd interface{}) (inte
 	return s.cfg.ChainParams.Net, nil
 }
 
+// handleGetDatabaseInfo implements the getdatabaseinfo command.
+func handleGetDatabaseInfo(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	return databaseInfo(s.cfg.DB)
+}
+
 // handleGetDifficulty implements the getdifficulty command.
 func handleGetDifficulty(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	best := s.cfg.Chain.BestSnapshot()
@@ -2159,6 +2944,44 @@ func handleGetHashesPerSec(_ context.Context, s *rpcServer, cmd interface{}) (in
 	return int64(s.cfg.CPUMiner.HashesPerSecond()), nil
 }
 
+// nodeCapabilities returns the names of the optional subsystems that are
+// enabled by the provided configuration.
+func nodeCapabilities(c *config) []string {
+	capabilities := make([]string, 0, 10)
+	addIf := func(enabled bool, name string) {
+		if enabled {
+			capabilities = append(capabilities, name)
+		}
+	}
+	addIf(c.TxIndex || c.AddrIndex, "txindex")
+	addIf(c.AddrIndex, "addrindex")
+	addIf(c.SpendIndex, "spendindex")
+	addIf(c.TimeIndex, "timeindex")
+	addIf(!c.NoExistsAddrIndex, "existsaddrindex")
+	addIf(!c.NoCFilters, "cfindex")
+	addIf(c.Proxy != "", "proxy")
+	addIf(!c.NoOnion && (c.Proxy != "" || c.OnionProxy != ""), "tor")
+	addIf(c.Upnp, "upnp")
+	addIf(len(c.MetricsListeners) > 0, "metrics")
+	return capabilities
+}
+
+// handleGetBuildInfo implements the getbuildinfo command.
+func handleGetBuildInfo(_ context.Context, s *rpcServer, _ interface{}) (interface{}, error) {
+	capabilities := nodeCapabilities(cfg)
+	if s.cfg.Profiler != nil && s.cfg.Profiler.ServerAddr() != "" {
+		capabilities = append(capabilities, "profiler")
+	}
+	return &types.GetBuildInfoResult{
+		Version:      version.String(),
+		Commit:       version.Commit,
+		GoVersion:    runtime.Version(),
+		OS:           runtime.GOOS,
+		Arch:         runtime.GOARCH,
+		Capabilities: capabilities,
+	}, nil
+}
+
 // handleGetCFilter implements the getcfilter command.
 func handleGetCFilter(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	cfIndex := s.cfg.SyncMgr.CFIndex()
@@ -2327,7 +3150,134 @@ func handleGetCFilterV2(_ context.Context, s *rpcServer, cmd interface{}) (inter
 		ProofIndex:  blockchain.HeaderCmtFilterIndex,
 		ProofHashes: nil,
 	}
-	return result, nil
+	return result, nil
+}
+
+// handleGetCFilterV2Headers implements the getcfilterv2headers command.
+func handleGetCFilterV2Headers(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetCFilterV2HeadersCmd)
+	count := wire.MaxBlockHeadersPerMsg
+	if c.Count != nil {
+		count = *c.Count
+	}
+	if count < 1 || count > wire.MaxBlockHeadersPerMsg {
+		return nil, rpcInvalidError("Count must be between 1 and %d",
+			wire.MaxBlockHeadersPerMsg)
+	}
+
+	best := s.cfg.Chain.BestSnapshot()
+	if c.StartHeight < 0 || c.StartHeight > best.Height {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Block number out of range: %v",
+				c.StartHeight),
+		}
+	}
+
+	commitments, err := s.cfg.Chain.MainChainFilterCommitments(c.StartHeight,
+		count)
+	if err != nil {
+		context := "Failed to load filter commitments"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	results := make([]types.GetCFilterV2HeaderResult, 0, len(commitments))
+	var headerBuf bytes.Buffer
+	for i := range commitments {
+		commitment := &commitments[i]
+		headerBuf.Reset()
+		err := commitment.Header.Serialize(&headerBuf)
+		if err != nil {
+			context := "Failed to serialize block header"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+
+		proofHashes := make([]string, 0, len(commitment.ProofHashes))
+		for j := range commitment.ProofHashes {
+			proofHashes = append(proofHashes, commitment.ProofHashes[j].String())
+		}
+
+		results = append(results, types.GetCFilterV2HeaderResult{
+			BlockHash:   commitment.Header.BlockHash().String(),
+			Height:      int64(commitment.Header.Height),
+			Header:      hex.EncodeToString(headerBuf.Bytes()),
+			FilterHash:  commitment.FilterHash.String(),
+			ProofIndex:  commitment.ProofIndex,
+			ProofHashes: proofHashes,
+			Committed:   commitment.Committed,
+		})
+	}
+	return results, nil
+}
+
+// handleGetCFiltersV2 implements the getcfiltersv2 command.
+func handleGetCFiltersV2(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetCFiltersV2Cmd)
+	count := 100
+	if c.Count != nil {
+		count = *c.Count
+	}
+	if count < 1 || count > maxCFiltersV2PerRequest {
+		return nil, rpcInvalidError("Count must be between 1 and %d",
+			maxCFiltersV2PerRequest)
+	}
+
+	best := s.cfg.Chain.BestSnapshot()
+	if c.StartHeight < 0 || c.StartHeight > best.Height {
+		return nil, &dcrjson.RPCError{
+			Code: dcrjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Block number out of range: %v",
+				c.StartHeight),
+		}
+	}
+
+	hashes, filters, err := s.cfg.Chain.MainChainFilters(c.StartHeight, count)
+	if err != nil {
+		context := "Failed to load filters"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	// NOTE: See the comment in handleGetCFilterV2 regarding the proof hashes
+	// always being empty.
+	results := make([]types.GetCFilterV2Result, 0, len(filters))
+	for i, filter := range filters {
+		results = append(results, types.GetCFilterV2Result{
+			BlockHash:   hashes[i].String(),
+			Data:        hex.EncodeToString(filter.Bytes()),
+			ProofIndex:  blockchain.HeaderCmtFilterIndex,
+			ProofHashes: nil,
+		})
+	}
+	return results, nil
+}
+
+// handleGetIndexInfo implements the getindexinfo command.
+func handleGetIndexInfo(_ context.Context, s *rpcServer, _ interface{}) (interface{}, error) {
+	// There is nothing to report when no optional indexes are enabled.
+	indexManager := s.cfg.IndexManager
+	if indexManager == nil {
+		return []types.GetIndexInfoResult{}, nil
+	}
+
+	progress, err := indexManager.Progress()
+	if err != nil {
+		context := "Failed to retrieve index progress"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+
+	bestHeight := s.cfg.Chain.BestSnapshot().Height
+	results := make([]types.GetIndexInfoResult, 0, len(progress))
+	for i := range progress {
+		p := &progress[i]
+		results = append(results, types.GetIndexInfoResult{
+			Name:         p.Name,
+			Height:       p.Height,
+			Synced:       p.TargetHeight == 0 && p.Height >= bestHeight,
+			TargetHeight: p.TargetHeight,
+			ETA:          int64(p.ETA / time.Second),
+		})
+	}
+	return results, nil
 }
 
 // handleGetInfo implements the getinfo command. We only return the fields
@@ -2344,7 +3294,10 @@ func handleGetInfo(_ context.Context, s *rpcServer, cmd interface{}) (interface{
 		Proxy:           cfg.Proxy,
 		Difficulty:      getDifficultyRatio(best.Bits, s.cfg.ChainParams),
 		TestNet:         cfg.TestNet,
-		RelayFee:        cfg.minRelayTxFee.ToCoin(),
+		RelayFee:        s.cfg.TxMemPool.MinRelayTxFee().ToCoin(),
+		MaxOrphanTx:     s.cfg.TxMemPool.MaxOrphanTxs(),
+		MaxPeers:        s.cfg.ConnMgr.MaxPeers(),
+		BanDuration:     int64(s.cfg.ConnMgr.BanDuration().Seconds()),
 	}
 
 	return ret, nil
@@ -2360,8 +3313,9 @@ func handleGetMempoolInfo(_ context.Context, s *rpcServer, cmd interface{}) (int
 	}
 
 	ret := &types.GetMempoolInfoResult{
-		Size:  int64(len(mempoolTxns)),
-		Bytes: numBytes,
+		Size:        int64(len(mempoolTxns)),
+		Bytes:       numBytes,
+		RateLimited: s.cfg.TxMemPool.NumRateLimited(),
 	}
 
 	return ret, nil
@@ -2399,6 +3353,7 @@ func handleGetMiningInfo(ctx context.Context, s *rpcServer, cmd interface{}) (in
 		StakeDifficulty:  nextStakeDiff,
 		Generate:         s.cfg.CPUMiner.IsMining(),
 		GenProcLimit:     s.cfg.CPUMiner.NumWorkers(),
+		GeneratePaused:   s.cfg.CPUMiner.IsPaused(),
 		HashesPerSec:     int64(s.cfg.CPUMiner.HashesPerSecond()),
 		NetworkHashPS:    networkHashesPerSec,
 		PooledTx:         uint64(s.cfg.TxMemPool.Count()),
@@ -2410,10 +3365,18 @@ func handleGetMiningInfo(ctx context.Context, s *rpcServer, cmd interface{}) (in
 // handleGetNetTotals implements the getnettotals command.
 func handleGetNetTotals(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	totalBytesRecv, totalBytesSent := s.cfg.ConnMgr.NetTotals()
+	uploadTarget := s.cfg.ConnMgr.UploadTarget()
 	reply := &types.GetNetTotalsResult{
 		TotalBytesRecv: totalBytesRecv,
 		TotalBytesSent: totalBytesSent,
 		TimeMillis:     time.Now().UTC().UnixNano() / int64(time.Millisecond),
+		UploadTarget: types.UploadTargetResult{
+			TimeFrame:             int64(uploadTarget.TimeFrame.Seconds()),
+			Target:                uploadTarget.Target,
+			TargetReached:         uploadTarget.TargetReached,
+			ServeHistoricalBlocks: uploadTarget.ServeHistoricalBlocks,
+			BytesLeftInCycle:      uploadTarget.BytesLeft,
+		},
 	}
 	return reply, nil
 }
@@ -2535,7 +3498,7 @@ func handleGetNetworkInfo(_ context.Context, s *rpcServer, cmd interface{}) (int
 		ProtocolVersion: int32(maxProtocolVersion),
 		TimeOffset:      int64(s.cfg.TimeSource.Offset().Seconds()),
 		Connections:     s.cfg.ConnMgr.ConnectedCount(),
-		RelayFee:        cfg.minRelayTxFee.ToCoin(),
+		RelayFee:        s.cfg.TxMemPool.MinRelayTxFee().ToCoin(),
 		Networks:        networks,
 		LocalAddresses:  localAddrs,
 		LocalServices:   fmt.Sprintf("%016x", uint64(s.cfg.Services)),
@@ -2564,6 +3527,9 @@ func handleGetPeerInfo(_ context.Context, s *rpcServer, cmd interface{}) (interf
 			BytesRecv:      statsSnap.BytesRecv,
 			ConnTime:       statsSnap.ConnTime.Unix(),
 			PingTime:       float64(statsSnap.LastPingMicros),
+			PingP50:        float64(statsSnap.PingP50Micros),
+			PingP95:        float64(statsSnap.PingP95Micros),
+			PingJitter:     float64(statsSnap.PingJitterMicros),
 			TimeOffset:     statsSnap.TimeOffset,
 			Version:        statsSnap.Version,
 			SubVer:         statsSnap.UserAgent,
@@ -2583,6 +3549,40 @@ func handleGetPeerInfo(_ context.Context, s *rpcServer, cmd interface{}) (interf
 	return infos, nil
 }
 
+// paginateHashes returns the page of the provided hex-encoded hashes requested
+// by the optional count and cursor parameters of commands that support
+// pagination.  The hashes are sorted in ascending order when either parameter
+// is provided so that the pages are stable across calls.  Only the hashes that
+// sort after the cursor are considered, and at most count of them are returned.
+//
+// NOTE: The passed slice is sorted in place.
+func paginateHashes(hashes []string, count *int, cursor *string) ([]string, error) {
+	if count == nil && cursor == nil {
+		return hashes, nil
+	}
+	if count != nil && *count <= 0 {
+		return nil, rpcInvalidError("Count must be positive: %d", *count)
+	}
+
+	sort.Strings(hashes)
+	if cursor != nil {
+		cursorHash, err := chainhash.NewHashFromStr(*cursor)
+		if err != nil {
+			return nil, rpcDecodeHexError(*cursor)
+		}
+		after := cursorHash.String()
+		start := sort.SearchStrings(hashes, after)
+		if start < len(hashes) && hashes[start] == after {
+			start++
+		}
+		hashes = hashes[start:]
+	}
+	if count != nil && *count < len(hashes) {
+		hashes = hashes[:*count]
+	}
+	return hashes, nil
+}
+
 // handleGetRawMempool implements the getrawmempool command.
 func handleGetRawMempool(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetRawMempoolCmd)
@@ -2619,13 +3619,26 @@ func handleGetRawMempool(_ context.Context, s *rpcServer, cmd interface{}) (inte
 	mp := s.cfg.TxMemPool
 	if c.Verbose != nil && *c.Verbose {
 		descs := mp.VerboseTxDescs()
-		result := make(map[string]*types.GetRawMempoolVerboseResult, len(descs))
+		descsByHash := make(map[string]*mempool.VerboseTxDesc, len(descs))
+		hashStrings := make([]string, 0, len(descs))
 		for i := range descs {
 			desc := descs[i]
 			if filterType != nil && desc.Type != *filterType {
 				continue
 			}
+			hash := desc.Tx.Hash().String()
+			descsByHash[hash] = desc
+			hashStrings = append(hashStrings, hash)
+		}
+		hashStrings, err := paginateHashes(hashStrings, c.Count, c.Cursor)
+		if err != nil {
+			return nil, err
+		}
 
+		result := make(map[string]*types.GetRawMempoolVerboseResult,
+			len(hashStrings))
+		for _, hash := range hashStrings {
+			desc := descsByHash[hash]
 			tx := desc.Tx
 			mpd := &types.GetRawMempoolVerboseResult{
 				Size:             int32(tx.MsgTx().SerializeSize()),
@@ -2640,7 +3653,7 @@ func handleGetRawMempool(_ context.Context, s *rpcServer, cmd interface{}) (inte
 				mpd.Depends[j] = depDesc.Tx.Hash().String()
 			}
 
-			result[tx.Hash().String()] = mpd
+			result[hash] = mpd
 		}
 
 		return result, nil
@@ -2656,7 +3669,7 @@ func handleGetRawMempool(_ context.Context, s *rpcServer, cmd interface{}) (inte
 		}
 		hashStrings = append(hashStrings, descs[i].Tx.Hash().String())
 	}
-	return hashStrings, nil
+	return paginateHashes(hashStrings, c.Count, c.Cursor)
 }
 
 // handleGetRawTransaction implements the getrawtransaction command.
@@ -2781,6 +3794,111 @@ func handleGetRawTransaction(_ context.Context, s *rpcServer, cmd interface{}) (
 	return *rawTxn, nil
 }
 
+// handleGetRPCLimitInfo implements the getrpclimitinfo command.
+func handleGetRPCLimitInfo(_ context.Context, s *rpcServer, _ interface{}) (interface{}, error) {
+	return s.clientLimiter.info(), nil
+}
+
+// handleGetScriptStats implements the getscriptstats command.
+func handleGetScriptStats(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetScriptStatsCmd)
+	chain := s.cfg.Chain
+	best := chain.BestSnapshot()
+	endHeight := best.Height
+	if c.EndHeight != nil {
+		endHeight = *c.EndHeight
+	}
+	if c.StartHeight < 0 || endHeight < c.StartHeight ||
+		endHeight > best.Height {
+
+		return nil, rpcInvalidError("Block range must be within the main "+
+			"chain and the start height must not be after the end height "+
+			"(start %d, end %d)", c.StartHeight, endHeight)
+	}
+	if endHeight-c.StartHeight >= maxScriptStatsBlocks {
+		return nil, rpcInvalidError("Block range must not exceed %d "+
+			"blocks", maxScriptStatsBlocks)
+	}
+
+	// Tally the opcodes in all of the signature scripts and public key
+	// scripts in the range along with the classes of the public key
+	// scripts.  Note that the coinbase and stakebase inputs are skipped
+	// since their signature scripts are not executed.
+	var stats txscript.ScriptStats
+	addTxScripts := func(tx *wire.MsgTx, skipFirstInput bool) {
+		for txInIdx, txIn := range tx.TxIn {
+			if skipFirstInput && txInIdx == 0 {
+				continue
+			}
+			stats.AddScript(0, txIn.SignatureScript)
+		}
+		for _, txOut := range tx.TxOut {
+			stats.AddPkScript(txOut.Version, txOut.PkScript)
+		}
+	}
+	for height := c.StartHeight; height <= endHeight; height++ {
+		block, err := chain.BlockByHeight(height)
+		if err != nil {
+			context := "Failed to fetch block"
+			return nil, rpcInternalError(err.Error(), context)
+		}
+
+		msgBlock := block.MsgBlock()
+		for txIdx, tx := range msgBlock.Transactions {
+			addTxScripts(tx, txIdx == 0)
+		}
+		for _, stx := range msgBlock.STransactions {
+			addTxScripts(stx, stake.IsSSGen(stx))
+		}
+	}
+
+	return &types.GetScriptStatsResult{
+		StartHeight:           c.StartHeight,
+		EndHeight:             endHeight,
+		NumScripts:            stats.NumScripts,
+		NumUnparsable:         stats.NumUnparsable,
+		NumUnsupportedVersion: stats.NumUnsupportedVersion,
+		Opcodes:               stats.OpcodeUsage(),
+		Classes:               stats.ClassUsage(),
+	}, nil
+}
+
+// handleGetSpentInfo implements the getspentinfo command.
+func handleGetSpentInfo(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	// Respond with an error if the spend index is not enabled.
+	spendIndex := s.cfg.SpendIndex
+	if spendIndex == nil {
+		return nil, rpcInternalError("Spend index must be "+
+			"enabled (--spendindex)", "Configuration")
+	}
+
+	c := cmd.(*types.GetSpentInfoCmd)
+	txHash, err := chainhash.NewHashFromStr(c.Txid)
+	if err != nil {
+		return nil, rpcDecodeHexError(c.Txid)
+	}
+
+	// Look up the spender of the output in the spend index.
+	outpoint := wire.OutPoint{Hash: *txHash, Index: c.Vout}
+	entry, err := spendIndex.Entry(&outpoint)
+	if err != nil {
+		context := "Failed to retrieve spend information"
+		return nil, rpcInternalError(err.Error(), context)
+	}
+	if entry == nil {
+		return nil, dcrjson.NewRPCError(dcrjson.ErrRPCNoTxInfo,
+			fmt.Sprintf("No spend information available for output "+
+				"%v:%d", txHash, c.Vout))
+	}
+
+	return &types.GetSpentInfoResult{
+		TxID:      entry.SpenderHash.String(),
+		Vin:       entry.InputIndex,
+		BlockHash: entry.BlockHash.String(),
+		Height:    int64(entry.BlockHeight),
+	}, nil
+}
+
 // handleGetStakeDifficulty implements the getstakedifficulty command.
 func handleGetStakeDifficulty(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	chain := s.cfg.Chain
@@ -2901,6 +4019,166 @@ func handleGetStakeVersionInfo(_ context.Context, s *rpcServer, cmd interface{})
 	return result, nil
 }
 
+// voteStatsForInterval returns the vote version and agenda choice statistics
+// for the provided votes cast in blocks within the given interval.  The choices
+// for each agenda are determined from the deployments defined for the version
+// of each vote.
+func voteStatsForInterval(params *chaincfg.Params, startHeight, endHeight int64, votes []stake.VoteVersionTuple) types.VoteStatsInterval {
+	percent := func(count, total uint32) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(count) * 100 / float64(total)
+	}
+
+	versionCounts := make(map[uint32]uint32)
+	agendaStats := make(map[string]*types.AgendaVoteStats)
+	for _, vote := range votes {
+		versionCounts[vote.Version]++
+
+		for i := range params.Deployments[vote.Version] {
+			agenda := &params.Deployments[vote.Version][i].Vote
+			stats, ok := agendaStats[agenda.Id]
+			if !ok {
+				stats = &types.AgendaVoteStats{
+					ID:      agenda.Id,
+					Version: vote.Version,
+					Choices: make([]types.VoteChoiceStats,
+						len(agenda.Choices)),
+				}
+				for j := range agenda.Choices {
+					stats.Choices[j].ID = agenda.Choices[j].Id
+				}
+				agendaStats[agenda.Id] = stats
+			}
+
+			// Votes with invalid bits for the agenda are not counted.
+			choiceIdx := agenda.VoteIndex(vote.Bits)
+			if choiceIdx == -1 {
+				continue
+			}
+			stats.NumVotes++
+			stats.Choices[choiceIdx].Count++
+		}
+	}
+
+	result := types.VoteStatsInterval{
+		StartHeight:  startHeight,
+		EndHeight:    endHeight,
+		NumVotes:     uint32(len(votes)),
+		VoteVersions: make([]types.VoteVersionStats, 0, len(versionCounts)),
+		Agendas:      make([]types.AgendaVoteStats, 0, len(agendaStats)),
+	}
+	for version, count := range versionCounts {
+		result.VoteVersions = append(result.VoteVersions,
+			types.VoteVersionStats{
+				Version:    version,
+				Count:      count,
+				Percentage: percent(count, result.NumVotes),
+			})
+	}
+	sort.Slice(result.VoteVersions, func(i, j int) bool {
+		return result.VoteVersions[i].Version < result.VoteVersions[j].Version
+	})
+	for _, stats := range agendaStats {
+		for i := range stats.Choices {
+			choice := &stats.Choices[i]
+			choice.Percentage = percent(choice.Count, stats.NumVotes)
+		}
+		result.Agendas = append(result.Agendas, *stats)
+	}
+	sort.Slice(result.Agendas, func(i, j int) bool {
+		a, b := &result.Agendas[i], &result.Agendas[j]
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.ID < b.ID
+	})
+
+	return result
+}
+
+// handleGetVoteStats implements the getvotestats command.
+func handleGetVoteStats(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetVoteStatsCmd)
+
+	count := int32(1)
+	if c.Count != nil {
+		count = *c.Count
+		if count <= 0 || count > maxVoteStatsIntervals {
+			return nil, rpcInvalidError("Count must be between 1 and %d",
+				maxVoteStatsIntervals)
+		}
+	}
+
+	chain := s.cfg.Chain
+	snapshot := chain.BestSnapshot()
+	interval := s.cfg.ChainParams.StakeVersionInterval
+
+	// Fetch the votes for all blocks in the requested intervals at once.  The
+	// first interval is the current one which might not be complete yet.
+	firstStart := chain.CalcWantHeight(interval, snapshot.Height) + 1
+	if firstStart < 0 {
+		firstStart = 0
+	}
+	numIntervals := firstStart/interval + 1
+	if int64(count) > numIntervals {
+		return nil, rpcInvalidError("Count must not exceed the %d "+
+			"intervals up to the current best block", numIntervals)
+	}
+	oldestStart := firstStart - int64(count-1)*interval
+	if oldestStart < 0 {
+		oldestStart = 0
+	}
+	numBlocks := int32(snapshot.Height - oldestStart + 1)
+	sv, err := chain.GetStakeVersions(&snapshot.Hash, numBlocks)
+	if err != nil {
+		return nil, rpcInternalError(err.Error(),
+			"Could not obtain stake versions")
+	}
+
+	// Assemble the statistics for each interval starting with the current one
+	// and descending from there.  The stake versions are ordered from the
+	// current best block backwards.
+	result := types.GetVoteStatsResult{
+		CurrentHeight: snapshot.Height,
+		Hash:          snapshot.Hash.String(),
+	}
+	startHeight, endHeight := firstStart, snapshot.Height
+	var votes []stake.VoteVersionTuple
+	for i := 0; i < len(sv); i++ {
+		votes = append(votes, sv[i].Votes...)
+		if sv[i].Height > startHeight && i != len(sv)-1 {
+			continue
+		}
+
+		stats := voteStatsForInterval(s.cfg.ChainParams, sv[i].Height,
+			endHeight, votes)
+		result.Intervals = append(result.Intervals, stats)
+		votes = votes[:0]
+		endHeight = sv[i].Height - 1
+		startHeight -= interval
+	}
+
+	// Calculate the trend of each vote version as the change in percentage
+	// compared to the prior interval.
+	for i := 0; i < len(result.Intervals)-1; i++ {
+		prior := result.Intervals[i+1].VoteVersions
+		for j := range result.Intervals[i].VoteVersions {
+			vs := &result.Intervals[i].VoteVersions[j]
+			vs.Trend = vs.Percentage
+			for k := range prior {
+				if prior[k].Version == vs.Version {
+					vs.Trend -= prior[k].Percentage
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // handleGetStakeVersions implements the getstakeversions command.
 func handleGetStakeVersions(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	c := cmd.(*types.GetStakeVersionsCmd)
@@ -2943,6 +4221,155 @@ func handleGetStakeVersions(_ context.Context, s *rpcServer, cmd interface{}) (i
 	return result, nil
 }
 
+// handleGetSyncStatus implements the getsyncstatus command.
+func handleGetSyncStatus(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	status := s.cfg.SyncMgr.SyncStatus()
+	return &types.GetSyncStatusResult{
+		SyncPeerID:           status.SyncPeerID,
+		Current:              status.Current,
+		HeadersFirst:         status.HeadersFirst,
+		BlockHeight:          status.BlockHeight,
+		HeaderHeight:         status.HeaderHeight,
+		SyncHeight:           status.SyncHeight,
+		VerificationProgress: status.VerificationProgress,
+		BlocksPerSecond:      status.BlocksPerSecond,
+		BytesPerSecond:       status.BytesPerSecond,
+		ETASeconds:           int64(status.ETA / time.Second),
+	}, nil
+}
+
+// handleGetTicketPoolInfo implements the getticketpoolinfo command.
+func handleGetTicketPoolInfo(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.GetTicketPoolInfoCmd)
+
+	numBuckets := uint32(10)
+	if c.Buckets != nil {
+		numBuckets = *c.Buckets
+	}
+	if numBuckets == 0 || numBuckets > maxTicketPoolInfoBuckets {
+		return nil, rpcInvalidError("Number of price buckets must be "+
+			"between 1 and %d", maxTicketPoolInfoBuckets)
+	}
+
+	// Tickets expire after the ticket expiry, so there is no point in
+	// reporting more windows than it spans.
+	params := s.cfg.ChainParams
+	winSize := params.StakeDiffWindowSize
+	maxWindows := (int64(params.TicketExpiry) + winSize - 1) / winSize
+	numWindows := int64(8)
+	if numWindows > maxWindows {
+		numWindows = maxWindows
+	}
+	if c.Windows != nil {
+		numWindows = int64(*c.Windows)
+	}
+	if numWindows > maxWindows {
+		return nil, rpcInvalidError("Number of windows must not exceed "+
+			"%d", maxWindows)
+	}
+
+	// Determine the purchase height and price of all live tickets.  The
+	// tickets are grouped by the height they matured at, so all tickets in a
+	// group were purchased in the same block for the same price.
+	chain := s.cfg.Chain
+	best := chain.BestSnapshot()
+	type ticketGroup struct {
+		purchaseHeight int64
+		expiryHeight   int64
+		price          int64
+		count          uint32
+	}
+	liveByHeight := chain.LiveTicketsByHeight()
+	groups := make([]ticketGroup, 0, len(liveByHeight))
+	var poolSize uint32
+	minPrice, maxPrice := int64(math.MaxInt64), int64(0)
+	for matureHeight, count := range liveByHeight {
+		purchaseHeight := int64(matureHeight) - int64(params.TicketMaturity)
+		header, err := chain.HeaderByHeight(purchaseHeight)
+		if err != nil {
+			context := fmt.Sprintf("Failed to fetch header at height %d",
+				purchaseHeight)
+			return nil, rpcInternalError(err.Error(), context)
+		}
+		if header.SBits < minPrice {
+			minPrice = header.SBits
+		}
+		if header.SBits > maxPrice {
+			maxPrice = header.SBits
+		}
+		groups = append(groups, ticketGroup{
+			purchaseHeight: purchaseHeight,
+			expiryHeight:   int64(matureHeight) + int64(params.TicketExpiry),
+			price:          header.SBits,
+			count:          count,
+		})
+		poolSize += count
+	}
+
+	// Distribute the tickets into evenly-sized price buckets which span the
+	// range of purchase prices.
+	priceBuckets := make([]types.TicketPoolPriceBucket, 0, numBuckets)
+	if len(groups) > 0 {
+		bucketSize := (maxPrice-minPrice)/int64(numBuckets) + 1
+		for i := int64(0); i < int64(numBuckets); i++ {
+			start := minPrice + i*bucketSize
+			priceBuckets = append(priceBuckets, types.TicketPoolPriceBucket{
+				MinPrice: dcrutil.Amount(start).ToCoin(),
+				MaxPrice: dcrutil.Amount(start + bucketSize - 1).ToCoin(),
+			})
+		}
+		for _, group := range groups {
+			idx := (group.price - minPrice) / bucketSize
+			priceBuckets[idx].Count += group.count
+		}
+	}
+
+	// Distribute the tickets into stake difficulty windows based on their age
+	// as well as the window they are projected to expire in should they not
+	// be selected to vote.  The final age window includes all older tickets.
+	ages := make([]types.TicketPoolWindow, 0, numWindows)
+	expirations := make([]types.TicketPoolWindow, 0, numWindows)
+	for i := int64(0); i < numWindows; i++ {
+		ageStart := best.Height - (i+1)*winSize + 1
+		if ageStart < 0 || i == numWindows-1 {
+			ageStart = 0
+		}
+		ages = append(ages, types.TicketPoolWindow{
+			StartHeight: ageStart,
+			EndHeight:   best.Height - i*winSize,
+		})
+		if ageStart == 0 {
+			break
+		}
+	}
+	for i := int64(0); i < numWindows; i++ {
+		expirations = append(expirations, types.TicketPoolWindow{
+			StartHeight: best.Height + i*winSize + 1,
+			EndHeight:   best.Height + (i+1)*winSize,
+		})
+	}
+	for _, group := range groups {
+		for i := range ages {
+			if group.purchaseHeight >= ages[i].StartHeight {
+				ages[i].Count += group.count
+				break
+			}
+		}
+		idx := (group.expiryHeight - best.Height - 1) / winSize
+		if idx >= 0 && idx < int64(len(expirations)) {
+			expirations[idx].Count += group.count
+		}
+	}
+
+	return &types.GetTicketPoolInfoResult{
+		Height:       best.Height,
+		PoolSize:     poolSize,
+		PriceBuckets: priceBuckets,
+		Ages:         ages,
+		Expirations:  expirations,
+	}, nil
+}
+
 // handleGetTicketPoolValue implements the getticketpoolvalue command.
 func handleGetTicketPoolValue(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	amt, err := s.cfg.Chain.TicketPoolValue()
@@ -3522,6 +4949,7 @@ func handleHelp(_ context.Context, s *rpcServer, cmd interface{}) (interface{},
 
 // handleLiveTickets implements the livetickets command.
 func handleLiveTickets(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.LiveTicketsCmd)
 	lt, err := s.cfg.Chain.LiveTickets()
 	if err != nil {
 		return nil, rpcInternalError("Could not get live tickets "+
@@ -3532,12 +4960,17 @@ func handleLiveTickets(_ context.Context, s *rpcServer, cmd interface{}) (interf
 	for i := range lt {
 		ltString[i] = lt[i].String()
 	}
+	ltString, err = paginateHashes(ltString, c.Count, c.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
 	return types.LiveTicketsResult{Tickets: ltString}, nil
 }
 
 // handleMissedTickets implements the missedtickets command.
 func handleMissedTickets(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.MissedTicketsCmd)
 	mt, err := s.cfg.Chain.MissedTickets()
 	if err != nil {
 		return nil, rpcInternalError("Could not get missed tickets "+
@@ -3548,6 +4981,10 @@ func handleMissedTickets(_ context.Context, s *rpcServer, cmd interface{}) (inte
 	for i, hash := range mt {
 		mtString[i] = hash.String()
 	}
+	mtString, err = paginateHashes(mtString, c.Count, c.Cursor)
+	if err != nil {
+		return nil, err
+	}
 
 	return types.MissedTicketsResult{Tickets: mtString}, nil
 }
@@ -3565,6 +5002,32 @@ func handlePing(_ context.Context, s *rpcServer, cmd interface{}) (interface{},
 	return nil, nil
 }
 
+// handleProfileServer implements the profileserver command.
+func handleProfileServer(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.ProfileServerCmd)
+
+	var err error
+	switch c.SubCmd {
+	case types.PSStart:
+		listen := defaultProfileListen
+		if c.Listen != nil {
+			listen = *c.Listen
+		}
+		err = s.cfg.Profiler.StartServer(listen)
+	case types.PSStop:
+		err = s.cfg.Profiler.StopServer()
+	default:
+		return nil, rpcInvalidError("Invalid subcommand for profileserver")
+	}
+
+	if err != nil {
+		return nil, rpcInvalidError("%v: %v", c.SubCmd, err)
+	}
+
+	// no data returned unless an error.
+	return nil, nil
+}
+
 // handleRegenTemplate implements the regentemplate command.
 func handleRegenTemplate(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	bg := s.cfg.BgBlkTmplGenerator()
@@ -3758,8 +5221,8 @@ func createVinListPrevOut(s *rpcServer, mtx *wire.MsgTx, chainParams *chaincfg.P
 		// Ignore the error here since an error means the script
 		// couldn't parse and there is no additional information about
 		// it anyways.
-		_, addrs, _, _ := txscript.ExtractPkScriptAddrs(originTxOut.Version,
-			originTxOut.PkScript, chainParams)
+		scriptClass, addrs, _, _ := txscript.ExtractPkScriptAddrs(
+			originTxOut.Version, originTxOut.PkScript, chainParams)
 
 		// Encode the addresses while checking if the address passes
 		// the filter when needed.
@@ -3795,6 +5258,7 @@ func createVinListPrevOut(s *rpcServer, mtx *wire.MsgTx, chainParams *chaincfg.P
 			vinListEntry.PrevOut = &types.PrevOut{
 				Addresses: encodedAddrs,
 				Value:     dcrutil.Amount(originTxOut.Value).ToCoin(),
+				Type:      scriptClass.String(),
 			}
 		}
 	}
@@ -4178,6 +5642,12 @@ func handleSetGenerate(_ context.Context, s *rpcServer, cmd interface{}) (interf
 
 	if !generate {
 		s.cfg.CPUMiner.Stop()
+
+		// Update the number of workers used by discrete generation via the
+		// generate command when a limit is provided.
+		if genProcLimit > 0 {
+			s.cfg.CPUMiner.SetNumWorkers(int32(genProcLimit))
+		}
 	} else {
 		// Respond with an error if there are no addresses to pay the
 		// created blocks to.
@@ -4193,6 +5663,78 @@ func handleSetGenerate(_ context.Context, s *rpcServer, cmd interface{}) (interf
 	return nil, nil
 }
 
+// handleSetPolicy implements the setpolicy command.
+func handleSetPolicy(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.SetPolicyCmd)
+
+	switch c.Setting {
+	case "minrelaytxfee":
+		feeDCR, err := strconv.ParseFloat(c.Value, 64)
+		if err != nil {
+			return nil, rpcInvalidError("Invalid minrelaytxfee %q: %v",
+				c.Value, err)
+		}
+		fee, err := dcrutil.NewAmount(feeDCR)
+		if err != nil || fee < 0 {
+			return nil, rpcInvalidError("Invalid minrelaytxfee %q",
+				c.Value)
+		}
+		oldFee := s.cfg.TxMemPool.MinRelayTxFee()
+		s.cfg.TxMemPool.SetMinRelayTxFee(fee)
+		rpcsLog.Infof("Minimum relay transaction fee changed from %v to "+
+			"%v per kB", oldFee, fee)
+
+	case "maxorphantx":
+		maxOrphans, err := strconv.ParseInt(c.Value, 10, 32)
+		if err != nil || maxOrphans < 0 {
+			return nil, rpcInvalidError("Invalid maxorphantx %q: must "+
+				"be a non-negative integer", c.Value)
+		}
+		oldMaxOrphans := s.cfg.TxMemPool.MaxOrphanTxs()
+		numEvicted := s.cfg.TxMemPool.SetMaxOrphanTxs(int(maxOrphans))
+		rpcsLog.Infof("Max orphan transactions changed from %d to %d "+
+			"(%d evicted)", oldMaxOrphans, maxOrphans, numEvicted)
+
+	case "maxpeers":
+		maxPeers, err := strconv.ParseInt(c.Value, 10, 32)
+		if err != nil || maxPeers < 0 {
+			return nil, rpcInvalidError("Invalid maxpeers %q: must be "+
+				"a non-negative integer", c.Value)
+		}
+		oldMaxPeers := s.cfg.ConnMgr.MaxPeers()
+		s.cfg.ConnMgr.SetMaxPeers(int(maxPeers))
+		rpcsLog.Infof("Max peers changed from %d to %d", oldMaxPeers,
+			maxPeers)
+
+	case "banduration":
+		banDuration, err := time.ParseDuration(c.Value)
+		if err != nil || banDuration < time.Second {
+			return nil, rpcInvalidError("Invalid banduration %q: must "+
+				"be a duration of at least 1s", c.Value)
+		}
+		oldBanDuration := s.cfg.ConnMgr.BanDuration()
+		s.cfg.ConnMgr.SetBanDuration(banDuration)
+		rpcsLog.Infof("Ban duration changed from %v to %v",
+			oldBanDuration, banDuration)
+
+	default:
+		return nil, rpcInvalidError("Invalid setting %q", c.Setting)
+	}
+
+	return nil, nil
+}
+
+// handlePauseGenerate implements the pausegenerate command.
+func handlePauseGenerate(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.PauseGenerateCmd)
+	if c.Pause {
+		s.cfg.CPUMiner.Pause()
+	} else {
+		s.cfg.CPUMiner.Resume()
+	}
+	return nil, nil
+}
+
 // handleStop implements the stop command.
 func handleStop(_ context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
 	select {
@@ -4856,12 +6398,48 @@ func handleVersion(_ context.Context, s *rpcServer, cmd interface{}) (interface{
 	return result, nil
 }
 
+// handleWriteProfile implements the writeprofile command.
+func handleWriteProfile(ctx context.Context, s *rpcServer, cmd interface{}) (interface{}, error) {
+	c := cmd.(*types.WriteProfileCmd)
+
+	path, err := s.cfg.Profiler.ProfilePath(c.File)
+	if err != nil {
+		return nil, rpcInvalidError("%v", err)
+	}
+
+	switch {
+	case c.Profile == cpuProfileName:
+		seconds := uint32(30)
+		if c.Seconds != nil {
+			seconds = *c.Seconds
+		}
+		if seconds == 0 || seconds > maxCPUProfileSeconds {
+			return nil, rpcInvalidError("Seconds must be between 1 "+
+				"and %d", maxCPUProfileSeconds)
+		}
+		duration := time.Duration(seconds) * time.Second
+		err = s.cfg.Profiler.WriteCPUProfile(ctx, path, duration)
+	case isRuntimeProfile(c.Profile):
+		err = s.cfg.Profiler.WriteProfile(c.Profile, path)
+	default:
+		return nil, rpcInvalidError("Unknown profile %q", c.Profile)
+	}
+	if err != nil {
+		return nil, rpcInternalError(err.Error(), "Could not write profile")
+	}
+
+	return path, nil
+}
+
 // rpcServer provides a concurrent safe RPC server to a chain server.
 type rpcServer struct {
 	cfg                    rpcserverConfig
-	authsha                [sha256.Size]byte
-	limitauthsha           [sha256.Size]byte
+	credentials            []rpcCredential
+	clientCertPerms        map[string]rpcPermission
+	latencyStats           *rpcLatencyStats
 	ntfnMgr                *wsNotificationManager
+	grpcServer             *grpcServer
+	clientLimiter          *rpcClientLimiter
 	numClients             int32
 	statusLines            map[int]string
 	statusLock             sync.RWMutex
@@ -4955,14 +6533,23 @@ func (s *rpcServer) NotifyNewTransactions(txns []*dcrutil.Tx) {
 	for _, tx := range txns {
 		// Notify websocket clients about mempool transactions.
 		s.ntfnMgr.NotifyMempoolTx(tx, true)
+
+		// Notify gRPC notification streams about mempool transactions.
+		if s.grpcServer != nil {
+			s.grpcServer.NotifyMempoolTx(tx)
+		}
 	}
 }
 
 // limitConnections responds with a 503 service unavailable and returns true if
-// adding another client would exceed the maximum allow RPC clients.
+// adding another client would exceed the maximum allow RPC clients.  Clients
+// connecting from whitelisted addresses are never limited.
 //
 // This function is safe for concurrent access.
 func (s *rpcServer) limitConnections(w http.ResponseWriter, remoteAddr string) bool {
+	if isWhitelistedAddr(remoteAddr) {
+		return false
+	}
 	if int(atomic.LoadInt32(&s.numClients)+1) > cfg.RPCMaxClients {
 		rpcsLog.Infof("Max RPC clients exceeded [%d] - "+
 			"disconnecting client %s", cfg.RPCMaxClients,
@@ -4974,6 +6561,33 @@ func (s *rpcServer) limitConnections(w http.ResponseWriter, remoteAddr string) b
 	return false
 }
 
+// limitClient reserves a request for the client connected from the provided
+// remote address and returns a function that must be called once the request
+// has completed.  When the client has exceeded its request rate limit or its
+// concurrent request limit, it writes an error response that describes the
+// exceeded limit and returns false.  Clients connecting from whitelisted
+// addresses are never limited.
+//
+// This function is safe for concurrent access.
+func (s *rpcServer) limitClient(w http.ResponseWriter, remoteAddr string) (func(), bool) {
+	if isWhitelistedAddr(remoteAddr) {
+		return func() {}, true
+	}
+	release, err := s.clientLimiter.acquire(remoteAddr)
+	if err != nil {
+		rpcsLog.Debugf("Limiting RPC client %s: %v", remoteAddr, err)
+		var limitErr *rpcLimitError
+		if errors.As(err, &limitErr) && limitErr.retryAfter > 0 {
+			retryAfter := math.Ceil(limitErr.retryAfter.Seconds())
+			w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter)))
+		}
+		http.Error(w, "429 Too many requests: "+err.Error(),
+			http.StatusTooManyRequests)
+		return nil, false
+	}
+	return release, true
+}
+
 // incrementClients adds one to the number of connected RPC clients.  Note this
 // only applies to standard clients.  Websocket clients have their own limits
 // and are tracked separately.
@@ -4992,46 +6606,53 @@ func (s *rpcServer) decrementClients() {
 	atomic.AddInt32(&s.numClients, -1)
 }
 
-// checkAuth checks the HTTP Basic authentication supplied by a wallet or RPC
-// client in the HTTP request r.  If the supplied authentication does not match
-// the username and password expected, a non-nil error is returned.
+// checkAuth checks the TLS client certificate or HTTP Basic authentication
+// supplied by a wallet or RPC client in the HTTP request r.  If the supplied
+// authentication does not match a permitted client certificate or the username
+// and password expected, a non-nil error is returned.
 //
 // This check is time-constant.
 //
-// The first bool return value signifies auth success (true if successful) and
-// the second bool return value specifies whether the user can change the state
-// of the server (true) or whether the user is limited (false). The second is
-// always false if the first is.
-func (s *rpcServer) checkAuth(r *http.Request, require bool) (bool, bool, error) {
-	authhdr := r.Header["Authorization"]
+// The bool return value signifies auth success (true if successful) and the
+// permission return value specifies the set of methods the user is authorized
+// to invoke.  The permission is always rpcPermNone if auth was not successful.
+func (s *rpcServer) checkAuth(r *http.Request, require bool) (bool, rpcPermission, error) {
+	return s.checkAuthHeader(r.TLS, r.Header["Authorization"], r.RemoteAddr,
+		require)
+}
+
+// checkAuthHeader checks the client certificate presented on the TLS
+// connection with the provided state, which may be nil, and the provided
+// values of an HTTP Basic authorization header sent by the client at the
+// provided remote address.  It is the transport independent implementation of
+// checkAuth and has the same semantics.
+//
+// A client certificate that grants a permission takes precedence over the
+// authorization header.
+//
+// This check is time-constant.
+func (s *rpcServer) checkAuthHeader(tlsState *tls.ConnectionState, authhdr []string, remoteAddr string, require bool) (bool, rpcPermission, error) {
+	if perm := matchClientCert(s.clientCertPerms, tlsState); perm != rpcPermNone {
+		return true, perm, nil
+	}
+
 	if len(authhdr) <= 0 {
 		if require {
 			rpcsLog.Warnf("RPC authentication failure from %s",
-				r.RemoteAddr)
-			return false, false, errors.New("auth failure")
+				remoteAddr)
+			return false, rpcPermNone, errors.New("auth failure")
 		}
 
-		return false, false, nil
-	}
-
-	authsha := sha256.Sum256([]byte(authhdr[0]))
-
-	// Check for limited auth first as in environments with limited users,
-	// those are probably expected to have a higher volume of calls
-	limitcmp := subtle.ConstantTimeCompare(authsha[:], s.limitauthsha[:])
-	if limitcmp == 1 {
-		return true, false, nil
+		return false, rpcPermNone, nil
 	}
 
-	// Check for admin-level auth
-	cmp := subtle.ConstantTimeCompare(authsha[:], s.authsha[:])
-	if cmp == 1 {
-		return true, true, nil
+	perm := matchCredential(s.credentials, authhdr[0])
+	if perm == rpcPermNone {
+		// Request's auth doesn't match any user.
+		rpcsLog.Warnf("RPC authentication failure from %s", remoteAddr)
+		return false, rpcPermNone, errors.New("auth failure")
 	}
-
-	// Request's auth doesn't match either user
-	rpcsLog.Warnf("RPC authentication failure from %s", r.RemoteAddr)
-	return false, false, errors.New("auth failure")
+	return true, perm, nil
 }
 
 // parsedRPCCmd represents a JSON-RPC request object that has been parsed into
@@ -5066,7 +6687,10 @@ func (s *rpcServer) standardCmdResult(ctx context.Context, cmd *parsedRPCCmd) (i
 	}
 	return nil, dcrjson.ErrRPCMethodNotFound
 handled:
-	return handler(ctx, s, cmd.params)
+	start := time.Now()
+	result, err := handler(ctx, s, cmd.params)
+	s.latencyStats.observe(string(cmd.method), time.Since(start))
+	return result, err
 }
 
 // parseCmd parses a JSON-RPC request object into known concrete command.  The
@@ -5115,15 +6739,13 @@ func createMarshalledReply(rpcVersion string, id interface{}, result interface{}
 
 // processRequest determines the incoming request type (single or batched),
 // parses it and returns a marshalled response.
-func (s *rpcServer) processRequest(ctx context.Context, request *dcrjson.Request, isAdmin bool) []byte {
+func (s *rpcServer) processRequest(ctx context.Context, request *dcrjson.Request, perm rpcPermission) []byte {
 	var result interface{}
 	var jsonErr error
 
-	if !isAdmin {
-		if _, ok := rpcLimited[request.Method]; !ok {
-			jsonErr = rpcInvalidError("limited user not " +
-				"authorized for this method")
-		}
+	if !perm.allows(request.Method) {
+		jsonErr = rpcInvalidError("%s user not authorized for this "+
+			"method", perm)
 	}
 
 	if jsonErr == nil {
@@ -5166,7 +6788,7 @@ func (s *rpcServer) processRequest(ctx context.Context, request *dcrjson.Request
 }
 
 // jsonRPCRead handles reading and responding to RPC messages.
-func (s *rpcServer) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *http.Request, isAdmin bool) {
+func (s *rpcServer) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *http.Request, perm rpcPermission) {
 	select {
 	case <-sCtx.Done():
 		return
@@ -5251,7 +6873,7 @@ func (s *rpcServer) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *
 		}
 
 		if err == nil {
-			resp = s.processRequest(ctx, &req, isAdmin)
+			resp = s.processRequest(ctx, &req, perm)
 		}
 
 		if resp != nil {
@@ -5340,7 +6962,7 @@ func (s *rpcServer) jsonRPCRead(sCtx context.Context, w http.ResponseWriter, r *
 						continue
 					}
 
-					resp = s.processRequest(ctx, &req, isAdmin)
+					resp = s.processRequest(ctx, &req, perm)
 					if resp != nil {
 						results = append(results, resp)
 					}
@@ -5420,19 +7042,58 @@ func (s *rpcServer) route(ctx context.Context) *http.Server {
 		// Keep track of the number of connected clients.
 		s.incrementClients()
 		defer s.decrementClients()
-		_, isAdmin, err := s.checkAuth(r, true)
+		_, perm, err := s.checkAuth(r, true)
 		if err != nil {
 			jsonAuthFail(w)
 			return
 		}
 
+		// Limit the rate and number of concurrent requests per client.
+		release, ok := s.limitClient(w, r.RemoteAddr)
+		if !ok {
+			return
+		}
+		defer release()
+
 		// Read and respond to the request.
-		s.jsonRPCRead(ctx, w, r, isAdmin)
+		s.jsonRPCRead(ctx, w, r, perm)
 	})
 
+	// Read-only REST endpoint.
+	if cfg.RESTEnable {
+		rpcServeMux.HandleFunc(restPathPrefix, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Connection", "close")
+			r.Close = true
+
+			// Limit the number of connections to max allowed.
+			if s.limitConnections(w, r.RemoteAddr) {
+				return
+			}
+
+			// Keep track of the number of connected clients.
+			s.incrementClients()
+			defer s.decrementClients()
+			_, perm, err := s.checkAuth(r, true)
+			if err != nil {
+				jsonAuthFail(w)
+				return
+			}
+
+			// Limit the rate and number of concurrent requests per
+			// client.
+			release, ok := s.limitClient(w, r.RemoteAddr)
+			if !ok {
+				return
+			}
+			defer release()
+
+			s.handleREST(ctx, w, r, perm)
+		})
+	}
+
 	// Websocket endpoint.
 	rpcServeMux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
-		authenticated, isAdmin, err := s.checkAuth(r, false)
+		authenticated, perm, err := s.checkAuth(r, false)
 		if err != nil {
 			jsonAuthFail(w)
 			return
@@ -5466,7 +7127,7 @@ func (s *rpcServer) route(ctx context.Context) *http.Server {
 			rpcsLog.Tracef("pong payload: %s", payload)
 			return nil
 		})
-		s.WebsocketHandler(ws, r.RemoteAddr, authenticated, isAdmin)
+		s.WebsocketHandler(ws, r.RemoteAddr, authenticated, perm)
 	})
 	return httpServer
 }
@@ -5486,6 +7147,14 @@ func (s *rpcServer) Run(ctx context.Context) {
 		}(listener)
 	}
 
+	if s.grpcServer != nil {
+		s.wg.Add(1)
+		go func() {
+			s.grpcServer.Run(ctx)
+			s.wg.Done()
+		}()
+	}
+
 	s.ntfnMgr.Run(ctx)
 	err := s.shutdown()
 	if err != nil {
@@ -5494,14 +7163,23 @@ func (s *rpcServer) Run(ctx context.Context) {
 	}
 }
 
-// genCertPair generates a key/cert pair to the paths provided.
-func genCertPair(certFile, keyFile string, altDNSNames []string, tlsCurve elliptic.Curve) error {
+// genCertPair generates a key/cert pair to the paths provided.  The curve must
+// either be one of the curves supported by tlsCurve or Ed25519.
+func genCertPair(certFile, keyFile, curve string, opts *certgen.Options) error {
 	rpcsLog.Infof("Generating TLS certificates...")
 
-	org := "dcrd autogenerated cert"
-	validUntil := time.Now().Add(10 * 365 * 24 * time.Hour)
-	cert, key, err := certgen.NewTLSCertPair(tlsCurve, org,
-		validUntil, altDNSNames)
+	var cert, key []byte
+	var err error
+	if curve == ed25519TLSCurve {
+		cert, key, err = certgen.NewEd25519TLSCertPairWithOptions(opts)
+	} else {
+		var ecCurve elliptic.Curve
+		ecCurve, err = tlsCurve(curve)
+		if err != nil {
+			return err
+		}
+		cert, key, err = certgen.NewTLSCertPairWithOptions(ecCurve, opts)
+	}
 	if err != nil {
 		return err
 	}
@@ -5527,6 +7205,14 @@ type rpcserverConfig struct {
 	// is stopped.
 	Listeners []net.Listener
 
+	// GRPCListeners defines a slice of listeners for which the gRPC server
+	// will take ownership of and accept connections.  The gRPC server is
+	// disabled when there are none.  GRPCTLSConfig defines the TLS
+	// configuration used by the gRPC server and must be nil when TLS is
+	// disabled.
+	GRPCListeners []net.Listener
+	GRPCTLSConfig *tls.Config
+
 	// StartupTime is the unix timestamp for when the server that is hosting
 	// the RPC server started.
 	StartupTime int64
@@ -5573,8 +7259,18 @@ type rpcserverConfig struct {
 
 	// These fields define any optional indexes the RPC server can make use
 	// of to provide additional data when queried.
-	TxIndex   *indexers.TxIndex
-	AddrIndex *indexers.AddrIndex
+	TxIndex    *indexers.TxIndex
+	AddrIndex  *indexers.AddrIndex
+	SpendIndex *indexers.SpendIndex
+	TimeIndex  *indexers.TimeIndex
+
+	// IndexManager manages the optional indexes and is used to report their
+	// status.  It is nil when no optional indexes are enabled.
+	IndexManager *indexers.Manager
+
+	// Profiler provides runtime control over the profiling server and
+	// allows profiles to be captured.
+	Profiler *profiler
 }
 
 // newRPCServer returns a new instance of the rpcServer struct.
@@ -5586,19 +7282,21 @@ func newRPCServer(config *rpcserverConfig) (*rpcServer, error) {
 		helpCacher:             newHelpCacher(),
 		requestProcessShutdown: make(chan struct{}),
 	}
-	if cfg.RPCUser != "" && cfg.RPCPass != "" {
-		login := cfg.RPCUser + ":" + cfg.RPCPass
-		auth := "Basic " +
-			base64.StdEncoding.EncodeToString([]byte(login))
-		rpc.authsha = sha256.Sum256([]byte(auth))
+	for _, authUser := range cfg.rpcAuthUsers {
+		rpc.credentials = append(rpc.credentials,
+			newRPCCredential(authUser))
 	}
-	if cfg.RPCLimitUser != "" && cfg.RPCLimitPass != "" {
-		login := cfg.RPCLimitUser + ":" + cfg.RPCLimitPass
-		auth := "Basic " +
-			base64.StdEncoding.EncodeToString([]byte(login))
-		rpc.limitauthsha = sha256.Sum256([]byte(auth))
+	rpc.clientCertPerms = cfg.rpcClientCertPerms
+	if len(cfg.MetricsListeners) > 0 {
+		rpc.latencyStats = newRPCLatencyStats()
 	}
+	rpc.clientLimiter = newRPCClientLimiter(cfg.RPCRateLimit,
+		cfg.RPCRateBurst, cfg.RPCMaxClientReqs)
 	rpc.ntfnMgr = newWsNotificationManager(&rpc)
+	if len(config.GRPCListeners) > 0 {
+		rpc.grpcServer = newGRPCServer(&rpc, config.GRPCListeners,
+			config.GRPCTLSConfig)
+	}
 
 	return &rpc, nil
 }
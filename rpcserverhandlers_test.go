@@ -6,12 +6,20 @@ package main
 
 import (
 	"errors"
+	"math"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/blockchain/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/dcrjson/v3"
 	"github.com/decred/dcrd/dcrutil/v3"
 	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
 )
 
 // testCfg holds the cfg for the test server.
@@ -165,3 +173,398 @@ func TestHandleCreateRawTransaction(t *testing.T) {
 		}
 	}
 }
+
+// TestHandleGetTicketPoolInfoLimits ensures the getticketpoolinfo RPC rejects
+// requests for more price buckets or windows than allowed.
+func TestHandleGetTicketPoolInfoLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		buckets uint32
+		windows uint32
+	}{{
+		name:    "no buckets",
+		buckets: 0,
+		windows: 8,
+	}, {
+		name:    "too many buckets",
+		buckets: maxTicketPoolInfoBuckets + 1,
+		windows: 8,
+	}, {
+		name:    "max uint32 buckets",
+		buckets: math.MaxUint32,
+		windows: 8,
+	}, {
+		name:    "windows beyond ticket expiry",
+		buckets: 10,
+		windows: 286,
+	}, {
+		name:    "max uint32 windows",
+		buckets: 10,
+		windows: math.MaxUint32,
+	}}
+
+	for _, test := range tests {
+		cmd := &types.GetTicketPoolInfoCmd{
+			Buckets: &test.buckets,
+			Windows: &test.windows,
+		}
+		_, err := handleGetTicketPoolInfo(nil, testServer, cmd)
+		var rpcErr *dcrjson.RPCError
+		if !errors.As(err, &rpcErr) ||
+			rpcErr.Code != dcrjson.ErrRPCInvalidParameter {
+
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+	}
+}
+
+// TestHandleGetVoteStatsLimits ensures the getvotestats RPC rejects requests
+// for a number of intervals outside of the allowed range.
+func TestHandleGetVoteStatsLimits(t *testing.T) {
+	for _, count := range []int32{-1, 0, maxVoteStatsIntervals + 1,
+		math.MaxInt32} {
+
+		cmd := &types.GetVoteStatsCmd{Count: &count}
+		_, err := handleGetVoteStats(nil, testServer, cmd)
+		var rpcErr *dcrjson.RPCError
+		if !errors.As(err, &rpcErr) ||
+			rpcErr.Code != dcrjson.ErrRPCInvalidParameter {
+
+			t.Fatalf("count %d: unexpected error: %v", count, err)
+		}
+	}
+}
+
+// TestVoteStatsForInterval ensures the vote version and agenda choice
+// statistics calculated for an interval are accurate.
+func TestVoteStatsForInterval(t *testing.T) {
+	votes := []stake.VoteVersionTuple{
+		{Version: 4, Bits: 0x0004}, // sdiffalgorithm yes, lnsupport abstain
+		{Version: 4, Bits: 0x000a}, // sdiffalgorithm no, lnsupport no
+		{Version: 4, Bits: 0x0006}, // sdiffalgorithm invalid, lnsupport abstain
+		{Version: 3, Bits: 0x0001}, // no agendas defined
+	}
+	got := voteStatsForInterval(testCfg.ChainParams, 10, 20, votes)
+	want := types.VoteStatsInterval{
+		StartHeight: 10,
+		EndHeight:   20,
+		NumVotes:    4,
+		VoteVersions: []types.VoteVersionStats{
+			{Version: 3, Count: 1, Percentage: 25},
+			{Version: 4, Count: 3, Percentage: 75},
+		},
+		Agendas: []types.AgendaVoteStats{{
+			ID:       "lnsupport",
+			Version:  4,
+			NumVotes: 3,
+			Choices: []types.VoteChoiceStats{
+				{ID: "abstain", Count: 2, Percentage: 200.0 / 3},
+				{ID: "no", Count: 1, Percentage: 100.0 / 3},
+				{ID: "yes", Count: 0, Percentage: 0},
+			},
+		}, {
+			ID:       "sdiffalgorithm",
+			Version:  4,
+			NumVotes: 2,
+			Choices: []types.VoteChoiceStats{
+				{ID: "abstain", Count: 0, Percentage: 0},
+				{ID: "no", Count: 1, Percentage: 50},
+				{ID: "yes", Count: 1, Percentage: 50},
+			},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mismatched vote stats:\ngot: %+v\nwant: %+v", got, want)
+	}
+}
+
+// TestEstimateTicketPurchases ensures the expected number of ticket purchases
+// and the associated bounds are calculated correctly.
+func TestEstimateTicketPurchases(t *testing.T) {
+	tests := []struct {
+		name        string
+		samples     []uint8
+		remaining   int64
+		maxPerBlock int64
+		lower       int64
+		expected    int64
+		upper       int64
+	}{{
+		name:        "no samples",
+		samples:     nil,
+		remaining:   10,
+		maxPerBlock: 20,
+	}, {
+		name:        "no remaining blocks",
+		samples:     []uint8{5, 5},
+		remaining:   0,
+		maxPerBlock: 20,
+	}, {
+		name:        "constant purchases",
+		samples:     []uint8{5, 5, 5, 5},
+		remaining:   10,
+		maxPerBlock: 20,
+		lower:       50,
+		expected:    50,
+		upper:       50,
+	}, {
+		name:        "varying purchases",
+		samples:     []uint8{0, 10, 0, 10},
+		remaining:   4,
+		maxPerBlock: 20,
+		lower:       0,
+		expected:    20,
+		upper:       40,
+	}, {
+		name:        "clamped to max purchases",
+		samples:     []uint8{20, 0, 20, 0},
+		remaining:   1,
+		maxPerBlock: 20,
+		lower:       0,
+		expected:    10,
+		upper:       20,
+	}}
+
+	for _, test := range tests {
+		lower, expected, upper := estimateTicketPurchases(test.samples,
+			test.remaining, test.maxPerBlock)
+		if lower != test.lower || expected != test.expected ||
+			upper != test.upper {
+
+			t.Errorf("%q: unexpected estimate -- got (%d, %d, %d), want "+
+				"(%d, %d, %d)", test.name, lower, expected, upper,
+				test.lower, test.expected, test.upper)
+		}
+	}
+}
+
+// TestPaginateHashes ensures the expected pages of hashes are selected by the
+// count and cursor pagination parameters.
+func TestPaginateHashes(t *testing.T) {
+	hash := func(c string) string { return strings.Repeat(c, 64) }
+	hashes := func() []string {
+		return []string{hash("3"), hash("1"), hash("4"), hash("2")}
+	}
+
+	tests := []struct {
+		name    string
+		count   *int
+		cursor  *string
+		want    []string
+		errCode dcrjson.RPCErrorCode
+	}{{
+		name: "no pagination keeps order",
+		want: hashes(),
+	}, {
+		name:  "first page",
+		count: dcrjson.Int(2),
+		want:  []string{hash("1"), hash("2")},
+	}, {
+		name:   "page after existing hash",
+		count:  dcrjson.Int(2),
+		cursor: dcrjson.String(hash("2")),
+		want:   []string{hash("3"), hash("4")},
+	}, {
+		name:   "page after missing hash",
+		cursor: dcrjson.String(hash("2")[:63] + "5"),
+		want:   []string{hash("3"), hash("4")},
+	}, {
+		name:   "short cursor",
+		count:  dcrjson.Int(1),
+		cursor: dcrjson.String("1"),
+		want:   []string{hash("1")},
+	}, {
+		name:   "last page",
+		count:  dcrjson.Int(2),
+		cursor: dcrjson.String(hash("4")),
+		want:   []string{},
+	}, {
+		name:    "zero count",
+		count:   dcrjson.Int(0),
+		errCode: dcrjson.ErrRPCInvalidParameter,
+	}, {
+		name:    "invalid cursor",
+		cursor:  dcrjson.String("zz"),
+		errCode: dcrjson.ErrRPCDecodeHexString,
+	}}
+
+	for _, test := range tests {
+		got, err := paginateHashes(hashes(), test.count, test.cursor)
+		if test.errCode != 0 {
+			var rpcErr *dcrjson.RPCError
+			if !errors.As(err, &rpcErr) || rpcErr.Code != test.errCode {
+				t.Errorf("%q: unexpected error -- got %v, want code %v",
+					test.name, err, test.errCode)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: unexpected page -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// testPrevScripts provides a source of previous output scripts for tests.
+type testPrevScripts map[wire.OutPoint][]byte
+
+// PrevScript returns the script associated with the provided outpoint.
+func (s testPrevScripts) PrevScript(prevOut *wire.OutPoint) (uint16, []byte, bool) {
+	script, ok := s[*prevOut]
+	return 0, script, ok
+}
+
+// TestAddVinPrevOuts ensures the previous output details are added to the
+// inputs that spend outputs available from the source of previous scripts.
+func TestAddVinPrevOuts(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	addr, err := dcrutil.DecodeAddress("DcurAwesomeAddressmqDctW5wJCW1Cn2MF",
+		params)
+	if err != nil {
+		t.Fatalf("unable to decode address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+
+	// Create a transaction that spends a known and an unknown output.
+	known := wire.OutPoint{Hash: chainhash.Hash{0x01}, Index: 1}
+	unknown := wire.OutPoint{Hash: chainhash.Hash{0x02}, Index: 0}
+	mtx := wire.NewMsgTx()
+	mtx.AddTxIn(wire.NewTxIn(&known, 150000000, nil))
+	mtx.AddTxIn(wire.NewTxIn(&unknown, 25000000, nil))
+	mtx.AddTxOut(wire.NewTxOut(100000000, pkScript))
+
+	vinList := createVinList(mtx)
+	prevScripts := testPrevScripts{known: pkScript}
+	addVinPrevOuts(vinList, mtx, prevScripts, params)
+
+	want := &types.PrevOut{
+		Addresses: []string{addr.Address()},
+		Value:     1.5,
+		Type:      "scripthash",
+	}
+	if !reflect.DeepEqual(vinList[0].PrevOut, want) {
+		t.Fatalf("unexpected prev out -- got %+v, want %+v",
+			vinList[0].PrevOut, want)
+	}
+	if vinList[1].PrevOut != nil {
+		t.Fatalf("unexpected prev out for unknown output: %+v",
+			vinList[1].PrevOut)
+	}
+}
+
+// TestAddressOutputs ensures the outputs that pay to an address are identified.
+func TestAddressOutputs(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	addr, err := dcrutil.DecodeAddress("DcurAwesomeAddressmqDctW5wJCW1Cn2MF",
+		params)
+	if err != nil {
+		t.Fatalf("unable to decode address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+
+	mtx := wire.NewMsgTx()
+	mtx.AddTxOut(wire.NewTxOut(1, pkScript))
+	mtx.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	mtx.AddTxOut(wire.NewTxOut(2, pkScript))
+
+	got := addressOutputs(mtx, addr.Address(), params)
+	if want := []uint32{0, 2}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected outputs -- got %v, want %v", got, want)
+	}
+	if got := addressOutputs(mtx, "DsUnknownAddress", params); got != nil {
+		t.Fatalf("unexpected outputs for unknown address: %v", got)
+	}
+}
+
+// TestBlockProposalRejectReason ensures rule errors for rejected block
+// proposals are converted to the expected BIP0023 reasons.
+func TestBlockProposalRejectReason(t *testing.T) {
+	tests := []struct {
+		code blockchain.ErrorCode
+		want string
+	}{
+		{code: blockchain.ErrDuplicateBlock, want: "duplicate"},
+		{code: blockchain.ErrMissingParent, want: "bad-prevblk"},
+		{code: blockchain.ErrInvalidTemplateParent, want: "bad-prevblk"},
+		{code: blockchain.ErrBadMerkleRoot, want: "bad-merkle-root"},
+		{code: blockchain.ErrBlockTooBig, want: "bad-block-too-big"},
+		{code: blockchain.ErrUnexpectedDifficulty, want: "bad-unexpected-difficulty"},
+		{code: blockchain.ErrBadStakebaseScrVal, want: "bad-stakebase-scr-val"},
+	}
+
+	for _, test := range tests {
+		rErr := blockchain.RuleError{ErrorCode: test.code}
+		if got := blockProposalRejectReason(rErr); got != test.want {
+			t.Errorf("%v: unexpected reason -- got %q, want %q", test.code,
+				got, test.want)
+		}
+	}
+}
+
+// TestNodeCapabilities ensures the optional subsystems reported as enabled
+// reflect the provided configuration.
+func TestNodeCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config
+		want []string
+	}{{
+		name: "default indexes",
+		want: []string{"existsaddrindex", "cfindex"},
+	}, {
+		name: "everything disabled",
+		cfg:  config{NoExistsAddrIndex: true, NoCFilters: true},
+		want: []string{},
+	}, {
+		name: "addrindex implies txindex",
+		cfg:  config{AddrIndex: true, NoExistsAddrIndex: true, NoCFilters: true},
+		want: []string{"txindex", "addrindex"},
+	}, {
+		name: "transports and metrics",
+		cfg: config{
+			NoExistsAddrIndex: true,
+			NoCFilters:        true,
+			OnionProxy:        "127.0.0.1:9050",
+			Upnp:              true,
+			MetricsListeners:  []string{"127.0.0.1:9120"},
+		},
+		want: []string{"tor", "upnp", "metrics"},
+	}, {
+		name: "proxy without onion",
+		cfg: config{
+			NoExistsAddrIndex: true,
+			NoCFilters:        true,
+			Proxy:             "127.0.0.1:9050",
+			NoOnion:           true,
+		},
+		want: []string{"proxy"},
+	}}
+
+	for _, test := range tests {
+		got := nodeCapabilities(&test.cfg)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: unexpected capabilities -- got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestAddrManBucketsInfo ensures the occupancy of address manager buckets is
+// summarized correctly.
+func TestAddrManBucketsInfo(t *testing.T) {
+	got := addrManBucketsInfo([]int{0, 1, 4, 0, 4, 3}, 4)
+	want := types.AddrManBucketsInfo{Count: 6, Size: 4, Used: 4, Full: 2}
+	if got != want {
+		t.Fatalf("unexpected buckets info -- got %+v, want %+v", got, want)
+	}
+}
@@ -35,6 +35,15 @@ var helpDescsEnUS = map[string]string{
 	"addnode-addr":      "IP address and port of the peer to operate on",
 	"addnode-subcmd":    "'add' to add a persistent peer, 'remove' to remove a persistent peer, or 'onetry' to try a single connection to a peer",
 
+	// AdvanceTimeCmd help.
+	"advancetime--synopsis": "Advances the adjusted time of the node by the provided number of seconds so time-dependent scenarios can be tested without waiting (simnet or regnet only).",
+	"advancetime-seconds":   "The number of seconds to advance the adjusted time by",
+	"advancetime--result0":  "The new adjusted time of the node in seconds since the Unix epoch",
+
+	// CompactDatabaseCmd help.
+	"compactdatabase--synopsis": "Compacts the database in order to reclaim space that is no longer used by live data and returns details about the storage used by it afterwards.\n" +
+		"Write operations are paused while the compaction is in progress, which can take a while for large databases.",
+
 	// NodeCmd help.
 	"node--synopsis":     "Attempts to add or remove a peer.",
 	"node-subcmd":        "'disconnect' to remove all matching non-persistent peers, 'remove' to remove a persistent peer, or 'connect' to connect to a peer",
@@ -95,6 +104,7 @@ var helpDescsEnUS = map[string]string{
 	// PrevOut help.
 	"prevout-addresses": "previous output addresses",
 	"prevout-value":     "previous output value",
+	"prevout-type":      "previous output script type",
 
 	// VinPrevOut help.
 	"vinprevout-coinbase":    "The hex-encoded bytes of the signature script (coinbase txns only)",
@@ -120,6 +130,7 @@ var helpDescsEnUS = map[string]string{
 	"vin-blockindex":  "The block idx of the origin transaction",
 	"vin-blockheight": "The block height of the origin transaction",
 	"vin-amountin":    "The amount in",
+	"vin-prevOut":     "Details of the previous output spent by the input (only for getblock with the prevout flag set)",
 
 	// ScriptPubKeyResult help.
 	"scriptpubkeyresult-asm":       "Disassembly of the script",
@@ -159,6 +170,10 @@ var helpDescsEnUS = map[string]string{
 	"decodescript-hexscript": "Hex-encoded script",
 	"decodescript-version":   "The script version, defaults to version 0 if not set.",
 
+	// DeleteAddressCmd help.
+	"deleteaddress--synopsis": "Removes a known peer address from the address manager so it is no longer selected for connections or relayed to other peers.",
+	"deleteaddress-addr":      "IP address and port of the known peer address to remove",
+
 	// ExistsAddressCmd help.
 	"existsaddress--synopsis": "Test for the existence of the provided address",
 	"existsaddress-address":   "The address to check",
@@ -194,12 +209,25 @@ var helpDescsEnUS = map[string]string{
 	"existsmempooltxs-txhashes":  "Array of hashes to check",
 	"existsmempooltxs--result0":  "Bool blob showing if txs exist in the mempool or not",
 
+	// ForceVoteCmd help.
+	"forcevote--synopsis": "Forces the outcome of the vote on an agenda to the provided choice regardless of the votes cast (simnet or regnet only).\n" +
+		"The agenda becomes active with any choice other than abstain or no, and it fails with the no choice.\n" +
+		"Forcing the abstain choice removes any previously forced outcome.  All nodes in the network must force the same outcomes to remain in consensus.",
+	"forcevote-agendaid": "The ID of the agenda to force the outcome of",
+	"forcevote-choiceid": "The ID of the choice to force",
+
 	// GenerateCmd help
 	"generate--synopsis": "Generates a set number of blocks (simnet or regtest only) and returns a JSON\n" +
 		" array of their hashes.",
 	"generate-numblocks": "Number of blocks to generate",
 	"generate--result0":  "The hashes, in order, of blocks generated by the call",
 
+	// GenerateToAddressCmd help.
+	"generatetoaddress--synopsis": "Generates a set number of blocks that pay to the provided address (simnet or regnet only) and returns a JSON array of their hashes.",
+	"generatetoaddress-numblocks": "Number of blocks to generate",
+	"generatetoaddress-address":   "The address the generated blocks pay to",
+	"generatetoaddress--result0":  "The hashes, in order, of blocks generated by the call",
+
 	// GetAddedNodeInfoResultAddr help.
 	"getaddednodeinforesultaddr-address":   "The ip address for this DNS entry",
 	"getaddednodeinforesultaddr-connected": "The connection 'direction' (inbound/outbound/false)",
@@ -217,6 +245,64 @@ var helpDescsEnUS = map[string]string{
 	"getaddednodeinfo--condition1": "dns=true",
 	"getaddednodeinfo--result0":    "List of added peers",
 
+	// GetAddressUTXOsCmd help.
+	"getaddressutxos--synopsis":      "Returns the unspent transaction outputs that pay to the given address in the order they were created, requires the address index (--addrindex).",
+	"getaddressutxos-address":        "The encoded address to return unspent outputs for",
+	"getaddressutxos-skip":           "The number of leading unspent outputs to leave out of the final result",
+	"getaddressutxos-count":          "The maximum number of unspent outputs to return",
+	"getaddressutxos-includemempool": "Include outputs created by unconfirmed transactions and leave out those they spend",
+
+	// GetAddressUTXOsResult help.
+	"getaddressutxosresult-txid":          "The hash of the transaction that created the output",
+	"getaddressutxosresult-vout":          "The index of the output",
+	"getaddressutxosresult-tree":          "The tree of the transaction that created the output",
+	"getaddressutxosresult-amount":        "The amount of the output in DCR",
+	"getaddressutxosresult-scriptpubkey":  "The hex-encoded public key script of the output",
+	"getaddressutxosresult-scriptversion": "The version of the public key script",
+	"getaddressutxosresult-height":        "The height of the block that contains the transaction (0 when unconfirmed)",
+	"getaddressutxosresult-confirmations": "The number of confirmations of the transaction (0 when unconfirmed)",
+
+	// GetAddrManInfoCmd help.
+	"getaddrmaninfo--synopsis": "Returns information about the known peer addresses and the buckets of the address manager.",
+	"getaddrmaninfo-verbose":   "Include information about every known address",
+
+	// GetAddrManInfoResult help.
+	"getaddrmaninforesult-new":                     "The number of addresses in the new buckets",
+	"getaddrmaninforesult-tried":                   "The number of addresses in the tried buckets",
+	"getaddrmaninforesult-networks":                "The number of known addresses by type of network",
+	"getaddrmaninforesult-networks--desc":          "The known addresses on each type of network",
+	"getaddrmaninforesult-networks--key":           "The type of network (local, ipv4, ipv6, onion, or cjdns)",
+	"getaddrmaninforesult-networks--value":         "The number of known addresses on the type of network",
+	"getaddrmaninforesult-newbuckets":              "The occupancy of the new buckets",
+	"getaddrmaninforesult-triedbuckets":            "The occupancy of the tried buckets",
+	"getaddrmaninforesult-useragents":              "The number of known addresses by the user agent most recently advertised by the peer at them",
+	"getaddrmaninforesult-useragents--desc":        "The known addresses with each user agent",
+	"getaddrmaninforesult-useragents--key":         "The user agent",
+	"getaddrmaninforesult-useragents--value":       "The number of known addresses with the user agent",
+	"getaddrmaninforesult-protocolversions":        "The number of known addresses by the protocol version most recently advertised by the peer at them",
+	"getaddrmaninforesult-protocolversions--desc":  "The known addresses with each protocol version",
+	"getaddrmaninforesult-protocolversions--key":   "The protocol version",
+	"getaddrmaninforesult-protocolversions--value": "The number of known addresses with the protocol version",
+	"getaddrmaninforesult-addresses":               "The known addresses (only when verbose)",
+	"addrmannetworkinfo-new":                       "The number of addresses in the new buckets",
+	"addrmannetworkinfo-tried":                     "The number of addresses in the tried buckets",
+	"addrmanbucketsinfo-count":                     "The number of buckets",
+	"addrmanbucketsinfo-size":                      "The maximum number of addresses in each bucket",
+	"addrmanbucketsinfo-used":                      "The number of buckets that contain any addresses",
+	"addrmanbucketsinfo-full":                      "The number of buckets that are full",
+	"addrmanaddressinfo-address":                   "The IP address and port of the known address",
+	"addrmanaddressinfo-source":                    "The IP address and port of the peer the address was learned from",
+	"addrmanaddressinfo-network":                   "The type of network the address belongs to",
+	"addrmanaddressinfo-services":                  "The services advertised for the address",
+	"addrmanaddressinfo-lastseen":                  "The time the address was last seen in seconds since 1 Jan 1970 GMT",
+	"addrmanaddressinfo-attempts":                  "The number of connection attempts since the last successful connection",
+	"addrmanaddressinfo-lastattempt":               "The time of the last connection attempt in seconds since 1 Jan 1970 GMT (0 when never attempted)",
+	"addrmanaddressinfo-lastsuccess":               "The time of the last successful connection in seconds since 1 Jan 1970 GMT (0 when never connected)",
+	"addrmanaddressinfo-tried":                     "Whether or not the address is in the tried buckets",
+	"addrmanaddressinfo-useragent":                 "The user agent most recently advertised by the peer at the address",
+	"addrmanaddressinfo-protocolversion":           "The protocol version most recently advertised by the peer at the address",
+	"addrmanaddressinfo-lastblock":                 "The height of the last block advertised by the peer at the address",
+
 	// GetBestBlockResult help.
 	"getbestblockresult-hash":   "Hex-encoded bytes of the best block hash",
 	"getbestblockresult-height": "Height of the best block",
@@ -234,10 +320,20 @@ var helpDescsEnUS = map[string]string{
 	"getblock-hash":        "The hash of the block",
 	"getblock-verbose":     "Specifies the block is returned as a JSON object instead of hex-encoded string",
 	"getblock-verbosetx":   "Specifies that each transaction is returned as a JSON object and only applies if the verbose flag is true (dcrd extension)",
+	"getblock-prevout":     "Specifies that the previous outputs spent by each input are included and only applies if the verbosetx flag is true (dcrd extension)",
 	"getblock--condition0": "verbose=false",
 	"getblock--condition1": "verbose=true",
 	"getblock--result0":    "Hex-encoded bytes of the serialized block",
 
+	// GetBlockByTimeCmd help.
+	"getblockbytime--synopsis": "Returns the block in the main chain with the timestamp closest to the given time, requires the block timestamp index (--timeindex).",
+	"getblockbytime-timestamp": "The time to find the nearest block for in seconds since 1 Jan 1970 GMT",
+
+	// GetBlockByTimeResult help.
+	"getblockbytimeresult-hash":   "The hash of the block",
+	"getblockbytimeresult-height": "The height of the block",
+	"getblockbytimeresult-time":   "The timestamp of the block in seconds since 1 Jan 1970 GMT",
+
 	// GetBlockchainInfoCmd help.
 	"getblockchaininfo--synopsis": "Returns information about the current state of the block chain.",
 
@@ -365,6 +461,12 @@ var helpDescsEnUS = map[string]string{
 	"getblockheaderverboseresult-extradata":         "Extra data field for the requested block",
 	"getblockheaderverboseresult-stakeversion":      "The stake version of the block",
 
+	// GetBlocksByTimeCmd help.
+	"getblocksbytime--synopsis": "Returns the blocks in the main chain with timestamps within the given range ordered by their timestamps, requires the block timestamp index (--timeindex).",
+	"getblocksbytime-start":     "The inclusive start of the time range in seconds since 1 Jan 1970 GMT",
+	"getblocksbytime-end":       "The exclusive end of the time range in seconds since 1 Jan 1970 GMT",
+	"getblocksbytime-count":     "The maximum number of blocks to return",
+
 	// GetBlockSubsidyCmd help.
 	"getblocksubsidy--synopsis": "Returns information regarding subsidy amounts.",
 	"getblocksubsidy-height":    "The block height",
@@ -376,6 +478,30 @@ var helpDescsEnUS = map[string]string{
 	"getblocksubsidyresult-pow":       "The Proof-of-Work subsidy",
 	"getblocksubsidyresult-total":     "The total subsidy",
 
+	// TemplateRequest help.
+	"templaterequest-mode": "The mode of the request, which must be 'proposal' since generating templates is not supported (use getwork instead)",
+	"templaterequest-data": "Hex-encoded serialized block to validate when the mode is 'proposal'",
+
+	// GetBlockTemplateCmd help.
+	"getblocktemplate--synopsis": "Fully validates a proposed block as if it were connected to the main chain without broadcasting it per BIP0023.\n" +
+		"The proof of work is not checked, so the block may be unsolved.",
+	"getblocktemplate-request":     "Request object with the mode and proposed block",
+	"getblocktemplate--condition0": "block proposal accepted",
+	"getblocktemplate--condition1": "block proposal rejected",
+	"getblocktemplate--result1": "The reason the block proposal was rejected, such as 'duplicate', 'bad-prevblk', " +
+		"or 'bad-' followed by the violated rule such as 'bad-merkle-root'",
+
+	// GetBuildInfoCmd help.
+	"getbuildinfo--synopsis": "Returns information about the build of the server and the optional subsystems that are enabled.",
+
+	// GetBuildInfoResult help.
+	"getbuildinforesult-version":      "The version of the server",
+	"getbuildinforesult-commit":       "The revision of the source code the server was built from or an empty string when it is not known",
+	"getbuildinforesult-goversion":    "The version of Go the server was built with",
+	"getbuildinforesult-os":           "The operating system the server was built for",
+	"getbuildinforesult-arch":         "The architecture the server was built for",
+	"getbuildinforesult-capabilities": "The enabled optional subsystems (txindex, addrindex, spendindex, timeindex, existsaddrindex, cfindex, proxy, tor, upnp, metrics, profiler)",
+
 	// GetCFilterCmd help.
 	"getcfilter--synopsis":  "Returns the committed filter for a block",
 	"getcfilter--result0":   "The committed filter serialized with the N value and encoded as a hex string",
@@ -398,6 +524,27 @@ var helpDescsEnUS = map[string]string{
 	"getcfilterv2result-proofindex":  "The index of the leaf that represents the filter hash in the header commitment",
 	"getcfilterv2result-proofhashes": "The hashes needed to prove the filter is committed to by the header commitment",
 
+	// GetCFilterV2HeadersCmd help.
+	"getcfilterv2headers--synopsis": "Returns the hashes of the version 2 block filters for a range of main chain blocks along with the block headers and the proofs that the filters are committed to by the headers.\n" +
+		"The headers form a chain that links to one another so light clients can efficiently verify the filters for long ranges of blocks.",
+	"getcfilterv2headers-startheight": "The height of the first block in the range",
+	"getcfilterv2headers-count":       "The maximum number of blocks to return (max: 2000)",
+
+	// GetCFilterV2HeaderResult help.
+	"getcfilterv2headerresult-blockhash":   "The hash of the block",
+	"getcfilterv2headerresult-height":      "The height of the block",
+	"getcfilterv2headerresult-header":      "Hex-encoded bytes of the serialized block header",
+	"getcfilterv2headerresult-filterhash":  "The hash of the version 2 filter for the block",
+	"getcfilterv2headerresult-proofindex":  "The index of the leaf that represents the filter hash in the header commitment",
+	"getcfilterv2headerresult-proofhashes": "The hashes needed to prove the filter hash is committed to by the header commitment",
+	"getcfilterv2headerresult-committed":   "Whether or not the block header commits to the filter (only true once the header commitments agenda is active)",
+
+	// GetCFiltersV2Cmd help.
+	"getcfiltersv2--synopsis": "Returns the version 2 block filters for a range of main chain blocks along with the proofs that can be used to prove the filters are committed to by the block headers.\n" +
+		"The filters are returned in order of increasing height and fewer filters than requested are returned when the range extends beyond the current best block.",
+	"getcfiltersv2-startheight": "The height of the first block in the range",
+	"getcfiltersv2-count":       "The maximum number of filters to return (max: 1000)",
+
 	// GetChainTips help.
 	"getchaintips--synopsis": "Returns information about all known chain tips the in the block tree.\n\n" +
 		"The statuses in the result have the following meanings:\n" +
@@ -422,6 +569,16 @@ var helpDescsEnUS = map[string]string{
 	"getcurrentnet--synopsis": "Get Decred network the server is running on.",
 	"getcurrentnet--result0":  "The network identifier",
 
+	// GetDatabaseInfoCmd help.
+	"getdatabaseinfo--synopsis":                 "Returns details about the type of the database and the storage used by it.",
+	"getdatabaseinforesult-type":                "The database driver type",
+	"getdatabaseinforesult-metadatafiles":       "The number of files that house the metadata",
+	"getdatabaseinforesult-metadatasize":        "The total size in bytes of the files that house the metadata",
+	"getdatabaseinforesult-metadatalivesize":    "The number of bytes of the metadata files that are used by live data",
+	"getdatabaseinforesult-metadatagarbagesize": "The number of bytes of the metadata files that can be reclaimed by compaction",
+	"getdatabaseinforesult-blockfiles":          "The number of flat files that house the blocks",
+	"getdatabaseinforesult-blockfilessize":      "The total size in bytes of the flat block files",
+
 	// GetDifficultyCmd help.
 	"getdifficulty--synopsis": "Returns the proof-of-work difficulty as a multiple of the minimum difficulty.",
 	"getdifficulty--result0":  "The difficulty",
@@ -457,6 +614,19 @@ var helpDescsEnUS = map[string]string{
 	"versionbits-version":                  "The version of the vote.",
 	"versionbits-bits":                     "The bits assigned by the vote.",
 
+	// GetSyncStatusCmd help.
+	"getsyncstatus--synopsis":                  "Returns details about the progress of syncing the chain with the network.",
+	"getsyncstatusresult-syncpeerid":           "The id of the peer being synced with or 0 when there is none",
+	"getsyncstatusresult-current":              "Whether or not the chain is believed to be current with the network",
+	"getsyncstatusresult-headersfirst":         "Whether or not headers are being downloaded prior to their blocks",
+	"getsyncstatusresult-blockheight":          "The height of the current best chain",
+	"getsyncstatusresult-headerheight":         "The height of the best known header",
+	"getsyncstatusresult-syncheight":           "The latest known block height being synced to",
+	"getsyncstatusresult-verificationprogress": "An estimate of the fraction of blocks that have been downloaded and verified",
+	"getsyncstatusresult-blockspersecond":      "The number of blocks processed per second over the last minute",
+	"getsyncstatusresult-bytespersecond":       "The number of block bytes processed per second over the last minute",
+	"getsyncstatusresult-etaseconds":           "The estimated number of seconds until the chain is synced or 0 when unknown or current",
+
 	// GetVoteInfo
 	"getvoteinfo--synopsis":           "Returns the vote info statistics.",
 	"getvoteinfo-version":             "The stake version.",
@@ -502,6 +672,9 @@ var helpDescsEnUS = map[string]string{
 	"infochainresult-difficulty":      "The current target difficulty",
 	"infochainresult-testnet":         "Whether or not server is using testnet",
 	"infochainresult-relayfee":        "The minimum relay fee for non-free transactions in DCR/KB",
+	"infochainresult-maxorphantx":     "The maximum number of orphan transactions kept in memory",
+	"infochainresult-maxpeers":        "The maximum number of inbound and outbound peers",
+	"infochainresult-banduration":     "The number of seconds misbehaving peers are banned for",
 	"infochainresult-errors":          "Any current errors",
 
 	// InfoWalletResult help.
@@ -528,6 +701,16 @@ var helpDescsEnUS = map[string]string{
 	"getheaders-hashstop":      "Block hash to stop including block headers for. Set to zero to get as many blocks as possible",
 	"getheadersresult-headers": "Serialized block headers of all located blocks, limited to some arbitrary maximum number of hashes (currently 2000, which matches the wire protocol headers message, but this is not guaranteed)",
 
+	// GetIndexInfoCmd help.
+	"getindexinfo--synopsis": "Returns the status of each enabled optional index including the progress of any indexes that are being caught up to the main chain.",
+
+	// GetIndexInfoResult help.
+	"getindexinforesult-name":         "The human-readable name of the index",
+	"getindexinforesult-height":       "The height of the current tip of the index",
+	"getindexinforesult-synced":       "Whether or not the index is caught up to the main chain",
+	"getindexinforesult-targetheight": "The height the index is being caught up to (only present while catching up)",
+	"getindexinforesult-eta":          "The estimated number of seconds remaining until the index is caught up (only present while catching up)",
+
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
@@ -535,8 +718,9 @@ var helpDescsEnUS = map[string]string{
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
 	// GetMempoolInfoResult help.
-	"getmempoolinforesult-bytes": "Size in bytes of the mempool",
-	"getmempoolinforesult-size":  "Number of transactions in the mempool",
+	"getmempoolinforesult-bytes":       "Size in bytes of the mempool",
+	"getmempoolinforesult-size":        "Number of transactions in the mempool",
+	"getmempoolinforesult-ratelimited": "Number of free or low-fee transactions rejected by the rate limiter",
 
 	// GetMiningInfoResult help.
 	"getmininginforesult-blocks":           "Height of the latest best block",
@@ -547,6 +731,7 @@ var helpDescsEnUS = map[string]string{
 	"getmininginforesult-errors":           "Any current errors",
 	"getmininginforesult-generate":         "Whether or not server is set to generate coins",
 	"getmininginforesult-genproclimit":     "Number of processors to use for coin generation (-1 when disabled)",
+	"getmininginforesult-generatepaused":   "Whether or not coin generation is paused",
 	"getmininginforesult-hashespersec":     "Recent hashes per second performance measurement while generating coins",
 	"getmininginforesult-networkhashps":    "Estimated network hashes per second for the most recent blocks",
 	"getmininginforesult-pooledtx":         "Number of transactions in the memory pool",
@@ -594,6 +779,14 @@ var helpDescsEnUS = map[string]string{
 	"getnettotalsresult-totalbytesrecv": "Total bytes received",
 	"getnettotalsresult-totalbytessent": "Total bytes sent",
 	"getnettotalsresult-timemillis":     "Number of milliseconds since 1 Jan 1970 GMT",
+	"getnettotalsresult-uploadtarget":   "Details about the upload target that limits the serving of historical blocks",
+
+	// UploadTargetResult help.
+	"uploadtargetresult-timeframe":             "The number of seconds in the rolling window the upload target applies to",
+	"uploadtargetresult-target":                "The maximum number of bytes to upload during the window (0 = unlimited)",
+	"uploadtargetresult-targetreached":         "Whether or not the upload target has been reached",
+	"uploadtargetresult-servehistoricalblocks": "Whether or not historical blocks are being served to non-whitelisted peers",
+	"uploadtargetresult-bytesleftincycle":      "The number of bytes that may be uploaded during the window before the target is reached",
 
 	// GetPeerInfoResult help.
 	"getpeerinforesult-id":             "A unique node ID",
@@ -609,6 +802,9 @@ var helpDescsEnUS = map[string]string{
 	"getpeerinforesult-timeoffset":     "The time offset of the peer",
 	"getpeerinforesult-pingtime":       "Number of microseconds the last ping took",
 	"getpeerinforesult-pingwait":       "Number of microseconds a queued ping has been waiting for a response",
+	"getpeerinforesult-pingp50":        "Median number of microseconds the most recent pings took",
+	"getpeerinforesult-pingp95":        "95th percentile number of microseconds the most recent pings took",
+	"getpeerinforesult-pingjitter":     "Mean absolute difference in microseconds between the times consecutive recent pings took",
 	"getpeerinforesult-version":        "The protocol version of the peer",
 	"getpeerinforesult-subver":         "The user agent of the peer",
 	"getpeerinforesult-inbound":        "Whether or not the peer is an inbound connection",
@@ -633,6 +829,8 @@ var helpDescsEnUS = map[string]string{
 	"getrawmempool--synopsis":   "Returns information about all of the transactions currently in the memory pool.",
 	"getrawmempool-verbose":     "Returns JSON object when true or an array of transaction hashes when false",
 	"getrawmempool-txtype":      "Type of tx to return. (all/regular/tickets/votes/revocations)",
+	"getrawmempool-count":       "Maximum number of transactions to return in ascending order of their hashes",
+	"getrawmempool-cursor":      "Only return transactions with hashes that sort after this hash, such as the final hash of the previous page",
 	"getrawmempool--condition0": "verbose=false",
 	"getrawmempool--condition1": "verbose=true",
 	"getrawmempool--result0":    "Array of transaction hashes",
@@ -645,6 +843,79 @@ var helpDescsEnUS = map[string]string{
 	"getrawtransaction--condition1": "verbose=true",
 	"getrawtransaction--result0":    "Hex-encoded bytes of the serialized transaction",
 
+	// GetRPCLimitInfoCmd help.
+	"getrpclimitinfo--synopsis": "Returns the configured per client RPC request limits along with statistics about the requests that have been limited.\n" +
+		"Clients are identified by their IP address and are only reported while they have recently made requests.",
+
+	// GetRPCLimitInfoResult help.
+	"getrpclimitinforesult-ratelimit":          "The max average number of requests per second allowed from each client (0 when disabled)",
+	"getrpclimitinforesult-rateburst":          "The max number of requests each client may make in a burst",
+	"getrpclimitinforesult-maxclientrequests":  "The max number of concurrent requests allowed from each client (0 when disabled)",
+	"getrpclimitinforesult-ratelimited":        "The total number of requests rejected due to exceeding the rate limit",
+	"getrpclimitinforesult-concurrencylimited": "The total number of requests rejected due to exceeding the concurrent request limit",
+	"getrpclimitinforesult-clients":            "Statistics for each recently active client",
+
+	// RPCClientLimitInfo help.
+	"rpcclientlimitinfo-address":            "The IP address of the client",
+	"rpcclientlimitinfo-inflight":           "The number of requests from the client that are currently being processed",
+	"rpcclientlimitinfo-requests":           "The number of requests made by the client",
+	"rpcclientlimitinfo-ratelimited":        "The number of requests from the client rejected due to exceeding the rate limit",
+	"rpcclientlimitinfo-concurrencylimited": "The number of requests from the client rejected due to exceeding the concurrent request limit",
+
+	// GetScriptStatsCmd help.
+	"getscriptstats--synopsis": "Returns the number of times each opcode and public key script class is used by the transactions in the given range of main chain blocks.\n" +
+		"The signature scripts of coinbase and stakebase inputs are not included and the range may not exceed 10000 blocks.",
+	"getscriptstats-startheight": "The height of the first block in the range",
+	"getscriptstats-endheight":   "The height of the last block in the range (default: the current best block)",
+
+	// GetScriptStatsResult help.
+	"getscriptstatsresult-startheight":           "The height of the first block in the range",
+	"getscriptstatsresult-endheight":             "The height of the last block in the range",
+	"getscriptstatsresult-numscripts":            "The total number of signature and public key scripts",
+	"getscriptstatsresult-numunparsable":         "The number of scripts that failed to parse (opcodes prior to the failure are still counted)",
+	"getscriptstatsresult-numunsupportedversion": "The number of public key scripts with an unsupported script version (their opcodes are not counted)",
+	"getscriptstatsresult-opcodes":               "The number of times each opcode is used",
+	"getscriptstatsresult-opcodes--desc":         "Opcode usage",
+	"getscriptstatsresult-opcodes--key":          "The name of the opcode",
+	"getscriptstatsresult-opcodes--value":        "The number of times the opcode is used",
+	"getscriptstatsresult-classes":               "The number of public key scripts of each class",
+	"getscriptstatsresult-classes--desc":         "Script class usage",
+	"getscriptstatsresult-classes--key":          "The name of the script class",
+	"getscriptstatsresult-classes--value":        "The number of public key scripts of the class",
+
+	// GetSpentInfoCmd help.
+	"getspentinfo--synopsis": "Returns the transaction input that spent the given output, requires the spend index (--spendindex).",
+	"getspentinfo-txid":      "The hash of the transaction that contains the output",
+	"getspentinfo-vout":      "The index of the output",
+
+	// GetSpentInfoResult help.
+	"getspentinforesult-txid":      "The hash of the transaction that spent the output",
+	"getspentinforesult-vin":       "The index of the input within the spending transaction",
+	"getspentinforesult-blockhash": "The hash of the block that contains the spending transaction",
+	"getspentinforesult-height":    "The height of the block that contains the spending transaction",
+
+	// GetTicketPoolInfoCmd help.
+	"getticketpoolinfo--synopsis": "Returns the composition of the live ticket pool by purchase price, age, and projected expiration",
+	"getticketpoolinfo-buckets":   "The number of evenly-sized purchase price buckets to distribute the live tickets into (max: 100)",
+	"getticketpoolinfo-windows":   "The number of stake difficulty windows to report the age distribution and projected expirations for (max: the number of windows spanned by the ticket expiry)",
+
+	// GetTicketPoolInfoResult help.
+	"getticketpoolinforesult-height":       "The height of the current best block",
+	"getticketpoolinforesult-poolsize":     "The number of live tickets in the pool",
+	"getticketpoolinforesult-pricebuckets": "The number of live tickets purchased within each price range",
+	"getticketpoolinforesult-ages":         "The number of live tickets purchased within each stake difficulty window, descending from the best block -- the final window includes all older tickets",
+	"getticketpoolinforesult-expirations":  "The number of live tickets that will expire within each upcoming stake difficulty window if they are not selected to vote",
+
+	// TicketPoolPriceBucket help.
+	"ticketpoolpricebucket-minprice": "The minimum purchase price in the bucket (inclusive)",
+	"ticketpoolpricebucket-maxprice": "The maximum purchase price in the bucket (inclusive)",
+	"ticketpoolpricebucket-count":    "The number of live tickets in the bucket",
+
+	// TicketPoolWindow help.
+	"ticketpoolwindow-startheight": "First block in the window (inclusive)",
+	"ticketpoolwindow-endheight":   "Last block in the window (inclusive)",
+	"ticketpoolwindow-count":       "The number of live tickets in the window",
+
 	// GetTicketPoolValue help.
 	"getticketpoolvalue--synopsis": "Return the current value of all locked funds in the ticket pool",
 	"getticketpoolvalue--result0":  "Total value of ticket pool",
@@ -681,6 +952,39 @@ var helpDescsEnUS = map[string]string{
 	"getworkresult-midstate": "(DEPRECATED) Hex-encoded precomputed hash state after hashing first half of the data",
 	"getworkresult-target":   "Hex-encoded little-endian hash target",
 
+	// GetVoteStatsCmd help.
+	"getvotestats--synopsis": "Returns vote version and agenda choice statistics for the votes included in blocks within the current and, optionally, prior stake version intervals",
+	"getvotestats-count":     "Number of intervals to return (max: 50 and the number of intervals up to the current best block)",
+
+	// GetVoteStatsResult help.
+	"getvotestatsresult-currentheight": "Current best block height",
+	"getvotestatsresult-hash":          "Current best block hash",
+	"getvotestatsresult-intervals":     "Array of interval statistics, starting with the current interval and descending from there",
+
+	// VoteStatsInterval help.
+	"votestatsinterval-startheight":  "Start of the interval",
+	"votestatsinterval-endheight":    "End of the interval",
+	"votestatsinterval-numvotes":     "Total number of votes included in blocks within the interval",
+	"votestatsinterval-voteversions": "Statistics for each vote version seen in the interval",
+	"votestatsinterval-agendas":      "Statistics for each agenda defined by the vote versions seen in the interval",
+
+	// VoteVersionStats help.
+	"voteversionstats-version":    "The vote version",
+	"voteversionstats-count":      "Number of votes cast with the version",
+	"voteversionstats-percentage": "Percentage of votes in the interval cast with the version",
+	"voteversionstats-trend":      "Change in percentage compared to the prior interval",
+
+	// AgendaVoteStats help.
+	"agendavotestats-id":       "Unique identifier of the agenda",
+	"agendavotestats-version":  "The vote version the agenda is defined by",
+	"agendavotestats-numvotes": "Number of valid votes cast on the agenda",
+	"agendavotestats-choices":  "Statistics for each choice of the agenda",
+
+	// VoteChoiceStats help.
+	"votechoicestats-id":         "Unique identifier of the choice",
+	"votechoicestats-count":      "Number of votes cast for the choice",
+	"votechoicestats-percentage": "Percentage of votes on the agenda cast for the choice",
+
 	// GetWorkCmd help.
 	"getwork--synopsis":   "Returns formatted hash data to work on or checks and submits solved data.",
 	"getwork-data":        "Hex-encoded data to check",
@@ -696,9 +1000,20 @@ var helpDescsEnUS = map[string]string{
 	"help--result0":    "List of commands",
 	"help--result1":    "Help for specified command",
 
+	// PauseGenerateCmd help.
+	"pausegenerate--synopsis": "Pause or resume coin generation (mining), including blocks being generated by the generate command.\n" +
+		"Generation remains enabled while paused, so it continues where it left off once resumed.",
+	"pausegenerate-pause": "Use true to pause generation, false to resume it",
+
 	// PingCmd help.
 	"ping--synopsis": "Queues a ping to be sent to each connected peer.\n" +
-		"Ping times are provided by getpeerinfo via the pingtime and pingwait fields.",
+		"Ping times are provided by getpeerinfo via the pingtime, pingwait, pingp50,\n" +
+		"pingp95, and pingjitter fields.",
+
+	// ProfileServerCmd help.
+	"profileserver--synopsis": "Starts or stops the HTTP profiling server which provides runtime profiling data in the format expected by the pprof visualization tool.",
+	"profileserver-subcmd":    "'start' to start the profiling server or 'stop' to stop it",
+	"profileserver-listen":    "The [addr:]port to listen on when starting the server where only a port listens on the loopback interface (default: 127.0.0.1:6061) -- NOTE port must be between 1024 and 65535",
 
 	// RebroadcastMissed help.
 	"rebroadcastmissed--synopsis": "Asks the daemon to rebroadcast missed votes.\n",
@@ -734,6 +1049,11 @@ var helpDescsEnUS = map[string]string{
 	"setgenerate-generate":     "Use true to enable generation, false to disable it",
 	"setgenerate-genproclimit": "The number of processors (cores) to limit generation to or -1 for default",
 
+	// SetPolicyCmd help.
+	"setpolicy--synopsis": "Changes a relay or connection policy setting without restarting the server.  Lowering maxpeers does not disconnect existing peers and a new banduration only applies to peers banned after the change.",
+	"setpolicy-setting":   "The setting to change: minrelaytxfee (DCR/kB), maxorphantx, maxpeers, or banduration (e.g. 12h)",
+	"setpolicy-value":     "The new value for the setting",
+
 	// StopCmd help.
 	"stop--synopsis": "Shutdown dcrd.",
 	"stop--result0":  "The string 'dcrd stopping.'",
@@ -786,6 +1106,9 @@ var helpDescsEnUS = map[string]string{
 	// NotifyNewTicketsCmd help
 	"notifynewtickets--synopsis": "Request notifications for whenever new tickets are found.",
 
+	// NotifyTicketLifecycleCmd help
+	"notifyticketlifecycle--synopsis": "Request notifications for whenever tickets become live, are selected to vote, vote, miss, expire, or are revoked.",
+
 	// NotifyStakeDifficultyCmd help
 	"notifystakedifficulty--synopsis": "Request notifications for whenever stake difficulty goes up.",
 
@@ -794,9 +1117,12 @@ var helpDescsEnUS = map[string]string{
 
 	// NotifyBlocksCmd help.
 	"notifyblocks--synopsis": "Request notifications for whenever a block is connected or disconnected from the main (best) chain.",
+	"notifyblocks-payload":   "Additional block data to include in blockconnected notifications (header: none, txids: the regular and stake transaction hashes, full: the serialized block) -- replaces any previously requested payload",
 
 	// NotifyWorkCmd help.
-	"notifywork--synopsis": "Request notifications for whenever a new block template is generated.",
+	"notifywork--synopsis":   "Request notifications for whenever a new block template is generated.",
+	"notifywork-verbose":     "Include additional details about the block template such as its identifier, height, and parent block in the notifications",
+	"notifywork-sharetarget": "Hex-encoded little-endian share target to include in the template details of the notifications, which implies verbose -- replaces any previously requested share target",
 
 	// StopNotifyBlocksCmd help.
 	"stopnotifyblocks--synopsis": "Cancel registered notifications for whenever a block is connected or disconnected from the main (best) chain.",
@@ -807,10 +1133,27 @@ var helpDescsEnUS = map[string]string{
 	// NotifyNewTransactionsCmd help.
 	"notifynewtransactions--synopsis": "Send either a txaccepted or a txacceptedverbose notification when a new transaction is accepted into the mempool.",
 	"notifynewtransactions-verbose":   "Specifies which type of notification to receive. If verbose is true, then the caller receives txacceptedverbose, otherwise the caller receives txaccepted",
+	"notifynewtransactions-filter":    "Restricts the notifications to transactions that match every specified criterion -- replaces any previously registered filter and all transactions are sent when omitted",
+
+	// NotifyNewTransactionsFilter help.
+	"notifynewtransactionsfilter-addresses":     "Matches transactions with an output that pays to any of the addresses",
+	"notifynewtransactionsfilter-scriptclasses": "Matches transactions with an output script of any of the classes (nonstandard/pubkey/pubkeyalt/pubkeyhash/pubkeyhashalt/scripthash/multisig/nulldata/stakesubmission/stakegen/stakerevoke/sstxchange)",
+	"notifynewtransactionsfilter-txtypes":       "Matches transactions of any of the types (regular/tickets/votes/revocations)",
 
 	// StopNotifyNewTransactionsCmd help.
 	"stopnotifynewtransactions--synopsis": "Stop sending either a txaccepted or a txacceptedverbose notification when a new transaction is accepted into the mempool.",
 
+	// NotifyUTXOsCmd help.
+	"notifyutxos--synopsis": "Send utxocreated and utxospent notifications whenever outputs that pay to any of the addresses or scripts are created or spent in the mempool or a block connected to the main chain.\n" +
+		"The addresses, scripts, and outpoints are added to any that were previously registered.\n" +
+		"Spends are reported for outputs created after they were registered and the provided outpoints, which are watched until they are spent in a block.",
+	"notifyutxos-addresses": "Array of addresses to register",
+	"notifyutxos-scripts":   "Array of hex-encoded output scripts to register",
+	"notifyutxos-outpoints": "Array of outpoints of existing unspent outputs to watch for spends",
+
+	// StopNotifyUTXOsCmd help.
+	"stopnotifyutxos--synopsis": "Stop sending utxocreated and utxospent notifications and remove all registered addresses, scripts, and outpoints.",
+
 	// OutPoint help.
 	"outpoint-hash":  "The hex-encoded bytes of the outpoint hash",
 	"outpoint-index": "The index of the outpoint",
@@ -840,11 +1183,13 @@ var helpDescsEnUS = map[string]string{
 	"estimatesmartfee--result0":      "Estimated fee rate (in DCR/KB).",
 
 	// EstimateStakeDiff help.
-	"estimatestakediff--synopsis":      "Estimate the next minimum, maximum, expected, and user-specified stake difficulty",
+	"estimatestakediff--synopsis":      "Estimate the next minimum, maximum, expected (with lower and upper bounds), and user-specified stake difficulty",
 	"estimatestakediff-tickets":        "Use this number of new tickets in blocks to estimate the next difficulty",
 	"estimatestakediffresult-min":      "Minimum estimate for stake difficulty",
 	"estimatestakediffresult-max":      "Maximum estimate for stake difficulty",
 	"estimatestakediffresult-expected": "Expected estimate for stake difficulty",
+	"estimatestakediffresult-lower":    "Lower bound of the expected estimate for stake difficulty based on the recent rate of ticket purchases (~95% confidence)",
+	"estimatestakediffresult-upper":    "Upper bound of the expected estimate for stake difficulty based on the recent rate of ticket purchases (~95% confidence)",
 	"estimatestakediffresult-user":     "Estimate for stake difficulty with the passed user amount of tickets",
 
 	// GetCoinSupply help
@@ -853,10 +1198,14 @@ var helpDescsEnUS = map[string]string{
 
 	// LiveTickets help.
 	"livetickets--synopsis":     "Returns live ticket hashes from the ticket database",
+	"livetickets-count":         "Maximum number of tickets to return in ascending order of their hashes",
+	"livetickets-cursor":        "Only return tickets with hashes that sort after this hash, such as the final hash of the previous page",
 	"liveticketsresult-tickets": "List of live tickets",
 
 	// MissedTickets help.
 	"missedtickets--synopsis":     "Returns missed ticket hashes from the ticket database",
+	"missedtickets-count":         "Maximum number of tickets to return in ascending order of their hashes",
+	"missedtickets-cursor":        "Only return tickets with hashes that sort after this hash, such as the final hash of the previous page",
 	"missedticketsresult-tickets": "List of missed tickets",
 
 	// TicketBuckets help.
@@ -936,6 +1285,14 @@ var helpDescsEnUS = map[string]string{
 	"version--result0--key":   "Program or API name",
 	"version--result0--value": "Object containing the semantic version",
 
+	// WriteProfileCmd help.
+	"writeprofile--synopsis": "Captures a runtime profile in the format expected by the pprof visualization tool and writes it to a file in the profiles directory of the home directory.\n" +
+		"The call does not return until the profile is written, which takes the requested number of seconds for a CPU profile.",
+	"writeprofile-profile":  "The profile to capture: 'cpu' or one of the runtime profiles such as 'heap', 'allocs', 'goroutine', 'block', 'mutex', or 'threadcreate'",
+	"writeprofile-file":     "The name of the file within the profiles directory to write the profile to",
+	"writeprofile-seconds":  "The number of seconds to capture a CPU profile for (max 600)",
+	"writeprofile--result0": "The full path of the written profile",
+
 	// regentemplate help
 	"regentemplate--synopsis": "Asks the node to regenerate its block mining template.",
 }
@@ -945,12 +1302,15 @@ var helpDescsEnUS = map[string]string{
 // pointer to the type (or nil to indicate no return value).
 var rpcResultTypes = map[types.Method][]interface{}{
 	"addnode":               nil,
+	"advancetime":           {(*int64)(nil)},
+	"compactdatabase":       {(*types.GetDatabaseInfoResult)(nil)},
 	"createrawsstx":         {(*string)(nil)},
 	"createrawssrtx":        {(*string)(nil)},
 	"createrawtransaction":  {(*string)(nil)},
 	"debuglevel":            {(*string)(nil), (*string)(nil)},
 	"decoderawtransaction":  {(*types.TxRawDecodeResult)(nil)},
 	"decodescript":          {(*types.DecodeScriptResult)(nil)},
+	"deleteaddress":         nil,
 	"estimatefee":           {(*float64)(nil)},
 	"estimatesmartfee":      {(*float64)(nil)},
 	"estimatestakediff":     {(*types.EstimateStakeDiffResult)(nil)},
@@ -962,21 +1322,32 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"existslivetickets":     {(*string)(nil)},
 	"existsmempooltxs":      {(*string)(nil)},
 	"getaddednodeinfo":      {(*[]string)(nil), (*[]types.GetAddedNodeInfoResult)(nil)},
+	"getaddressutxos":       {(*[]types.GetAddressUTXOsResult)(nil)},
+	"getaddrmaninfo":        {(*types.GetAddrManInfoResult)(nil)},
 	"getbestblock":          {(*types.GetBestBlockResult)(nil)},
+	"forcevote":             nil,
 	"generate":              {(*[]string)(nil)},
+	"generatetoaddress":     {(*[]string)(nil)},
 	"getbestblockhash":      {(*string)(nil)},
 	"getblock":              {(*string)(nil), (*types.GetBlockVerboseResult)(nil)},
+	"getblockbytime":        {(*types.GetBlockByTimeResult)(nil)},
 	"getblockchaininfo":     {(*types.GetBlockChainInfoResult)(nil)},
 	"getblockcount":         {(*int64)(nil)},
 	"getblockhash":          {(*string)(nil)},
 	"getblockheader":        {(*string)(nil), (*types.GetBlockHeaderVerboseResult)(nil)},
+	"getblocksbytime":       {(*[]types.GetBlockByTimeResult)(nil)},
 	"getblocksubsidy":       {(*types.GetBlockSubsidyResult)(nil)},
+	"getblocktemplate":      {nil, (*string)(nil)},
+	"getbuildinfo":          {(*types.GetBuildInfoResult)(nil)},
 	"getcfilter":            {(*string)(nil)},
 	"getcfilterheader":      {(*string)(nil)},
 	"getcfilterv2":          {(*types.GetCFilterV2Result)(nil)},
+	"getcfilterv2headers":   {(*[]types.GetCFilterV2HeaderResult)(nil)},
+	"getcfiltersv2":         {(*[]types.GetCFilterV2Result)(nil)},
 	"getchaintips":          {(*[]types.GetChainTipsResult)(nil)},
 	"getconnectioncount":    {(*int32)(nil)},
 	"getcurrentnet":         {(*uint32)(nil)},
+	"getdatabaseinfo":       {(*types.GetDatabaseInfoResult)(nil)},
 	"getdifficulty":         {(*float64)(nil)},
 	"getstakedifficulty":    {(*types.GetStakeDifficultyResult)(nil)},
 	"getstakeversioninfo":   {(*types.GetStakeVersionInfoResult)(nil)},
@@ -984,6 +1355,7 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"getgenerate":           {(*bool)(nil)},
 	"gethashespersec":       {(*float64)(nil)},
 	"getheaders":            {(*types.GetHeadersResult)(nil)},
+	"getindexinfo":          {(*[]types.GetIndexInfoResult)(nil)},
 	"getinfo":               {(*types.InfoChainResult)(nil)},
 	"getmempoolinfo":        {(*types.GetMempoolInfoResult)(nil)},
 	"getmininginfo":         {(*types.GetMiningInfoResult)(nil)},
@@ -993,21 +1365,30 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"getpeerinfo":           {(*[]types.GetPeerInfoResult)(nil)},
 	"getrawmempool":         {(*[]string)(nil), (*types.GetRawMempoolVerboseResult)(nil)},
 	"getrawtransaction":     {(*string)(nil), (*types.TxRawResult)(nil)},
+	"getrpclimitinfo":       {(*types.GetRPCLimitInfoResult)(nil)},
+	"getscriptstats":        {(*types.GetScriptStatsResult)(nil)},
+	"getspentinfo":          {(*types.GetSpentInfoResult)(nil)},
+	"getsyncstatus":         {(*types.GetSyncStatusResult)(nil)},
+	"getticketpoolinfo":     {(*types.GetTicketPoolInfoResult)(nil)},
 	"getticketpoolvalue":    {(*float64)(nil)},
 	"gettxout":              {(*types.GetTxOutResult)(nil)},
 	"gettxoutsetinfo":       {(*types.GetTxOutSetInfoResult)(nil)},
 	"getvoteinfo":           {(*types.GetVoteInfoResult)(nil)},
+	"getvotestats":          {(*types.GetVoteStatsResult)(nil)},
 	"getwork":               {(*types.GetWorkResult)(nil), (*bool)(nil)},
 	"getcoinsupply":         {(*int64)(nil)},
 	"help":                  {(*string)(nil), (*string)(nil)},
 	"livetickets":           {(*types.LiveTicketsResult)(nil)},
 	"missedtickets":         {(*types.MissedTicketsResult)(nil)},
 	"node":                  nil,
+	"pausegenerate":         nil,
 	"ping":                  nil,
+	"profileserver":         nil,
 	"regentemplate":         nil,
 	"searchrawtransactions": {(*string)(nil), (*[]types.SearchRawTransactionsResult)(nil)},
 	"sendrawtransaction":    {(*string)(nil)},
 	"setgenerate":           nil,
+	"setpolicy":             nil,
 	"stop":                  {(*string)(nil)},
 	"submitblock":           {nil, (*string)(nil)},
 	"ticketfeeinfo":         {(*types.TicketFeeInfoResult)(nil)},
@@ -1018,6 +1399,7 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"verifychain":           {(*bool)(nil)},
 	"verifymessage":         {(*bool)(nil)},
 	"version":               {(*map[string]types.VersionResult)(nil)},
+	"writeprofile":          {(*string)(nil)},
 
 	// Websocket commands.
 	"loadtxfilter":                nil,
@@ -1025,9 +1407,11 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"notifyspentandmissedtickets": nil,
 	"notifynewtickets":            nil,
 	"notifystakedifficulty":       nil,
+	"notifyticketlifecycle":       nil,
 	"notifyblocks":                nil,
 	"notifywork":                  nil,
 	"notifynewtransactions":       nil,
+	"notifyutxos":                 nil,
 	"notifyreceived":              nil,
 	"notifyspent":                 nil,
 	"rebroadcastmissed":           nil,
@@ -1037,6 +1421,7 @@ var rpcResultTypes = map[types.Method][]interface{}{
 	"stopnotifyblocks":            nil,
 	"stopnotifywork":              nil,
 	"stopnotifynewtransactions":   nil,
+	"stopnotifyutxos":             nil,
 	"stopnotifyreceived":          nil,
 	"stopnotifyspent":             nil,
 }
@@ -8,14 +8,13 @@ package main
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
-	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"strconv"
 	"sync"
 	"time"
@@ -74,7 +73,9 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 	"notifyspentandmissedtickets": handleSpentAndMissedTickets,
 	"notifynewtickets":            handleNewTickets,
 	"notifystakedifficulty":       handleStakeDifficulty,
+	"notifyticketlifecycle":       handleTicketLifecycle,
 	"notifynewtransactions":       handleNotifyNewTransactions,
+	"notifyutxos":                 handleNotifyUTXOs,
 	"rebroadcastmissed":           handleRebroadcastMissed,
 	"rebroadcastwinners":          handleRebroadcastWinners,
 	"rescan":                      handleRescan,
@@ -82,6 +83,7 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 	"stopnotifyblocks":            handleStopNotifyBlocks,
 	"stopnotifywork":              handleStopNotifyWork,
 	"stopnotifynewtransactions":   handleStopNotifyNewTransactions,
+	"stopnotifyutxos":             handleStopNotifyUTXOs,
 }
 
 // WebsocketHandler handles a new websocket client by creating a new wsClient,
@@ -89,7 +91,7 @@ var wsHandlersBeforeInit = map[types.Method]wsCommandHandler{
 // must be run in a separate goroutine.  It should be invoked from the websocket
 // server handler which runs each new connection in a new goroutine thereby
 // satisfying the requirement.
-func (s *rpcServer) WebsocketHandler(conn *websocket.Conn, remoteAddr string, authenticated bool, isAdmin bool) {
+func (s *rpcServer) WebsocketHandler(conn *websocket.Conn, remoteAddr string, authenticated bool, perm rpcPermission) {
 	// Clear the read deadline that was set before the websocket hijacked
 	// the connection.
 	conn.SetReadDeadline(timeZeroVal)
@@ -107,7 +109,7 @@ func (s *rpcServer) WebsocketHandler(conn *websocket.Conn, remoteAddr string, au
 	// Create a new websocket client to handle the new websocket connection
 	// and wait for it to shutdown.  Once it has shutdown (and hence
 	// disconnected), remove it and any notifications it registered for.
-	client, err := newWebsocketClient(s, conn, remoteAddr, authenticated, isAdmin)
+	client, err := newWebsocketClient(s, conn, remoteAddr, authenticated, perm)
 	if err != nil {
 		rpcsLog.Errorf("Failed to serve client %s: %v", remoteAddr, err)
 		conn.Close()
@@ -356,6 +358,30 @@ func (m *wsNotificationManager) NotifyNewTickets(
 	}
 }
 
+// NotifyTicketLifecycle passes ticket state transitions for an incoming block
+// from the best chain to the notification manager for block notification
+// processing.
+func (m *wsNotificationManager) NotifyTicketLifecycle(
+	tlnd *blockchain.TicketLifecycleNtfnsData) {
+	m.mtx.RLock()
+	if m.ctx == nil {
+		// Notification manager not started yet.
+		m.mtx.RUnlock()
+		return
+	}
+	ctx := m.ctx
+	m.mtx.RUnlock()
+
+	// As NotifyTicketLifecycle will be called by the block manager
+	// and the RPC server may no longer be running, use a select
+	// statement to unblock enqueuing the notification once the RPC
+	// server has begun shutting down.
+	select {
+	case m.queueNotification <- (*notificationTicketLifecycle)(tlnd):
+	case <-ctx.Done():
+	}
+}
+
 // NotifyNewTickets passes a new ticket data for an incoming block from the best
 // chain to the notification manager for block notification processing.
 func (m *wsNotificationManager) NotifyStakeDifficulty(
@@ -548,6 +574,186 @@ func (f *wsClientFilter) existsUnspentOutPoint(op *wire.OutPoint) bool {
 	return ok
 }
 
+// wsUTXOFilter houses the addresses and output scripts a websocket client has
+// registered to receive UTXO notifications for along with the outpoints of the
+// unspent outputs that are watched for spends.
+type wsUTXOFilter struct {
+	mu sync.Mutex
+
+	// addrs houses the registered addresses and the watched outpoints.
+	addrs *wsClientFilter
+
+	// scripts houses the registered output scripts.
+	scripts map[string]struct{}
+}
+
+// makeWSUTXOFilter returns a new empty UTXO filter that decodes addresses using
+// the provided parameters.
+func makeWSUTXOFilter(params dcrutil.AddressParams) *wsUTXOFilter {
+	return &wsUTXOFilter{
+		addrs:   makeWSClientFilter(nil, nil, params),
+		scripts: make(map[string]struct{}),
+	}
+}
+
+// matchesOutput returns whether or not an output with the provided script and
+// addresses extracted from it pays to a registered address or script.
+//
+// This function MUST be called with the filter lock held.
+func (f *wsUTXOFilter) matchesOutput(pkScript []byte, addrs []dcrutil.Address) bool {
+	if _, ok := f.scripts[string(pkScript)]; ok {
+		return true
+	}
+	for _, a := range addrs {
+		if f.addrs.existsAddress(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// newTxFilterScriptClasses houses the script classes that may be used to
+// filter new transaction notifications keyed by their names.
+var newTxFilterScriptClasses = func() map[string]txscript.ScriptClass {
+	classes := []txscript.ScriptClass{
+		txscript.NonStandardTy, txscript.PubKeyTy, txscript.PubkeyAltTy,
+		txscript.PubKeyHashTy, txscript.PubkeyHashAltTy,
+		txscript.ScriptHashTy, txscript.MultiSigTy, txscript.NullDataTy,
+		txscript.StakeSubmissionTy, txscript.StakeGenTy,
+		txscript.StakeRevocationTy, txscript.StakeSubChangeTy,
+	}
+	m := make(map[string]txscript.ScriptClass, len(classes))
+	for _, class := range classes {
+		m[class.String()] = class
+	}
+	return m
+}()
+
+// newTxFilterTxTypes houses the transaction types that may be used to filter
+// new transaction notifications keyed by the same names accepted by the
+// getrawmempool command.
+var newTxFilterTxTypes = map[types.GetRawMempoolTxTypeCmd]stake.TxType{
+	types.GRMRegular:     stake.TxTypeRegular,
+	types.GRMTickets:     stake.TxTypeSStx,
+	types.GRMVotes:       stake.TxTypeSSGen,
+	types.GRMRevocations: stake.TxTypeSSRtx,
+}
+
+// wsNewTxFilter restricts the new transaction notifications that are sent to
+// a websocket client.  A nil criterion matches all transactions.
+type wsNewTxFilter struct {
+	addrs   *wsClientFilter
+	classes map[txscript.ScriptClass]struct{}
+	txTypes map[stake.TxType]struct{}
+}
+
+// makeWSNewTxFilter returns a new transaction filter for the provided filter
+// parameters of a notifynewtransactions command.  A nil filter, which matches
+// all transactions, is returned when the parameters do not specify any
+// criteria.
+func makeWSNewTxFilter(f *types.NotifyNewTransactionsFilter, params *chaincfg.Params) (*wsNewTxFilter, error) {
+	if f == nil || (len(f.Addresses) == 0 && len(f.ScriptClasses) == 0 &&
+		len(f.TxTypes) == 0) {
+
+		return nil, nil
+	}
+
+	var filter wsNewTxFilter
+	if len(f.Addresses) > 0 {
+		filter.addrs = makeWSClientFilter(nil, nil, params)
+		for _, s := range f.Addresses {
+			a, err := dcrutil.DecodeAddress(s, params)
+			if err != nil {
+				return nil, rpcAddressKeyError("Could not decode "+
+					"address: %v", err)
+			}
+			filter.addrs.addAddress(a)
+		}
+	}
+	if len(f.ScriptClasses) > 0 {
+		filter.classes = make(map[txscript.ScriptClass]struct{})
+		for _, name := range f.ScriptClasses {
+			class, ok := newTxFilterScriptClasses[name]
+			if !ok {
+				return nil, rpcInvalidError("Invalid script class: %s",
+					name)
+			}
+			filter.classes[class] = struct{}{}
+		}
+	}
+	if len(f.TxTypes) > 0 {
+		filter.txTypes = make(map[stake.TxType]struct{})
+		for _, name := range f.TxTypes {
+			txType, ok := newTxFilterTxTypes[types.GetRawMempoolTxTypeCmd(name)]
+			if !ok {
+				supported := []types.GetRawMempoolTxTypeCmd{
+					types.GRMRegular, types.GRMTickets, types.GRMVotes,
+					types.GRMRevocations}
+				return nil, rpcInvalidError("Invalid transaction type: "+
+					"%s -- supported types: %v", name, supported)
+			}
+			filter.txTypes[txType] = struct{}{}
+		}
+	}
+	return &filter, nil
+}
+
+// newTxFilterOutput houses the details of a transaction output that are
+// needed to match it against new transaction filters.
+type newTxFilterOutput struct {
+	class txscript.ScriptClass
+	addrs []dcrutil.Address
+}
+
+// newTxFilterOutputs returns the details of all outputs of the provided
+// transaction that are needed to match it against new transaction filters.
+func newTxFilterOutputs(tx *wire.MsgTx, params *chaincfg.Params) []newTxFilterOutput {
+	outputs := make([]newTxFilterOutput, 0, len(tx.TxOut))
+	for _, txOut := range tx.TxOut {
+		class, addrs, _, _ := txscript.ExtractPkScriptAddrs(txOut.Version,
+			txOut.PkScript, params)
+		outputs = append(outputs, newTxFilterOutput{class, addrs})
+	}
+	return outputs
+}
+
+// matches returns whether or not a transaction of the provided type with the
+// provided output details matches the filter.  A nil filter matches all
+// transactions.
+func (f *wsNewTxFilter) matches(txType stake.TxType, outputs []newTxFilterOutput) bool {
+	if f == nil {
+		return true
+	}
+	if f.txTypes != nil {
+		if _, ok := f.txTypes[txType]; !ok {
+			return false
+		}
+	}
+	if f.classes != nil {
+		var found bool
+		for i := range outputs {
+			if _, ok := f.classes[outputs[i].class]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.addrs != nil {
+		for i := range outputs {
+			for _, a := range outputs[i].addrs {
+				if f.addrs.existsAddress(a) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
 // Notification types
 type notificationBlockConnected dcrutil.Block
 type notificationBlockDisconnected dcrutil.Block
@@ -556,6 +762,7 @@ type notificationReorganization blockchain.ReorganizationNtfnsData
 type notificationWinningTickets WinningTicketsNtfnData
 type notificationSpentAndMissedTickets blockchain.TicketNotificationsData
 type notificationNewTickets blockchain.TicketNotificationsData
+type notificationTicketLifecycle blockchain.TicketLifecycleNtfnsData
 type notificationStakeDifficulty StakeDifficultyNtfnData
 type notificationTxAcceptedByMempool struct {
 	isNew bool
@@ -575,10 +782,14 @@ type notificationRegisterSpentAndMissedTickets wsClient
 type notificationUnregisterSpentAndMissedTickets wsClient
 type notificationRegisterNewTickets wsClient
 type notificationUnregisterNewTickets wsClient
+type notificationRegisterTicketLifecycle wsClient
+type notificationUnregisterTicketLifecycle wsClient
 type notificationRegisterStakeDifficulty wsClient
 type notificationUnregisterStakeDifficulty wsClient
 type notificationRegisterNewMempoolTxs wsClient
 type notificationUnregisterNewMempoolTxs wsClient
+type notificationRegisterUTXOs wsClient
+type notificationUnregisterUTXOs wsClient
 
 // notificationHandler reads notifications and control messages from the queue
 // handler and processes one at a time.
@@ -598,8 +809,10 @@ func (m *wsNotificationManager) notificationHandler() {
 	winningTicketNotifications := make(map[chan struct{}]*wsClient)
 	ticketSMNotifications := make(map[chan struct{}]*wsClient)
 	ticketNewNotifications := make(map[chan struct{}]*wsClient)
+	ticketLifecycleNotifications := make(map[chan struct{}]*wsClient)
 	stakeDifficultyNotifications := make(map[chan struct{}]*wsClient)
 	txNotifications := make(map[chan struct{}]*wsClient)
+	utxoNotifications := make(map[chan struct{}]*wsClient)
 
 out:
 	for {
@@ -617,6 +830,10 @@ out:
 			case *notificationBlockConnected:
 				block := (*dcrutil.Block)(n)
 
+				if len(utxoNotifications) != 0 {
+					m.notifyUTXOsForBlock(utxoNotifications, block)
+				}
+
 				// Skip iterating through all txs if no tx
 				// notification requests exist.
 				if len(blockNotifications) == 0 {
@@ -648,6 +865,10 @@ out:
 				m.notifyNewTickets(ticketNewNotifications,
 					(*blockchain.TicketNotificationsData)(n))
 
+			case *notificationTicketLifecycle:
+				m.notifyTicketLifecycle(ticketLifecycleNotifications,
+					(*blockchain.TicketLifecycleNtfnsData)(n))
+
 			case *notificationStakeDifficulty:
 				m.notifyStakeDifficulty(stakeDifficultyNotifications,
 					(*StakeDifficultyNtfnData)(n))
@@ -656,6 +877,9 @@ out:
 				if n.isNew && len(txNotifications) != 0 {
 					m.notifyForNewTx(txNotifications, n.tx)
 				}
+				if n.isNew && len(utxoNotifications) != 0 {
+					m.notifyUTXOs(utxoNotifications, n.tx, nil)
+				}
 				m.notifyRelevantTxAccepted(n.tx, clients)
 
 			case *notificationRegisterBlocks:
@@ -698,6 +922,14 @@ out:
 				wsc := (*wsClient)(n)
 				delete(ticketNewNotifications, wsc.quit)
 
+			case *notificationRegisterTicketLifecycle:
+				wsc := (*wsClient)(n)
+				ticketLifecycleNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterTicketLifecycle:
+				wsc := (*wsClient)(n)
+				delete(ticketLifecycleNotifications, wsc.quit)
+
 			case *notificationRegisterStakeDifficulty:
 				wsc := (*wsClient)(n)
 				stakeDifficultyNotifications[wsc.quit] = wsc
@@ -720,7 +952,9 @@ out:
 				delete(winningTicketNotifications, wsc.quit)
 				delete(ticketSMNotifications, wsc.quit)
 				delete(ticketNewNotifications, wsc.quit)
+				delete(ticketLifecycleNotifications, wsc.quit)
 				delete(stakeDifficultyNotifications, wsc.quit)
+				delete(utxoNotifications, wsc.quit)
 				delete(clients, wsc.quit)
 
 			case *notificationRegisterNewMempoolTxs:
@@ -731,6 +965,14 @@ out:
 				wsc := (*wsClient)(n)
 				delete(txNotifications, wsc.quit)
 
+			case *notificationRegisterUTXOs:
+				wsc := (*wsClient)(n)
+				utxoNotifications[wsc.quit] = wsc
+
+			case *notificationUnregisterUTXOs:
+				wsc := (*wsClient)(n)
+				delete(utxoNotifications, wsc.quit)
+
 			default:
 				rpcsLog.Warn("Unhandled notification type")
 			}
@@ -880,6 +1122,43 @@ func (m *wsNotificationManager) notifyBlockConnected(clients map[chan struct{}]*
 	ntfn := types.BlockConnectedNtfn{
 		Header:        hex.EncodeToString(headerBytes),
 		SubscribedTxs: nil, // Set individually for each client
+		Payload:       nil, // Set individually for each client
+	}
+
+	// The additional block data that clients may request is only created
+	// when at least one client has requested it.
+	payloads := make(map[types.NotifyBlocksPayload]*types.BlockConnectedPayload)
+	blockPayload := func(payload types.NotifyBlocksPayload) *types.BlockConnectedPayload {
+		if p, ok := payloads[payload]; ok {
+			return p
+		}
+		var p *types.BlockConnectedPayload
+		switch payload {
+		case types.NBPTxIDs:
+			p = &types.BlockConnectedPayload{
+				TxIDs:  make([]string, 0, len(block.Transactions())),
+				STxIDs: make([]string, 0, len(block.STransactions())),
+			}
+			for _, tx := range block.Transactions() {
+				p.TxIDs = append(p.TxIDs, tx.Hash().String())
+			}
+			for _, tx := range block.STransactions() {
+				p.STxIDs = append(p.STxIDs, tx.Hash().String())
+			}
+
+		case types.NBPFull:
+			blockBytes, err := block.Bytes()
+			if err != nil {
+				rpcsLog.Errorf("Failed to serialize connected block "+
+					"%v: %v", block.Hash(), err)
+				break
+			}
+			p = &types.BlockConnectedPayload{
+				Block: hex.EncodeToString(blockBytes),
+			}
+		}
+		payloads[payload] = p
+		return p
 	}
 
 	// Search for relevant transactions for each client and save them
@@ -909,6 +1188,12 @@ func (m *wsNotificationManager) notifyBlockConnected(clients map[chan struct{}]*
 		// if any.
 		ntfn.SubscribedTxs = subscribedTxs[quitChan]
 
+		// Add the additional block data requested by the client, if any.
+		client.Lock()
+		payload := client.blockPayload
+		client.Unlock()
+		ntfn.Payload = blockPayload(payload)
+
 		// Marshal and queue notification.
 		marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, &ntfn)
 		if err != nil {
@@ -1013,29 +1298,58 @@ func (m *wsNotificationManager) notifyWork(clients map[chan struct{}]*wsClient,
 	// an artifact of some legacy internal state in the reference
 	// implementation, but it is required for compatibility.
 	target := bigToLEUint256(standalone.CompactToBig(header.Bits))
+	templateKey := getWorkTemplateKey(header)
 	ntfn := types.WorkNtfn{
 		Data:   hex.EncodeToString(data),
 		Target: hex.EncodeToString(target[:]),
 		Reason: updateReasonToWorkNtfnString(templateNtfn.Reason),
 	}
 
-	// Notify interested websocket clients about new mining work.
-	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, &ntfn)
-	if err != nil {
-		rpcsLog.Errorf("Failed to marshal new work notification: %v", err)
-		return
-	}
-
 	// Add the template to the template pool.  Since the key is a combination
 	// of the merkle and stake root fields, this will not add duplicate entries
 	// for the templates with modified timestamps and/or difficulty bits.
-	templateKey := getWorkTemplateKey(header)
 	state := m.server.workState
 	state.Lock()
 	state.templatePool[templateKey] = templateNtfn.Template.Block
 	state.Unlock()
 
+	// Notify interested websocket clients about new mining work.  Clients
+	// that requested template details receive a notification that includes
+	// them along with their share target, if any, so the notifications are
+	// marshalled lazily and cached by the requested share target.
+	type workNtfnKey struct {
+		details     bool
+		shareTarget string
+	}
+	marshalled := make(map[workNtfnKey][]byte)
 	for _, wsc := range clients {
+		wsc.Lock()
+		key := workNtfnKey{
+			details:     wsc.workDetails || wsc.workShareTarget != "",
+			shareTarget: wsc.workShareTarget,
+		}
+		wsc.Unlock()
+
+		marshalledJSON, ok := marshalled[key]
+		if !ok {
+			ntfn.Details = nil
+			if key.details {
+				ntfn.Details = &types.WorkNtfnDetails{
+					TemplateID:  hex.EncodeToString(templateKey[:]),
+					Height:      int64(header.Height),
+					PrevBlock:   header.PrevBlock.String(),
+					ShareTarget: key.shareTarget,
+				}
+			}
+			var err error
+			marshalledJSON, err = dcrjson.MarshalCmd("1.0", nil, &ntfn)
+			if err != nil {
+				rpcsLog.Errorf("Failed to marshal new work notification: "+
+					"%v", err)
+				return
+			}
+			marshalled[key] = marshalledJSON
+		}
 		wsc.QueueNotification(marshalledJSON)
 	}
 }
@@ -1156,6 +1470,18 @@ func (m *wsNotificationManager) UnregisterNewTickets(wsc *wsClient) {
 	m.queueNotification <- (*notificationUnregisterNewTickets)(wsc)
 }
 
+// RegisterTicketLifecycle requests ticket lifecycle notifications to the
+// passed websocket client.
+func (m *wsNotificationManager) RegisterTicketLifecycle(wsc *wsClient) {
+	m.queueNotification <- (*notificationRegisterTicketLifecycle)(wsc)
+}
+
+// UnregisterTicketLifecycle removes ticket lifecycle notifications for the
+// passed websocket client.
+func (m *wsNotificationManager) UnregisterTicketLifecycle(wsc *wsClient) {
+	m.queueNotification <- (*notificationUnregisterTicketLifecycle)(wsc)
+}
+
 // RegisterStakeDifficulty requests stake difficulty notifications
 // to the passed websocket client.
 func (m *wsNotificationManager) RegisterStakeDifficulty(wsc *wsClient) {
@@ -1192,6 +1518,41 @@ func (*wsNotificationManager) notifyNewTickets(clients map[chan struct{}]*wsClie
 	}
 }
 
+// hashStrings returns the string representations of the provided hashes.
+func hashStrings(hashes []chainhash.Hash) []string {
+	strs := make([]string, 0, len(hashes))
+	for i := range hashes {
+		strs = append(strs, hashes[i].String())
+	}
+	return strs
+}
+
+// notifyTicketLifecycle notifies websocket clients that have registered for
+// ticket lifecycle updates.
+func (*wsNotificationManager) notifyTicketLifecycle(clients map[chan struct{}]*wsClient, tlnd *blockchain.TicketLifecycleNtfnsData) {
+	// Skip marshalling the notification when there is nobody to send it to.
+	if len(clients) == 0 {
+		return
+	}
+
+	// Notify interested websocket clients about the connected block.
+	ntfn := types.NewTicketLifecycleNtfn(tlnd.Hash.String(),
+		int32(tlnd.Height), hashStrings(tlnd.Live),
+		hashStrings(tlnd.Selected), hashStrings(tlnd.Voted),
+		hashStrings(tlnd.Missed), hashStrings(tlnd.Expired),
+		hashStrings(tlnd.Revoked))
+
+	marshalledJSON, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+	if err != nil {
+		rpcsLog.Errorf("Failed to marshal ticket lifecycle notification: "+
+			"%v", err)
+		return
+	}
+	for _, wsc := range clients {
+		wsc.QueueNotification(marshalledJSON)
+	}
+}
+
 // notifyStakeDifficulty notifies websocket clients that have registered for
 // maturing ticket updates.
 func (*wsNotificationManager) notifyStakeDifficulty(clients map[chan struct{}]*wsClient, sdnd *StakeDifficultyNtfnData) {
@@ -1243,10 +1604,32 @@ func (m *wsNotificationManager) notifyForNewTx(clients map[chan struct{}]*wsClie
 		return
 	}
 
+	// The details needed to match the transaction against client filters
+	// are only determined when at least one client has a filter.
+	var txType stake.TxType
+	var outputs []newTxFilterOutput
+	var haveFilterDetails bool
+
 	var verboseNtfn *types.TxAcceptedVerboseNtfn
 	var marshalledJSONVerbose []byte
 	for _, wsc := range clients {
-		if wsc.verboseTxUpdates {
+		wsc.Lock()
+		filter := wsc.newTxFilter
+		verbose := wsc.verboseTxUpdates
+		wsc.Unlock()
+		if filter != nil {
+			if !haveFilterDetails {
+				txType = stake.DetermineTxType(mtx)
+				outputs = newTxFilterOutputs(mtx,
+					m.server.cfg.ChainParams)
+				haveFilterDetails = true
+			}
+			if !filter.matches(txType, outputs) {
+				continue
+			}
+		}
+
+		if verbose {
 			if marshalledJSONVerbose != nil {
 				wsc.QueueNotification(marshalledJSONVerbose)
 				continue
@@ -1274,6 +1657,111 @@ func (m *wsNotificationManager) notifyForNewTx(clients map[chan struct{}]*wsClie
 	}
 }
 
+// RegisterUTXOUpdates requests notifications to the passed websocket client
+// when outputs that match its UTXO filter are created or spent.
+func (m *wsNotificationManager) RegisterUTXOUpdates(wsc *wsClient) {
+	m.queueNotification <- (*notificationRegisterUTXOs)(wsc)
+}
+
+// UnregisterUTXOUpdates removes notifications to the passed websocket client
+// when outputs that match its UTXO filter are created or spent.
+func (m *wsNotificationManager) UnregisterUTXOUpdates(wsc *wsClient) {
+	m.queueNotification <- (*notificationUnregisterUTXOs)(wsc)
+}
+
+// notifyUTXOsForBlock notifies websocket clients that have registered for UTXO
+// updates about the outputs created and spent by the transactions of a block
+// that was connected to the main chain.
+func (m *wsNotificationManager) notifyUTXOsForBlock(clients map[chan struct{}]*wsClient, block *dcrutil.Block) {
+	for _, tx := range block.STransactions() {
+		m.notifyUTXOs(clients, tx, block)
+	}
+	for _, tx := range block.Transactions() {
+		m.notifyUTXOs(clients, tx, block)
+	}
+}
+
+// notifyUTXOs notifies websocket clients that have registered for UTXO updates
+// about the watched outputs spent by the passed transaction and the outputs it
+// creates that pay to a registered address or script.  The created outputs are
+// watched for spends from then on.  The block is nil when the transaction was
+// accepted to the memory pool.  Watched outputs are only removed once they are
+// spent by a transaction in a block connected to the main chain.
+func (m *wsNotificationManager) notifyUTXOs(clients map[chan struct{}]*wsClient, tx *dcrutil.Tx, block *dcrutil.Block) {
+	blockHash, blockHeight := "", int64(-1)
+	if block != nil {
+		blockHash = block.Hash().String()
+		blockHeight = block.Height()
+	}
+
+	// Extract the addresses of the outputs once for all clients.
+	msgTx := tx.MsgTx()
+	params := m.server.cfg.ChainParams
+	outputAddrs := make([][]dcrutil.Address, len(msgTx.TxOut))
+	for i, txOut := range msgTx.TxOut {
+		_, outputAddrs[i], _, _ = txscript.ExtractPkScriptAddrs(txOut.Version,
+			txOut.PkScript, params)
+	}
+
+	txHash := tx.Hash()
+	for _, wsc := range clients {
+		wsc.Lock()
+		f := wsc.utxoFilter
+		wsc.Unlock()
+		if f == nil {
+			continue
+		}
+
+		var ntfns []interface{}
+		f.mu.Lock()
+		for i, txIn := range msgTx.TxIn {
+			prevOut := &txIn.PreviousOutPoint
+			if !f.addrs.existsUnspentOutPoint(prevOut) {
+				continue
+			}
+			if block != nil {
+				delete(f.addrs.unspent, *prevOut)
+			}
+			op := types.OutPoint{
+				Hash:  prevOut.Hash.String(),
+				Tree:  prevOut.Tree,
+				Index: prevOut.Index,
+			}
+			ntfns = append(ntfns, types.NewUTXOSpentNtfn(op,
+				txHash.String(), uint32(i), blockHash, blockHeight))
+		}
+		for i, txOut := range msgTx.TxOut {
+			if !f.matchesOutput(txOut.PkScript, outputAddrs[i]) {
+				continue
+			}
+			outPoint := wire.OutPoint{
+				Hash:  *txHash,
+				Index: uint32(i),
+				Tree:  tx.Tree(),
+			}
+			f.addrs.addUnspentOutPoint(&outPoint)
+			op := types.OutPoint{
+				Hash:  txHash.String(),
+				Tree:  outPoint.Tree,
+				Index: outPoint.Index,
+			}
+			ntfns = append(ntfns, types.NewUTXOCreatedNtfn(op, txOut.Value,
+				hex.EncodeToString(txOut.PkScript), blockHash, blockHeight))
+		}
+		f.mu.Unlock()
+
+		for _, ntfn := range ntfns {
+			marshalled, err := dcrjson.MarshalCmd("1.0", nil, ntfn)
+			if err != nil {
+				rpcsLog.Errorf("Failed to marshal UTXO notification: %v",
+					err)
+				continue
+			}
+			wsc.QueueNotification(marshalled)
+		}
+	}
+}
+
 // txHexString returns the serialized transaction encoded in hexadecimal.
 func txHexString(tx *wire.MsgTx) string {
 	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
@@ -1437,9 +1925,9 @@ type wsClient struct {
 	// and therefore is allowed to communicated over the websocket.
 	authenticated bool
 
-	// isAdmin specifies whether a client may change the state of the server;
-	// false means its access is only to the limited set of RPC calls.
-	isAdmin bool
+	// perm specifies the set of RPC calls the client is authorized to
+	// invoke.
+	perm rpcPermission
 
 	// sessionID is a random ID generated for each client when connected.
 	// These IDs may be queried by a client using the session RPC.  A change
@@ -1450,8 +1938,27 @@ type wsClient struct {
 	// information about all new transactions.
 	verboseTxUpdates bool
 
+	// newTxFilter restricts the new transactions the client is notified
+	// about.  It is nil when the client is notified about all of them.
+	newTxFilter *wsNewTxFilter
+
+	// blockPayload specifies the additional block data a client has
+	// requested to be included in blockconnected notifications.
+	blockPayload types.NotifyBlocksPayload
+
+	// workDetails specifies whether a client has requested additional
+	// details about the block template in work notifications.
+	// workShareTarget is the hex-encoded little-endian share target the
+	// client has requested to be included in the details, if any.
+	workDetails     bool
+	workShareTarget string
+
 	filterData *wsClientFilter
 
+	// utxoFilter houses the addresses and output scripts the client has
+	// registered to receive UTXO notifications for.
+	utxoFilter *wsUTXOFilter
+
 	// Networking infrastructure.
 	serviceRequestSem semaphore
 	ntfnChan          chan []byte
@@ -1463,6 +1970,10 @@ type wsClient struct {
 // inHandler handles all incoming messages for the websocket connection.  It
 // must be run as a goroutine.
 func (c *wsClient) inHandler(ctx context.Context) {
+	// releaseBatch releases the client limiter reservation of the batched
+	// request that is being processed, if any.  It is also invoked when the
+	// loop below is exited while processing a batch.
+	releaseBatch := func() {}
 out:
 	for {
 		// Break out of the loop once the quit channel has been closed.
@@ -1573,15 +2084,13 @@ out:
 				// Check credentials.
 				login := authCmd.Username + ":" + authCmd.Passphrase
 				auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
-				authSha := sha256.Sum256([]byte(auth))
-				cmp := subtle.ConstantTimeCompare(authSha[:], c.rpcServer.authsha[:])
-				limitcmp := subtle.ConstantTimeCompare(authSha[:], c.rpcServer.limitauthsha[:])
-				if cmp != 1 && limitcmp != 1 {
+				perm := matchCredential(c.rpcServer.credentials, auth)
+				if perm == rpcPermNone {
 					rpcsLog.Warnf("Auth failure.")
 					break out
 				}
 				c.authenticated = true
-				c.isAdmin = cmp == 1
+				c.perm = perm
 
 				// Marshal and send response.
 				reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, nil)
@@ -1594,24 +2103,22 @@ out:
 				continue
 			}
 
-			// Check if the client is using limited RPC credentials and
-			// error when not authorized to call the supplied RPC.
-			if !c.isAdmin {
-				if _, ok := rpcLimited[req.Method]; !ok {
-					jsonErr := &dcrjson.RPCError{
-						Code:    dcrjson.ErrRPCInvalidParams.Code,
-						Message: "limited user not authorized for this method",
-					}
-					// Marshal and send response.
-					reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
-					if err != nil {
-						rpcsLog.Errorf("Failed to marshal parse failure "+
-							"reply: %v", err)
-						continue
-					}
-					c.SendMessage(reply, nil)
+			// Check if the credentials used by the client authorize it
+			// to call the supplied RPC and error when they do not.
+			if !c.perm.allows(req.Method) {
+				jsonErr := &dcrjson.RPCError{
+					Code:    dcrjson.ErrRPCInvalidParams.Code,
+					Message: fmt.Sprintf("%s user not authorized for this method", c.perm),
+				}
+				// Marshal and send response.
+				reply, err = createMarshalledReply("", req.ID, nil, jsonErr)
+				if err != nil {
+					rpcsLog.Errorf("Failed to marshal parse failure "+
+						"reply: %v", err)
 					continue
 				}
+				c.SendMessage(reply, nil)
+				continue
 			}
 
 			// Asynchronously handle the request.  A semaphore is used to
@@ -1634,10 +2141,31 @@ out:
 			// that also reads a time.After channel.  This will unblock the
 			// read of the next request from the websocket client and allow
 			// many requests to be waited on concurrently.
+			//
+			// The rate and number of concurrent requests across all of the
+			// connections from the same client are also limited.  Requests
+			// that exceed those limits are rejected with an error.
+			release, err := c.rpcServer.clientLimiter.acquire(c.addr)
+			if err != nil {
+				rpcsLog.Debugf("Limiting websocket client %s: %v",
+					c.addr, err)
+				jsonErr := &dcrjson.RPCError{
+					Code:    dcrjson.ErrRPCMisc,
+					Message: err.Error(),
+				}
+				reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, jsonErr)
+				if err != nil {
+					rpcsLog.Errorf("Failed to marshal limit reply: %v", err)
+					continue
+				}
+				c.SendMessage(reply, nil)
+				continue
+			}
 			c.serviceRequestSem.acquire()
 			go func() {
 				c.serviceRequest(ctx, cmd)
 				c.serviceRequestSem.release()
+				release()
 			}()
 		}
 
@@ -1647,6 +2175,29 @@ out:
 			var results []json.RawMessage
 			var batchSize int
 			var reply json.RawMessage
+
+			// Limit the rate and number of concurrent requests of
+			// authenticated clients.  An entire batch counts as a single
+			// request.
+			if c.authenticated {
+				release, err := c.rpcServer.clientLimiter.acquire(c.addr)
+				if err != nil {
+					rpcsLog.Debugf("Limiting websocket client %s: %v",
+						c.addr, err)
+					jsonErr := &dcrjson.RPCError{
+						Code:    dcrjson.ErrRPCMisc,
+						Message: err.Error(),
+					}
+					reply, err = dcrjson.MarshalResponse("2.0", nil, nil, jsonErr)
+					if err != nil {
+						rpcsLog.Errorf("Failed to marshal limit reply: %v", err)
+						continue
+					}
+					c.SendMessage(reply, nil)
+					continue
+				}
+				releaseBatch = release
+			}
 			c.serviceRequestSem.acquire()
 			err = json.Unmarshal(msg, &batchedRequests)
 			if err != nil {
@@ -1810,16 +2361,14 @@ out:
 							// Check credentials.
 							login := authCmd.Username + ":" + authCmd.Passphrase
 							auth := "Basic " + base64.StdEncoding.EncodeToString([]byte(login))
-							authSha := sha256.Sum256([]byte(auth))
-							cmp := subtle.ConstantTimeCompare(authSha[:], c.rpcServer.authsha[:])
-							limitcmp := subtle.ConstantTimeCompare(authSha[:], c.rpcServer.limitauthsha[:])
-							if cmp != 1 && limitcmp != 1 {
+							perm := matchCredential(c.rpcServer.credentials, auth)
+							if perm == rpcPermNone {
 								rpcsLog.Warnf("Auth failure.")
 								break out
 							}
 
 							c.authenticated = true
-							c.isAdmin = cmp == 1
+							c.perm = perm
 
 							// Marshal and send response.
 							reply, err = createMarshalledReply(cmd.jsonrpc, cmd.id, nil, nil)
@@ -1835,27 +2384,25 @@ out:
 							continue
 						}
 
-						// Check if the client is using limited RPC credentials and
-						// error when not authorized to call the supplied RPC.
-						if !c.isAdmin {
-							if _, ok := rpcLimited[req.Method]; !ok {
-								jsonErr := &dcrjson.RPCError{
-									Code:    dcrjson.ErrRPCInvalidParams.Code,
-									Message: "limited user not authorized for this method",
-								}
-								// Marshal and send response.
-								reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
-								if err != nil {
-									rpcsLog.Errorf("Failed to marshal parse failure "+
-										"reply: %v", err)
-									continue
-								}
-
-								if reply != nil {
-									results = append(results, reply)
-								}
+						// Check if the credentials used by the client authorize it
+						// to call the supplied RPC and error when they do not.
+						if !c.perm.allows(req.Method) {
+							jsonErr := &dcrjson.RPCError{
+								Code:    dcrjson.ErrRPCInvalidParams.Code,
+								Message: fmt.Sprintf("%s user not authorized for this method", c.perm),
+							}
+							// Marshal and send response.
+							reply, err = createMarshalledReply(req.Jsonrpc, req.ID, nil, jsonErr)
+							if err != nil {
+								rpcsLog.Errorf("Failed to marshal parse failure "+
+									"reply: %v", err)
 								continue
 							}
+
+							if reply != nil {
+								results = append(results, reply)
+							}
+							continue
 						}
 
 						// Lookup the websocket extension for the command, if it doesn't
@@ -1913,8 +2460,10 @@ out:
 
 			c.SendMessage(payload, nil)
 			c.serviceRequestSem.release()
+			releaseBatch()
 		}
 	}
+	releaseBatch()
 
 	// Ensure the connection is closed.
 	c.Disconnect()
@@ -2169,7 +2718,7 @@ func (c *wsClient) WaitForShutdown() {
 // incoming and outgoing messages in separate goroutines complete with queuing
 // and asynchronous handling for long-running operations.
 func newWebsocketClient(server *rpcServer, conn *websocket.Conn,
-	remoteAddr string, authenticated bool, isAdmin bool) (*wsClient, error) {
+	remoteAddr string, authenticated bool, perm rpcPermission) (*wsClient, error) {
 
 	sessionID, err := wire.RandomUint64()
 	if err != nil {
@@ -2180,7 +2729,7 @@ func newWebsocketClient(server *rpcServer, conn *websocket.Conn,
 		conn:              conn,
 		addr:              remoteAddr,
 		authenticated:     authenticated,
-		isAdmin:           isAdmin,
+		perm:              perm,
 		sessionID:         sessionID,
 		rpcServer:         server,
 		serviceRequestSem: makeSemaphore(cfg.RPCMaxConcurrentReqs),
@@ -2282,6 +2831,27 @@ func handleLoadTxFilter(wsc *wsClient, icmd interface{}) (interface{}, error) {
 // handleNotifyBlocks implements the notifyblocks command extension for
 // websocket connections.
 func handleNotifyBlocks(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.NotifyBlocksCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	payload := types.NBPHeader
+	if cmd.Payload != nil {
+		payload = types.NotifyBlocksPayload(*cmd.Payload)
+	}
+	switch payload {
+	case types.NBPHeader, types.NBPTxIDs, types.NBPFull:
+	default:
+		supported := []types.NotifyBlocksPayload{types.NBPHeader,
+			types.NBPTxIDs, types.NBPFull}
+		return nil, rpcInvalidError("Invalid payload: %s -- supported "+
+			"payloads: %v", payload, supported)
+	}
+
+	wsc.Lock()
+	wsc.blockPayload = payload
+	wsc.Unlock()
 	wsc.rpcServer.ntfnMgr.RegisterBlockUpdates(wsc)
 	return nil, nil
 }
@@ -2347,6 +2917,32 @@ func handleRebroadcastWinners(wsc *wsClient, icmd interface{}) (interface{}, err
 // handleNotifyWork implements the notifywork command extension for
 // websocket connections.
 func handleNotifyWork(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.NotifyWorkCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	// The share target must be a non-zero hex-encoded little-endian
+	// uint256 in the same format as the target of work notifications.
+	var shareTarget string
+	if cmd.ShareTarget != nil {
+		target, err := hex.DecodeString(*cmd.ShareTarget)
+		if err != nil || len(target) != uint256Size {
+			return nil, rpcInvalidError("Invalid share target: %q -- "+
+				"must be a %d-byte hex-encoded little-endian target",
+				*cmd.ShareTarget, uint256Size)
+		}
+		if new(big.Int).SetBytes(target).Sign() == 0 {
+			return nil, rpcInvalidError("Invalid share target: %q -- "+
+				"must not be zero", *cmd.ShareTarget)
+		}
+		shareTarget = hex.EncodeToString(target)
+	}
+
+	wsc.Lock()
+	wsc.workDetails = cmd.Verbose != nil && *cmd.Verbose
+	wsc.workShareTarget = shareTarget
+	wsc.Unlock()
 	wsc.rpcServer.ntfnMgr.RegisterWorkUpdates(wsc)
 	return nil, nil
 }
@@ -2378,6 +2974,13 @@ func handleNewTickets(wsc *wsClient, icmd interface{}) (interface{}, error) {
 	return nil, nil
 }
 
+// handleTicketLifecycle implements the notifyticketlifecycle command extension
+// for websocket connections.
+func handleTicketLifecycle(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.RegisterTicketLifecycle(wsc)
+	return nil, nil
+}
+
 // handleStakeDifficulty implements the notifystakedifficulty command extension
 // for websocket connections.
 func handleStakeDifficulty(wsc *wsClient, icmd interface{}) (interface{}, error) {
@@ -2407,7 +3010,16 @@ func handleNotifyNewTransactions(wsc *wsClient, icmd interface{}) (interface{},
 		return nil, dcrjson.ErrRPCInternal
 	}
 
+	filter, err := makeWSNewTxFilter(cmd.Filter,
+		wsc.rpcServer.cfg.ChainParams)
+	if err != nil {
+		return nil, err
+	}
+
+	wsc.Lock()
 	wsc.verboseTxUpdates = cmd.Verbose != nil && *cmd.Verbose
+	wsc.newTxFilter = filter
+	wsc.Unlock()
 	wsc.rpcServer.ntfnMgr.RegisterNewMempoolTxsUpdates(wsc)
 	return nil, nil
 }
@@ -2419,6 +3031,85 @@ func handleStopNotifyNewTransactions(wsc *wsClient, icmd interface{}) (interface
 	return nil, nil
 }
 
+// handleNotifyUTXOs implements the notifyutxos command extension for websocket
+// connections.
+func handleNotifyUTXOs(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	cmd, ok := icmd.(*types.NotifyUTXOsCmd)
+	if !ok {
+		return nil, dcrjson.ErrRPCInternal
+	}
+
+	// Decode all parameters before modifying the filter so it is not
+	// partially updated when any of them are invalid.
+	params := wsc.rpcServer.cfg.ChainParams
+	addrs := make([]dcrutil.Address, 0, len(cmd.Addresses))
+	for _, s := range cmd.Addresses {
+		a, err := dcrutil.DecodeAddress(s, params)
+		if err != nil {
+			return nil, rpcAddressKeyError("Could not decode address: %v",
+				err)
+		}
+		addrs = append(addrs, a)
+	}
+	scripts := make([][]byte, 0, len(cmd.Scripts))
+	for _, s := range cmd.Scripts {
+		script, err := hex.DecodeString(s)
+		if err != nil || len(script) == 0 {
+			return nil, rpcDecodeHexError(s)
+		}
+		scripts = append(scripts, script)
+	}
+	outPoints := make([]wire.OutPoint, 0, len(cmd.OutPoints))
+	for _, op := range cmd.OutPoints {
+		hash, err := chainhash.NewHashFromStr(op.Hash)
+		if err != nil {
+			return nil, rpcDecodeHexError(op.Hash)
+		}
+		if op.Tree != wire.TxTreeRegular && op.Tree != wire.TxTreeStake {
+			return nil, rpcInvalidError("Tx tree must be %d or %d",
+				wire.TxTreeRegular, wire.TxTreeStake)
+		}
+		outPoints = append(outPoints, wire.OutPoint{
+			Hash:  *hash,
+			Index: op.Index,
+			Tree:  op.Tree,
+		})
+	}
+
+	wsc.Lock()
+	if wsc.utxoFilter == nil {
+		wsc.utxoFilter = makeWSUTXOFilter(params)
+	}
+	filter := wsc.utxoFilter
+	wsc.Unlock()
+
+	filter.mu.Lock()
+	for _, a := range addrs {
+		filter.addrs.addAddress(a)
+	}
+	for _, script := range scripts {
+		filter.scripts[string(script)] = struct{}{}
+	}
+	for i := range outPoints {
+		filter.addrs.addUnspentOutPoint(&outPoints[i])
+	}
+	filter.mu.Unlock()
+
+	wsc.rpcServer.ntfnMgr.RegisterUTXOUpdates(wsc)
+	return nil, nil
+}
+
+// handleStopNotifyUTXOs implements the stopnotifyutxos command extension for
+// websocket connections.
+func handleStopNotifyUTXOs(wsc *wsClient, icmd interface{}) (interface{}, error) {
+	wsc.rpcServer.ntfnMgr.UnregisterUTXOUpdates(wsc)
+
+	wsc.Lock()
+	wsc.utxoFilter = nil
+	wsc.Unlock()
+	return nil, nil
+}
+
 // rescanBlock rescans a block for any relevant transactions for the passed
 // lookup keys.  Any discovered transactions are returned hex encoded as a
 // string slice.
@@ -0,0 +1,258 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/rpc/jsonrpc/types/v2"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestNewTxFilter ensures new transaction notification filters are created
+// from the notifynewtransactions filter parameters and match transactions as
+// expected.
+func TestNewTxFilter(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	const addrStr = "Dsnx1HW62otMif9zFyLzDnQdKuaV9cRNoyr"
+	addr, err := dcrutil.DecodeAddress(addrStr, params)
+	if err != nil {
+		t.Fatalf("unable to decode address: %v", err)
+	}
+	otherAddr, err := dcrutil.NewAddressPubKeyHash(make([]byte, 20), params,
+		dcrec.STEcdsaSecp256k1)
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	tx := wire.NewMsgTx()
+	tx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	tx.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	txType := stake.DetermineTxType(tx)
+	outputs := newTxFilterOutputs(tx, params)
+
+	tests := []struct {
+		name    string
+		filter  *types.NotifyNewTransactionsFilter
+		nilFltr bool
+		wantErr bool
+		matches bool
+	}{{
+		name:    "no filter",
+		nilFltr: true,
+		matches: true,
+	}, {
+		name:    "empty filter",
+		filter:  &types.NotifyNewTransactionsFilter{},
+		nilFltr: true,
+		matches: true,
+	}, {
+		name: "matching address",
+		filter: &types.NotifyNewTransactionsFilter{
+			Addresses: []string{addrStr},
+		},
+		matches: true,
+	}, {
+		name: "other address",
+		filter: &types.NotifyNewTransactionsFilter{
+			Addresses: []string{otherAddr.Address()},
+		},
+		matches: false,
+	}, {
+		name: "invalid address",
+		filter: &types.NotifyNewTransactionsFilter{
+			Addresses: []string{"invalid"},
+		},
+		wantErr: true,
+	}, {
+		name: "matching script class",
+		filter: &types.NotifyNewTransactionsFilter{
+			ScriptClasses: []string{"stakesubmission", "nulldata"},
+		},
+		matches: true,
+	}, {
+		name: "other script class",
+		filter: &types.NotifyNewTransactionsFilter{
+			ScriptClasses: []string{"scripthash"},
+		},
+		matches: false,
+	}, {
+		name: "invalid script class",
+		filter: &types.NotifyNewTransactionsFilter{
+			ScriptClasses: []string{"bogus"},
+		},
+		wantErr: true,
+	}, {
+		name: "matching tx type",
+		filter: &types.NotifyNewTransactionsFilter{
+			TxTypes: []string{"regular"},
+		},
+		matches: true,
+	}, {
+		name: "other tx type",
+		filter: &types.NotifyNewTransactionsFilter{
+			TxTypes: []string{"tickets", "votes", "revocations"},
+		},
+		matches: false,
+	}, {
+		name: "invalid tx type",
+		filter: &types.NotifyNewTransactionsFilter{
+			TxTypes: []string{"all"},
+		},
+		wantErr: true,
+	}, {
+		name: "all criteria must match",
+		filter: &types.NotifyNewTransactionsFilter{
+			Addresses: []string{addrStr},
+			TxTypes:   []string{"tickets"},
+		},
+		matches: false,
+	}}
+
+	for _, test := range tests {
+		filter, err := makeWSNewTxFilter(test.filter, params)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%q: did not receive expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		if (filter == nil) != test.nilFltr {
+			t.Errorf("%q: unexpected nil filter -- got %v, want %v",
+				test.name, filter == nil, test.nilFltr)
+			continue
+		}
+		if got := filter.matches(txType, outputs); got != test.matches {
+			t.Errorf("%q: unexpected match -- got %v, want %v", test.name,
+				got, test.matches)
+		}
+	}
+}
+
+// TestNotifyUTXOs ensures websocket clients are notified about outputs that pay
+// to their registered addresses and scripts along with the spends of them.
+func TestNotifyUTXOs(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	addr, err := dcrutil.DecodeAddress("Dsnx1HW62otMif9zFyLzDnQdKuaV9cRNoyr",
+		params)
+	if err != nil {
+		t.Fatalf("unable to decode address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	script := []byte{txscript.OP_TRUE}
+
+	m := &wsNotificationManager{
+		server: &rpcServer{cfg: rpcserverConfig{ChainParams: params}},
+	}
+	wsc := &wsClient{
+		ntfnChan:   make(chan []byte, 10),
+		quit:       make(chan struct{}),
+		utxoFilter: makeWSUTXOFilter(params),
+	}
+	wsc.utxoFilter.addrs.addAddress(addr)
+	wsc.utxoFilter.scripts[string(script)] = struct{}{}
+	clients := map[chan struct{}]*wsClient{wsc.quit: wsc}
+
+	// ntfns returns the methods and block heights of the queued
+	// notifications.
+	ntfns := func() ([]string, []int64) {
+		var methods []string
+		var heights []int64
+		for {
+			select {
+			case marshalled := <-wsc.ntfnChan:
+				var ntfn struct {
+					Method string            `json:"method"`
+					Params []json.RawMessage `json:"params"`
+				}
+				if err := json.Unmarshal(marshalled, &ntfn); err != nil {
+					t.Fatalf("unable to unmarshal notification: %v", err)
+				}
+				var height int64
+				err := json.Unmarshal(ntfn.Params[len(ntfn.Params)-1],
+					&height)
+				if err != nil {
+					t.Fatalf("unable to unmarshal height: %v", err)
+				}
+				methods = append(methods, ntfn.Method)
+				heights = append(heights, height)
+			default:
+				return methods, heights
+			}
+		}
+	}
+
+	// Ensure outputs paying to the registered address and script are
+	// reported as created when the transaction is accepted to the mempool.
+	createTx := wire.NewMsgTx()
+	createTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	createTx.AddTxOut(wire.NewTxOut(1e8, pkScript))
+	createTx.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_RETURN}))
+	createTx.AddTxOut(wire.NewTxOut(2e8, script))
+	tx := dcrutil.NewTx(createTx)
+	tx.SetTree(wire.TxTreeRegular)
+	m.notifyUTXOs(clients, tx, nil)
+	methods, heights := ntfns()
+	if len(methods) != 2 || methods[0] != "utxocreated" ||
+		methods[1] != "utxocreated" || heights[0] != -1 {
+
+		t.Fatalf("unexpected mempool notifications: %v %v", methods,
+			heights)
+	}
+
+	// Ensure a spend of a watched output is reported when the transaction
+	// is accepted to the mempool and again when it is included in a block,
+	// after which the output is no longer watched.
+	createHash := createTx.TxHash()
+	spentOut := wire.OutPoint{Hash: createHash, Index: 0}
+	spendTx := wire.NewMsgTx()
+	spendTx.AddTxIn(wire.NewTxIn(&spentOut, 1e8, nil))
+	spendTx.AddTxOut(wire.NewTxOut(1e8, []byte{txscript.OP_RETURN}))
+	tx = dcrutil.NewTx(spendTx)
+	tx.SetTree(wire.TxTreeRegular)
+	m.notifyUTXOs(clients, tx, nil)
+	methods, heights = ntfns()
+	if len(methods) != 1 || methods[0] != "utxospent" || heights[0] != -1 {
+		t.Fatalf("unexpected mempool spend notifications: %v %v", methods,
+			heights)
+	}
+	block := dcrutil.NewBlock(&wire.MsgBlock{
+		Header: wire.BlockHeader{
+			PrevBlock: chainhash.Hash{0x01},
+			Height:    100,
+		},
+		Transactions: []*wire.MsgTx{spendTx},
+	})
+	m.notifyUTXOsForBlock(clients, block)
+	methods, heights = ntfns()
+	if len(methods) != 1 || methods[0] != "utxospent" || heights[0] != 100 {
+		t.Fatalf("unexpected block spend notifications: %v %v", methods,
+			heights)
+	}
+	if wsc.utxoFilter.addrs.existsUnspentOutPoint(&spentOut) {
+		t.Fatal("spent output is still watched")
+	}
+	m.notifyUTXOsForBlock(clients, block)
+	if methods, _ = ntfns(); len(methods) != 0 {
+		t.Fatalf("unexpected notifications for spent output: %v", methods)
+	}
+}
@@ -33,6 +33,12 @@ const FileContents = `[Application Options]
 ; Use simnet.
 ; simnet=1
 
+; Use a custom private network defined by a network parameters file.  The
+; network is derived from regnet or simnet and may override the magic bytes,
+; ports, genesis timestamp, and subsidy and stake parameters.  See
+; docs/custom_networks.md for details.
+; netparams=~/.dcrd/privnet.conf
+
 ; Change how long to wait for TCP connection completion.  Valid time units are
 ; {s, m, h}.  Minimum 1 second".
 ; dialtimeout=30s
@@ -62,6 +68,12 @@ const FileContents = `[Application Options]
 ; to correlate connections.
 ; torisolation=1
 
+; Treat addresses in the IPv6 address block used by the cjdns encrypted mesh
+; network (fc00::/8) as reachable so they are exchanged with and connected to
+; like other peer addresses.  They are otherwise treated as unroutable private
+; addresses.  Only enable this when the node is connected to a cjdns network.
+; cjdns=1
+
 ; Use Universal Plug and Play (UPnP) to automatically open the listen port
 ; and obtain the external IP address from supported devices.  NOTE: This option
 ; will have no effect if external IP addresses are specified.
@@ -75,6 +87,11 @@ const FileContents = `[Application Options]
 ; externalip=1.2.3.4
 ; externalip=2002::1234
 
+; Specify STUN servers to query to discover the public IPv4 and IPv6 addresses
+; your node is reachable at when no external IP addresses are specified.  One
+; server per line.  NOTE: This option has no effect when a proxy is used.
+; stunserver=stun.example.com:3478
+
 ; ******************************************************************************
 ; Summary of 'addpeer' versus 'connect'.
 ;
@@ -119,6 +136,16 @@ const FileContents = `[Application Options]
 ; Maximum number of inbound and outbound peers.
 ; maxpeers=8
 
+; Maximum number of MiB to upload to peers over a rolling 24 hour window.  Once
+; the target is approached, blocks older than a week are no longer served to
+; non-whitelisted peers so the remainder of the target is available to relay
+; new blocks and transactions.  This is useful to protect metered connections.
+; Note that enough of the target is reserved to relay a maximum size block for
+; every block expected during the window, so the target should be larger than
+; that reserve for historical blocks to be served at all.  A value of 0 means
+; unlimited.
+; maxuploadtarget=0
+
 ; Disable banning of misbehaving peers.
 ; nobanning=1
 
@@ -131,12 +158,59 @@ const FileContents = `[Application Options]
 ; banduration=11h30m15s
 
 ; Add whitelisted IP networks and IPs. Connected peers whose IP matches a
-; whitelist will not have their ban score increased.
+; whitelist will not have their ban score increased and are never banned.
+; Inbound peers and RPC clients whose IP matches a whitelist are also exempt
+; from the maximum number of peers and RPC clients as well as the RPC request
+; rate limits.  This is useful for monitoring and wallet hosts.
 ; whitelist=127.0.0.1
 ; whitelist=::1
 ; whitelist=192.168.0.0/24
 ; whitelist=fd00::/16
 
+; Refuse connections from peers running software that is known to be broken or
+; abusive based on the user agent they advertise.  Patterns are matched against
+; the entire user agent and may contain * wildcards.  Peers with a user agent
+; that matches any denyuseragent pattern are refused.  When any allowuseragent
+; patterns are specified, peers must also match one of them.  Allow patterns may
+; be suffixed with @<n> to also require at least protocol version n.  Whitelisted
+; peers are exempt.
+; denyuseragent=*BrokenNode*
+; allowuseragent=/dcrwire:*/dcrd:*@8
+
+; Limit the rate of messages with a given command that are received from each
+; peer as <command>:<messages>/<interval>.  Bursts of up to the number of
+; messages are permitted.  Peers that exceed the limit are disconnected.  By
+; default, peers may send up to 10 addr messages per minute.  A limit of 0
+; messages removes the default limit for the command.
+; msgratelimit=addr:10/1m
+; msgratelimit=headers:100/1m
+
+; Limit the payload size of messages with a given command that are received from
+; peers as <command>:<bytes>.  This is in addition to the limits defined by the
+; wire protocol.  Peers that send larger messages are disconnected.
+; msgsizelimit=headers:100000
+
+; Group peer addresses by the autonomous system (AS) they are announced by
+; instead of by network prefix using the provided asmap file.  This makes it
+; harder for an adversary that controls many addresses spread across network
+; prefixes in a single AS to eclipse the node.  The file uses the same compact
+; binary format as the asmap files produced for other cryptocurrency nodes.
+; Known addresses are redistributed when the asmap changes.
+; asmap=~/.dcrd/ip_asn.map
+
+; Never store peer addresses that match the IP networks and onion address
+; patterns in the provided files, such as external threat intelligence
+; blocklists.  Each file contains one IP network (eg. 192.168.1.0/24), IP
+; address, or onion address pattern (eg. abc*.onion) per line.  Empty lines and
+; anything following a # are ignored.  May be specified multiple times.
+; addrblocklist=~/.dcrd/blocklist.txt
+
+; Format used to save known peer addresses.  Valid options are json (default)
+; and binary.  The binary format is saved to peers.dat instead of peers.json and
+; is much faster to load and save when tens of thousands of addresses are known.
+; Addresses saved in the other format are loaded when the format is changed.
+; peersfileformat=json
+
 ; Disable DNS seeding for peers.  By default, when dcrd starts, it will use
 ; DNS to query for available peers to connect with.
 ; nodnsseed=1
@@ -169,6 +243,22 @@ const FileContents = `[Application Options]
 ;   listen=0.0.0.0:8336
 ; All ipv6 interfaces on non-standard port 8336:
 ;   listen=[::]:8336
+;
+; Settings that only apply to connections accepted via a listen address may
+; follow it as comma-separated options:
+;   net=ipv4|ipv6|onion     Only accept connections from the given network
+;                           types.  May be specified multiple times.  Onion
+;                           accepts loopback connections from a local Tor
+;                           hidden service.
+;   whitelist=<ip|network>  Whitelist the IP or network on the listener.  May
+;                           be specified multiple times.
+;   maxinbound=<n>          Max number of inbound peers via the listener.
+;   externalip=<address>    Advertise the address for the listener instead of
+;                           the bound address.  May be specified multiple times.
+; Clearnet ipv4 interface limited to 100 inbound peers with a trusted network:
+;   listen=203.0.113.1:9108,net=ipv4,maxinbound=100,whitelist=203.0.113.0/24
+; Local interface for a Tor hidden service advertised via its onion address:
+;   listen=127.0.0.1:9108,net=onion,externalip=yourhiddenservice.onion
 
 ; Disable listening for incoming connections.  This will override all listeners.
 ; nolisten=1
@@ -178,8 +268,8 @@ const FileContents = `[Application Options]
 ; RPC server options - The following options control the built-in RPC server
 ; which is used to control and query information from a running dcrd process.
 ;
-; NOTE: The RPC server is disabled by default if no rpcuser or rpcpass is
-; specified.
+; NOTE: The RPC server is disabled by default if no rpcuser or rpcpass, no
+; rpcauth, and no rpcclientcafile is specified.
 ; ------------------------------------------------------------------------------
 
 ; Secure the RPC API by specifying the username and password.  You must specify
@@ -187,6 +277,31 @@ const FileContents = `[Application Options]
 ; rpcuser=whatever_username_you_want
 ; rpcpass=
 
+; Specify additional RPC credentials that are bound to a permission scope in
+; the form user:pass:scope.  The scope must be one of the following:
+;   admin    - Access to all methods (same as rpcuser/rpcpass)
+;   readonly - Access to the limited set of methods (same as
+;              rpclimituser/rpclimitpass)
+;   mining   - Access to the methods needed to obtain and submit work along
+;              with basic chain queries
+; Each username must be unique.  This option may be specified multiple times.
+; rpcauth=monitor:some_password:readonly
+; rpcauth=pool:another_password:mining
+
+; Authenticate RPC clients by the TLS client certificate they present as an
+; alternative to a username and password.  Client certificates must be signed
+; by one of the certificate authorities in the specified PEM-encoded file.
+; rpcclientcafile=~/.dcrd/rpcclientca.cert
+
+; Grant a permission scope to the client certificates with the given subject
+; common name in the form commonname:scope where the scope is one of the scopes
+; accepted by rpcauth.  All verified client certificates are granted the admin
+; scope when this option is not specified.  Otherwise, client certificates with
+; a common name that is not specified are rejected.  This option may be
+; specified multiple times.
+; rpcclientcert=monitor:readonly
+; rpcclientcert=pool:mining
+
 ; Specify the interfaces for the RPC server listen on.  One listen address per
 ; line.  NOTE: The default port is modified by some options such as 'testnet',
 ; so it is recommended to not specify a port and allow a proper default to be
@@ -217,12 +332,46 @@ const FileContents = `[Application Options]
 ; All ipv6 interfaces on non-standard port 8337:
 ;   rpclisten=[::]:8337
 
+; Specify the interfaces for the gRPC server to listen on, one listen address
+; per line.  The gRPC server is disabled unless at least one interface is
+; specified.  It uses the same credentials and TLS certificate as the RPC server
+; and requires the RPC server to be enabled.  The default port is 9119 on
+; mainnet and 19119 on testnet.
+; Only ipv4 localhost on the default port:
+;   grpclisten=127.0.0.1
+
+; Specify the interfaces to serve Prometheus metrics on over HTTP at /metrics,
+; one listen address per line.  The metrics server is disabled unless at least
+; one interface is specified.  It does not require authentication, so it should
+; only be reachable by the monitoring system.  The default port is 9120 on
+; mainnet and 19120 on testnet.
+; Only ipv4 localhost on the default port:
+;   metricslisten=127.0.0.1
+
 ; Specify the maximum number of concurrent RPC clients for standard connections.
 ; rpcmaxclients=10
 
 ; Specify the maximum number of concurrent RPC websocket clients.
 ; rpcmaxwebsockets=25
 
+; Limit the rate of RPC requests allowed from each client IP address.  Clients
+; may make up to rpcrateburst requests in a burst after which they are limited
+; to an average of rpcratelimit requests per second.  Each HTTP POST request or
+; websocket message counts as a single request.  The rate is not limited by
+; default.
+; rpcratelimit=10
+; rpcrateburst=20
+
+; Specify the maximum number of concurrent RPC requests allowed from each client
+; IP address across all of its connections.  The number of concurrent requests
+; is not limited by default.
+; rpcmaxclientreqs=4
+
+; Enable the read-only REST interface which is served on the RPC listeners under
+; the /rest/ path.  Requests must authenticate with the same credentials as RPC
+; connections.
+; rest=1
+
 ; Use the following setting to disable the RPC server even if the rpcuser and
 ; rpcpass are specified above.  This allows one to quickly disable the RPC
 ; server without having to remove credentials from the config file.
@@ -232,9 +381,25 @@ const FileContents = `[Application Options]
 ; Note that after changing this the rpccert/rpckey files need to be deleted so
 ; that they are recreated with the new curve.
 ;
-; Supported curves: P-521, P-256.
+; Supported curves: P-521, P-256, Ed25519.
 ; tlscurve=P-521
 
+; Specify the duration generated TLS certificates for the rpc endpoint are valid
+; for.
+; rpccertvalidity=87600h
+
+; Regenerate the TLS certificate and key for the rpc endpoint on startup when
+; the certificate has expired or will expire within a week.  Note that clients
+; which pin the certificate will need the new certificate.
+; rpccertregen=1
+
+; Only include the names and addresses specified with altdnsnames in generated
+; TLS certificates for the rpc endpoint instead of also including the host name,
+; local interface addresses, and localhost.  This is useful when the rpc
+; endpoint is accessed via a load balancer or from outside of a container.
+; altdnsnames=dcrd.example.com
+; rpccertnolocalhosts=1
+
 
 ; ------------------------------------------------------------------------------
 ; Mempool Settings - The following options
@@ -247,13 +412,30 @@ const FileContents = `[Application Options]
 ; minute.
 ; limitfreerelay=15
 
+; Track free transactions for the rate limit above over an exponentially
+; decaying window of approximately 10 minutes.
+; freerelaywindow=10m
+
+; Exempt free transactions relayed by whitelisted peers from rate limiting.
+; whitelistfreerelay=0
+
+; Exempt free transactions relayed by specific whitelisted peers from rate
+; limiting.  Peers must also match a whitelist entry for this to apply.  You
+; may specify this option multiple times.
+; freerelaywhitelist=192.168.0.10
+; freerelaywhitelist=fd00::/16
+
 ; Require high priority for relaying free or low-fee transactions.
 ; norelaypriority=0
 
 ; Limit orphan transaction pool to 100 transactions.
 ; maxorphantx=100
 
-; Do not accept transactions from remote peers.
+; Disable transaction relay, which is announced to peers during the handshake,
+; while still receiving blocks and votes.  This drastically reduces bandwidth
+; for nodes that do not participate in transaction propagation.  Votes are only
+; received from peers that support relaying them to nodes with transaction relay
+; disabled.
 ; blocksonly=1
 
 ; Accept and relay non-standard transactions to the network regardless of the
@@ -281,9 +463,17 @@ const FileContents = `[Application Options]
 ; txindex=1
 
 ; Build and maintain a full address-based transaction index which makes the
-; searchrawtransactions RPC available.
+; searchrawtransactions and getaddressutxos RPCs available.
 ; addrindex=1
 
+; Build and maintain an index of spent transaction outputs which makes the
+; getspentinfo RPC available.
+; spendindex=1
+
+; Build and maintain an index of block timestamps which makes the getblockbytime
+; and getblocksbytime RPCs available.
+; timeindex=1
+
 
 ; ------------------------------------------------------------------------------
 ; Signature Verification Cache
@@ -359,7 +549,9 @@ const FileContents = `[Application Options]
 ; The profile server will be disabled if this option is not specified.  Profile
 ; information can be accessed at http://ipaddr:<profileport>/debug/pprof once
 ; running.  Note that the IP address will default to 127.0.0.1 if an IP address
-; is not specified, so that the profiler is not accessible on the network.
+; is not specified, so that the profiler is not accessible on the network.  The
+; profile server may also be started and stopped at runtime via the
+; profileserver RPC.
 ; Listen on selected port on localhost only:
 ;   profile=6061
 ; Listen on selected port on all network interfaces:
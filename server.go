@@ -9,13 +9,16 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"net"
 	"path"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,6 +30,7 @@ import (
 	"github.com/decred/dcrd/blockchain/standalone"
 	"github.com/decred/dcrd/blockchain/v3"
 	"github.com/decred/dcrd/blockchain/v3/indexers"
+	"github.com/decred/dcrd/certgen"
 	"github.com/decred/dcrd/chaincfg/chainhash"
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/connmgr/v3"
@@ -36,7 +40,6 @@ import (
 	"github.com/decred/dcrd/gcs/v2"
 	"github.com/decred/dcrd/gcs/v2/blockcf"
 	"github.com/decred/dcrd/internal/version"
-	"github.com/decred/dcrd/lru"
 	"github.com/decred/dcrd/mempool/v4"
 	"github.com/decred/dcrd/mining/v3"
 	"github.com/decred/dcrd/peer/v2"
@@ -67,11 +70,7 @@ const (
 	connectionRetryInterval = time.Second * 5
 
 	// maxProtocolVersion is the max protocol version the server supports.
-	maxProtocolVersion = wire.CFilterV2Version
-
-	// maxKnownAddrsPerPeer is the maximum number of items to keep in the
-	// per-peer known address cache.
-	maxKnownAddrsPerPeer = 10000
+	maxProtocolVersion = wire.BlocksOnlyVoteRelayVersion
 
 	// maxCachedNaSubmissions is the maximum number of network address
 	// submissions cached.
@@ -265,14 +264,12 @@ func (sc *naSubmissionCache) bestSubmission(net addrmgr.NetworkAddress) *naSubmi
 	return best
 }
 
-// peerState maintains state of inbound, persistent, outbound peers as well
-// as banned peers and outbound groups.
+// peerState maintains state of inbound, persistent, and outbound peers as well
+// as the submission cache of network addresses.
 type peerState struct {
 	inboundPeers    map[int32]*serverPeer
 	outboundPeers   map[int32]*serverPeer
 	persistentPeers map[int32]*serverPeer
-	banned          map[string]time.Time
-	outboundGroups  map[string]int
 	subCache        *naSubmissionCache
 }
 
@@ -297,6 +294,18 @@ func (ps *peerState) ConnectionsWithIP(ip net.IP) int {
 	return total
 }
 
+// InboundWithListener returns the number of inbound peers that were accepted
+// by the listener with the given settings.
+func (ps *peerState) InboundWithListener(lc *listenerConfig) int {
+	var total int
+	for _, p := range ps.inboundPeers {
+		if p.listenerCfg == lc {
+			total++
+		}
+	}
+	return total
+}
+
 // Count returns the count of all known peers.
 func (ps *peerState) Count() int {
 	return len(ps.inboundPeers) + len(ps.outboundPeers) +
@@ -433,14 +442,18 @@ type server struct {
 	// Putting the uint64s first makes them 64-bit aligned for 32-bit systems.
 	bytesReceived uint64 // Total bytes received from all peers since start.
 	bytesSent     uint64 // Total bytes sent by all peers since start.
+	banDuration   int64  // Duration peers are banned for in nanoseconds.
 	shutdown      int32
+	maxPeers      int32 // Max number of inbound and outbound peers.
 
 	chainParams          *chaincfg.Params
 	addrManager          *addrmgr.AddrManager
+	uploadBudget         *uploadBudget
 	connManager          *connmgr.ConnManager
 	sigCache             *txscript.SigCache
 	subsidyCache         *standalone.SubsidyCache
 	rpcServer            *rpcServer
+	metricsServer        *metricsServer
 	blockManager         *blockManager
 	bg                   *BgBlkTmplGenerator
 	chain                *blockchain.BlockChain
@@ -467,6 +480,9 @@ type server struct {
 	// do not need to be protected for concurrent access.
 	txIndex         *indexers.TxIndex
 	addrIndex       *indexers.AddrIndex
+	spendIndex      *indexers.SpendIndex
+	timeIndex       *indexers.TimeIndex
+	indexManager    *indexers.Manager
 	existsAddrIndex *indexers.ExistsAddrIndex
 	cfIndex         *indexers.CFIndex
 }
@@ -483,7 +499,7 @@ type serverPeer struct {
 	relayMtx       sync.Mutex
 	disableRelayTx bool
 	isWhitelisted  bool
-	knownAddresses lru.Cache
+	listenerCfg    *listenerConfig
 	banScore       connmgr.DynamicBanScore
 	quit           chan struct{}
 
@@ -500,6 +516,10 @@ type serverPeer struct {
 	// peerNa is network address of the peer connected to.
 	peerNa    *wire.NetAddress
 	peerNaMtx sync.Mutex
+
+	// lastBlockTime is the unix time the peer last relayed a block that was
+	// accepted by the chain.  It must be accessed atomically.
+	lastBlockTime int64
 }
 
 // newServerPeer returns a new serverPeer instance. The peer needs to be set by
@@ -508,7 +528,6 @@ func newServerPeer(s *server, isPersistent bool) *serverPeer {
 	return &serverPeer{
 		server:         s,
 		persistent:     isPersistent,
-		knownAddresses: lru.NewCache(maxKnownAddrsPerPeer),
 		quit:           make(chan struct{}),
 		txProcessed:    make(chan struct{}, 1),
 		blockProcessed: make(chan struct{}, 1),
@@ -525,14 +544,7 @@ func (sp *serverPeer) newestBlock() (*chainhash.Hash, int64, error) {
 // addKnownAddresses adds the given addresses to the set of known addresses to
 // the peer to prevent sending duplicate addresses.
 func (sp *serverPeer) addKnownAddresses(addresses []*wire.NetAddress) {
-	for _, na := range addresses {
-		sp.knownAddresses.Add(addrmgr.NetAddressKey(na))
-	}
-}
-
-// addressKnown true if the given address is already known to the peer.
-func (sp *serverPeer) addressKnown(na *wire.NetAddress) bool {
-	return sp.knownAddresses.Contains(addrmgr.NetAddressKey(na))
+	sp.server.addrManager.AddKnownAddresses(sp.ID(), addresses)
 }
 
 // setDisableRelayTx toggles relaying of transactions for the given peer.
@@ -554,16 +566,26 @@ func (sp *serverPeer) relayTxDisabled() bool {
 	return isDisabled
 }
 
+// wantsTxInv returns whether or not transaction inventory should be relayed to
+// the peer.  Peers that disabled transaction relay in their version message
+// are only sent votes, and only when they are full nodes that negotiated a
+// protocol version which supports it.  Clients such as SPV wallets do not
+// advertise the full node service.
+func (sp *serverPeer) wantsTxInv(isVote bool) bool {
+	if !sp.relayTxDisabled() {
+		return true
+	}
+
+	isFullNode := sp.Services()&wire.SFNodeNetwork != 0
+	return isVote && isFullNode &&
+		sp.ProtocolVersion() >= wire.BlocksOnlyVoteRelayVersion
+}
+
 // pushAddrMsg sends an addr message to the connected peer using the provided
 // addresses.
 func (sp *serverPeer) pushAddrMsg(addresses []*wire.NetAddress) {
 	// Filter addresses already known to the peer.
-	addrs := make([]*wire.NetAddress, 0, len(addresses))
-	for _, addr := range addresses {
-		if !sp.addressKnown(addr) {
-			addrs = append(addrs, addr)
-		}
-	}
+	addrs := sp.server.addrManager.UnknownAddresses(sp.ID(), addresses)
 	known, err := sp.PushAddrMsg(addrs)
 	if err != nil {
 		peerLog.Errorf("Can't push address message to %s: %v", sp.Peer, err)
@@ -637,6 +659,10 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 	addrManager := sp.server.addrManager
 	if !cfg.SimNet && !cfg.RegNet && !isInbound {
 		addrManager.SetServices(remoteAddr, msg.Services)
+		addrManager.SetUserAgent(remoteAddr, msg.UserAgent,
+			uint32(msg.ProtocolVersion))
+		addrManager.SetLastBlock(remoteAddr, int64(msg.LastBlock),
+			sp.server.chain.BestSnapshot().Height)
 	}
 
 	// Ignore peers that have a protocol version that is too old.  The peer
@@ -645,6 +671,18 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 		return nil
 	}
 
+	// Reject peers running software that is refused by the configured user
+	// agent policy unless they are whitelisted.
+	if cfg.userAgentFilter != nil && !sp.isWhitelisted {
+		err := cfg.userAgentFilter.Check(msg.UserAgent,
+			uint32(msg.ProtocolVersion))
+		if err != nil {
+			srvrLog.Debugf("Rejecting peer %s: %v", sp.Peer, err)
+			return wire.NewMsgReject(msg.Command(), wire.RejectNonstandard,
+				"user agent refused")
+		}
+	}
+
 	// Reject outbound peers that are not full nodes.
 	wantServices := wire.SFNodeNetwork
 	if !isInbound && !hasServices(msg.Services, wantServices) {
@@ -669,7 +707,7 @@ func (sp *serverPeer) OnVersion(p *peer.Peer, msg *wire.MsgVersion) *wire.MsgRej
 		if !cfg.DisableListen && sp.server.blockManager.IsCurrent() {
 			// Get address that best matches.
 			lna := addrManager.GetBestLocalAddress(remoteAddr)
-			if addrmgr.IsRoutable(lna) {
+			if addrManager.IsRoutable(lna) {
 				// Filter addresses the peer already knows about.
 				addresses := []*wire.NetAddress{lna}
 				sp.pushAddrMsg(addresses)
@@ -857,9 +895,14 @@ func (sp *serverPeer) OnMiningState(p *peer.Peer, msg *wire.MsgMiningState) {
 // serialize all transactions through a single thread transactions don't rely on
 // the previous one in a linear fashion like blocks.
 func (sp *serverPeer) OnTx(p *peer.Peer, msg *wire.MsgTx) {
-	if cfg.BlocksOnly {
-		peerLog.Tracef("Ignoring tx %v from %v - blocksonly enabled",
-			msg.TxHash(), p)
+	// Votes are still accepted in blocks-only mode since they are required
+	// to build block templates and are relayed to full nodes that have
+	// transaction relay disabled.  Any other transactions are a violation of
+	// the relay preference announced during the handshake.
+	if cfg.BlocksOnly && !stake.IsSSGen(msg) {
+		peerLog.Infof("Peer %v sent non-vote transaction %v with "+
+			"blocksonly enabled -- disconnecting", p, msg.TxHash())
+		p.Disconnect()
 		return
 	}
 
@@ -874,16 +917,31 @@ func (sp *serverPeer) OnTx(p *peer.Peer, msg *wire.MsgTx) {
 	// intentionally block further receives until the transaction is fully
 	// processed and known good or bad.  This helps prevent a malicious peer
 	// from queuing up a bunch of bad transactions before disconnecting (or
-	// being disconnected) and wasting memory.
-	sp.server.blockManager.QueueTx(tx, sp.Peer, sp.txProcessed)
+	// being disconnected) and wasting memory.  Free and low-fee transactions
+	// from whitelisted peers are exempt from rate limiting when configured.
+	rateLimit := !sp.isFreeRelayExempt()
+	sp.server.blockManager.QueueTx(tx, sp.Peer, rateLimit, sp.txProcessed)
 	<-sp.txProcessed
 }
 
+// isFreeRelayExempt returns whether free and low-fee transactions relayed by
+// the peer are exempt from rate limiting.  Only whitelisted peers may be
+// exempt, either because all whitelisted peers are exempt or because the peer
+// matches one of the free relay whitelisted networks and IPs.
+func (sp *serverPeer) isFreeRelayExempt() bool {
+	if !sp.isWhitelisted {
+		return false
+	}
+	return cfg.WhitelistFreeRelay || isFreeRelayWhitelistedAddr(sp.Addr())
+}
+
 // OnBlock is invoked when a peer receives a block wire message.  It blocks
 // until the network block has been fully processed.
 func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	// Convert the raw MsgBlock to a dcrutil.Block which provides some
-	// convenience methods and things such as hash caching.
+	// convenience methods and things such as hash caching.  Note that the
+	// raw bytes are nil since blocks are decoded as they are streamed from
+	// the peer, in which case they are serialized on demand.
 	block := dcrutil.NewBlockFromBlockAndBytes(msg, buf)
 
 	// Add the block to the known inventory for the peer.
@@ -901,6 +959,13 @@ func (sp *serverPeer) OnBlock(p *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	// fully processed.
 	sp.server.blockManager.QueueBlock(block, sp.Peer, sp.blockProcessed)
 	<-sp.blockProcessed
+
+	// Keep track of when the peer last relayed an accepted block so the
+	// peers that were most recently useful for block relay can be saved as
+	// anchors on shutdown.
+	if sp.server.chain.HaveBlock(block.Hash()) {
+		atomic.StoreInt64(&sp.lastBlockTime, time.Now().Unix())
+	}
 }
 
 // OnInv is invoked when a peer receives an inv wire message and is used to
@@ -923,9 +988,15 @@ func (sp *serverPeer) OnInv(p *peer.Peer, msg *wire.MsgInv) {
 		return
 	}
 
+	// Peers that negotiated a protocol version which supports relaying votes
+	// to nodes with transaction relay disabled only announce votes, so their
+	// transaction inventory is requested.  Transactions announced by any
+	// other peers are a violation of the relay preference announced during
+	// the handshake.
+	relaysVotesOnly := sp.ProtocolVersion() >= wire.BlocksOnlyVoteRelayVersion
 	newInv := wire.NewMsgInvSizeHint(uint(len(msg.InvList)))
 	for _, invVect := range msg.InvList {
-		if invVect.Type == wire.InvTypeTx {
+		if invVect.Type == wire.InvTypeTx && !relaysVotesOnly {
 			peerLog.Infof("Peer %v is announcing transactions -- "+
 				"disconnecting", p)
 			p.Disconnect()
@@ -1514,6 +1585,14 @@ func (s *server) TransactionConfirmed(tx *dcrutil.Tx) {
 	}
 }
 
+// ReportPoorService marks the address of the provided peer as having served
+// poorly so it is less likely to be selected for future outbound connections.
+func (s *server) ReportPoorService(p *peer.Peer) {
+	if na := p.NA(); na != nil {
+		s.addrManager.ServedPoorly(na)
+	}
+}
+
 // pushTxMsg sends a tx message for the provided transaction hash to the
 // connected peer.  An error is returned if the transaction hash is not known.
 func (s *server) pushTxMsg(sp *serverPeer, hash *chainhash.Hash, doneChan chan<- struct{}, waitChan <-chan struct{}) error {
@@ -1558,6 +1637,23 @@ func (s *server) pushBlockMsg(sp *serverPeer, hash *chainhash.Hash, doneChan cha
 		return err
 	}
 
+	// Stop serving historical blocks to non-whitelisted peers once the
+	// upload target is approached so the remainder of it is available to
+	// relay new blocks and transactions.  The peer is disconnected since it
+	// is likely syncing and would otherwise keep requesting more of them.
+	now := time.Now()
+	if !sp.isWhitelisted && isHistoricalBlock(block.MsgBlock().Header.Timestamp, now) &&
+		!s.uploadBudget.serveHistorical(now) {
+
+		peerLog.Infof("Upload target reached -- not serving historical "+
+			"block %v to peer %s and disconnecting", hash, sp)
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		sp.Disconnect()
+		return errUploadTargetReached
+	}
+
 	// Once we have fetched data wait for any previous operation to finish.
 	if waitChan != nil {
 		<-waitChan
@@ -1631,24 +1727,13 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 		return false
 	}
 
-	// Disconnect banned peers.
-	host, _, err := net.SplitHostPort(sp.Addr())
-	if err != nil {
-		srvrLog.Debugf("can't split hostport %v", err)
+	// Disconnect banned peers.  Whitelisted peers are never considered
+	// banned.
+	if !sp.isWhitelisted && s.addrManager.IsBanned(sp.NA()) {
+		srvrLog.Debugf("Peer %s is banned - disconnecting", sp)
 		sp.Disconnect()
 		return false
 	}
-	if banEnd, ok := state.banned[host]; ok {
-		if time.Now().Before(banEnd) {
-			srvrLog.Debugf("Peer %s is banned for another %v - disconnecting",
-				host, time.Until(banEnd))
-			sp.Disconnect()
-			return false
-		}
-
-		srvrLog.Infof("Peer %s is no longer banned", host)
-		delete(state.banned, host)
-	}
 
 	// Limit max number of connections from a single IP.  However, allow
 	// whitelisted inbound peers and localhost connections regardless.
@@ -1664,15 +1749,28 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 
 	// Limit max number of total peers.  However, allow whitelisted inbound
 	// peers regardless.
-	if state.Count()+1 > cfg.MaxPeers && !isInboundWhitelisted {
+	maxPeers := s.MaxPeers()
+	if state.Count()+1 > maxPeers && !isInboundWhitelisted {
 		srvrLog.Infof("Max peers reached [%d] - disconnecting peer %s",
-			cfg.MaxPeers, sp)
+			maxPeers, sp)
 		sp.Disconnect()
 		// TODO: how to handle permanent peers here?
 		// they should be rescheduled.
 		return false
 	}
 
+	// Limit max number of inbound peers accepted by the listener the peer
+	// connected to when it is configured with a limit.  However, allow
+	// whitelisted peers regardless.
+	if lc := sp.listenerCfg; sp.Inbound() && lc != nil && lc.maxInbound > 0 &&
+		!isInboundWhitelisted && state.InboundWithListener(lc)+1 > lc.maxInbound {
+
+		srvrLog.Infof("Max inbound peers for listener reached [%d] - "+
+			"disconnecting peer %s", lc.maxInbound, sp)
+		sp.Disconnect()
+		return false
+	}
+
 	sp.peerNaMtx.Lock()
 	na := sp.peerNa
 	sp.peerNaMtx.Unlock()
@@ -1695,7 +1793,7 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 			}
 		}
 	} else {
-		state.outboundGroups[addrmgr.GroupKey(sp.NA())]++
+		s.addrManager.AddOutbound(sp.NA())
 		if sp.persistent {
 			state.persistentPeers[sp.ID()] = sp
 		} else {
@@ -1708,7 +1806,8 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 		//
 		// The conditions to disable automatic network address
 		// discovery are:
-		//	- If there is a proxy set (--proxy, --onion).
+		//	- If there is an onion proxy set (--onion).  The dial policy
+		//	of the address manager covers the clearnet proxy (--proxy).
 		//	- If automatic network address discovery is explicitly
 		//		disabled (--nodiscoverip).
 		//	- If there is an external ip explicitly set (--externalip).
@@ -1716,8 +1815,9 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 		//	disabled because of --connect, etc).
 		//	- If Universal Plug and Play is enabled (--upnp).
 		//	- If the active network is simnet or regnet.
-		if (cfg.Proxy != "" || cfg.OnionProxy != "") ||
-			cfg.NoDiscoverIP || len(cfg.ExternalIPs) > 0 ||
+		if cfg.OnionProxy != "" || cfg.NoDiscoverIP ||
+			len(cfg.ExternalIPs) > 0 ||
+			hasListenerExternalIPs(cfg.listenerConfigs) ||
 			(cfg.DisableListen || len(cfg.Listeners) == 0) || cfg.Upnp ||
 			s.chainParams.Name == simNetParams.Name ||
 			s.chainParams.Name == regNetParams.Name {
@@ -1730,10 +1830,10 @@ func (s *server) handleAddPeerMsg(state *peerState, sp *serverPeer) bool {
 				net = addrmgr.IPv6Address
 			}
 
-			valid, reach := s.addrManager.ValidatePeerNa(na, sp.NA())
-			if !valid {
+			if !s.addrManager.IsExternalAddrCandidate(na, sp.NA()) {
 				return true
 			}
+			_, reach := s.addrManager.ValidatePeerNa(na, sp.NA())
 
 			id := na.IP.String()
 			if state.subCache.exists(id) {
@@ -1780,7 +1880,14 @@ func (s *server) handleDonePeerMsg(state *peerState, sp *serverPeer) {
 	}
 	if _, ok := list[sp.ID()]; ok {
 		if !sp.Inbound() && sp.VersionKnown() {
-			state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+			s.addrManager.RemoveOutbound(sp.NA())
+
+			// Record the latency and uptime of the connection so
+			// reliable peers are favoured for future connections.
+			latency := time.Duration(sp.LastPingMicros()) *
+				time.Microsecond
+			uptime := time.Since(sp.TimeConnected())
+			s.addrManager.RecordConnectionStats(sp.NA(), latency, uptime)
 		}
 		if !sp.Inbound() && sp.connReq != nil {
 			s.connManager.Disconnect(sp.connReq.ID())
@@ -1807,20 +1914,31 @@ func (s *server) handleDonePeerMsg(state *peerState, sp *serverPeer) {
 // handleBanPeerMsg deals with banning peers.  It is invoked from the
 // peerHandler goroutine.
 func (s *server) handleBanPeerMsg(state *peerState, sp *serverPeer) {
+	// Whitelisted peers are never banned.
+	if sp.isWhitelisted {
+		srvrLog.Debugf("Not banning whitelisted peer %s", sp)
+		return
+	}
+
 	host, _, err := net.SplitHostPort(sp.Addr())
 	if err != nil {
 		srvrLog.Debugf("can't split ban peer %s %v", sp.Addr(), err)
 		return
 	}
 	direction := directionString(sp.Inbound())
-	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction,
-		cfg.BanDuration)
-	state.banned[host] = time.Now().Add(cfg.BanDuration)
+	banDuration := s.BanDuration()
+	srvrLog.Infof("Banned peer %s (%s) for %v", host, direction, banDuration)
+	s.addrManager.BanAddress(sp.NA(), banDuration)
 }
 
 // handleRelayInvMsg deals with relaying inventory to peers that are not already
 // known to have it.  It is invoked from the peerHandler goroutine.
 func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
+	var isVote bool
+	if tx, ok := msg.data.(*dcrutil.Tx); ok {
+		isVote = stake.IsSSGen(tx.MsgTx())
+	}
+
 	state.forAllPeers(func(sp *serverPeer) {
 		if !sp.Connected() {
 			return
@@ -1846,12 +1964,11 @@ func (s *server) handleRelayInvMsg(state *peerState, msg relayMsg) {
 			return
 		}
 
-		if msg.invVect.Type == wire.InvTypeTx {
-			// Don't relay the transaction to the peer when it has
-			// transaction relaying disabled.
-			if sp.relayTxDisabled() {
-				return
-			}
+		// Don't relay the transaction to the peer when it has
+		// transaction relaying disabled unless it is a vote the peer
+		// negotiated to receive.
+		if msg.invVect.Type == wire.InvTypeTx && !sp.wantsTxInv(isVote) {
+			return
 		}
 
 		// Either queue the inventory to be relayed immediately or with
@@ -1893,11 +2010,6 @@ type getPeersMsg struct {
 	reply chan []*serverPeer
 }
 
-type getOutboundGroup struct {
-	key   string
-	reply chan int
-}
-
 type getAddedNodesMsg struct {
 	reply chan []*serverPeer
 }
@@ -1949,7 +2061,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 	case connectNodeMsg:
 		// XXX duplicate oneshots?
 		// Limit max number of total peers.
-		if state.Count() >= cfg.MaxPeers {
+		if state.Count() >= s.MaxPeers() {
 			msg.reply <- errors.New("max peers reached")
 			return
 		}
@@ -1981,7 +2093,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		found := disconnectPeer(state.persistentPeers, msg.cmp, func(sp *serverPeer) {
 			// Keep group counts ok since we remove from
 			// the list now.
-			state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+			s.addrManager.RemoveOutbound(sp.NA())
 
 			peerLog.Debugf("Removing persistent peer %s:%d (reqid %d)",
 				sp.NA().IP, sp.NA().Port, sp.connReq.ID())
@@ -2005,13 +2117,6 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 			return
 		}
 		msg.reply <- s.connManager.CancelPending(netAddr)
-	case getOutboundGroup:
-		count, ok := state.outboundGroups[msg.key]
-		if ok {
-			msg.reply <- count
-		} else {
-			msg.reply <- 0
-		}
 	// Request a list of the persistent (added) peers.
 	case getAddedNodesMsg:
 		// Respond with a slice of the relevant peers.
@@ -2033,7 +2138,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 		found = disconnectPeer(state.outboundPeers, msg.cmp, func(sp *serverPeer) {
 			// Keep group counts ok since we remove from
 			// the list now.
-			state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+			s.addrManager.RemoveOutbound(sp.NA())
 		})
 		if found {
 			// If there are multiple outbound connections to the same
@@ -2041,7 +2146,7 @@ func (s *server) handleQuery(state *peerState, querymsg interface{}) {
 			// peers are found.
 			for found {
 				found = disconnectPeer(state.outboundPeers, msg.cmp, func(sp *serverPeer) {
-					state.outboundGroups[addrmgr.GroupKey(sp.NA())]--
+					s.addrManager.RemoveOutbound(sp.NA())
 				})
 			}
 			msg.reply <- nil
@@ -2116,6 +2221,8 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 		DisableRelayTx:    cfg.BlocksOnly,
 		ProtocolVersion:   maxProtocolVersion,
 		IdleTimeout:       cfg.PeerIdleTimeout,
+		MessageLimits:     cfg.peerMessageLimits,
+		StreamBlocks:      true,
 	}
 }
 
@@ -2124,8 +2231,21 @@ func newPeerConfig(sp *serverPeer) *peer.Config {
 // instance, associates it with the connection, and starts a goroutine to wait
 // for disconnection.
 func (s *server) inboundPeerConnected(conn net.Conn) {
+	// Reject connections from network types the listener that accepted the
+	// connection is not configured to accept.
+	lc := connListenerConfig(conn)
+	if !lc.acceptsAddr(conn.RemoteAddr()) {
+		srvrLog.Debugf("Rejecting inbound connection from %s on %s - "+
+			"network type not accepted by listener", conn.RemoteAddr(),
+			conn.LocalAddr())
+		conn.Close()
+		return
+	}
+
 	sp := newServerPeer(s, false)
-	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr())
+	sp.isWhitelisted = isWhitelisted(conn.RemoteAddr()) ||
+		lc.isWhitelisted(conn.RemoteAddr())
+	sp.listenerCfg = lc
 	sp.Peer = peer.NewInboundPeer(newPeerConfig(sp))
 	sp.AssociateConnection(conn)
 	go s.peerDoneHandler(sp)
@@ -2169,9 +2289,68 @@ func (s *server) peerDoneHandler(sp *serverPeer) {
 				pickNoun(numEvicted, "orphan", "orphans"), sp, sp.ID())
 		}
 	}
+
+	// Forget the addresses known to the peer now that they will no longer
+	// be relayed to it.
+	s.addrManager.ForgetKnownAddresses(sp.ID())
 	close(sp.quit)
 }
 
+// anchorsEnabled returns whether or not anchor connections are saved on
+// shutdown and reconnected to on startup.  Like automatic connections, they are
+// disabled in connect-only mode and on the simulation and regression networks.
+func (s *server) anchorsEnabled() bool {
+	return !cfg.SimNet && !cfg.RegNet && len(cfg.ConnectPeers) == 0
+}
+
+// saveAnchors sets the addresses of the non-persistent outbound peers that most
+// recently relayed blocks accepted by the chain as the anchors the address
+// manager saves when it is stopped.  Reconnecting to known good peers on the
+// next start makes it harder for an adversary to take advantage of a restart
+// to eclipse the node.
+//
+// This function MUST be called from the peer handler goroutine.
+func (s *server) saveAnchors(state *peerState) {
+	type anchor struct {
+		na            *wire.NetAddress
+		lastBlockTime int64
+	}
+	var anchors []anchor
+	for _, sp := range state.outboundPeers {
+		lastBlockTime := atomic.LoadInt64(&sp.lastBlockTime)
+		if lastBlockTime == 0 {
+			continue
+		}
+		anchors = append(anchors, anchor{sp.NA(), lastBlockTime})
+	}
+	sort.Slice(anchors, func(i, j int) bool {
+		return anchors[i].lastBlockTime > anchors[j].lastBlockTime
+	})
+
+	addrs := make([]*wire.NetAddress, 0, len(anchors))
+	for _, anchor := range anchors {
+		addrs = append(addrs, anchor.na)
+	}
+	s.addrManager.SetAnchors(addrs)
+}
+
+// connectToAnchors initiates non-permanent outbound connections to the anchors
+// saved by the address manager on the last shutdown.
+func (s *server) connectToAnchors() {
+	for _, na := range s.addrManager.GetAnchors() {
+		addr, err := addrStringToNetAddr(addrmgr.NetAddressKey(na))
+		if err != nil {
+			srvrLog.Warnf("Unable to connect to anchor %s: %v",
+				addrmgr.NetAddressKey(na), err)
+			continue
+		}
+
+		srvrLog.Infof("Connecting to anchor %s", addr)
+		go s.connManager.Connect(context.Background(),
+			&connmgr.ConnReq{Addr: addr})
+	}
+}
+
 // peerHandler is used to handle peer operations such as adding and removing
 // peers to and from the server, banning peers, and broadcasting messages to
 // peers.  It must be run in a goroutine.
@@ -2186,12 +2365,15 @@ func (s *server) peerHandler(ctx context.Context) {
 
 	srvrLog.Tracef("Starting peer handler")
 
+	// Reconnect to the anchors saved on the last shutdown.
+	if s.anchorsEnabled() {
+		s.connectToAnchors()
+	}
+
 	state := &peerState{
 		inboundPeers:    make(map[int32]*serverPeer),
 		persistentPeers: make(map[int32]*serverPeer),
 		outboundPeers:   make(map[int32]*serverPeer),
-		banned:          make(map[string]time.Time),
-		outboundGroups:  make(map[string]int),
 		subCache: &naSubmissionCache{
 			cache: make(map[string]*naSubmission, maxCachedNaSubmissions),
 			limit: maxCachedNaSubmissions,
@@ -2230,6 +2412,12 @@ out:
 			s.handleQuery(state, qmsg)
 
 		case <-ctx.Done():
+			// Save the outbound peers that most recently relayed
+			// blocks as anchors to reconnect to on the next start.
+			if s.anchorsEnabled() {
+				s.saveAnchors(state)
+			}
+
 			// Disconnect all peers on server shutdown.
 			state.forAllPeers(func(sp *serverPeer) {
 				srvrLog.Tracef("Shutdown peer %s", sp)
@@ -2296,14 +2484,6 @@ func (s *server) ConnectedCount() int32 {
 	return <-replyChan
 }
 
-// OutboundGroupCount returns the number of peers connected to the given
-// outbound group key.
-func (s *server) OutboundGroupCount(key string) int {
-	replyChan := make(chan int)
-	s.query <- getOutboundGroup{key: key, reply: replyChan}
-	return <-replyChan
-}
-
 // AddedNodeInfo returns an array of dcrjson.GetAddedNodeInfoResult structures
 // describing the persistent (added) nodes.
 func (s *server) AddedNodeInfo() []*serverPeer {
@@ -2405,9 +2585,11 @@ func (s *server) ConnectNode(addr string, permanent bool) error {
 }
 
 // AddBytesSent adds the passed number of bytes to the total bytes sent counter
-// for the server.  It is safe for concurrent access.
+// for the server and records them against the upload budget.  It is safe for
+// concurrent access.
 func (s *server) AddBytesSent(bytesSent uint64) {
 	atomic.AddUint64(&s.bytesSent, bytesSent)
+	s.uploadBudget.add(time.Now(), bytesSent)
 }
 
 // AddBytesReceived adds the passed number of bytes to the total bytes received
@@ -2423,6 +2605,32 @@ func (s *server) NetTotals() (uint64, uint64) {
 		atomic.LoadUint64(&s.bytesSent)
 }
 
+// MaxPeers returns the maximum number of inbound and outbound peers the server
+// currently allows.  It is safe for concurrent access.
+func (s *server) MaxPeers() int {
+	return int(atomic.LoadInt32(&s.maxPeers))
+}
+
+// SetMaxPeers sets the maximum number of inbound and outbound peers the server
+// allows.  Existing peers are not disconnected when the limit is lowered, but
+// new peers are rejected until the number of connected peers drops below it.
+// It is safe for concurrent access.
+func (s *server) SetMaxPeers(maxPeers int) {
+	atomic.StoreInt32(&s.maxPeers, int32(maxPeers))
+}
+
+// BanDuration returns the duration misbehaving peers are currently banned for.
+// It is safe for concurrent access.
+func (s *server) BanDuration() time.Duration {
+	return time.Duration(atomic.LoadInt64(&s.banDuration))
+}
+
+// SetBanDuration sets the duration misbehaving peers are banned for.  It only
+// applies to peers banned after the change.  It is safe for concurrent access.
+func (s *server) SetBanDuration(banDuration time.Duration) {
+	atomic.StoreInt64(&s.banDuration, int64(banDuration))
+}
+
 // UpdatePeerHeights updates the heights of all peers who have announced
 // the latest connected main chain block, or a recognized orphan. These height
 // updates allow us to dynamically refresh peer heights, ensuring sync peer
@@ -2575,7 +2783,7 @@ func (s *server) querySeeders(ctx context.Context) {
 				const httpsPort = 443
 				srcAddr = wire.NewNetAddressIPPort(srcIPs[0], httpsPort, 0)
 			}
-			s.addrManager.AddAddresses(addrs, srcAddr)
+			s.addrManager.AddSeedAddresses(srcAddr, addrs)
 		}(seeder)
 	}
 }
@@ -2622,6 +2830,14 @@ func (s *server) Run(ctx context.Context) {
 		}(s)
 	}
 
+	if s.metricsServer != nil {
+		s.wg.Add(1)
+		go func(s *server) {
+			s.metricsServer.Run(serverCtx)
+			s.wg.Done()
+		}(s)
+	}
+
 	// Start the background block template generator if the config provides
 	// a mining address.
 	if len(cfg.MiningAddrs) > 0 {
@@ -2706,6 +2922,10 @@ func parseListeners(addrs []string) ([]net.Addr, error) {
 }
 
 func (s *server) upnpUpdateThread(ctx context.Context) {
+	// leaseTime is the duration of the port mapping lease and, in turn, of
+	// the local address obtained via UPnP.
+	const leaseTime = 20 * time.Minute
+
 	// Go off immediately to prevent code duplication, thereafter we renew
 	// lease every 15 minutes.
 	timer := time.NewTimer(0 * time.Second)
@@ -2722,25 +2942,29 @@ out:
 			// listen port?
 			// XXX this assumes timeout is in seconds.
 			listenPort, err := s.nat.AddPortMapping("tcp", int(lport), int(lport),
-				"dcrd listen port", 20*60)
+				"dcrd listen port", int(leaseTime/time.Second))
 			if err != nil {
 				srvrLog.Warnf("can't add UPnP port mapping: %v", err)
 			}
-			if first && err == nil {
-				// TODO: look this up periodically to see if upnp domain changed
-				// and so did ip.
+			if err == nil {
+				// Look up the external address on every renewal in case it
+				// changed.  The local address expires along with the port
+				// mapping unless it is renewed, so it does not remain
+				// advertised after the mapping lapses.
 				externalip, err := s.nat.GetExternalAddress()
 				if err != nil {
 					srvrLog.Warnf("UPnP can't get external address: %v", err)
+					timer.Reset(time.Minute * 15)
 					continue out
 				}
 				na := wire.NewNetAddressIPPort(externalip, uint16(listenPort),
 					s.services)
-				err = s.addrManager.AddLocalAddress(na, addrmgr.UpnpPrio)
+				err = s.addrManager.AddLocalAddressWithExpiry(na,
+					addrmgr.UpnpPrio, leaseTime, nil)
 				if err != nil {
 					srvrLog.Warnf("Failed to add UPnP local address %s: %v",
 						na.IP.String(), err)
-				} else {
+				} else if first {
 					srvrLog.Warnf("Successfully bound via UPnP to %s",
 						addrmgr.NetAddressKey(na))
 					first = false
@@ -2784,50 +3008,112 @@ func standardScriptVerifyFlags(chain *blockchain.BlockChain) (txscript.ScriptFla
 	return scriptFlags, nil
 }
 
-// setupRPCListeners returns a slice of listeners that are configured for use
-// with the RPC server depending on the configuration settings for listen
-// addresses and TLS.
-func setupRPCListeners() ([]net.Listener, error) {
-	// Setup TLS if not disabled.
-	listenFunc := net.Listen
-	if !cfg.DisableRPC && !cfg.DisableTLS {
-		// Generate the TLS cert and key file if both don't already exist.
-		keyFileExists := fileExists(cfg.RPCKey)
-		certFileExists := fileExists(cfg.RPCCert)
-		if len(cfg.AltDNSNames) != 0 && (keyFileExists || certFileExists) {
-			rpcsLog.Warn("Additional DNS names specified when TLS " +
-				"certificates already exist will NOT be included:")
-			rpcsLog.Warnf("- In order to create TLS certs that include the "+
-				"additional DNS names, delete %q and %q and restart the server",
-				cfg.RPCKey, cfg.RPCCert)
-		}
-		if !keyFileExists && !certFileExists {
-			curve, err := tlsCurve(cfg.TLSCurve)
-			if err != nil {
-				return nil, err
-			}
-			err = genCertPair(cfg.RPCCert, cfg.RPCKey, cfg.AltDNSNames, curve)
-			if err != nil {
-				return nil, err
-			}
-		}
-		keypair, err := tls.LoadX509KeyPair(cfg.RPCCert, cfg.RPCKey)
+// rpcCertRenewWindow is the amount of time prior to the expiration of the RPC
+// certificate at which it is regenerated when regeneration is enabled.
+const rpcCertRenewWindow = time.Hour * 24 * 7
+
+// rpcCertNeedsRegen returns whether or not the existing RPC certificate has
+// expired or is about to expire and should therefore be regenerated.  Warnings
+// are logged instead when regeneration is not enabled.
+func rpcCertNeedsRegen() bool {
+	certBytes, err := ioutil.ReadFile(cfg.RPCCert)
+	if err != nil {
+		rpcsLog.Warnf("Unable to read RPC certificate %q: %v", cfg.RPCCert,
+			err)
+		return false
+	}
+	expiry, err := certgen.CertExpiry(certBytes)
+	if err != nil {
+		rpcsLog.Warnf("Unable to determine the expiry of RPC certificate "+
+			"%q: %v", cfg.RPCCert, err)
+		return false
+	}
+	if time.Until(expiry) > rpcCertRenewWindow {
+		return false
+	}
+	if cfg.RPCCertRegen {
+		rpcsLog.Infof("RPC certificate %q expires %v -- regenerating",
+			cfg.RPCCert, expiry)
+		return true
+	}
+
+	if time.Now().After(expiry) {
+		rpcsLog.Warnf("RPC certificate %q expired %v", cfg.RPCCert, expiry)
+	} else {
+		rpcsLog.Warnf("RPC certificate %q expires %v", cfg.RPCCert, expiry)
+	}
+	rpcsLog.Warnf("- In order to create a new TLS cert, delete %q and %q "+
+		"and restart the server or enable --rpccertregen", cfg.RPCKey,
+		cfg.RPCCert)
+	return false
+}
+
+// rpcTLSConfig returns the TLS configuration used by the RPC and gRPC servers.
+// The certificate and key files are generated when neither of them exists, or
+// when the certificate is about to expire and regeneration is enabled.
+func rpcTLSConfig() (*tls.Config, error) {
+	// Generate the TLS cert and key file if both don't already exist or the
+	// existing cert needs to be regenerated.
+	keyFileExists := fileExists(cfg.RPCKey)
+	certFileExists := fileExists(cfg.RPCCert)
+	generate := !keyFileExists && !certFileExists
+	if keyFileExists && certFileExists {
+		generate = rpcCertNeedsRegen()
+	}
+	if !generate && len(cfg.AltDNSNames) != 0 {
+		rpcsLog.Warn("Additional DNS names specified when TLS " +
+			"certificates already exist will NOT be included:")
+		rpcsLog.Warnf("- In order to create TLS certs that include the "+
+			"additional DNS names, delete %q and %q and restart the server",
+			cfg.RPCKey, cfg.RPCCert)
+	}
+	if generate {
+		err := genCertPair(cfg.RPCCert, cfg.RPCKey, cfg.TLSCurve,
+			&certgen.Options{
+				Organization:   "dcrd autogenerated cert",
+				ValidUntil:     time.Now().Add(cfg.RPCCertValidity),
+				Hosts:          cfg.AltDNSNames,
+				NoDefaultHosts: cfg.RPCCertNoLocalHosts,
+			})
 		if err != nil {
 			return nil, err
 		}
+	}
+	keypair, err := tls.LoadX509KeyPair(cfg.RPCCert, cfg.RPCKey)
+	if err != nil {
+		return nil, err
+	}
 
-		tlsConfig := tls.Config{
-			Certificates: []tls.Certificate{keypair},
-			MinVersion:   tls.VersionTLS12,
-		}
+	tlsConfig := tls.Config{
+		Certificates: []tls.Certificate{keypair},
+		MinVersion:   tls.VersionTLS12,
+	}
 
-		// Change the standard net.Listen function to the tls one.
-		listenFunc = func(net string, laddr string) (net.Listener, error) {
-			return tls.Listen(net, laddr, &tlsConfig)
+	// Request and verify client certificates when client certificate
+	// authentication is enabled.  Clients that do not present a certificate
+	// are still permitted to connect so they may authenticate with a
+	// username and password instead.
+	if cfg.RPCClientCAs != "" {
+		caPEM, err := ioutil.ReadFile(cfg.RPCClientCAs)
+		if err != nil {
+			return nil, err
 		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificates found in %s",
+				cfg.RPCClientCAs)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
 	}
+	return &tlsConfig, nil
+}
 
-	netAddrs, err := parseListeners(cfg.RPCListeners)
+// listenAddrs returns a listener for each of the provided addresses that can
+// be listened on using the provided listen function.  Addresses that can't be
+// listened on are logged and skipped.
+func listenAddrs(addrs []string, listenFunc func(string, string) (net.Listener, error)) ([]net.Listener, error) {
+	netAddrs, err := parseListeners(addrs)
 	if err != nil {
 		return nil, err
 	}
@@ -2845,16 +3131,84 @@ func setupRPCListeners() ([]net.Listener, error) {
 	return listeners, nil
 }
 
+// setupRPCListeners returns a slice of listeners that are configured for use
+// with the RPC server depending on the configuration settings for listen
+// addresses and TLS.
+func setupRPCListeners() ([]net.Listener, error) {
+	// Setup TLS if not disabled.
+	listenFunc := net.Listen
+	if !cfg.DisableRPC && !cfg.DisableTLS {
+		tlsConfig, err := rpcTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		// Change the standard net.Listen function to the tls one.
+		listenFunc = func(net string, laddr string) (net.Listener, error) {
+			return tls.Listen(net, laddr, tlsConfig)
+		}
+	}
+
+	return listenAddrs(cfg.RPCListeners, listenFunc)
+}
+
+// setupGRPCListeners returns a slice of listeners that are configured for use
+// with the gRPC server along with the TLS configuration the server must use,
+// which is nil when TLS is disabled.  Unlike the RPC listeners, TLS is
+// negotiated by the gRPC server itself, so the listeners are not wrapped.
+func setupGRPCListeners() ([]net.Listener, *tls.Config, error) {
+	var tlsConfig *tls.Config
+	if !cfg.DisableTLS {
+		var err error
+		tlsConfig, err = rpcTLSConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	listeners, err := listenAddrs(cfg.GRPCListeners, net.Listen)
+	if err != nil {
+		return nil, nil, err
+	}
+	return listeners, tlsConfig, nil
+}
+
+// setupMetricsListeners returns a slice of listeners that are configured for
+// use with the metrics server.  Metrics are served over plain HTTP.
+func setupMetricsListeners() ([]net.Listener, error) {
+	return listenAddrs(cfg.MetricsListeners, net.Listen)
+}
+
 // newServer returns a new dcrd server configured to listen on addr for the
 // decred network type specified by chainParams.  Use start to begin accepting
 // connections from peers.
-func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainParams *chaincfg.Params, dataDir string) (*server, error) {
+func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainParams *chaincfg.Params, dataDir string, prof *profiler) (*server, error) {
 	services := defaultServices
 	if cfg.NoCFilters {
 		services &^= wire.SFNodeCF
 	}
 
-	amgr := addrmgr.New(cfg.DataDir, dcrdLookup)
+	// Addresses in the IPv6 address block used by cjdns are otherwise
+	// treated as unroutable unique local addresses.
+	amgr := addrmgr.NewWithConfig(&addrmgr.Config{
+		DataDir:        cfg.DataDir,
+		LookupFunc:     dcrdLookup,
+		CJDNSReachable: cfg.CJDNS,
+	})
+	for _, blocklist := range cfg.addrBlocklists {
+		amgr.AddBlocklist(blocklist)
+	}
+	if cfg.asmap != nil {
+		amgr.SetASMap(cfg.asmap)
+	}
+	amgr.SetPeersFileFormat(cfg.peersFileFormat)
+
+	// Clearnet local addresses must not be advertised or learned when all
+	// clearnet connections are made through a proxy since they would reveal
+	// the network location of the node.
+	amgr.SetDialPolicy(addrmgr.NetworkReachabilityPolicy{
+		ClearnetProxied: cfg.Proxy != "",
+	})
 
 	var listeners []net.Listener
 	var nat NAT
@@ -2869,9 +3223,31 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		}
 	}
 
+	// Limit the serving of historical blocks once the upload target is
+	// approached.
+	uploadTarget := cfg.MaxUploadTarget * 1024 * 1024
+	uploadReserve := uploadBudgetReserve(chainParams)
+	uploadBudget := newUploadBudget(uploadTarget, uploadReserve)
+	if uploadTarget != 0 && uploadTarget <= uploadReserve {
+		srvrLog.Warnf("The upload target of %d MiB does not exceed the %d "+
+			"MiB reserved to relay new blocks -- historical blocks will "+
+			"not be served to non-whitelisted peers", cfg.MaxUploadTarget,
+			uploadReserve/(1024*1024))
+	}
+
+	// Allow the adjusted time to be advanced on demand on the test networks
+	// so test suites are able to script time-dependent scenarios.
+	var timeSource blockchain.MedianTimeSource = blockchain.NewMedianTime()
+	if chainParams.Net == wire.SimNet || chainParams.Net == wire.RegNet {
+		timeSource = newMockTimeSource(timeSource)
+	}
+
 	s := server{
+		banDuration:          int64(cfg.BanDuration),
+		maxPeers:             int32(cfg.MaxPeers),
 		chainParams:          chainParams,
 		addrManager:          amgr,
+		uploadBudget:         uploadBudget,
 		newPeers:             make(chan *serverPeer, cfg.MaxPeers),
 		donePeers:            make(chan *serverPeer, cfg.MaxPeers),
 		banPeers:             make(chan *serverPeer, cfg.MaxPeers),
@@ -2882,7 +3258,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		peerHeightsUpdate:    make(chan updatePeerHeightsMsg),
 		nat:                  nat,
 		db:                   db,
-		timeSource:           blockchain.NewMedianTime(),
+		timeSource:           timeSource,
 		services:             services,
 		sigCache:             txscript.NewSigCache(cfg.SigCacheMaxSize),
 		subsidyCache:         standalone.NewSubsidyCache(chainParams),
@@ -2914,6 +3290,16 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		s.addrIndex = indexers.NewAddrIndex(db, chainParams)
 		indexes = append(indexes, s.addrIndex)
 	}
+	if cfg.SpendIndex {
+		indxLog.Info("Spend index is enabled")
+		s.spendIndex = indexers.NewSpendIndex(db)
+		indexes = append(indexes, s.spendIndex)
+	}
+	if cfg.TimeIndex {
+		indxLog.Info("Block timestamp index is enabled")
+		s.timeIndex = indexers.NewTimeIndex(db)
+		indexes = append(indexes, s.timeIndex)
+	}
 	if !cfg.NoExistsAddrIndex {
 		indxLog.Info("Exists address index is enabled")
 		s.existsAddrIndex = indexers.NewExistsAddrIndex(db, chainParams)
@@ -2948,7 +3334,8 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 	// Create an index manager if any of the optional indexes are enabled.
 	var indexManager indexers.IndexManager
 	if len(indexes) > 0 {
-		indexManager = indexers.NewManager(db, indexes, chainParams)
+		s.indexManager = indexers.NewManager(db, indexes, chainParams)
+		indexManager = s.indexManager
 	}
 
 	// Only configure checkpoints when enabled.
@@ -2983,6 +3370,7 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			DisableRelayPriority: cfg.NoRelayPriority,
 			AcceptNonStd:         cfg.AcceptNonStd,
 			FreeTxRelayLimit:     cfg.FreeTxRelayLimit,
+			FreeTxRelayWindow:    cfg.FreeTxRelayWindow,
 			MaxOrphanTxs:         cfg.MaxOrphanTxs,
 			MaxOrphanTxSize:      defaultMaxOrphanTxSize,
 			MaxSigOpsPerTx:       blockchain.MaxSigOpsPerBlock / 5,
@@ -3094,19 +3482,20 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 	if !cfg.SimNet && !cfg.RegNet && len(cfg.ConnectPeers) == 0 {
 		newAddressFunc = func() (net.Addr, error) {
 			for tries := 0; tries < 100; tries++ {
-				addr := s.addrManager.GetAddress()
+				addr := s.addrManager.SuggestDiverseAddress()
 				if addr == nil {
 					break
 				}
 
 				// Address will not be invalid, local or unroutable
 				// because addrmanager rejects those on addition.
-				// Just check that we don't already have an address
-				// in the same group so that we are not connecting
-				// to the same network segment at the expense of
-				// others.
-				key := addrmgr.GroupKey(addr.NetAddress())
-				if s.OutboundGroupCount(key) != 0 {
+				// Addresses in groups without outbound peers are
+				// preferred, but just check that we don't already
+				// have an address in the same group so that we are
+				// not connecting to the same network segment at the
+				// expense of others.
+				key := s.addrManager.GroupKey(addr.NetAddress())
+				if s.addrManager.OutboundGroupCount(key) != 0 {
 					continue
 				}
 
@@ -3116,6 +3505,15 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 					continue
 				}
 
+				// Skip addresses that are known to run software
+				// refused by the configured user agent policy.
+				if cfg.userAgentFilter != nil && addr.UserAgent() != "" &&
+					cfg.userAgentFilter.Check(addr.UserAgent(),
+						addr.ProtocolVersion()) != nil {
+
+					continue
+				}
+
 				// allow nondefault ports after 50 failed tries.
 				if fmt.Sprintf("%d", addr.NetAddress().Port) !=
 					s.chainParams.DefaultPort && tries < 50 {
@@ -3180,26 +3578,46 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 			return nil, errors.New("no usable rpc listen addresses")
 		}
 
+		// Setup listeners for the configured gRPC listen addresses, if
+		// any.
+		var grpcListeners []net.Listener
+		var grpcTLSConfig *tls.Config
+		if len(cfg.GRPCListeners) > 0 {
+			grpcListeners, grpcTLSConfig, err = setupGRPCListeners()
+			if err != nil {
+				return nil, err
+			}
+			if len(grpcListeners) == 0 {
+				return nil, errors.New("no usable grpc listen addresses")
+			}
+		}
+
 		s.rpcServer, err = newRPCServer(&rpcserverConfig{
-			Listeners:    rpcListeners,
-			ConnMgr:      &rpcConnManager{&s},
-			SyncMgr:      &rpcSyncMgr{&s, s.blockManager},
-			PeerNotifier: &s,
-			FeeEstimator: s.feeEstimator,
-			TimeSource:   s.timeSource,
-			Services:     s.services,
-			AddrManager:  s.addrManager,
-			SubsidyCache: s.subsidyCache,
-			Chain:        s.chain,
-			ChainParams:  chainParams,
-			DB:           db,
-			TxMemPool:    s.txMemPool,
+			Listeners:     rpcListeners,
+			GRPCListeners: grpcListeners,
+			GRPCTLSConfig: grpcTLSConfig,
+			ConnMgr:       &rpcConnManager{&s},
+			SyncMgr:       &rpcSyncMgr{&s, s.blockManager},
+			PeerNotifier:  &s,
+			FeeEstimator:  s.feeEstimator,
+			TimeSource:    s.timeSource,
+			Services:      s.services,
+			AddrManager:   s.addrManager,
+			SubsidyCache:  s.subsidyCache,
+			Chain:         s.chain,
+			ChainParams:   chainParams,
+			DB:            db,
+			TxMemPool:     s.txMemPool,
 			BgBlkTmplGenerator: func() *BgBlkTmplGenerator {
 				return s.bg
 			},
-			CPUMiner:  s.cpuMiner,
-			TxIndex:   s.txIndex,
-			AddrIndex: s.addrIndex,
+			CPUMiner:     s.cpuMiner,
+			TxIndex:      s.txIndex,
+			AddrIndex:    s.addrIndex,
+			SpendIndex:   s.spendIndex,
+			TimeIndex:    s.timeIndex,
+			IndexManager: s.indexManager,
+			Profiler:     prof,
 		})
 		if err != nil {
 			return nil, err
@@ -3212,6 +3630,17 @@ func newServer(ctx context.Context, listenAddrs []string, db database.DB, chainP
 		}()
 	}
 
+	if len(cfg.MetricsListeners) > 0 {
+		metricsListeners, err := setupMetricsListeners()
+		if err != nil {
+			return nil, err
+		}
+		if len(metricsListeners) == 0 {
+			return nil, errors.New("no usable metrics listen addresses")
+		}
+		s.metricsServer = newMetricsServer(&s, metricsListeners)
+	}
+
 	return &s, nil
 }
 
@@ -3233,6 +3662,9 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 			srvrLog.Warnf("Can't listen on %s: %v", addr, err)
 			continue
 		}
+		if lc := cfg.listenerConfigs[addr.String()]; lc != nil {
+			listener = &configuredListener{Listener: listener, cfg: lc}
+		}
 		listeners = append(listeners, listener)
 	}
 
@@ -3245,32 +3677,7 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 			return nil, nil, err
 		}
 
-		for _, sip := range cfg.ExternalIPs {
-			eport := uint16(defaultPort)
-			host, portstr, err := net.SplitHostPort(sip)
-			if err != nil {
-				// no port, use default.
-				host = sip
-			} else {
-				port, err := strconv.ParseUint(portstr, 10, 16)
-				if err != nil {
-					srvrLog.Warnf("Can not parse port from %s for "+
-						"externalip: %v", sip, err)
-					continue
-				}
-				eport = uint16(port)
-			}
-			na, err := amgr.HostToNetAddress(host, eport, services)
-			if err != nil {
-				srvrLog.Warnf("Not adding %s as externalip: %v", sip, err)
-				continue
-			}
-
-			err = amgr.AddLocalAddress(na, addrmgr.ManualPrio)
-			if err != nil {
-				amgrLog.Warnf("Skipping specified external IP: %v", err)
-			}
-		}
+		addExternalIPs(amgr, cfg.ExternalIPs, uint16(defaultPort), services)
 	} else {
 		if cfg.Upnp {
 			var err error
@@ -3281,8 +3688,31 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 			// nil nat here is fine, just means no upnp on network.
 		}
 
+		// Discover the public addresses to advertise via the configured
+		// STUN servers unless a proxy is used since the queries would
+		// reveal the public address of the node.
+		if len(cfg.StunServers) > 0 && cfg.Proxy == "" &&
+			cfg.OnionProxy == "" {
+
+			defaultPort, err := strconv.ParseUint(params.DefaultPort, 10, 16)
+			if err != nil {
+				srvrLog.Errorf("Can not parse default port %s for active "+
+					"chain: %v", params.DefaultPort, err)
+				return nil, nil, err
+			}
+			go amgr.DiscoverStunAddresses(ctx, cfg.StunServers,
+				uint16(defaultPort), services)
+		}
+
 		// Add bound addresses to address manager to be advertised to peers.
+		// Listeners with their own external addresses advertise those
+		// instead.
 		for _, listener := range listeners {
+			if l, ok := listener.(*configuredListener); ok &&
+				len(l.cfg.externalIPs) > 0 {
+				continue
+			}
+
 			addr := listener.Addr().String()
 			err := addLocalAddress(amgr, addr, services)
 			if err != nil {
@@ -3291,9 +3721,56 @@ func initListeners(ctx context.Context, params *chaincfg.Params, amgr *addrmgr.A
 		}
 	}
 
+	// Add the external addresses of listeners that have them to the address
+	// manager to be advertised to peers.  They default to the port the
+	// listener is bound to.
+	for _, listener := range listeners {
+		l, ok := listener.(*configuredListener)
+		if !ok || len(l.cfg.externalIPs) == 0 {
+			continue
+		}
+		var port uint16
+		if tcpAddr, ok := l.Addr().(*net.TCPAddr); ok {
+			port = uint16(tcpAddr.Port)
+		}
+		addExternalIPs(amgr, l.cfg.externalIPs, port, services)
+	}
+
 	return listeners, nat, nil
 }
 
+// addExternalIPs adds the provided external addresses, which use the provided
+// default port when they do not specify one, to the address manager so that
+// they may be relayed to peers.
+func addExternalIPs(amgr *addrmgr.AddrManager, externalIPs []string, defaultPort uint16, services wire.ServiceFlag) {
+	for _, sip := range externalIPs {
+		eport := defaultPort
+		host, portstr, err := net.SplitHostPort(sip)
+		if err != nil {
+			// no port, use default.
+			host = sip
+		} else {
+			port, err := strconv.ParseUint(portstr, 10, 16)
+			if err != nil {
+				srvrLog.Warnf("Can not parse port from %s for "+
+					"externalip: %v", sip, err)
+				continue
+			}
+			eport = uint16(port)
+		}
+		na, err := amgr.HostToNetAddress(host, eport, services)
+		if err != nil {
+			srvrLog.Warnf("Not adding %s as externalip: %v", sip, err)
+			continue
+		}
+
+		err = amgr.AddLocalAddress(na, addrmgr.ManualPrio)
+		if err != nil {
+			amgrLog.Warnf("Skipping specified external IP: %v", err)
+		}
+	}
+}
+
 // addrStringToNetAddr takes an address in the form of 'host:port' and returns
 // a net.Addr which maps to the original address with any host names resolved
 // to IP addresses.
@@ -3374,11 +3851,30 @@ func addLocalAddress(addrMgr *addrmgr.AddrManager, addr string, services wire.Se
 // isWhitelisted returns whether the IP address is included in the whitelisted
 // networks and IPs.
 func isWhitelisted(addr net.Addr) bool {
-	if len(cfg.whitelists) == 0 {
+	return isWhitelistedAddr(addr.String())
+}
+
+// isWhitelistedAddr returns whether the IP address of the provided host:port
+// address string is included in the whitelisted networks and IPs.
+func isWhitelistedAddr(addr string) bool {
+	return addrInNetworks(addr, cfg.whitelists)
+}
+
+// isFreeRelayWhitelistedAddr returns whether the IP address of the provided
+// host:port address string is included in the networks and IPs whose free
+// transactions are exempt from rate limiting.
+func isFreeRelayWhitelistedAddr(addr string) bool {
+	return addrInNetworks(addr, cfg.freeRelayWhitelists)
+}
+
+// addrInNetworks returns whether the IP address of the provided host:port
+// address string is included in any of the provided networks.
+func addrInNetworks(addr string, networks []*net.IPNet) bool {
+	if len(networks) == 0 {
 		return false
 	}
 
-	host, _, err := net.SplitHostPort(addr.String())
+	host, _, err := net.SplitHostPort(addr)
 	if err != nil {
 		srvrLog.Warnf("Unable to SplitHostPort on '%s': %v", addr, err)
 		return false
@@ -3389,7 +3885,7 @@ func isWhitelisted(addr net.Addr) bool {
 		return false
 	}
 
-	for _, ipnet := range cfg.whitelists {
+	for _, ipnet := range networks {
 		if ipnet.Contains(ip) {
 			return true
 		}
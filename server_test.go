@@ -0,0 +1,514 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/addrmgr"
+	"github.com/decred/dcrd/blockchain/stake/v3"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/peer/v2"
+	"github.com/decred/dcrd/txscript/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// newTestServerPeer returns a server peer that is not connected to a remote
+// peer along with the block manager message channel it queues messages to.
+// The peer reports the provided protocol version and services.
+func newTestServerPeer(pver uint32, services wire.ServiceFlag) (*serverPeer, chan interface{}) {
+	msgChan := make(chan interface{}, 1)
+	s := &server{blockManager: &blockManager{msgChan: msgChan}}
+	sp := newServerPeer(s, false)
+	sp.Peer = peer.NewInboundPeer(&peer.Config{
+		ProtocolVersion: pver,
+		Services:        services,
+	})
+	return sp, msgChan
+}
+
+// isDisconnected returns whether or not the provided peer has been
+// disconnected.
+func isDisconnected(p *peer.Peer) bool {
+	done := make(chan struct{})
+	go func() {
+		p.WaitForDisconnect()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(50 * time.Millisecond):
+		return false
+	}
+}
+
+// newTestVote returns a minimal vote transaction that is recognized as such by
+// the stake package.
+func newTestVote(t *testing.T) *wire.MsgTx {
+	t.Helper()
+
+	vote := wire.NewMsgTx()
+	stakebase := wire.NewOutPoint(&chainhash.Hash{}, ^uint32(0),
+		wire.TxTreeRegular)
+	vote.AddTxIn(wire.NewTxIn(stakebase, 0, nil))
+	ticket := wire.NewOutPoint(&chainhash.Hash{0x01}, 0, wire.TxTreeStake)
+	vote.AddTxIn(wire.NewTxIn(ticket, 0, nil))
+
+	blockRefScript, err := txscript.GenerateSSGenBlockRef(chainhash.Hash{}, 0)
+	if err != nil {
+		t.Fatalf("unable to create block reference script: %v", err)
+	}
+	vote.AddTxOut(wire.NewTxOut(0, blockRefScript))
+	voteScript, err := txscript.GenerateProvablyPruneableOut([]byte{0x01, 0x00})
+	if err != nil {
+		t.Fatalf("unable to create vote script: %v", err)
+	}
+	vote.AddTxOut(wire.NewTxOut(0, voteScript))
+	rewardScript, err := txscript.PayToSSGenPKHDirect(make([]byte, 20))
+	if err != nil {
+		t.Fatalf("unable to create vote reward script: %v", err)
+	}
+	vote.AddTxOut(wire.NewTxOut(0, rewardScript))
+
+	if !stake.IsSSGen(vote) {
+		t.Fatal("test vote is not recognized as a vote")
+	}
+	return vote
+}
+
+// TestBlocksOnlyOnInv ensures inventory announcements are filtered according
+// to the negotiated protocol version of the announcing peer in blocks-only
+// mode.
+func TestBlocksOnlyOnInv(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	blockInv := wire.NewInvVect(wire.InvTypeBlock, &chainhash.Hash{0x01})
+	txInv := wire.NewInvVect(wire.InvTypeTx, &chainhash.Hash{0x02})
+
+	tests := []struct {
+		name       string          // test description
+		blocksOnly bool            // whether blocks-only mode is enabled
+		pver       uint32          // announcing peer protocol version
+		invs       []*wire.InvVect // announced inventory
+		wantQueued []*wire.InvVect // inventory queued to the block manager
+		wantDisc   bool            // whether the peer is disconnected
+	}{{
+		name:       "tx announcement without blocks-only",
+		pver:       wire.CFilterV2Version,
+		invs:       []*wire.InvVect{blockInv, txInv},
+		wantQueued: []*wire.InvVect{blockInv, txInv},
+	}, {
+		name:       "block announcement from old peer",
+		blocksOnly: true,
+		pver:       wire.CFilterV2Version,
+		invs:       []*wire.InvVect{blockInv},
+		wantQueued: []*wire.InvVect{blockInv},
+	}, {
+		name:       "tx announcement from old peer",
+		blocksOnly: true,
+		pver:       wire.CFilterV2Version,
+		invs:       []*wire.InvVect{blockInv, txInv},
+		wantDisc:   true,
+	}, {
+		name:       "vote announcement from vote relay peer",
+		blocksOnly: true,
+		pver:       wire.BlocksOnlyVoteRelayVersion,
+		invs:       []*wire.InvVect{blockInv, txInv},
+		wantQueued: []*wire.InvVect{blockInv, txInv},
+	}}
+
+	for _, test := range tests {
+		cfg = &config{BlocksOnly: test.blocksOnly}
+		sp, msgChan := newTestServerPeer(test.pver, wire.SFNodeNetwork)
+		msg := wire.NewMsgInv()
+		for _, iv := range test.invs {
+			msg.AddInvVect(iv)
+		}
+		sp.OnInv(sp.Peer, msg)
+
+		if gotDisc := isDisconnected(sp.Peer); gotDisc != test.wantDisc {
+			t.Errorf("%q: unexpected disconnect state -- got %v, want %v",
+				test.name, gotDisc, test.wantDisc)
+			continue
+		}
+		var gotQueued []*wire.InvVect
+		select {
+		case m := <-msgChan:
+			gotQueued = m.(*invMsg).inv.InvList
+		default:
+		}
+		if len(gotQueued) != len(test.wantQueued) {
+			t.Errorf("%q: unexpected queued inventory -- got %v, want %v",
+				test.name, gotQueued, test.wantQueued)
+			continue
+		}
+		for i := range gotQueued {
+			if *gotQueued[i] != *test.wantQueued[i] {
+				t.Errorf("%q: unexpected queued inventory -- got %v, "+
+					"want %v", test.name, gotQueued, test.wantQueued)
+				break
+			}
+		}
+	}
+}
+
+// TestBlocksOnlyOnTx ensures only votes are accepted from peers in blocks-only
+// mode and that peers sending any other transactions are disconnected.
+func TestBlocksOnlyOnTx(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	vote := newTestVote(t)
+	regularTx := wire.NewMsgTx()
+	regularTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0,
+		wire.TxTreeRegular), 0, nil))
+	regularTx.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_TRUE}))
+
+	tests := []struct {
+		name       string      // test description
+		blocksOnly bool        // whether blocks-only mode is enabled
+		tx         *wire.MsgTx // received transaction
+		wantQueued bool        // whether the tx is queued to the block manager
+	}{{
+		name:       "regular tx without blocks-only",
+		tx:         regularTx,
+		wantQueued: true,
+	}, {
+		name:       "vote with blocks-only",
+		blocksOnly: true,
+		tx:         vote,
+		wantQueued: true,
+	}, {
+		name:       "regular tx with blocks-only",
+		blocksOnly: true,
+		tx:         regularTx,
+		wantQueued: false,
+	}}
+
+	for _, test := range tests {
+		cfg = &config{BlocksOnly: test.blocksOnly}
+		sp, msgChan := newTestServerPeer(wire.BlocksOnlyVoteRelayVersion,
+			wire.SFNodeNetwork)
+
+		// Reply to any queued transaction as the block manager would so
+		// the handler returns.
+		gotQueued := make(chan bool, 1)
+		go func() {
+			select {
+			case m := <-msgChan:
+				m.(*txMsg).reply <- struct{}{}
+				gotQueued <- true
+			case <-time.After(time.Second):
+				gotQueued <- false
+			}
+		}()
+		sp.OnTx(sp.Peer, test.tx)
+
+		if got := <-gotQueued; got != test.wantQueued {
+			t.Errorf("%q: unexpected queued state -- got %v, want %v",
+				test.name, got, test.wantQueued)
+		}
+		gotDisc := isDisconnected(sp.Peer)
+		if wantDisc := !test.wantQueued; gotDisc != wantDisc {
+			t.Errorf("%q: unexpected disconnect state -- got %v, want %v",
+				test.name, gotDisc, wantDisc)
+		}
+	}
+}
+
+// TestWantsTxInv ensures transaction inventory is only relayed to peers that
+// disabled transaction relay when it is a vote and the peer is a full node that
+// negotiated a protocol version which supports it.
+func TestWantsTxInv(t *testing.T) {
+	tests := []struct {
+		name         string           // test description
+		relayDisable bool             // whether the peer disabled tx relay
+		pver         uint32           // peer protocol version
+		services     wire.ServiceFlag // peer services
+		isVote       bool             // whether the tx is a vote
+		want         bool             // expected result
+	}{{
+		name:     "tx to relaying peer",
+		pver:     wire.CFilterV2Version,
+		services: wire.SFNodeNetwork,
+		want:     true,
+	}, {
+		name:     "vote to relaying peer",
+		pver:     wire.CFilterV2Version,
+		services: wire.SFNodeNetwork,
+		isVote:   true,
+		want:     true,
+	}, {
+		name:         "tx to blocks-only peer",
+		relayDisable: true,
+		pver:         wire.BlocksOnlyVoteRelayVersion,
+		services:     wire.SFNodeNetwork,
+		want:         false,
+	}, {
+		name:         "vote to blocks-only peer",
+		relayDisable: true,
+		pver:         wire.BlocksOnlyVoteRelayVersion,
+		services:     wire.SFNodeNetwork,
+		isVote:       true,
+		want:         true,
+	}, {
+		name:         "vote to old blocks-only peer",
+		relayDisable: true,
+		pver:         wire.CFilterV2Version,
+		services:     wire.SFNodeNetwork,
+		isVote:       true,
+		want:         false,
+	}, {
+		name:         "vote to client with tx relay disabled",
+		relayDisable: true,
+		pver:         wire.BlocksOnlyVoteRelayVersion,
+		services:     wire.SFNodeCF,
+		isVote:       true,
+		want:         false,
+	}}
+
+	for _, test := range tests {
+		sp, _ := newTestServerPeer(test.pver, test.services)
+		sp.disableRelayTx = test.relayDisable
+		if got := sp.wantsTxInv(test.isVote); got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// testWhitelists are the whitelist entries used by the whitelist tests.
+var testWhitelists = []string{"192.168.1.0/24", "10.0.0.5", "::1",
+	"2001:db8::/32"}
+
+// whitelistAddrTests are addresses along with whether or not they match the
+// test whitelist entries.
+var whitelistAddrTests = []struct {
+	name        string // test description
+	addr        string // host:port of the remote address
+	whitelisted bool   // whether the address matches a whitelist entry
+}{{
+	name:        "IPv4 in CIDR whitelist",
+	addr:        "192.168.1.20:9108",
+	whitelisted: true,
+}, {
+	name: "IPv4 outside CIDR whitelist",
+	addr: "192.168.2.20:9108",
+}, {
+	name:        "IPv4 matching single IP whitelist",
+	addr:        "10.0.0.5:9108",
+	whitelisted: true,
+}, {
+	name: "IPv4 not matching single IP whitelist",
+	addr: "10.0.0.6:9108",
+}, {
+	name:        "IPv4-mapped IPv6 in IPv4 CIDR whitelist",
+	addr:        "[::ffff:192.168.1.20]:9108",
+	whitelisted: true,
+}, {
+	name:        "IPv6 matching single IP whitelist",
+	addr:        "[::1]:9108",
+	whitelisted: true,
+}, {
+	name:        "IPv6 in CIDR whitelist",
+	addr:        "[2001:db8:1::1]:9108",
+	whitelisted: true,
+}, {
+	name: "IPv6 outside CIDR whitelist",
+	addr: "[2001:db9::1]:9108",
+}}
+
+// setTestWhitelists sets the global config to one with the test whitelist
+// entries.
+func setTestWhitelists(t *testing.T) {
+	t.Helper()
+
+	cfg = &config{NoDiscoverIP: true, RPCMaxClients: 1}
+	for _, addr := range testWhitelists {
+		ipnet, err := parseWhitelist(addr)
+		if err != nil {
+			t.Fatalf("unable to parse whitelist %q: %v", addr, err)
+		}
+		cfg.whitelists = append(cfg.whitelists, ipnet)
+	}
+}
+
+// TestIsWhitelistedAddr ensures addresses are only considered whitelisted when
+// they match a whitelisted IP or network.
+func TestIsWhitelistedAddr(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	// No addresses are whitelisted without any whitelist entries.
+	cfg = &config{}
+	for _, test := range whitelistAddrTests {
+		if isWhitelistedAddr(test.addr) {
+			t.Errorf("%q: whitelisted without whitelist entries", test.name)
+		}
+	}
+
+	setTestWhitelists(t)
+	for _, test := range whitelistAddrTests {
+		got := isWhitelistedAddr(test.addr)
+		if got != test.whitelisted {
+			t.Errorf("%q: unexpected result -- got %v, want %v", test.name,
+				got, test.whitelisted)
+		}
+	}
+
+	// Addresses that can't be parsed are never whitelisted.
+	for _, addr := range []string{"192.168.1.20", "example.com:9108"} {
+		if isWhitelistedAddr(addr) {
+			t.Errorf("%q: invalid address is whitelisted", addr)
+		}
+	}
+}
+
+// TestWhitelistBans ensures whitelisted peers are never banned and are added
+// even when their host was banned while other peers are banned and rejected.
+func TestWhitelistBans(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+	setTestWhitelists(t)
+
+	for _, test := range whitelistAddrTests {
+		s := &server{
+			addrManager: addrmgr.NewWithConfig(&addrmgr.Config{
+				MemoryOnly: true,
+			}),
+			banDuration: int64(time.Hour),
+			maxPeers:    8,
+		}
+		state := &peerState{
+			inboundPeers:    make(map[int32]*serverPeer),
+			persistentPeers: make(map[int32]*serverPeer),
+			outboundPeers:   make(map[int32]*serverPeer),
+		}
+		newPeer := func() *serverPeer {
+			sp := newServerPeer(s, false)
+			p, err := peer.NewOutboundPeer(&peer.Config{}, test.addr)
+			if err != nil {
+				t.Fatalf("%q: unable to create peer: %v", test.name, err)
+			}
+			sp.Peer = p
+			sp.isWhitelisted = isWhitelistedAddr(test.addr)
+			return sp
+		}
+
+		// Ensure the peer is only banned when it is not whitelisted.
+		sp := newPeer()
+		s.handleBanPeerMsg(state, sp)
+		gotBanned := s.addrManager.IsBanned(sp.NA())
+		if wantBanned := !test.whitelisted; gotBanned != wantBanned {
+			t.Errorf("%q: unexpected ban state -- got %v, want %v",
+				test.name, gotBanned, wantBanned)
+			continue
+		}
+
+		// Ensure a new peer from a banned host is only added when it is
+		// whitelisted.
+		s.addrManager.BanAddress(sp.NA(), time.Hour)
+		sp = newPeer()
+		gotAdded := s.handleAddPeerMsg(state, sp)
+		if gotAdded != test.whitelisted {
+			t.Errorf("%q: unexpected add peer result -- got %v, want %v",
+				test.name, gotAdded, test.whitelisted)
+		}
+		if gotDisc := isDisconnected(sp.Peer); gotDisc == test.whitelisted {
+			t.Errorf("%q: unexpected disconnect state -- got %v, want %v",
+				test.name, gotDisc, !test.whitelisted)
+		}
+	}
+}
+
+// TestFreeRelayExemption ensures free and low-fee transactions relayed by
+// whitelisted peers are only exempt from rate limiting when all whitelisted
+// peers are exempt or the peer matches a free relay whitelist entry.
+func TestFreeRelayExemption(t *testing.T) {
+	origCfg := cfg
+	defer func() { cfg = origCfg }()
+
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0,
+		wire.TxTreeRegular), 0, nil))
+	tx.AddTxOut(wire.NewTxOut(0, []byte{txscript.OP_TRUE}))
+
+	tests := []struct {
+		name         string // test description
+		addr         string // peer address
+		allExempt    bool   // whether all whitelisted peers are exempt
+		wantExempted bool   // whether the peer is exempt from rate limiting
+	}{{
+		name: "non-whitelisted IPv4 peer",
+		addr: "192.168.2.10:9108",
+	}, {
+		name:      "non-whitelisted IPv4 peer with all exempt",
+		addr:      "192.168.2.10:9108",
+		allExempt: true,
+	}, {
+		name: "whitelisted IPv4 peer",
+		addr: "192.168.1.20:9108",
+	}, {
+		name:         "whitelisted IPv4 peer with all exempt",
+		addr:         "192.168.1.20:9108",
+		allExempt:    true,
+		wantExempted: true,
+	}, {
+		name:         "whitelisted IPv4 peer in free relay whitelist",
+		addr:         "192.168.1.10:9108",
+		wantExempted: true,
+	}, {
+		name:         "whitelisted IPv6 peer in free relay network",
+		addr:         "[2001:db8:1::1]:9108",
+		wantExempted: true,
+	}, {
+		name: "whitelisted IPv6 peer outside free relay network",
+		addr: "[2001:db8:2::1]:9108",
+	}, {
+		name: "non-whitelisted peer in free relay whitelist",
+		addr: "172.16.0.1:9108",
+	}}
+
+	for _, test := range tests {
+		setTestWhitelists(t)
+		cfg.WhitelistFreeRelay = test.allExempt
+		for _, addr := range []string{"192.168.1.10", "2001:db8:1::/48",
+			"172.16.0.1"} {
+
+			ipnet, err := parseWhitelist(addr)
+			if err != nil {
+				t.Fatalf("unable to parse whitelist %q: %v", addr, err)
+			}
+			cfg.freeRelayWhitelists = append(cfg.freeRelayWhitelists, ipnet)
+		}
+
+		msgChan := make(chan interface{}, 1)
+		s := &server{blockManager: &blockManager{msgChan: msgChan}}
+		sp := newServerPeer(s, false)
+		p, err := peer.NewOutboundPeer(&peer.Config{}, test.addr)
+		if err != nil {
+			t.Fatalf("%q: unable to create peer: %v", test.name, err)
+		}
+		sp.Peer = p
+		sp.isWhitelisted = isWhitelistedAddr(test.addr)
+
+		// Ensure the transaction is queued with the expected rate limiting
+		// and reply as the block manager would so the handler returns.
+		gotRateLimit := make(chan bool, 1)
+		go func() {
+			m := (<-msgChan).(*txMsg)
+			m.reply <- struct{}{}
+			gotRateLimit <- m.rateLimit
+		}()
+		sp.OnTx(sp.Peer, tx)
+		if got, want := <-gotRateLimit, !test.wantExempted; got != want {
+			t.Errorf("%q: unexpected rate limit -- got %v, want %v",
+				test.name, got, want)
+		}
+	}
+}
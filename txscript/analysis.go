@@ -0,0 +1,389 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"fmt"
+)
+
+// ScriptAnalysis houses the results of statically analyzing a script for its
+// resource usage and other properties without executing it.
+type ScriptAnalysis struct {
+	// Size is the size of the script in bytes.
+	Size int
+
+	// NumOpcodes is the total number of opcodes in the script, including
+	// data pushes.
+	NumOpcodes int
+
+	// NumOps is the number of non-push operations in the script.  This is
+	// the value that is limited to MaxOpsPerScript during execution.  Note
+	// that operations in branches that are not executed are still counted.
+	NumOps int
+
+	// NumSigOps is the number of signature operations in the script counted
+	// in the same precise manner used for redeem scripts.
+	NumSigOps int
+
+	// MaxElementSize is the size of the largest data push in the script.
+	MaxElementSize int
+
+	// MaxStackDepth is an upper bound on the combined depth of the primary
+	// and alternate stacks the script can reach relative to the depth at the
+	// start of the script.  All conditional branches are considered, so it is
+	// conservative for scripts with branches that are never executed together.
+	MaxStackDepth int
+
+	// PushOnly indicates whether the script only consists of data pushes.
+	PushOnly bool
+
+	// CanonicalPushes indicates whether all data pushes in the script use the
+	// smallest possible opcode.
+	CanonicalPushes bool
+
+	// Class is the class of the script as returned by GetScriptClass.
+	Class ScriptClass
+}
+
+// stackEffectV0 returns the number of items the provided version 0 opcode
+// removes from and adds to the combined primary and alternate stacks.  The
+// number of removed items for opcodes that remove a variable number of items
+// is the minimum possible, while the number of added items is the maximum
+// possible, so the results are suitable for calculating an upper bound on the
+// stack depth.  The provided previous opcode is used to determine the number of
+// public keys for multisignature operations when possible.
+//
+// Opcodes that cause execution to fail, such as disabled and reserved opcodes,
+// do not affect the stack.
+func stackEffectV0(op, prevOp byte) (int, int) {
+	switch {
+	// Data pushes and small integers.
+	case op <= OP_16 && op != OP_RESERVED:
+		return 0, 1
+	}
+
+	switch op {
+	case OP_IF, OP_NOTIF, OP_VERIFY, OP_DROP, OP_NIP, OP_ROLL:
+		return 1, 0
+
+	case OP_2DROP:
+		return 2, 0
+
+	case OP_DUP, OP_IFDUP:
+		return 1, 2
+
+	case OP_OVER:
+		return 2, 3
+
+	case OP_2DUP, OP_2OVER:
+		return 2, 4
+
+	case OP_3DUP:
+		return 3, 6
+
+	case OP_DEPTH:
+		return 0, 1
+
+	case OP_PICK:
+		return 1, 1
+
+	case OP_SWAP:
+		return 2, 2
+
+	case OP_ROT:
+		return 3, 3
+
+	case OP_2SWAP:
+		return 4, 4
+
+	case OP_2ROT:
+		return 6, 6
+
+	case OP_TUCK:
+		return 2, 3
+
+	case OP_SIZE:
+		return 1, 2
+
+	case OP_SUBSTR, OP_WITHIN:
+		return 3, 1
+
+	case OP_CAT, OP_LEFT, OP_RIGHT, OP_AND, OP_OR, OP_XOR, OP_EQUAL,
+		OP_ROTR, OP_ROTL, OP_ADD, OP_SUB, OP_MUL, OP_DIV, OP_MOD, OP_LSHIFT,
+		OP_RSHIFT, OP_BOOLAND, OP_BOOLOR, OP_NUMEQUAL, OP_NUMNOTEQUAL,
+		OP_LESSTHAN, OP_GREATERTHAN, OP_LESSTHANOREQUAL,
+		OP_GREATERTHANOREQUAL, OP_MIN, OP_MAX, OP_CHECKSIG:
+
+		return 2, 1
+
+	case OP_INVERT, OP_1ADD, OP_1SUB, OP_2MUL, OP_2DIV, OP_NEGATE, OP_ABS,
+		OP_NOT, OP_0NOTEQUAL, OP_RIPEMD160, OP_SHA1, OP_BLAKE256,
+		OP_HASH160, OP_HASH256, OP_SHA256:
+
+		return 1, 1
+
+	case OP_EQUALVERIFY, OP_NUMEQUALVERIFY, OP_CHECKSIGVERIFY:
+		return 2, 0
+
+	case OP_CHECKSIGALT:
+		return 3, 1
+
+	case OP_CHECKSIGALTVERIFY:
+		return 3, 0
+
+	case OP_CHECKMULTISIG, OP_CHECKMULTISIGVERIFY:
+		// The number of public keys is unknown unless it was pushed by the
+		// previous opcode, so only count the items that are always removed
+		// otherwise.
+		numRemoved := 2
+		if isSmallInt(prevOp) {
+			numRemoved += asSmallInt(prevOp)
+		}
+		if op == OP_CHECKMULTISIG {
+			return numRemoved, 1
+		}
+		return numRemoved, 0
+	}
+
+	// All other opcodes, such as OP_TOALTSTACK, OP_FROMALTSTACK, and the
+	// NOPs, either do not modify the combined depth of the stacks or cause
+	// execution to fail.
+	return 0, 0
+}
+
+// AnalyzeScript statically analyzes the provided script and returns details
+// about its resource usage and other properties, such as the number of
+// operations, signature operations, and the maximum stack depth it can reach,
+// without executing it.  This allows scripts to be checked against the limits
+// imposed during execution, via CheckLimits on the result, and their
+// standardness to be predicted before they are broadcast.
+//
+// An error is returned when the script fails to parse.
+//
+// NOTE: This function is only valid for version 0 scripts.  An error is
+// returned for all other script versions.
+func AnalyzeScript(scriptVersion uint16, script []byte) (*ScriptAnalysis, error) {
+	if scriptVersion != 0 {
+		str := fmt.Sprintf("unsupported script version %d", scriptVersion)
+		return nil, scriptError(ErrUnsupportedScriptVersion, str)
+	}
+
+	analysis := ScriptAnalysis{
+		Size:            len(script),
+		PushOnly:        true,
+		CanonicalPushes: true,
+	}
+
+	// condBranch tracks the stack depth at the start of a conditional branch
+	// along with the maximum depth at the end of any of its completed
+	// branches.
+	type condBranch struct {
+		startDepth  int
+		maxEndDepth int
+	}
+	var condBranches []condBranch
+
+	var depth int
+	prevOp := byte(OP_INVALIDOPCODE)
+	tokenizer := MakeScriptTokenizer(scriptVersion, script)
+	for tokenizer.Next() {
+		op := tokenizer.Opcode()
+		data := tokenizer.Data()
+		analysis.NumOpcodes++
+
+		// Note that this includes OP_RESERVED which counts as a push
+		// operation in the same way as it is counted by the engine.
+		if op > OP_16 {
+			analysis.NumOps++
+			analysis.PushOnly = false
+		} else if len(data) > analysis.MaxElementSize {
+			analysis.MaxElementSize = len(data)
+		}
+		if !isCanonicalPush(op, data) {
+			analysis.CanonicalPushes = false
+		}
+
+		// Calculate the stack depth after the opcode.  Items removed beyond
+		// the start of the script are assumed to have been provided by
+		// previous scripts.
+		numRemoved, numAdded := stackEffectV0(op, prevOp)
+		depth -= numRemoved
+		if depth < 0 {
+			depth = 0
+		}
+		depth += numAdded
+		if depth > analysis.MaxStackDepth {
+			analysis.MaxStackDepth = depth
+		}
+
+		// Account for all possible paths through conditional branches by
+		// restoring the depth at the start of the conditional for alternate
+		// branches and using the maximum depth of all of them once the
+		// conditional ends.
+		switch op {
+		case OP_IF, OP_NOTIF:
+			condBranches = append(condBranches, condBranch{
+				startDepth:  depth,
+				maxEndDepth: -1,
+			})
+
+		case OP_ELSE:
+			if len(condBranches) > 0 {
+				branch := &condBranches[len(condBranches)-1]
+				if depth > branch.maxEndDepth {
+					branch.maxEndDepth = depth
+				}
+				depth = branch.startDepth
+			}
+
+		case OP_ENDIF:
+			if len(condBranches) > 0 {
+				branch := condBranches[len(condBranches)-1]
+				condBranches = condBranches[:len(condBranches)-1]
+				if branch.maxEndDepth > depth {
+					depth = branch.maxEndDepth
+				}
+			}
+		}
+
+		prevOp = op
+	}
+	if err := tokenizer.Err(); err != nil {
+		return nil, err
+	}
+
+	analysis.NumSigOps = countSigOpsV0(script, true)
+	analysis.Class = GetScriptClass(scriptVersion, script)
+	return &analysis, nil
+}
+
+// CheckLimits returns an error if the analyzed script is guaranteed to exceed
+// any of the limits imposed on scripts during execution, such as the maximum
+// script size, number of operations, and data push size.  An error is also
+// returned when the maximum stack depth of the script exceeds the maximum
+// allowed stack size, which means the script might exceed it depending on the
+// branches that are executed.
+//
+// Note that a nil error does not mean the script will execute successfully
+// since only the limits that can be determined statically are checked.
+func (a *ScriptAnalysis) CheckLimits() error {
+	if a.Size > MaxScriptSize {
+		str := fmt.Sprintf("script size %d is larger than max allowed size "+
+			"%d", a.Size, MaxScriptSize)
+		return scriptError(ErrScriptTooBig, str)
+	}
+	if a.NumOps > MaxOpsPerScript {
+		str := fmt.Sprintf("script has %d operations which exceeds the max "+
+			"operation limit of %d", a.NumOps, MaxOpsPerScript)
+		return scriptError(ErrTooManyOperations, str)
+	}
+	if a.MaxElementSize > MaxScriptElementSize {
+		str := fmt.Sprintf("element size %d exceeds max allowed size %d",
+			a.MaxElementSize, MaxScriptElementSize)
+		return scriptError(ErrElementTooBig, str)
+	}
+	if a.MaxStackDepth > MaxStackSize {
+		str := fmt.Sprintf("max stack depth %d might exceed max allowed "+
+			"stack size %d", a.MaxStackDepth, MaxStackSize)
+		return scriptError(ErrStackOverflow, str)
+	}
+	return nil
+}
+
+// IsStandardPkScript returns whether the analyzed script is predicted to be a
+// standard public key script.  That is to say it is one of the recognized
+// standard script forms and does not exceed any of the script limits.
+//
+// Note that additional policy enforced by the memory pool, such as the maximum
+// number of public keys allowed in a standard multisignature script, is not
+// considered.
+func (a *ScriptAnalysis) IsStandardPkScript() bool {
+	return a.Class != NonStandardTy && a.CheckLimits() == nil
+}
+
+// IsStandardSigScript returns whether the analyzed script is predicted to be a
+// standard signature script.  That is to say it only consists of canonical data
+// pushes and does not exceed any of the script limits.
+//
+// Note that additional policy enforced by the memory pool, such as the maximum
+// size of a standard signature script, is not considered.
+func (a *ScriptAnalysis) IsStandardSigScript() bool {
+	return a.PushOnly && a.CanonicalPushes && a.CheckLimits() == nil
+}
+
+// ScriptStats tallies the usage of opcodes and script classes across a set of
+// scripts in order to support data-driven decisions regarding changes to the
+// standardness and consensus rules.  The zero value is ready to use.
+type ScriptStats struct {
+	// NumScripts is the total number of scripts that have been added.
+	NumScripts uint64
+
+	// NumUnparsable is the number of scripts that failed to parse.  The
+	// opcodes prior to the parse failure are still tallied.
+	NumUnparsable uint64
+
+	// NumUnsupportedVersion is the number of scripts with a version other
+	// than 0.  Their opcodes are not tallied since opcodes are only defined
+	// for version 0 scripts.
+	NumUnsupportedVersion uint64
+
+	// Opcodes tallies the number of times each opcode appears in the scripts
+	// indexed by the opcode value.
+	Opcodes [256]uint64
+
+	// Classes tallies the number of public key scripts of each class.
+	Classes map[ScriptClass]uint64
+}
+
+// AddScript tallies the opcodes in the provided script.  It is typically used
+// for signature scripts.  Use AddPkScript for public key scripts so their
+// classes are also tallied.
+func (s *ScriptStats) AddScript(version uint16, script []byte) {
+	s.NumScripts++
+	if version != 0 {
+		s.NumUnsupportedVersion++
+		return
+	}
+
+	tokenizer := MakeScriptTokenizer(version, script)
+	for tokenizer.Next() {
+		s.Opcodes[tokenizer.Opcode()]++
+	}
+	if tokenizer.Err() != nil {
+		s.NumUnparsable++
+	}
+}
+
+// AddPkScript tallies the opcodes in the provided public key script along with
+// its script class.
+func (s *ScriptStats) AddPkScript(version uint16, pkScript []byte) {
+	s.AddScript(version, pkScript)
+	if s.Classes == nil {
+		s.Classes = make(map[ScriptClass]uint64)
+	}
+	s.Classes[GetScriptClass(version, pkScript)]++
+}
+
+// OpcodeUsage returns the number of times each opcode that has been seen at
+// least once appears in the scripts keyed by the opcode name.
+func (s *ScriptStats) OpcodeUsage() map[string]uint64 {
+	usage := make(map[string]uint64)
+	for op, count := range s.Opcodes {
+		if count > 0 {
+			usage[opcodeArray[op].name] = count
+		}
+	}
+	return usage
+}
+
+// ClassUsage returns the number of public key scripts of each class that has
+// been seen at least once keyed by the class name.
+func (s *ScriptStats) ClassUsage() map[string]uint64 {
+	usage := make(map[string]uint64, len(s.Classes))
+	for class, count := range s.Classes {
+		usage[class.String()] = count
+	}
+	return usage
+}
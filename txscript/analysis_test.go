@@ -0,0 +1,239 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAnalyzeScript ensures statically analyzing various scripts produces the
+// expected results.
+func TestAnalyzeScript(t *testing.T) {
+	t.Parallel()
+
+	const (
+		pubKey = "DATA_33 0x02" +
+			"4b2d1b4de6b5f6b5b1aa5f12b4ad47ab0fb64ee1a8a11ab4e1ab2b5cdd05e4dc"
+		hash160 = "DATA_20 0x0102030405060708090a0b0c0d0e0f1011121314"
+		sig     = "DATA_9 0x300602010102010101"
+	)
+	tests := []struct {
+		name        string
+		version     uint16
+		script      string
+		want        ScriptAnalysis
+		wantErr     error
+		limitsErr   error
+		standardPk  bool
+		standardSig bool
+	}{{
+		name:   "empty script",
+		script: "",
+		want: ScriptAnalysis{
+			PushOnly:        true,
+			CanonicalPushes: true,
+			Class:           NonStandardTy,
+		},
+		standardSig: true,
+	}, {
+		name:   "pay-to-pubkey-hash",
+		script: "DUP HASH160 " + hash160 + " EQUALVERIFY CHECKSIG",
+		want: ScriptAnalysis{
+			Size:            25,
+			NumOpcodes:      5,
+			NumOps:          4,
+			NumSigOps:       1,
+			MaxElementSize:  20,
+			MaxStackDepth:   3,
+			CanonicalPushes: true,
+			Class:           PubKeyHashTy,
+		},
+		standardPk: true,
+	}, {
+		name:   "signature script",
+		script: sig + " " + pubKey,
+		want: ScriptAnalysis{
+			Size:            44,
+			NumOpcodes:      2,
+			MaxElementSize:  33,
+			MaxStackDepth:   2,
+			PushOnly:        true,
+			CanonicalPushes: true,
+			Class:           NonStandardTy,
+		},
+		standardSig: true,
+	}, {
+		name: "2-of-3 multisig",
+		script: "2 " + pubKey + " " + pubKey + " " + pubKey +
+			" 3 CHECKMULTISIG",
+		want: ScriptAnalysis{
+			Size:            105,
+			NumOpcodes:      6,
+			NumOps:          1,
+			NumSigOps:       3,
+			MaxElementSize:  33,
+			MaxStackDepth:   5,
+			CanonicalPushes: true,
+			Class:           MultiSigTy,
+		},
+		standardPk: true,
+	}, {
+		name:   "conditional branches use deepest branch",
+		script: "IF 1 2 3 ELSE 1 ENDIF 4",
+		want: ScriptAnalysis{
+			Size:            8,
+			NumOpcodes:      8,
+			NumOps:          3,
+			MaxStackDepth:   4,
+			CanonicalPushes: true,
+			Class:           NonStandardTy,
+		},
+	}, {
+		name:   "non-canonical push",
+		script: "DATA_1 0x05",
+		want: ScriptAnalysis{
+			Size:           2,
+			NumOpcodes:     1,
+			MaxElementSize: 1,
+			MaxStackDepth:  1,
+			PushOnly:       true,
+			Class:          NonStandardTy,
+		},
+	}, {
+		name:   "too many operations",
+		script: strings.Repeat("NOP ", MaxOpsPerScript+1),
+		want: ScriptAnalysis{
+			Size:            MaxOpsPerScript + 1,
+			NumOpcodes:      MaxOpsPerScript + 1,
+			NumOps:          MaxOpsPerScript + 1,
+			CanonicalPushes: true,
+			Class:           NonStandardTy,
+		},
+		limitsErr: scriptError(ErrTooManyOperations, ""),
+	}, {
+		name:   "possible stack overflow",
+		script: strings.Repeat("1 ", MaxStackSize+1),
+		want: ScriptAnalysis{
+			Size:            MaxStackSize + 1,
+			NumOpcodes:      MaxStackSize + 1,
+			MaxStackDepth:   MaxStackSize + 1,
+			PushOnly:        true,
+			CanonicalPushes: true,
+			Class:           NonStandardTy,
+		},
+		limitsErr: scriptError(ErrStackOverflow, ""),
+	}, {
+		name:    "parse failure",
+		script:  "NOP DATA_2 0x01",
+		wantErr: scriptError(ErrMalformedPush, ""),
+	}, {
+		name:    "unsupported script version",
+		version: 1,
+		script:  "NOP",
+		wantErr: scriptError(ErrUnsupportedScriptVersion, ""),
+	}}
+
+	for _, test := range tests {
+		script := mustParseShortForm(test.script)
+		analysis, err := AnalyzeScript(test.version, script)
+		if err := tstCheckScriptError(err, test.wantErr); err != nil {
+			t.Errorf("%q: %v", test.name, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+
+		if *analysis != test.want {
+			t.Errorf("%q: mismatched analysis -- got %+v, want %+v",
+				test.name, *analysis, test.want)
+			continue
+		}
+		err = analysis.CheckLimits()
+		if err := tstCheckScriptError(err, test.limitsErr); err != nil {
+			t.Errorf("%q: unexpected limits result: %v", test.name, err)
+			continue
+		}
+		if got := analysis.IsStandardPkScript(); got != test.standardPk {
+			t.Errorf("%q: unexpected standard pkscript result -- got %v, "+
+				"want %v", test.name, got, test.standardPk)
+		}
+		if got := analysis.IsStandardSigScript(); got != test.standardSig {
+			t.Errorf("%q: unexpected standard sigscript result -- got %v, "+
+				"want %v", test.name, got, test.standardSig)
+		}
+	}
+}
+
+// TestScriptStats ensures tallying the opcode and script class usage of
+// various scripts produces the expected results.
+func TestScriptStats(t *testing.T) {
+	t.Parallel()
+
+	const hash160 = "DATA_20 0x0102030405060708090a0b0c0d0e0f1011121314"
+	p2pkh := mustParseShortForm("DUP HASH160 " + hash160 +
+		" EQUALVERIFY CHECKSIG")
+	p2sh := mustParseShortForm("HASH160 " + hash160 + " EQUAL")
+
+	var stats ScriptStats
+	stats.AddPkScript(0, p2pkh)
+	stats.AddPkScript(0, p2pkh)
+	stats.AddPkScript(0, p2sh)
+	stats.AddPkScript(1, p2sh)
+	stats.AddScript(0, mustParseShortForm("1 NOP DATA_2 0x01"))
+
+	if stats.NumScripts != 5 {
+		t.Errorf("unexpected number of scripts -- got %d, want 5",
+			stats.NumScripts)
+	}
+	if stats.NumUnparsable != 1 {
+		t.Errorf("unexpected number of unparsable scripts -- got %d, "+
+			"want 1", stats.NumUnparsable)
+	}
+	if stats.NumUnsupportedVersion != 1 {
+		t.Errorf("unexpected number of unsupported version scripts -- "+
+			"got %d, want 1", stats.NumUnsupportedVersion)
+	}
+
+	wantOpcodes := map[string]uint64{
+		"OP_DUP":         2,
+		"OP_HASH160":     3,
+		"OP_DATA_20":     3,
+		"OP_EQUALVERIFY": 2,
+		"OP_CHECKSIG":    2,
+		"OP_EQUAL":       1,
+		"OP_1":           1,
+		"OP_NOP":         1,
+	}
+	gotOpcodes := stats.OpcodeUsage()
+	if len(gotOpcodes) != len(wantOpcodes) {
+		t.Errorf("unexpected opcode usage -- got %v, want %v", gotOpcodes,
+			wantOpcodes)
+	}
+	for name, want := range wantOpcodes {
+		if got := gotOpcodes[name]; got != want {
+			t.Errorf("unexpected usage for opcode %s -- got %d, want %d",
+				name, got, want)
+		}
+	}
+
+	wantClasses := map[string]uint64{
+		PubKeyHashTy.String():  2,
+		ScriptHashTy.String():  1,
+		NonStandardTy.String(): 1,
+	}
+	gotClasses := stats.ClassUsage()
+	if len(gotClasses) != len(wantClasses) {
+		t.Errorf("unexpected class usage -- got %v, want %v", gotClasses,
+			wantClasses)
+	}
+	for name, want := range wantClasses {
+		if got := gotClasses[name]; got != want {
+			t.Errorf("unexpected usage for class %s -- got %d, want %d",
+				name, got, want)
+		}
+	}
+}
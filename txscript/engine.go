@@ -98,12 +98,20 @@ type Engine struct {
 	// since transaction scripts are often executed more than once from various
 	// contexts (e.g. new block templates, when transactions are first seen
 	// prior to being mined, part of full block verification, etc).
-	flags    ScriptFlags
-	tx       wire.MsgTx
-	txIdx    int
-	version  uint16
-	isP2SH   bool
-	sigCache *SigCache
+	//
+	// sigBatch is the batch that the verification of signatures that support
+	// batch verification is deferred to when executing via ExecuteBatched.
+	//
+	// deferredSigs houses the signatures whose verification was deferred
+	// during execution until they are added to the batch.
+	flags        ScriptFlags
+	tx           wire.MsgTx
+	txIdx        int
+	version      uint16
+	isP2SH       bool
+	sigCache     *SigCache
+	sigBatch     *SigBatch
+	deferredSigs []schnorrBatchEntry
 
 	// The following fields handle keeping track of the current execution state
 	// of the engine.
@@ -580,9 +588,96 @@ func (vm *Engine) Step() (done bool, err error) {
 	return false, nil
 }
 
+// StepTrace houses details about a single opcode stepped through by the script
+// engine along with the state of the engine after it was processed.
+type StepTrace struct {
+	// ScriptIdx is the index of the script that contains the opcode.  Index 0
+	// is the signature script and 1 is the public key script.  In the case of
+	// pay-to-script-hash, index 2 is the redeem script.
+	ScriptIdx int
+
+	// OpcodeIdx is the index of the opcode within the script.  Note that it
+	// differs from the byte index of the opcode within the script.
+	OpcodeIdx int
+
+	// Disasm is the disassembly of the opcode in the same format returned by
+	// DisasmPC.
+	Disasm string
+
+	// Executed is false when the opcode is in a conditional branch that is not
+	// being executed, in which case it only affected the conditional nesting
+	// depth, if anything.
+	Executed bool
+
+	// Stack and AltStack are the contents of the primary and alternate stacks
+	// after the opcode was processed where the last item in each is the top of
+	// the stack.  Note that they reflect any transitions between scripts, such
+	// as clearing the alternate stack at the end of a script.
+	Stack    [][]byte
+	AltStack [][]byte
+
+	// Err is the error that caused execution to fail at the opcode, if any.
+	Err error
+}
+
 // Execute will execute all scripts in the script engine and return either nil
 // for successful validation or an error if one occurred.
 func (vm *Engine) Execute() (err error) {
+	return vm.execute(nil)
+}
+
+// ExecuteWithTrace executes all scripts in the script engine in the same way as
+// Execute while also invoking the provided function with the details of every
+// opcode after it is processed, including the opcode that caused execution to
+// fail, if any.  This allows callers such as script debuggers to inspect the
+// stacks opcode by opcode without instrumenting the engine.
+func (vm *Engine) ExecuteWithTrace(trace func(*StepTrace)) error {
+	return vm.execute(trace)
+}
+
+// ExecuteBatched executes all scripts in the script engine in the same way as
+// Execute except that the verification of secp256k1 Schnorr signatures is
+// deferred to the provided batch instead of being performed immediately.  The
+// deferred signatures are assumed to be valid during execution and are only
+// added to the batch when execution succeeds.
+//
+// Since the failure might be the result of assuming an invalid signature is
+// valid, execution is repeated without deferring any signatures when it fails
+// after deferring some.  Thus, any error returned is the same one Execute would
+// return.
+//
+// However, a nil result only indicates the scripts are valid when the batch is
+// subsequently verified.  The scripts MUST be executed again via Execute to
+// determine the result when the batch fails to verify.
+func (vm *Engine) ExecuteBatched(batch *SigBatch) error {
+	vm.sigBatch = batch
+	err := vm.execute(nil)
+	vm.sigBatch = nil
+	if err != nil {
+		if len(vm.deferredSigs) == 0 {
+			return err
+		}
+
+		// Execute the scripts again with a new engine that verifies all of
+		// the signatures immediately.
+		freshVM, err := NewEngine(vm.scripts[1], &vm.tx, vm.txIdx, vm.flags,
+			vm.version, vm.sigCache)
+		if err != nil {
+			return err
+		}
+		return freshVM.Execute()
+	}
+
+	batch.add(vm.deferredSigs)
+	vm.deferredSigs = nil
+	return nil
+}
+
+// execute executes all scripts in the script engine and returns either nil for
+// successful validation or an error if one occurred.  The trace function is
+// invoked with the details of every opcode after it is processed when it is
+// not nil.
+func (vm *Engine) execute(trace func(*StepTrace)) (err error) {
 	// All script versions other than 0 currently execute without issue,
 	// making all outputs to them anyone can pay. In the future this
 	// will allow for the addition of new scripting languages.
@@ -600,7 +695,25 @@ func (vm *Engine) Execute() (err error) {
 			return fmt.Sprintf("stepping %v", dis)
 		}))
 
+		// Note the details about the opcode that is about to be executed
+		// prior to stepping since doing so updates the program counter.
+		var step *StepTrace
+		if trace != nil {
+			step = &StepTrace{
+				ScriptIdx: vm.scriptIdx,
+				OpcodeIdx: vm.opcodeIdx,
+				Executed:  vm.isBranchExecuting(),
+			}
+			step.Disasm, _ = vm.DisasmPC()
+		}
+
 		done, err = vm.Step()
+		if trace != nil {
+			step.Stack = vm.GetStack()
+			step.AltStack = vm.GetAltStack()
+			step.Err = err
+			trace(step)
+		}
 		if err != nil {
 			return err
 		}
@@ -6,6 +6,7 @@
 package txscript
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
@@ -377,3 +378,120 @@ func TestCheckSignatureEncoding(t *testing.T) {
 		}
 	}
 }
+
+// TestExecuteWithTrace ensures ExecuteWithTrace reports the expected details
+// and stack contents for every opcode, including the one that causes a failure.
+func TestExecuteWithTrace(t *testing.T) {
+	t.Parallel()
+
+	type traceResult struct {
+		scriptIdx int
+		opcodeIdx int
+		disasm    string
+		executed  bool
+		stack     [][]byte
+		err       bool
+	}
+
+	tests := []struct {
+		name      string
+		sigScript string
+		pkScript  string
+		want      []traceResult
+		wantErr   bool
+	}{{
+		name:      "successful execution with skipped branch",
+		sigScript: "1 2",
+		pkScript:  "ADD 0 IF 9 ENDIF 3 EQUAL",
+		want: []traceResult{
+			{0, 0, "00:0000: OP_1", true, [][]byte{{1}}, false},
+			{0, 1, "00:0001: OP_2", true, [][]byte{{1}, {2}}, false},
+			{1, 0, "01:0000: OP_ADD", true, [][]byte{{3}}, false},
+			{1, 1, "01:0001: OP_0", true, [][]byte{{3}, nil}, false},
+			{1, 2, "01:0002: OP_IF", true, [][]byte{{3}}, false},
+			{1, 3, "01:0003: OP_9", false, [][]byte{{3}}, false},
+			{1, 4, "01:0004: OP_ENDIF", false, [][]byte{{3}}, false},
+			{1, 5, "01:0005: OP_3", true, [][]byte{{3}, {3}}, false},
+			{1, 6, "01:0006: OP_EQUAL", true, [][]byte{{1}}, false},
+		},
+	}, {
+		name:      "failed verify is reported",
+		sigScript: "1",
+		pkScript:  "2 EQUALVERIFY 1",
+		want: []traceResult{
+			{0, 0, "00:0000: OP_1", true, [][]byte{{1}}, false},
+			{1, 0, "01:0000: OP_2", true, [][]byte{{1}, {2}}, false},
+			{1, 1, "01:0001: OP_EQUALVERIFY", true, [][]byte{}, true},
+		},
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		tx := &wire.MsgTx{
+			SerType: wire.TxSerializeFull,
+			Version: 1,
+			TxIn: []*wire.TxIn{{
+				SignatureScript: mustParseShortForm(test.sigScript),
+				Sequence:        wire.MaxTxInSequenceNum,
+			}},
+			TxOut: []*wire.TxOut{{Value: 1}},
+		}
+		pkScript := mustParseShortForm(test.pkScript)
+		vm, err := NewEngine(pkScript, tx, 0, 0, 0, nil)
+		if err != nil {
+			t.Errorf("%q: failed to create engine: %v", test.name, err)
+			continue
+		}
+
+		var got []traceResult
+		err = vm.ExecuteWithTrace(func(step *StepTrace) {
+			if len(step.AltStack) != 0 {
+				t.Errorf("%q: unexpected alt stack %x", test.name,
+					step.AltStack)
+			}
+			got = append(got, traceResult{
+				scriptIdx: step.ScriptIdx,
+				opcodeIdx: step.OpcodeIdx,
+				disasm:    step.Disasm,
+				executed:  step.Executed,
+				stack:     step.Stack,
+				err:       step.Err != nil,
+			})
+		})
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error result -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%q: unexpected number of steps -- got %d, want %d",
+				test.name, len(got), len(test.want))
+			continue
+		}
+		for i, want := range test.want {
+			gotStep := got[i]
+			if gotStep.scriptIdx != want.scriptIdx ||
+				gotStep.opcodeIdx != want.opcodeIdx ||
+				gotStep.disasm != want.disasm ||
+				gotStep.executed != want.executed ||
+				gotStep.err != want.err {
+
+				t.Errorf("%q: mismatched step %d -- got %+v, want %+v",
+					test.name, i, gotStep, want)
+				continue
+			}
+			if len(gotStep.stack) != len(want.stack) {
+				t.Errorf("%q: mismatched stack for step %d -- got %x, "+
+					"want %x", test.name, i, gotStep.stack, want.stack)
+				continue
+			}
+			for j := range want.stack {
+				if !bytes.Equal(gotStep.stack[j], want.stack[j]) {
+					t.Errorf("%q: mismatched stack for step %d -- got %x, "+
+						"want %x", test.name, i, gotStep.stack, want.stack)
+					break
+				}
+			}
+		}
+	}
+}
@@ -2887,6 +2887,20 @@ func opcodeCheckSigAlt(op *opcode, data []byte, vm *Engine) error {
 			vm.dstack.PushBool(false)
 			return nil
 		}
+
+		// Defer the verification of the signature to the batch when
+		// executing in batch mode.  The signature is assumed to be valid
+		// since the batch is required to be verified for the scripts to be
+		// considered valid.
+		if vm.sigBatch != nil {
+			vm.deferredSigs = append(vm.deferredSigs, schnorrBatchEntry{
+				sig:    sigSec,
+				hash:   hash,
+				pubKey: pubKeySec,
+			})
+			vm.dstack.PushBool(true)
+			return nil
+		}
 		ok := sigSec.Verify(hash, pubKeySec)
 		vm.dstack.PushBool(ok)
 		return nil
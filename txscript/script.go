@@ -7,6 +7,7 @@ package txscript
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 )
 
@@ -181,6 +182,114 @@ func DisasmString(script []byte) (string, error) {
 	return disbuf.String(), tokenizer.Err()
 }
 
+// annotateOpcode returns a human-readable description of the data pushed by
+// the provided opcode along with any notable properties of it.  An empty string
+// is returned for opcodes that do not push data.
+//
+// NOTE: This function is only valid for version 0 opcodes.  Since the function
+// does not accept a script version, the results are undefined for other script
+// versions.
+func annotateOpcode(op *opcode, data []byte) string {
+	switch {
+	case isSmallInt(op.value):
+		return fmt.Sprintf("small integer %d", asSmallInt(op.value))
+
+	case op.value == OP_1NEGATE:
+		return "small integer -1"
+
+	case op.value > OP_PUSHDATA4:
+		return ""
+	}
+
+	var desc string
+	dataLen := len(data)
+	switch {
+	case isStrictPubKeyEncoding(data) && dataLen == 33:
+		desc = "compressed public key"
+
+	case isStrictPubKeyEncoding(data):
+		desc = "uncompressed public key"
+
+	case dataLen == 20:
+		desc = "20-byte hash"
+
+	case dataLen == 32:
+		desc = "32-byte hash"
+
+	// Signatures are DER encoded sequences followed by the hash type byte
+	// where the second byte is the length of the remaining sequence.
+	case dataLen >= 9 && dataLen <= 73 && data[0] == 0x30 &&
+		int(data[1]) == dataLen-3:
+
+		hashType := SigHashType(data[dataLen-1])
+		desc = fmt.Sprintf("signature with hash type 0x%02x", byte(hashType))
+
+	default:
+		desc = fmt.Sprintf("%d-byte data push", dataLen)
+	}
+
+	if !isCanonicalPush(op.value, data) {
+		desc += " (non-canonical push)"
+	}
+	return desc
+}
+
+// DisasmAnnotated returns a multi-line disassembly of the provided script
+// intended to help debug scripts.  Each line consists of the byte offset of an
+// opcode within the script, the full opcode disassembly, and, for opcodes that
+// push data, a description of the data such as whether it appears to be a
+// public key, hash, or signature.  Opcodes within conditional branches are
+// indented according to their nesting depth.
+//
+// In the case of a script that fails to parse, the returned string will contain
+// the disassembled script up to the point the failure occurred along with a
+// final line containing the string '[error]' and the parse error will be
+// returned.
+//
+// NOTE: This function is only valid for version 0 scripts.  An error is
+// returned for all other script versions.
+func DisasmAnnotated(scriptVersion uint16, script []byte) (string, error) {
+	if scriptVersion != 0 {
+		str := fmt.Sprintf("unsupported script version %d", scriptVersion)
+		return "", scriptError(ErrUnsupportedScriptVersion, str)
+	}
+
+	var disbuf strings.Builder
+	var depth int
+	tokenizer := MakeScriptTokenizer(scriptVersion, script)
+	for offset := tokenizer.ByteIndex(); tokenizer.Next(); offset = tokenizer.ByteIndex() {
+		op := tokenizer.op
+		indent := depth
+		switch op.value {
+		case OP_IF, OP_NOTIF:
+			depth++
+		case OP_ELSE:
+			indent--
+		case OP_ENDIF:
+			depth--
+			indent--
+		}
+		if depth < 0 {
+			depth = 0
+		}
+		if indent < 0 {
+			indent = 0
+		}
+
+		fmt.Fprintf(&disbuf, "%04x: %s", offset, strings.Repeat("  ", indent))
+		disasmOpcode(&disbuf, op, tokenizer.Data(), false)
+		if annotation := annotateOpcode(op, tokenizer.Data()); annotation != "" {
+			disbuf.WriteString(" ; ")
+			disbuf.WriteString(annotation)
+		}
+		disbuf.WriteByte('\n')
+	}
+	if tokenizer.Err() != nil {
+		fmt.Fprintf(&disbuf, "%04x: [error]\n", tokenizer.ByteIndex())
+	}
+	return disbuf.String(), tokenizer.Err()
+}
+
 // isCanonicalPush returns true if the opcode is either not a push instruction
 // or the data associated with the push instruction uses the smallest
 // instruction to do the job.  False otherwise.
@@ -538,3 +538,86 @@ func TestIsUnspendable(t *testing.T) {
 		}
 	}
 }
+
+// TestDisasmAnnotated ensures the annotated disassembly of various scripts
+// produces the expected results.
+func TestDisasmAnnotated(t *testing.T) {
+	t.Parallel()
+
+	const (
+		pubKey = "0x21 0x02" +
+			"4b2d1b4de6b5f6b5b1aa5f12b4ad47ab0fb64ee1a8a11ab4e1ab2b5cdd05e4dc"
+		hash160 = "0x14 0x0102030405060708090a0b0c0d0e0f1011121314"
+		sig     = "0x09 0x300602010102010101"
+	)
+	tests := []struct {
+		name    string
+		version uint16
+		script  string
+		want    string
+		wantErr bool
+	}{{
+		name:   "empty script",
+		script: "",
+		want:   "",
+	}, {
+		name:   "pay-to-pubkey-hash",
+		script: "DUP HASH160 " + hash160 + " EQUALVERIFY CHECKSIG",
+		want: "0000: OP_DUP\n" +
+			"0001: OP_HASH160\n" +
+			"0002: OP_DATA_20 0x0102030405060708090a0b0c0d0e0f1011121314" +
+			" ; 20-byte hash\n" +
+			"0017: OP_EQUALVERIFY\n" +
+			"0018: OP_CHECKSIG\n",
+	}, {
+		name:   "signature and public key",
+		script: sig + " " + pubKey,
+		want: "0000: OP_DATA_9 0x300602010102010101 ; signature with " +
+			"hash type 0x01\n" +
+			"000a: OP_DATA_33 0x024b2d1b4de6b5f6b5b1aa5f12b4ad47ab0fb64ee1" +
+			"a8a11ab4e1ab2b5cdd05e4dc ; compressed public key\n",
+	}, {
+		name:   "nested conditionals and small integers",
+		script: "1NEGATE IF 0 IF 16 ENDIF ELSE 0x01 0x05 ENDIF",
+		want: "0000: OP_1NEGATE ; small integer -1\n" +
+			"0001: OP_IF\n" +
+			"0002:   OP_0 ; small integer 0\n" +
+			"0003:   OP_IF\n" +
+			"0004:     OP_16 ; small integer 16\n" +
+			"0005:   OP_ENDIF\n" +
+			"0006: OP_ELSE\n" +
+			"0007:   OP_DATA_1 0x05 ; 1-byte data push (non-canonical push)\n" +
+			"0009: OP_ENDIF\n",
+	}, {
+		name:   "unbalanced conditional",
+		script: "ENDIF 1",
+		want: "0000: OP_ENDIF\n" +
+			"0001: OP_1 ; small integer 1\n",
+	}, {
+		name:   "parse failure",
+		script: "NOP 0x02 0x01",
+		want: "0000: OP_NOP\n" +
+			"0001: [error]\n",
+		wantErr: true,
+	}, {
+		name:    "unsupported script version",
+		version: 1,
+		script:  "NOP",
+		want:    "",
+		wantErr: true,
+	}}
+
+	for _, test := range tests {
+		script := mustParseShortForm(test.script)
+		got, err := DisasmAnnotated(test.version, script)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error result -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%q: mismatched disassembly\ngot:\n%s\nwant:\n%s",
+				test.name, got, test.want)
+		}
+	}
+}
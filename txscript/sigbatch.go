@@ -0,0 +1,122 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/schnorr"
+)
+
+// minSigBatchChunkSize is the minimum number of signatures verified together
+// by each goroutine when verifying a signature batch.  It ensures the benefits
+// of batch verification are not lost by splitting the batch too finely.
+const minSigBatchChunkSize = 32
+
+// schnorrBatchEntry houses a secp256k1 Schnorr signature whose verification
+// was deferred along with the hash and public key it is associated with.
+type schnorrBatchEntry struct {
+	sig    *schnorr.Signature
+	hash   []byte
+	pubKey *secp256k1.PublicKey
+}
+
+// SigBatch houses signatures whose verification was deferred while executing
+// scripts via ExecuteBatched in order to verify them together as a batch, which
+// is significantly faster than verifying them individually.
+//
+// Only secp256k1 Schnorr signatures are currently deferred since they are the
+// only signatures supported by scripts whose verification equations can be
+// combined.
+//
+// It is safe for concurrent access.
+type SigBatch struct {
+	mtx     sync.Mutex
+	schnorr []schnorrBatchEntry
+}
+
+// NewSigBatch returns a new empty signature batch.
+func NewSigBatch() *SigBatch {
+	return &SigBatch{}
+}
+
+// add adds the provided deferred signatures to the batch.
+//
+// This function is safe for concurrent access.
+func (b *SigBatch) add(entries []schnorrBatchEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	b.mtx.Lock()
+	b.schnorr = append(b.schnorr, entries...)
+	b.mtx.Unlock()
+}
+
+// Len returns the number of signatures in the batch.
+//
+// This function is safe for concurrent access.
+func (b *SigBatch) Len() int {
+	b.mtx.Lock()
+	n := len(b.schnorr)
+	b.mtx.Unlock()
+	return n
+}
+
+// Verify returns whether or not all of the signatures in the batch are valid.
+// The batch is split among multiple goroutines based on the number of processor
+// cores when it is large enough.
+//
+// Note that a failed batch does not identify which signatures are invalid, so
+// the scripts that deferred them must be executed again via Execute to
+// determine which ones are invalid.
+//
+// This function is safe for concurrent access.
+func (b *SigBatch) Verify() bool {
+	b.mtx.Lock()
+	entries := b.schnorr
+	b.mtx.Unlock()
+
+	// Determine the number of signatures to verify in each goroutine.
+	numChunks := runtime.NumCPU()
+	if maxChunks := len(entries) / minSigBatchChunkSize; numChunks > maxChunks {
+		numChunks = maxChunks
+	}
+	if numChunks < 1 {
+		numChunks = 1
+	}
+	chunkSize := (len(entries) + numChunks - 1) / numChunks
+
+	verifyChunk := func(chunk []schnorrBatchEntry) bool {
+		var v schnorr.BatchVerifier
+		for i := range chunk {
+			entry := &chunk[i]
+			v.Add(entry.sig, entry.hash, entry.pubKey)
+		}
+		return v.Verify()
+	}
+	if numChunks == 1 {
+		return verifyChunk(entries)
+	}
+
+	results := make(chan bool, numChunks)
+	for start := 0; start < len(entries); start += chunkSize {
+		end := start + chunkSize
+		if end > len(entries) {
+			end = len(entries)
+		}
+		go func(chunk []schnorrBatchEntry) {
+			results <- verifyChunk(chunk)
+		}(entries[start:end])
+	}
+	valid := true
+	for start := 0; start < len(entries); start += chunkSize {
+		if !<-results {
+			valid = false
+		}
+	}
+	return valid
+}
@@ -0,0 +1,181 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+	"github.com/decred/dcrd/wire"
+)
+
+// TestExecuteBatched ensures executing scripts with deferred signature
+// verification produces the same results as executing them normally and only
+// adds the deferred signatures to the batch when execution succeeds.
+func TestExecuteBatched(t *testing.T) {
+	t.Parallel()
+
+	privKeyBytes := []byte{
+		0x9e, 0x06, 0x99, 0xc9, 0x1c, 0xa1, 0xe3, 0xb7,
+		0xe3, 0xc9, 0xba, 0x71, 0xeb, 0x71, 0xc8, 0x98,
+		0x90, 0x87, 0x2b, 0xe9, 0x75, 0x76, 0x01, 0x0f,
+		0xe5, 0x93, 0xfb, 0xf3, 0xfd, 0x57, 0xe6, 0x6d,
+	}
+	pubKey := secp256k1.PrivKeyFromBytes(privKeyBytes).PubKey()
+	checkSigScript := func(negate bool) []byte {
+		builder := NewScriptBuilder().AddData(pubKey.SerializeCompressed()).
+			AddInt64(int64(dcrec.STSchnorrSecp256k1)).AddOp(OP_CHECKSIGALT)
+		if negate {
+			builder.AddOp(OP_NOT)
+		}
+		script, err := builder.Script()
+		if err != nil {
+			t.Fatalf("failed to build script: %v", err)
+		}
+		return script
+	}
+
+	tests := []struct {
+		name       string
+		negate     bool // whether the public key script negates the result
+		invalidSig bool // whether the signature is invalid
+		wantErr    bool // whether execution is expected to fail
+		wantLen    int  // expected number of signatures added to the batch
+		wantValid  bool // whether the batch is expected to verify
+	}{{
+		name:      "valid signature",
+		wantLen:   1,
+		wantValid: true,
+	}, {
+		name:       "invalid signature is deferred",
+		invalidSig: true,
+		wantLen:    1,
+		wantValid:  false,
+	}, {
+		name:       "invalid signature with negated result",
+		negate:     true,
+		invalidSig: true,
+		wantLen:    0,
+		wantValid:  true,
+	}, {
+		name:      "valid signature with negated result",
+		negate:    true,
+		wantErr:   true,
+		wantLen:   0,
+		wantValid: true,
+	}}
+
+	for _, test := range tests {
+		pkScript := checkSigScript(test.negate)
+		tx := &wire.MsgTx{
+			SerType: wire.TxSerializeFull,
+			Version: 1,
+			TxIn: []*wire.TxIn{{
+				PreviousOutPoint: wire.OutPoint{Index: 1},
+				Sequence:         wire.MaxTxInSequenceNum,
+			}},
+			TxOut: []*wire.TxOut{{Value: 1}},
+		}
+		sig, err := RawTxInSignature(tx, 0, pkScript, SigHashAll,
+			privKeyBytes, dcrec.STSchnorrSecp256k1)
+		if err != nil {
+			t.Fatalf("%q: failed to sign: %v", test.name, err)
+		}
+		if test.invalidSig {
+			sig[40] ^= 0x01
+		}
+		tx.TxIn[0].SignatureScript, err = NewScriptBuilder().AddData(sig).
+			Script()
+		if err != nil {
+			t.Fatalf("%q: failed to build signature script: %v", test.name,
+				err)
+		}
+
+		vm, err := NewEngine(pkScript, tx, 0, 0, 0, nil)
+		if err != nil {
+			t.Fatalf("%q: failed to create engine: %v", test.name, err)
+		}
+		batch := NewSigBatch()
+		err = vm.ExecuteBatched(batch)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: unexpected error -- got %v, want error %v",
+				test.name, err, test.wantErr)
+			continue
+		}
+		if batch.Len() != test.wantLen {
+			t.Errorf("%q: unexpected batch length -- got %d, want %d",
+				test.name, batch.Len(), test.wantLen)
+			continue
+		}
+		if valid := batch.Verify(); valid != test.wantValid {
+			t.Errorf("%q: unexpected batch result -- got %v, want %v",
+				test.name, valid, test.wantValid)
+		}
+	}
+}
+
+// TestSigBatchVerifyChunks ensures large signature batches that are split
+// among multiple goroutines are verified as expected.
+func TestSigBatchVerifyChunks(t *testing.T) {
+	t.Parallel()
+
+	// Create enough valid signatures to require splitting the batch.
+	privKeyBytes := []byte{0x01}
+	privKey := secp256k1.PrivKeyFromBytes(privKeyBytes)
+	pubKey := privKey.PubKey()
+	pkScript, err := NewScriptBuilder().AddData(pubKey.SerializeCompressed()).
+		AddInt64(int64(dcrec.STSchnorrSecp256k1)).AddOp(OP_CHECKSIGALT).
+		Script()
+	if err != nil {
+		t.Fatalf("failed to build script: %v", err)
+	}
+	batch := NewSigBatch()
+	const numSigs = minSigBatchChunkSize*2 + 1
+	for i := 0; i < numSigs; i++ {
+		tx := &wire.MsgTx{
+			SerType: wire.TxSerializeFull,
+			Version: 1,
+			TxIn: []*wire.TxIn{{
+				PreviousOutPoint: wire.OutPoint{Index: uint32(i)},
+				Sequence:         wire.MaxTxInSequenceNum,
+			}},
+			TxOut: []*wire.TxOut{{Value: 1}},
+		}
+		sig, err := RawTxInSignature(tx, 0, pkScript, SigHashAll,
+			privKeyBytes, dcrec.STSchnorrSecp256k1)
+		if err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		tx.TxIn[0].SignatureScript, err = NewScriptBuilder().AddData(sig).
+			Script()
+		if err != nil {
+			t.Fatalf("failed to build signature script: %v", err)
+		}
+		vm, err := NewEngine(pkScript, tx, 0, 0, 0, nil)
+		if err != nil {
+			t.Fatalf("failed to create engine: %v", err)
+		}
+		if err := vm.ExecuteBatched(batch); err != nil {
+			t.Fatalf("failed to execute: %v", err)
+		}
+	}
+	if batch.Len() != numSigs {
+		t.Fatalf("unexpected batch length -- got %d, want %d", batch.Len(),
+			numSigs)
+	}
+	if !batch.Verify() {
+		t.Fatal("valid batch did not verify")
+	}
+
+	// Ensure the batch fails to verify once an invalid signature is added.
+	invalid := batch.schnorr[numSigs-1]
+	invalid.hash = append([]byte(nil), invalid.hash...)
+	invalid.hash[0] ^= 0x01
+	batch.add([]schnorrBatchEntry{invalid})
+	if batch.Verify() {
+		t.Fatal("invalid batch verified")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+const (
+	// uploadBudgetWindow is the rolling window of time over which the bytes
+	// uploaded to peers are limited by the upload target.
+	uploadBudgetWindow = time.Hour * 24
+
+	// uploadBudgetBuckets is the number of buckets the upload budget window
+	// is divided into.  Uploaded bytes expire from the window one bucket at
+	// a time.
+	uploadBudgetBuckets = 24
+
+	// uploadBudgetBucketSize is the period of time covered by each bucket
+	// of the upload budget window.
+	uploadBudgetBucketSize = uploadBudgetWindow / uploadBudgetBuckets
+
+	// historicalBlockAge is the age after which blocks are considered
+	// historical and are therefore no longer served to non-whitelisted
+	// peers once the upload target is approached.
+	historicalBlockAge = time.Hour * 24 * 7
+)
+
+// errUploadTargetReached is returned when a historical block is not served due
+// to the upload target being approached.
+var errUploadTargetReached = errors.New("upload target reached")
+
+// uploadBucket houses the number of bytes uploaded during a period of time
+// identified by its index since the unix epoch.
+type uploadBucket struct {
+	index int64
+	bytes uint64
+}
+
+// uploadBudget tracks the number of bytes uploaded to peers over a rolling
+// window in order to stop serving historical blocks once a configured upload
+// target is approached, while leaving enough of the target to continue
+// relaying new blocks and transactions.
+//
+// The budget is safe for concurrent access.
+type uploadBudget struct {
+	target  uint64 // Max bytes per window.  Zero indicates no limit.
+	reserve uint64 // Bytes reserved for relaying new blocks.
+
+	mtx     sync.Mutex
+	buckets [uploadBudgetBuckets]uploadBucket
+}
+
+// newUploadBudget returns a new upload budget that limits the serving of
+// historical blocks once the provided reserve of the target number of bytes
+// per window remains.  A target of zero disables the limit.
+func newUploadBudget(target, reserve uint64) *uploadBudget {
+	return &uploadBudget{
+		target:  target,
+		reserve: reserve,
+	}
+}
+
+// uploadBudgetReserve returns the number of bytes of the upload target to
+// reserve for relaying new blocks on the provided network.  It is the number
+// of bytes required to relay a maximum size block for every block expected to
+// be mined during the upload budget window.
+func uploadBudgetReserve(params *chaincfg.Params) uint64 {
+	var maxBlockSize int
+	for _, size := range params.MaximumBlockSizes {
+		if size > maxBlockSize {
+			maxBlockSize = size
+		}
+	}
+	blocksPerWindow := uint64(uploadBudgetWindow / params.TargetTimePerBlock)
+	return blocksPerWindow * uint64(maxBlockSize)
+}
+
+// bucketIndex returns the index of the bucket that covers the provided time.
+func bucketIndex(now time.Time) int64 {
+	return now.UnixNano() / int64(uploadBudgetBucketSize)
+}
+
+// add records that the provided number of bytes were uploaded at the provided
+// time.
+func (b *uploadBudget) add(now time.Time, bytes uint64) {
+	index := bucketIndex(now)
+	b.mtx.Lock()
+	bucket := &b.buckets[index%uploadBudgetBuckets]
+	if bucket.index != index {
+		bucket.index = index
+		bucket.bytes = 0
+	}
+	bucket.bytes += bytes
+	b.mtx.Unlock()
+}
+
+// used returns the number of bytes uploaded during the window ending at the
+// provided time.
+func (b *uploadBudget) used(now time.Time) uint64 {
+	index := bucketIndex(now)
+	var used uint64
+	b.mtx.Lock()
+	for _, bucket := range b.buckets {
+		if bucket.index > index-uploadBudgetBuckets && bucket.index <= index {
+			used += bucket.bytes
+		}
+	}
+	b.mtx.Unlock()
+	return used
+}
+
+// targetReached returns whether or not the number of bytes uploaded during the
+// window ending at the provided time has reached the upload target.
+func (b *uploadBudget) targetReached(now time.Time) bool {
+	return b.target != 0 && b.used(now) >= b.target
+}
+
+// serveHistorical returns whether or not historical blocks may be served to
+// non-whitelisted peers as of the provided time.  They are no longer served
+// once the remainder of the upload target is within the reserve.
+func (b *uploadBudget) serveHistorical(now time.Time) bool {
+	return b.target == 0 || b.used(now)+b.reserve < b.target
+}
+
+// bytesLeft returns the number of bytes that may be uploaded during the window
+// ending at the provided time before the upload target is reached.  Zero is
+// returned when there is no upload target.
+func (b *uploadBudget) bytesLeft(now time.Time) uint64 {
+	if b.target == 0 {
+		return 0
+	}
+	used := b.used(now)
+	if used >= b.target {
+		return 0
+	}
+	return b.target - used
+}
+
+// isHistoricalBlock returns whether or not a block with the provided timestamp
+// is considered historical as of the provided time.
+func isHistoricalBlock(timestamp, now time.Time) bool {
+	return now.Sub(timestamp) > historicalBlockAge
+}
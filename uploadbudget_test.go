@@ -0,0 +1,83 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+// TestUploadBudget ensures the upload budget tracks uploaded bytes over the
+// rolling window and limits serving historical blocks as expected.
+func TestUploadBudget(t *testing.T) {
+	// Ensure there is no limit without a target.
+	start := time.Unix(1600000000, 0)
+	unlimited := newUploadBudget(0, 100)
+	unlimited.add(start, 1000)
+	if !unlimited.serveHistorical(start) || unlimited.targetReached(start) {
+		t.Fatal("budget without a target limited historical blocks")
+	}
+
+	// Ensure historical blocks are served until the remainder of the target
+	// is within the reserve.
+	budget := newUploadBudget(1000, 200)
+	budget.add(start, 700)
+	if !budget.serveHistorical(start) {
+		t.Fatal("historical blocks not served before reserve reached")
+	}
+	budget.add(start.Add(uploadBudgetBucketSize), 100)
+	now := start.Add(uploadBudgetBucketSize)
+	if budget.serveHistorical(now) {
+		t.Fatal("historical blocks served after reserve reached")
+	}
+	if budget.targetReached(now) {
+		t.Fatal("target reached before all bytes were used")
+	}
+	if left := budget.bytesLeft(now); left != 200 {
+		t.Fatalf("unexpected bytes left -- got %d, want 200", left)
+	}
+	budget.add(now, 300)
+	if !budget.targetReached(now) || budget.bytesLeft(now) != 0 {
+		t.Fatal("target not reached after all bytes were used")
+	}
+
+	// Ensure bytes expire from the window one bucket at a time.
+	now = start.Add(uploadBudgetWindow)
+	if used := budget.used(now); used != 400 {
+		t.Fatalf("unexpected used bytes after first bucket expired -- got "+
+			"%d, want 400", used)
+	}
+	if !budget.serveHistorical(now) {
+		t.Fatal("historical blocks not served after bytes expired")
+	}
+	now = now.Add(uploadBudgetBucketSize)
+	if used := budget.used(now); used != 0 {
+		t.Fatalf("unexpected used bytes after window expired -- got %d, "+
+			"want 0", used)
+	}
+
+	// Ensure blocks are only considered historical once they are older
+	// than the historical block age.
+	if isHistoricalBlock(now.Add(-historicalBlockAge), now) {
+		t.Fatal("block at historical block age considered historical")
+	}
+	if !isHistoricalBlock(now.Add(-historicalBlockAge-time.Second), now) {
+		t.Fatal("block older than historical block age not considered " +
+			"historical")
+	}
+}
+
+// TestUploadBudgetReserve ensures the bytes reserved to relay new blocks are
+// calculated as expected.
+func TestUploadBudgetReserve(t *testing.T) {
+	params := chaincfg.MainNetParams()
+	reserve := uploadBudgetReserve(params)
+	want := uint64(288 * 393216)
+	if reserve != want {
+		t.Fatalf("unexpected reserve -- got %d, want %d", reserve, want)
+	}
+}
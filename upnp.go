@@ -231,7 +231,7 @@ func getServiceURL(rootURL string) (url string, err error) {
 	}
 	defer r.Body.Close()
 	if r.StatusCode >= 400 {
-		err = errors.New(string(r.StatusCode))
+		err = errors.New(strconv.Itoa(r.StatusCode))
 		return
 	}
 	var root root
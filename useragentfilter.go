@@ -0,0 +1,123 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// userAgentPattern is a pattern that matches peer user agents along with an
+// optional minimum protocol version peers with a matching user agent must
+// advertise.
+type userAgentPattern struct {
+	pattern    string
+	re         *regexp.Regexp
+	minVersion uint32
+}
+
+// parseUserAgentPattern parses the provided user agent pattern which is of the
+// form <pattern>[@<minimum protocol version>].  The pattern is matched against
+// the entire user agent and may contain * wildcards which match any sequence of
+// characters.  For example, /dcrwire:*/dcrd:1.6.*@8 matches version 1.6 of dcrd
+// that advertises at least protocol version 8.
+func parseUserAgentPattern(s string) (*userAgentPattern, error) {
+	pattern := s
+	var minVersion uint32
+	if i := strings.LastIndexByte(s, '@'); i != -1 {
+		pver, err := strconv.ParseUint(s[i+1:], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum protocol version in "+
+				"user agent pattern %q", s)
+		}
+		pattern, minVersion = s[:i], uint32(pver)
+	}
+	if pattern == "" {
+		return nil, fmt.Errorf("empty user agent pattern %q", s)
+	}
+
+	// Convert the pattern into an anchored regular expression with any
+	// wildcards matching any sequence of characters.
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+	return &userAgentPattern{pattern: pattern, re: re, minVersion: minVersion}, nil
+}
+
+// userAgentFilter decides which peers are allowed to remain connected based on
+// the user agent and protocol version they advertise.  It allows operators to
+// refuse connections from node software that is known to be broken or abusive.
+//
+// Peers with a user agent that matches any of the deny patterns are refused.
+// When there are allow patterns, peers must also have a user agent that
+// matches at least one of them and advertise at least the minimum protocol
+// version of a matching pattern.
+type userAgentFilter struct {
+	allow []*userAgentPattern
+	deny  []*userAgentPattern
+}
+
+// newUserAgentFilter returns a new user agent filter for the provided allow and
+// deny patterns.  See parseUserAgentPattern for the pattern format.  Minimum
+// protocol versions are not permitted for deny patterns since they would be
+// ambiguous.
+func newUserAgentFilter(allow, deny []string) (*userAgentFilter, error) {
+	var f userAgentFilter
+	for _, s := range allow {
+		p, err := parseUserAgentPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		f.allow = append(f.allow, p)
+	}
+	for _, s := range deny {
+		p, err := parseUserAgentPattern(s)
+		if err != nil {
+			return nil, err
+		}
+		if p.minVersion != 0 {
+			return nil, fmt.Errorf("deny user agent pattern %q may not "+
+				"specify a minimum protocol version", s)
+		}
+		f.deny = append(f.deny, p)
+	}
+	return &f, nil
+}
+
+// Check returns an error describing why a peer with the provided user agent and
+// protocol version is refused, or nil when it is allowed.
+func (f *userAgentFilter) Check(userAgent string, protocolVersion uint32) error {
+	for _, p := range f.deny {
+		if p.re.MatchString(userAgent) {
+			return fmt.Errorf("user agent %q matches denied pattern %q",
+				userAgent, p.pattern)
+		}
+	}
+	if len(f.allow) == 0 {
+		return nil
+	}
+
+	var matched *userAgentPattern
+	for _, p := range f.allow {
+		if !p.re.MatchString(userAgent) {
+			continue
+		}
+		if protocolVersion >= p.minVersion {
+			return nil
+		}
+		matched = p
+	}
+	if matched != nil {
+		return fmt.Errorf("protocol version %d of user agent %q is less "+
+			"than the minimum of %d", protocolVersion, userAgent,
+			matched.minVersion)
+	}
+	return fmt.Errorf("user agent %q does not match any allowed pattern",
+		userAgent)
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+// TestUserAgentFilter ensures peers are allowed or refused by the user agent
+// filter according to the configured patterns.
+func TestUserAgentFilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		allow     []string
+		deny      []string
+		userAgent string
+		pver      uint32
+		want      bool
+	}{{
+		name:      "no patterns",
+		userAgent: "/dcrwire:0.4.0/dcrd:1.6.0/",
+		pver:      8,
+		want:      true,
+	}, {
+		name:      "denied",
+		deny:      []string{"*BrokenNode*"},
+		userAgent: "/dcrwire:0.4.0/BrokenNode:0.1.0/",
+		pver:      8,
+		want:      false,
+	}, {
+		name:      "not denied",
+		deny:      []string{"*BrokenNode*"},
+		userAgent: "/dcrwire:0.4.0/dcrd:1.6.0/",
+		pver:      8,
+		want:      true,
+	}, {
+		name:      "deny takes precedence over allow",
+		allow:     []string{"*"},
+		deny:      []string{"/dcrwire:*/dcrd:1.5.*"},
+		userAgent: "/dcrwire:0.4.0/dcrd:1.5.1/",
+		pver:      7,
+		want:      false,
+	}, {
+		name:      "allowed",
+		allow:     []string{"/dcrwire:*/dcrd:1.6.*"},
+		userAgent: "/dcrwire:0.4.0/dcrd:1.6.0/",
+		pver:      8,
+		want:      true,
+	}, {
+		name:      "not allowed",
+		allow:     []string{"/dcrwire:*/dcrd:1.6.*"},
+		userAgent: "/dcrwire:0.4.0/dcrd:1.5.1/",
+		pver:      8,
+		want:      false,
+	}, {
+		name:      "pattern is anchored",
+		allow:     []string{"dcrd"},
+		userAgent: "/dcrwire:0.4.0/dcrd:1.6.0/",
+		pver:      8,
+		want:      false,
+	}, {
+		name:      "pattern is literal other than wildcards",
+		allow:     []string{"/dcrd:1.6.?/"},
+		userAgent: "/dcrd:1.6.0/",
+		pver:      8,
+		want:      false,
+	}, {
+		name:      "protocol version too old",
+		allow:     []string{"/dcrwire:*/dcrd:*@8"},
+		userAgent: "/dcrwire:0.4.0/dcrd:1.5.1/",
+		pver:      7,
+		want:      false,
+	}, {
+		name:      "protocol version satisfied by another pattern",
+		allow:     []string{"/dcrwire:*/dcrd:*@8", "*/dcrd:1.5.*"},
+		userAgent: "/dcrwire:0.4.0/dcrd:1.5.1/",
+		pver:      7,
+		want:      true,
+	}}
+
+	for _, test := range tests {
+		f, err := newUserAgentFilter(test.allow, test.deny)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.name, err)
+			continue
+		}
+		err = f.Check(test.userAgent, test.pver)
+		if got := err == nil; got != test.want {
+			t.Errorf("%q: unexpected result -- got %v, want %v (err %v)",
+				test.name, got, test.want, err)
+		}
+	}
+}
+
+// TestUserAgentFilterInvalid ensures invalid user agent patterns are rejected.
+func TestUserAgentFilterInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []string
+		deny  []string
+	}{
+		{"empty allow", []string{""}, nil},
+		{"empty deny", nil, []string{""}},
+		{"only version", []string{"@8"}, nil},
+		{"invalid version", []string{"/dcrd:*@x"}, nil},
+		{"version too large", []string{"/dcrd:*@4294967296"}, nil},
+		{"deny with version", nil, []string{"/dcrd:*@8"}},
+	}
+
+	for _, test := range tests {
+		if _, err := newUserAgentFilter(test.allow, test.deny); err == nil {
+			t.Errorf("%q: did not receive expected error", test.name)
+		}
+	}
+}
@@ -122,6 +122,24 @@ a remote node running a Decred peer.  Example syntax is:
 		// Log and handle the error
 	}
 
+Additional per-command limits on the size and rate of messages read from a
+remote node may be enforced via ReadMessageLimitedN and a MessageLimiter.  The
+limits are checked against the message header, so the payloads of messages that
+exceed them are discarded without being deserialized.  Example syntax is:
+
+	// Create a limiter for the connection that only allows up to 10 addr
+	// messages per minute.
+	limiter := wire.NewMessageLimiter(&wire.MessageLimits{
+		Rates: map[string]wire.MessageRate{
+			wire.CmdAddr: {Messages: 10, Interval: time.Minute},
+		},
+	})
+	_, msg, rawPayload, err := wire.ReadMessageLimitedN(conn, pver, btcnet,
+		limiter)
+	if errors.Is(err, wire.ErrMsgRateExceeded) {
+		// The message was discarded
+	}
+
 Writing Messages
 
 In order to marshall Decred messages to the wire, use the WriteMessage
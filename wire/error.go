@@ -121,6 +121,10 @@ const (
 	// ErrMalformedStrictString is returned when a string that has strict
 	// formatting requirements does not conform to the requirements.
 	ErrMalformedStrictString
+
+	// ErrMsgRateExceeded is returned when messages of a given type are
+	// received faster than the maximum rate allowed.
+	ErrMsgRateExceeded
 )
 
 // Map of ErrorCode values back to their constant names for pretty printing.
@@ -153,6 +157,7 @@ var errorCodeStrings = map[ErrorCode]string{
 	ErrUserAgentTooLong:              "ErrUserAgentTooLong",
 	ErrTooManyFilterHeaders:          "ErrTooManyFilterHeaders",
 	ErrMalformedStrictString:         "ErrMalformedStrictString",
+	ErrMsgRateExceeded:               "ErrMsgRateExceeded",
 }
 
 // String returns the ErrorCode as a human-readable name.
@@ -47,6 +47,7 @@ func TestMessageErrorCodeStringer(t *testing.T) {
 		{ErrUserAgentTooLong, "ErrUserAgentTooLong"},
 		{ErrTooManyFilterHeaders, "ErrTooManyFilterHeaders"},
 		{ErrMalformedStrictString, "ErrMalformedStrictString"},
+		{ErrMsgRateExceeded, "ErrMsgRateExceeded"},
 		{0xffff, "Unknown ErrorCode (65535)"},
 	}
 
@@ -5,4 +5,5 @@ go 1.13
 require (
 	github.com/davecgh/go-spew v1.1.1
 	github.com/decred/dcrd/chaincfg/chainhash v1.0.2
+	github.com/decred/dcrd/crypto/blake256 v1.0.0
 )
@@ -6,11 +6,15 @@
 package wire
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
+	"hash"
 	"io"
+	"io/ioutil"
 
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/crypto/blake256"
 )
 
 // MessageHeaderSize is the number of bytes in a Decred message header.
@@ -300,6 +304,38 @@ func WriteMessage(w io.Writer, msg Message, pver uint32, dcrnet CurrencyNet) err
 // message.  This function is the same as ReadMessage except it also returns the
 // number of bytes read.
 func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, []byte, error) {
+	return ReadMessageLimitedN(r, pver, dcrnet, nil)
+}
+
+// ReadMessageLimitedN reads, validates, and parses the next Decred Message from
+// r for the provided protocol version and Decred network while enforcing the
+// per-command limits of the provided message limiter.  The limits are enforced
+// based on the message header before the payload is read, so the payloads of
+// messages that exceed them are discarded without being deserialized.  A nil
+// limiter imposes no additional limits.
+//
+// This function is the same as ReadMessageN except for the additional limits.
+func ReadMessageLimitedN(r io.Reader, pver uint32, dcrnet CurrencyNet, limiter *MessageLimiter) (int, Message, []byte, error) {
+	return readMessage(r, pver, dcrnet, limiter, false)
+}
+
+// ReadMessageStreamN is the same as ReadMessageLimitedN except block messages
+// are decoded as their payload is read instead of first reading the entire
+// payload into memory.  The checksum of the payload is calculated while it is
+// read and verified once the block is fully decoded.  This reduces the peak
+// memory required to read large block messages.
+//
+// Since the payload of block messages is not buffered, the returned raw payload
+// is nil for block messages.
+func ReadMessageStreamN(r io.Reader, pver uint32, dcrnet CurrencyNet, limiter *MessageLimiter) (int, Message, []byte, error) {
+	return readMessage(r, pver, dcrnet, limiter, true)
+}
+
+// readMessage reads, validates, and parses the next Decred Message from r for
+// the provided protocol version and Decred network while enforcing the limits
+// of the provided message limiter.  Block messages are decoded as they are read
+// without buffering the payload when streamBlocks is true.
+func readMessage(r io.Reader, pver uint32, dcrnet CurrencyNet, limiter *MessageLimiter, streamBlocks bool) (int, Message, []byte, error) {
 	const op = "ReadMessage"
 	totalBytes := 0
 	n, hdr, err := readMessageHeader(r)
@@ -350,6 +386,31 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 		return totalBytes, nil, nil, messageError(op, ErrPayloadTooLarge, msg)
 	}
 
+	// Enforce the configured limits for the message type, if any.
+	if maxSize, ok := limiter.maxSize(command); ok && hdr.length > maxSize {
+		discardInput(r, hdr.length)
+		msg := fmt.Sprintf("payload exceeds configured max length - header "+
+			"indicates %v bytes, but max payload size for messages of "+
+			"type [%v] is %v.", hdr.length, command, maxSize)
+		return totalBytes, nil, nil, messageError(op, ErrPayloadTooLarge, msg)
+	}
+	if !limiter.allow(command) {
+		discardInput(r, hdr.length)
+		msg := fmt.Sprintf("messages of type [%v] exceed the configured "+
+			"max rate", command)
+		return totalBytes, nil, nil, messageError(op, ErrMsgRateExceeded, msg)
+	}
+
+	// Decode block messages directly from the stream when requested.
+	if block, ok := msg.(*MsgBlock); ok && streamBlocks {
+		n, err := readBlockStream(r, block, hdr, pver)
+		totalBytes += n
+		if err != nil {
+			return totalBytes, nil, nil, err
+		}
+		return totalBytes, msg, nil, nil
+	}
+
 	// Read payload.
 	payload := make([]byte, hdr.length)
 	n, err = io.ReadFull(r, payload)
@@ -377,6 +438,73 @@ func ReadMessageN(r io.Reader, pver uint32, dcrnet CurrencyNet) (int, Message, [
 	return totalBytes, msg, payload, nil
 }
 
+// blockStreamBufferSize is the size of the buffer used to read the payload of
+// block messages that are decoded as they are read.  The payload is buffered
+// in chunks of this size to avoid excessive small reads from the underlying
+// reader.
+const blockStreamBufferSize = 64 * 1024
+
+// payloadReader reads a message payload of a fixed length from an underlying
+// reader while hashing the bytes read so the checksum of the payload can be
+// verified without buffering it.
+type payloadReader struct {
+	r         io.Reader
+	remaining uint32
+	hasher    hash.Hash
+	n         int
+}
+
+// Read reads up to the remaining length of the payload into p.
+//
+// This is part of the io.Reader interface.
+func (pr *payloadReader) Read(p []byte) (int, error) {
+	if pr.remaining == 0 {
+		return 0, io.EOF
+	}
+	if uint32(len(p)) > pr.remaining {
+		p = p[:pr.remaining]
+	}
+	n, err := pr.r.Read(p)
+	pr.remaining -= uint32(n)
+	pr.n += n
+	pr.hasher.Write(p[:n])
+	if err == io.EOF && pr.remaining > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// readBlockStream decodes the payload of a block message described by the
+// provided header from r into the provided block as it is read and verifies
+// the checksum of the payload once it has been fully read.  It returns the
+// number of payload bytes read.
+//
+// The entire payload is always read, even when decoding fails, so the next
+// message can be read from r.
+func readBlockStream(r io.Reader, block *MsgBlock, hdr *messageHeader, pver uint32) (int, error) {
+	const op = "ReadMessage"
+	pr := &payloadReader{r: r, remaining: hdr.length, hasher: blake256.New()}
+	br := bufio.NewReaderSize(pr, blockStreamBufferSize)
+	decodeErr := block.BtcDecode(br, pver)
+
+	// Read any remaining bytes of the payload so the checksum covers all of
+	// it and the reader is positioned at the next message.
+	_, err := io.Copy(ioutil.Discard, br)
+	if err != nil {
+		return pr.n, err
+	}
+
+	// Test checksum.
+	checksum := pr.hasher.Sum(nil)[0:4]
+	if !bytes.Equal(checksum, hdr.checksum[:]) {
+		msg := fmt.Sprintf("payload checksum failed - header indicates %v, "+
+			"but actual checksum is %v.", hdr.checksum, checksum)
+		return pr.n, messageError(op, ErrPayloadChecksum, msg)
+	}
+
+	return pr.n, decodeErr
+}
+
 // ReadMessage reads, validates, and parses the next Decred Message from r for
 // the provided protocol version and Decred network.  It returns the parsed
 // Message and raw bytes which comprise the message.  This function only differs
@@ -362,6 +362,80 @@ func TestReadMessageWireErrors(t *testing.T) {
 	}
 }
 
+// TestReadMessageStream ensures block messages decoded as they are read from
+// the stream match those decoded from a buffered payload and that the payload
+// checksum is verified.
+func TestReadMessageStream(t *testing.T) {
+	t.Parallel()
+
+	pver := ProtocolVersion
+	dcrnet := MainNet
+	var buf bytes.Buffer
+	for _, msg := range []Message{&testBlock, NewMsgPing(1)} {
+		if err := WriteMessage(&buf, msg, pver, dcrnet); err != nil {
+			t.Fatalf("WriteMessage: %v", err)
+		}
+	}
+	encoded := buf.Bytes()
+
+	// Ensure the streamed block matches the original, reports the total
+	// number of bytes read, and does not return the raw payload.
+	r := bytes.NewReader(encoded)
+	n, msg, payload, err := ReadMessageStreamN(r, pver, dcrnet, nil)
+	if err != nil {
+		t.Fatalf("ReadMessageStreamN: %v", err)
+	}
+	wantN := MessageHeaderSize + testBlock.SerializeSize()
+	if n != wantN {
+		t.Fatalf("unexpected bytes read -- got %d, want %d", n, wantN)
+	}
+	if !reflect.DeepEqual(msg, &testBlock) {
+		t.Fatalf("mismatched block -- got %v, want %v", spew.Sdump(msg),
+			spew.Sdump(&testBlock))
+	}
+	if payload != nil {
+		t.Fatal("unexpected raw payload for streamed block")
+	}
+
+	// Ensure the next message is read from the correct position and that
+	// messages other than blocks return the raw payload.
+	_, msg, payload, err = ReadMessageStreamN(r, pver, dcrnet, nil)
+	if err != nil {
+		t.Fatalf("ReadMessageStreamN: %v", err)
+	}
+	if _, ok := msg.(*MsgPing); !ok || payload == nil {
+		t.Fatalf("unexpected message %T with payload %x", msg, payload)
+	}
+
+	// Ensure a corrupted block payload fails the checksum while still
+	// consuming the entire payload.
+	corrupted := make([]byte, len(encoded))
+	copy(corrupted, encoded)
+	corrupted[MessageHeaderSize+len(encoded[MessageHeaderSize:])/2] ^= 0xff
+	r = bytes.NewReader(corrupted)
+	_, _, _, err = ReadMessageStreamN(r, pver, dcrnet, nil)
+	if !errors.Is(err, ErrPayloadChecksum) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			ErrPayloadChecksum)
+	}
+	_, msg, _, err = ReadMessageStreamN(r, pver, dcrnet, nil)
+	if err != nil {
+		t.Fatalf("ReadMessageStreamN after corruption: %v", err)
+	}
+	if _, ok := msg.(*MsgPing); !ok {
+		t.Fatalf("unexpected message %T after corruption", msg)
+	}
+
+	// Ensure a truncated block payload returns an unexpected EOF.
+	truncated := encoded[:MessageHeaderSize+testBlock.SerializeSize()/2]
+	_, _, _, err = ReadMessageStreamN(bytes.NewReader(truncated), pver,
+		dcrnet, nil)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("unexpected error -- got %v, want %v", err,
+			io.ErrUnexpectedEOF)
+	}
+}
+
 // TestWriteMessageWireErrors performs negative tests against wire encoding from
 // concrete messages to confirm error paths work correctly.
 func TestWriteMessageWireErrors(t *testing.T) {
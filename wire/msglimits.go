@@ -0,0 +1,111 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"time"
+)
+
+// MessageRate defines the maximum rate messages may be received as a number of
+// messages per interval.  Bursts of up to the number of messages are permitted.
+type MessageRate struct {
+	Messages uint32
+	Interval time.Duration
+}
+
+// MessageLimits houses configurable per-command limits that are enforced when
+// reading messages before their payloads are deserialized.  The limits are
+// keyed by command, such as CmdAddr or CmdHeaders.
+//
+// The limits may be shared by multiple message limiters and must not be
+// modified once they are in use.
+type MessageLimits struct {
+	// MaxSizes specifies the maximum payload size of messages.  The smaller
+	// of this and the maximum payload length defined by the message type
+	// is enforced.
+	MaxSizes map[string]uint32
+
+	// Rates specifies the maximum rate messages may be received.
+	Rates map[string]MessageRate
+}
+
+// rateBucket tracks the number of messages of a given type that may currently
+// be received via the token bucket algorithm.
+type rateBucket struct {
+	tokens     float64
+	lastUpdate time.Time
+}
+
+// MessageLimiter enforces message limits for a single stream of messages, such
+// as those received from a peer, and houses the state required to track the
+// rates of the stream.
+//
+// The limiter is NOT safe for concurrent access.
+type MessageLimiter struct {
+	limits  *MessageLimits
+	buckets map[string]*rateBucket
+	timeNow func() time.Time
+}
+
+// NewMessageLimiter returns a new message limiter that enforces the provided
+// limits.
+func NewMessageLimiter(limits *MessageLimits) *MessageLimiter {
+	return &MessageLimiter{
+		limits:  limits,
+		buckets: make(map[string]*rateBucket),
+		timeNow: time.Now,
+	}
+}
+
+// maxSize returns the configured maximum payload size for messages with the
+// provided command and whether or not there is one.
+//
+// This function is safe to call on a nil limiter.
+func (l *MessageLimiter) maxSize(command string) (uint32, bool) {
+	if l == nil || l.limits == nil {
+		return 0, false
+	}
+	maxSize, ok := l.limits.MaxSizes[command]
+	return maxSize, ok
+}
+
+// allow returns whether or not a message with the provided command may be
+// received without exceeding the configured rate and consumes the allowance
+// for it when it may.
+//
+// This function is safe to call on a nil limiter.
+func (l *MessageLimiter) allow(command string) bool {
+	if l == nil || l.limits == nil {
+		return true
+	}
+	rate, ok := l.limits.Rates[command]
+	if !ok || rate.Interval <= 0 {
+		return true
+	}
+
+	// Replenish the bucket in proportion to the time since it was last
+	// updated up to the permitted burst.
+	now := l.timeNow()
+	burst := float64(rate.Messages)
+	bucket, ok := l.buckets[command]
+	if !ok {
+		bucket = &rateBucket{tokens: burst, lastUpdate: now}
+		l.buckets[command] = bucket
+	}
+	elapsed := now.Sub(bucket.lastUpdate)
+	if elapsed > 0 {
+		bucket.tokens += burst * float64(elapsed) / float64(rate.Interval)
+		if bucket.tokens > burst {
+			bucket.tokens = burst
+		}
+		bucket.lastUpdate = now
+	}
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
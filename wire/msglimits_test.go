@@ -0,0 +1,104 @@
+// Copyright (c) 2020 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestMessageLimiter ensures the per-command size and rate limits of a message
+// limiter are enforced when reading messages.
+func TestMessageLimiter(t *testing.T) {
+	t.Parallel()
+
+	pver := ProtocolVersion
+	dcrnet := MainNet
+	limiter := NewMessageLimiter(&MessageLimits{
+		MaxSizes: map[string]uint32{CmdAddr: 100},
+		Rates: map[string]MessageRate{
+			CmdPing: {Messages: 2, Interval: time.Minute},
+		},
+	})
+	now := time.Unix(1600000000, 0)
+	limiter.timeNow = func() time.Time { return now }
+
+	// writeMsgs returns a reader with the provided messages serialized to it.
+	writeMsgs := func(msgs ...Message) *bytes.Buffer {
+		var buf bytes.Buffer
+		for _, msg := range msgs {
+			if err := WriteMessage(&buf, msg, pver, dcrnet); err != nil {
+				t.Fatalf("WriteMessage: %v", err)
+			}
+		}
+		return &buf
+	}
+
+	// Ensure messages that exceed the configured max size are rejected and
+	// their payloads are discarded so the next message can be read.
+	largeAddr := NewMsgAddr()
+	for i := 0; i < 10; i++ {
+		na := NewNetAddressIPPort([]byte{127, 0, 0, byte(i)}, 9108, SFNodeNetwork)
+		if err := largeAddr.AddAddress(na); err != nil {
+			t.Fatalf("AddAddress: %v", err)
+		}
+	}
+	r := writeMsgs(largeAddr, NewMsgAddr())
+	_, _, _, err := ReadMessageLimitedN(r, pver, dcrnet, limiter)
+	if !errors.Is(err, ErrPayloadTooLarge) {
+		t.Fatalf("unexpected error for large message -- got %v, want %v",
+			err, ErrPayloadTooLarge)
+	}
+	_, msg, _, err := ReadMessageLimitedN(r, pver, dcrnet, limiter)
+	if err != nil {
+		t.Fatalf("unexpected error for small message: %v", err)
+	}
+	if _, ok := msg.(*MsgAddr); !ok {
+		t.Fatalf("unexpected message type %T", msg)
+	}
+
+	// Ensure messages are allowed up to the burst and rejected afterwards
+	// until enough time has passed to replenish the allowance.
+	r = writeMsgs(NewMsgPing(1), NewMsgPing(2), NewMsgPing(3), NewMsgPing(4),
+		NewMsgPing(5))
+	for i := 0; i < 2; i++ {
+		_, _, _, err := ReadMessageLimitedN(r, pver, dcrnet, limiter)
+		if err != nil {
+			t.Fatalf("unexpected error for message within rate: %v", err)
+		}
+	}
+	_, _, _, err = ReadMessageLimitedN(r, pver, dcrnet, limiter)
+	if !errors.Is(err, ErrMsgRateExceeded) {
+		t.Fatalf("unexpected error for message over rate -- got %v, want %v",
+			err, ErrMsgRateExceeded)
+	}
+	now = now.Add(30 * time.Second)
+	_, msg, _, err = ReadMessageLimitedN(r, pver, dcrnet, limiter)
+	if err != nil {
+		t.Fatalf("unexpected error after rate replenished: %v", err)
+	}
+	if ping, ok := msg.(*MsgPing); !ok || ping.Nonce != 4 {
+		t.Fatalf("unexpected message after rate replenished: %v", msg)
+	}
+	_, _, _, err = ReadMessageLimitedN(r, pver, dcrnet, limiter)
+	if !errors.Is(err, ErrMsgRateExceeded) {
+		t.Fatalf("unexpected error for message over rate -- got %v, want %v",
+			err, ErrMsgRateExceeded)
+	}
+
+	// Ensure messages without limits and a nil limiter are not limited.
+	r = writeMsgs(NewMsgPong(1), NewMsgPing(6))
+	for i := 0; i < 2; i++ {
+		var l *MessageLimiter
+		if i == 0 {
+			l = limiter
+		}
+		if _, _, _, err := ReadMessageLimitedN(r, pver, dcrnet, l); err != nil {
+			t.Fatalf("unexpected error for unlimited message: %v", err)
+		}
+	}
+}
@@ -17,7 +17,7 @@ const (
 	InitialProcotolVersion uint32 = 1
 
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 7
+	ProtocolVersion uint32 = 8
 
 	// NodeBloomVersion is the protocol version which added the SFNodeBloom
 	// service flag (unused).
@@ -43,6 +43,12 @@ const (
 	// CFilterV2Version is the protocol version which adds the getcfilterv2 and
 	// cfiltverv2 messages.
 	CFilterV2Version uint32 = 7
+
+	// BlocksOnlyVoteRelayVersion is the protocol version which allows peers
+	// that disable transaction relay in their version message to still
+	// receive vote announcements.  Only votes may be announced to such
+	// peers.
+	BlocksOnlyVoteRelayVersion uint32 = 8
 )
 
 // ServiceFlag identifies services supported by a Decred peer.